@@ -8,6 +8,15 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 )
 
+// Client is the part of the KMS API used to sign with KMS keys, which is implemented by *kms.Client
+type Client interface {
+	GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (
+		*kms.GetPublicKeyOutput, error)
+	Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error)
+}
+
+var _ Client = (*kms.Client)(nil)
+
 func NewKMSClient(ctx context.Context, region string) (*kms.Client, error) {
 	config, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
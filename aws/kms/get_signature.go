@@ -16,7 +16,7 @@ type asn1EcSig struct {
 
 // GetECDSASignature retrieves the ECDSA signature for a message using a KMS key
 func GetECDSASignature(
-	ctx context.Context, svc *kms.Client, keyId string, msg []byte,
+	ctx context.Context, svc Client, keyId string, msg []byte,
 ) (r []byte, s []byte, err error) {
 	signInput := &kms.SignInput{
 		KeyId:            aws.String(keyId),
@@ -156,16 +156,11 @@ func NewBindingsFromConfig(
 			return nil, utils.WrapError("Failed to create BLSRegistryCoordinator contract", err)
 		}
 
-		serviceManagerAddr, err = contractBlsRegistryCoordinator.ServiceManager(&bind.CallOpts{})
-		if err != nil {
-			return nil, utils.WrapError("Failed to fetch ServiceManager address", err)
-		}
-		contractServiceManager, err = servicemanager.NewContractServiceManagerBase(
-			serviceManagerAddr,
-			client,
-		)
-		if err != nil {
-			return nil, utils.WrapError("Failed to create ServiceManager contract", err)
+		if isZeroAddress(cfg.ServiceManagerAddress) {
+			serviceManagerAddr, err = contractBlsRegistryCoordinator.ServiceManager(&bind.CallOpts{})
+			if err != nil {
+				return nil, utils.WrapError("Failed to fetch ServiceManager address", err)
+			}
 		}
 
 		stakeRegistryAddr, err = contractBlsRegistryCoordinator.StakeRegistry(&bind.CallOpts{})
@@ -205,6 +200,21 @@ func NewBindingsFromConfig(
 		if err != nil {
 			return nil, utils.WrapError("Failed to get DelegationManager address", err)
 		}
+	}
+
+	if !isZeroAddress(cfg.ServiceManagerAddress) {
+		serviceManagerAddr = cfg.ServiceManagerAddress
+	}
+	if isZeroAddress(serviceManagerAddr) {
+		logger.Debug("ServiceManager address not provided, the calls to the contract will not work")
+	} else {
+		contractServiceManager, err = servicemanager.NewContractServiceManagerBase(
+			serviceManagerAddr,
+			client,
+		)
+		if err != nil {
+			return nil, utils.WrapError("Failed to create ServiceManager contract", err)
+		}
 		avsDirectoryAddr, err = contractServiceManager.AvsDirectory(&bind.CallOpts{})
 		if err != nil {
 			return nil, utils.WrapError("Failed to get AvsDirectory address", err)
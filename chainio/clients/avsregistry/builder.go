@@ -36,9 +36,10 @@ func BuildReadClients(
 		logger,
 		client,
 	)
+	chainReader.indexRegistry = avsBindings.IndexRegistry
 
-	chainSubscriber, err := NewSubscriberFromConfig(
-		config,
+	chainSubscriber, err := newSubscriberFromBindings(
+		avsBindings,
 		wsClient,
 		logger,
 	)
@@ -49,6 +50,7 @@ func BuildReadClients(
 	return chainReader, chainSubscriber, avsBindings, nil
 }
 
+// BuildClients creates the AVS registry reader, subscriber and writer, all sharing the same contract bindings.
 func BuildClients(
 	config Config,
 	client eth.HttpBackend,
@@ -75,9 +77,10 @@ func BuildClients(
 		logger,
 		client,
 	)
+	chainReader.indexRegistry = avsBindings.IndexRegistry
 
-	chainSubscriber, err := NewSubscriberFromConfig(
-		config,
+	chainSubscriber, err := newSubscriberFromBindings(
+		avsBindings,
 		wsClient,
 		logger,
 	)
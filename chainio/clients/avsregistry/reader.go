@@ -1,17 +1,27 @@
 package avsregistry
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
 	apkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
+	blssigcheck "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IBLSSignatureChecker"
+	indexregistry "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IndexRegistry"
 	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
 	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
 	stakeregistry "github.com/Layr-Labs/eigensdk-go/contracts/bindings/StakeRegistry"
@@ -28,6 +38,8 @@ var DefaultQueryBlockRange = big.NewInt(10_000)
 type Config struct {
 	RegistryCoordinatorAddress    common.Address
 	OperatorStateRetrieverAddress common.Address
+	// ServiceManagerAddress is optional. When not provided, it is fetched from the RegistryCoordinator.
+	ServiceManagerAddress common.Address
 }
 
 type ChainReader struct {
@@ -37,7 +49,9 @@ type ChainReader struct {
 	registryCoordinator     *regcoord.ContractRegistryCoordinator
 	operatorStateRetriever  *opstateretriever.ContractOperatorStateRetriever
 	stakeRegistry           *stakeregistry.ContractStakeRegistry
-	ethClient               eth.HttpBackend
+	// indexRegistry is only set by the config based constructors, see NewReaderFromConfig
+	indexRegistry *indexregistry.ContractIndexRegistry
+	ethClient     eth.HttpBackend
 }
 
 func NewChainReader(
@@ -73,7 +87,7 @@ func NewReaderFromConfig(
 		return nil, err
 	}
 
-	return NewChainReader(
+	chainReader := NewChainReader(
 		bindings.RegistryCoordinatorAddr,
 		bindings.BlsApkRegistryAddr,
 		bindings.RegistryCoordinator,
@@ -81,7 +95,9 @@ func NewReaderFromConfig(
 		bindings.StakeRegistry,
 		logger,
 		client,
-	), nil
+	)
+	chainReader.indexRegistry = bindings.IndexRegistry
+	return chainReader, nil
 }
 
 // BuildAvsRegistryChainReader creates a new ChainReader
@@ -115,7 +131,15 @@ func BuildAvsRegistryChainReader(
 	if err != nil {
 		return nil, utils.WrapError("Failed to create contractOperatorStateRetriever", err)
 	}
-	return NewChainReader(
+	indexRegistryAddr, err := contractRegistryCoordinator.IndexRegistry(&bind.CallOpts{})
+	if err != nil {
+		return nil, utils.WrapError("Failed to get indexRegistryAddr", err)
+	}
+	contractIndexRegistry, err := indexregistry.NewContractIndexRegistry(indexRegistryAddr, ethClient)
+	if err != nil {
+		return nil, utils.WrapError("Failed to create contractIndexRegistry", err)
+	}
+	chainReader := NewChainReader(
 		registryCoordinatorAddr,
 		blsApkRegistryAddr,
 		contractRegistryCoordinator,
@@ -123,7 +147,9 @@ func BuildAvsRegistryChainReader(
 		contractStakeRegistry,
 		logger,
 		ethClient,
-	), nil
+	)
+	chainReader.indexRegistry = contractIndexRegistry
+	return chainReader, nil
 }
 
 func (r *ChainReader) GetQuorumCount(opts *bind.CallOpts) (uint8, error) {
@@ -335,6 +361,101 @@ func (r *ChainReader) GetCheckSignaturesIndices(
 	return checkSignatureIndices, nil
 }
 
+// BuildNonSignerStakesAndSignature builds the NonSignerStakesAndSignature struct expected by
+// BLSSignatureChecker.checkSignatures. The non-signer pubkeys are sorted by operator id, as required by the contract,
+// before fetching the check-signature indices for them. quorumApks must be in the same order as quorumNumbers.
+func (r *ChainReader) BuildNonSignerStakesAndSignature(
+	ctx context.Context,
+	quorumNumbers types.QuorumNums,
+	referenceBlock uint32,
+	nonSignerPubkeys []*bls.G1Point,
+	quorumApks []*bls.G1Point,
+	aggSig *bls.Signature,
+	aggPubkeyG2 *bls.G2Point,
+) (blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature, error) {
+	if len(quorumApks) != len(quorumNumbers) {
+		return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{}, fmt.Errorf(
+			"got %d quorum apks for %d quorums",
+			len(quorumApks),
+			len(quorumNumbers),
+		)
+	}
+
+	// the contract requires non-signers to be sorted in strictly ascending order of their operator ids
+	sortedNonSignerPubkeys := make([]*bls.G1Point, len(nonSignerPubkeys))
+	copy(sortedNonSignerPubkeys, nonSignerPubkeys)
+	sort.Slice(sortedNonSignerPubkeys, func(i, j int) bool {
+		iOperatorId := types.OperatorIdFromG1Pubkey(sortedNonSignerPubkeys[i])
+		jOperatorId := types.OperatorIdFromG1Pubkey(sortedNonSignerPubkeys[j])
+		return bytes.Compare(iOperatorId[:], jOperatorId[:]) < 0
+	})
+	nonSignerOperatorIds := make([]types.OperatorId, len(sortedNonSignerPubkeys))
+	nonSignerPubkeysBN254 := make([]blssigcheck.BN254G1Point, len(sortedNonSignerPubkeys))
+	var err error
+	for i, pubkey := range sortedNonSignerPubkeys {
+		nonSignerOperatorIds[i] = types.OperatorIdFromG1Pubkey(pubkey)
+		if i > 0 && nonSignerOperatorIds[i] == nonSignerOperatorIds[i-1] {
+			return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{}, fmt.Errorf(
+				"duplicate non-signer with operator id %s",
+				nonSignerOperatorIds[i],
+			)
+		}
+		nonSignerPubkeysBN254[i], err = chainioutils.G1PointToBinding[blssigcheck.BN254G1Point](pubkey)
+		if err != nil {
+			return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{}, utils.WrapError(
+				fmt.Sprintf("invalid pubkey of non-signer %s", nonSignerOperatorIds[i]),
+				err,
+			)
+		}
+	}
+
+	quorumApksBN254 := make([]blssigcheck.BN254G1Point, len(quorumApks))
+	for i, apk := range quorumApks {
+		quorumApksBN254[i], err = chainioutils.G1PointToBinding[blssigcheck.BN254G1Point](apk)
+		if err != nil {
+			return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{}, utils.WrapError(
+				fmt.Sprintf("invalid apk of quorum %d", quorumNumbers[i]),
+				err,
+			)
+		}
+	}
+	apkG2, err := chainioutils.G2PointToBinding[blssigcheck.BN254G2Point](aggPubkeyG2)
+	if err != nil {
+		return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{}, utils.WrapError(
+			"invalid aggregated G2 pubkey",
+			err,
+		)
+	}
+	sigma, err := chainioutils.SignatureToBinding[blssigcheck.BN254G1Point](aggSig)
+	if err != nil {
+		return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{}, utils.WrapError(
+			"invalid aggregated signature",
+			err,
+		)
+	}
+
+	indices, err := r.GetCheckSignaturesIndices(
+		&bind.CallOpts{Context: ctx},
+		referenceBlock,
+		quorumNumbers,
+		nonSignerOperatorIds,
+	)
+	if err != nil {
+		return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{}, err
+	}
+
+	return blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: indices.NonSignerQuorumBitmapIndices,
+		NonSignerPubkeys:             nonSignerPubkeysBN254,
+		QuorumApks:                   quorumApksBN254,
+		ApkG2:                        apkG2,
+		Sigma:                        sigma,
+		QuorumApkIndices:             indices.QuorumApkIndices,
+		TotalStakeIndices:            indices.TotalStakeIndices,
+		NonSignerStakeIndices:        indices.NonSignerStakeIndices,
+	}, nil
+}
+
 func (r *ChainReader) GetOperatorId(
 	opts *bind.CallOpts,
 	operatorAddress common.Address,
@@ -371,31 +492,41 @@ func (r *ChainReader) GetOperatorFromId(
 	return operatorAddress, nil
 }
 
+// QuorumRegistrationDetail indicates, for each quorum i, whether an operator is registered in quorum i
+type QuorumRegistrationDetail []bool
+
+// String returns a compact summary of the quorums the operator is registered in, e.g. "quorums: 0,2,5"
+func (d QuorumRegistrationDetail) String() string {
+	registeredQuorums := make([]string, 0, len(d))
+	for quorum, registered := range d {
+		if registered {
+			registeredQuorums = append(registeredQuorums, strconv.Itoa(quorum))
+		}
+	}
+	return "quorums: " + strings.Join(registeredQuorums, ",")
+}
+
+// QueryRegistrationDetail returns whether the operator is registered in each of the AVS's quorums. The result has one
+// entry per quorum (see GetQuorumCount); operators that are not registered get all entries set to false.
 func (r *ChainReader) QueryRegistrationDetail(
 	opts *bind.CallOpts,
 	operatorAddress common.Address,
-) ([]bool, error) {
+) (QuorumRegistrationDetail, error) {
 	operatorId, err := r.GetOperatorId(opts, operatorAddress)
 	if err != nil {
 		return nil, utils.WrapError("Failed to get operator id", err)
 	}
-	value, err := r.registryCoordinator.GetCurrentQuorumBitmap(opts, operatorId)
+	quorumBitmap, err := r.registryCoordinator.GetCurrentQuorumBitmap(opts, operatorId)
 	if err != nil {
 		return nil, utils.WrapError("Failed to get operator quorums", err)
 	}
-	numBits := value.BitLen()
-	var quorums []bool
-	for i := 0; i < numBits; i++ {
-		quorums = append(quorums, value.Int64()&(1<<i) != 0)
+	numQuorums, err := r.GetQuorumCount(opts)
+	if err != nil {
+		return nil, utils.WrapError("Failed to get quorum count", err)
 	}
-	if len(quorums) == 0 {
-		numQuorums, err := r.GetQuorumCount(opts)
-		if err != nil {
-			return nil, utils.WrapError("Failed to get quorum count", err)
-		}
-		for i := uint8(0); i < numQuorums; i++ {
-			quorums = append(quorums, false)
-		}
+	quorums := make(QuorumRegistrationDetail, numQuorums)
+	for i := range quorums {
+		quorums[i] = quorumBitmap.Bit(i) == 1
 	}
 	return quorums, nil
 }
@@ -418,6 +549,134 @@ func (r *ChainReader) IsOperatorRegistered(
 	return registeredWithAvs, nil
 }
 
+// GetOperatorIdsInQuorumAtBlock returns the ids of the operators registered in the quorum at blockNumber, read from the
+// IndexRegistry. The ids are returned in the IndexRegistry's canonical order (by operator index in the quorum), which
+// is the order that the indices used by BLSSignatureChecker.checkSignatures refer to, so callers must not re-sort them.
+func (r *ChainReader) GetOperatorIdsInQuorumAtBlock(
+	ctx context.Context,
+	quorum types.QuorumNum,
+	blockNumber uint32,
+) ([][32]byte, error) {
+	if r.indexRegistry == nil {
+		return nil, errors.New("IndexRegistry contract not provided")
+	}
+
+	operatorIds, err := r.indexRegistry.GetOperatorListAtBlockNumber(
+		&bind.CallOpts{Context: ctx},
+		quorum.UnderlyingType(),
+		blockNumber,
+	)
+	if err != nil {
+		return nil, utils.WrapError("Failed to get operator list at block number", err)
+	}
+	return operatorIds, nil
+}
+
+// GetQuorumUpdateBlockNumber returns the block at which the stakes of all operators in the quorum were last updated
+// (through updateOperatorsForQuorum). 0 means the quorum has never been updated.
+func (r *ChainReader) GetQuorumUpdateBlockNumber(ctx context.Context, quorum types.QuorumNum) (uint64, error) {
+	if r.registryCoordinator == nil {
+		return 0, errors.New("RegistryCoordinator contract not provided")
+	}
+
+	updateBlockNumber, err := r.registryCoordinator.QuorumUpdateBlockNumber(
+		&bind.CallOpts{Context: ctx},
+		quorum.UnderlyingType(),
+	)
+	if err != nil {
+		return 0, utils.WrapError("Failed to get quorum update block number", err)
+	}
+	return updateBlockNumber.Uint64(), nil
+}
+
+// IsQuorumStakeStale reports whether the quorum's stakes were last updated more than maxAgeBlocks blocks ago, along
+// with the age in blocks. Quorums that have never been updated are always stale, and their age is the current block
+// number.
+func (r *ChainReader) IsQuorumStakeStale(
+	ctx context.Context,
+	quorum types.QuorumNum,
+	maxAgeBlocks uint64,
+) (bool, uint64, error) {
+	updateBlockNumber, err := r.GetQuorumUpdateBlockNumber(ctx, quorum)
+	if err != nil {
+		return false, 0, err
+	}
+	curBlockNum, err := r.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return false, 0, utils.WrapError("Failed to get current block number", err)
+	}
+
+	var age uint64
+	if curBlockNum > updateBlockNumber {
+		age = curBlockNum - updateBlockNumber
+	}
+	if updateBlockNumber == 0 {
+		return true, age, nil
+	}
+	return age > maxAgeBlocks, age, nil
+}
+
+// GetMinimumStakeForQuorum returns the minimum weighted stake an operator needs to register for the given quorum
+func (r *ChainReader) GetMinimumStakeForQuorum(ctx context.Context, quorum types.QuorumNum) (*big.Int, error) {
+	if r.stakeRegistry == nil {
+		return nil, errors.New("StakeRegistry contract not provided")
+	}
+
+	minimumStake, err := r.stakeRegistry.MinimumStakeForQuorum(&bind.CallOpts{Context: ctx}, quorum.UnderlyingType())
+	if err != nil {
+		return nil, utils.WrapError("Failed to get minimum stake for quorum", err)
+	}
+	return minimumStake, nil
+}
+
+// GetStrategyParamsLength returns the number of strategies used to weigh stake in the given quorum
+func (r *ChainReader) GetStrategyParamsLength(ctx context.Context, quorum types.QuorumNum) (*big.Int, error) {
+	if r.stakeRegistry == nil {
+		return nil, errors.New("StakeRegistry contract not provided")
+	}
+
+	length, err := r.stakeRegistry.StrategyParamsLength(&bind.CallOpts{Context: ctx}, quorum.UnderlyingType())
+	if err != nil {
+		return nil, utils.WrapError("Failed to get strategy params length", err)
+	}
+	return length, nil
+}
+
+// GetStrategyParamsAtIndex returns the strategy and multiplier at the given index of the quorum's strategy list
+func (r *ChainReader) GetStrategyParamsAtIndex(
+	ctx context.Context,
+	quorum types.QuorumNum,
+	index *big.Int,
+) (StrategyParams, error) {
+	if r.stakeRegistry == nil {
+		return StrategyParams{}, errors.New("StakeRegistry contract not provided")
+	}
+
+	params, err := r.stakeRegistry.StrategyParamsByIndex(&bind.CallOpts{Context: ctx}, quorum.UnderlyingType(), index)
+	if err != nil {
+		return StrategyParams{}, utils.WrapError("Failed to get strategy params by index", err)
+	}
+	return params, nil
+}
+
+// GetQuorumStrategyParams returns all the strategies and multipliers used to weigh stake in the given quorum
+func (r *ChainReader) GetQuorumStrategyParams(ctx context.Context, quorum types.QuorumNum) ([]StrategyParams, error) {
+	length, err := r.GetStrategyParamsLength(ctx, quorum)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]StrategyParams, 0, length.Int64())
+	for i := int64(0); i < length.Int64(); i++ {
+		param, err := r.GetStrategyParamsAtIndex(ctx, quorum, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
 func (r *ChainReader) QueryExistingRegisteredOperatorPubKeys(
 	ctx context.Context,
 	startBlock *big.Int,
@@ -480,36 +739,11 @@ func (r *ChainReader) QueryExistingRegisteredOperatorPubKeys(
 		)
 
 		for _, vLog := range logs {
-			// get the operator address
-			operatorAddr := common.HexToAddress(vLog.Topics[1].Hex())
-			operatorAddresses = append(operatorAddresses, operatorAddr)
-
-			event, err := blsApkRegistryAbi.Unpack("NewPubkeyRegistration", vLog.Data)
+			operatorAddr, operatorPubkey, err := parseNewPubkeyRegistrationLog(blsApkRegistryAbi, vLog)
 			if err != nil {
-				return nil, nil, utils.WrapError("Cannot unpack event data", err)
+				return nil, nil, err
 			}
-
-			G1Pubkey := event[0].(struct {
-				X *big.Int "json:\"X\""
-				Y *big.Int "json:\"Y\""
-			})
-
-			G2Pubkey := event[1].(struct {
-				X [2]*big.Int "json:\"X\""
-				Y [2]*big.Int "json:\"Y\""
-			})
-
-			operatorPubkey := types.OperatorPubkeys{
-				G1Pubkey: bls.NewG1Point(
-					G1Pubkey.X,
-					G1Pubkey.Y,
-				),
-				G2Pubkey: bls.NewG2Point(
-					G2Pubkey.X,
-					G2Pubkey.Y,
-				),
-			}
-
+			operatorAddresses = append(operatorAddresses, operatorAddr)
 			operatorPubkeys = append(operatorPubkeys, operatorPubkey)
 		}
 	}
@@ -517,6 +751,41 @@ func (r *ChainReader) QueryExistingRegisteredOperatorPubKeys(
 	return operatorAddresses, operatorPubkeys, nil
 }
 
+func parseNewPubkeyRegistrationLog(
+	blsApkRegistryAbi *abi.ABI,
+	vLog gethtypes.Log,
+) (types.OperatorAddr, types.OperatorPubkeys, error) {
+	// get the operator address
+	operatorAddr := common.HexToAddress(vLog.Topics[1].Hex())
+
+	event, err := blsApkRegistryAbi.Unpack("NewPubkeyRegistration", vLog.Data)
+	if err != nil {
+		return common.Address{}, types.OperatorPubkeys{}, utils.WrapError("Cannot unpack event data", err)
+	}
+
+	G1Pubkey := event[0].(struct {
+		X *big.Int "json:\"X\""
+		Y *big.Int "json:\"Y\""
+	})
+
+	G2Pubkey := event[1].(struct {
+		X [2]*big.Int "json:\"X\""
+		Y [2]*big.Int "json:\"Y\""
+	})
+
+	operatorPubkey := types.OperatorPubkeys{
+		G1Pubkey: bls.NewG1Point(
+			G1Pubkey.X,
+			G1Pubkey.Y,
+		),
+		G2Pubkey: bls.NewG2Point(
+			G2Pubkey.X,
+			G2Pubkey.Y,
+		),
+	}
+	return operatorAddr, operatorPubkey, nil
+}
+
 func (r *ChainReader) QueryExistingRegisteredOperatorSockets(
 	ctx context.Context,
 	startBlock *big.Int,
@@ -582,3 +851,283 @@ func (r *ChainReader) QueryExistingRegisteredOperatorSockets(
 	}
 	return operatorIdToSocketMap, nil
 }
+
+// QueryOpts configures the event scans done by ChainReader.QueryExistingRegisteredOperators
+type QueryOpts struct {
+	// BlockRange is the number of blocks queried per eth_getLogs call. Defaults to DefaultQueryBlockRange.
+	BlockRange uint64
+}
+
+// OperatorInfo is the state of an operator, as rebuilt from the events of the AVS registry contracts
+type OperatorInfo struct {
+	OperatorAddr types.OperatorAddr
+	// Pubkeys are only set if the pubkey registration happened within the queried block range
+	Pubkeys      types.OperatorPubkeys
+	Socket       types.Socket
+	IsRegistered bool
+	// LastUpdateBlock is the block of the most recent event seen for this operator
+	LastUpdateBlock uint64
+	// LastRegistrationUpdateBlock is the block of the most recent OperatorRegistered or OperatorDeregistered event
+	// seen for this operator, 0 if there's none within the queried block range
+	LastRegistrationUpdateBlock uint64
+}
+
+// QueryExistingRegisteredOperators rebuilds the state of every operator that appears in the AVS registry events
+// between startBlock and stopBlock (inclusive, 0 meaning the current block), in a single chunked scan over the
+// NewPubkeyRegistration, OperatorSocketUpdate, OperatorRegistered and OperatorDeregistered events.
+// Events are applied in chain order, so operators that deregistered and registered again are reported as registered.
+func (r *ChainReader) QueryExistingRegisteredOperators(
+	ctx context.Context,
+	startBlock uint64,
+	stopBlock uint64,
+	opts QueryOpts,
+) (map[types.OperatorId]OperatorInfo, error) {
+	if r.registryCoordinator == nil {
+		return nil, errors.New("RegistryCoordinator contract not provided")
+	}
+	blsApkRegistryAbi, err := apkreg.ContractBLSApkRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, utils.WrapError("Cannot get Abi", err)
+	}
+	regCoordAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return nil, utils.WrapError("Cannot get Abi", err)
+	}
+
+	if stopBlock == 0 {
+		stopBlock, err = r.ethClient.BlockNumber(ctx)
+		if err != nil {
+			return nil, utils.WrapError("Cannot get current block number", err)
+		}
+	}
+	blockRange := opts.BlockRange
+	if blockRange == 0 {
+		blockRange = DefaultQueryBlockRange.Uint64()
+	}
+
+	newPubkeyRegistrationId := blsApkRegistryAbi.Events["NewPubkeyRegistration"].ID
+	operatorSocketUpdateId := regCoordAbi.Events["OperatorSocketUpdate"].ID
+	operatorRegisteredId := regCoordAbi.Events["OperatorRegistered"].ID
+	operatorDeregisteredId := regCoordAbi.Events["OperatorDeregistered"].ID
+
+	operators := make(map[types.OperatorId]OperatorInfo)
+	err = chainioutils.ForEachBlockChunk(startBlock, stopBlock, blockRange, func(fromBlock, toBlock uint64) error {
+		query := ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: []common.Address{r.blsApkRegistryAddr, r.registryCoordinatorAddr},
+			Topics: [][]common.Hash{{
+				newPubkeyRegistrationId,
+				operatorSocketUpdateId,
+				operatorRegisteredId,
+				operatorDeregisteredId,
+			}},
+		}
+		logs, err := r.ethClient.FilterLogs(ctx, query)
+		if err != nil {
+			return utils.WrapError("Cannot filter logs", err)
+		}
+		r.logger.Debug(
+			"avsRegistryChainReader.QueryExistingRegisteredOperators",
+			"numTransactionLogs",
+			len(logs),
+			"fromBlock",
+			fromBlock,
+			"toBlock",
+			toBlock,
+		)
+
+		// logs from different contracts are only ordered within a contract, so we sort them to apply them in chain
+		// order
+		sort.SliceStable(logs, func(i, j int) bool {
+			if logs[i].BlockNumber != logs[j].BlockNumber {
+				return logs[i].BlockNumber < logs[j].BlockNumber
+			}
+			return logs[i].Index < logs[j].Index
+		})
+		for _, vLog := range logs {
+			if len(vLog.Topics) == 0 {
+				continue
+			}
+			var operatorId types.OperatorId
+			var operator OperatorInfo
+			switch {
+			case vLog.Address == r.blsApkRegistryAddr && vLog.Topics[0] == newPubkeyRegistrationId:
+				operatorAddr, pubkeys, err := parseNewPubkeyRegistrationLog(blsApkRegistryAbi, vLog)
+				if err != nil {
+					return err
+				}
+				operatorId = types.OperatorIdFromG1Pubkey(pubkeys.G1Pubkey)
+				operator = operators[operatorId]
+				operator.OperatorAddr = operatorAddr
+				operator.Pubkeys = pubkeys
+			case vLog.Address != r.registryCoordinatorAddr:
+				continue
+			case vLog.Topics[0] == operatorSocketUpdateId:
+				event, err := r.registryCoordinator.ParseOperatorSocketUpdate(vLog)
+				if err != nil {
+					return utils.WrapError("Cannot parse OperatorSocketUpdate event", err)
+				}
+				operatorId = event.OperatorId
+				operator = operators[operatorId]
+				operator.Socket = types.Socket(event.Socket)
+			case vLog.Topics[0] == operatorRegisteredId:
+				event, err := r.registryCoordinator.ParseOperatorRegistered(vLog)
+				if err != nil {
+					return utils.WrapError("Cannot parse OperatorRegistered event", err)
+				}
+				operatorId = event.OperatorId
+				operator = operators[operatorId]
+				operator.OperatorAddr = event.Operator
+				operator.IsRegistered = true
+				operator.LastRegistrationUpdateBlock = vLog.BlockNumber
+			case vLog.Topics[0] == operatorDeregisteredId:
+				event, err := r.registryCoordinator.ParseOperatorDeregistered(vLog)
+				if err != nil {
+					return utils.WrapError("Cannot parse OperatorDeregistered event", err)
+				}
+				operatorId = event.OperatorId
+				operator = operators[operatorId]
+				operator.OperatorAddr = event.Operator
+				operator.IsRegistered = false
+				operator.LastRegistrationUpdateBlock = vLog.BlockNumber
+			default:
+				continue
+			}
+			operator.LastUpdateBlock = vLog.BlockNumber
+			operators[operatorId] = operator
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return operators, nil
+}
+
+// QueryOperatorInfo rebuilds the state of the operator operatorAddr from its AVS registry events between startBlock and
+// stopBlock (inclusive, 0 meaning the current block), like QueryExistingRegisteredOperators but only querying the
+// events of this operator. It returns false if the operator didn't register its pubkeys within the queried range.
+func (r *ChainReader) QueryOperatorInfo(
+	ctx context.Context,
+	operatorAddr common.Address,
+	startBlock uint64,
+	stopBlock uint64,
+	opts QueryOpts,
+) (OperatorInfo, bool, error) {
+	if r.registryCoordinator == nil {
+		return OperatorInfo{}, false, errors.New("RegistryCoordinator contract not provided")
+	}
+	blsApkRegistryAbi, err := apkreg.ContractBLSApkRegistryMetaData.GetAbi()
+	if err != nil {
+		return OperatorInfo{}, false, utils.WrapError("Cannot get Abi", err)
+	}
+	regCoordAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return OperatorInfo{}, false, utils.WrapError("Cannot get Abi", err)
+	}
+	if stopBlock == 0 {
+		stopBlock, err = r.ethClient.BlockNumber(ctx)
+		if err != nil {
+			return OperatorInfo{}, false, utils.WrapError("Cannot get current block number", err)
+		}
+	}
+
+	newPubkeyRegistrationId := blsApkRegistryAbi.Events["NewPubkeyRegistration"].ID
+	operatorRegisteredId := regCoordAbi.Events["OperatorRegistered"].ID
+	operatorDeregisteredId := regCoordAbi.Events["OperatorDeregistered"].ID
+	// the operator address is the first indexed argument of these events
+	logs, err := r.filterLogsInRange(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{r.blsApkRegistryAddr, r.registryCoordinatorAddr},
+		Topics: [][]common.Hash{
+			{newPubkeyRegistrationId, operatorRegisteredId, operatorDeregisteredId},
+			{common.BytesToHash(operatorAddr.Bytes())},
+		},
+	}, startBlock, stopBlock, opts)
+	if err != nil {
+		return OperatorInfo{}, false, err
+	}
+	operator := OperatorInfo{OperatorAddr: operatorAddr}
+	for _, vLog := range logs {
+		switch {
+		case vLog.Address == r.blsApkRegistryAddr && vLog.Topics[0] == newPubkeyRegistrationId:
+			_, pubkeys, err := parseNewPubkeyRegistrationLog(blsApkRegistryAbi, vLog)
+			if err != nil {
+				return OperatorInfo{}, false, err
+			}
+			operator.Pubkeys = pubkeys
+		case vLog.Address != r.registryCoordinatorAddr:
+			continue
+		case vLog.Topics[0] == operatorRegisteredId:
+			operator.IsRegistered = true
+			operator.LastRegistrationUpdateBlock = vLog.BlockNumber
+		case vLog.Topics[0] == operatorDeregisteredId:
+			operator.IsRegistered = false
+			operator.LastRegistrationUpdateBlock = vLog.BlockNumber
+		default:
+			continue
+		}
+		operator.LastUpdateBlock = vLog.BlockNumber
+	}
+	if operator.Pubkeys.G1Pubkey == nil {
+		return operator, false, nil
+	}
+
+	operatorSocketUpdateId := regCoordAbi.Events["OperatorSocketUpdate"].ID
+	operatorId := types.OperatorIdFromG1Pubkey(operator.Pubkeys.G1Pubkey)
+	logs, err = r.filterLogsInRange(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{r.registryCoordinatorAddr},
+		Topics:    [][]common.Hash{{operatorSocketUpdateId}, {common.Hash(operatorId)}},
+	}, startBlock, stopBlock, opts)
+	if err != nil {
+		return OperatorInfo{}, false, err
+	}
+	for _, vLog := range logs {
+		event, err := r.registryCoordinator.ParseOperatorSocketUpdate(vLog)
+		if err != nil {
+			return OperatorInfo{}, false, utils.WrapError("Cannot parse OperatorSocketUpdate event", err)
+		}
+		operator.Socket = types.Socket(event.Socket)
+		if vLog.BlockNumber > operator.LastUpdateBlock {
+			operator.LastUpdateBlock = vLog.BlockNumber
+		}
+	}
+	return operator, true, nil
+}
+
+// filterLogsInRange returns the logs of query between startBlock and stopBlock (inclusive) in chain order, querying
+// them in chunks of opts.BlockRange blocks
+func (r *ChainReader) filterLogsInRange(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	startBlock uint64,
+	stopBlock uint64,
+	opts QueryOpts,
+) ([]gethtypes.Log, error) {
+	blockRange := opts.BlockRange
+	if blockRange == 0 {
+		blockRange = DefaultQueryBlockRange.Uint64()
+	}
+	var logs []gethtypes.Log
+	err := chainioutils.ForEachBlockChunk(startBlock, stopBlock, blockRange, func(fromBlock, toBlock uint64) error {
+		query.FromBlock = new(big.Int).SetUint64(fromBlock)
+		query.ToBlock = new(big.Int).SetUint64(toBlock)
+		chunkLogs, err := r.ethClient.FilterLogs(ctx, query)
+		if err != nil {
+			return utils.WrapError("Cannot filter logs", err)
+		}
+		logs = append(logs, chunkLogs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// logs from different contracts are only ordered within a contract, so we sort them to apply them in chain order
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+	return logs, nil
+}
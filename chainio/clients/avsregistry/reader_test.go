@@ -5,10 +5,13 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/Layr-Labs/eigensdk-go/testutils/testclients"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/require"
 )
 
@@ -102,6 +105,8 @@ func TestReaderMethods(t *testing.T) {
 		quorums, err := chainReader.QueryRegistrationDetail(&bind.CallOpts{}, operatorAddress)
 		require.NoError(t, err)
 		require.Equal(t, 1, len(quorums))
+		require.False(t, quorums[0])
+		require.Equal(t, "quorums: ", quorums.String())
 	})
 
 	t.Run("is operator registered", func(t *testing.T) {
@@ -135,4 +140,91 @@ func TestReaderMethods(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, 0, len(address_to_sockets))
 		})
+
+	t.Run("never updated quorum is stale", func(t *testing.T) {
+		quorumCount, err := chainReader.GetQuorumCount(&bind.CallOpts{})
+		require.NoError(t, err)
+		// quorums past the quorum count have never been created, let alone updated
+		isStale, _, err := chainReader.IsQuorumStakeStale(context.Background(), types.QuorumNum(quorumCount), 1000)
+		require.NoError(t, err)
+		require.True(t, isStale)
+	})
+
+	t.Run("get minimum stake for quorum", func(t *testing.T) {
+		minimumStake, err := chainReader.GetMinimumStakeForQuorum(context.Background(), quorumNumbers[0])
+		require.NoError(t, err)
+		require.NotNil(t, minimumStake)
+	})
+
+	t.Run("get quorum strategy params", func(t *testing.T) {
+		length, err := chainReader.GetStrategyParamsLength(context.Background(), quorumNumbers[0])
+		require.NoError(t, err)
+
+		params, err := chainReader.GetQuorumStrategyParams(context.Background(), quorumNumbers[0])
+		require.NoError(t, err)
+		require.Equal(t, length.Int64(), int64(len(params)))
+
+		for i, param := range params {
+			paramAtIndex, err := chainReader.GetStrategyParamsAtIndex(
+				context.Background(),
+				quorumNumbers[0],
+				big.NewInt(int64(i)),
+			)
+			require.NoError(t, err)
+			require.Equal(t, paramAtIndex.Strategy, param.Strategy)
+			require.Equal(t, 0, paramAtIndex.Multiplier.Cmp(param.Multiplier))
+		}
+	})
+
+	t.Run("compute operator weight matches stake registry", func(t *testing.T) {
+		operatorAddr := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+		params, err := chainReader.GetQuorumStrategyParams(context.Background(), quorumNumbers[0])
+		require.NoError(t, err)
+
+		shares := make(map[common.Address]*big.Int)
+		for _, param := range params {
+			strategyShares, err := clients.ElChainReader.GetOperatorSharesInStrategy(
+				context.Background(),
+				operatorAddr,
+				param.Strategy,
+			)
+			require.NoError(t, err)
+			shares[param.Strategy] = strategyShares
+		}
+
+		expectedWeight, err := clients.AvsRegistryContractBindings.StakeRegistry.WeightOfOperatorForQuorum(
+			&bind.CallOpts{},
+			quorumNumbers[0].UnderlyingType(),
+			operatorAddr,
+		)
+		require.NoError(t, err)
+		require.Equal(t, 0, expectedWeight.Cmp(avsregistry.ComputeOperatorWeight(shares, params)))
+	})
+}
+
+func TestQuorumRegistrationDetailString(t *testing.T) {
+	detail := avsregistry.QuorumRegistrationDetail{true, false, true, false, false, true}
+	require.Equal(t, "quorums: 0,2,5", detail.String())
+	require.Equal(t, "quorums: ", avsregistry.QuorumRegistrationDetail{false}.String())
+}
+
+func TestReaderFromConfigWithServiceManagerAddress(t *testing.T) {
+	_, anvilHttpEndpoint := testclients.BuildTestClients(t)
+	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	ethHttpClient, err := ethclient.Dial(anvilHttpEndpoint)
+	require.NoError(t, err)
+
+	bindings, err := avsregistry.NewBindingsFromConfig(
+		avsregistry.Config{
+			RegistryCoordinatorAddress:    contractAddrs.RegistryCoordinator,
+			OperatorStateRetrieverAddress: contractAddrs.OperatorStateRetriever,
+			ServiceManagerAddress:         contractAddrs.ServiceManager,
+		},
+		ethHttpClient,
+		testutils.GetTestLogger(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, contractAddrs.ServiceManager, bindings.ServiceManagerAddr)
+	require.NotNil(t, bindings.ServiceManager)
+	require.NotEqual(t, common.Address{}, bindings.AvsDirectoryAddr)
 }
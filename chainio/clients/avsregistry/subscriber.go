@@ -1,36 +1,53 @@
 package avsregistry
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
 	blsapkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
 	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/Layr-Labs/eigensdk-go/utils"
 )
 
 type ChainSubscriber struct {
-	logger         logging.Logger
-	regCoord       regcoord.ContractRegistryCoordinatorFilters
-	blsApkRegistry blsapkreg.ContractBLSApkRegistryFilters
+	logger             logging.Logger
+	regCoordAddr       common.Address
+	blsApkRegistryAddr common.Address
+	regCoord           regcoord.ContractRegistryCoordinatorFilters
+	blsApkRegistry     blsapkreg.ContractBLSApkRegistryFilters
+	logFilterer        ethereum.LogFilterer
 }
 
 // NewChainSubscriber creates a new instance of ChainSubscriber
-// The bindings must be created using websocket ETH Client
+// The bindings and the log filterer must be created using websocket ETH Client
 func NewChainSubscriber(
+	regCoordAddr common.Address,
+	blsApkRegistryAddr common.Address,
 	regCoord regcoord.ContractRegistryCoordinatorFilters,
 	blsApkRegistry blsapkreg.ContractBLSApkRegistryFilters,
+	logFilterer ethereum.LogFilterer,
 	logger logging.Logger,
 ) *ChainSubscriber {
 	logger = logger.With(logging.ComponentKey, "avsregistry/ChainSubscriber")
 
 	return &ChainSubscriber{
-		regCoord:       regCoord,
-		blsApkRegistry: blsApkRegistry,
-		logger:         logger,
+		regCoordAddr:       regCoordAddr,
+		blsApkRegistryAddr: blsApkRegistryAddr,
+		regCoord:           regCoord,
+		blsApkRegistry:     blsApkRegistry,
+		logFilterer:        logFilterer,
+		logger:             logger,
 	}
 }
 
@@ -53,7 +70,41 @@ func BuildAvsRegistryChainSubscriber(
 	if err != nil {
 		return nil, utils.WrapError("Failed to create BLSApkRegistry contract", err)
 	}
-	return NewChainSubscriber(regCoord, blsApkReg, logger), nil
+	return NewChainSubscriber(regCoordAddr, blsApkRegAddr, regCoord, blsApkReg, ethWsClient, logger), nil
+}
+
+// newSubscriberFromBindings creates a new instance of ChainSubscriber reusing the contract addresses already resolved
+// in bindings, so that no additional RPC calls are needed. A websocket ETH Client must be provided
+func newSubscriberFromBindings(
+	bindings *ContractBindings,
+	wsClient eth.WsBackend,
+	logger logging.Logger,
+) (*ChainSubscriber, error) {
+	var (
+		regCoord  regcoord.ContractRegistryCoordinatorFilters
+		blsApkReg blsapkreg.ContractBLSApkRegistryFilters
+	)
+	// interface fields are only set when the contracts exist, so that the not provided checks work
+	if bindings.RegistryCoordinator != nil {
+		regCoordContract, err := regcoord.NewContractRegistryCoordinator(bindings.RegistryCoordinatorAddr, wsClient)
+		if err != nil {
+			return nil, utils.WrapError("Failed to create RegistryCoordinator contract", err)
+		}
+		blsApkRegContract, err := blsapkreg.NewContractBLSApkRegistry(bindings.BlsApkRegistryAddr, wsClient)
+		if err != nil {
+			return nil, utils.WrapError("Failed to create BLSApkRegistry contract", err)
+		}
+		regCoord, blsApkReg = regCoordContract, blsApkRegContract
+	}
+
+	return NewChainSubscriber(
+		bindings.RegistryCoordinatorAddr,
+		bindings.BlsApkRegistryAddr,
+		regCoord,
+		blsApkReg,
+		wsClient,
+		logger,
+	), nil
 }
 
 // NewSubscriberFromConfig creates a new instance of ChainSubscriber
@@ -68,13 +119,28 @@ func NewSubscriberFromConfig(
 		return nil, err
 	}
 
-	return NewChainSubscriber(bindings.RegistryCoordinator, bindings.BlsApkRegistry, logger), nil
+	return newSubscriberFromBindings(bindings, wsClient, logger)
 }
 
+// SubscribeToNewPubkeyRegistrations subscribes to BLS pubkey registrations of all operators
 func (s *ChainSubscriber) SubscribeToNewPubkeyRegistrations() (chan *blsapkreg.ContractBLSApkRegistryNewPubkeyRegistration, event.Subscription, error) {
-	newPubkeyRegistrationChan := make(chan *blsapkreg.ContractBLSApkRegistryNewPubkeyRegistration)
-	sub, err := s.blsApkRegistry.WatchNewPubkeyRegistration(
-		&bind.WatchOpts{}, newPubkeyRegistrationChan, nil,
+	if s.blsApkRegistry == nil {
+		return nil, nil, errors.New("BLSApkRegistry contract not provided")
+	}
+	contractAbi, err := blsapkreg.ContractBLSApkRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, nil, utils.WrapError("Failed to get BLSApkRegistry abi", err)
+	}
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{s.blsApkRegistryAddr},
+		Topics:    [][]common.Hash{{contractAbi.Events["NewPubkeyRegistration"].ID}},
+	}
+	newPubkeyRegistrationChan, sub, err := subscribeToEvent(
+		context.Background(),
+		s.logFilterer,
+		s.blsApkRegistry.ParseNewPubkeyRegistration,
+		query,
+		SubOpts{},
 	)
 	if err != nil {
 		return nil, nil, utils.WrapError("Failed to subscribe to NewPubkeyRegistration events", err)
@@ -82,13 +148,223 @@ func (s *ChainSubscriber) SubscribeToNewPubkeyRegistrations() (chan *blsapkreg.C
 	return newPubkeyRegistrationChan, sub, nil
 }
 
+// SubscribeToOperatorSocketUpdates subscribes to socket updates of all operators
 func (s *ChainSubscriber) SubscribeToOperatorSocketUpdates() (chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate, event.Subscription, error) {
-	operatorSocketUpdateChan := make(chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate)
-	sub, err := s.regCoord.WatchOperatorSocketUpdate(
-		&bind.WatchOpts{}, operatorSocketUpdateChan, nil,
+	return s.subscribeToOperatorSocketUpdates(context.Background(), SubOpts{})
+}
+
+// SubscribeToOperatorSocketUpdatesWithOpts subscribes to operator socket updates, optionally only for the operators
+// in opts.OperatorIds
+func (s *ChainSubscriber) SubscribeToOperatorSocketUpdatesWithOpts(
+	ctx context.Context,
+	opts SubOpts,
+) (<-chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate, event.Subscription, error) {
+	return s.subscribeToOperatorSocketUpdates(ctx, opts)
+}
+
+func (s *ChainSubscriber) subscribeToOperatorSocketUpdates(
+	ctx context.Context,
+	opts SubOpts,
+) (chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate, event.Subscription, error) {
+	if s.regCoord == nil {
+		return nil, nil, errors.New("RegistryCoordinator contract not provided")
+	}
+	contractAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return nil, nil, utils.WrapError("Failed to get RegistryCoordinator abi", err)
+	}
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{s.regCoordAddr},
+		Topics:    [][]common.Hash{{contractAbi.Events["OperatorSocketUpdate"].ID}},
+	}
+	// the operator id is the first indexed argument of OperatorSocketUpdate
+	opts.operatorIdTopic = 1
+	operatorSocketUpdateChan, sub, err := subscribeToEvent(
+		ctx,
+		s.logFilterer,
+		s.regCoord.ParseOperatorSocketUpdate,
+		query,
+		opts,
 	)
 	if err != nil {
 		return nil, nil, utils.WrapError("Failed to subscribe to OperatorSocketUpdate events", err)
 	}
 	return operatorSocketUpdateChan, sub, nil
 }
+
+// SubscribeToOperatorRegistrations subscribes to the registrations of all operators with the RegistryCoordinator,
+// including their registrations after a deregistration
+func (s *ChainSubscriber) SubscribeToOperatorRegistrations() (<-chan *regcoord.ContractRegistryCoordinatorOperatorRegistered, event.Subscription, error) {
+	return s.SubscribeToOperatorRegistrationsWithOpts(context.Background(), SubOpts{})
+}
+
+// SubscribeToOperatorRegistrationsWithOpts subscribes to operator registrations, optionally only for the operators
+// in opts.OperatorIds
+func (s *ChainSubscriber) SubscribeToOperatorRegistrationsWithOpts(
+	ctx context.Context,
+	opts SubOpts,
+) (<-chan *regcoord.ContractRegistryCoordinatorOperatorRegistered, event.Subscription, error) {
+	if s.regCoord == nil {
+		return nil, nil, errors.New("RegistryCoordinator contract not provided")
+	}
+	contractAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return nil, nil, utils.WrapError("Failed to get RegistryCoordinator abi", err)
+	}
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{s.regCoordAddr},
+		Topics:    [][]common.Hash{{contractAbi.Events["OperatorRegistered"].ID}},
+	}
+	// the operator id is the second indexed argument of OperatorRegistered, after the operator address
+	opts.operatorIdTopic = 2
+	operatorRegisteredChan, sub, err := subscribeToEvent(
+		ctx,
+		s.logFilterer,
+		s.regCoord.ParseOperatorRegistered,
+		query,
+		opts,
+	)
+	if err != nil {
+		return nil, nil, utils.WrapError("Failed to subscribe to OperatorRegistered events", err)
+	}
+	return operatorRegisteredChan, sub, nil
+}
+
+// SubscribeToOperatorDeregistrations subscribes to the deregistrations of all operators from the RegistryCoordinator
+func (s *ChainSubscriber) SubscribeToOperatorDeregistrations() (<-chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered, event.Subscription, error) {
+	return s.SubscribeToOperatorDeregistrationsWithOpts(context.Background(), SubOpts{})
+}
+
+// SubscribeToOperatorDeregistrationsWithOpts subscribes to operator deregistrations, optionally only for the
+// operators in opts.OperatorIds
+func (s *ChainSubscriber) SubscribeToOperatorDeregistrationsWithOpts(
+	ctx context.Context,
+	opts SubOpts,
+) (<-chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered, event.Subscription, error) {
+	if s.regCoord == nil {
+		return nil, nil, errors.New("RegistryCoordinator contract not provided")
+	}
+	contractAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return nil, nil, utils.WrapError("Failed to get RegistryCoordinator abi", err)
+	}
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{s.regCoordAddr},
+		Topics:    [][]common.Hash{{contractAbi.Events["OperatorDeregistered"].ID}},
+	}
+	// the operator id is the second indexed argument of OperatorDeregistered, after the operator address
+	opts.operatorIdTopic = 2
+	operatorDeregisteredChan, sub, err := subscribeToEvent(
+		ctx,
+		s.logFilterer,
+		s.regCoord.ParseOperatorDeregistered,
+		query,
+		opts,
+	)
+	if err != nil {
+		return nil, nil, utils.WrapError("Failed to subscribe to OperatorDeregistered events", err)
+	}
+	return operatorDeregisteredChan, sub, nil
+}
+
+// SubOpts configures a subscription created by SubscribeToEvent
+type SubOpts struct {
+	// BufferSize is the capacity of the returned channel. 0 means unbuffered.
+	BufferSize int
+	// OperatorIds, if non-empty, restricts the subscription to the events of these operator ids. The subscribe
+	// methods of ChainSubscriber filter the indexed operator id of their event, while SubscribeToEvent filters the
+	// first indexed argument of the event, which must then be the operator id (e.g. OperatorSocketUpdate, but not
+	// OperatorRegistered whose first indexed argument is the operator address).
+	OperatorIds []types.OperatorId
+	// DropWhenFull makes the subscription drop events instead of blocking when the returned channel is full.
+	// Every dropped event is counted in DroppedEvents, which is then required.
+	DropWhenFull bool
+	// DroppedEvents counts the events dropped because the channel was full. Only used when DropWhenFull is set.
+	DroppedEvents prometheus.Counter
+
+	// operatorIdTopic is the index in the topics of the logs of the operator id filtered with OperatorIds, set by the
+	// subscribe methods of ChainSubscriber for their event. 0 means 1, the first indexed argument.
+	operatorIdTopic int
+}
+
+// SubscribeToEvent subscribes to the logs matching query, parses them with parseFn and forwards the parsed events on
+// the returned channel. By default, delivery blocks until the event is read, so no events are lost; see SubOpts for
+// buffering and dropping behavior. Parsing errors terminate the subscription and are reported on its Err channel.
+func SubscribeToEvent[T any](
+	ctx context.Context,
+	logFilterer ethereum.LogFilterer,
+	parseFn func(gethtypes.Log) (T, error),
+	query ethereum.FilterQuery,
+	opts SubOpts,
+) (<-chan T, event.Subscription, error) {
+	return subscribeToEvent(ctx, logFilterer, parseFn, query, opts)
+}
+
+// subscribeToEvent is SubscribeToEvent filtering the operator ids in the topic opts.operatorIdTopic, and returning a
+// bidirectional channel for the subscribe methods which returned one before SubscribeToEvent was added
+func subscribeToEvent[T any](
+	ctx context.Context,
+	logFilterer ethereum.LogFilterer,
+	parseFn func(gethtypes.Log) (T, error),
+	query ethereum.FilterQuery,
+	opts SubOpts,
+) (chan T, event.Subscription, error) {
+	if opts.BufferSize < 0 {
+		return nil, nil, fmt.Errorf("invalid buffer size %d", opts.BufferSize)
+	}
+	if opts.DropWhenFull && opts.DroppedEvents == nil {
+		return nil, nil, errors.New("a DroppedEvents counter is required when DropWhenFull is set")
+	}
+	if len(opts.OperatorIds) > 0 {
+		operatorIdTopics := make([]common.Hash, len(opts.OperatorIds))
+		for i, operatorId := range opts.OperatorIds {
+			operatorIdTopics[i] = common.Hash(operatorId)
+		}
+		operatorIdTopic := max(1, opts.operatorIdTopic)
+		// copy the topics so that the caller's query is left untouched
+		topics := make([][]common.Hash, max(operatorIdTopic+1, len(query.Topics)))
+		copy(topics, query.Topics)
+		topics[operatorIdTopic] = operatorIdTopics
+		query.Topics = topics
+	}
+
+	logs := make(chan gethtypes.Log)
+	logSub, err := logFilterer.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sink := make(chan T, opts.BufferSize)
+	sub := event.NewSubscription(func(quit <-chan struct{}) error {
+		defer logSub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				parsed, err := parseFn(log)
+				if err != nil {
+					return err
+				}
+				if opts.DropWhenFull {
+					select {
+					case sink <- parsed:
+					default:
+						opts.DroppedEvents.Inc()
+					}
+					continue
+				}
+				select {
+				case sink <- parsed:
+				case err := <-logSub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-logSub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+	return sink, sub, nil
+}
@@ -0,0 +1,213 @@
+package avsregistry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	blsapkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogFilterer records the last subscription query and lets the test push logs into it
+type fakeLogFilterer struct {
+	query ethereum.FilterQuery
+	logsC chan<- gethtypes.Log
+}
+
+func (f *fakeLogFilterer) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	return nil, nil
+}
+
+func (f *fakeLogFilterer) SubscribeFilterLogs(
+	ctx context.Context,
+	q ethereum.FilterQuery,
+	ch chan<- gethtypes.Log,
+) (ethereum.Subscription, error) {
+	f.query = q
+	f.logsC = ch
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func parseBlockNumber(log gethtypes.Log) (uint64, error) {
+	if log.BlockNumber == 0 {
+		return 0, errors.New("invalid log")
+	}
+	return log.BlockNumber, nil
+}
+
+func TestSubscribeToEvent(t *testing.T) {
+	eventTopic := common.HexToHash("0x1")
+	query := ethereum.FilterQuery{Topics: [][]common.Hash{{eventTopic}}}
+
+	t.Run("forwards parsed events", func(t *testing.T) {
+		filterer := &fakeLogFilterer{}
+		eventsC, sub, err := avsregistry.SubscribeToEvent(
+			context.Background(),
+			filterer,
+			parseBlockNumber,
+			query,
+			avsregistry.SubOpts{},
+		)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		go func() { filterer.logsC <- gethtypes.Log{BlockNumber: 7} }()
+		select {
+		case blockNumber := <-eventsC:
+			require.Equal(t, uint64(7), blockNumber)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+	})
+
+	t.Run("filters by operator id", func(t *testing.T) {
+		filterer := &fakeLogFilterer{}
+		operatorId := types.OperatorId{0x1}
+		_, sub, err := avsregistry.SubscribeToEvent(
+			context.Background(),
+			filterer,
+			parseBlockNumber,
+			query,
+			avsregistry.SubOpts{OperatorIds: []types.OperatorId{operatorId}},
+		)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		require.Equal(t, [][]common.Hash{{eventTopic}, {common.Hash(operatorId)}}, filterer.query.Topics)
+		// the caller's query must not be modified
+		require.Len(t, query.Topics, 1)
+	})
+
+	t.Run("counts events dropped when the channel is full", func(t *testing.T) {
+		filterer := &fakeLogFilterer{}
+		droppedEvents := prometheus.NewCounter(prometheus.CounterOpts{Name: "dropped_events"})
+		eventsC, sub, err := avsregistry.SubscribeToEvent(
+			context.Background(),
+			filterer,
+			parseBlockNumber,
+			query,
+			avsregistry.SubOpts{BufferSize: 1, DropWhenFull: true, DroppedEvents: droppedEvents},
+		)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		for i := uint64(1); i <= 3; i++ {
+			filterer.logsC <- gethtypes.Log{BlockNumber: i}
+		}
+		// the third send is only accepted once the second log has been processed, so wait for it to be dropped too
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(droppedEvents) == 2
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, uint64(1), <-eventsC)
+	})
+
+	t.Run("requires a counter to drop events", func(t *testing.T) {
+		_, _, err := avsregistry.SubscribeToEvent(
+			context.Background(),
+			&fakeLogFilterer{},
+			parseBlockNumber,
+			query,
+			avsregistry.SubOpts{DropWhenFull: true},
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("parse errors terminate the subscription", func(t *testing.T) {
+		filterer := &fakeLogFilterer{}
+		_, sub, err := avsregistry.SubscribeToEvent(
+			context.Background(),
+			filterer,
+			parseBlockNumber,
+			query,
+			avsregistry.SubOpts{},
+		)
+		require.NoError(t, err)
+
+		filterer.logsC <- gethtypes.Log{}
+		select {
+		case err := <-sub.Err():
+			require.Error(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for subscription error")
+		}
+	})
+}
+
+func TestSubscribeToOperatorRegistrationsWithOpts(t *testing.T) {
+	filterer := &fakeLogFilterer{}
+	regCoordAddr := common.HexToAddress("0x1")
+	regCoord, err := regcoord.NewContractRegistryCoordinatorFilterer(regCoordAddr, filterer)
+	require.NoError(t, err)
+	blsApkRegistry, err := blsapkreg.NewContractBLSApkRegistryFilterer(common.HexToAddress("0x2"), filterer)
+	require.NoError(t, err)
+	subscriber := avsregistry.NewChainSubscriber(
+		regCoordAddr,
+		common.HexToAddress("0x2"),
+		regCoord,
+		blsApkRegistry,
+		filterer,
+		testutils.GetTestLogger(),
+	)
+
+	operatorAddr := common.HexToAddress("0x3")
+	operatorId := types.OperatorId{0x4}
+	registeredC, sub, err := subscriber.SubscribeToOperatorRegistrationsWithOpts(
+		context.Background(),
+		avsregistry.SubOpts{OperatorIds: []types.OperatorId{operatorId}},
+	)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	// the operator id is the second indexed argument of OperatorRegistered, after the operator address
+	contractAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	require.NoError(t, err)
+	eventId := contractAbi.Events["OperatorRegistered"].ID
+	require.Equal(t, [][]common.Hash{{eventId}, nil, {common.Hash(operatorId)}}, filterer.query.Topics)
+
+	go func() {
+		filterer.logsC <- gethtypes.Log{
+			Address:     regCoordAddr,
+			Topics:      []common.Hash{eventId, common.BytesToHash(operatorAddr.Bytes()), common.Hash(operatorId)},
+			BlockNumber: 7,
+		}
+	}()
+	select {
+	case registered := <-registeredC:
+		require.Equal(t, operatorAddr, registered.Operator)
+		require.Equal(t, [32]byte(operatorId), registered.OperatorId)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
+
+func TestSubscriberWithoutContracts(t *testing.T) {
+	subscriber, err := avsregistry.NewSubscriberFromConfig(avsregistry.Config{}, nil, testutils.GetTestLogger())
+	require.NoError(t, err)
+
+	_, _, err = subscriber.SubscribeToNewPubkeyRegistrations()
+	require.ErrorContains(t, err, "BLSApkRegistry contract not provided")
+
+	_, _, err = subscriber.SubscribeToOperatorSocketUpdates()
+	require.ErrorContains(t, err, "RegistryCoordinator contract not provided")
+
+	_, _, err = subscriber.SubscribeToOperatorRegistrations()
+	require.ErrorContains(t, err, "RegistryCoordinator contract not provided")
+
+	_, _, err = subscriber.SubscribeToOperatorDeregistrations()
+	require.ErrorContains(t, err, "RegistryCoordinator contract not provided")
+}
@@ -0,0 +1,33 @@
+package avsregistry
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	stakeregistry "github.com/Layr-Labs/eigensdk-go/contracts/bindings/StakeRegistry"
+)
+
+// StrategyParams is a strategy used by a quorum to weigh operator stake, along with its multiplier
+type StrategyParams = stakeregistry.IStakeRegistryStrategyParams
+
+// WeightingDivisor mirrors StakeRegistry.WEIGHTING_DIVISOR, which every strategy multiplier is scaled by
+var WeightingDivisor = big.NewInt(1e18)
+
+// ComputeOperatorWeight replicates StakeRegistry.weightOfOperatorForQuorum off-chain.
+// shares maps each strategy to the shares delegated to the operator in it; strategies missing from the map
+// count as zero shares. As on-chain, the division by WeightingDivisor is done per strategy, so results match
+// the contract exactly, including rounding.
+func ComputeOperatorWeight(shares map[common.Address]*big.Int, params []StrategyParams) *big.Int {
+	weight := new(big.Int)
+	for _, param := range params {
+		strategyShares, ok := shares[param.Strategy]
+		if !ok || strategyShares == nil || strategyShares.Sign() <= 0 || param.Multiplier == nil {
+			continue
+		}
+		strategyWeight := new(big.Int).Mul(strategyShares, param.Multiplier)
+		strategyWeight.Quo(strategyWeight, WeightingDivisor)
+		weight.Add(weight, strategyWeight)
+	}
+	return weight
+}
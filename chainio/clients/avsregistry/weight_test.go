@@ -0,0 +1,77 @@
+package avsregistry_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeOperatorWeight(t *testing.T) {
+	strategyA := common.HexToAddress("0xa")
+	strategyB := common.HexToAddress("0xb")
+	oneEther := big.NewInt(1e18)
+
+	tests := []struct {
+		name           string
+		shares         map[common.Address]*big.Int
+		params         []avsregistry.StrategyParams
+		expectedWeight *big.Int
+	}{
+		{
+			name:           "no strategies",
+			shares:         map[common.Address]*big.Int{strategyA: big.NewInt(100)},
+			params:         nil,
+			expectedWeight: big.NewInt(0),
+		},
+		{
+			name:   "single strategy with unit multiplier",
+			shares: map[common.Address]*big.Int{strategyA: big.NewInt(100)},
+			params: []avsregistry.StrategyParams{
+				{Strategy: strategyA, Multiplier: oneEther},
+			},
+			expectedWeight: big.NewInt(100),
+		},
+		{
+			name: "multiple strategies are summed",
+			shares: map[common.Address]*big.Int{
+				strategyA: big.NewInt(100),
+				strategyB: big.NewInt(10),
+			},
+			params: []avsregistry.StrategyParams{
+				{Strategy: strategyA, Multiplier: oneEther},
+				{Strategy: strategyB, Multiplier: new(big.Int).Mul(big.NewInt(3), oneEther)},
+			},
+			expectedWeight: big.NewInt(130),
+		},
+		{
+			name: "division is rounded down per strategy",
+			shares: map[common.Address]*big.Int{
+				strategyA: big.NewInt(3),
+				strategyB: big.NewInt(3),
+			},
+			params: []avsregistry.StrategyParams{
+				{Strategy: strategyA, Multiplier: big.NewInt(5e17)},
+				{Strategy: strategyB, Multiplier: big.NewInt(5e17)},
+			},
+			expectedWeight: big.NewInt(2),
+		},
+		{
+			name:   "missing shares count as zero",
+			shares: map[common.Address]*big.Int{strategyA: big.NewInt(100)},
+			params: []avsregistry.StrategyParams{
+				{Strategy: strategyB, Multiplier: oneEther},
+			},
+			expectedWeight: big.NewInt(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weight := avsregistry.ComputeOperatorWeight(tt.shares, tt.params)
+			require.Equal(t, 0, tt.expectedWeight.Cmp(weight), "expected %s, got %s", tt.expectedWeight, weight)
+		})
+	}
+}
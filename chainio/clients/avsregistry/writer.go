@@ -5,8 +5,10 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -22,7 +24,9 @@ import (
 	smbase "github.com/Layr-Labs/eigensdk-go/contracts/bindings/ServiceManagerBase"
 	stakeregistry "github.com/Layr-Labs/eigensdk-go/contracts/bindings/StakeRegistry"
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls/blssigner"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/Layr-Labs/eigensdk-go/utils"
 )
@@ -47,6 +51,10 @@ type ChainWriter struct {
 	logger                 logging.Logger
 	ethClient              eth.HttpBackend
 	txMgr                  txmgr.TxManager
+	calldataSuffix         []byte
+	// see WithReceiptDetails
+	receiptDetails  bool
+	revertErrorABIs []*abi.ABI
 }
 
 func NewChainWriter(
@@ -215,21 +223,10 @@ func (w *ChainWriter) RegisterOperatorInQuorumWithAVSRegistryCoordinator(
 		"socket",
 		socket,
 	)
-	// params to register bls pubkey with bls apk registry
-	g1HashedMsgToSign, err := w.registryCoordinator.PubkeyRegistrationMessageHash(&bind.CallOpts{}, operatorAddr)
+	pubkeyRegParams, err := w.makePubkeyRegistrationParams(ctx, operatorAddr, blssigner.NewLocalSigner(blsKeyPair))
 	if err != nil {
 		return nil, err
 	}
-	signedMsg := chainioutils.ConvertToBN254G1Point(
-		blsKeyPair.SignHashedToCurveMessage(chainioutils.ConvertBn254GethToGnark(g1HashedMsgToSign)).G1Point,
-	)
-	G1pubkeyBN254 := chainioutils.ConvertToBN254G1Point(blsKeyPair.GetPubKeyG1())
-	G2pubkeyBN254 := chainioutils.ConvertToBN254G2Point(blsKeyPair.GetPubKeyG2())
-	pubkeyRegParams := regcoord.IBLSApkRegistryPubkeyRegistrationParams{
-		PubkeyRegistrationSignature: signedMsg,
-		PubkeyG1:                    G1pubkeyBN254,
-		PubkeyG2:                    G2pubkeyBN254,
-	}
 
 	// params to register operator in delegation manager's operator-avs mapping
 	msgToSign, err := w.elReader.CalculateOperatorAVSRegistrationDigestHash(
@@ -256,7 +253,7 @@ func (w *ChainWriter) RegisterOperatorInQuorumWithAVSRegistryCoordinator(
 		Expiry:    operatorToAvsRegistrationSigExpiry,
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -272,9 +269,9 @@ func (w *ChainWriter) RegisterOperatorInQuorumWithAVSRegistryCoordinator(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info(
 		"successfully registered operator with AVS registry coordinator",
@@ -294,18 +291,38 @@ func (w *ChainWriter) RegisterOperatorInQuorumWithAVSRegistryCoordinator(
 // generates a random salt and expiry for the signature.
 func (w *ChainWriter) RegisterOperator(
 	ctx context.Context,
-	// we need to pass the private key explicitly and can't use the signer because registering requires signing a
-	// message which isn't a transaction and the signer can only signs transactions. See operatorSignature in
-	// https://github.com/Layr-Labs/eigenlayer-middleware/blob/m2-mainnet/docs/RegistryCoordinator.md#registeroperator
-	// TODO(madhur): check to see if we can make the signer and txmgr more flexible so we can use them (and remote
-	// signers) to sign non txs
 	operatorEcdsaPrivateKey *ecdsa.PrivateKey,
 	blsKeyPair *bls.KeyPair,
 	quorumNumbers types.QuorumNums,
 	socket string,
 	waitForReceipt bool,
 ) (*gethtypes.Receipt, error) {
-	operatorAddr := crypto.PubkeyToAddress(operatorEcdsaPrivateKey.PublicKey)
+	return w.RegisterOperatorWithSigner(
+		ctx,
+		crypto.PubkeyToAddress(operatorEcdsaPrivateKey.PublicKey),
+		signerv2.PrivateKeyDigestSignerFn(operatorEcdsaPrivateKey),
+		blssigner.NewLocalSigner(blsKeyPair),
+		quorumNumbers,
+		socket,
+		waitForReceipt,
+	)
+}
+
+// RegisterOperatorWithSigner registers the operator with the AVS's registry coordinator, using operatorSigner
+// to produce the operator's AVS registration signature. Unlike RegisterOperator, this does not require the
+// operator's raw ECDSA private key, so it can be used with remote signers or hardware keys. Likewise, blsSigner signs
+// the BLS pubkey registration message, e.g. with a remote BLS signer.
+// See operatorSignature in
+// https://github.com/Layr-Labs/eigenlayer-middleware/blob/m2-mainnet/docs/RegistryCoordinator.md#registeroperator
+func (w *ChainWriter) RegisterOperatorWithSigner(
+	ctx context.Context,
+	operatorAddr gethcommon.Address,
+	operatorSigner signerv2.DigestSignerFn,
+	blsSigner blssigner.Signer,
+	quorumNumbers types.QuorumNums,
+	socket string,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
 	w.logger.Info(
 		"registering operator with the AVS's registry coordinator",
 		"avs-service-manager",
@@ -317,21 +334,10 @@ func (w *ChainWriter) RegisterOperator(
 		"socket",
 		socket,
 	)
-	// params to register bls pubkey with bls apk registry
-	g1HashedMsgToSign, err := w.registryCoordinator.PubkeyRegistrationMessageHash(&bind.CallOpts{}, operatorAddr)
+	pubkeyRegParams, err := w.makePubkeyRegistrationParams(ctx, operatorAddr, blsSigner)
 	if err != nil {
 		return nil, err
 	}
-	signedMsg := chainioutils.ConvertToBN254G1Point(
-		blsKeyPair.SignHashedToCurveMessage(chainioutils.ConvertBn254GethToGnark(g1HashedMsgToSign)).G1Point,
-	)
-	G1pubkeyBN254 := chainioutils.ConvertToBN254G1Point(blsKeyPair.GetPubKeyG1())
-	G2pubkeyBN254 := chainioutils.ConvertToBN254G2Point(blsKeyPair.GetPubKeyG2())
-	pubkeyRegParams := regcoord.IBLSApkRegistryPubkeyRegistrationParams{
-		PubkeyRegistrationSignature: signedMsg,
-		PubkeyG1:                    G1pubkeyBN254,
-		PubkeyG2:                    G2pubkeyBN254,
-	}
 
 	// generate a random salt and 1 hour expiry for the signature
 	var operatorToAvsRegistrationSigSalt [32]byte
@@ -364,21 +370,27 @@ func (w *ChainWriter) RegisterOperator(
 	if err != nil {
 		return nil, err
 	}
-	operatorSignature, err := crypto.Sign(msgToSign[:], operatorEcdsaPrivateKey)
+	operatorSignature, err := operatorSigner(ctx, operatorAddr, msgToSign)
 	if err != nil {
-		return nil, err
+		return nil, utils.WrapError("Failed to sign operator AVS registration digest", err)
+	}
+	if len(operatorSignature) != 65 {
+		return nil, fmt.Errorf("invalid operator signature length: expected 65, got %d", len(operatorSignature))
 	}
 	// the crypto library is low level and deals with 0/1 v values, whereas ethereum expects 27/28, so we add 27
 	// see https://github.com/ethereum/go-ethereum/issues/28757#issuecomment-1874525854
 	// and https://twitter.com/pcaversaccio/status/1671488928262529031
-	operatorSignature[64] += 27
+	// remote signers may already return 27/28, in which case the signature is left untouched
+	if operatorSignature[64] < 27 {
+		operatorSignature[64] += 27
+	}
 	operatorSignatureWithSaltAndExpiry := regcoord.ISignatureUtilsSignatureWithSaltAndExpiry{
 		Signature: operatorSignature,
 		Salt:      operatorToAvsRegistrationSigSalt,
 		Expiry:    operatorToAvsRegistrationSigExpiry,
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -394,9 +406,9 @@ func (w *ChainWriter) RegisterOperator(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info(
 		"successfully registered operator with AVS registry coordinator",
@@ -412,6 +424,43 @@ func (w *ChainWriter) RegisterOperator(
 	return receipt, nil
 }
 
+// makePubkeyRegistrationParams returns the params to register the bls pubkey of the operator with the bls apk
+// registry, signed by blsSigner and checked like the contract does so that a bad key pair fails before sending the
+// registration
+func (w *ChainWriter) makePubkeyRegistrationParams(
+	ctx context.Context,
+	operatorAddr gethcommon.Address,
+	blsSigner blssigner.Signer,
+) (regcoord.IBLSApkRegistryPubkeyRegistrationParams, error) {
+	g1HashedMsgToSign, err := w.registryCoordinator.PubkeyRegistrationMessageHash(&bind.CallOpts{}, operatorAddr)
+	if err != nil {
+		return regcoord.IBLSApkRegistryPubkeyRegistrationParams{}, err
+	}
+	registrationMessageHashG1, err := chainioutils.G1PointFromBinding(g1HashedMsgToSign)
+	if err != nil {
+		return regcoord.IBLSApkRegistryPubkeyRegistrationParams{}, utils.WrapError(
+			"invalid pubkey registration message hash",
+			err,
+		)
+	}
+	signature, err := blsSigner.SignG1(ctx, registrationMessageHashG1)
+	if err != nil {
+		return regcoord.IBLSApkRegistryPubkeyRegistrationParams{}, utils.WrapError(
+			"failed to sign pubkey registration message",
+			err,
+		)
+	}
+	params := &bls.PubkeyRegistrationParams{
+		PubkeyRegistrationSignature: signature,
+		PubkeyG1:                    blsSigner.GetPublicKeyG1(),
+		PubkeyG2:                    blsSigner.GetPublicKeyG2(),
+	}
+	if err := bls.VerifyPubkeyRegistrationParams(params, registrationMessageHashG1.G1Affine); err != nil {
+		return regcoord.IBLSApkRegistryPubkeyRegistrationParams{}, err
+	}
+	return chainioutils.PubkeyRegistrationParamsToBinding[regcoord.IBLSApkRegistryPubkeyRegistrationParams](params)
+}
+
 // UpdateStakesOfEntireOperatorSetForQuorums is used by avs teams running https://github.com/Layr-Labs/avs-sync
 // to updates the stake of their entire operator set.
 // Because of high gas costs of this operation, it typically needs to be called for every quorum, or perhaps for a
@@ -424,7 +473,7 @@ func (w *ChainWriter) UpdateStakesOfEntireOperatorSetForQuorums(
 	waitForReceipt bool,
 ) (*gethtypes.Receipt, error) {
 	w.logger.Info("updating stakes for entire operator set", "quorumNumbers", quorumNumbers)
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -436,9 +485,9 @@ func (w *ChainWriter) UpdateStakesOfEntireOperatorSetForQuorums(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info(
 		"successfully updated stakes for entire operator set",
@@ -457,7 +506,7 @@ func (w *ChainWriter) UpdateStakesOfOperatorSubsetForAllQuorums(
 	waitForReceipt bool,
 ) (*gethtypes.Receipt, error) {
 	w.logger.Info("updating stakes of operator subset for all quorums", "operators", operators)
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -465,9 +514,9 @@ func (w *ChainWriter) UpdateStakesOfOperatorSubsetForAllQuorums(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info(
 		"successfully updated stakes of operator subset for all quorums",
@@ -486,7 +535,7 @@ func (w *ChainWriter) DeregisterOperator(
 	waitForReceipt bool,
 ) (*gethtypes.Receipt, error) {
 	w.logger.Info("deregistering operator with the AVS's registry coordinator")
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -494,9 +543,9 @@ func (w *ChainWriter) DeregisterOperator(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info(
 		"successfully deregistered operator with the AVS's registry coordinator",
@@ -511,7 +560,7 @@ func (w *ChainWriter) UpdateSocket(
 	socket types.Socket,
 	waitForReceipt bool,
 ) (*gethtypes.Receipt, error) {
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -519,9 +568,292 @@ func (w *ChainWriter) UpdateSocket(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
 		return nil, errors.New("failed to send UpdateSocket tx with err: " + err.Error())
 	}
 	return receipt, nil
 }
+
+// CreateTotalDelegatedStakeQuorum creates a new quorum in the RegistryCoordinator which weighs operators by their
+// total delegated stake in the given strategies. Only the RegistryCoordinator owner can create quorums.
+// The number of the newly created quorum is parsed from the QuorumCreated event of the receipt, so it is only
+// returned when waitForReceipt is true.
+// The RegistryCoordinator the bindings are generated from predates slashing, so slashable stake quorums can't be
+// created with the ChainWriter.
+func (w *ChainWriter) CreateTotalDelegatedStakeQuorum(
+	ctx context.Context,
+	operatorSetParams regcoord.IRegistryCoordinatorOperatorSetParam,
+	minimumStake *big.Int,
+	strategyParams []regcoord.IStakeRegistryStrategyParams,
+	waitForReceipt bool,
+) (types.QuorumNum, *gethtypes.Receipt, error) {
+	if err := validateStrategyParams(strategyParams); err != nil {
+		return 0, nil, err
+	}
+	if w.registryCoordinator == nil {
+		return 0, nil, errors.New("RegistryCoordinator contract not provided")
+	}
+
+	w.logger.Info(
+		"creating total delegated stake quorum",
+		"maxOperatorCount",
+		operatorSetParams.MaxOperatorCount,
+		"minimumStake",
+		minimumStake,
+		"numStrategies",
+		len(strategyParams),
+	)
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	tx, err := w.registryCoordinator.CreateQuorum(noSendTxOpts, operatorSetParams, minimumStake, strategyParams)
+	if err != nil {
+		return 0, nil, err
+	}
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
+	if err != nil {
+		return 0, nil, utils.WrapError("failed to send tx", err)
+	}
+	if !waitForReceipt {
+		return 0, receipt, nil
+	}
+
+	quorumNumber, err := w.parseCreatedQuorumNumber(receipt)
+	if err != nil {
+		return 0, receipt, err
+	}
+	w.logger.Info(
+		"successfully created total delegated stake quorum",
+		"txHash",
+		receipt.TxHash.String(),
+		"quorumNumber",
+		quorumNumber,
+	)
+	return quorumNumber, receipt, nil
+}
+
+// parseCreatedQuorumNumber returns the quorum number of the QuorumCreated event emitted by the StakeRegistry
+func (w *ChainWriter) parseCreatedQuorumNumber(receipt *gethtypes.Receipt) (types.QuorumNum, error) {
+	if w.stakeRegistry == nil {
+		return 0, errors.New("StakeRegistry contract not provided")
+	}
+	for _, log := range receipt.Logs {
+		event, err := w.stakeRegistry.ParseQuorumCreated(*log)
+		if err == nil {
+			return types.QuorumNum(event.QuorumNumber), nil
+		}
+	}
+	return 0, fmt.Errorf("QuorumCreated event not found in receipt of tx %s", receipt.TxHash.String())
+}
+
+// validateStrategyParams checks the strategy params the same way the StakeRegistry does, so that we can fail early
+// instead of sending a transaction that will revert
+func validateStrategyParams(strategyParams []regcoord.IStakeRegistryStrategyParams) error {
+	if len(strategyParams) == 0 {
+		return errors.New("no strategy params provided")
+	}
+	seenStrategies := make(map[gethcommon.Address]bool, len(strategyParams))
+	for _, params := range strategyParams {
+		if params.Multiplier == nil || params.Multiplier.Sign() == 0 {
+			return fmt.Errorf("strategy %s has a zero multiplier", params.Strategy.Hex())
+		}
+		if seenStrategies[params.Strategy] {
+			return fmt.Errorf("strategy %s is provided more than once", params.Strategy.Hex())
+		}
+		seenStrategies[params.Strategy] = true
+	}
+	return nil
+}
+
+// ErrSenderNotRegistryCoordinatorOwner is returned by the admin methods when the tx sender is not the owner of the
+// RegistryCoordinator. The check is done before sending the tx, so that we don't pay gas for a tx that will revert.
+var ErrSenderNotRegistryCoordinatorOwner = errors.New("tx sender is not the RegistryCoordinator owner")
+
+// Admin methods
+// The methods below can only be called by the owner of the RegistryCoordinator.
+
+// SetOperatorSetParams updates the max operator count and churn parameters of the given quorum
+func (w *ChainWriter) SetOperatorSetParams(
+	ctx context.Context,
+	quorumNumber types.QuorumNum,
+	operatorSetParams regcoord.IRegistryCoordinatorOperatorSetParam,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	w.logger.Info(
+		"setting operator set params",
+		"quorumNumber",
+		quorumNumber,
+		"maxOperatorCount",
+		operatorSetParams.MaxOperatorCount,
+		"kickBIPsOfOperatorStake",
+		operatorSetParams.KickBIPsOfOperatorStake,
+		"kickBIPsOfTotalStake",
+		operatorSetParams.KickBIPsOfTotalStake,
+	)
+	noSendTxOpts, err := w.getOwnerNoSendTxOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := w.registryCoordinator.SetOperatorSetParams(
+		noSendTxOpts,
+		quorumNumber.UnderlyingType(),
+		operatorSetParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return w.sendAdminTx(ctx, tx, waitForReceipt, "successfully set operator set params")
+}
+
+// SetChurnApprover sets the address whose signature is required to register operators with churn
+func (w *ChainWriter) SetChurnApprover(
+	ctx context.Context,
+	churnApprover gethcommon.Address,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	w.logger.Info("setting churn approver", "churnApprover", churnApprover)
+	noSendTxOpts, err := w.getOwnerNoSendTxOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := w.registryCoordinator.SetChurnApprover(noSendTxOpts, churnApprover)
+	if err != nil {
+		return nil, err
+	}
+	return w.sendAdminTx(ctx, tx, waitForReceipt, "successfully set churn approver")
+}
+
+// SetEjector sets the address that is allowed to eject operators from the AVS
+func (w *ChainWriter) SetEjector(
+	ctx context.Context,
+	ejector gethcommon.Address,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	w.logger.Info("setting ejector", "ejector", ejector)
+	noSendTxOpts, err := w.getOwnerNoSendTxOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := w.registryCoordinator.SetEjector(noSendTxOpts, ejector)
+	if err != nil {
+		return nil, err
+	}
+	return w.sendAdminTx(ctx, tx, waitForReceipt, "successfully set ejector")
+}
+
+// SetEjectionCooldown sets the number of seconds an ejected operator has to wait before being able to register again
+func (w *ChainWriter) SetEjectionCooldown(
+	ctx context.Context,
+	ejectionCooldownSeconds *big.Int,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	w.logger.Info("setting ejection cooldown", "ejectionCooldownSeconds", ejectionCooldownSeconds)
+	noSendTxOpts, err := w.getOwnerNoSendTxOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := w.registryCoordinator.SetEjectionCooldown(noSendTxOpts, ejectionCooldownSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return w.sendAdminTx(ctx, tx, waitForReceipt, "successfully set ejection cooldown")
+}
+
+// getOwnerNoSendTxOpts returns the noSend TransactOpts of the txMgr, after checking that its sender is the owner of
+// the RegistryCoordinator
+func (w *ChainWriter) getOwnerNoSendTxOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	if w.registryCoordinator == nil {
+		return nil, errors.New("RegistryCoordinator contract not provided")
+	}
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := w.registryCoordinator.Owner(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, utils.WrapError("Failed to get RegistryCoordinator owner", err)
+	}
+	if owner != noSendTxOpts.From {
+		return nil, fmt.Errorf(
+			"%w: sender %s, owner %s",
+			ErrSenderNotRegistryCoordinatorOwner,
+			noSendTxOpts.From.Hex(),
+			owner.Hex(),
+		)
+	}
+	return noSendTxOpts, nil
+}
+
+func (w *ChainWriter) sendAdminTx(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+	successMsg string,
+) (*gethtypes.Receipt, error) {
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
+	if err != nil {
+		return nil, utils.WrapError("failed to send tx", err)
+	}
+	w.logger.Info(successMsg, "txHash", receipt.TxHash.String())
+	return receipt, nil
+}
+
+// WithCalldataSuffix makes the writer append suffix to the calldata of every transaction it sends, e.g. to tag the
+// transactions so that indexers can attribute them. The gas limit is estimated with the suffix included.
+func (w *ChainWriter) WithCalldataSuffix(suffix []byte) *ChainWriter {
+	w.calldataSuffix = suffix
+	return w
+}
+
+// WithReceiptDetails makes the writer enrich the receipts it waits for (see txmgr.EnrichReceipt): the transactions
+// which were mined but reverted are replayed to extract their revert reason, and returned as a txmgr.ErrTxReverted
+// holding the receipt with its details. The custom errors declared by errorABIs, e.g. the ABIs of the called
+// contracts, are decoded with their name and arguments.
+func (w *ChainWriter) WithReceiptDetails(errorABIs ...*abi.ABI) *ChainWriter {
+	w.receiptDetails = true
+	w.revertErrorABIs = errorABIs
+	return w
+}
+
+func (w *ChainWriter) sendTx(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	tx, err := chainioutils.AppendCalldataSuffix(tx, w.calldataSuffix)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	if err != nil || !waitForReceipt || !w.receiptDetails {
+		return receipt, err
+	}
+	return w.enrichReceipt(ctx, tx, receipt)
+}
+
+// enrichReceipt logs the effective gas cost of the receipt of tx, and returns an ErrTxReverted with the revert reason
+// if tx failed. The receipt is returned as is if it can't be enriched.
+func (w *ChainWriter) enrichReceipt(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	receipt *gethtypes.Receipt,
+) (*gethtypes.Receipt, error) {
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
+	if err != nil {
+		w.logger.Warn("failed to get sender to enrich receipt", "txHash", receipt.TxHash.Hex(), "err", err)
+		return receipt, nil
+	}
+	details, err := txmgr.EnrichReceipt(ctx, w.ethClient, noSendTxOpts.From, tx, receipt, w.revertErrorABIs...)
+	if err != nil {
+		w.logger.Warn("failed to enrich receipt", "txHash", receipt.TxHash.Hex(), "err", err)
+		return receipt, nil
+	}
+	w.logger.Debug("transaction confirmed", "txHash", receipt.TxHash.Hex(),
+		"effectiveGasCost", details.EffectiveGasCost, "status", receipt.Status)
+	if receipt.Status == gethtypes.ReceiptStatusFailed {
+		return nil, &txmgr.ErrTxReverted{Receipt: details}
+	}
+	return receipt, nil
+}
@@ -2,15 +2,26 @@ package avsregistry_test
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/mocks"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
 	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	blssigcheck "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IBLSSignatureChecker"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls/blssigner"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/Layr-Labs/eigensdk-go/testutils/testclients"
 	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestWriterMethods(t *testing.T) {
@@ -59,6 +70,16 @@ func TestWriterMethods(t *testing.T) {
 		)
 		require.NoError(t, err)
 		require.NotNil(t, receipt)
+
+		chainReader := clients.ReadClients.AvsRegistryChainReader
+		updateBlockNumber, err := chainReader.GetQuorumUpdateBlockNumber(context.Background(), quorumNumbers[0])
+		require.NoError(t, err)
+		require.Equal(t, receipt.BlockNumber.Uint64(), updateBlockNumber)
+
+		isStale, age, err := chainReader.IsQuorumStakeStale(context.Background(), quorumNumbers[0], 10)
+		require.NoError(t, err)
+		require.False(t, isStale)
+		require.Less(t, age, uint64(10))
 	})
 
 	t.Run("deregister operator", func(t *testing.T) {
@@ -71,4 +92,330 @@ func TestWriterMethods(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, receipt)
 	})
+
+	t.Run("register operator with external signer", func(t *testing.T) {
+		// mimics a web3signer-style remote signer, which never exposes the key and returns v as 27/28
+		remoteSigner := func(ctx context.Context, address gethcommon.Address, digest [32]byte) ([]byte, error) {
+			require.Equal(t, addr, address)
+			signature, err := crypto.Sign(digest[:], ecdsaPrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			signature[64] += 27
+			return signature, nil
+		}
+
+		receipt, err := chainWriter.RegisterOperatorWithSigner(
+			context.Background(),
+			addr,
+			remoteSigner,
+			blssigner.NewLocalSigner(keypair),
+			quorumNumbers,
+			"",
+			true,
+		)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	})
+
+	t.Run("query existing registered operators resolves re-registration", func(t *testing.T) {
+		operators, err := clients.ReadClients.AvsRegistryChainReader.QueryExistingRegisteredOperators(
+			context.Background(),
+			0,
+			0,
+			avsregistry.QueryOpts{BlockRange: 100},
+		)
+		require.NoError(t, err)
+
+		operator, ok := operators[types.OperatorIdFromKeyPair(keypair)]
+		require.True(t, ok)
+		require.Equal(t, addr, operator.OperatorAddr)
+		require.True(t, operator.IsRegistered)
+		require.True(t, operator.Pubkeys.G1Pubkey.Equal(keypair.GetPubKeyG1().G1Affine))
+		require.NotZero(t, operator.LastUpdateBlock)
+		require.NotZero(t, operator.LastRegistrationUpdateBlock)
+	})
+
+	t.Run("query operator info", func(t *testing.T) {
+		chainReader := clients.ReadClients.AvsRegistryChainReader
+		operator, found, err := chainReader.QueryOperatorInfo(
+			context.Background(),
+			addr,
+			0,
+			0,
+			avsregistry.QueryOpts{BlockRange: 100},
+		)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, addr, operator.OperatorAddr)
+		require.True(t, operator.IsRegistered)
+		require.True(t, operator.Pubkeys.G1Pubkey.Equal(keypair.GetPubKeyG1().G1Affine))
+		require.True(t, operator.Pubkeys.G2Pubkey.Equal(keypair.GetPubKeyG2().G2Affine))
+		require.NotZero(t, operator.LastRegistrationUpdateBlock)
+
+		_, found, err = chainReader.QueryOperatorInfo(
+			context.Background(),
+			gethcommon.HexToAddress("0x1"),
+			0,
+			0,
+			avsregistry.QueryOpts{BlockRange: 100},
+		)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("get operator ids in quorum at block", func(t *testing.T) {
+		chainReader := clients.ReadClients.AvsRegistryChainReader
+		curBlockNum, err := clients.EthHttpClient.BlockNumber(context.Background())
+		require.NoError(t, err)
+		blockNumber := uint32(curBlockNum)
+
+		operatorIds, err := chainReader.GetOperatorIdsInQuorumAtBlock(
+			context.Background(),
+			quorumNumbers[0],
+			blockNumber,
+		)
+		require.NoError(t, err)
+		require.Contains(t, operatorIds, [32]byte(types.OperatorIdFromKeyPair(keypair)))
+
+		// the ordering must match the one used by the OperatorStateRetriever
+		operatorsState, err := chainReader.GetOperatorsStakeInQuorumsAtBlock(
+			&bind.CallOpts{},
+			quorumNumbers,
+			blockNumber,
+		)
+		require.NoError(t, err)
+		require.Len(t, operatorsState[0], len(operatorIds))
+		nonSignerIds := make([]types.OperatorId, len(operatorIds))
+		for i, operator := range operatorsState[0] {
+			require.Equal(t, operator.OperatorId, operatorIds[i])
+			nonSignerIds[i] = operatorIds[i]
+		}
+
+		// every returned operator id is registered at the block, so the check signatures indices can be fetched
+		_, err = chainReader.GetCheckSignaturesIndices(&bind.CallOpts{}, blockNumber, quorumNumbers, nonSignerIds)
+		require.NoError(t, err)
+	})
+
+	t.Run("create total delegated stake quorum", func(t *testing.T) {
+		quorumCount, err := clients.ReadClients.AvsRegistryChainReader.GetQuorumCount(&bind.CallOpts{})
+		require.NoError(t, err)
+
+		quorumNumber, receipt, err := chainWriter.CreateTotalDelegatedStakeQuorum(
+			context.Background(),
+			regcoord.IRegistryCoordinatorOperatorSetParam{
+				MaxOperatorCount:        10,
+				KickBIPsOfOperatorStake: 10000,
+				KickBIPsOfTotalStake:    100,
+			},
+			big.NewInt(0),
+			[]regcoord.IStakeRegistryStrategyParams{
+				{Strategy: gethcommon.Address{0x1}, Multiplier: big.NewInt(1e18)},
+			},
+			true,
+		)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+		require.Equal(t, types.QuorumNum(quorumCount), quorumNumber)
+	})
+
+	t.Run("set operator set params", func(t *testing.T) {
+		receipt, err := chainWriter.SetOperatorSetParams(
+			context.Background(),
+			quorumNumbers[0],
+			regcoord.IRegistryCoordinatorOperatorSetParam{
+				MaxOperatorCount:        20,
+				KickBIPsOfOperatorStake: 10000,
+				KickBIPsOfTotalStake:    100,
+			},
+			true,
+		)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	})
+
+	t.Run("set churn approver", func(t *testing.T) {
+		receipt, err := chainWriter.SetChurnApprover(context.Background(), addr, true)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	})
+
+	t.Run("set ejector", func(t *testing.T) {
+		receipt, err := chainWriter.SetEjector(context.Background(), addr, true)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	})
+
+	t.Run("set ejection cooldown", func(t *testing.T) {
+		receipt, err := chainWriter.SetEjectionCooldown(context.Background(), big.NewInt(60), true)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	})
+
+	t.Run("build non signer stakes and signature", func(t *testing.T) {
+		// the operator registered above is the only member of the quorum and the only signer
+		curBlockNum, err := clients.EthHttpClient.BlockNumber(context.Background())
+		require.NoError(t, err)
+		referenceBlock := uint32(curBlockNum - 1)
+		msgHash := crypto.Keccak256Hash([]byte("task response"))
+		signature := keypair.SignMessage(msgHash)
+
+		params, err := clients.ReadClients.AvsRegistryChainReader.BuildNonSignerStakesAndSignature(
+			context.Background(),
+			quorumNumbers,
+			referenceBlock,
+			nil,
+			[]*bls.G1Point{keypair.GetPubKeyG1()},
+			signature,
+			keypair.GetPubKeyG2(),
+		)
+		require.NoError(t, err)
+
+		sigChecker, err := blssigcheck.NewContractIBLSSignatureChecker(
+			clients.AvsRegistryContractBindings.ServiceManagerAddr,
+			clients.EthHttpClient,
+		)
+		require.NoError(t, err)
+		_, _, err = sigChecker.CheckSignatures(
+			&bind.CallOpts{},
+			msgHash,
+			quorumNumbers.UnderlyingType(),
+			referenceBlock,
+			params,
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestAdminMethodsRequireOwner(t *testing.T) {
+	clients, anvilHttpEndpoint := testclients.BuildTestClients(t)
+	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	logger := testutils.GetTestLogger()
+
+	// the wallet only expects its sender address to be queried, so the test fails if any tx gets broadcasted
+	mockWallet := mocks.NewMockWallet(gomock.NewController(t))
+	nonOwnerAddr := gethcommon.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	mockWallet.EXPECT().SenderAddress(gomock.Any()).Return(nonOwnerAddr, nil).AnyTimes()
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(mockWallet, clients.EthHttpClient, logger)
+	chainWriter, err := avsregistry.NewWriterFromConfig(
+		avsregistry.Config{
+			RegistryCoordinatorAddress:    contractAddrs.RegistryCoordinator,
+			OperatorStateRetrieverAddress: contractAddrs.OperatorStateRetriever,
+		},
+		clients.EthHttpClient,
+		txMgr,
+		logger,
+	)
+	require.NoError(t, err)
+
+	_, err = chainWriter.SetOperatorSetParams(
+		context.Background(),
+		0,
+		regcoord.IRegistryCoordinatorOperatorSetParam{MaxOperatorCount: 20},
+		true,
+	)
+	require.ErrorIs(t, err, avsregistry.ErrSenderNotRegistryCoordinatorOwner)
+
+	_, err = chainWriter.SetChurnApprover(context.Background(), nonOwnerAddr, true)
+	require.ErrorIs(t, err, avsregistry.ErrSenderNotRegistryCoordinatorOwner)
+
+	_, err = chainWriter.SetEjector(context.Background(), nonOwnerAddr, true)
+	require.ErrorIs(t, err, avsregistry.ErrSenderNotRegistryCoordinatorOwner)
+
+	_, err = chainWriter.SetEjectionCooldown(context.Background(), big.NewInt(60), true)
+	require.ErrorIs(t, err, avsregistry.ErrSenderNotRegistryCoordinatorOwner)
+}
+
+func TestCreateQuorumStrategyParamsValidation(t *testing.T) {
+	chainWriter := avsregistry.NewChainWriter(
+		gethcommon.Address{},
+		nil, nil, nil, nil, nil,
+		testutils.GetTestLogger(),
+		nil, nil,
+	)
+	operatorSetParams := regcoord.IRegistryCoordinatorOperatorSetParam{MaxOperatorCount: 10}
+	strategy := gethcommon.Address{0x1}
+
+	tests := []struct {
+		name           string
+		strategyParams []regcoord.IStakeRegistryStrategyParams
+		wantErr        string
+	}{
+		{
+			name:           "no strategies",
+			strategyParams: nil,
+			wantErr:        "no strategy params provided",
+		},
+		{
+			name: "zero multiplier",
+			strategyParams: []regcoord.IStakeRegistryStrategyParams{
+				{Strategy: strategy, Multiplier: big.NewInt(0)},
+			},
+			wantErr: "zero multiplier",
+		},
+		{
+			name: "duplicate strategy",
+			strategyParams: []regcoord.IStakeRegistryStrategyParams{
+				{Strategy: strategy, Multiplier: big.NewInt(1)},
+				{Strategy: strategy, Multiplier: big.NewInt(2)},
+			},
+			wantErr: "provided more than once",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := chainWriter.CreateTotalDelegatedStakeQuorum(
+				context.Background(),
+				operatorSetParams,
+				big.NewInt(0),
+				tt.strategyParams,
+				true,
+			)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestWriterWithCalldataSuffix(t *testing.T) {
+	clients, anvilHttpEndpoint := testclients.BuildTestClients(t)
+	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	logger := testutils.GetTestLogger()
+	ownerAddr := gethcommon.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	suffix := []byte{0xca, 0xfe, 0xba, 0xbe}
+
+	var sentTx *gethtypes.Transaction
+	mockWallet := mocks.NewMockWallet(gomock.NewController(t))
+	mockWallet.EXPECT().SenderAddress(gomock.Any()).Return(ownerAddr, nil).AnyTimes()
+	mockWallet.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, tx *gethtypes.Transaction) (string, error) {
+			sentTx = tx
+			return tx.Hash().Hex(), nil
+		},
+	)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(mockWallet, clients.EthHttpClient, logger)
+	chainWriter, err := avsregistry.NewWriterFromConfig(
+		avsregistry.Config{
+			RegistryCoordinatorAddress:    contractAddrs.RegistryCoordinator,
+			OperatorStateRetrieverAddress: contractAddrs.OperatorStateRetriever,
+		},
+		clients.EthHttpClient,
+		txMgr,
+		logger,
+	)
+	require.NoError(t, err)
+	chainWriter = chainWriter.WithCalldataSuffix(suffix)
+
+	ejector := gethcommon.HexToAddress("0x1234")
+	_, err = chainWriter.SetEjector(context.Background(), ejector, false)
+	require.NoError(t, err)
+	require.NotNil(t, sentTx)
+
+	regCoordAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	require.NoError(t, err)
+	data := sentTx.Data()
+	require.Equal(t, suffix, data[len(data)-len(suffix):])
+	require.Equal(t, regCoordAbi.Methods["setEjector"].ID, data[:4])
+	args, err := regCoordAbi.Methods["setEjector"].Inputs.Unpack(data[4 : len(data)-len(suffix)])
+	require.NoError(t, err)
+	require.Equal(t, ejector, args[0])
 }
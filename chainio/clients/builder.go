@@ -5,8 +5,6 @@ import (
 	"crypto/ecdsa"
 	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
-
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
@@ -27,6 +25,11 @@ type BuildAllConfig struct {
 	OperatorStateRetrieverAddr string
 	AvsName                    string
 	PromMetricsIpPortAddress   string
+	// EthClientOptions configure the connection to both the http and ws endpoints (e.g. auth headers)
+	EthClientOptions []eth.ClientOption
+	// PrivateRelayUrl is the json-rpc endpoint of a private relay (e.g. Flashbots Protect) which the transactions are
+	// sent to instead of the public mempool, see txmgr.PrivateRelayBroadcaster. Optional.
+	PrivateRelayUrl string
 }
 
 // ReadClients is a struct that holds only the read clients for interacting with the AVS and EL contracts.
@@ -63,12 +66,17 @@ func BuildReadClients(
 	eigenMetrics := metrics.NewEigenMetrics(config.AvsName, config.PromMetricsIpPortAddress, promReg, logger)
 
 	// creating two types of Eth clients: HTTP and WS
-	ethHttpClient, err := ethclient.Dial(config.EthHttpUrl)
+	ethHttpClient, err := eth.NewClientWithOptions(config.EthHttpUrl, config.EthClientOptions...)
 	if err != nil {
 		return nil, utils.WrapError("Failed to create Eth Http client", err)
 	}
 
-	ethWsClient, err := ethclient.Dial(config.EthWsUrl)
+	ethWsClient, err := eth.NewWsClientWithReconnect(
+		config.EthWsUrl,
+		logger,
+		eth.WsReconnectOpts{},
+		config.EthClientOptions...,
+	)
 	if err != nil {
 		return nil, utils.WrapError("Failed to create Eth WS client", err)
 	}
@@ -134,12 +142,17 @@ func BuildAll(
 	eigenMetrics := metrics.NewEigenMetrics(config.AvsName, config.PromMetricsIpPortAddress, promReg, logger)
 
 	// creating two types of Eth clients: HTTP and WS
-	ethHttpClient, err := ethclient.Dial(config.EthHttpUrl)
+	ethHttpClient, err := eth.NewClientWithOptions(config.EthHttpUrl, config.EthClientOptions...)
 	if err != nil {
 		return nil, utils.WrapError("Failed to create Eth Http client", err)
 	}
 
-	ethWsClient, err := ethclient.Dial(config.EthWsUrl)
+	ethWsClient, err := eth.NewWsClientWithReconnect(
+		config.EthWsUrl,
+		logger,
+		eth.WsReconnectOpts{},
+		config.EthClientOptions...,
+	)
 	if err != nil {
 		return nil, utils.WrapError("Failed to create Eth WS client", err)
 	}
@@ -150,16 +163,30 @@ func BuildAll(
 	if err != nil {
 		logger.Fatal("Cannot get chain id", "err", err)
 	}
-	signerV2, addr, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaPrivateKey}, chainid)
+	signerV2, signerAddr, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaPrivateKey}, chainid)
 	if err != nil {
 		return nil, utils.WrapError("Failed to create the signer from the given config", err)
 	}
 
-	pkWallet, err := wallet.NewPrivateKeyWallet(ethHttpClient, signerV2, addr, logger)
+	var walletBackend wallet.EthBackend = ethHttpClient
+	if config.PrivateRelayUrl != "" {
+		walletBackend, err = txmgr.NewPrivateRelayBroadcaster(config.PrivateRelayUrl, ethHttpClient, logger)
+		if err != nil {
+			return nil, utils.WrapError("Failed to create private relay broadcaster", err)
+		}
+	}
+	pkWallet, err := wallet.NewPrivateKeyWallet(walletBackend, signerV2, signerAddr, logger)
 	if err != nil {
 		return nil, utils.WrapError("Failed to create transaction sender", err)
 	}
-	txMgr := txmgr.NewSimpleTxManager(pkWallet, ethHttpClient, logger, addr)
+	sender, err := pkWallet.SenderAddress(rpcCtx)
+	if err != nil {
+		return nil, utils.WrapError("Failed to get the sender address of the wallet", err)
+	}
+	// the el and avs registry writers share the tx manager, and thus the nonce manager, so that their txs sent
+	// concurrently don't use the same nonces
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethHttpClient, logger).
+		WithNonceManager(txmgr.NewNonceManager(ethHttpClient, sender))
 
 	// creating AVS clients: Reader and Writer
 	avsRegistryChainReader, avsRegistryChainSubscriber, avsRegistryChainWriter, avsRegistryContractBindings, err := avsregistry.BuildClients(
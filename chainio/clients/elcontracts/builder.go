@@ -33,6 +33,7 @@ func BuildReadClients(
 		logger,
 		client,
 	)
+	elChainReader.rewardsCoordinatorAddr = elContractBindings.RewardsCoordinatorAddress
 
 	return elChainReader, elContractBindings, nil
 }
@@ -62,6 +63,7 @@ func BuildClients(
 		logger,
 		client,
 	)
+	elChainReader.rewardsCoordinatorAddr = elContractBindings.RewardsCoordinatorAddress
 
 	elChainWriter := NewChainWriter(
 		elContractBindings.Slasher,
@@ -2,13 +2,17 @@ package elcontracts
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
 	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
@@ -37,6 +41,8 @@ type ChainReader struct {
 	avsDirectory       *avsdirectory.ContractIAVSDirectory
 	rewardsCoordinator *rewardscoordinator.ContractIRewardsCoordinator
 	ethClient          eth.HttpBackend
+	// only needed by SimulateCheckClaimWithActivatedRoot, which has to set state overrides on the contract
+	rewardsCoordinatorAddr common.Address
 }
 
 func NewChainReader(
@@ -102,7 +108,7 @@ func NewReaderFromConfig(
 	if err != nil {
 		return nil, err
 	}
-	return NewChainReader(
+	chainReader := NewChainReader(
 		elContractBindings.Slasher,
 		elContractBindings.DelegationManager,
 		elContractBindings.StrategyManager,
@@ -110,7 +116,9 @@ func NewReaderFromConfig(
 		elContractBindings.RewardsCoordinator,
 		logger,
 		ethClient,
-	), nil
+	)
+	chainReader.rewardsCoordinatorAddr = elContractBindings.RewardsCoordinatorAddress
+	return chainReader, nil
 }
 
 func (r *ChainReader) IsOperatorRegistered(
@@ -337,6 +345,107 @@ func (r *ChainReader) CheckClaim(
 	return r.rewardsCoordinator.CheckClaim(&bind.CallOpts{Context: ctx}, claim)
 }
 
+// rewardsCoordinatorDistributionRootsSlot is the storage slot of the RewardsCoordinator _distributionRoots array,
+// after the slots used by the Initializable, OwnableUpgradeable, Pausable and ReentrancyGuardUpgradeable parents and
+// the _DOMAIN_SEPARATOR
+const rewardsCoordinatorDistributionRootsSlot = 202
+
+// activatedDistributionRootStorage returns the storage slot holding the rewardsCalculationEndTimestamp, activatedAt
+// and disabled fields of the distribution root at rootIndex, and its value with activatedAt set to 0
+func activatedDistributionRootStorage(
+	rootIndex uint32,
+	root rewardscoordinator.IRewardsCoordinatorDistributionRoot,
+) (common.Hash, common.Hash) {
+	arrayStart := new(big.Int).SetBytes(
+		crypto.Keccak256(common.BigToHash(big.NewInt(rewardsCoordinatorDistributionRootsSlot)).Bytes()),
+	)
+	// each DistributionRoot takes 2 slots: the root, then the packed other fields
+	slot := arrayStart.Add(arrayStart, big.NewInt(2*int64(rootIndex)+1))
+
+	var value common.Hash
+	binary.BigEndian.PutUint32(value[28:], root.RewardsCalculationEndTimestamp)
+	if root.Disabled {
+		value[23] = 1
+	}
+	return common.BigToHash(slot), value
+}
+
+// SimulateCheckClaimWithActivatedRoot runs CheckClaim as if the distribution root of the claim was already
+// activated, so that claims can be validated against a root which was just submitted.
+// It overrides the RewardsCoordinator storage during the eth_call, so the eth client must implement
+// CallContractWithOverrides (e.g. eth.InstrumentedClient).
+func (r *ChainReader) SimulateCheckClaimWithActivatedRoot(
+	ctx context.Context,
+	claim rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim,
+) (bool, error) {
+	if r.rewardsCoordinator == nil {
+		return false, errors.New("RewardsCoordinator contract not provided")
+	}
+	overridesClient, ok := r.ethClient.(interface {
+		CallContractWithOverrides(
+			ctx context.Context,
+			msg ethereum.CallMsg,
+			blockNumber *big.Int,
+			overrides map[common.Address]eth.OverrideAccount,
+		) ([]byte, error)
+	})
+	if !ok {
+		return false, errors.New("eth client does not support state overrides")
+	}
+	rewardsCoordinatorAbi, err := rewardscoordinator.ContractIRewardsCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return false, err
+	}
+
+	root, err := r.rewardsCoordinator.GetDistributionRootAtIndex(
+		&bind.CallOpts{Context: ctx},
+		big.NewInt(int64(claim.RootIndex)),
+	)
+	if err != nil {
+		return false, utils.WrapError("Failed to get distribution root", err)
+	}
+	slot, value := activatedDistributionRootStorage(claim.RootIndex, root)
+	overrides := map[common.Address]eth.OverrideAccount{
+		r.rewardsCoordinatorAddr: {StateDiff: map[common.Hash]common.Hash{slot: value}},
+	}
+	callWithOverrides := func(method string, args ...interface{}) ([]interface{}, error) {
+		data, err := rewardsCoordinatorAbi.Pack(method, args...)
+		if err != nil {
+			return nil, err
+		}
+		out, err := overridesClient.CallContractWithOverrides(
+			ctx,
+			ethereum.CallMsg{To: &r.rewardsCoordinatorAddr, Data: data},
+			nil,
+			overrides,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return rewardsCoordinatorAbi.Unpack(method, out)
+	}
+
+	// make sure the override hit the right slot before trusting the simulation
+	out, err := callWithOverrides("getDistributionRootAtIndex", big.NewInt(int64(claim.RootIndex)))
+	if err != nil {
+		return false, utils.WrapError("Failed to get overridden distribution root", err)
+	}
+	overriddenRoot := *abi.ConvertType(
+		out[0],
+		new(rewardscoordinator.IRewardsCoordinatorDistributionRoot),
+	).(*rewardscoordinator.IRewardsCoordinatorDistributionRoot)
+	root.ActivatedAt = 0
+	if overriddenRoot != root {
+		return false, errors.New("unexpected RewardsCoordinator storage layout, can't activate the distribution root")
+	}
+
+	out, err = callWithOverrides("checkClaim", claim)
+	if err != nil {
+		return false, utils.WrapError("Failed to simulate checkClaim", err)
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
 func (r *ChainReader) GetOperatorAVSSplit(
 	ctx context.Context,
 	operator gethcommon.Address,
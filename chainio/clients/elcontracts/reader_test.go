@@ -5,7 +5,12 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
 	erc20 "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IERC20"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IRewardsCoordinator"
+	servicemanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/ServiceManagerBase"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/Layr-Labs/eigensdk-go/testutils/testclients"
 	"github.com/Layr-Labs/eigensdk-go/types"
@@ -19,6 +24,7 @@ func TestChainReader(t *testing.T) {
 	ctx := context.Background()
 
 	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	anvilChainID := big.NewInt(31337)
 	operator := types.Operator{
 		Address: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
 	}
@@ -113,6 +119,20 @@ func TestChainReader(t *testing.T) {
 		)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, digest)
+
+		// the digest of the typed data signed by signerv2 is the same
+		typedData := signerv2.DelegationApprovalTypedData(
+			anvilChainID,
+			contractAddrs.DelegationManager,
+			staker,
+			common.HexToAddress(operator.Address),
+			delegationApprover,
+			approverSalt,
+			expiry,
+		)
+		typedDataDigest, err := signerv2.TypedDataDigest(typedData.Domain, typedData.Types, typedData.Message)
+		assert.NoError(t, err)
+		assert.Equal(t, digest, typedDataDigest)
 	})
 
 	t.Run("calculate operator AVS registration digest hash", func(t *testing.T) {
@@ -128,5 +148,42 @@ func TestChainReader(t *testing.T) {
 		)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, digest)
+
+		// the digest of the typed data signed by signerv2 is the same
+		serviceManager, err := servicemanager.NewContractServiceManagerBase(
+			contractAddrs.ServiceManager,
+			clients.EthHttpClient,
+		)
+		assert.NoError(t, err)
+		avsDirectory, err := serviceManager.AvsDirectory(&bind.CallOpts{})
+		assert.NoError(t, err)
+		typedData := signerv2.OperatorAVSRegistrationTypedData(
+			anvilChainID,
+			avsDirectory,
+			common.HexToAddress(operator.Address),
+			avs,
+			salt,
+			expiry,
+		)
+		typedDataDigest, err := signerv2.TypedDataDigest(typedData.Domain, typedData.Types, typedData.Message)
+		assert.NoError(t, err)
+		assert.Equal(t, digest, typedDataDigest)
 	})
 }
+
+func TestSimulateCheckClaimRequiresStateOverrides(t *testing.T) {
+	// an HttpBackend which doesn't implement CallContractWithOverrides
+	var ethClient struct{ eth.HttpBackend }
+	chainReader, err := elcontracts.NewReaderFromConfig(
+		elcontracts.Config{RewardsCoordinatorAddress: common.HexToAddress("0x1")},
+		ethClient,
+		testutils.GetTestLogger(),
+	)
+	assert.NoError(t, err)
+
+	_, err = chainReader.SimulateCheckClaimWithActivatedRoot(
+		context.Background(),
+		rewardscoordinator.IRewardsCoordinatorRewardsMerkleClaim{},
+	)
+	assert.ErrorContains(t, err, "eth client does not support state overrides")
+}
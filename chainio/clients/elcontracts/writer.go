@@ -6,11 +6,13 @@ import (
 
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
 	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
 	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
 	avsdirectory "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IAVSDirectory"
 	erc20 "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IERC20"
@@ -41,6 +43,10 @@ type ChainWriter struct {
 	ethClient           eth.HttpBackend
 	logger              logging.Logger
 	txMgr               txmgr.TxManager
+	calldataSuffix      []byte
+	// see WithReceiptDetails
+	receiptDetails  bool
+	revertErrorABIs []*abi.ABI
 }
 
 func NewChainWriter(
@@ -172,7 +178,7 @@ func (w *ChainWriter) RegisterAsOperator(
 		DelegationApprover:         gethcommon.HexToAddress(operator.DelegationApproverAddress),
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -180,9 +186,9 @@ func (w *ChainWriter) RegisterAsOperator(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 
@@ -207,7 +213,7 @@ func (w *ChainWriter) UpdateOperatorDetails(
 		StakerOptOutWindowBlocks:   operator.StakerOptOutWindowBlocks,
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -216,9 +222,9 @@ func (w *ChainWriter) UpdateOperatorDetails(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info(
 		"successfully updated operator details",
@@ -237,7 +243,7 @@ func (w *ChainWriter) UpdateMetadataURI(ctx context.Context, uri string, waitFor
 		return nil, errors.New("DelegationManager contract not provided")
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -246,9 +252,9 @@ func (w *ChainWriter) UpdateMetadataURI(ctx context.Context, uri string, waitFor
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 	w.logger.Info(
 		"successfully updated operator metadata uri",
@@ -270,7 +276,7 @@ func (w *ChainWriter) DepositERC20IntoStrategy(
 	}
 
 	w.logger.Infof("depositing %s tokens into strategy %s", amount.String(), strategyAddr)
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -286,18 +292,18 @@ func (w *ChainWriter) DepositERC20IntoStrategy(
 	if err != nil {
 		return nil, errors.Join(errors.New("failed to approve token transfer"), err)
 	}
-	_, err = w.txMgr.Send(ctx, tx, waitForReceipt)
+	_, err = w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 
 	tx, err = w.strategyManager.DepositIntoStrategy(noSendTxOpts, strategyAddr, underlyingTokenAddr, amount)
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
-		return nil, errors.New("failed to send tx with err: " + err.Error())
+		return nil, utils.WrapError("failed to send tx", err)
 	}
 
 	w.logger.Infof("deposited %s into strategy %s", amount.String(), strategyAddr)
@@ -313,7 +319,7 @@ func (w *ChainWriter) SetClaimerFor(
 		return nil, errors.New("RewardsCoordinator contract not provided")
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -322,7 +328,7 @@ func (w *ChainWriter) SetClaimerFor(
 	if err != nil {
 		return nil, err
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
 		return nil, utils.WrapError("failed to send tx", err)
 	}
@@ -340,7 +346,7 @@ func (w *ChainWriter) ProcessClaim(
 		return nil, errors.New("RewardsCoordinator contract not provided")
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, utils.WrapError("failed to get no send tx opts", err)
 	}
@@ -349,7 +355,7 @@ func (w *ChainWriter) ProcessClaim(
 	if err != nil {
 		return nil, utils.WrapError("failed to create ProcessClaim tx", err)
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
 		return nil, utils.WrapError("failed to send tx", err)
 	}
@@ -368,7 +374,7 @@ func (w *ChainWriter) SetOperatorAVSSplit(
 		return nil, errors.New("RewardsCoordinator contract not provided")
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, utils.WrapError("failed to get no send tx opts", err)
 	}
@@ -377,7 +383,7 @@ func (w *ChainWriter) SetOperatorAVSSplit(
 	if err != nil {
 		return nil, utils.WrapError("failed to create SetOperatorAVSSplit tx", err)
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
 		return nil, utils.WrapError("failed to send tx", err)
 	}
@@ -399,7 +405,7 @@ func (w *ChainWriter) ProcessClaims(
 		return nil, errors.New("claims is empty, at least one claim must be provided")
 	}
 
-	noSendTxOpts, err := w.txMgr.GetNoSendTxOpts()
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
 	if err != nil {
 		return nil, utils.WrapError("failed to get no send tx opts", err)
 	}
@@ -408,10 +414,68 @@ func (w *ChainWriter) ProcessClaims(
 	if err != nil {
 		return nil, utils.WrapError("failed to create ProcessClaims tx", err)
 	}
-	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	receipt, err := w.sendTx(ctx, tx, waitForReceipt)
 	if err != nil {
 		return nil, utils.WrapError("failed to send tx", err)
 	}
 
 	return receipt, nil
 }
+
+// WithCalldataSuffix makes the writer append suffix to the calldata of every transaction it sends, e.g. to tag the
+// transactions so that indexers can attribute them. The gas limit is estimated with the suffix included.
+func (w *ChainWriter) WithCalldataSuffix(suffix []byte) *ChainWriter {
+	w.calldataSuffix = suffix
+	return w
+}
+
+// WithReceiptDetails makes the writer enrich the receipts it waits for (see txmgr.EnrichReceipt): the transactions
+// which were mined but reverted are replayed to extract their revert reason, and returned as a txmgr.ErrTxReverted
+// holding the receipt with its details. The custom errors declared by errorABIs, e.g. the ABIs of the called
+// contracts, are decoded with their name and arguments.
+func (w *ChainWriter) WithReceiptDetails(errorABIs ...*abi.ABI) *ChainWriter {
+	w.receiptDetails = true
+	w.revertErrorABIs = errorABIs
+	return w
+}
+
+func (w *ChainWriter) sendTx(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	waitForReceipt bool,
+) (*gethtypes.Receipt, error) {
+	tx, err := chainioutils.AppendCalldataSuffix(tx, w.calldataSuffix)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := w.txMgr.Send(ctx, tx, waitForReceipt)
+	if err != nil || !waitForReceipt || !w.receiptDetails {
+		return receipt, err
+	}
+	return w.enrichReceipt(ctx, tx, receipt)
+}
+
+// enrichReceipt logs the effective gas cost of the receipt of tx, and returns an ErrTxReverted with the revert reason
+// if tx failed. The receipt is returned as is if it can't be enriched.
+func (w *ChainWriter) enrichReceipt(
+	ctx context.Context,
+	tx *gethtypes.Transaction,
+	receipt *gethtypes.Receipt,
+) (*gethtypes.Receipt, error) {
+	noSendTxOpts, err := w.txMgr.GetNoSendTxOptsWithContext(ctx)
+	if err != nil {
+		w.logger.Warn("failed to get sender to enrich receipt", "txHash", receipt.TxHash.Hex(), "err", err)
+		return receipt, nil
+	}
+	details, err := txmgr.EnrichReceipt(ctx, w.ethClient, noSendTxOpts.From, tx, receipt, w.revertErrorABIs...)
+	if err != nil {
+		w.logger.Warn("failed to enrich receipt", "txHash", receipt.TxHash.Hex(), "err", err)
+		return receipt, nil
+	}
+	w.logger.Debug("transaction confirmed", "txHash", receipt.TxHash.Hex(),
+		"effectiveGasCost", details.EffectiveGasCost, "status", receipt.Status)
+	if receipt.Status == gethtypes.ReceiptStatusFailed {
+		return nil, &txmgr.ErrTxReverted{Receipt: details}
+	}
+	return receipt, nil
+}
@@ -2,15 +2,26 @@ package elcontracts_test
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	"github.com/Layr-Labs/eigensdk-go/internal/fakes"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/Layr-Labs/eigensdk-go/testutils/testclients"
 	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -146,3 +157,106 @@ func TestChainWriter(t *testing.T) {
 		assert.True(t, receipt.Status == 1)
 	})
 }
+
+// fakeChainBackend accepts every transaction, which is mined as soon as it's sent
+type fakeChainBackend struct {
+	*fakes.EthClient
+	mu      sync.Mutex
+	sentTxs map[common.Hash]*gethtypes.Transaction
+}
+
+func (b *fakeChainBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (b *fakeChainBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 50_000, nil
+}
+
+func (b *fakeChainBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return &gethtypes.Header{Number: big.NewInt(1), BaseFee: big.NewInt(1)}, nil
+}
+
+func (b *fakeChainBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (b *fakeChainBackend) BlockByNumber(ctx context.Context, number *big.Int) (*gethtypes.Block, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (b *fakeChainBackend) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sentTxs[tx.Hash()] = tx
+	return nil
+}
+
+func (b *fakeChainBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.sentTxs[txHash]; !ok {
+		return nil, ethereum.NotFound
+	}
+	return &gethtypes.Receipt{
+		TxHash:      txHash,
+		Status:      gethtypes.ReceiptStatusSuccessful,
+		BlockNumber: big.NewInt(1),
+	}, nil
+}
+
+func TestChainWriterWithMultiWallet(t *testing.T) {
+	backend := &fakeChainBackend{EthClient: fakes.NewEthClient(), sentTxs: map[common.Hash]*gethtypes.Transaction{}}
+	chainId, err := backend.ChainID(context.Background())
+	require.NoError(t, err)
+	logger := testutils.GetTestLogger()
+	wallets := map[common.Address]wallet.Wallet{}
+	var senders []common.Address
+	for i := 0; i < 2; i++ {
+		ecdsaSk, addr, err := testutils.NewEcdsaSkAndAddress()
+		require.NoError(t, err)
+		signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
+		require.NoError(t, err)
+		wallets[addr], err = wallet.NewPrivateKeyWallet(backend, signerFn, addr, logger)
+		require.NoError(t, err)
+		senders = append(senders, addr)
+	}
+	multiWallet, err := wallet.NewMultiWallet(wallets)
+	require.NoError(t, err)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(multiWallet, backend, logger)
+
+	delegationManager, err := delegationmanager.NewContractDelegationManager(common.Address{0x1}, backend)
+	require.NoError(t, err)
+	chainWriter := elcontracts.NewChainWriter(
+		nil,
+		delegationManager,
+		nil,
+		nil,
+		nil,
+		common.Address{},
+		nil,
+		backend,
+		logger,
+		nil,
+		txMgr,
+	)
+
+	// the sender of each transaction is the one selected on the context of the call
+	for _, sender := range senders {
+		ctx := wallet.WithSenderAddress(context.Background(), sender)
+		receipt, err := chainWriter.UpdateMetadataURI(ctx, "https://example.com/metadata.json", true)
+		require.NoError(t, err)
+
+		backend.mu.Lock()
+		tx := backend.sentTxs[receipt.TxHash]
+		backend.mu.Unlock()
+		require.NotNil(t, tx)
+		from, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(chainId), tx)
+		require.NoError(t, err)
+		require.Equal(t, sender, from)
+	}
+
+	// a multi wallet has no sender unless one is selected
+	_, err = chainWriter.UpdateMetadataURI(context.Background(), "https://example.com/metadata.json", true)
+	require.Error(t, err)
+}
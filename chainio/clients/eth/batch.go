@@ -0,0 +1,54 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchGetTransactionReceipts fetches the receipts of all the txs in a single batch request.
+// The receipts and errors are returned in the order of hashes: for each tx, either its receipt or its error is set.
+// The error of txs which are unknown or not mined yet is ethereum.NotFound.
+// The returned error is only set when the whole batch failed.
+func BatchGetTransactionReceipts(
+	ctx context.Context,
+	client BatchCaller,
+	hashes []common.Hash,
+) ([]*types.Receipt, []error, error) {
+	results := make([]json.RawMessage, len(hashes))
+	batch := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash},
+			Result: &results[i],
+		}
+	}
+	if err := client.BatchCall(ctx, batch); err != nil {
+		return nil, nil, err
+	}
+
+	receipts := make([]*types.Receipt, len(hashes))
+	errs := make([]error, len(hashes))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			errs[i] = elem.Error
+			continue
+		}
+		if len(results[i]) == 0 || string(results[i]) == "null" {
+			errs[i] = ethereum.NotFound
+			continue
+		}
+		receipt := new(types.Receipt)
+		if err := json.Unmarshal(results[i], receipt); err != nil {
+			errs[i] = err
+			continue
+		}
+		receipts[i] = receipt
+	}
+	return receipts, errs, nil
+}
@@ -0,0 +1,73 @@
+package eth_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	rpccalls "github.com/Layr-Labs/eigensdk-go/metrics/collectors/rpc_calls"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var errBadReceipt = errors.New("bad receipt")
+
+// fakeReceiptsService knows the receipts of the txs whose hash starts with 0x01, and fails for those starting
+// with 0xff
+type fakeReceiptsService struct{}
+
+func (s *fakeReceiptsService) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	switch hash[0] {
+	case 0x01:
+		return &types.Receipt{
+			TxHash:      hash,
+			Status:      types.ReceiptStatusSuccessful,
+			Logs:        []*types.Log{},
+			BlockNumber: big.NewInt(1),
+		}, nil
+	case 0xff:
+		return nil, errBadReceipt
+	default:
+		return nil, nil
+	}
+}
+
+func TestBatchGetTransactionReceipts(t *testing.T) {
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", &fakeReceiptsService{}))
+	server := httptest.NewServer(rpcServer)
+	defer server.Close()
+	reg := prometheus.NewRegistry()
+	client, err := eth.NewInstrumentedClient(server.URL, rpccalls.NewCollector("exampleAvs", reg))
+	require.NoError(t, err)
+
+	hashes := []common.Hash{
+		{0x01, 0x1},
+		{0xff},
+		{0x01, 0x2},
+		{0x02},
+	}
+	receipts, errs, err := eth.BatchGetTransactionReceipts(context.Background(), client, hashes)
+	require.NoError(t, err)
+	require.Len(t, receipts, len(hashes))
+	require.Len(t, errs, len(hashes))
+
+	require.NoError(t, errs[0])
+	require.Equal(t, hashes[0], receipts[0].TxHash)
+	require.ErrorContains(t, errs[1], errBadReceipt.Error())
+	require.Nil(t, receipts[1])
+	require.NoError(t, errs[2])
+	require.Equal(t, hashes[2], receipts[2].TxHash)
+	require.ErrorIs(t, errs[3], ethereum.NotFound)
+	require.Nil(t, receipts[3])
+
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "eigen_rpc_batch_size"))
+}
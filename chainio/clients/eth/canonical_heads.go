@@ -0,0 +1,215 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxTrackedReorgDepth is the number of headers kept on top of the confirmation depth to detect reorgs.
+// Reorgs deeper than depth+maxTrackedReorgDepth can't be detected.
+const maxTrackedReorgDepth = 64
+
+// HeadsClient is the subset of the eth client needed to follow the canonical chain
+type HeadsClient interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+}
+
+// Reorg notifies that the chain seen so far was replaced
+type Reorg struct {
+	// OldHead is the hash of the head of the replaced chain
+	OldHead common.Hash
+	// NewHead is the hash of the head of the new chain
+	NewHead common.Hash
+	// CommonAncestor is the number of the last block shared by both chains
+	CommonAncestor uint64
+}
+
+// CanonicalHeadsSubscription is the subscription returned by SubscribeCanonicalHeads
+type CanonicalHeadsSubscription struct {
+	headsC  chan *types.Header
+	reorgsC chan Reorg
+	errC    chan error
+	quit    chan struct{}
+	once    sync.Once
+}
+
+// Heads returns the channel receiving the headers once they are confirmed, in increasing block number order.
+// After a Reorg deeper than the confirmation depth, the headers of the new chain after the common ancestor are sent
+// again.
+func (s *CanonicalHeadsSubscription) Heads() <-chan *types.Header {
+	return s.headsC
+}
+
+// Reorgs returns the channel receiving the reorgs of the chain seen by the subscription
+func (s *CanonicalHeadsSubscription) Reorgs() <-chan Reorg {
+	return s.reorgsC
+}
+
+// Err returns the channel receiving the error terminating the subscription. It is closed on Unsubscribe.
+func (s *CanonicalHeadsSubscription) Err() <-chan error {
+	return s.errC
+}
+
+func (s *CanonicalHeadsSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.quit)
+	})
+}
+
+// SubscribeCanonicalHeads subscribes to the new heads of the chain, only emitting them once they are depth blocks
+// deep, and emitting a Reorg each time the chain seen so far is replaced.
+// Parent hashes are verified against the recently seen headers, and the headers missed by the underlying
+// subscription are fetched.
+func SubscribeCanonicalHeads(
+	ctx context.Context,
+	client HeadsClient,
+	depth uint64,
+) (*CanonicalHeadsSubscription, error) {
+	newHeadsC := make(chan *types.Header)
+	sub, err := client.SubscribeNewHead(ctx, newHeadsC)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &CanonicalHeadsSubscription{
+		headsC:  make(chan *types.Header),
+		reorgsC: make(chan Reorg),
+		errC:    make(chan error, 1),
+		quit:    make(chan struct{}),
+	}
+	tracker := &headTracker{
+		client:   client,
+		depth:    depth,
+		capacity: int(depth) + maxTrackedReorgDepth,
+	}
+	go func() {
+		defer close(s.errC)
+		defer sub.Unsubscribe()
+		for {
+			var head *types.Header
+			select {
+			case <-s.quit:
+				return
+			case err := <-sub.Err():
+				s.errC <- err
+				return
+			case head = <-newHeadsC:
+			}
+
+			confirmed, reorg, err := tracker.add(ctx, head)
+			if err != nil {
+				s.errC <- err
+				return
+			}
+			if reorg != nil {
+				select {
+				case s.reorgsC <- *reorg:
+				case <-s.quit:
+					return
+				}
+			}
+			for _, header := range confirmed {
+				select {
+				case s.headsC <- header:
+				case <-s.quit:
+					return
+				}
+			}
+		}
+	}()
+	return s, nil
+}
+
+// headTracker keeps the recent headers of the canonical chain, ordered and linked by parent hash
+type headTracker struct {
+	client   HeadsClient
+	depth    uint64
+	capacity int
+
+	recent []*types.Header
+	// number of the next header to confirm, only valid if recent is not empty
+	nextConfirmed uint64
+}
+
+// add adds a new head, returning the headers which got confirmed by it and the reorg it caused, if any
+func (t *headTracker) add(ctx context.Context, head *types.Header) ([]*types.Header, *Reorg, error) {
+	if len(t.recent) == 0 {
+		t.recent = []*types.Header{head}
+		t.nextConfirmed = head.Number.Uint64()
+		return t.confirmed(), nil, nil
+	}
+	first := t.recent[0].Number.Uint64()
+	if number := head.Number.Uint64(); number >= first && number-first < uint64(len(t.recent)) &&
+		t.recent[number-first].Hash() == head.Hash() {
+		// already seen
+		return nil, nil, nil
+	}
+
+	// walk back from the new head until reaching a header we have already seen
+	newChain := []*types.Header{head}
+	cur := head
+	var ancestorIdx int
+	for {
+		number := cur.Number.Uint64()
+		if number <= first {
+			return nil, nil, fmt.Errorf(
+				"new head %s does not connect to the %d tracked blocks, the reorg is too deep",
+				head.Hash(),
+				len(t.recent),
+			)
+		}
+		ancestorIdx = int(number - 1 - first)
+		if ancestorIdx < len(t.recent) && t.recent[ancestorIdx].Hash() == cur.ParentHash {
+			break
+		}
+		parent, err := t.client.HeaderByHash(ctx, cur.ParentHash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get parent header %s: %w", cur.ParentHash, err)
+		}
+		if parent.Number.Uint64()+1 != number {
+			return nil, nil, errors.New("parent header has an unexpected block number")
+		}
+		newChain = append(newChain, parent)
+		cur = parent
+	}
+
+	var reorg *Reorg
+	oldHead := t.recent[len(t.recent)-1]
+	if ancestorIdx < len(t.recent)-1 {
+		ancestor := t.recent[ancestorIdx].Number.Uint64()
+		reorg = &Reorg{
+			OldHead:        oldHead.Hash(),
+			NewHead:        head.Hash(),
+			CommonAncestor: ancestor,
+		}
+		// the confirmed headers after the ancestor were replaced, the new ones will be confirmed again
+		t.nextConfirmed = min(t.nextConfirmed, ancestor+1)
+	}
+
+	t.recent = t.recent[:ancestorIdx+1]
+	for i := len(newChain) - 1; i >= 0; i-- {
+		t.recent = append(t.recent, newChain[i])
+	}
+	if len(t.recent) > t.capacity {
+		t.recent = t.recent[len(t.recent)-t.capacity:]
+	}
+	return t.confirmed(), reorg, nil
+}
+
+// confirmed returns the headers which are depth blocks deep and weren't returned yet
+func (t *headTracker) confirmed() []*types.Header {
+	headNumber := t.recent[len(t.recent)-1].Number.Uint64()
+	first := t.recent[0].Number.Uint64()
+	var confirmed []*types.Header
+	for t.nextConfirmed = max(t.nextConfirmed, first); t.nextConfirmed+t.depth <= headNumber; t.nextConfirmed++ {
+		confirmed = append(confirmed, t.recent[t.nextConfirmed-first])
+	}
+	return confirmed
+}
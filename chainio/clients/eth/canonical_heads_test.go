@@ -0,0 +1,144 @@
+package eth_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHeadsClient serves the headers it knows by hash, and lets the test push new heads
+type fakeHeadsClient struct {
+	mu      sync.Mutex
+	headers map[common.Hash]*types.Header
+	headsC  chan<- *types.Header
+}
+
+func (c *fakeHeadsClient) SubscribeNewHead(
+	ctx context.Context,
+	ch chan<- *types.Header,
+) (ethereum.Subscription, error) {
+	c.headsC = ch
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func (c *fakeHeadsClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header, ok := c.headers[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return header, nil
+}
+
+// extend creates n headers on top of parent, differentiated from other forks by fork
+func (c *fakeHeadsClient) extend(parent *types.Header, n int, fork byte) []*types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var headers []*types.Header
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Extra:      []byte{fork},
+		}
+		c.headers[header.Hash()] = header
+		headers = append(headers, header)
+		parent = header
+	}
+	return headers
+}
+
+func receiveHeads(t *testing.T, sub *eth.CanonicalHeadsSubscription, n int) []uint64 {
+	var numbers []uint64
+	for i := 0; i < n; i++ {
+		select {
+		case header := <-sub.Heads():
+			numbers = append(numbers, header.Number.Uint64())
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a confirmed head")
+		}
+	}
+	return numbers
+}
+
+func TestSubscribeCanonicalHeads(t *testing.T) {
+	genesis := &types.Header{Number: big.NewInt(0)}
+	client := &fakeHeadsClient{headers: map[common.Hash]*types.Header{genesis.Hash(): genesis}}
+	sub, err := eth.SubscribeCanonicalHeads(context.Background(), client, 2)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	chain := append([]*types.Header{genesis}, client.extend(genesis, 5, 0)...)
+	client.headsC <- chain[0]
+	client.headsC <- chain[1]
+	client.headsC <- chain[2]
+	require.Equal(t, []uint64{0}, receiveHeads(t, sub, 1))
+	client.headsC <- chain[3]
+	require.Equal(t, []uint64{1}, receiveHeads(t, sub, 1))
+	// block 4 is skipped by the underlying subscription, it must be fetched by hash
+	client.headsC <- chain[5]
+	require.Equal(t, []uint64{2, 3}, receiveHeads(t, sub, 2))
+
+	// replace blocks 4 and 5, which were not confirmed yet, and block 3, which was
+	fork := client.extend(chain[2], 4, 1)
+	client.headsC <- fork[3]
+	select {
+	case reorg := <-sub.Reorgs():
+		require.Equal(t, eth.Reorg{OldHead: chain[5].Hash(), NewHead: fork[3].Hash(), CommonAncestor: 2}, reorg)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the reorg")
+	}
+	require.Equal(t, []uint64{3, 4}, receiveHeads(t, sub, 2))
+}
+
+// TestSubscribeCanonicalHeadsAnvilReorg forces a reorg on anvil by reverting to a snapshot and mining again
+func TestSubscribeCanonicalHeadsAnvilReorg(t *testing.T) {
+	ctx := context.Background()
+	rpcClient, err := rpc.Dial(anvilHttpEndpoint)
+	require.NoError(t, err)
+	wsClient, err := ethclient.Dial(anvilWsEndpoint)
+	require.NoError(t, err)
+
+	sub, err := eth.SubscribeCanonicalHeads(ctx, wsClient, 0)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	var snapshotId string
+	require.NoError(t, rpcClient.CallContext(ctx, &snapshotId, "evm_snapshot"))
+	require.NoError(t, rpcClient.CallContext(ctx, nil, "anvil_mine", 3))
+	oldHeads := receiveHeads(t, sub, 3)
+
+	var reverted bool
+	require.NoError(t, rpcClient.CallContext(ctx, &reverted, "evm_revert", snapshotId))
+	require.True(t, reverted)
+	// make sure the new blocks differ from the reverted ones
+	require.NoError(t, rpcClient.CallContext(ctx, nil, "evm_increaseTime", 100))
+	require.NoError(t, rpcClient.CallContext(ctx, nil, "anvil_mine", 4))
+
+	select {
+	case reorg := <-sub.Reorgs():
+		require.Equal(t, oldHeads[0]-1, reorg.CommonAncestor)
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the reorg")
+	}
+}
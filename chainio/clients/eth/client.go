@@ -4,7 +4,11 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 )
@@ -30,3 +34,38 @@ type WsBackend interface {
 	BlockNumber(ctx context.Context) (uint64, error)
 	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
 }
+
+// Client is an HttpBackend which also exposes the fee and receipt related calls needed by the txmgr and fee
+// estimation, so that users don't need to bypass the InstrumentedClient (and lose its metrics) to make them
+type Client interface {
+	HttpBackend
+
+	ChainID(ctx context.Context) (*big.Int, error)
+	FeeHistory(
+		ctx context.Context,
+		blockCount uint64,
+		lastBlock *big.Int,
+		rewardPercentiles []float64,
+	) (*ethereum.FeeHistory, error)
+	BlobBaseFee(ctx context.Context) (*big.Int, error)
+	PendingTransactionCount(ctx context.Context) (uint, error)
+	BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CallContractWithOverrides(
+		ctx context.Context,
+		msg ethereum.CallMsg,
+		blockNumber *big.Int,
+		overrides map[common.Address]OverrideAccount,
+	) ([]byte, error)
+	BatchCaller
+}
+
+// BatchCaller sends multiple json-rpc requests in a single batch
+type BatchCaller interface {
+	// BatchCall sends all the batch elements in a single request. The returned error is only set when the whole batch
+	// failed, the error of each element is set in its Error field.
+	BatchCall(ctx context.Context, batch []rpc.BatchElem) error
+}
+
+// OverrideAccount specifies the state of an account to be overridden during an eth_call
+type OverrideAccount = gethclient.OverrideAccount
@@ -0,0 +1,101 @@
+package eth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ClientOption configures the connection of the clients created by NewClientWithOptions
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	headers        http.Header
+	bearerTokenFn  func() (string, error)
+	httpClient     *http.Client
+	requestTimeout time.Duration
+	rpcLogger      RPCLogger
+}
+
+// WithHeader sets a static header sent with every request (or with the handshake for websocket endpoints)
+func WithHeader(key, value string) ClientOption {
+	return func(o *clientOptions) {
+		o.headers.Set(key, value)
+	}
+}
+
+// WithBearerToken sets the Authorization header to "Bearer <token>", fetching the token from tokenFn before each
+// request so that it can be refreshed
+func WithBearerToken(tokenFn func() (string, error)) ClientOption {
+	return func(o *clientOptions) {
+		o.bearerTokenFn = tokenFn
+	}
+}
+
+// WithHTTPClient sets the http client used for HTTP endpoints, e.g. to configure TLS client certificates
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithRequestTimeout sets the timeout of each request made to HTTP endpoints
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.requestTimeout = timeout
+	}
+}
+
+func (o *clientOptions) rpcOptions() []rpc.ClientOption {
+	rpcOpts := []rpc.ClientOption{rpc.WithHeaders(o.headers)}
+	if o.bearerTokenFn != nil {
+		rpcOpts = append(rpcOpts, rpc.WithHTTPAuth(func(h http.Header) error {
+			token, err := o.bearerTokenFn()
+			if err != nil {
+				return err
+			}
+			h.Set("Authorization", "Bearer "+token)
+			return nil
+		}))
+	}
+	if o.httpClient != nil || o.requestTimeout > 0 || o.rpcLogger != nil {
+		httpClient := &http.Client{}
+		if o.httpClient != nil {
+			// copy the client so that setting the timeout or transport doesn't modify the caller's client
+			clientCopy := *o.httpClient
+			httpClient = &clientCopy
+		}
+		if o.requestTimeout > 0 {
+			httpClient.Timeout = o.requestTimeout
+		}
+		if o.rpcLogger != nil {
+			base := httpClient.Transport
+			if base == nil {
+				base = http.DefaultTransport
+			}
+			httpClient.Transport = &rpcLoggingTransport{base: base, rpcLogger: o.rpcLogger}
+		}
+		rpcOpts = append(rpcOpts, rpc.WithHTTPClient(httpClient))
+	}
+	return rpcOpts
+}
+
+// NewClientWithOptions dials url (http or websocket) with the given options
+func NewClientWithOptions(url string, opts ...ClientOption) (*ethclient.Client, error) {
+	return dialWithOptions(context.Background(), url, opts...)
+}
+
+func dialWithOptions(ctx context.Context, url string, opts ...ClientOption) (*ethclient.Client, error) {
+	o := &clientOptions{headers: make(http.Header)}
+	for _, opt := range opts {
+		opt(o)
+	}
+	rpcClient, err := rpc.DialOptions(ctx, url, o.rpcOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rpcClient), nil
+}
@@ -0,0 +1,85 @@
+package eth_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuthenticatedEthServer serves fakeEthService, rejecting the requests whose header key isn't set to value
+func newAuthenticatedEthServer(t *testing.T, key, value string) *httptest.Server {
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", &fakeEthService{blockNumber: 1}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(key) != value {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rpcServer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		rpcServer.Stop()
+	})
+	return server
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("static header", func(t *testing.T) {
+		server := newAuthenticatedEthServer(t, "X-Api-Key", "secret")
+
+		client, err := eth.NewClientWithOptions(server.URL)
+		require.NoError(t, err)
+		_, err = client.BlockNumber(ctx)
+		require.Error(t, err)
+
+		client, err = eth.NewClientWithOptions(server.URL, eth.WithHeader("X-Api-Key", "secret"))
+		require.NoError(t, err)
+		blockNumber, err := client.BlockNumber(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), blockNumber)
+	})
+
+	t.Run("bearer token is fetched for each request", func(t *testing.T) {
+		server := newAuthenticatedEthServer(t, "Authorization", "Bearer token-2")
+		tokenRefreshes := 0
+		tokenFn := func() (string, error) {
+			tokenRefreshes++
+			return fmt.Sprintf("token-%d", tokenRefreshes), nil
+		}
+
+		client, err := eth.NewClientWithOptions(server.URL, eth.WithBearerToken(tokenFn))
+		require.NoError(t, err)
+		_, err = client.BlockNumber(ctx)
+		require.Error(t, err)
+		_, err = client.BlockNumber(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("request timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		client, err := eth.NewClientWithOptions(
+			server.URL,
+			eth.WithHTTPClient(&http.Client{}),
+			eth.WithRequestTimeout(20*time.Millisecond),
+		)
+		require.NoError(t, err)
+		start := time.Now()
+		_, err = client.BlockNumber(ctx)
+		require.Error(t, err)
+		require.Less(t, time.Since(start), 200*time.Millisecond)
+	})
+}
@@ -0,0 +1,356 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+)
+
+const (
+	defaultMaxConsecutiveErrors  = 3
+	defaultRecoveryProbeInterval = 10 * time.Second
+)
+
+// FailoverOpts configures a FailoverClient
+type FailoverOpts struct {
+	// MaxConsecutiveErrors is the number of consecutive endpoint errors after which an endpoint is marked unhealthy.
+	// Defaults to 3.
+	MaxConsecutiveErrors int
+	// RecoveryProbeInterval is the interval at which unhealthy endpoints are probed (with eth_blockNumber) to detect
+	// their recovery. Defaults to 10s.
+	RecoveryProbeInterval time.Duration
+	// Registry is used to register the per-endpoint health metric. No metric is exposed if nil.
+	Registry prometheus.Registerer
+}
+
+// FailoverClient is an eth client backed by multiple endpoints. Calls are sent to the first healthy endpoint, in the
+// order the endpoints were given, and are retried on the next endpoints on connection errors.
+// An endpoint is marked unhealthy after FailoverOpts.MaxConsecutiveErrors consecutive errors and is probed in the
+// background until it recovers. Errors returned by the node itself (e.g. reverts) are not endpoint errors, so they
+// are returned as is without trying other endpoints.
+// Stateful calls (SubscribeFilterLogs) are pinned to a single endpoint until it fails.
+type FailoverClient struct {
+	endpoints []*failoverEndpoint
+	opts      FailoverOpts
+	// endpointHealthy is nil when no metrics registry is given
+	endpointHealthy *prometheus.GaugeVec
+
+	pinnedMu sync.Mutex
+	// index of the endpoint used for stateful calls
+	pinned int
+
+	stopC     chan struct{}
+	closeOnce sync.Once
+}
+
+type failoverEndpoint struct {
+	index  int
+	client *ethclient.Client
+
+	mu                sync.Mutex
+	healthy           bool
+	consecutiveErrors int
+}
+
+var _ HttpBackend = (*FailoverClient)(nil)
+var _ WsBackend = (*FailoverClient)(nil)
+
+// NewFailoverClient dials all the endpoints and starts probing the unhealthy ones in the background.
+// Close must be called to stop the background probing.
+func NewFailoverClient(endpoints []string, opts FailoverOpts) (*FailoverClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one endpoint is required")
+	}
+	if opts.MaxConsecutiveErrors <= 0 {
+		opts.MaxConsecutiveErrors = defaultMaxConsecutiveErrors
+	}
+	if opts.RecoveryProbeInterval <= 0 {
+		opts.RecoveryProbeInterval = defaultRecoveryProbeInterval
+	}
+
+	c := &FailoverClient{
+		opts:  opts,
+		stopC: make(chan struct{}),
+	}
+	if opts.Registry != nil {
+		c.endpointHealthy = promauto.With(opts.Registry).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: eigentypes.EigenPromNamespace,
+				Name:      "eth_failover_endpoint_healthy",
+				Help:      "Whether the eth endpoint (by its index in the configured list) is healthy (1) or not (0)",
+			},
+			[]string{"endpoint"},
+		)
+	}
+	for i, endpoint := range endpoints {
+		client, err := ethclient.Dial(endpoint)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.endpoints = append(c.endpoints, &failoverEndpoint{index: i, client: client, healthy: true})
+		c.setHealthMetric(i, true)
+	}
+
+	go c.probeUnhealthyEndpoints()
+	return c, nil
+}
+
+// Close stops the background probing and closes the connections to all the endpoints
+func (c *FailoverClient) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stopC)
+		for _, endpoint := range c.endpoints {
+			endpoint.client.Close()
+		}
+	})
+}
+
+// IsEndpointHealthy returns whether the endpoint at the given index is currently considered healthy
+func (c *FailoverClient) IsEndpointHealthy(index int) bool {
+	return c.isEndpointHealthy(c.endpoints[index])
+}
+
+func (c *FailoverClient) setHealthMetric(index int, healthy bool) {
+	if c.endpointHealthy == nil {
+		return
+	}
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	c.endpointHealthy.WithLabelValues(strconv.Itoa(index)).Set(value)
+}
+
+func (c *FailoverClient) recordSuccess(endpoint *failoverEndpoint) {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+	endpoint.consecutiveErrors = 0
+	if !endpoint.healthy {
+		endpoint.healthy = true
+		c.setHealthMetric(endpoint.index, true)
+	}
+}
+
+func (c *FailoverClient) recordError(endpoint *failoverEndpoint) {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+	endpoint.consecutiveErrors++
+	if endpoint.healthy && endpoint.consecutiveErrors >= c.opts.MaxConsecutiveErrors {
+		endpoint.healthy = false
+		c.setHealthMetric(endpoint.index, false)
+	}
+}
+
+// orderedEndpoints returns the healthy endpoints first, followed by the unhealthy ones as a last resort,
+// both in configuration order starting from the given index
+func (c *FailoverClient) orderedEndpoints(start int) []*failoverEndpoint {
+	healthy := make([]*failoverEndpoint, 0, len(c.endpoints))
+	var unhealthy []*failoverEndpoint
+	for i := range c.endpoints {
+		endpoint := c.endpoints[(start+i)%len(c.endpoints)]
+		if c.isEndpointHealthy(endpoint) {
+			healthy = append(healthy, endpoint)
+		} else {
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (c *FailoverClient) probeUnhealthyEndpoints() {
+	ticker := time.NewTicker(c.opts.RecoveryProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopC:
+			return
+		case <-ticker.C:
+		}
+		for _, endpoint := range c.endpoints {
+			if c.isEndpointHealthy(endpoint) {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), c.opts.RecoveryProbeInterval)
+			_, err := endpoint.client.BlockNumber(ctx)
+			cancel()
+			if err == nil {
+				c.recordSuccess(endpoint)
+			}
+		}
+	}
+}
+
+func (c *FailoverClient) isEndpointHealthy(endpoint *failoverEndpoint) bool {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+	return endpoint.healthy
+}
+
+// isEndpointError returns whether err is caused by the endpoint being unavailable, as opposed to an error returned
+// by the node for this specific request (reverts, unknown tx, ...) which other endpoints would return too
+func isEndpointError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+func failoverCall[T any](ctx context.Context, c *FailoverClient, call func(*ethclient.Client) (T, error)) (T, error) {
+	var lastErr error
+	for _, endpoint := range c.orderedEndpoints(0) {
+		result, err := call(endpoint.client)
+		if err == nil {
+			c.recordSuccess(endpoint)
+			return result, nil
+		}
+		if !isEndpointError(err) {
+			c.recordSuccess(endpoint)
+			return result, err
+		}
+		c.recordError(endpoint)
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	var zero T
+	return zero, lastErr
+}
+
+func (c *FailoverClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (*big.Int, error) {
+		return client.ChainID(ctx)
+	})
+}
+
+func (c *FailoverClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
+}
+
+func (c *FailoverClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (*types.Block, error) {
+		return client.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *FailoverClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *FailoverClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) ([]byte, error) {
+		return client.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (c *FailoverClient) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) ([]byte, error) {
+		return client.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (c *FailoverClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) ([]byte, error) {
+		return client.PendingCodeAt(ctx, account)
+	})
+}
+
+func (c *FailoverClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (uint64, error) {
+		return client.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *FailoverClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *FailoverClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *FailoverClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, call)
+	})
+}
+
+func (c *FailoverClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	// resending the same signed tx to another endpoint is safe, it can only be included once
+	_, err := failoverCall(ctx, c, func(client *ethclient.Client) (struct{}, error) {
+		return struct{}{}, client.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+func (c *FailoverClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, txHash)
+	})
+}
+
+func (c *FailoverClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return failoverCall(ctx, c, func(client *ethclient.Client) ([]types.Log, error) {
+		return client.FilterLogs(ctx, query)
+	})
+}
+
+// SubscribeFilterLogs subscribes on the pinned endpoint. If the subscription can't be created there, the next
+// endpoints are tried and the first one that succeeds becomes the pinned endpoint.
+func (c *FailoverClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	c.pinnedMu.Lock()
+	defer c.pinnedMu.Unlock()
+
+	var lastErr error
+	for _, endpoint := range c.orderedEndpoints(c.pinned) {
+		sub, err := endpoint.client.SubscribeFilterLogs(ctx, query, ch)
+		if err == nil {
+			c.recordSuccess(endpoint)
+			c.pinned = endpoint.index
+			return sub, nil
+		}
+		if !isEndpointError(err) {
+			return nil, err
+		}
+		c.recordError(endpoint)
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
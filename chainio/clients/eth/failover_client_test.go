@@ -0,0 +1,127 @@
+package eth_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEthService serves eth_blockNumber and eth_chainId with a fixed value identifying the server
+type fakeEthService struct {
+	blockNumber uint64
+}
+
+func (s *fakeEthService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.blockNumber)
+}
+
+func (s *fakeEthService) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(31337))
+}
+
+func newFakeEthServer(t *testing.T, blockNumber uint64) *httptest.Server {
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", &fakeEthService{blockNumber: blockNumber}))
+	server := httptest.NewServer(rpcServer)
+	t.Cleanup(func() {
+		server.Close()
+		rpcServer.Stop()
+	})
+	return server
+}
+
+func TestFailoverClient(t *testing.T) {
+	primary := newFakeEthServer(t, 1)
+	secondary := newFakeEthServer(t, 2)
+	reg := prometheus.NewRegistry()
+
+	client, err := eth.NewFailoverClient(
+		[]string{primary.URL, secondary.URL},
+		eth.FailoverOpts{MaxConsecutiveErrors: 2, RecoveryProbeInterval: 50 * time.Millisecond, Registry: reg},
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	blockNumber, err := client.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), blockNumber)
+
+	// kill the primary mid-run: calls must transparently fail over to the secondary
+	primary.CloseClientConnections()
+	primary.Close()
+	for i := 0; i < 2; i++ {
+		blockNumber, err = client.BlockNumber(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), blockNumber)
+	}
+	require.False(t, client.IsEndpointHealthy(0))
+	require.True(t, client.IsEndpointHealthy(1))
+	require.Equal(t, 2, testutil.CollectAndCount(reg, "eigen_eth_failover_endpoint_healthy"))
+
+	chainId, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(31337), chainId.Int64())
+}
+
+func TestFailoverClientRecovery(t *testing.T) {
+	var primaryDown atomic.Bool
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", &fakeEthService{blockNumber: 1}))
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rpcServer.ServeHTTP(w, r)
+	}))
+	defer primary.Close()
+	secondary := newFakeEthServer(t, 2)
+
+	client, err := eth.NewFailoverClient(
+		[]string{primary.URL, secondary.URL},
+		eth.FailoverOpts{MaxConsecutiveErrors: 1, RecoveryProbeInterval: 20 * time.Millisecond},
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	primaryDown.Store(true)
+	blockNumber, err := client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), blockNumber)
+	require.False(t, client.IsEndpointHealthy(0))
+
+	// the background probe brings the primary back once it recovers
+	primaryDown.Store(false)
+	require.Eventually(t, func() bool { return client.IsEndpointHealthy(0) }, time.Second, 10*time.Millisecond)
+	blockNumber, err = client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), blockNumber)
+}
+
+func TestFailoverClientAllEndpointsDown(t *testing.T) {
+	server := newFakeEthServer(t, 1)
+	client, err := eth.NewFailoverClient([]string{server.URL}, eth.FailoverOpts{})
+	require.NoError(t, err)
+	defer client.Close()
+
+	server.Close()
+	_, err = client.BlockNumber(context.Background())
+	require.Error(t, err)
+}
+
+func TestFailoverClientRequiresEndpoints(t *testing.T) {
+	_, err := eth.NewFailoverClient(nil, eth.FailoverOpts{})
+	require.Error(t, err)
+}
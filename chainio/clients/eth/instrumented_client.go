@@ -2,14 +2,19 @@ package eth
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	rpccalls "github.com/Layr-Labs/eigensdk-go/metrics/collectors/rpc_calls"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // InstrumentedClient is a wrapper around the geth ethclient that instruments
@@ -22,16 +27,27 @@ import (
 type InstrumentedClient struct {
 	client            *ethclient.Client
 	rpcCallsCollector *rpccalls.Collector
+
+	// static values of the endpoint, fetched once and cached until InvalidateStaticCache is called
+	staticCacheMu sync.Mutex
+	chainID       *big.Int
 	// we store both client and version because that's what the web3_clientVersion jsonrpc call returns
 	// https://ethereum.org/en/developers/docs/apis/json-rpc/#web3_clientversion
+	// empty when not cached
 	clientAndVersion string
 }
 
 var _ HttpBackend = (*InstrumentedClient)(nil)
 var _ WsBackend = (*InstrumentedClient)(nil)
+var _ Client = (*InstrumentedClient)(nil)
 
-func NewInstrumentedClient(rpcAddress string, rpcCallsCollector *rpccalls.Collector) (*InstrumentedClient, error) {
-	client, err := ethclient.Dial(rpcAddress)
+// NewInstrumentedClient dials rpcAddress with the given options (see NewClientWithOptions) and instruments the client
+func NewInstrumentedClient(
+	rpcAddress string,
+	rpcCallsCollector *rpccalls.Collector,
+	opts ...ClientOption,
+) (*InstrumentedClient, error) {
+	client, err := NewClientWithOptions(rpcAddress, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,12 +67,55 @@ func NewInstrumentedClientFromClient(
 	}
 }
 
+// InvalidateStaticCache clears the cached chain id and client version, so that they are fetched again on next use.
+// This is only needed if the endpoint behind the client can change networks (e.g. in failover setups).
+func (iec *InstrumentedClient) InvalidateStaticCache() {
+	iec.staticCacheMu.Lock()
+	defer iec.staticCacheMu.Unlock()
+	iec.chainID = nil
+	iec.clientAndVersion = ""
+}
+
+// ClientVersion returns the web3_clientVersion of the endpoint, which is fetched once and cached
+func (iec *InstrumentedClient) ClientVersion() string {
+	iec.staticCacheMu.Lock()
+	defer iec.staticCacheMu.Unlock()
+	if iec.clientAndVersion == "" {
+		iec.clientAndVersion = getClientAndVersion(iec.client)
+	}
+	return iec.clientAndVersion
+}
+
+// MustChainID returns the chain id like ChainID, but panics if it can't be fetched
+func (iec *InstrumentedClient) MustChainID(ctx context.Context) *big.Int {
+	chainID, err := iec.ChainID(ctx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to get chain id: %v", err))
+	}
+	return chainID
+}
+
 // gethClient interface methods
 
+// ChainID returns the chain id of the endpoint. It is only fetched on the first successful call, since it can't
+// change for a live connection.
 func (iec *InstrumentedClient) ChainID(ctx context.Context) (*big.Int, error) {
+	iec.staticCacheMu.Lock()
+	cachedChainID := iec.chainID
+	iec.staticCacheMu.Unlock()
+	if cachedChainID != nil {
+		return new(big.Int).Set(cachedChainID), nil
+	}
+
 	chainID := func() (*big.Int, error) { return iec.client.ChainID(ctx) }
 	id, err := instrumentFunction[*big.Int](chainID, "eth_chainId", iec)
-	return id, err
+	if err != nil {
+		return nil, err
+	}
+	iec.staticCacheMu.Lock()
+	iec.chainID = new(big.Int).Set(id)
+	iec.staticCacheMu.Unlock()
+	return id, nil
 }
 
 func (iec *InstrumentedClient) BalanceAt(
@@ -72,6 +131,32 @@ func (iec *InstrumentedClient) BalanceAt(
 	return balance, nil
 }
 
+// BlobBaseFee returns the base fee per blob gas of the next block (eth_blobBaseFee).
+// It is not exposed by the geth ethclient, so the raw rpc call is made.
+// BatchCall sends the batch in a single json-rpc request. It is recorded as a single "batch" request in the rpc
+// metrics, along with the batch size.
+func (iec *InstrumentedClient) BatchCall(ctx context.Context, batch []rpc.BatchElem) error {
+	batchCall := func() (int, error) { return 0, iec.client.Client().BatchCallContext(ctx, batch) }
+	_, err := instrumentFunction[int](batchCall, "batch", iec)
+	iec.rpcCallsCollector.ObserveRPCBatchSize(len(batch), iec.ClientVersion())
+	return err
+}
+
+func (iec *InstrumentedClient) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	blobBaseFee := func() (*big.Int, error) {
+		var fee hexutil.Big
+		if err := iec.client.Client().CallContext(ctx, &fee, "eth_blobBaseFee"); err != nil {
+			return nil, err
+		}
+		return (*big.Int)(&fee), nil
+	}
+	fee, err := instrumentFunction[*big.Int](blobBaseFee, "eth_blobBaseFee", iec)
+	if err != nil {
+		return nil, err
+	}
+	return fee, nil
+}
+
 func (iec *InstrumentedClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
 	blockByHash := func() (*types.Block, error) { return iec.client.BlockByHash(ctx, hash) }
 	block, err := instrumentFunction[*types.Block](blockByHash, "eth_getBlockByHash", iec)
@@ -103,6 +188,18 @@ func (iec *InstrumentedClient) BlockNumber(ctx context.Context) (uint64, error)
 	return number, nil
 }
 
+func (iec *InstrumentedClient) BlockReceipts(
+	ctx context.Context,
+	blockNrOrHash rpc.BlockNumberOrHash,
+) ([]*types.Receipt, error) {
+	blockReceipts := func() ([]*types.Receipt, error) { return iec.client.BlockReceipts(ctx, blockNrOrHash) }
+	receipts, err := instrumentFunction[[]*types.Receipt](blockReceipts, "eth_getBlockReceipts", iec)
+	if err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
 func (iec *InstrumentedClient) CallContract(
 	ctx context.Context,
 	call ethereum.CallMsg,
@@ -116,6 +213,23 @@ func (iec *InstrumentedClient) CallContract(
 	return bytes, nil
 }
 
+// CallContractWithOverrides executes an eth_call with the state of the given accounts overridden
+func (iec *InstrumentedClient) CallContractWithOverrides(
+	ctx context.Context,
+	msg ethereum.CallMsg,
+	blockNumber *big.Int,
+	overrides map[common.Address]OverrideAccount,
+) ([]byte, error) {
+	callContract := func() ([]byte, error) {
+		return gethclient.New(iec.client.Client()).CallContract(ctx, msg, blockNumber, &overrides)
+	}
+	bytes, err := instrumentFunction[[]byte](callContract, "eth_call", iec)
+	if err != nil {
+		return nil, err
+	}
+	return bytes, nil
+}
+
 func (iec *InstrumentedClient) CodeAt(
 	ctx context.Context,
 	contract common.Address,
@@ -372,7 +486,8 @@ func (iec *InstrumentedClient) TransactionByHash(
 	start := time.Now()
 	tx, isPending, err = iec.client.TransactionByHash(ctx, hash)
 	// we count both successful and erroring calls (even though this is not well defined in the spec)
-	iec.rpcCallsCollector.AddRPCRequestTotal("eth_getTransactionByHash", iec.clientAndVersion)
+	clientAndVersion := iec.ClientVersion()
+	iec.rpcCallsCollector.AddRPCRequestTotal("eth_getTransactionByHash", clientAndVersion)
 	if err != nil {
 		return nil, false, err
 	}
@@ -381,7 +496,7 @@ func (iec *InstrumentedClient) TransactionByHash(
 	iec.rpcCallsCollector.ObserveRPCRequestDurationSeconds(
 		float64(rpcRequestDuration),
 		"eth_getTransactionByHash",
-		iec.clientAndVersion,
+		clientAndVersion,
 	)
 
 	return tx, isPending, nil
@@ -451,7 +566,8 @@ func instrumentFunction[T any](
 	start := time.Now()
 	result, err := rpcCall()
 	// we count both successful and erroring calls (even though this is not well defined in the spec)
-	iec.rpcCallsCollector.AddRPCRequestTotal(rpcMethodName, iec.clientAndVersion)
+	clientAndVersion := iec.ClientVersion()
+	iec.rpcCallsCollector.AddRPCRequestTotal(rpcMethodName, clientAndVersion)
 	if err != nil {
 		return value, err
 	}
@@ -460,7 +576,7 @@ func instrumentFunction[T any](
 	iec.rpcCallsCollector.ObserveRPCRequestDurationSeconds(
 		float64(rpcRequestDuration),
 		rpcMethodName,
-		iec.clientAndVersion,
+		clientAndVersion,
 	)
 	return result, nil
 }
@@ -4,20 +4,28 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/mocks"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
 	rpccalls "github.com/Layr-Labs/eigensdk-go/metrics/collectors/rpc_calls"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
+	"go.uber.org/mock/gomock"
 )
 
 var (
@@ -62,6 +70,24 @@ func teardown() {
 	_ = anvil.Terminate(context.Background())
 }
 
+// assertRPCRequestRecorded checks that both the rpc request total and duration series of method were recorded
+func assertRPCRequestRecorded(t *testing.T, reg *prometheus.Registry, method string) {
+	metricFamilies, err := reg.Gather()
+	assert.NoError(t, err)
+	recorded := make(map[string]bool)
+	for _, metricFamily := range metricFamilies {
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "method" && label.GetValue() == method {
+					recorded[metricFamily.GetName()] = true
+				}
+			}
+		}
+	}
+	assert.True(t, recorded["eigen_rpc_request_total"], "rpc request total not recorded for %s", method)
+	assert.True(t, recorded["eigen_rpc_request_duration_seconds"], "rpc request duration not recorded for %s", method)
+}
+
 func TestNewInstrumentedClient(t *testing.T) {
 	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpcCallsCollector)
 	assert.NoError(t, err)
@@ -87,6 +113,17 @@ func TestBalanceAt(t *testing.T) {
 	assert.Equal(t, balance.Uint64(), uint64(0))
 }
 
+func TestBlobBaseFee(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpccalls.NewCollector("exampleAvs", reg))
+	assert.NoError(t, err)
+
+	fee, err := client.BlobBaseFee(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, fee)
+	assertRPCRequestRecorded(t, reg, "eth_blobBaseFee")
+}
+
 func TestBlockByHash(t *testing.T) {
 	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpcCallsCollector)
 	assert.NoError(t, err)
@@ -118,6 +155,17 @@ func TestBlockNumber(t *testing.T) {
 	assert.Equal(t, number, uint64(0))
 }
 
+func TestBlockReceipts(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpccalls.NewCollector("exampleAvs", reg))
+	assert.NoError(t, err)
+
+	receipts, err := client.BlockReceipts(context.Background(), rpc.BlockNumberOrHashWithNumber(0))
+	assert.NoError(t, err)
+	assert.Empty(t, receipts)
+	assertRPCRequestRecorded(t, reg, "eth_getBlockReceipts")
+}
+
 func TestCallContract(t *testing.T) {
 	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpcCallsCollector)
 	assert.NoError(t, err)
@@ -132,6 +180,23 @@ func TestCallContract(t *testing.T) {
 	assert.Equal(t, result, []byte{})
 }
 
+func TestCallContractWithOverrides(t *testing.T) {
+	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpcCallsCollector)
+	assert.NoError(t, err)
+
+	to := common.HexToAddress("0x1234")
+	// PUSH1 42 PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	code := common.FromHex("0x602a60005260206000f3")
+	result, err := client.CallContractWithOverrides(
+		context.Background(),
+		ethereum.CallMsg{To: &to},
+		nil,
+		map[common.Address]eth.OverrideAccount{to: {Code: code}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, common.LeftPadBytes([]byte{42}, 32), result)
+}
+
 func TestCodeAt(t *testing.T) {
 	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpcCallsCollector)
 	assert.NoError(t, err)
@@ -156,7 +221,8 @@ func TestEstimateGas(t *testing.T) {
 }
 
 func TestFeeHistory(t *testing.T) {
-	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpcCallsCollector)
+	reg := prometheus.NewRegistry()
+	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpccalls.NewCollector("exampleAvs", reg))
 	assert.NoError(t, err)
 
 	rewardsPercentiles := []float64{0.2}
@@ -166,6 +232,7 @@ func TestFeeHistory(t *testing.T) {
 	assert.Equal(t, feeHistory.Reward[0][0].Uint64(), uint64(0))
 	assert.Equal(t, feeHistory.BaseFee[0].Uint64(), uint64(0))
 	assert.Equal(t, feeHistory.GasUsedRatio, []float64{0})
+	assertRPCRequestRecorded(t, reg, "eth_feeHistory")
 }
 
 func TestFilterLogs(t *testing.T) {
@@ -280,12 +347,14 @@ func TestPendingStorageAt(t *testing.T) {
 }
 
 func TestPendingTransactionCount(t *testing.T) {
-	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpcCallsCollector)
+	reg := prometheus.NewRegistry()
+	client, err := eth.NewInstrumentedClient(anvilHttpEndpoint, rpccalls.NewCollector("exampleAvs", reg))
 	assert.NoError(t, err)
 
 	count, err := client.PendingTransactionCount(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, count, uint(0))
+	assertRPCRequestRecorded(t, reg, "eth_getBlockTransactionCountByNumber")
 }
 
 func TestStorageAt(t *testing.T) {
@@ -400,3 +469,58 @@ func TestTransactionMethods(t *testing.T) {
 		assert.Equal(t, tx.Hash(), signedTx.Hash())
 	})
 }
+
+// countingEthService serves eth_chainId and web3_clientVersion, counting the requests
+type countingEthService struct {
+	chainIdRequests       atomic.Int32
+	clientVersionRequests atomic.Int32
+}
+
+func (s *countingEthService) ChainId() *hexutil.Big {
+	s.chainIdRequests.Add(1)
+	return (*hexutil.Big)(big.NewInt(31337))
+}
+
+func (s *countingEthService) ClientVersion() string {
+	s.clientVersionRequests.Add(1)
+	return "fake/v1.0.0"
+}
+
+func TestStaticValuesAreCached(t *testing.T) {
+	service := &countingEthService{}
+	rpcServer := rpc.NewServer()
+	assert.NoError(t, rpcServer.RegisterName("eth", service))
+	assert.NoError(t, rpcServer.RegisterName("web3", service))
+	server := httptest.NewServer(rpcServer)
+	defer server.Close()
+
+	client, err := eth.NewInstrumentedClient(
+		server.URL,
+		rpccalls.NewCollector("exampleAvs", prometheus.NewRegistry()),
+	)
+	assert.NoError(t, err)
+
+	// construct the components fetching the chain id on startup
+	ctrl := gomock.NewController(t)
+	_, err = wallet.NewFireblocksWallet(mocks.NewMockFireblocksClient(ctrl), client, "vault", testutils.GetTestLogger())
+	assert.NoError(t, err)
+	privateKey, _, err := testutils.NewEcdsaSkAndAddress()
+	assert.NoError(t, err)
+	_, _, err = signerv2.SignerFromConfig(
+		signerv2.Config{PrivateKey: privateKey},
+		client.MustChainID(context.Background()),
+	)
+	assert.NoError(t, err)
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(31337), chainID)
+
+	assert.Equal(t, int32(1), service.chainIdRequests.Load())
+	assert.Equal(t, "fake/v1.0.0", client.ClientVersion())
+	assert.Equal(t, int32(1), service.clientVersionRequests.Load())
+
+	client.InvalidateStaticCache()
+	_ = client.MustChainID(context.Background())
+	assert.Equal(t, int32(2), service.chainIdRequests.Load())
+	assert.Equal(t, int32(2), service.clientVersionRequests.Load())
+}
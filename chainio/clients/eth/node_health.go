@@ -0,0 +1,142 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/services"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultMaxBlockAge is the age of the latest block after which CheckNodeHealth considers the node stale
+const DefaultMaxBlockAge = time.Minute
+
+type NodeStatus string
+
+const (
+	NodeStatusHealthy NodeStatus = "Healthy"
+	// NodeStatusSyncing means the node is still syncing the chain, so its state is not up to date
+	NodeStatusSyncing NodeStatus = "Syncing"
+	// NodeStatusStale means the latest block of the node is too old, it is probably not connected to its peers
+	NodeStatusStale NodeStatus = "Stale"
+	// NodeStatusWrongNetwork means the node is not on the expected chain
+	NodeStatusWrongNetwork NodeStatus = "WrongNetwork"
+)
+
+// NodeHealth is the health of an eth node as reported by CheckNodeHealth
+type NodeHealth struct {
+	Status  NodeStatus
+	ChainId *big.Int
+	// SyncProgress is nil when the node is not syncing
+	SyncProgress   *ethereum.SyncProgress
+	LatestBlockAge time.Duration
+	// ClientVersion is only set when the client exposes it (as the InstrumentedClient does)
+	ClientVersion string
+}
+
+// HealthCheckClient is the subset of the eth client needed to check the health of the node
+type HealthCheckClient interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// CheckNodeHealth checks that the node behind client is on the expected chain, synced, and has a recent latest block
+// (see DefaultMaxBlockAge). An error is only returned if the node couldn't be queried.
+func CheckNodeHealth(ctx context.Context, client HealthCheckClient, expectedChainId *big.Int) (NodeHealth, error) {
+	return CheckNodeHealthWithMaxBlockAge(ctx, client, expectedChainId, DefaultMaxBlockAge)
+}
+
+// CheckNodeHealthWithMaxBlockAge is CheckNodeHealth with a configurable age after which the node is considered stale
+func CheckNodeHealthWithMaxBlockAge(
+	ctx context.Context,
+	client HealthCheckClient,
+	expectedChainId *big.Int,
+	maxBlockAge time.Duration,
+) (NodeHealth, error) {
+	var health NodeHealth
+	if versionedClient, ok := client.(interface{ ClientVersion() string }); ok {
+		health.ClientVersion = versionedClient.ClientVersion()
+	}
+
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return health, fmt.Errorf("failed to get chain id: %w", err)
+	}
+	health.ChainId = chainId
+	syncProgress, err := client.SyncProgress(ctx)
+	if err != nil {
+		return health, fmt.Errorf("failed to get sync progress: %w", err)
+	}
+	health.SyncProgress = syncProgress
+	latestHeader, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return health, fmt.Errorf("failed to get latest block header: %w", err)
+	}
+	health.LatestBlockAge = time.Since(time.Unix(int64(latestHeader.Time), 0))
+
+	switch {
+	case expectedChainId != nil && chainId.Cmp(expectedChainId) != 0:
+		health.Status = NodeStatusWrongNetwork
+	case syncProgress != nil:
+		health.Status = NodeStatusSyncing
+	case health.LatestBlockAge > maxBlockAge:
+		health.Status = NodeStatusStale
+	default:
+		health.Status = NodeStatusHealthy
+	}
+	return health, nil
+}
+
+// NodeHealthReporter is a services.HealthReporter reporting the health of an eth node with
+// CheckNodeHealthWithMaxBlockAge: it is degraded while the node is syncing, and unhealthy when the node is stale, on
+// the wrong network or can't be reached
+type NodeHealthReporter struct {
+	name            string
+	client          HealthCheckClient
+	expectedChainId *big.Int
+	maxBlockAge     time.Duration
+}
+
+var _ services.HealthReporter = (*NodeHealthReporter)(nil)
+
+// NewNodeHealthReporter returns a NodeHealthReporter named name checking the node behind client
+func NewNodeHealthReporter(
+	name string,
+	client HealthCheckClient,
+	expectedChainId *big.Int,
+	maxBlockAge time.Duration,
+) *NodeHealthReporter {
+	return &NodeHealthReporter{
+		name:            name,
+		client:          client,
+		expectedChainId: expectedChainId,
+		maxBlockAge:     maxBlockAge,
+	}
+}
+
+func (r *NodeHealthReporter) Name() string {
+	return r.name
+}
+
+func (r *NodeHealthReporter) Health(ctx context.Context) (services.ServiceHealth, string) {
+	health, err := CheckNodeHealthWithMaxBlockAge(ctx, r.client, r.expectedChainId, r.maxBlockAge)
+	if err != nil {
+		return services.ServiceUnhealthy, err.Error()
+	}
+	switch health.Status {
+	case NodeStatusHealthy:
+		return services.ServiceHealthy, ""
+	case NodeStatusSyncing:
+		return services.ServiceDegraded, "eth node is syncing"
+	default:
+		return services.ServiceUnhealthy, fmt.Sprintf(
+			"eth node is %s, latest block age %s",
+			health.Status,
+			health.LatestBlockAge.Round(time.Second),
+		)
+	}
+}
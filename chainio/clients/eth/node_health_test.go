@@ -0,0 +1,88 @@
+package eth_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/services"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHealthCheckClient struct {
+	chainId         *big.Int
+	syncProgress    *ethereum.SyncProgress
+	latestBlockTime time.Time
+}
+
+func (c *fakeHealthCheckClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chainId, nil
+}
+
+func (c *fakeHealthCheckClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	return c.syncProgress, nil
+}
+
+func (c *fakeHealthCheckClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Time: uint64(c.latestBlockTime.Unix())}, nil
+}
+
+func (c *fakeHealthCheckClient) ClientVersion() string {
+	return "anvil/v0.2.0"
+}
+
+func TestCheckNodeHealth(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         *fakeHealthCheckClient
+		expectedStatus eth.NodeStatus
+		expectedHealth services.ServiceHealth
+	}{
+		{
+			name:           "healthy",
+			client:         &fakeHealthCheckClient{chainId: big.NewInt(1), latestBlockTime: time.Now()},
+			expectedStatus: eth.NodeStatusHealthy,
+			expectedHealth: services.ServiceHealthy,
+		},
+		{
+			name: "syncing",
+			client: &fakeHealthCheckClient{
+				chainId:         big.NewInt(1),
+				syncProgress:    &ethereum.SyncProgress{CurrentBlock: 10, HighestBlock: 100},
+				latestBlockTime: time.Now().Add(-time.Hour),
+			},
+			expectedStatus: eth.NodeStatusSyncing,
+			expectedHealth: services.ServiceDegraded,
+		},
+		{
+			name:           "stale",
+			client:         &fakeHealthCheckClient{chainId: big.NewInt(1), latestBlockTime: time.Now().Add(-time.Hour)},
+			expectedStatus: eth.NodeStatusStale,
+			expectedHealth: services.ServiceUnhealthy,
+		},
+		{
+			name:           "wrong network",
+			client:         &fakeHealthCheckClient{chainId: big.NewInt(17000), latestBlockTime: time.Now()},
+			expectedStatus: eth.NodeStatusWrongNetwork,
+			expectedHealth: services.ServiceUnhealthy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, err := eth.CheckNodeHealth(context.Background(), tt.client, big.NewInt(1))
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStatus, health.Status)
+			require.Equal(t, tt.client.chainId, health.ChainId)
+			require.Equal(t, tt.client.syncProgress, health.SyncProgress)
+			require.Equal(t, "anvil/v0.2.0", health.ClientVersion)
+
+			reporter := eth.NewNodeHealthReporter("eth", tt.client, big.NewInt(1), eth.DefaultMaxBlockAge)
+			reportedHealth, _ := reporter.Health(context.Background())
+			require.Equal(t, tt.expectedHealth, reportedHealth)
+		})
+	}
+}
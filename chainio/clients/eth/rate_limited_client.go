@@ -0,0 +1,178 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	rpccalls "github.com/Layr-Labs/eigensdk-go/metrics/collectors/rpc_calls"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/time/rate"
+)
+
+// cheapCallRateMultiplier is the default ratio between the limits of cheap calls and of all the other calls
+const cheapCallRateMultiplier = 4
+
+// RateLimitedClient wraps an HttpBackend, making every outgoing call wait on a client-side rate limiter so that a
+// provider's request budget is never exceeded.
+// Cheap calls (ChainID and BlockNumber) are counted against a separate, higher, limit.
+type RateLimitedClient struct {
+	inner        HttpBackend
+	limiter      *rate.Limiter
+	cheapLimiter *rate.Limiter
+	// rpcCallsCollector is nil when no metrics are exposed
+	rpcCallsCollector *rpccalls.Collector
+}
+
+var _ HttpBackend = (*RateLimitedClient)(nil)
+
+// NewRateLimitedClient returns a client allowing rps calls per second on average to inner, with bursts of up to
+// burst calls. Cheap calls are allowed 4 times that rate by default, see WithCheapCallLimit.
+func NewRateLimitedClient(inner HttpBackend, rps float64, burst int) *RateLimitedClient {
+	return &RateLimitedClient{
+		inner:        inner,
+		limiter:      rate.NewLimiter(rate.Limit(rps), burst),
+		cheapLimiter: rate.NewLimiter(rate.Limit(rps*cheapCallRateMultiplier), burst*cheapCallRateMultiplier),
+	}
+}
+
+// WithCheapCallLimit sets the limit applied to cheap calls (ChainID and BlockNumber)
+func (c *RateLimitedClient) WithCheapCallLimit(rps float64, burst int) *RateLimitedClient {
+	c.cheapLimiter = rate.NewLimiter(rate.Limit(rps), burst)
+	return c
+}
+
+// WithMetrics exposes the saturation of the limiters through the rpc calls collector
+func (c *RateLimitedClient) WithMetrics(rpcCallsCollector *rpccalls.Collector) *RateLimitedClient {
+	c.rpcCallsCollector = rpcCallsCollector
+	return c
+}
+
+// wait blocks until the limiter allows a call. It returns an error right away if the call can't be made before the
+// ctx deadline.
+func (c *RateLimitedClient) wait(ctx context.Context, limiter *rate.Limiter, limiterName string) error {
+	err := limiter.Wait(ctx)
+	if c.rpcCallsCollector != nil {
+		saturation := 1 - limiter.Tokens()/float64(limiter.Burst())
+		c.rpcCallsCollector.SetRateLimiterSaturation(min(max(saturation, 0), 1), limiterName)
+	}
+	return err
+}
+
+func rateLimitedCall[T any](ctx context.Context, c *RateLimitedClient, call func() (T, error)) (T, error) {
+	if err := c.wait(ctx, c.limiter, "default"); err != nil {
+		var zero T
+		return zero, err
+	}
+	return call()
+}
+
+func cheapRateLimitedCall[T any](ctx context.Context, c *RateLimitedClient, call func() (T, error)) (T, error) {
+	if err := c.wait(ctx, c.cheapLimiter, "cheap"); err != nil {
+		var zero T
+		return zero, err
+	}
+	return call()
+}
+
+// ChainID returns the chain id of the inner client, which must implement ChainID
+func (c *RateLimitedClient) ChainID(ctx context.Context) (*big.Int, error) {
+	chainIDer, ok := c.inner.(interface {
+		ChainID(ctx context.Context) (*big.Int, error)
+	})
+	if !ok {
+		return nil, errors.New("inner client does not implement ChainID")
+	}
+	return cheapRateLimitedCall(ctx, c, func() (*big.Int, error) {
+		return chainIDer.ChainID(ctx)
+	})
+}
+
+func (c *RateLimitedClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return cheapRateLimitedCall(ctx, c, func() (uint64, error) {
+		return c.inner.BlockNumber(ctx)
+	})
+}
+
+func (c *RateLimitedClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return rateLimitedCall(ctx, c, func() (*types.Block, error) {
+		return c.inner.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *RateLimitedClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return rateLimitedCall(ctx, c, func() (*types.Header, error) {
+		return c.inner.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *RateLimitedClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return rateLimitedCall(ctx, c, func() ([]byte, error) {
+		return c.inner.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (c *RateLimitedClient) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	return rateLimitedCall(ctx, c, func() ([]byte, error) {
+		return c.inner.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (c *RateLimitedClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return rateLimitedCall(ctx, c, func() ([]byte, error) {
+		return c.inner.PendingCodeAt(ctx, account)
+	})
+}
+
+func (c *RateLimitedClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return rateLimitedCall(ctx, c, func() (uint64, error) {
+		return c.inner.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *RateLimitedClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return rateLimitedCall(ctx, c, func() (*big.Int, error) {
+		return c.inner.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *RateLimitedClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return rateLimitedCall(ctx, c, func() (*big.Int, error) {
+		return c.inner.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *RateLimitedClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return rateLimitedCall(ctx, c, func() (uint64, error) {
+		return c.inner.EstimateGas(ctx, call)
+	})
+}
+
+func (c *RateLimitedClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := rateLimitedCall(ctx, c, func() (struct{}, error) {
+		return struct{}{}, c.inner.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+func (c *RateLimitedClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return rateLimitedCall(ctx, c, func() ([]types.Log, error) {
+		return c.inner.FilterLogs(ctx, query)
+	})
+}
+
+func (c *RateLimitedClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return rateLimitedCall(ctx, c, func() (ethereum.Subscription, error) {
+		return c.inner.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
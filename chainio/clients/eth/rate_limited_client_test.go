@@ -0,0 +1,96 @@
+package eth_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	rpccalls "github.com/Layr-Labs/eigensdk-go/metrics/collectors/rpc_calls"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend records the time of every contract call, and answers them with an abi encoded 1
+type countingBackend struct {
+	eth.HttpBackend
+	mu        sync.Mutex
+	callTimes []time.Time
+}
+
+func (b *countingBackend) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.callTimes = append(b.callTimes, time.Now())
+	return common.LeftPadBytes([]byte{1}, 32), nil
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	const (
+		rps      = 50
+		burst    = 2
+		numCalls = 12
+	)
+	backend := &countingBackend{}
+	reg := prometheus.NewRegistry()
+	client := eth.NewRateLimitedClient(backend, rps, burst).
+		WithMetrics(rpccalls.NewCollector("testavs", reg))
+
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinator(common.Address{0x1}, client)
+	require.NoError(t, err)
+	chainReader := avsregistry.NewChainReader(
+		common.Address{0x1},
+		common.Address{},
+		registryCoordinator,
+		nil,
+		nil,
+		testutils.GetTestLogger(),
+		client,
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numCalls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quorumCount, err := chainReader.GetQuorumCount(&bind.CallOpts{})
+			require.NoError(t, err)
+			require.Equal(t, uint8(1), quorumCount)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, backend.callTimes, numCalls)
+	// after the initial burst, calls can't be made faster than the configured rate
+	elapsed := backend.callTimes[numCalls-1].Sub(backend.callTimes[0])
+	minElapsed := time.Duration(float64(numCalls-burst) / rps * float64(time.Second))
+	require.GreaterOrEqual(t, elapsed, minElapsed-10*time.Millisecond)
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "eigen_rpc_rate_limiter_saturation"))
+}
+
+func TestRateLimitedClientReturnsOnContextDeadline(t *testing.T) {
+	client := eth.NewRateLimitedClient(&countingBackend{}, 0.1, 1)
+	_, err := client.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	require.NoError(t, err)
+
+	// the next token is only available in 10s, so the call must fail without waiting for it
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.CallContract(ctx, ethereum.CallMsg{}, nil)
+	require.Error(t, err)
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,250 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RetryConfig configures the retries of a RetryClient
+type RetryConfig struct {
+	// MaxRetries is the number of times a call is retried after its first attempt failed with a retryable error
+	MaxRetries int
+	// InitialBackoff is the base delay before the first retry. It is doubled after each retry, up to MaxBackoff.
+	// The actual delay is randomly jittered between half and the full backoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MethodMaxRetries overrides MaxRetries for the given methods, keyed by HttpBackend method name
+	// (e.g. "SendTransaction": 0 to never retry sending transactions)
+	MethodMaxRetries map[string]int
+}
+
+// DefaultRetryConfig returns a RetryConfig retrying 3 times with a backoff between 100ms and 2s.
+// Sending transactions is never retried, since the error might have happened after the tx was broadcast.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:       3,
+		InitialBackoff:   100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		MethodMaxRetries: map[string]int{"SendTransaction": 0},
+	}
+}
+
+// RetryClient wraps an HttpBackend, retrying the calls that fail with a transient error (see IsRetryableError)
+// with bounded exponential backoff and jitter
+type RetryClient struct {
+	inner HttpBackend
+	cfg   RetryConfig
+}
+
+var _ HttpBackend = (*RetryClient)(nil)
+
+func NewRetryClient(inner HttpBackend, cfg RetryConfig) *RetryClient {
+	return &RetryClient{
+		inner: inner,
+		cfg:   cfg,
+	}
+}
+
+// transientErrorMessages are (lowercased) substrings of errors returned by nodes and RPC providers
+// that are expected to go away when the request is retried
+var transientErrorMessages = []string{
+	"rate limit",
+	"too many requests",
+	"header not found",
+	"missing trie node",
+	"request timed out",
+	"connection reset",
+	"connection refused",
+}
+
+// IsRetryableError returns whether err is a transient error which might not happen again if the call is retried:
+// rate limiting, temporary network failures, or state not yet available on the node serving the request (which
+// happens on load-balanced providers right after a new block).
+// Reverts, ABI errors and context cancellation are never retryable, but per-call timeouts (ErrRPCTimeout) are.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrRPCTimeout) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// -32005 is the "limit exceeded" code of EIP-1474, used for rate limiting
+		if rpcErr.ErrorCode() == -32005 || rpcErr.ErrorCode() == http.StatusTooManyRequests {
+			return true
+		}
+		return hasTransientErrorMessage(rpcErr.Error())
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return hasTransientErrorMessage(err.Error())
+}
+
+func hasTransientErrorMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, transientMsg := range transientErrorMessages {
+		if strings.Contains(msg, transientMsg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *RetryClient) maxRetries(method string) int {
+	if maxRetries, ok := c.cfg.MethodMaxRetries[method]; ok {
+		return maxRetries
+	}
+	return c.cfg.MaxRetries
+}
+
+func (c *RetryClient) backoff(retry int) time.Duration {
+	backoff := c.cfg.InitialBackoff
+	for i := 0; i < retry && (c.cfg.MaxBackoff <= 0 || backoff < c.cfg.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if c.cfg.MaxBackoff > 0 && backoff > c.cfg.MaxBackoff {
+		backoff = c.cfg.MaxBackoff
+	}
+	if backoff <= 1 {
+		return backoff
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)))
+}
+
+func retryCall[T any](ctx context.Context, c *RetryClient, method string, call func() (T, error)) (T, error) {
+	maxRetries := c.maxRetries(method)
+	for retry := 0; ; retry++ {
+		result, err := call()
+		if err == nil || retry >= maxRetries || !IsRetryableError(err) {
+			return result, err
+		}
+		timer := time.NewTimer(c.backoff(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *RetryClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return retryCall(ctx, c, "BlockNumber", func() (uint64, error) {
+		return c.inner.BlockNumber(ctx)
+	})
+}
+
+func (c *RetryClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return retryCall(ctx, c, "BlockByNumber", func() (*types.Block, error) {
+		return c.inner.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *RetryClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return retryCall(ctx, c, "HeaderByNumber", func() (*types.Header, error) {
+		return c.inner.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *RetryClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return retryCall(ctx, c, "CodeAt", func() ([]byte, error) {
+		return c.inner.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (c *RetryClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return retryCall(ctx, c, "CallContract", func() ([]byte, error) {
+		return c.inner.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (c *RetryClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return retryCall(ctx, c, "PendingCodeAt", func() ([]byte, error) {
+		return c.inner.PendingCodeAt(ctx, account)
+	})
+}
+
+func (c *RetryClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return retryCall(ctx, c, "PendingNonceAt", func() (uint64, error) {
+		return c.inner.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *RetryClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return retryCall(ctx, c, "SuggestGasPrice", func() (*big.Int, error) {
+		return c.inner.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *RetryClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return retryCall(ctx, c, "SuggestGasTipCap", func() (*big.Int, error) {
+		return c.inner.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *RetryClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return retryCall(ctx, c, "EstimateGas", func() (uint64, error) {
+		return c.inner.EstimateGas(ctx, call)
+	})
+}
+
+func (c *RetryClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := retryCall(ctx, c, "SendTransaction", func() (struct{}, error) {
+		return struct{}{}, c.inner.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+func (c *RetryClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return retryCall(ctx, c, "FilterLogs", func() ([]types.Log, error) {
+		return c.inner.FilterLogs(ctx, query)
+	})
+}
+
+func (c *RetryClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return retryCall(ctx, c, "SubscribeFilterLogs", func() (ethereum.Subscription, error) {
+		return c.inner.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
@@ -0,0 +1,139 @@
+package eth_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRpcError struct {
+	code int
+	msg  string
+}
+
+func (e fakeRpcError) Error() string  { return e.msg }
+func (e fakeRpcError) ErrorCode() int { return e.code }
+
+// scriptedBackend returns the scripted errors in order, then succeeds
+type scriptedBackend struct {
+	eth.HttpBackend
+	errs  []error
+	calls int
+}
+
+func (b *scriptedBackend) next() error {
+	b.calls++
+	if b.calls <= len(b.errs) {
+		return b.errs[b.calls-1]
+	}
+	return nil
+}
+
+func (b *scriptedBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	if err := b.next(); err != nil {
+		return 0, err
+	}
+	return 42, nil
+}
+
+func (b *scriptedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.next()
+}
+
+func TestRetryClient(t *testing.T) {
+	cfg := eth.RetryConfig{
+		MaxRetries:       2,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		MethodMaxRetries: map[string]int{"SendTransaction": 0},
+	}
+	rateLimited := rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}
+	reverted := fakeRpcError{code: 3, msg: "execution reverted"}
+
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name:          "succeeds without retries",
+			expectedCalls: 1,
+		},
+		{
+			name:          "retries transient errors until success",
+			errs:          []error{rateLimited, fakeRpcError{code: -32000, msg: "header not found"}},
+			expectedCalls: 3,
+		},
+		{
+			name:          "returns the last error when retries are exhausted",
+			errs:          []error{rateLimited, syscall.ECONNRESET, fmt.Errorf("wrapped: %w", syscall.ECONNRESET)},
+			expectedCalls: 3,
+			expectedErr:   syscall.ECONNRESET,
+		},
+		{
+			name:          "does not retry reverts",
+			errs:          []error{reverted},
+			expectedCalls: 1,
+			expectedErr:   reverted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &scriptedBackend{errs: tt.errs}
+			client := eth.NewRetryClient(backend, cfg)
+
+			blockNumber, err := client.BlockNumber(context.Background())
+			require.Equal(t, tt.expectedCalls, backend.calls)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, uint64(42), blockNumber)
+		})
+	}
+
+	t.Run("method override disables retries", func(t *testing.T) {
+		backend := &scriptedBackend{errs: []error{rateLimited}}
+		client := eth.NewRetryClient(backend, cfg)
+
+		err := client.SendTransaction(context.Background(), nil)
+		require.Equal(t, rateLimited, err)
+		require.Equal(t, 1, backend.calls)
+	})
+
+	t.Run("stops retrying when the context is done", func(t *testing.T) {
+		backend := &scriptedBackend{errs: []error{rateLimited, rateLimited, rateLimited}}
+		client := eth.NewRetryClient(backend, eth.RetryConfig{MaxRetries: 2, InitialBackoff: time.Hour})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := client.BlockNumber(ctx)
+		require.Equal(t, rateLimited, err)
+		require.Equal(t, 1, backend.calls)
+	})
+}
+
+func TestIsRetryableError(t *testing.T) {
+	require.True(t, eth.IsRetryableError(rpc.HTTPError{StatusCode: http.StatusServiceUnavailable}))
+	require.True(t, eth.IsRetryableError(fakeRpcError{code: -32005, msg: "limit exceeded"}))
+	require.True(t, eth.IsRetryableError(fakeRpcError{code: -32000, msg: "missing trie node abcd"}))
+	require.True(t, eth.IsRetryableError(syscall.ECONNREFUSED))
+	require.True(t, eth.IsRetryableError(fmt.Errorf("%w: %w", eth.ErrRPCTimeout, context.DeadlineExceeded)))
+
+	require.False(t, eth.IsRetryableError(nil))
+	require.False(t, eth.IsRetryableError(rpc.HTTPError{StatusCode: http.StatusBadRequest}))
+	require.False(t, eth.IsRetryableError(fakeRpcError{code: 3, msg: "execution reverted"}))
+	require.False(t, eth.IsRetryableError(errors.New("abi: cannot unmarshal")))
+	require.False(t, eth.IsRetryableError(context.DeadlineExceeded))
+}
@@ -0,0 +1,175 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// RPCLogger is called once for every json-rpc call made by a client, with the decoded params, the raw json result
+// (nil on error), and the error returned by the node or the transport.
+// Only the calls made to HTTP endpoints are logged.
+type RPCLogger func(method string, params []interface{}, result interface{}, err error, duration time.Duration)
+
+// WithRPCLogger sets a hook called for every json-rpc call made by the client (see RPCLogger), e.g. to debug calls
+// failing for unclear reasons. Requests are not intercepted at all when no hook is set.
+func WithRPCLogger(rpcLogger RPCLogger) ClientOption {
+	return func(o *clientOptions) {
+		o.rpcLogger = rpcLogger
+	}
+}
+
+// redactedMethods are the methods whose params are not logged by the debug RPCLogger, since they contain signed
+// transactions
+var redactedMethods = map[string]bool{
+	"eth_sendRawTransaction": true,
+	"eth_sendTransaction":    true,
+	"eth_signTransaction":    true,
+}
+
+// NewDebugRPCLogger returns an RPCLogger logging every call at debug level, with its params truncated to
+// maxParamsBytes bytes of json (no limit if 0), and the params of the methods sending transactions redacted.
+func NewDebugRPCLogger(logger logging.Logger, maxParamsBytes int) RPCLogger {
+	return func(method string, params []interface{}, result interface{}, err error, duration time.Duration) {
+		var paramsStr string
+		if redactedMethods[method] {
+			paramsStr = "<redacted>"
+		} else {
+			paramsJson, marshalErr := json.Marshal(params)
+			if marshalErr != nil {
+				paramsStr = fmt.Sprintf("<unmarshallable params: %s>", marshalErr)
+			} else {
+				paramsStr = truncate(string(paramsJson), maxParamsBytes)
+			}
+		}
+		if err != nil {
+			logger.Debug("RPC call failed", "method", method, "params", paramsStr, "duration", duration, "err", err)
+			return
+		}
+		logger.Debug("RPC call", "method", method, "params", paramsStr, "duration", duration)
+	}
+}
+
+func truncate(s string, maxBytes int) string {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+	return s[:maxBytes] + "...(truncated)"
+}
+
+type loggedRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params []interface{}   `json:"params"`
+}
+
+type loggedResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *loggedRPCError `json:"error"`
+}
+
+// loggedRPCError is the error returned by the node for a call, passed to the RPCLogger
+type loggedRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *loggedRPCError) Error() string {
+	return e.Message
+}
+
+func (e *loggedRPCError) ErrorCode() int {
+	return e.Code
+}
+
+// rpcLoggingTransport decodes the json-rpc requests and responses going through it to call the rpcLogger
+type rpcLoggingTransport struct {
+	base      http.RoundTripper
+	rpcLogger RPCLogger
+}
+
+// decodeMessages decodes either a single json-rpc message or a batch of them
+func decodeMessages[T any](body []byte) ([]T, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var msgs []T
+		err := json.Unmarshal(body, &msgs)
+		return msgs, err
+	}
+	var msg T
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	return []T{msg}, nil
+}
+
+func (t *rpcLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.base.RoundTrip(req)
+	}
+	reqBody, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	calls, decodeErr := decodeMessages[loggedRequest](reqBody)
+	if decodeErr != nil {
+		// not a json-rpc request, nothing to log
+		return t.base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		t.logAll(calls, err, duration)
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		t.logAll(calls, err, duration)
+		return resp, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		t.logAll(calls, fmt.Errorf("%s: %s", resp.Status, respBody), duration)
+		return resp, nil
+	}
+	responses, err := decodeMessages[loggedResponse](respBody)
+	if err != nil {
+		t.logAll(calls, fmt.Errorf("invalid json-rpc response: %w", err), duration)
+		return resp, nil
+	}
+
+	responsesById := make(map[string]loggedResponse, len(responses))
+	for _, response := range responses {
+		responsesById[string(response.ID)] = response
+	}
+	for _, call := range calls {
+		response, ok := responsesById[string(call.ID)]
+		switch {
+		case !ok:
+			t.rpcLogger(call.Method, call.Params, nil, errors.New("no response received"), duration)
+		case response.Error != nil:
+			t.rpcLogger(call.Method, call.Params, nil, response.Error, duration)
+		default:
+			t.rpcLogger(call.Method, call.Params, response.Result, nil, duration)
+		}
+	}
+	return resp, nil
+}
+
+func (t *rpcLoggingTransport) logAll(calls []loggedRequest, err error, duration time.Duration) {
+	for _, call := range calls {
+		t.rpcLogger(call.Method, call.Params, nil, err, duration)
+	}
+}
@@ -0,0 +1,86 @@
+package eth_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSendTxService serves eth_blockNumber and accepts every raw transaction
+type fakeSendTxService struct{}
+
+func (s *fakeSendTxService) BlockNumber() hexutil.Uint64 {
+	return 1
+}
+
+func (s *fakeSendTxService) SendRawTransaction(data hexutil.Bytes) (common.Hash, error) {
+	return crypto.Keccak256Hash(data), nil
+}
+
+type loggedCall struct {
+	method string
+	params []interface{}
+	result interface{}
+	err    error
+}
+
+func TestWithRPCLogger(t *testing.T) {
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", &fakeSendTxService{}))
+	server := httptest.NewServer(rpcServer)
+	defer server.Close()
+
+	var calls []loggedCall
+	var logs bytes.Buffer
+	debugLogger := eth.NewDebugRPCLogger(
+		logging.NewTextSLogger(&logs, &logging.SLoggerOptions{Level: slog.LevelDebug, NoColor: true}),
+		64,
+	)
+	rpcLogger := func(method string, params []interface{}, result interface{}, err error, duration time.Duration) {
+		calls = append(calls, loggedCall{method: method, params: params, result: result, err: err})
+		debugLogger(method, params, result, err, duration)
+	}
+	client, err := eth.NewClientWithOptions(server.URL, eth.WithRPCLogger(rpcLogger))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = client.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	require.Equal(t, "eth_blockNumber", calls[0].method)
+	require.NoError(t, calls[0].err)
+	require.Equal(t, json.RawMessage(`"0x1"`), calls[0].result)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{
+		To:       &common.Address{0x1},
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+		Data:     bytes.Repeat([]byte{0xab}, 100),
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.SendTransaction(ctx, tx))
+	require.Len(t, calls, 2)
+	require.Equal(t, "eth_sendRawTransaction", calls[1].method)
+
+	rawTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	require.Contains(t, logs.String(), "eth_sendRawTransaction")
+	require.Contains(t, logs.String(), "<redacted>")
+	require.NotContains(t, logs.String(), hexutil.Encode(rawTx)[:20])
+}
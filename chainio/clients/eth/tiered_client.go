@@ -0,0 +1,165 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// headCacheTTL is how long the head block number of the primary is cached to make the routing decisions.
+// A stale head only sends a few more calls to the primary, which falls back to the archive on missing state anyway.
+const headCacheTTL = 2 * time.Second
+
+// missingStateErrorMessages are (lowercased) substrings of the errors returned by pruned nodes when queried for
+// state they don't have anymore
+var missingStateErrorMessages = []string{
+	"missing trie node",
+	"historical state",
+	"state not available",
+	"state is not available",
+}
+
+// IsMissingStateError returns whether err was returned by a node which pruned the state of the requested block
+func IsMissingStateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, missingStateMsg := range missingStateErrorMessages {
+		if strings.Contains(msg, missingStateMsg) {
+			return true
+		}
+	}
+	return false
+}
+
+// TieredClient routes the historical queries to an archive node, and everything else to a (pruned) primary node.
+// Calls for a block older than the primary's head minus archiveCutoffBlocks are sent to the archive, as well as the
+// calls for which the primary returned a missing state error (see IsMissingStateError).
+// Subscriptions and transactions always use the primary.
+type TieredClient struct {
+	primary             HttpBackend
+	archive             HttpBackend
+	archiveCutoffBlocks uint64
+
+	headMu        sync.Mutex
+	head          uint64
+	headFetchedAt time.Time
+}
+
+var _ HttpBackend = (*TieredClient)(nil)
+
+func NewTieredClient(primary, archive HttpBackend, archiveCutoffBlocks uint64) *TieredClient {
+	return &TieredClient{
+		primary:             primary,
+		archive:             archive,
+		archiveCutoffBlocks: archiveCutoffBlocks,
+	}
+}
+
+// isHistorical returns whether blockNumber is older than the primary's head minus the cutoff.
+// Errors getting the head are ignored, the call is then sent to the primary.
+func (c *TieredClient) isHistorical(ctx context.Context, blockNumber *big.Int) bool {
+	// nil and negative numbers are used for latest/pending/safe/finalized, which the primary always has
+	if blockNumber == nil || blockNumber.Sign() < 0 || !blockNumber.IsUint64() {
+		return false
+	}
+	c.headMu.Lock()
+	defer c.headMu.Unlock()
+	if time.Since(c.headFetchedAt) > headCacheTTL {
+		head, err := c.primary.BlockNumber(ctx)
+		if err != nil {
+			return false
+		}
+		c.head = head
+		c.headFetchedAt = time.Now()
+	}
+	return blockNumber.Uint64()+c.archiveCutoffBlocks < c.head
+}
+
+func tieredCall[T any](
+	ctx context.Context,
+	c *TieredClient,
+	blockNumber *big.Int,
+	call func(backend HttpBackend) (T, error),
+) (T, error) {
+	if c.isHistorical(ctx, blockNumber) {
+		return call(c.archive)
+	}
+	result, err := call(c.primary)
+	if IsMissingStateError(err) {
+		return call(c.archive)
+	}
+	return result, err
+}
+
+func (c *TieredClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.primary.BlockNumber(ctx)
+}
+
+func (c *TieredClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return tieredCall(ctx, c, number, func(backend HttpBackend) (*types.Block, error) {
+		return backend.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *TieredClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return tieredCall(ctx, c, number, func(backend HttpBackend) (*types.Header, error) {
+		return backend.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *TieredClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return tieredCall(ctx, c, blockNumber, func(backend HttpBackend) ([]byte, error) {
+		return backend.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (c *TieredClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return tieredCall(ctx, c, blockNumber, func(backend HttpBackend) ([]byte, error) {
+		return backend.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (c *TieredClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return c.primary.PendingCodeAt(ctx, account)
+}
+
+func (c *TieredClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.primary.PendingNonceAt(ctx, account)
+}
+
+func (c *TieredClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.primary.SuggestGasPrice(ctx)
+}
+
+func (c *TieredClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.primary.SuggestGasTipCap(ctx)
+}
+
+func (c *TieredClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return c.primary.EstimateGas(ctx, call)
+}
+
+func (c *TieredClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.primary.SendTransaction(ctx, tx)
+}
+
+// FilterLogs uses the primary, since pruned nodes keep the logs
+func (c *TieredClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return c.primary.FilterLogs(ctx, query)
+}
+
+func (c *TieredClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return c.primary.SubscribeFilterLogs(ctx, query, ch)
+}
@@ -0,0 +1,113 @@
+package eth_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// namedBackend answers contract calls with its name, failing them with err when it is set
+type namedBackend struct {
+	eth.HttpBackend
+	name  string
+	head  uint64
+	err   error
+	calls int
+}
+
+func (b *namedBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return b.head, nil
+}
+
+func (b *namedBackend) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	b.calls++
+	if b.err != nil {
+		return nil, b.err
+	}
+	return []byte(b.name), nil
+}
+
+func (b *namedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.calls++
+	return nil
+}
+
+func TestTieredClient(t *testing.T) {
+	tests := []struct {
+		name            string
+		blockNumber     *big.Int
+		primaryErr      error
+		expectedBackend string
+		expectedCalls   int
+	}{
+		{
+			name:            "latest block uses the primary",
+			expectedBackend: "primary",
+			expectedCalls:   1,
+		},
+		{
+			name:            "recent block uses the primary",
+			blockNumber:     big.NewInt(900),
+			expectedBackend: "primary",
+			expectedCalls:   1,
+		},
+		{
+			name:            "block older than the cutoff uses the archive",
+			blockNumber:     big.NewInt(899),
+			expectedBackend: "archive",
+			expectedCalls:   1,
+		},
+		{
+			name:            "missing state on the primary falls back to the archive",
+			blockNumber:     big.NewInt(950),
+			primaryErr:      errors.New("missing trie node 0xabcd (path ) state 0xabcd is not available"),
+			expectedBackend: "archive",
+			expectedCalls:   2,
+		},
+		{
+			name:            "other primary errors are returned",
+			blockNumber:     big.NewInt(950),
+			primaryErr:      errors.New("execution reverted"),
+			expectedBackend: "",
+			expectedCalls:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &namedBackend{name: "primary", head: 1000, err: tt.primaryErr}
+			archive := &namedBackend{name: "archive", head: 1000}
+			client := eth.NewTieredClient(primary, archive, 100)
+
+			result, err := client.CallContract(context.Background(), ethereum.CallMsg{}, tt.blockNumber)
+			require.Equal(t, tt.expectedCalls, primary.calls+archive.calls)
+			if tt.expectedBackend == "" {
+				require.ErrorIs(t, err, tt.primaryErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBackend, string(result))
+		})
+	}
+
+	t.Run("transactions always use the primary", func(t *testing.T) {
+		primary := &namedBackend{name: "primary"}
+		archive := &namedBackend{name: "archive"}
+		client := eth.NewTieredClient(primary, archive, 100)
+
+		tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+		require.NoError(t, client.SendTransaction(context.Background(), tx))
+		require.Equal(t, 1, primary.calls)
+		require.Equal(t, 0, archive.calls)
+	})
+}
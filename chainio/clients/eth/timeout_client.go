@@ -0,0 +1,143 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrRPCTimeout is returned (wrapped) by the TimeoutClient when a call exceeds its per-call timeout.
+// It is not returned when the caller's context itself expires.
+var ErrRPCTimeout = errors.New("rpc call timed out")
+
+type callTimeoutKey struct{}
+
+// WithCallTimeout returns a context overriding the per-call timeout of a TimeoutClient for the calls made with it,
+// e.g. for large FilterLogs queries. A timeout of 0 disables the per-call timeout.
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// TimeoutClient wraps an HttpBackend, bounding the duration of every call regardless of the caller's context,
+// so that a single hung call can't stall the caller
+type TimeoutClient struct {
+	inner          HttpBackend
+	perCallTimeout time.Duration
+}
+
+var _ HttpBackend = (*TimeoutClient)(nil)
+
+func NewTimeoutClient(inner HttpBackend, perCallTimeout time.Duration) *TimeoutClient {
+	return &TimeoutClient{
+		inner:          inner,
+		perCallTimeout: perCallTimeout,
+	}
+}
+
+func timeoutCall[T any](ctx context.Context, c *TimeoutClient, call func(ctx context.Context) (T, error)) (T, error) {
+	timeout := c.perCallTimeout
+	if override, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return call(ctx)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := call(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return result, fmt.Errorf("%w after %s: %w", ErrRPCTimeout, timeout, err)
+	}
+	return result, err
+}
+
+func (c *TimeoutClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (uint64, error) {
+		return c.inner.BlockNumber(ctx)
+	})
+}
+
+func (c *TimeoutClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (*types.Block, error) {
+		return c.inner.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *TimeoutClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (*types.Header, error) {
+		return c.inner.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *TimeoutClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) ([]byte, error) {
+		return c.inner.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (c *TimeoutClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) ([]byte, error) {
+		return c.inner.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (c *TimeoutClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) ([]byte, error) {
+		return c.inner.PendingCodeAt(ctx, account)
+	})
+}
+
+func (c *TimeoutClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (uint64, error) {
+		return c.inner.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *TimeoutClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (*big.Int, error) {
+		return c.inner.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *TimeoutClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (*big.Int, error) {
+		return c.inner.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *TimeoutClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (uint64, error) {
+		return c.inner.EstimateGas(ctx, call)
+	})
+}
+
+func (c *TimeoutClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := timeoutCall(ctx, c, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.inner.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+func (c *TimeoutClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) ([]types.Log, error) {
+		return c.inner.FilterLogs(ctx, query)
+	})
+}
+
+// SubscribeFilterLogs bounds the time taken to create the subscription, not its lifetime
+func (c *TimeoutClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return timeoutCall(ctx, c, func(ctx context.Context) (ethereum.Subscription, error) {
+		return c.inner.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
@@ -0,0 +1,48 @@
+package eth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/stretchr/testify/require"
+)
+
+// slowBackend answers BlockNumber after delay, unless the context is done first
+type slowBackend struct {
+	eth.HttpBackend
+	delay time.Duration
+}
+
+func (b *slowBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(b.delay):
+		return 1, nil
+	}
+}
+
+func TestTimeoutClient(t *testing.T) {
+	client := eth.NewTimeoutClient(&slowBackend{delay: 100 * time.Millisecond}, 10*time.Millisecond)
+
+	t.Run("calls exceeding the timeout fail with ErrRPCTimeout", func(t *testing.T) {
+		_, err := client.BlockNumber(context.Background())
+		require.ErrorIs(t, err, eth.ErrRPCTimeout)
+	})
+
+	t.Run("parent cancellation is not reported as a timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, err := client.BlockNumber(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotErrorIs(t, err, eth.ErrRPCTimeout)
+	})
+
+	t.Run("timeout can be overridden per call", func(t *testing.T) {
+		blockNumber, err := client.BlockNumber(eth.WithCallTimeout(context.Background(), time.Second))
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), blockNumber)
+	})
+}
@@ -0,0 +1,243 @@
+// Package tracing provides an OpenTelemetry traced eth.Client.
+// It is kept separate from the eth package so that users who don't trace their calls don't depend on OpenTelemetry.
+//
+// The TracedClient only creates spans; to also get the rpc metrics, wrap an eth.InstrumentedClient:
+//
+//	instrumentedClient, err := eth.NewInstrumentedClient(rpcAddress, rpcCallsCollector)
+//	...
+//	client := tracing.NewTracedClient(instrumentedClient, otel.Tracer("my-avs"))
+package tracing
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// MethodAttributeKey is the span attribute holding the json-rpc method called
+	MethodAttributeKey = attribute.Key("rpc.method")
+	// BlockNumberAttributeKey is the span attribute holding the block number argument of the call, when there is one.
+	// Unset when the call targets the latest block.
+	BlockNumberAttributeKey = attribute.Key("eth.block_number")
+)
+
+// TracedClient wraps an eth.Client, creating a span for every call made through it.
+// The spans are children of the span in the caller's context, and the caller's context (with the new span) is passed
+// to the inner client.
+type TracedClient struct {
+	inner  eth.Client
+	tracer trace.Tracer
+}
+
+var _ eth.Client = (*TracedClient)(nil)
+
+func NewTracedClient(inner eth.Client, tracer trace.Tracer) *TracedClient {
+	return &TracedClient{
+		inner:  inner,
+		tracer: tracer,
+	}
+}
+
+func blockNumberAttributes(blockNumber *big.Int) []attribute.KeyValue {
+	if blockNumber == nil {
+		return nil
+	}
+	if !blockNumber.IsInt64() {
+		// negative numbers are used for block tags, keep them as they would be sent to the node
+		return []attribute.KeyValue{BlockNumberAttributeKey.String(blockNumber.String())}
+	}
+	return []attribute.KeyValue{BlockNumberAttributeKey.Int64(blockNumber.Int64())}
+}
+
+func traceCall[T any](
+	ctx context.Context,
+	c *TracedClient,
+	rpcMethodName string,
+	attrs []attribute.KeyValue,
+	call func(ctx context.Context) (T, error),
+) (T, error) {
+	ctx, span := c.tracer.Start(
+		ctx,
+		rpcMethodName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, MethodAttributeKey.String(rpcMethodName))...),
+	)
+	defer span.End()
+	result, err := call(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+func (c *TracedClient) BatchCall(ctx context.Context, batch []rpc.BatchElem) error {
+	attrs := []attribute.KeyValue{attribute.Int("rpc.batch_size", len(batch))}
+	_, err := traceCall(ctx, c, "batch", attrs, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.inner.BatchCall(ctx, batch)
+	})
+	return err
+}
+
+func (c *TracedClient) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	return traceCall(ctx, c, "eth_blobBaseFee", nil, func(ctx context.Context) (*big.Int, error) {
+		return c.inner.BlobBaseFee(ctx)
+	})
+}
+
+func (c *TracedClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	attrs := blockNumberAttributes(number)
+	return traceCall(ctx, c, "eth_getBlockByNumber", attrs, func(ctx context.Context) (*types.Block, error) {
+		return c.inner.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *TracedClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return traceCall(ctx, c, "eth_blockNumber", nil, func(ctx context.Context) (uint64, error) {
+		return c.inner.BlockNumber(ctx)
+	})
+}
+
+func (c *TracedClient) BlockReceipts(
+	ctx context.Context,
+	blockNrOrHash rpc.BlockNumberOrHash,
+) ([]*types.Receipt, error) {
+	var attrs []attribute.KeyValue
+	if number, ok := blockNrOrHash.Number(); ok {
+		attrs = blockNumberAttributes(big.NewInt(number.Int64()))
+	}
+	return traceCall(ctx, c, "eth_getBlockReceipts", attrs, func(ctx context.Context) ([]*types.Receipt, error) {
+		return c.inner.BlockReceipts(ctx, blockNrOrHash)
+	})
+}
+
+func (c *TracedClient) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	attrs := blockNumberAttributes(blockNumber)
+	return traceCall(ctx, c, "eth_call", attrs, func(ctx context.Context) ([]byte, error) {
+		return c.inner.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (c *TracedClient) CallContractWithOverrides(
+	ctx context.Context,
+	msg ethereum.CallMsg,
+	blockNumber *big.Int,
+	overrides map[common.Address]eth.OverrideAccount,
+) ([]byte, error) {
+	attrs := blockNumberAttributes(blockNumber)
+	return traceCall(ctx, c, "eth_call", attrs, func(ctx context.Context) ([]byte, error) {
+		return c.inner.CallContractWithOverrides(ctx, msg, blockNumber, overrides)
+	})
+}
+
+func (c *TracedClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return traceCall(ctx, c, "eth_chainId", nil, func(ctx context.Context) (*big.Int, error) {
+		return c.inner.ChainID(ctx)
+	})
+}
+
+func (c *TracedClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	attrs := blockNumberAttributes(blockNumber)
+	return traceCall(ctx, c, "eth_getCode", attrs, func(ctx context.Context) ([]byte, error) {
+		return c.inner.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (c *TracedClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return traceCall(ctx, c, "eth_estimateGas", nil, func(ctx context.Context) (uint64, error) {
+		return c.inner.EstimateGas(ctx, call)
+	})
+}
+
+func (c *TracedClient) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	lastBlock *big.Int,
+	rewardPercentiles []float64,
+) (*ethereum.FeeHistory, error) {
+	attrs := blockNumberAttributes(lastBlock)
+	return traceCall(ctx, c, "eth_feeHistory", attrs, func(ctx context.Context) (*ethereum.FeeHistory, error) {
+		return c.inner.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+func (c *TracedClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return traceCall(ctx, c, "eth_getLogs", nil, func(ctx context.Context) ([]types.Log, error) {
+		return c.inner.FilterLogs(ctx, query)
+	})
+}
+
+func (c *TracedClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	attrs := blockNumberAttributes(number)
+	return traceCall(ctx, c, "eth_getBlockByNumber", attrs, func(ctx context.Context) (*types.Header, error) {
+		return c.inner.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *TracedClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return traceCall(ctx, c, "eth_getCode", nil, func(ctx context.Context) ([]byte, error) {
+		return c.inner.PendingCodeAt(ctx, account)
+	})
+}
+
+func (c *TracedClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return traceCall(ctx, c, "eth_getTransactionCount", nil, func(ctx context.Context) (uint64, error) {
+		return c.inner.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *TracedClient) PendingTransactionCount(ctx context.Context) (uint, error) {
+	return traceCall(ctx, c, "eth_getBlockTransactionCountByNumber", nil, func(ctx context.Context) (uint, error) {
+		return c.inner.PendingTransactionCount(ctx)
+	})
+}
+
+func (c *TracedClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := traceCall(ctx, c, "eth_sendRawTransaction", nil, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, c.inner.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+// SubscribeFilterLogs traces the creation of the subscription, not its lifetime
+func (c *TracedClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return traceCall(ctx, c, "eth_subscribe", nil, func(ctx context.Context) (ethereum.Subscription, error) {
+		return c.inner.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
+
+func (c *TracedClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return traceCall(ctx, c, "eth_gasPrice", nil, func(ctx context.Context) (*big.Int, error) {
+		return c.inner.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *TracedClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return traceCall(ctx, c, "eth_maxPriorityFeePerGas", nil, func(ctx context.Context) (*big.Int, error) {
+		return c.inner.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *TracedClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	attrs := []attribute.KeyValue{attribute.String("eth.tx_hash", txHash.Hex())}
+	return traceCall(ctx, c, "eth_getTransactionReceipt", attrs, func(ctx context.Context) (*types.Receipt, error) {
+		return c.inner.TransactionReceipt(ctx, txHash)
+	})
+}
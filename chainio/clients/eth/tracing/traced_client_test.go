@@ -0,0 +1,83 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth/tracing"
+	rpccalls "github.com/Layr-Labs/eigensdk-go/metrics/collectors/rpc_calls"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeEthService serves eth_blockNumber, and fails eth_getBlockByNumber
+type fakeEthService struct{}
+
+func (s *fakeEthService) BlockNumber() hexutil.Uint64 {
+	return 42
+}
+
+func (s *fakeEthService) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	return nil, errors.New("block unavailable")
+}
+
+func TestTracedInstrumentedClient(t *testing.T) {
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", &fakeEthService{}))
+	server := httptest.NewServer(rpcServer)
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	instrumentedClient, err := eth.NewInstrumentedClient(server.URL, rpccalls.NewCollector("exampleAvs", reg))
+	require.NoError(t, err)
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	client := tracing.NewTracedClient(instrumentedClient, tracer)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	blockNumber, err := client.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), blockNumber)
+	_, err = client.HeaderByNumber(ctx, big.NewInt(7))
+	require.Error(t, err)
+	parent.End()
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 3)
+	blockNumberSpan, headerSpan := spans[0], spans[1]
+	for _, span := range []sdktrace.ReadOnlySpan{blockNumberSpan, headerSpan} {
+		require.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	require.Equal(t, "eth_blockNumber", blockNumberSpan.Name())
+	require.Contains(t, blockNumberSpan.Attributes(), tracing.MethodAttributeKey.String("eth_blockNumber"))
+	require.Equal(t, codes.Unset, blockNumberSpan.Status().Code)
+
+	require.Equal(t, "eth_getBlockByNumber", headerSpan.Name())
+	require.Contains(t, headerSpan.Attributes(), tracing.BlockNumberAttributeKey.Int64(7))
+	require.Equal(t, codes.Error, headerSpan.Status().Code)
+	require.Contains(t, headerSpan.Status().Description, "block unavailable")
+	require.NotContains(t, attributeKeys(blockNumberSpan.Attributes()), tracing.BlockNumberAttributeKey)
+
+	// the instrumented client recorded both calls
+	require.Equal(t, 2, testutil.CollectAndCount(reg, "eigen_rpc_request_total"))
+}
+
+func attributeKeys(attrs []attribute.KeyValue) []attribute.Key {
+	var keys []attribute.Key
+	for _, attr := range attrs {
+		keys = append(keys, attr.Key)
+	}
+	return keys
+}
@@ -0,0 +1,299 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	defaultWsInitialBackoff = 500 * time.Millisecond
+	defaultWsMaxBackoff     = 30 * time.Second
+	wsDialTimeout           = 10 * time.Second
+)
+
+// WsReconnectOpts configures the redial backoff of a WsClientWithReconnect
+type WsReconnectOpts struct {
+	// InitialBackoff is the delay before the first redial attempt, doubled after each failed attempt.
+	// Defaults to 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff bounds the delay between redial attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+}
+
+// WsClientWithReconnect is a websocket eth client whose subscriptions survive connection drops: when the socket
+// drops, the client is redialed with backoff and all the subscriptions are resubscribed. Events emitted while the
+// connection was down are not replayed, consumers should listen to ReconnectingSubscription.Reconnected to
+// backfill them.
+type WsClientWithReconnect struct {
+	url        string
+	logger     logging.Logger
+	opts       WsReconnectOpts
+	clientOpts []ClientOption
+
+	mu     sync.RWMutex
+	client *ethclient.Client
+}
+
+var _ WsBackend = (*WsClientWithReconnect)(nil)
+
+// NewWsClientWithReconnect dials url with the given client options, which are reused when redialing.
+// Errors which can't be fixed by redialing (malformed url, authentication failure) are returned right away.
+func NewWsClientWithReconnect(
+	url string,
+	logger logging.Logger,
+	opts WsReconnectOpts,
+	clientOpts ...ClientOption,
+) (*WsClientWithReconnect, error) {
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaultWsInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultWsMaxBackoff
+	}
+	client, err := NewClientWithOptions(url, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &WsClientWithReconnect{
+		url:        url,
+		logger:     logger.With(logging.ComponentKey, "eth/WsClientWithReconnect"),
+		opts:       opts,
+		clientOpts: clientOpts,
+		client:     client,
+	}, nil
+}
+
+// Close closes the current connection. Subscriptions must be unsubscribed first, otherwise they will redial.
+func (c *WsClientWithReconnect) Close() {
+	c.current().Close()
+}
+
+func (c *WsClientWithReconnect) current() *ethclient.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// isUnrecoverableDialError returns whether redialing can't fix err
+func isUnrecoverableDialError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "no known transport") ||
+		strings.Contains(msg, "401") ||
+		strings.Contains(msg, "403")
+}
+
+// redial replaces the broken client with a new connection, unless another subscription already did.
+// It retries with backoff until it succeeds, an unrecoverable error happens or quit is closed.
+func (c *WsClientWithReconnect) redial(broken *ethclient.Client, quit <-chan struct{}) (*ethclient.Client, error) {
+	backoff := c.opts.InitialBackoff
+	for {
+		c.mu.Lock()
+		if c.client != broken {
+			client := c.client
+			c.mu.Unlock()
+			return client, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), wsDialTimeout)
+		client, err := dialWithOptions(ctx, c.url, c.clientOpts...)
+		cancel()
+		if err == nil {
+			broken.Close()
+			c.client = client
+			c.mu.Unlock()
+			c.logger.Info("Reconnected to the websocket endpoint")
+			return client, nil
+		}
+		c.mu.Unlock()
+		if isUnrecoverableDialError(err) {
+			return nil, err
+		}
+		c.logger.Warn("Failed to redial the websocket endpoint", "err", err, "retryIn", backoff)
+
+		select {
+		case <-quit:
+			return nil, errors.New("unsubscribed")
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, c.opts.MaxBackoff)
+	}
+}
+
+// ReconnectingSubscription is an ethereum.Subscription which is transparently resubscribed when the websocket
+// connection drops
+type ReconnectingSubscription struct {
+	errC         chan error
+	reconnectedC chan struct{}
+	quit         chan struct{}
+	unsubOnce    sync.Once
+}
+
+// Err returns a channel receiving the error which terminated the subscription, which only happens when
+// reconnecting fails with an unrecoverable error. It is closed on Unsubscribe.
+func (s *ReconnectingSubscription) Err() <-chan error {
+	return s.errC
+}
+
+// Reconnected returns a channel notified each time the subscription was re-established after a connection drop.
+// Notifications are dropped if the previous one was not consumed.
+func (s *ReconnectingSubscription) Reconnected() <-chan struct{} {
+	return s.reconnectedC
+}
+
+func (s *ReconnectingSubscription) Unsubscribe() {
+	s.unsubOnce.Do(func() {
+		close(s.quit)
+	})
+}
+
+// subscribeWithReconnect creates the subscription with subscribe on the current client and keeps it alive across
+// connection drops
+func (c *WsClientWithReconnect) subscribeWithReconnect(
+	ctx context.Context,
+	subscribe func(ctx context.Context, client *ethclient.Client) (ethereum.Subscription, error),
+) (*ReconnectingSubscription, error) {
+	client := c.current()
+	sub, err := subscribe(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ReconnectingSubscription{
+		errC:         make(chan error, 1),
+		reconnectedC: make(chan struct{}, 1),
+		quit:         make(chan struct{}),
+	}
+	go func() {
+		defer close(s.errC)
+		for {
+			select {
+			case <-s.quit:
+				sub.Unsubscribe()
+				return
+			case err := <-sub.Err():
+				c.logger.Warn("Websocket subscription dropped, resubscribing", "err", err)
+			}
+
+			for {
+				client, err = c.redial(client, s.quit)
+				if err != nil {
+					select {
+					case <-s.quit:
+					default:
+						s.errC <- err
+					}
+					return
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), wsDialTimeout)
+				sub, err = subscribe(ctx, client)
+				cancel()
+				if err == nil {
+					break
+				}
+				// the new connection might already be broken, redial it
+				c.logger.Warn("Failed to resubscribe", "err", err)
+			}
+			select {
+			case s.reconnectedC <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return s, nil
+}
+
+// SubscribeFilterLogs subscribes to the logs matching q. The returned subscription is a *ReconnectingSubscription.
+func (c *WsClientWithReconnect) SubscribeFilterLogs(
+	ctx context.Context,
+	q ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	subscribe := func(ctx context.Context, client *ethclient.Client) (ethereum.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, q, ch)
+	}
+	return c.subscribeWithReconnect(ctx, subscribe)
+}
+
+// SubscribeNewHead subscribes to the new heads. The returned subscription is a *ReconnectingSubscription.
+func (c *WsClientWithReconnect) SubscribeNewHead(
+	ctx context.Context,
+	ch chan<- *types.Header,
+) (ethereum.Subscription, error) {
+	subscribe := func(ctx context.Context, client *ethclient.Client) (ethereum.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	}
+	return c.subscribeWithReconnect(ctx, subscribe)
+}
+
+func (c *WsClientWithReconnect) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.current().ChainID(ctx)
+}
+
+func (c *WsClientWithReconnect) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.current().BlockNumber(ctx)
+}
+
+func (c *WsClientWithReconnect) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return c.current().BlockByNumber(ctx, number)
+}
+
+func (c *WsClientWithReconnect) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return c.current().HeaderByHash(ctx, hash)
+}
+
+func (c *WsClientWithReconnect) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.current().HeaderByNumber(ctx, number)
+}
+
+func (c *WsClientWithReconnect) CodeAt(
+	ctx context.Context,
+	contract common.Address,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	return c.current().CodeAt(ctx, contract, blockNumber)
+}
+
+func (c *WsClientWithReconnect) CallContract(
+	ctx context.Context,
+	call ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	return c.current().CallContract(ctx, call, blockNumber)
+}
+
+func (c *WsClientWithReconnect) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return c.current().PendingCodeAt(ctx, account)
+}
+
+func (c *WsClientWithReconnect) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.current().PendingNonceAt(ctx, account)
+}
+
+func (c *WsClientWithReconnect) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.current().SuggestGasPrice(ctx)
+}
+
+func (c *WsClientWithReconnect) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.current().SuggestGasTipCap(ctx)
+}
+
+func (c *WsClientWithReconnect) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return c.current().EstimateGas(ctx, call)
+}
+
+func (c *WsClientWithReconnect) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.current().SendTransaction(ctx, tx)
+}
+
+func (c *WsClientWithReconnect) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return c.current().FilterLogs(ctx, q)
+}
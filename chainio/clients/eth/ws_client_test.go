@@ -0,0 +1,106 @@
+package eth_test
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNewHeadsService publishes a new head every 10ms to eth_subscribe("newHeads") subscribers
+type fakeNewHeadsService struct{}
+
+func (s *fakeNewHeadsService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for i := int64(1); ; i++ {
+			select {
+			case <-sub.Err():
+				return
+			case <-ticker.C:
+				header := &types.Header{Number: big.NewInt(i), Difficulty: big.NewInt(0)}
+				if err := notifier.Notify(sub.ID, header); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// startFakeWsServer serves fakeNewHeadsService over websocket on addr, and returns a function stopping it
+// (which drops all the websocket connections)
+func startFakeWsServer(t *testing.T, addr string) (string, func()) {
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", &fakeNewHeadsService{}))
+	listener, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	httpServer := &http.Server{Handler: rpcServer.WebsocketHandler([]string{"*"})}
+	go func() { _ = httpServer.Serve(listener) }()
+	stop := func() {
+		rpcServer.Stop()
+		_ = httpServer.Close()
+	}
+	t.Cleanup(stop)
+	return listener.Addr().String(), stop
+}
+
+func TestWsClientWithReconnect(t *testing.T) {
+	addr, stopServer := startFakeWsServer(t, "127.0.0.1:0")
+	client, err := eth.NewWsClientWithReconnect(
+		"ws://"+addr,
+		testutils.GetTestLogger(),
+		eth.WsReconnectOpts{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	headersC := make(chan *types.Header, 100)
+	subscription, err := client.SubscribeNewHead(context.Background(), headersC)
+	require.NoError(t, err)
+	defer subscription.Unsubscribe()
+	sub := subscription.(*eth.ReconnectingSubscription)
+
+	receiveHeader := func() {
+		select {
+		case <-headersC:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a new head")
+		}
+	}
+	receiveHeader()
+
+	// restart the server mid-subscription
+	stopServer()
+	time.Sleep(50 * time.Millisecond)
+	startFakeWsServer(t, addr)
+
+	select {
+	case <-sub.Reconnected():
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the subscription to reconnect")
+	}
+	// drain the headers received before the restart
+	for len(headersC) > 0 {
+		<-headersC
+	}
+	receiveHeader()
+}
+
+func TestWsClientWithReconnectFailsFastOnBadUrl(t *testing.T) {
+	_, err := eth.NewWsClientWithReconnect("foo://localhost:1234", testutils.GetTestLogger(), eth.WsReconnectOpts{})
+	require.Error(t, err)
+}
@@ -7,10 +7,12 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
@@ -32,6 +34,8 @@ var AssetIDByChain = map[uint64]AssetID{
 	17000: AssetIDHolETH,    // holesky
 }
 
+// Client is a client of the Fireblocks API. The List methods return whole lists: they read all the pages of a paged
+// list, and an error reading any page fails the call rather than returning a truncated list.
 type Client interface {
 	// ContractCall makes a ContractCall request to the Fireblocks API.
 	// It signs and broadcasts a transaction and returns the transaction ID and status.
@@ -41,6 +45,10 @@ type Client interface {
 	// It signs and broadcasts a transaction and returns the transaction ID and status.
 	// ref: https://developers.fireblocks.com/reference/post_transactions
 	Transfer(ctx context.Context, body *TransactionRequest) (*TransactionResponse, error)
+	// RawSign makes a RawSign request to the Fireblocks API.
+	// It creates a RAW signing transaction, whose signed messages are returned by GetTransaction once it is completed.
+	// ref: https://developers.fireblocks.com/docs/raw-signing
+	RawSign(ctx context.Context, body *RawSigningRequest) (*TransactionResponse, error)
 	// CancelTransaction makes a CancelTransaction request to the Fireblocks API
 	// It cancels a transaction by its transaction ID.
 	// It returns true if the transaction was successfully canceled.
@@ -64,6 +72,10 @@ type Client interface {
 	// GetTransaction makes a GetTransaction request to the Fireblocks API
 	// It returns the transaction details for the given transaction ID.
 	GetTransaction(ctx context.Context, txID string) (*Transaction, error)
+	// GetTransactionByExternalTxID makes a GetTransactionByExternalTxID request to the Fireblocks API
+	// It returns the transaction details for the given external transaction ID (idempotency key).
+	// ref: https://developers.fireblocks.com/reference/get_transactions-external-tx-id-externaltxid
+	GetTransactionByExternalTxID(ctx context.Context, externalTxID string) (*Transaction, error)
 	// GetAssetAddresses makes a GetAssetAddresses request to the Fireblocks API
 	// It returns the addresses for the given asset ID and vault ID.
 	GetAssetAddresses(ctx context.Context, vaultID string, assetID AssetID) ([]AssetAddress, error)
@@ -78,6 +90,14 @@ type client struct {
 	logger     logging.Logger
 }
 
+// ErrExternalTxIDAlreadyExists is returned when creating a transaction with the external transaction ID of an existing
+// transaction
+var ErrExternalTxIDAlreadyExists = errors.New("external transaction ID already exists")
+
+// errorCodeExternalTxIDAlreadyExists is the code of the error response to the creation of a transaction whose external
+// transaction ID already exists
+const errorCodeExternalTxIDAlreadyExists = 1438
+
 type ErrorResponse struct {
 	Message string `json:"message"`
 	Code    int    `json:"code"`
@@ -206,6 +226,9 @@ func (f *client) makeRequest(ctx context.Context, method, path string, body inte
 		if err != nil {
 			return nil, fmt.Errorf("error parsing error response: %w", err)
 		}
+		if errResp.Code == errorCodeExternalTxIDAlreadyExists || strings.Contains(errResp.Message, "already exists") {
+			return nil, fmt.Errorf("%w: %s", ErrExternalTxIDAlreadyExists, errResp.Message)
+		}
 		return nil, fmt.Errorf(
 			"error response (%d) from Fireblocks with code %d: %s",
 			resp.StatusCode,
@@ -45,6 +45,8 @@ type Transaction struct {
 		BlockHeight string `json:"blockHeight"`
 		BlockHash   string `json:"blockHash"`
 	} `json:"blockInfo"`
+	// SignedMessages are the messages signed by a completed RAW signing transaction
+	SignedMessages []SignedMessage `json:"signedMessages"`
 }
 
 func (f *client) GetTransaction(ctx context.Context, txID string) (*Transaction, error) {
@@ -62,3 +64,19 @@ func (f *client) GetTransaction(ctx context.Context, txID string) (*Transaction,
 
 	return &tx, nil
 }
+
+func (f *client) GetTransactionByExternalTxID(ctx context.Context, externalTxID string) (*Transaction, error) {
+	f.logger.Debug("Fireblocks get transaction by external tx ID", "externalTxID", externalTxID)
+	path := fmt.Sprintf("/v1/transactions/external_tx_id/%s", externalTxID)
+	res, err := f.makeRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	var tx Transaction
+	err = json.NewDecoder(strings.NewReader(string(res))).Decode(&tx)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response body: %w", err)
+	}
+
+	return &tx, nil
+}
@@ -0,0 +1,82 @@
+package fireblocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// maxListPages bounds the number of pages read by the list calls, which fail if the list has more pages, e.g.
+// because the API keeps returning a next page cursor
+const maxListPages = 100
+
+// paging holds the cursors of a page of a paged list
+type paging struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// listPaged reads all the pages of the list at path, following the after cursor of each page until a page has none.
+// Each page is either an object holding its items under key along with its paging, or a plain JSON array holding the
+// whole list. Any page failing fails the whole call, so that a truncated list is never returned.
+func listPaged[T any](ctx context.Context, f *client, path string, key string) ([]T, error) {
+	var items []T
+	after := ""
+	for page := 0; page < maxListPages; page++ {
+		u, err := url.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing URL: %w", err)
+		}
+		if after != "" {
+			q := u.Query()
+			q.Set("after", after)
+			u.RawQuery = q.Encode()
+		}
+		res, err := f.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error making request for page %d: %w", page, err)
+		}
+		pageItems, p, err := decodePage[T](res, key)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing response body of page %d: %s: %w", page, string(res), err)
+		}
+		items = append(items, pageItems...)
+
+		if p.After == "" {
+			return items, nil
+		}
+		if p.After == after {
+			return nil, fmt.Errorf("page %d of %s has the same after cursor as the previous page", page, path)
+		}
+		after = p.After
+	}
+	return nil, fmt.Errorf("%s has more than %d pages", path, maxListPages)
+}
+
+// decodePage returns the items and paging of a page of a list, see listPaged
+func decodePage[T any](body []byte, key string) ([]T, paging, error) {
+	var items []T
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(body, &items)
+		return items, paging{}, err
+	}
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, paging{}, err
+	}
+	if rawItems, ok := response[key]; ok {
+		if err := json.Unmarshal(rawItems, &items); err != nil {
+			return nil, paging{}, err
+		}
+	}
+	var p paging
+	if rawPaging, ok := response["paging"]; ok {
+		if err := json.Unmarshal(rawPaging, &p); err != nil {
+			return nil, paging{}, err
+		}
+	}
+	return items, p, nil
+}
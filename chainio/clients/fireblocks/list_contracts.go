@@ -2,9 +2,6 @@ package fireblocks
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -21,16 +18,5 @@ type WhitelistedContract struct {
 }
 
 func (f *client) ListContracts(ctx context.Context) ([]WhitelistedContract, error) {
-	var contracts []WhitelistedContract
-	res, err := f.makeRequest(ctx, "GET", "/v1/contracts", nil)
-	if err != nil {
-		return contracts, fmt.Errorf("error making request: %w", err)
-	}
-	body := string(res)
-	err = json.NewDecoder(strings.NewReader(body)).Decode(&contracts)
-	if err != nil {
-		return contracts, fmt.Errorf("error parsing response body: %s: %w", body, err)
-	}
-
-	return contracts, nil
+	return listPaged[WhitelistedContract](ctx, f, "/v1/contracts", "contracts")
 }
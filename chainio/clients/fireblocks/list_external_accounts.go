@@ -2,9 +2,6 @@ package fireblocks
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -23,16 +20,5 @@ type WhitelistedAccount struct {
 }
 
 func (f *client) ListExternalWallets(ctx context.Context) ([]WhitelistedAccount, error) {
-	var accounts []WhitelistedAccount
-	res, err := f.makeRequest(ctx, "GET", "/v1/external_wallets", nil)
-	if err != nil {
-		return accounts, fmt.Errorf("error making request: %w", err)
-	}
-	body := string(res)
-	err = json.NewDecoder(strings.NewReader(body)).Decode(&accounts)
-	if err != nil {
-		return accounts, fmt.Errorf("error parsing response body: %s: %w", body, err)
-	}
-
-	return accounts, nil
+	return listPaged[WhitelistedAccount](ctx, f, "/v1/external_wallets", "wallets")
 }
@@ -0,0 +1,140 @@
+package fireblocks_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/fireblocks"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedListServer serves the paged lists of the Fireblocks API: the list at each path is served in the pages of
+// items, the page after cursor "pageN" being page N
+type pagedListServer struct {
+	t     *testing.T
+	key   map[string]string
+	pages [][]string
+	// failingPage is the page answered with an error if not 0
+	failingPage int
+	// endless makes every page point to a next page
+	endless  bool
+	requests int
+}
+
+func (s *pagedListServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	require.NotEmpty(s.t, r.Header.Get("Authorization"))
+	s.requests++
+	page := 0
+	if after := r.URL.Query().Get("after"); after != "" {
+		_, err := fmt.Sscanf(after, "page%d", &page)
+		require.NoError(s.t, err)
+	}
+	if s.failingPage != 0 && page == s.failingPage {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"message":"internal error","code":500}`)
+		return
+	}
+
+	items := make([]string, 0)
+	if page < len(s.pages) {
+		for _, id := range s.pages[page] {
+			items = append(items, fmt.Sprintf(`{"id":%q}`, id))
+		}
+	}
+	paging := "{}"
+	if page+1 < len(s.pages) || s.endless {
+		paging = fmt.Sprintf(`{"after":"page%d"}`, page+1)
+	}
+	fmt.Fprintf(w, `{%q:[%s],"paging":%s}`, s.key[r.URL.Path], strings.Join(items, ","), paging)
+}
+
+func newTestFireblocksClient(t *testing.T, server *httptest.Server) fireblocks.Client {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	secretKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	c, err := fireblocks.NewClient("apiKey", secretKey, server.URL, 5*time.Second, testutils.NewTestLogger())
+	require.NoError(t, err)
+	return c
+}
+
+func TestListPaged(t *testing.T) {
+	keys := map[string]string{
+		"/v1/vault/accounts_paged": "accounts",
+		"/v1/contracts":            "contracts",
+		"/v1/external_wallets":     "wallets",
+	}
+	pages := [][]string{{"1", "2"}, {"3", "4"}, {"5"}}
+	listIDs := func(c fireblocks.Client) (map[string][]string, map[string]error) {
+		ids := make(map[string][]string)
+		errs := make(map[string]error)
+		accounts, err := c.ListVaultAccounts(context.Background())
+		errs["accounts"] = err
+		for _, account := range accounts {
+			ids["accounts"] = append(ids["accounts"], account.ID)
+		}
+		contracts, err := c.ListContracts(context.Background())
+		errs["contracts"] = err
+		for _, contract := range contracts {
+			ids["contracts"] = append(ids["contracts"], contract.ID)
+		}
+		wallets, err := c.ListExternalWallets(context.Background())
+		errs["wallets"] = err
+		for _, wallet := range wallets {
+			ids["wallets"] = append(ids["wallets"], wallet.ID)
+		}
+		return ids, errs
+	}
+
+	t.Run("Reads all the pages", func(t *testing.T) {
+		s := &pagedListServer{t: t, key: keys, pages: pages}
+		server := httptest.NewServer(s)
+		defer server.Close()
+
+		ids, errs := listIDs(newTestFireblocksClient(t, server))
+		for _, key := range keys {
+			require.NoError(t, errs[key])
+			require.Equal(t, []string{"1", "2", "3", "4", "5"}, ids[key])
+		}
+		require.Equal(t, 3*len(pages), s.requests)
+	})
+
+	t.Run("A failing page fails the list", func(t *testing.T) {
+		server := httptest.NewServer(&pagedListServer{t: t, key: keys, pages: pages, failingPage: 2})
+		defer server.Close()
+
+		ids, errs := listIDs(newTestFireblocksClient(t, server))
+		for _, key := range keys {
+			require.ErrorContains(t, errs[key], "page 2")
+			require.Empty(t, ids[key])
+		}
+	})
+
+	t.Run("Endless lists fail", func(t *testing.T) {
+		server := httptest.NewServer(&pagedListServer{t: t, key: keys, pages: pages, endless: true})
+		defer server.Close()
+
+		_, err := newTestFireblocksClient(t, server).ListContracts(context.Background())
+		require.ErrorContains(t, err, "more than")
+	})
+
+	t.Run("Unpaged lists", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"id":"1"},{"id":"2"}]`)
+		}))
+		defer server.Close()
+
+		contracts, err := newTestFireblocksClient(t, server).ListContracts(context.Background())
+		require.NoError(t, err)
+		require.Len(t, contracts, 2)
+	})
+}
@@ -0,0 +1,97 @@
+package fireblocks
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RawMessage is a message signed by a RAW signing transaction
+type RawMessage struct {
+	// Content is the hex encoded (without 0x prefix) 32 bytes digest to sign
+	Content string `json:"content"`
+	// BIP44AddressIndex is the address index, in the BIP44 path of the asset of the vault account, of the signing key
+	BIP44AddressIndex int `json:"bip44addressIndex"`
+}
+
+type rawMessageData struct {
+	Messages []RawMessage `json:"messages"`
+}
+
+type rawExtraParams struct {
+	RawMessageData rawMessageData `json:"rawMessageData"`
+}
+
+// RawSigningRequest is the request of a RAW signing transaction, which signs messages without broadcasting anything
+// ref: https://developers.fireblocks.com/docs/raw-signing
+type RawSigningRequest struct {
+	Operation       TransactionOperation `json:"operation"`
+	AssetID         AssetID              `json:"assetId"`
+	Source          account              `json:"source"`
+	Note            string               `json:"note,omitempty"`
+	ExtraParameters rawExtraParams       `json:"extraParameters"`
+}
+
+// MessageSignature is the signature of a message signed by a RAW signing transaction
+type MessageSignature struct {
+	// FullSig is the hex encoded R || S
+	FullSig string `json:"fullSig"`
+	R       string `json:"r"`
+	S       string `json:"s"`
+	// V is the recovery id, 0 or 1
+	V int `json:"v"`
+}
+
+// SignedMessage is a message signed by a completed RAW signing transaction
+type SignedMessage struct {
+	Content        string           `json:"content"`
+	Algorithm      string           `json:"algorithm"`
+	DerivationPath []int            `json:"derivationPath"`
+	Signature      MessageSignature `json:"signature"`
+	PublicKey      string           `json:"publicKey"`
+}
+
+// NewRawSigningRequest returns the request signing digest with the key at bip44AddressIndex of the assetID addresses
+// of the vault account sourceAccountID
+func NewRawSigningRequest(
+	assetID AssetID,
+	sourceAccountID string,
+	bip44AddressIndex int,
+	digest [32]byte,
+	note string,
+) *RawSigningRequest {
+	return &RawSigningRequest{
+		Operation: Raw,
+		AssetID:   assetID,
+		Source: account{
+			Type: "VAULT_ACCOUNT",
+			ID:   sourceAccountID,
+		},
+		Note: note,
+		ExtraParameters: rawExtraParams{
+			RawMessageData: rawMessageData{
+				Messages: []RawMessage{{
+					Content:           hex.EncodeToString(digest[:]),
+					BIP44AddressIndex: bip44AddressIndex,
+				}},
+			},
+		},
+	}
+}
+
+func (f *client) RawSign(ctx context.Context, req *RawSigningRequest) (*TransactionResponse, error) {
+	f.logger.Debug("Fireblocks raw sign", "req", req)
+	res, err := f.makeRequest(ctx, "POST", "/v1/transactions", req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	var response TransactionResponse
+	err = json.NewDecoder(strings.NewReader(string(res))).Decode(&response)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response body: %w", err)
+	}
+
+	return &response, nil
+}
@@ -19,6 +19,13 @@ const (
 type account struct {
 	Type string `json:"type"`
 	ID   string `json:"id"`
+	// OneTimeAddress is the address of a ONE_TIME_ADDRESS destination, which has no ID
+	OneTimeAddress *OneTimeAddress `json:"oneTimeAddress,omitempty"`
+}
+
+// OneTimeAddress is a destination address which is not whitelisted in the workspace
+type OneTimeAddress struct {
+	Address string `json:"address"`
 }
 
 type extraParams struct {
@@ -55,6 +62,17 @@ type TransactionRequest struct {
 	FeeLevel FeeLevel `json:"feeLevel,omitempty"`
 }
 
+// WithOneTimeAddressDestination sets the destination of the request to address, which doesn't need to be whitelisted
+// if one-time addresses are enabled in the workspace
+// ref: https://developers.fireblocks.com/reference/transaction-sources-destinations
+func (r *TransactionRequest) WithOneTimeAddressDestination(address string) *TransactionRequest {
+	r.Destination = account{
+		Type:           "ONE_TIME_ADDRESS",
+		OneTimeAddress: &OneTimeAddress{Address: address},
+	}
+	return r
+}
+
 type TransactionResponse struct {
 	ID     string   `json:"id"`
 	Status TxStatus `json:"status"`
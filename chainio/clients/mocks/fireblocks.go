@@ -100,6 +100,21 @@ func (mr *MockFireblocksClientMockRecorder) GetTransaction(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockFireblocksClient)(nil).GetTransaction), arg0, arg1)
 }
 
+// GetTransactionByExternalTxID mocks base method.
+func (m *MockFireblocksClient) GetTransactionByExternalTxID(arg0 context.Context, arg1 string) (*fireblocks.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByExternalTxID", arg0, arg1)
+	ret0, _ := ret[0].(*fireblocks.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByExternalTxID indicates an expected call of GetTransactionByExternalTxID.
+func (mr *MockFireblocksClientMockRecorder) GetTransactionByExternalTxID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByExternalTxID", reflect.TypeOf((*MockFireblocksClient)(nil).GetTransactionByExternalTxID), arg0, arg1)
+}
+
 // ListContracts mocks base method.
 func (m *MockFireblocksClient) ListContracts(arg0 context.Context) ([]fireblocks.WhitelistedContract, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +160,21 @@ func (mr *MockFireblocksClientMockRecorder) ListVaultAccounts(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVaultAccounts", reflect.TypeOf((*MockFireblocksClient)(nil).ListVaultAccounts), arg0)
 }
 
+// RawSign mocks base method.
+func (m *MockFireblocksClient) RawSign(arg0 context.Context, arg1 *fireblocks.RawSigningRequest) (*fireblocks.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RawSign", arg0, arg1)
+	ret0, _ := ret[0].(*fireblocks.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RawSign indicates an expected call of RawSign.
+func (mr *MockFireblocksClientMockRecorder) RawSign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RawSign", reflect.TypeOf((*MockFireblocksClient)(nil).RawSign), arg0, arg1)
+}
+
 // Transfer mocks base method.
 func (m *MockFireblocksClient) Transfer(arg0 context.Context, arg1 *fireblocks.TransactionRequest) (*fireblocks.TransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,32 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DigestSigner is implemented by the wallets which can also sign 32 bytes digests with the key of their sender
+// address, e.g. the AVS registration digest of an operator, such as the fireblocks wallet
+type DigestSigner interface {
+	SenderAddress(ctx context.Context) (common.Address, error)
+	// SignDigest returns the 65 bytes [R || S || V] signature of digest by the sender address
+	SignDigest(ctx context.Context, digest [32]byte) ([]byte, error)
+}
+
+// NewDigestSignerFn returns a signerv2.DigestSignerFn signing the digests of the sender address of signer, e.g. to
+// register with avsregistry's RegisterOperatorWithSigner an operator whose key is held by Fireblocks
+func NewDigestSignerFn(signer DigestSigner) signerv2.DigestSignerFn {
+	return func(ctx context.Context, address common.Address, digest [32]byte) ([]byte, error) {
+		sender, err := signer.SenderAddress(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if address != sender {
+			return nil, bind.ErrNotAuthorized
+		}
+		return signer.SignDigest(ctx, digest)
+	}
+}
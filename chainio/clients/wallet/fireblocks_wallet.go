@@ -2,25 +2,31 @@ package wallet
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/fireblocks"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 )
 
 var _ Wallet = (*fireblocksWallet)(nil)
+var _ DigestSigner = (*fireblocksWallet)(nil)
 
 var (
 	// ErrNotYetBroadcasted indicates that the transaction has not been broadcasted yet.
@@ -31,8 +37,23 @@ var (
 	// yet.
 	ErrReceiptNotYetAvailable = errors.New("transaction receipt not yet available")
 	ErrTransactionFailed      = errors.New("transaction failed")
+	// ErrOneTimeAddressesDisabled indicates that ETH is transferred to an address which is not a whitelisted external
+	// wallet, which requires one-time addresses to be enabled with WithOneTimeAddresses and in the workspace
+	ErrOneTimeAddressesDisabled = errors.New("one-time addresses are disabled")
+	// ErrAssetIDChainMismatch indicates that the asset of the wallet is not the native asset of the connected chain
+	ErrAssetIDChainMismatch = errors.New("asset ID does not match the chain")
+
+	errNotWhitelisted = errors.New("not whitelisted")
 )
 
+// DefaultWhitelistCacheTTL is the time after which the cached whitelisted contracts and external wallets are listed
+// again unless set with WithWhitelistCacheTTL
+const DefaultWhitelistCacheTTL = 10 * time.Minute
+
+// DefaultRawSigningPollInterval is the interval at which the status of the RAW signing transactions of SignDigest is
+// polled unless set with WithRawSigningPollInterval
+const DefaultRawSigningPollInterval = 2 * time.Second
+
 type ethClient interface {
 	ChainID(ctx context.Context) (*big.Int, error)
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
@@ -44,54 +65,158 @@ type fireblocksWallet struct {
 	// mu protects access to nonceToTxID and txIDToNonce which can be
 	// accessed concurrently by SendTransaction and GetTransactionReceipt
 	mu sync.Mutex
+	// cacheMu protects access to the caches, which can be accessed by concurrent sends
+	cacheMu sync.Mutex
 
 	fireblocksClient fireblocks.Client
 	ethClient        ethClient
 	vaultAccountName string
 	logger           logging.Logger
 	chainID          *big.Int
+	// assetID is the asset of the chain the transactions are sent with
+	assetID fireblocks.AssetID
+	// oneTimeAddresses allows to transfer ETH to addresses which are not whitelisted external wallets
+	oneTimeAddresses bool
+	// rawSigningPollInterval is the interval at which the RAW signing transactions are polled
+	rawSigningPollInterval time.Duration
 
 	// nonceToTx keeps track of the transaction ID for each nonce
 	// this is used to retrieve the transaction hash for a given nonce
 	// when a replacement transaction is submitted.
 	nonceToTxID map[uint64]TxID
 	txIDToNonce map[TxID]uint64
+	// txIDToTxHash keeps track of the hash of the unsigned transaction of each transaction ID, so that a transaction
+	// sent again is not sent as its own replacement
+	txIDToTxHash map[TxID]common.Hash
+	// replacedBy maps the ID of each replaced transaction to the ID of its replacement, so that the receipt of a
+	// transaction can be looked up along its replacements
+	replacedBy map[TxID]TxID
 
 	// caches
-	account              *fireblocks.VaultAccount
-	whitelistedContracts map[common.Address]*fireblocks.WhitelistedContract
-	whitelistedAccounts  map[common.Address]*fireblocks.WhitelistedAccount
+	account                         *fireblocks.VaultAccount
+	senderAddress                   *common.Address
+	senderAddressIndex              int
+	whitelistCacheTTL               time.Duration
+	whitelistedContracts            map[common.Address]*fireblocks.WhitelistedContract
+	whitelistedContractsRefreshedAt time.Time
+	whitelistedAccounts             map[common.Address]*fireblocks.WhitelistedAccount
+	whitelistedAccountsRefreshedAt  time.Time
 }
 
+// FireblocksWalletOption configures the wallets created by NewFireblocksWallet
+type FireblocksWalletOption func(*fireblocksWallet)
+
+// WithWhitelistCacheTTL sets the time after which the cached whitelisted contracts and external wallets, which are
+// resolved to the destination IDs of the transactions, are listed again
+func WithWhitelistCacheTTL(ttl time.Duration) FireblocksWalletOption {
+	return func(w *fireblocksWallet) {
+		w.whitelistCacheTTL = ttl
+	}
+}
+
+// WithRawSigningPollInterval sets the interval at which SignDigest polls the status of its RAW signing transactions,
+// which complete once approved and signed by Fireblocks
+func WithRawSigningPollInterval(interval time.Duration) FireblocksWalletOption {
+	return func(w *fireblocksWallet) {
+		w.rawSigningPollInterval = interval
+	}
+}
+
+// WithOneTimeAddresses transfers the ETH sent to addresses which are not whitelisted external wallets to one-time
+// addresses, which must be enabled in the workspace. Otherwise, these transfers fail with ErrOneTimeAddressesDisabled.
+func WithOneTimeAddresses() FireblocksWalletOption {
+	return func(w *fireblocksWallet) {
+		w.oneTimeAddresses = true
+	}
+}
+
+// WithAssetID sets the asset the transactions are sent with, which is otherwise the asset of the chain in
+// fireblocks.AssetIDByChain. It is needed on the chains missing from AssetIDByChain.
+func WithAssetID(assetID fireblocks.AssetID) FireblocksWalletOption {
+	return func(w *fireblocksWallet) {
+		w.assetID = assetID
+	}
+}
+
+// NewFireblocksWallet returns a wallet sending the transactions of the vault account vaultAccountName. It returns
+// ErrAssetIDChainMismatch if the asset set with WithAssetID is the asset of another chain than the one of ethClient.
+// On the chains missing from fireblocks.AssetIDByChain, the wallet is created without an asset unless set with
+// WithAssetID, and its transactions and sender address requests fail with an unsupported chain error.
 func NewFireblocksWallet(
 	fireblocksClient fireblocks.Client,
 	ethClient ethClient,
 	vaultAccountName string,
 	logger logging.Logger,
+	opts ...FireblocksWalletOption,
 ) (Wallet, error) {
 	chainID, err := ethClient.ChainID(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("error getting chain ID: %w", err)
 	}
 	logger.Debug("Creating new Fireblocks wallet for chain", "chainID", chainID)
-	return &fireblocksWallet{
+	w := &fireblocksWallet{
 		fireblocksClient: fireblocksClient,
 		ethClient:        ethClient,
 		vaultAccountName: vaultAccountName,
 		logger:           logger,
 		chainID:          chainID,
 
-		nonceToTxID: make(map[uint64]TxID),
-		txIDToNonce: make(map[TxID]uint64),
+		rawSigningPollInterval: DefaultRawSigningPollInterval,
+
+		nonceToTxID:  make(map[uint64]TxID),
+		txIDToNonce:  make(map[TxID]uint64),
+		txIDToTxHash: make(map[TxID]common.Hash),
+		replacedBy:   make(map[TxID]TxID),
 
 		// caches
 		account:              nil,
+		whitelistCacheTTL:    DefaultWhitelistCacheTTL,
 		whitelistedContracts: make(map[common.Address]*fireblocks.WhitelistedContract),
 		whitelistedAccounts:  make(map[common.Address]*fireblocks.WhitelistedAccount),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.resolveAssetID(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// resolveAssetID sets the asset of the wallet to the asset of its chain unless set, and checks that it is not the
+// asset of another chain. The asset is left unset on the chains missing from fireblocks.AssetIDByChain, see
+// checkAssetID.
+func (t *fireblocksWallet) resolveAssetID() error {
+	chainAssetID, ok := fireblocks.AssetIDByChain[t.chainID.Uint64()]
+	if t.assetID == "" {
+		t.assetID = chainAssetID
+		return nil
+	}
+	if ok && t.assetID != chainAssetID {
+		return fmt.Errorf("%w: asset %s is set for chain %d whose asset is %s", ErrAssetIDChainMismatch, t.assetID,
+			t.chainID.Uint64(), chainAssetID)
+	}
+	for chainID, assetID := range fireblocks.AssetIDByChain {
+		if assetID == t.assetID && chainID != t.chainID.Uint64() {
+			return fmt.Errorf("%w: asset %s of chain %d is set for chain %d", ErrAssetIDChainMismatch, t.assetID,
+				chainID, t.chainID.Uint64())
+		}
+	}
+	return nil
+}
+
+// checkAssetID returns an error if the wallet has no asset, i.e. its chain is missing from fireblocks.AssetIDByChain
+// and no asset was set with WithAssetID
+func (t *fireblocksWallet) checkAssetID() error {
+	if t.assetID == "" {
+		return fmt.Errorf("unsupported chain %d: set its asset with WithAssetID", t.chainID.Uint64())
+	}
+	return nil
 }
 
 func (t *fireblocksWallet) getAccount(ctx context.Context) (*fireblocks.VaultAccount, error) {
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
 	if t.account == nil {
 		accounts, err := t.fireblocksClient.ListVaultAccounts(ctx)
 		if err != nil {
@@ -107,73 +232,88 @@ func (t *fireblocksWallet) getAccount(ctx context.Context) (*fireblocks.VaultAcc
 	return t.account, nil
 }
 
+// getWhitelistedAccount returns the whitelisted external wallet of address from the cache, which is refreshed when
+// it is older than the cache TTL or when address is missing from it, so that a newly whitelisted wallet is found on
+// its first lookup
 func (f *fireblocksWallet) getWhitelistedAccount(
 	ctx context.Context,
 	address common.Address,
 ) (*fireblocks.WhitelistedAccount, error) {
-	assetID, ok := fireblocks.AssetIDByChain[f.chainID.Uint64()]
-	if !ok {
-		return nil, fmt.Errorf("unsupported chain %d", f.chainID.Uint64())
-	}
-	whitelistedAccount, ok := f.whitelistedAccounts[address]
-	if !ok {
-		accounts, err := f.fireblocksClient.ListExternalWallets(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("error listing external wallets: %w", err)
+	f.cacheMu.Lock()
+	defer f.cacheMu.Unlock()
+	if time.Since(f.whitelistedAccountsRefreshedAt) < f.whitelistCacheTTL {
+		if whitelistedAccount, ok := f.whitelistedAccounts[address]; ok {
+			return whitelistedAccount, nil
 		}
-		for i, a := range accounts {
-			for _, asset := range a.Assets {
-				if asset.Address == address && asset.Status == "APPROVED" && asset.ID == assetID {
-					f.whitelistedAccounts[address] = &accounts[i]
-					whitelistedAccount = &accounts[i]
-					return whitelistedAccount, nil
-				}
+	}
+
+	accounts, err := f.fireblocksClient.ListExternalWallets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing external wallets: %w", err)
+	}
+	f.whitelistedAccounts = make(map[common.Address]*fireblocks.WhitelistedAccount)
+	for i, a := range accounts {
+		for _, asset := range a.Assets {
+			if asset.Status == "APPROVED" && asset.ID == f.assetID {
+				f.whitelistedAccounts[asset.Address] = &accounts[i]
 			}
 		}
 	}
+	f.whitelistedAccountsRefreshedAt = time.Now()
 
-	if whitelistedAccount == nil {
-		return nil, fmt.Errorf("account %s not found in whitelisted accounts", address.Hex())
+	whitelistedAccount, ok := f.whitelistedAccounts[address]
+	if !ok {
+		return nil, fmt.Errorf("account %s %w in the external wallets", address.Hex(), errNotWhitelisted)
 	}
 	return whitelistedAccount, nil
 }
 
+// getWhitelistedContract returns the whitelisted contract of address from the cache, which is refreshed like the
+// cache of the whitelisted external wallets
 func (t *fireblocksWallet) getWhitelistedContract(
 	ctx context.Context,
 	address common.Address,
 ) (*fireblocks.WhitelistedContract, error) {
-	assetID, ok := fireblocks.AssetIDByChain[t.chainID.Uint64()]
-	if !ok {
-		return nil, fmt.Errorf("unsupported chain %d", t.chainID.Uint64())
-	}
-	contract, ok := t.whitelistedContracts[address]
-	if !ok {
-		contracts, err := t.fireblocksClient.ListContracts(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("error listing contracts: %w", err)
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
+	if time.Since(t.whitelistedContractsRefreshedAt) < t.whitelistCacheTTL {
+		if contract, ok := t.whitelistedContracts[address]; ok {
+			return contract, nil
 		}
-		for i_c, c := range contracts {
-			for _, a := range c.Assets {
-				if a.Address == address && a.Status == "APPROVED" && a.ID == assetID {
-					t.whitelistedContracts[address] = &contracts[i_c]
-					contract = &contracts[i_c]
-					return contract, nil
-				}
+	}
+
+	contracts, err := t.fireblocksClient.ListContracts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing contracts: %w", err)
+	}
+	t.whitelistedContracts = make(map[common.Address]*fireblocks.WhitelistedContract)
+	for i_c, c := range contracts {
+		for _, a := range c.Assets {
+			if a.Status == "APPROVED" && a.ID == t.assetID {
+				t.whitelistedContracts[a.Address] = &contracts[i_c]
 			}
 		}
 	}
+	t.whitelistedContractsRefreshedAt = time.Now()
 
-	if contract == nil {
+	contract, ok := t.whitelistedContracts[address]
+	if !ok {
 		return nil, fmt.Errorf("contract %s not found in whitelisted contracts", address.Hex())
 	}
 	return contract, nil
 }
 
 func (t *fireblocksWallet) SendTransaction(ctx context.Context, tx *types.Transaction) (TxID, error) {
-	assetID, ok := fireblocks.AssetIDByChain[t.chainID.Uint64()]
-	if !ok {
-		return "", fmt.Errorf("unsupported chain %d", t.chainID.Uint64())
+	if tx.Type() == types.BlobTxType {
+		return "", errors.New("blob transactions are not supported by the fireblocks wallet")
+	}
+	if tx.To() == nil {
+		return "", errors.New("contract creations are not supported by the fireblocks wallet")
 	}
+	if err := t.checkAssetID(); err != nil {
+		return "", err
+	}
+	assetID := t.assetID
 	account, err := t.getAccount(ctx)
 	if err != nil {
 		return "", fmt.Errorf("error getting account: %w", err)
@@ -195,18 +335,24 @@ func (t *fireblocksWallet) SendTransaction(ctx context.Context, tx *types.Transa
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	// if the nonce is already in the map, it means that the transaction was already submitted
-	// we need to get the replacement transaction hash and use it as the replaceTxByHash parameter
+	// and that this transaction must replace it instead of being sent along with it
 	replaceTxByHash := ""
 	nonce := tx.Nonce()
-	if txID, ok := t.nonceToTxID[nonce]; ok {
-		fireblockTx, err := t.fireblocksClient.GetTransaction(ctx, txID)
+	prevTxID, isReplacement := t.nonceToTxID[nonce]
+	if isReplacement && t.txIDToTxHash[prevTxID] == tx.Hash() {
+		// the same transaction is sent again, e.g. retried concurrently
+		t.logger.Debug("Fireblocks transaction already sent", "txID", prevTxID)
+		return prevTxID, nil
+	}
+	replacedTxID := TxID("")
+	if isReplacement {
+		replacedTxID = prevTxID
+		replaceTxByHash, err = t.prepareReplacement(ctx, prevTxID)
 		if err != nil {
-			return "", fmt.Errorf("error getting fireblocks transaction %s: %w", txID, err)
-		}
-		if fireblockTx.TxHash != "" {
-			replaceTxByHash = fireblockTx.TxHash
+			return "", err
 		}
 	}
+	externalTxID := FireblocksExternalTxID(t.chainID, account.ID, nonce, *tx.To(), tx.Value(), tx.Data(), replacedTxID)
 
 	gasLimit := ""
 	if tx.Gas() > 0 {
@@ -219,7 +365,9 @@ func (t *fireblocksWallet) SendTransaction(ctx context.Context, tx *types.Transa
 	priorityFee := ""
 	gasPrice := ""
 	feeLevel := fireblocks.FeeLevel("")
-	if tx.GasFeeCap().Cmp(big.NewInt(0)) > 0 && tx.GasTipCap().Cmp(big.NewInt(0)) > 0 {
+	// the gas tip and fee caps of legacy txs are their gas price, so they must be priced with it
+	if tx.Type() != types.LegacyTxType &&
+		tx.GasFeeCap().Cmp(big.NewInt(0)) > 0 && tx.GasTipCap().Cmp(big.NewInt(0)) > 0 {
 		maxFee = weiToGwei(tx.GasFeeCap()).String()
 		priorityFee = weiToGwei(tx.GasTipCap()).String()
 	} else if tx.GasPrice().Cmp(big.NewInt(0)) > 0 {
@@ -228,55 +376,78 @@ func (t *fireblocksWallet) SendTransaction(ctx context.Context, tx *types.Transa
 		feeLevel = fireblocks.FeeLevelHigh
 	}
 
-	var res *fireblocks.TransactionResponse
+	// createTx creates the Fireblocks transaction of tx with the external transaction ID externalTxID
+	var createTx func(externalTxID string) (*fireblocks.TransactionResponse, error)
 	if len(tx.Data()) == 0 && tx.Value().Cmp(big.NewInt(0)) > 0 {
+		destinationID := ""
 		targetAccount, clientErr := t.getWhitelistedAccount(ctx, *tx.To())
-		if clientErr != nil {
+		if clientErr == nil {
+			destinationID = targetAccount.ID
+		} else if !errors.Is(clientErr, errNotWhitelisted) {
 			return "", fmt.Errorf("error getting whitelisted account %s: %w", tx.To().Hex(), clientErr)
+		} else if !t.oneTimeAddresses {
+			return "", fmt.Errorf("%w: %s is not a whitelisted external wallet", ErrOneTimeAddressesDisabled,
+				tx.To().Hex())
+		}
+		createTx = func(externalTxID string) (*fireblocks.TransactionResponse, error) {
+			req := fireblocks.NewTransferRequest(
+				externalTxID,
+				assetID,
+				account.ID,              // source account ID
+				destinationID,           // destination account ID
+				formatEther(tx.Value()), // amount in ETH
+				replaceTxByHash,         // replaceTxByHash
+				gasPrice,
+				gasLimit,
+				maxFee,
+				priorityFee,
+				feeLevel,
+			)
+			if destinationID == "" {
+				req.WithOneTimeAddressDestination(tx.To().Hex())
+			}
+			res, err := t.fireblocksClient.Transfer(ctx, req)
+			if err != nil && destinationID == "" && isOneTimeAddressError(err) {
+				err = fmt.Errorf("%w: %w", ErrOneTimeAddressesDisabled, err)
+			}
+			return res, err
 		}
-		req := fireblocks.NewTransferRequest(
-			"", // externalTxID
-			assetID,
-			account.ID,                      // source account ID
-			targetAccount.ID,                // destination account ID
-			weiToEther(tx.Value()).String(), // amount in ETH
-			replaceTxByHash,                 // replaceTxByHash
-			gasPrice,
-			gasLimit,
-			maxFee,
-			priorityFee,
-			feeLevel,
-		)
-		res, err = t.fireblocksClient.Transfer(ctx, req)
 	} else if len(tx.Data()) > 0 {
 		contract, clientErr := t.getWhitelistedContract(ctx, *tx.To())
 		if clientErr != nil {
 			return "", fmt.Errorf("error getting whitelisted contract %s: %w", tx.To().Hex(), clientErr)
 		}
-		req := fireblocks.NewContractCallRequest(
-			"", // externalTxID
-			assetID,
-			account.ID,                      // source account ID
-			contract.ID,                     // destination account ID
-			weiToEther(tx.Value()).String(), // amount
-			hexutil.Encode(tx.Data()),       // calldata
-			replaceTxByHash,                 // replaceTxByHash
-			gasPrice,
-			gasLimit,
-			maxFee,
-			priorityFee,
-			feeLevel,
-		)
-		res, err = t.fireblocksClient.ContractCall(ctx, req)
+		createTx = func(externalTxID string) (*fireblocks.TransactionResponse, error) {
+			req := fireblocks.NewContractCallRequest(
+				externalTxID,
+				assetID,
+				account.ID,                // source account ID
+				contract.ID,               // destination account ID
+				formatEther(tx.Value()),   // amount in ETH
+				hexutil.Encode(tx.Data()), // calldata
+				replaceTxByHash,           // replaceTxByHash
+				gasPrice,
+				gasLimit,
+				maxFee,
+				priorityFee,
+				feeLevel,
+			)
+			return t.fireblocksClient.ContractCall(ctx, req)
+		}
 	} else {
 		return "", errors.New("transaction has no value and no data")
 	}
 
+	res, err := t.createTransaction(ctx, externalTxID, createTx)
 	if err != nil {
 		return "", fmt.Errorf("error sending a transaction %s: %w", tx.To().Hex(), err)
 	}
 	t.nonceToTxID[nonce] = res.ID
 	t.txIDToNonce[res.ID] = nonce
+	t.txIDToTxHash[res.ID] = tx.Hash()
+	if isReplacement {
+		t.replacedBy[prevTxID] = res.ID
+	}
 	t.logger.Debug("Fireblocks contract call complete", "txID", res.ID, "status", res.Status)
 
 	return res.ID, nil
@@ -286,7 +457,121 @@ func (t *fireblocksWallet) CancelTransactionBroadcast(ctx context.Context, txID
 	return t.fireblocksClient.CancelTransaction(ctx, string(txID))
 }
 
+// FireblocksExternalTxID returns the external transaction ID of the Fireblocks transaction sending value and calldata
+// to the address to with nonce from the vault account vaultAccountID on chainID, replacing the transaction
+// replacedTxID if not empty. Fireblocks rejects the creation of a transaction whose external transaction ID already
+// exists, so that a send that is retried doesn't create a duplicate transaction.
+func FireblocksExternalTxID(
+	chainID *big.Int,
+	vaultAccountID string,
+	nonce uint64,
+	to common.Address,
+	value *big.Int,
+	calldata []byte,
+	replacedTxID TxID,
+) string {
+	calldataHash := crypto.Keccak256Hash(calldata)
+	key := fmt.Sprintf("%s:%s:%d:%s:%s:%s:%s", chainID, vaultAccountID, nonce, to.Hex(), value, calldataHash.Hex(),
+		replacedTxID)
+	return crypto.Keccak256Hash([]byte(key)).Hex()
+}
+
+// createTransaction creates a Fireblocks transaction with createTx and the external transaction ID externalTxID. The
+// transaction which already exists with this ID, e.g. created by a send whose response was lost, is returned instead
+// unless it failed, in which case the transaction is created again with an external ID derived from the ID of the
+// failed transaction, so that the retries of the send, even after a restart, find the same transactions.
+func (t *fireblocksWallet) createTransaction(
+	ctx context.Context,
+	externalTxID string,
+	createTx func(externalTxID string) (*fireblocks.TransactionResponse, error),
+) (*fireblocks.TransactionResponse, error) {
+	for {
+		res, err := createTx(externalTxID)
+		if !errors.Is(err, fireblocks.ErrExternalTxIDAlreadyExists) {
+			return res, err
+		}
+		fireblockTx, err := t.fireblocksClient.GetTransactionByExternalTxID(ctx, externalTxID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting fireblocks transaction with external tx ID %s: %w", externalTxID,
+				err)
+		}
+		switch fireblockTx.Status {
+		case fireblocks.Failed, fireblocks.Rejected, fireblocks.Cancelled, fireblocks.Blocked:
+			t.logger.Debug("Fireblocks transaction with the external tx ID failed, creating it again",
+				"txID", fireblockTx.ID, "status", fireblockTx.Status, "externalTxID", externalTxID)
+			externalTxID = crypto.Keccak256Hash([]byte(externalTxID + ":" + fireblockTx.ID)).Hex()
+			continue
+		}
+		t.logger.Debug("Fireblocks transaction already exists", "txID", fireblockTx.ID, "externalTxID", externalTxID)
+		return &fireblocks.TransactionResponse{ID: fireblockTx.ID, Status: fireblockTx.Status}, nil
+	}
+}
+
+// prepareReplacement returns the hash to replace by RBF (replaceTxByHash) the transaction prevTxID sent with the same
+// nonce. A transaction not broadcast yet has no hash and is cancelled in Fireblocks instead, so that it is recreated
+// with the new fees rather than being broadcast along with its replacement.
+func (t *fireblocksWallet) prepareReplacement(ctx context.Context, prevTxID TxID) (string, error) {
+	prevTx, err := t.fireblocksClient.GetTransaction(ctx, prevTxID)
+	if err != nil {
+		return "", fmt.Errorf("error getting fireblocks transaction %s: %w", prevTxID, err)
+	}
+	switch prevTx.Status {
+	case fireblocks.Failed, fireblocks.Rejected, fireblocks.Cancelled, fireblocks.Blocked:
+		// the transaction is not pending anymore, so there is nothing to replace
+		return "", nil
+	}
+	if prevTx.TxHash != "" {
+		return prevTx.TxHash, nil
+	}
+
+	t.logger.Debug("Cancelling Fireblocks transaction not broadcasted yet to recreate it", "txID", prevTxID)
+	cancelled, err := t.fireblocksClient.CancelTransaction(ctx, prevTxID)
+	if err != nil {
+		return "", fmt.Errorf("error cancelling fireblocks transaction %s: %w", prevTxID, err)
+	}
+	if !cancelled {
+		return "", fmt.Errorf("fireblocks transaction %s could not be cancelled to be replaced", prevTxID)
+	}
+	return "", nil
+}
+
+// GetTransactionReceipt returns the receipt of the transaction txID or of any of its replacements, whichever was
+// mined. Otherwise, the error of the latest replacement is returned.
 func (t *fireblocksWallet) GetTransactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error) {
+	t.mu.Lock()
+	replacements := []TxID{txID}
+	for next, ok := t.replacedBy[txID]; ok; next, ok = t.replacedBy[next] {
+		replacements = append(replacements, next)
+	}
+	t.mu.Unlock()
+
+	var err error
+	for _, id := range replacements {
+		var receipt *types.Receipt
+		receipt, err = t.transactionReceipt(ctx, id)
+		if err == nil {
+			t.forgetTransactions(replacements)
+			return receipt, nil
+		}
+	}
+	return nil, err
+}
+
+// forgetTransactions stops tracking the transactions txIDs sent with the same nonce once one of them was mined
+func (t *fireblocksWallet) forgetTransactions(txIDs []TxID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, txID := range txIDs {
+		if nonce, ok := t.txIDToNonce[txID]; ok {
+			delete(t.nonceToTxID, nonce)
+			delete(t.txIDToNonce, txID)
+		}
+		delete(t.txIDToTxHash, txID)
+		delete(t.replacedBy, txID)
+	}
+}
+
+func (t *fireblocksWallet) transactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error) {
 	fireblockTx, err := t.fireblocksClient.GetTransaction(ctx, txID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting fireblocks transaction %s: %w", txID, err)
@@ -295,13 +580,6 @@ func (t *fireblocksWallet) GetTransactionReceipt(ctx context.Context, txID TxID)
 		txHash := common.HexToHash(fireblockTx.TxHash)
 		receipt, err := t.ethClient.TransactionReceipt(ctx, txHash)
 		if err == nil {
-			t.mu.Lock()
-			defer t.mu.Unlock()
-			if nonce, ok := t.txIDToNonce[txID]; ok {
-				delete(t.nonceToTxID, nonce)
-				delete(t.txIDToNonce, txID)
-			}
-
 			return receipt, nil
 		}
 		if errors.Is(err, ethereum.NotFound) {
@@ -333,7 +611,18 @@ func (t *fireblocksWallet) GetTransactionReceipt(ctx context.Context, txID TxID)
 	)
 }
 
+// SenderAddress returns the address of the vault account for the asset of the chain, which is fetched on the first call
 func (f *fireblocksWallet) SenderAddress(ctx context.Context) (common.Address, error) {
+	f.cacheMu.Lock()
+	senderAddress := f.senderAddress
+	f.cacheMu.Unlock()
+	if senderAddress != nil {
+		return *senderAddress, nil
+	}
+	if err := f.checkAssetID(); err != nil {
+		return common.Address{}, err
+	}
+
 	account, err := f.getAccount(ctx)
 	if err != nil {
 		return common.Address{}, fmt.Errorf("error getting account: %w", err)
@@ -341,7 +630,7 @@ func (f *fireblocksWallet) SenderAddress(ctx context.Context) (common.Address, e
 	addresses, err := f.fireblocksClient.GetAssetAddresses(
 		ctx,
 		account.ID,
-		fireblocks.AssetIDByChain[f.chainID.Uint64()],
+		f.assetID,
 	)
 	if err != nil {
 		return common.Address{}, fmt.Errorf("error getting asset addresses: %w", err)
@@ -349,13 +638,138 @@ func (f *fireblocksWallet) SenderAddress(ctx context.Context) (common.Address, e
 	if len(addresses) == 0 {
 		return common.Address{}, errors.New("no addresses found")
 	}
-	return common.HexToAddress(addresses[0].Address), nil
+	address := common.HexToAddress(addresses[0].Address)
+	f.cacheMu.Lock()
+	f.senderAddress = &address
+	f.senderAddressIndex = addresses[0].BIP44AddressIndex
+	f.cacheMu.Unlock()
+	return address, nil
+}
+
+// SignDigest signs digest with the key of the sender address of the vault account, with a Fireblocks RAW signing
+// transaction which is polled until it completes, i.e. once approved by the policies of the workspace. The returned
+// signature is a 65 bytes [R || S || V] Ethereum signature, with a low S and V being 27 or 28.
+func (t *fireblocksWallet) SignDigest(ctx context.Context, digest [32]byte) ([]byte, error) {
+	account, err := t.getAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting account: %w", err)
+	}
+	sender, err := t.SenderAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.cacheMu.Lock()
+	senderAddressIndex := t.senderAddressIndex
+	t.cacheMu.Unlock()
+
+	req := fireblocks.NewRawSigningRequest(t.assetID, account.ID, senderAddressIndex, digest,
+		fmt.Sprintf("sign digest %s", hexutil.Encode(digest[:])))
+	res, err := t.fireblocksClient.RawSign(ctx, req)
+	if err != nil {
+		return nil, utils.WrapError("error creating raw signing transaction", err)
+	}
+	t.logger.Debug("Fireblocks raw signing transaction created, waiting for its signature", "txID", res.ID,
+		"status", res.Status)
+
+	ticker := time.NewTicker(t.rawSigningPollInterval)
+	defer ticker.Stop()
+	for {
+		fireblockTx, err := t.fireblocksClient.GetTransaction(ctx, res.ID)
+		if err != nil {
+			return nil, utils.WrapError("error getting raw signing transaction", err)
+		}
+		switch fireblockTx.Status {
+		case fireblocks.Completed:
+			if len(fireblockTx.SignedMessages) != 1 {
+				return nil, fmt.Errorf("raw signing transaction %s has %d signed messages instead of 1", res.ID,
+					len(fireblockTx.SignedMessages))
+			}
+			return ethereumSignature(digest, fireblockTx.SignedMessages[0].Signature, sender)
+		case fireblocks.Failed, fireblocks.Rejected, fireblocks.Cancelled, fireblocks.Blocked:
+			return nil, fmt.Errorf("%w: raw signing transaction %s status %s, sub-status %s", ErrTransactionFailed,
+				res.ID, fireblockTx.Status, fireblockTx.SubStatus)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ethereumSignature returns the 65 bytes [R || S || V] Ethereum signature of digest by signer from the signature of a
+// RAW signing transaction: S is normalized to the lower half of the curve order, and the recovery id, which is checked
+// against signer, is set to 27 or 28
+func ethereumSignature(
+	digest [32]byte,
+	messageSignature fireblocks.MessageSignature,
+	signer common.Address,
+) ([]byte, error) {
+	var r, s []byte
+	if messageSignature.R != "" && messageSignature.S != "" {
+		var err error
+		if r, err = hex.DecodeString(strings.TrimPrefix(messageSignature.R, "0x")); err != nil {
+			return nil, utils.WrapError("invalid signature r", err)
+		}
+		if s, err = hex.DecodeString(strings.TrimPrefix(messageSignature.S, "0x")); err != nil {
+			return nil, utils.WrapError("invalid signature s", err)
+		}
+	} else {
+		fullSig, err := hex.DecodeString(strings.TrimPrefix(messageSignature.FullSig, "0x"))
+		if err != nil || len(fullSig) != 64 {
+			return nil, fmt.Errorf("invalid full signature %q", messageSignature.FullSig)
+		}
+		r, s = fullSig[:32], fullSig[32:]
+	}
+	if len(r) > 32 || len(s) > 32 {
+		return nil, errors.New("invalid signature length")
+	}
+
+	curveOrder := crypto.S256().Params().N
+	sInt := new(big.Int).SetBytes(s)
+	v := byte(messageSignature.V % 2)
+	if sInt.Cmp(new(big.Int).Rsh(curveOrder, 1)) > 0 {
+		// (r, n - s) is the signature of the same digest with the other recovery id
+		sInt.Sub(curveOrder, sInt)
+		v ^= 1
+	}
+	signature := make([]byte, crypto.SignatureLength)
+	new(big.Int).SetBytes(r).FillBytes(signature[:32])
+	sInt.FillBytes(signature[32:64])
+
+	// the recovery id returned by Fireblocks is only trusted once checked
+	for _, recoveryID := range []byte{v, v ^ 1} {
+		signature[64] = recoveryID
+		publicKey, err := crypto.SigToPub(digest[:], signature)
+		if err == nil && crypto.PubkeyToAddress(*publicKey) == signer {
+			signature[64] += 27
+			return signature, nil
+		}
+	}
+	return nil, fmt.Errorf("signature is not a signature of %s", signer.Hex())
 }
 
 func weiToGwei(wei *big.Int) *big.Float {
 	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei))
 }
 
-func weiToEther(wei *big.Int) *big.Float {
-	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.Ether))
+// formatEther returns the exact decimal amount of ETH of wei, e.g. "1.5" or "0.000000000000000001"
+func formatEther(wei *big.Int) string {
+	sign := ""
+	if wei.Sign() < 0 {
+		sign = "-"
+	}
+	ether, rem := new(big.Int).QuoRem(new(big.Int).Abs(wei), big.NewInt(params.Ether), new(big.Int))
+	if rem.Sign() == 0 {
+		return sign + ether.String()
+	}
+	decimals := rem.String()
+	decimals = strings.TrimRight(strings.Repeat("0", 18-len(decimals))+decimals, "0")
+	return sign + ether.String() + "." + decimals
+}
+
+// isOneTimeAddressError returns whether err is the error of Fireblocks rejecting a transfer to a one-time address
+func isOneTimeAddressError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "one time address") || strings.Contains(msg, "one-time address")
 }
@@ -2,8 +2,22 @@ package wallet_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/internal/fakes"
 
@@ -11,9 +25,13 @@ import (
 	cmocks "github.com/Layr-Labs/eigensdk-go/chainio/clients/mocks"
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -115,29 +133,39 @@ func TestTransfer(t *testing.T) {
 		},
 	}, nil)
 	fireblocksClient.EXPECT().Transfer(gomock.Any(), &fireblocks.TransactionRequest{
-		Operation:    fireblocks.Transfer,
-		ExternalTxID: "",
-		AssetID:      "ETH_TEST3",
+		Operation: fireblocks.Transfer,
+		ExternalTxID: wallet.FireblocksExternalTxID(
+			big.NewInt(5),
+			"vaultAccountID",
+			0,
+			common.HexToAddress(externalAccount),
+			big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil),
+			[]byte{},
+			"",
+		),
+		AssetID: "ETH_TEST3",
 		Source: struct {
-			Type string `json:"type"`
-			ID   string `json:"id"`
+			Type           string                     `json:"type"`
+			ID             string                     `json:"id"`
+			OneTimeAddress *fireblocks.OneTimeAddress `json:"oneTimeAddress,omitempty"`
 		}{
 			Type: "VAULT_ACCOUNT",
 			ID:   "vaultAccountID",
 		},
 		Destination: struct {
-			Type string `json:"type"`
-			ID   string `json:"id"`
+			Type           string                     `json:"type"`
+			ID             string                     `json:"id"`
+			OneTimeAddress *fireblocks.OneTimeAddress `json:"oneTimeAddress,omitempty"`
 		}{
 			Type: "EXTERNAL_WALLET",
 			ID:   "accountID",
 		},
 		Amount:          "1",
 		ReplaceTxByHash: "",
-		GasPrice:        "",
+		GasPrice:        "1e-07",
 		GasLimit:        "100000",
-		MaxFee:          "1e-07",
-		PriorityFee:     "1e-07",
+		MaxFee:          "",
+		PriorityFee:     "",
 	}).Return(&fireblocks.TransactionResponse{
 		ID:     "1234",
 		Status: fireblocks.Confirming,
@@ -279,7 +307,7 @@ func TestSendTransactionNoValidExternalAccount(t *testing.T) {
 		big.NewInt(100), // gasPrice
 		[]byte{},        // data
 	))
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, wallet.ErrOneTimeAddressesDisabled)
 	assert.Equal(t, "", txID)
 }
 
@@ -397,7 +425,7 @@ func TestSendTransactionReplaceTx(t *testing.T) {
 		TxHash: expectedTxHash,
 	}, nil)
 	fireblocksClient.EXPECT().ContractCall(gomock.Any(), fireblocks.NewContractCallRequest(
-		"",
+		wallet.FireblocksExternalTxID(big.NewInt(5), "vaultAccountID", 0, addr, big.NewInt(0), baseTx.Data, "1234"),
 		"ETH_TEST3",
 		"vaultAccountID",
 		"contractID",
@@ -543,4 +571,726 @@ func TestSenderAddress(t *testing.T) {
 	addr, err := w.SenderAddress(context.Background())
 	assert.Nil(t, err)
 	assert.Equal(t, expectedSenderAddr, addr.String())
+
+	// the address is cached, so the vault account isn't queried again
+	addr, err = w.SenderAddress(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, expectedSenderAddr, addr.String())
+}
+
+func whitelistedContracts(addresses ...common.Address) []fireblocks.WhitelistedContract {
+	contracts := make([]fireblocks.WhitelistedContract, len(addresses))
+	for i, address := range addresses {
+		contracts[i].ID = "contractID-" + address.Hex()
+		contracts[i].Name = "TestContract"
+		contracts[i].Assets = append(contracts[i].Assets, struct {
+			ID      fireblocks.AssetID `json:"id"`
+			Status  string             `json:"status"`
+			Address common.Address     `json:"address"`
+			Tag     string             `json:"tag"`
+		}{ID: "ETH_TEST3", Status: "APPROVED", Address: address})
+	}
+	return contracts
+}
+
+func newContractCallTx(nonce uint64, to common.Address, gasPrice int64) *types.Transaction {
+	return types.NewTransaction(nonce, to, big.NewInt(0), 100000, big.NewInt(gasPrice), common.Hex2Bytes("6057361d"))
+}
+
+func TestSendTransactionWhitelistCache(t *testing.T) {
+	contract := common.HexToAddress(contractAddress)
+	newContract := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	vaultAccounts := []fireblocks.VaultAccount{{
+		ID:     "vaultAccountID",
+		Name:   vaultAccountName,
+		Assets: []fireblocks.Asset{{ID: "ETH_TEST3", Total: "1", Balance: "1", Available: "1"}},
+	}}
+	logger, err := logging.NewZapLogger(logging.Development)
+	assert.NoError(t, err)
+
+	t.Run("second send performs no list call", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		fireblocksClient.EXPECT().ListVaultAccounts(gomock.Any()).Return(vaultAccounts, nil).Times(1)
+		fireblocksClient.EXPECT().ListContracts(gomock.Any()).Return(whitelistedContracts(contract), nil).Times(1)
+		fireblocksClient.EXPECT().ContractCall(gomock.Any(), gomock.Any()).Return(&fireblocks.TransactionResponse{
+			ID:     "1234",
+			Status: fireblocks.Confirming,
+		}, nil).Times(2)
+
+		for nonce := uint64(0); nonce < 2; nonce++ {
+			_, err = sender.SendTransaction(context.Background(), newContractCallTx(nonce, contract, 100))
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("concurrent sends list the contracts once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		fireblocksClient.EXPECT().ListVaultAccounts(gomock.Any()).Return(vaultAccounts, nil).Times(1)
+		fireblocksClient.EXPECT().ListContracts(gomock.Any()).Return(whitelistedContracts(contract), nil).Times(1)
+		fireblocksClient.EXPECT().ContractCall(gomock.Any(), gomock.Any()).Return(&fireblocks.TransactionResponse{
+			ID:     "1234",
+			Status: fireblocks.Confirming,
+		}, nil).Times(5)
+
+		var wg sync.WaitGroup
+		for nonce := uint64(0); nonce < 5; nonce++ {
+			wg.Add(1)
+			go func(nonce uint64) {
+				defer wg.Done()
+				_, err := sender.SendTransaction(context.Background(), newContractCallTx(nonce, contract, 100))
+				assert.NoError(t, err)
+			}(nonce)
+		}
+		wg.Wait()
+	})
+
+	t.Run("miss refreshes the cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		fireblocksClient.EXPECT().ListVaultAccounts(gomock.Any()).Return(vaultAccounts, nil).Times(1)
+		gomock.InOrder(
+			fireblocksClient.EXPECT().ListContracts(gomock.Any()).Return(whitelistedContracts(contract), nil),
+			// newContract is whitelisted after the first send
+			fireblocksClient.EXPECT().ListContracts(gomock.Any()).Return(
+				whitelistedContracts(contract, newContract), nil,
+			),
+		)
+		fireblocksClient.EXPECT().ContractCall(gomock.Any(), gomock.Any()).Return(&fireblocks.TransactionResponse{
+			ID:     "1234",
+			Status: fireblocks.Confirming,
+		}, nil).Times(3)
+
+		_, err = sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 100))
+		assert.NoError(t, err)
+		_, err = sender.SendTransaction(context.Background(), newContractCallTx(1, newContract, 100))
+		assert.NoError(t, err)
+		_, err = sender.SendTransaction(context.Background(), newContractCallTx(2, contract, 100))
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired cache is refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+		sender, err := wallet.NewFireblocksWallet(
+			fireblocksClient,
+			fakes.NewEthClient(),
+			vaultAccountName,
+			logger,
+			wallet.WithWhitelistCacheTTL(10*time.Millisecond),
+		)
+		assert.NoError(t, err)
+
+		fireblocksClient.EXPECT().ListVaultAccounts(gomock.Any()).Return(vaultAccounts, nil).Times(1)
+		fireblocksClient.EXPECT().ListContracts(gomock.Any()).Return(whitelistedContracts(contract), nil).Times(2)
+		fireblocksClient.EXPECT().ContractCall(gomock.Any(), gomock.Any()).Return(&fireblocks.TransactionResponse{
+			ID:     "1234",
+			Status: fireblocks.Confirming,
+		}, nil).Times(2)
+
+		_, err = sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 100))
+		assert.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+		_, err = sender.SendTransaction(context.Background(), newContractCallTx(1, contract, 100))
+		assert.NoError(t, err)
+	})
+}
+
+// fakeFireblocksClient is a stateful fake of the Fireblocks API, whose transactions are pending signature until they
+// are broadcast with broadcast
+type fakeFireblocksClient struct {
+	fireblocks.Client
+	mu           sync.Mutex
+	contract     common.Address
+	txs          map[string]*fireblocks.Transaction
+	requests     []*fireblocks.TransactionRequest
+	cancelledTxs []string
+	// loseNextResponse makes the next transaction creation fail after creating the transaction
+	loseNextResponse bool
+}
+
+func newFakeFireblocksClient(contract common.Address) *fakeFireblocksClient {
+	return &fakeFireblocksClient{contract: contract, txs: make(map[string]*fireblocks.Transaction)}
+}
+
+func (f *fakeFireblocksClient) ContractCall(
+	ctx context.Context,
+	req *fireblocks.TransactionRequest,
+) (*fireblocks.TransactionResponse, error) {
+	return f.createTransaction(req)
+}
+
+func (f *fakeFireblocksClient) Transfer(
+	ctx context.Context,
+	req *fireblocks.TransactionRequest,
+) (*fireblocks.TransactionResponse, error) {
+	return f.createTransaction(req)
+}
+
+func (f *fakeFireblocksClient) ListExternalWallets(ctx context.Context) ([]fireblocks.WhitelistedAccount, error) {
+	return nil, nil
+}
+
+func (f *fakeFireblocksClient) createTransaction(
+	req *fireblocks.TransactionRequest,
+) (*fireblocks.TransactionResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	for _, tx := range f.txs {
+		if tx.ExternalID == req.ExternalTxID {
+			return nil, fmt.Errorf("error making request: %w", fireblocks.ErrExternalTxIDAlreadyExists)
+		}
+	}
+	id := fmt.Sprintf("tx-%d", len(f.txs))
+	f.txs[id] = &fireblocks.Transaction{ID: id, ExternalID: req.ExternalTxID, Status: fireblocks.PendingSignature}
+	if f.loseNextResponse {
+		f.loseNextResponse = false
+		return nil, errors.New("connection reset by peer")
+	}
+	return &fireblocks.TransactionResponse{ID: id, Status: fireblocks.Submitted}, nil
+}
+
+func (f *fakeFireblocksClient) GetTransactionByExternalTxID(
+	ctx context.Context,
+	externalTxID string,
+) (*fireblocks.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, tx := range f.txs {
+		if tx.ExternalID == externalTxID {
+			txCopy := *tx
+			return &txCopy, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction with external tx ID %s not found", externalTxID)
+}
+
+// numTxs returns the number of transactions created in Fireblocks
+func (f *fakeFireblocksClient) numTxs() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.txs)
+}
+
+func (f *fakeFireblocksClient) CancelTransaction(ctx context.Context, txID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tx, ok := f.txs[txID]
+	if !ok || tx.TxHash != "" {
+		return false, nil
+	}
+	f.cancelledTxs = append(f.cancelledTxs, txID)
+	tx.Status = fireblocks.Cancelled
+	return true, nil
+}
+
+func (f *fakeFireblocksClient) ListContracts(ctx context.Context) ([]fireblocks.WhitelistedContract, error) {
+	return whitelistedContracts(f.contract), nil
+}
+
+func (f *fakeFireblocksClient) ListVaultAccounts(ctx context.Context) ([]fireblocks.VaultAccount, error) {
+	return []fireblocks.VaultAccount{{
+		ID:     "vaultAccountID",
+		Name:   vaultAccountName,
+		Assets: []fireblocks.Asset{{ID: "ETH_TEST3", Total: "1", Balance: "1", Available: "1"}},
+	}}, nil
+}
+
+func (f *fakeFireblocksClient) GetTransaction(ctx context.Context, txID string) (*fireblocks.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tx, ok := f.txs[txID]
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", txID)
+	}
+	txCopy := *tx
+	return &txCopy, nil
+}
+
+// broadcast sets the status and hash of the transaction txID once it is broadcast
+func (f *fakeFireblocksClient) broadcast(txID string, status fireblocks.TxStatus, txHash string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.txs[txID].Status = status
+	f.txs[txID].TxHash = txHash
+}
+
+func TestSendTransactionReplacement(t *testing.T) {
+	contract := common.HexToAddress(contractAddress)
+	logger, err := logging.NewZapLogger(logging.Development)
+	assert.NoError(t, err)
+
+	t.Run("broadcasted tx is replaced by RBF", func(t *testing.T) {
+		fireblocksClient := newFakeFireblocksClient(contract)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		txID, err := sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 100))
+		assert.NoError(t, err)
+		fireblocksClient.broadcast(txID, fireblocks.Broadcasting, "0xdeadbeef")
+		replacementTxID, err := sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 200))
+		assert.NoError(t, err)
+
+		assert.Len(t, fireblocksClient.requests, 2)
+		assert.Equal(t, "0xdeadbeef", fireblocksClient.requests[1].ReplaceTxByHash)
+		assert.Empty(t, fireblocksClient.cancelledTxs)
+
+		// the replacement is mined and the replaced tx is dropped
+		fireblocksClient.broadcast(txID, fireblocks.Failed, "0xdeadbeef")
+		fireblocksClient.broadcast(replacementTxID, fireblocks.Completed, fakes.TransactionHash)
+		receipt, err := sender.GetTransactionReceipt(context.Background(), txID)
+		assert.NoError(t, err)
+		assert.Equal(t, fakes.TransactionHash, receipt.TxHash.String())
+	})
+
+	t.Run("tx not broadcasted yet is cancelled and recreated", func(t *testing.T) {
+		fireblocksClient := newFakeFireblocksClient(contract)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		txID, err := sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 100))
+		assert.NoError(t, err)
+		replacementTxID, err := sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 200))
+		assert.NoError(t, err)
+		secondReplacementTxID, err := sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 300))
+		assert.NoError(t, err)
+
+		assert.Len(t, fireblocksClient.requests, 3)
+		assert.Empty(t, fireblocksClient.requests[1].ReplaceTxByHash)
+		assert.Empty(t, fireblocksClient.requests[2].ReplaceTxByHash)
+		assert.Equal(t, []string{txID, replacementTxID}, fireblocksClient.cancelledTxs)
+
+		// the receipt lookups follow the replacements
+		_, err = sender.GetTransactionReceipt(context.Background(), txID)
+		assert.ErrorIs(t, err, wallet.ErrNotYetBroadcasted)
+		fireblocksClient.broadcast(secondReplacementTxID, fireblocks.Completed, fakes.TransactionHash)
+		receipt, err := sender.GetTransactionReceipt(context.Background(), txID)
+		assert.NoError(t, err)
+		assert.Equal(t, fakes.TransactionHash, receipt.TxHash.String())
+	})
+
+	t.Run("replaced tx mined first", func(t *testing.T) {
+		fireblocksClient := newFakeFireblocksClient(contract)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		txID, err := sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 100))
+		assert.NoError(t, err)
+		fireblocksClient.broadcast(txID, fireblocks.Broadcasting, fakes.TransactionHash)
+		replacementTxID, err := sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 200))
+		assert.NoError(t, err)
+
+		fireblocksClient.broadcast(txID, fireblocks.Completed, fakes.TransactionHash)
+		fireblocksClient.broadcast(replacementTxID, fireblocks.Failed, "")
+		receipt, err := sender.GetTransactionReceipt(context.Background(), txID)
+		assert.NoError(t, err)
+		assert.Equal(t, fakes.TransactionHash, receipt.TxHash.String())
+
+		// the nonce isn't tracked anymore once mined, so the next tx with this nonce is a new tx
+		_, err = sender.SendTransaction(context.Background(), newContractCallTx(0, contract, 300))
+		assert.NoError(t, err)
+		assert.Empty(t, fireblocksClient.requests[2].ReplaceTxByHash)
+	})
+}
+
+func TestFireblocksExternalTxID(t *testing.T) {
+	chainID := big.NewInt(5)
+	to := common.HexToAddress(contractAddress)
+	value := big.NewInt(1)
+	calldata := common.Hex2Bytes("6057361d")
+	key := wallet.FireblocksExternalTxID(chainID, "vaultAccountID", 1, to, value, calldata, "")
+	sameKey := wallet.FireblocksExternalTxID(
+		big.NewInt(5),
+		"vaultAccountID",
+		1,
+		common.HexToAddress(contractAddress),
+		big.NewInt(1),
+		common.CopyBytes(calldata),
+		"",
+	)
+	assert.Equal(t, key, sameKey)
+
+	for _, otherKey := range []string{
+		wallet.FireblocksExternalTxID(big.NewInt(1), "vaultAccountID", 1, to, value, calldata, ""),
+		wallet.FireblocksExternalTxID(chainID, "otherVaultAccountID", 1, to, value, calldata, ""),
+		wallet.FireblocksExternalTxID(chainID, "vaultAccountID", 2, to, value, calldata, ""),
+		wallet.FireblocksExternalTxID(chainID, "vaultAccountID", 1, common.HexToAddress(externalAccount), value,
+			calldata, ""),
+		wallet.FireblocksExternalTxID(chainID, "vaultAccountID", 1, to, big.NewInt(2), calldata, ""),
+		wallet.FireblocksExternalTxID(chainID, "vaultAccountID", 1, to, value, common.Hex2Bytes("6057361e"), ""),
+		wallet.FireblocksExternalTxID(chainID, "vaultAccountID", 1, to, value, calldata, "replacedTxID"),
+	} {
+		assert.NotEqual(t, key, otherKey)
+	}
+}
+
+func TestSendTransactionIdempotency(t *testing.T) {
+	contract := common.HexToAddress(contractAddress)
+	logger, err := logging.NewZapLogger(logging.Development)
+	assert.NoError(t, err)
+
+	t.Run("concurrent sends of the same tx create one fireblocks tx", func(t *testing.T) {
+		fireblocksClient := newFakeFireblocksClient(contract)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		tx := newContractCallTx(0, contract, 100)
+		txIDs := make([]wallet.TxID, 2)
+		var wg sync.WaitGroup
+		for i := range txIDs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				txID, err := sender.SendTransaction(context.Background(), tx)
+				assert.NoError(t, err)
+				txIDs[i] = txID
+			}(i)
+		}
+		wg.Wait()
+		assert.Equal(t, 1, fireblocksClient.numTxs())
+		assert.Equal(t, txIDs[0], txIDs[1])
+	})
+
+	t.Run("retried send adopts the tx created by the failed send", func(t *testing.T) {
+		fireblocksClient := newFakeFireblocksClient(contract)
+		fireblocksClient.loseNextResponse = true
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		assert.NoError(t, err)
+
+		tx := newContractCallTx(0, contract, 100)
+		_, err = sender.SendTransaction(context.Background(), tx)
+		assert.Error(t, err)
+		txID, err := sender.SendTransaction(context.Background(), tx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, fireblocksClient.numTxs())
+		assert.Equal(t, "tx-0", txID)
+		assert.Equal(t,
+			wallet.FireblocksExternalTxID(big.NewInt(5), "vaultAccountID", 0, contract, big.NewInt(0), tx.Data(), ""),
+			fireblocksClient.requests[1].ExternalTxID,
+		)
+	})
+
+	t.Run("different transfer with the same nonce after a restart creates a new tx", func(t *testing.T) {
+		fireblocksClient := newFakeFireblocksClient(contract)
+		newSender := func() wallet.Wallet {
+			sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName,
+				logger, wallet.WithOneTimeAddresses())
+			assert.NoError(t, err)
+			return sender
+		}
+
+		txID, err := newSender().SendTransaction(context.Background(), types.NewTransaction(
+			0, common.HexToAddress(externalAccount), big.NewInt(1), 21000, big.NewInt(100), nil))
+		assert.NoError(t, err)
+		// the sender restarts and sends another transfer with the same nonce and no calldata
+		otherTxID, err := newSender().SendTransaction(context.Background(), types.NewTransaction(
+			0, common.HexToAddress(externalAccount), big.NewInt(2), 21000, big.NewInt(100), nil))
+		assert.NoError(t, err)
+		assert.NotEqual(t, txID, otherTxID)
+		otherRecipientTxID, err := newSender().SendTransaction(context.Background(), types.NewTransaction(
+			0, common.HexToAddress("0x3333333333333333333333333333333333333333"), big.NewInt(1), 21000,
+			big.NewInt(100), nil))
+		assert.NoError(t, err)
+		assert.NotEqual(t, txID, otherRecipientTxID)
+		assert.Equal(t, 3, fireblocksClient.numTxs())
+	})
+
+	for _, status := range []fireblocks.TxStatus{
+		fireblocks.Failed,
+		fireblocks.Rejected,
+		fireblocks.Cancelled,
+		fireblocks.Blocked,
+	} {
+		t.Run(fmt.Sprintf("retried send recreates the %s tx created by the failed send", status), func(t *testing.T) {
+			fireblocksClient := newFakeFireblocksClient(contract)
+			fireblocksClient.loseNextResponse = true
+			sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+			assert.NoError(t, err)
+
+			tx := newContractCallTx(0, contract, 100)
+			_, err = sender.SendTransaction(context.Background(), tx)
+			assert.Error(t, err)
+			fireblocksClient.broadcast("tx-0", status, "")
+			txID, err := sender.SendTransaction(context.Background(), tx)
+			assert.NoError(t, err)
+			assert.Equal(t, "tx-1", txID)
+			assert.Equal(t, 2, fireblocksClient.numTxs())
+
+			// the sender restarts and retries the send, adopting the recreated tx
+			restartedSender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(),
+				vaultAccountName, logger)
+			assert.NoError(t, err)
+			txID, err = restartedSender.SendTransaction(context.Background(), tx)
+			assert.NoError(t, err)
+			assert.Equal(t, "tx-1", txID)
+			assert.Equal(t, 2, fireblocksClient.numTxs())
+		})
+	}
+}
+
+// fakeRawSigningServer serves the Fireblocks API endpoints used by SignDigest, signing the digests with key
+type fakeRawSigningServer struct {
+	t   *testing.T
+	key *ecdsa.PrivateKey
+	// highS makes the server return the high S signatures, and wrongV a wrong recovery id
+	highS  bool
+	wrongV bool
+	status fireblocks.TxStatus
+
+	mu       sync.Mutex
+	requests []string
+	polls    int
+}
+
+func (s *fakeRawSigningServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	address := crypto.PubkeyToAddress(s.key.PublicKey)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/vault/accounts_paged":
+		fmt.Fprintf(w, `{"accounts":[{"id":"vaultAccountID","name":%q}],"paging":{}}`, vaultAccountName)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/vault/accounts/vaultAccountID/ETH_TEST3/addresses_paginated":
+		fmt.Fprintf(w, `{"addresses":[{"assetId":"ETH_TEST3","address":%q,"bip44AddressIndex":2}],"paging":{}}`,
+			address.Hex())
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/transactions":
+		body, err := io.ReadAll(r.Body)
+		require.NoError(s.t, err)
+		s.requests = append(s.requests, string(body))
+		fmt.Fprint(w, `{"id":"rawTxID","status":"SUBMITTED"}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/transactions/rawTxID":
+		s.polls++
+		if s.polls == 1 {
+			fmt.Fprint(w, `{"id":"rawTxID","status":"PENDING_SIGNATURE"}`)
+			return
+		}
+		if s.status != fireblocks.Completed {
+			fmt.Fprintf(w, `{"id":"rawTxID","status":%q,"subStatus":"REJECTED_BY_USER"}`, s.status)
+			return
+		}
+		var request fireblocks.RawSigningRequest
+		require.NoError(s.t, json.Unmarshal([]byte(s.requests[len(s.requests)-1]), &request))
+		digest, err := hex.DecodeString(request.ExtraParameters.RawMessageData.Messages[0].Content)
+		require.NoError(s.t, err)
+		signature, err := crypto.Sign(digest, s.key)
+		require.NoError(s.t, err)
+		sig, v := new(big.Int).SetBytes(signature[32:64]), signature[64]
+		if s.highS {
+			sig.Sub(crypto.S256().Params().N, sig)
+			v ^= 1
+		}
+		if s.wrongV {
+			v ^= 1
+		}
+		fmt.Fprintf(w, `{"id":"rawTxID","status":"COMPLETED","signedMessages":[{"content":"%x",`+
+			`"algorithm":"MPC_ECDSA_SECP256K1","signature":{"r":"%x","s":"%064x","v":%d}}]}`,
+			digest, signature[:32], sig, v)
+	default:
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSignDigest(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	secretKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	digest := crypto.Keccak256Hash([]byte("operator AVS registration"))
+	newWallet := func(t *testing.T, server *fakeRawSigningServer) wallet.Wallet {
+		httpServer := httptest.NewServer(server)
+		t.Cleanup(httpServer.Close)
+		fireblocksClient, err := fireblocks.NewClient("apiKey", secretKey, httpServer.URL, 5*time.Second, logger)
+		require.NoError(t, err)
+		fireblocksWallet, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName,
+			logger, wallet.WithRawSigningPollInterval(time.Millisecond))
+		require.NoError(t, err)
+		return fireblocksWallet
+	}
+
+	for _, tt := range []struct {
+		name   string
+		highS  bool
+		wrongV bool
+	}{
+		{name: "Signs the digest with a RAW signing transaction"},
+		{name: "High S signatures are normalized", highS: true},
+		{name: "Wrong recovery ids are fixed", wrongV: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeRawSigningServer{t: t, key: key, highS: tt.highS, wrongV: tt.wrongV,
+				status: fireblocks.Completed}
+			signDigest := wallet.NewDigestSignerFn(newWallet(t, server).(wallet.DigestSigner))
+
+			signature, err := signDigest(context.Background(), signer, digest)
+			require.NoError(t, err)
+
+			require.Len(t, server.requests, 1)
+			expectedRequest := fmt.Sprintf(`{"operation":"RAW","assetId":"ETH_TEST3",`+
+				`"source":{"type":"VAULT_ACCOUNT","id":"vaultAccountID"},"note":"sign digest %s",`+
+				`"extraParameters":{"rawMessageData":{"messages":[{"content":"%x","bip44addressIndex":2}]}}}`,
+				digest.Hex(), digest[:])
+			require.JSONEq(t, expectedRequest, server.requests[0])
+			require.Len(t, signature, 65)
+			require.Contains(t, []byte{27, 28}, signature[64])
+			s := new(big.Int).SetBytes(signature[32:64])
+			require.LessOrEqual(t, s.Cmp(new(big.Int).Rsh(crypto.S256().Params().N, 1)), 0)
+			recoverableSig := append(common.CopyBytes(signature[:64]), signature[64]-27)
+			publicKey, err := crypto.SigToPub(digest[:], recoverableSig)
+			require.NoError(t, err)
+			require.Equal(t, signer, crypto.PubkeyToAddress(*publicKey))
+		})
+	}
+
+	t.Run("Rejected signing and other signers fail", func(t *testing.T) {
+		server := &fakeRawSigningServer{t: t, key: key, status: fireblocks.Rejected}
+		signDigest := wallet.NewDigestSignerFn(newWallet(t, server).(wallet.DigestSigner))
+
+		_, err := signDigest(context.Background(), signer, digest)
+		require.ErrorIs(t, err, wallet.ErrTransactionFailed)
+		_, err = signDigest(context.Background(), common.HexToAddress(externalAccount), digest)
+		require.ErrorIs(t, err, bind.ErrNotAuthorized)
+	})
+}
+
+func TestSendTransactionValue(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	vaultAccounts := []fireblocks.VaultAccount{{
+		ID:     "vaultAccountID",
+		Name:   vaultAccountName,
+		Assets: []fireblocks.Asset{{ID: "ETH_TEST3", Total: "3", Balance: "3", Available: "3"}},
+	}}
+	// 1.5 ETH and 1 wei
+	value, ok := new(big.Int).SetString("1500000000000000001", 10)
+	require.True(t, ok)
+
+	t.Run("contract call with value", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger)
+		require.NoError(t, err)
+
+		fireblocksClient.EXPECT().ListVaultAccounts(gomock.Any()).Return(vaultAccounts, nil)
+		fireblocksClient.EXPECT().ListContracts(gomock.Any()).Return([]fireblocks.WhitelistedContract{{
+			ID:   "contractID",
+			Name: "TestContract",
+			Assets: []struct {
+				ID      fireblocks.AssetID `json:"id"`
+				Status  string             `json:"status"`
+				Address common.Address     `json:"address"`
+				Tag     string             `json:"tag"`
+			}{{ID: "ETH_TEST3", Status: "APPROVED", Address: common.HexToAddress(contractAddress)}},
+		}}, nil)
+		fireblocksClient.EXPECT().ContractCall(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *fireblocks.TransactionRequest) (*fireblocks.TransactionResponse, error) {
+				assert.Equal(t, "1.500000000000000001", req.Amount)
+				assert.Equal(t, "contractID", req.Destination.ID)
+				return &fireblocks.TransactionResponse{ID: "1234", Status: fireblocks.Confirming}, nil
+			})
+
+		txID, err := sender.SendTransaction(context.Background(), types.NewTransaction(
+			0, common.HexToAddress(contractAddress), value, 100000, big.NewInt(100), []byte{1, 2, 3}))
+		require.NoError(t, err)
+		assert.Equal(t, "1234", txID)
+	})
+
+	t.Run("transfer to a one-time address", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger,
+			wallet.WithOneTimeAddresses())
+		require.NoError(t, err)
+
+		fireblocksClient.EXPECT().ListVaultAccounts(gomock.Any()).Return(vaultAccounts, nil)
+		fireblocksClient.EXPECT().ListExternalWallets(gomock.Any()).Return(nil, nil)
+		fireblocksClient.EXPECT().Transfer(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *fireblocks.TransactionRequest) (*fireblocks.TransactionResponse, error) {
+				assert.Equal(t, "1.500000000000000001", req.Amount)
+				assert.Equal(t, "ONE_TIME_ADDRESS", req.Destination.Type)
+				require.NotNil(t, req.Destination.OneTimeAddress)
+				assert.Equal(t, common.HexToAddress(externalAccount).Hex(), req.Destination.OneTimeAddress.Address)
+				body, err := json.Marshal(req.Destination)
+				require.NoError(t, err)
+				assert.JSONEq(t,
+					fmt.Sprintf(`{"type":"ONE_TIME_ADDRESS","id":"","oneTimeAddress":{"address":%q}}`,
+						common.HexToAddress(externalAccount).Hex()),
+					string(body))
+				return &fireblocks.TransactionResponse{ID: "1234", Status: fireblocks.Confirming}, nil
+			})
+
+		txID, err := sender.SendTransaction(context.Background(), types.NewTransaction(
+			0, common.HexToAddress(externalAccount), value, 21000, big.NewInt(100), nil))
+		require.NoError(t, err)
+		assert.Equal(t, "1234", txID)
+	})
+
+	t.Run("one-time addresses disabled in the workspace", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+		sender, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger,
+			wallet.WithOneTimeAddresses())
+		require.NoError(t, err)
+
+		fireblocksClient.EXPECT().ListVaultAccounts(gomock.Any()).Return(vaultAccounts, nil)
+		fireblocksClient.EXPECT().ListExternalWallets(gomock.Any()).Return(nil, nil)
+		fireblocksClient.EXPECT().Transfer(gomock.Any(), gomock.Any()).Return(nil,
+			errors.New("error response (400): One time address is not enabled for this workspace"))
+
+		_, err = sender.SendTransaction(context.Background(), types.NewTransaction(
+			0, common.HexToAddress(externalAccount), value, 21000, big.NewInt(100), nil))
+		require.ErrorIs(t, err, wallet.ErrOneTimeAddressesDisabled)
+	})
+}
+
+func TestFireblocksWalletAssetID(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	ctrl := gomock.NewController(t)
+	fireblocksClient := cmocks.NewMockFireblocksClient(ctrl)
+
+	// the chain of the fake client is goerli, whose asset is ETH_TEST3
+	_, err := wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger,
+		wallet.WithAssetID(fireblocks.AssetIDGoerliETH))
+	require.NoError(t, err)
+	_, err = wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger,
+		wallet.WithAssetID(fireblocks.AssetIDETH))
+	require.ErrorIs(t, err, wallet.ErrAssetIDChainMismatch)
+	_, err = wallet.NewFireblocksWallet(fireblocksClient, fakes.NewEthClient(), vaultAccountName, logger,
+		wallet.WithAssetID("ETH_TEST5"))
+	require.ErrorIs(t, err, wallet.ErrAssetIDChainMismatch)
+
+	// the wallets of the chains missing from AssetIDByChain are created without an asset, failing at send time
+	anvilClient := &chainIDEthClient{EthClient: fakes.NewEthClient(), chainID: big.NewInt(31337)}
+	sender, err := wallet.NewFireblocksWallet(fireblocksClient, anvilClient, vaultAccountName, logger)
+	require.NoError(t, err)
+	_, err = sender.SendTransaction(context.Background(), types.NewTransaction(
+		0, common.HexToAddress(contractAddress), big.NewInt(0), 21000, big.NewInt(100), []byte{}))
+	require.ErrorContains(t, err, "unsupported chain 31337")
+	_, err = sender.SenderAddress(context.Background())
+	require.ErrorContains(t, err, "unsupported chain 31337")
+	_, err = wallet.NewFireblocksWallet(fireblocksClient, anvilClient, vaultAccountName, logger,
+		wallet.WithAssetID("ANVIL_ETH"))
+	require.NoError(t, err)
+}
+
+// chainIDEthClient is a fake eth client of the chain chainID
+type chainIDEthClient struct {
+	*fakes.EthClient
+	chainID *big.Int
+}
+
+func (c *chainIDEthClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chainID, nil
 }
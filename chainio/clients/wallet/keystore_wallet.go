@@ -0,0 +1,187 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PasswordSource returns the password of a keystore, e.g. StaticPassword, EnvPassword, or a function prompting the
+// user interactively
+type PasswordSource func() (string, error)
+
+// StaticPassword returns a PasswordSource always returning password
+func StaticPassword(password string) PasswordSource {
+	return func() (string, error) {
+		return password, nil
+	}
+}
+
+// EnvPassword returns a PasswordSource reading the password from the environment variable envVar, which must be set
+func EnvPassword(envVar string) PasswordSource {
+	return func() (string, error) {
+		password, ok := os.LookupEnv(envVar)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", envVar)
+		}
+		return password, nil
+	}
+}
+
+// KeystoreEthBackend is the eth client of a keystore wallet, which also needs the chain id to sign the transactions
+type KeystoreEthBackend interface {
+	EthBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+type keystoreWallet struct {
+	ethClient KeystoreEthBackend
+	keys      map[common.Address]*ecdsa.PrivateKey
+	// defaultSender is the address of the only key of the keystore, nil if it has multiple keys
+	defaultSender *common.Address
+	logger        logging.Logger
+
+	chainIDMu sync.Mutex
+	chainID   *big.Int
+}
+
+var _ Wallet = (*keystoreWallet)(nil)
+
+// NewKeystoreWallet returns a wallet signing with the keys of the geth (v3) keystore at keystorePath, which is either
+// a keystore file, or a keystore directory whose keys all use the same password. The keys are decrypted once, with
+// the password returned by password, and only kept in memory.
+// With a keystore directory containing multiple keys, the sender of every call must be selected with
+// WithSenderAddress.
+func NewKeystoreWallet(
+	keystorePath string,
+	password PasswordSource,
+	client KeystoreEthBackend,
+	logger logging.Logger,
+) (Wallet, error) {
+	keyFiles, err := keystoreFiles(keystorePath)
+	if err != nil {
+		return nil, err
+	}
+	keystorePassword, err := password()
+	if err != nil {
+		return nil, utils.WrapError("failed to get keystore password", err)
+	}
+
+	keys := make(map[common.Address]*ecdsa.PrivateKey, len(keyFiles))
+	for _, keyFile := range keyFiles {
+		keyJSON, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, utils.WrapError(fmt.Errorf("failed to read keystore file %s", keyFile), err)
+		}
+		key, err := keystore.DecryptKey(keyJSON, keystorePassword)
+		if err != nil {
+			return nil, utils.WrapError(fmt.Errorf("failed to decrypt keystore file %s", keyFile), err)
+		}
+		keys[key.Address] = key.PrivateKey
+	}
+	w := &keystoreWallet{
+		ethClient: client,
+		keys:      keys,
+		logger:    logger,
+	}
+	if len(keys) == 1 {
+		for address := range keys {
+			w.defaultSender = &address
+		}
+	}
+	return w, nil
+}
+
+// keystoreFiles returns the keystore file at path, or the keystore files in the directory at path, skipping the
+// hidden files and subdirectories like geth
+func keystoreFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, utils.WrapError("failed to read keystore", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, utils.WrapError("failed to read keystore directory", err)
+	}
+	var keyFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		keyFiles = append(keyFiles, filepath.Join(path, entry.Name()))
+	}
+	if len(keyFiles) == 0 {
+		return nil, fmt.Errorf("no keystore file in directory %s", path)
+	}
+	return keyFiles, nil
+}
+
+func (t *keystoreWallet) SendTransaction(ctx context.Context, tx *types.Transaction) (TxID, error) {
+	sender, err := t.SenderAddress(ctx)
+	if err != nil {
+		return "", err
+	}
+	chainID, err := t.getChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	t.logger.Debug("Sending transaction", "sender", sender.Hex())
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), t.keys[sender])
+	if err != nil {
+		return "", utils.WrapError(fmt.Errorf("sign: tx %v failed", tx.Hash().String()), err)
+	}
+	if err := t.ethClient.SendTransaction(ctx, signedTx); err != nil {
+		return "", utils.WrapError(fmt.Errorf("send: tx %v failed", tx.Hash().String()), err)
+	}
+	return signedTx.Hash().Hex(), nil
+}
+
+// getChainID returns the chain id of the eth client, which is fetched on the first call
+func (t *keystoreWallet) getChainID(ctx context.Context) (*big.Int, error) {
+	t.chainIDMu.Lock()
+	defer t.chainIDMu.Unlock()
+	if t.chainID == nil {
+		chainID, err := t.ethClient.ChainID(ctx)
+		if err != nil {
+			return nil, utils.WrapError("failed to get chain id", err)
+		}
+		t.chainID = chainID
+	}
+	return t.chainID, nil
+}
+
+func (t *keystoreWallet) GetTransactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error) {
+	txHash := common.HexToHash(txID)
+	return t.ethClient.TransactionReceipt(ctx, txHash)
+}
+
+// SenderAddress returns the address selected with WithSenderAddress, or the address of the only key of the keystore
+func (t *keystoreWallet) SenderAddress(ctx context.Context) (common.Address, error) {
+	if sender, ok := SenderAddressFromContext(ctx); ok {
+		if _, ok := t.keys[sender]; !ok {
+			return common.Address{}, fmt.Errorf("no key for sender %s in keystore", sender.Hex())
+		}
+		return sender, nil
+	}
+	if t.defaultSender == nil {
+		return common.Address{}, errors.New("keystore has multiple keys: select the sender with WithSenderAddress")
+	}
+	return *t.defaultSender, nil
+}
@@ -0,0 +1,161 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeystoreEthBackend records the transactions sent, which are never mined
+type fakeKeystoreEthBackend struct {
+	mu      sync.Mutex
+	sentTxs []*types.Transaction
+}
+
+func (b *fakeKeystoreEthBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sentTxs = append(b.sentTxs, tx)
+	return nil
+}
+
+func (b *fakeKeystoreEthBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return nil, ethereum.NotFound
+}
+
+func (b *fakeKeystoreEthBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return chainId, nil
+}
+
+func newKeystoreTestTx(to common.Address) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     0,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1_000_000_000),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	})
+}
+
+func TestKeystoreWallet(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	password := "testpassword"
+
+	t.Run("Keystore file with the password from the environment", func(t *testing.T) {
+		ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+		account, err := ks.NewAccount(password)
+		require.NoError(t, err)
+		t.Setenv("KEYSTORE_PASSWORD", password)
+		backend := &fakeKeystoreEthBackend{}
+
+		ksWallet, err := NewKeystoreWallet(account.URL.Path, EnvPassword("KEYSTORE_PASSWORD"), backend, logger)
+		require.NoError(t, err)
+		sender, err := ksWallet.SenderAddress(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, account.Address, sender)
+
+		txID, err := ksWallet.SendTransaction(context.Background(), newKeystoreTestTx(account.Address))
+		require.NoError(t, err)
+		require.Len(t, backend.sentTxs, 1)
+		signedTx := backend.sentTxs[0]
+		require.Equal(t, signedTx.Hash().Hex(), txID)
+		from, err := types.Sender(types.LatestSignerForChainID(chainId), signedTx)
+		require.NoError(t, err)
+		require.Equal(t, account.Address, from)
+	})
+
+	t.Run("Keystore directory with multiple keys", func(t *testing.T) {
+		keystoreDir := t.TempDir()
+		ks := keystore.NewKeyStore(keystoreDir, keystore.LightScryptN, keystore.LightScryptP)
+		account1, err := ks.NewAccount(password)
+		require.NoError(t, err)
+		account2, err := ks.NewAccount(password)
+		require.NoError(t, err)
+		backend := &fakeKeystoreEthBackend{}
+		prompts := 0
+		promptPassword := func() (string, error) {
+			prompts++
+			return password, nil
+		}
+
+		ksWallet, err := NewKeystoreWallet(keystoreDir, promptPassword, backend, logger)
+		require.NoError(t, err)
+		// the keys are decrypted once
+		require.Equal(t, 1, prompts)
+		_, err = ksWallet.SenderAddress(context.Background())
+		require.Error(t, err)
+		_, err = ksWallet.SenderAddress(WithSenderAddress(context.Background(), common.Address{0x1}))
+		require.Error(t, err)
+
+		for _, account := range []common.Address{account1.Address, account2.Address} {
+			ctx := WithSenderAddress(context.Background(), account)
+			sender, err := ksWallet.SenderAddress(ctx)
+			require.NoError(t, err)
+			require.Equal(t, account, sender)
+			_, err = ksWallet.SendTransaction(ctx, newKeystoreTestTx(account))
+			require.NoError(t, err)
+			from, err := types.Sender(types.LatestSignerForChainID(chainId), backend.sentTxs[len(backend.sentTxs)-1])
+			require.NoError(t, err)
+			require.Equal(t, account, from)
+		}
+		require.Equal(t, 1, prompts)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+		account, err := ks.NewAccount(password)
+		require.NoError(t, err)
+
+		_, err = NewKeystoreWallet(account.URL.Path, StaticPassword("wrong"), &fakeKeystoreEthBackend{}, logger)
+		require.ErrorIs(t, err, keystore.ErrDecrypt)
+		_, err = NewKeystoreWallet(account.URL.Path, EnvPassword("UNSET_KEYSTORE_PASSWORD"), &fakeKeystoreEthBackend{},
+			logger)
+		require.Error(t, err)
+	})
+
+	t.Run("SendTransaction + GetTransactionReceipt on anvil", func(t *testing.T) {
+		anvilC, err := testutils.StartAnvilContainer("")
+		require.NoError(t, err)
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		anvilHttpEndpoint, err := anvilC.Endpoint(ctxWithTimeout, "http")
+		require.NoError(t, err)
+		ethClient, err := ethclient.Dial(anvilHttpEndpoint)
+		require.NoError(t, err)
+
+		ecdsaPrivKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+		require.NoError(t, err)
+		ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+		account, err := ks.ImportECDSA(ecdsaPrivKey, password)
+		require.NoError(t, err)
+		ksWallet, err := NewKeystoreWallet(account.URL.Path, StaticPassword(password), ethClient, logger)
+		require.NoError(t, err)
+
+		ctxWithTimeout, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		txID, err := ksWallet.SendTransaction(ctxWithTimeout, newKeystoreTestTx(account.Address))
+		require.NoError(t, err)
+
+		// need to give some time for anvil to process the tx and mine the block
+		time.Sleep(3 * time.Second)
+
+		ctxWithTimeout, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		receipt, err := ksWallet.GetTransactionReceipt(ctxWithTimeout, txID)
+		require.NoError(t, err)
+		require.Equal(t, txID, receipt.TxHash.String())
+	})
+}
@@ -0,0 +1,13 @@
+//go:build !ledger
+
+package wallet
+
+import (
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// NewLedgerWallet returns ErrLedgerUnsupported: the Ledger wallet is only available in binaries built with the
+// ledger build tag, which requires cgo and the USB HID libraries
+func NewLedgerWallet(derivationPath string, client KeystoreEthBackend, logger logging.Logger) (Wallet, error) {
+	return nil, ErrLedgerUnsupported
+}
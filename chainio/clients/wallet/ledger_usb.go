@@ -0,0 +1,50 @@
+//go:build ledger
+
+package wallet
+
+import (
+	"errors"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/karalabe/hid"
+)
+
+const (
+	// ledgerVendorID is the USB vendor id of the Ledger devices
+	ledgerVendorID = 0x2c97
+	// ledgerUsagePage is the usage page of the HID interface of the Ledger devices (Windows/Mac only)
+	ledgerUsagePage = 0xffa0
+)
+
+// NewLedgerWallet returns a wallet signing with the key at derivationPath (e.g. "m/44'/60'/0'/0/0") of the first
+// Ledger device plugged in, whose Ethereum app must be open, and broadcasting the transactions with client.
+// Every transaction must be confirmed by the user on the device. EIP-1559 transactions require the Ethereum app
+// 1.9.0 or later.
+// NewLedgerWallet is only available in binaries built with the ledger build tag, which requires cgo: it returns
+// ErrLedgerUnsupported otherwise.
+func NewLedgerWallet(derivationPath string, client KeystoreEthBackend, logger logging.Logger) (Wallet, error) {
+	if !hid.Supported() {
+		return nil, errors.New("USB HID devices are not supported on this platform")
+	}
+	infos, err := hid.Enumerate(ledgerVendorID, 0)
+	if err != nil {
+		return nil, utils.WrapError("failed to list USB HID devices", err)
+	}
+	for _, info := range infos {
+		if info.UsagePage != ledgerUsagePage && info.Interface != 0 {
+			continue
+		}
+		device, err := info.Open()
+		if err != nil {
+			return nil, utils.WrapError("failed to open ledger device", err)
+		}
+		w, err := newLedgerWallet(device, derivationPath, client, logger)
+		if err != nil {
+			device.Close()
+			return nil, err
+		}
+		return w, nil
+	}
+	return nil, ErrLedgerNotFound
+}
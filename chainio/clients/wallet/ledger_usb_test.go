@@ -0,0 +1,48 @@
+//go:build ledger
+
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLedgerWalletIntegration signs a legacy and an EIP-1559 transaction with the first account of a plugged in
+// Ledger device, which must be unlocked with its Ethereum app open, and both transactions must be confirmed on it.
+// The signed transactions are not broadcast.
+func TestLedgerWalletIntegration(t *testing.T) {
+	t.Skip("skipping test as it's meant for manual runs only")
+
+	logger := testutils.NewTestLogger()
+	backend := &fakeKeystoreEthBackend{}
+	ledgerWallet, err := NewLedgerWallet("m/44'/60'/0'/0/0", backend, logger)
+	require.NoError(t, err)
+	sender, err := ledgerWallet.SenderAddress(context.Background())
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	txs := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1_000_000_000), Gas: 21000, To: &to}),
+		types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainId,
+			Nonce:     1,
+			GasTipCap: big.NewInt(1_000_000_000),
+			GasFeeCap: big.NewInt(2_000_000_000),
+			Gas:       21000,
+			To:        &to,
+		}),
+	}
+	for _, tx := range txs {
+		_, err := ledgerWallet.SendTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		from, err := types.Sender(types.LatestSignerForChainID(chainId), backend.sentTxs[len(backend.sentTxs)-1])
+		require.NoError(t, err)
+		require.Equal(t, sender, from)
+	}
+}
@@ -0,0 +1,343 @@
+package wallet
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// ErrLedgerUnsupported is returned by NewLedgerWallet in binaries built without the ledger build tag
+	ErrLedgerUnsupported = errors.New("ledger support is not compiled in: build with -tags ledger")
+	// ErrLedgerNotFound indicates that no Ledger device is plugged in
+	ErrLedgerNotFound = errors.New("no ledger device found")
+	// ErrLedgerDeviceLocked indicates that the Ledger device must be unlocked with its PIN
+	ErrLedgerDeviceLocked = errors.New("ledger device is locked")
+	// ErrLedgerAppClosed indicates that the Ethereum app is not open on the Ledger device
+	ErrLedgerAppClosed = errors.New("ledger ethereum app is not open")
+	// ErrLedgerUserRejected indicates that the user rejected the transaction on the Ledger device
+	ErrLedgerUserRejected = errors.New("transaction rejected on the ledger device")
+)
+
+// APDUs of the Ethereum app of Ledger devices, as sent by go-ethereum's usbwallet
+// Reference: https://github.com/LedgerHQ/app-ethereum/blob/develop/doc/ethapp.adoc
+const (
+	ledgerOpRetrieveAddress  = 0x02
+	ledgerOpSignTransaction  = 0x04
+	ledgerOpGetConfiguration = 0x06
+
+	ledgerP1InitTransactionData = 0x00
+	ledgerP1ContTransactionData = 0x80
+)
+
+// status words returned by the Ledger device, along with the reply of every APDU
+const (
+	ledgerStatusOK                 = 0x9000
+	ledgerStatusDeviceLocked       = 0x5515
+	ledgerStatusSecurityNotSatisfy = 0x6982
+	ledgerStatusUserRejected       = 0x6985
+	ledgerStatusAppNotOpen         = 0x6511
+	ledgerStatusInsNotSupported    = 0x6d00
+	ledgerStatusClaNotSupported    = 0x6e00
+)
+
+// ledgerMinTypedTxVersion is the first version of the Ethereum app signing typed (EIP-2718) transactions
+var ledgerMinTypedTxVersion = [3]byte{1, 9, 0}
+
+type ledgerWallet struct {
+	// deviceMu serializes the APDU exchanges, which must not interleave
+	deviceMu sync.Mutex
+	device   io.ReadWriter
+	// version is the version of the Ethereum app of the device
+	version [3]byte
+
+	path      accounts.DerivationPath
+	address   common.Address
+	ethClient KeystoreEthBackend
+	logger    logging.Logger
+
+	chainIDMu sync.Mutex
+	chainID   *big.Int
+}
+
+var _ Wallet = (*ledgerWallet)(nil)
+
+// newLedgerWallet returns a wallet signing with the key at derivationPath of the Ledger device, which is exchanged
+// with as a HID device sending and receiving 64 bytes reports
+func newLedgerWallet(
+	device io.ReadWriter,
+	derivationPath string,
+	client KeystoreEthBackend,
+	logger logging.Logger,
+) (*ledgerWallet, error) {
+	path, err := accounts.ParseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, utils.WrapError("invalid derivation path", err)
+	}
+	w := &ledgerWallet{
+		device:    device,
+		path:      path,
+		ethClient: client,
+		logger:    logger,
+	}
+	config, err := w.exchange(ledgerOpGetConfiguration, 0, 0, nil)
+	if err != nil {
+		return nil, utils.WrapError("failed to get ledger ethereum app configuration", err)
+	}
+	if len(config) < 4 {
+		return nil, fmt.Errorf("invalid ledger ethereum app configuration %x", config)
+	}
+	copy(w.version[:], config[1:4])
+
+	w.address, err = w.deriveAddress()
+	if err != nil {
+		return nil, utils.WrapError(fmt.Errorf("failed to derive address at %s", derivationPath), err)
+	}
+	logger.Info("Using ledger account", "address", w.address.Hex(), "derivationPath", derivationPath,
+		"appVersion", fmt.Sprintf("%d.%d.%d", w.version[0], w.version[1], w.version[2]))
+	return w, nil
+}
+
+func (t *ledgerWallet) SendTransaction(ctx context.Context, tx *types.Transaction) (TxID, error) {
+	chainID, err := t.getChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	t.logger.Info("Confirm the transaction on the ledger device", "sender", t.address.Hex(), "to", tx.To(),
+		"nonce", tx.Nonce(), "value", tx.Value())
+	signedTx, err := t.signTransaction(tx, chainID)
+	if err != nil {
+		return "", utils.WrapError(fmt.Errorf("sign: tx %v failed", tx.Hash().String()), err)
+	}
+	t.logger.Info("Transaction confirmed on the ledger device", "txHash", signedTx.Hash().Hex())
+
+	if err := t.ethClient.SendTransaction(ctx, signedTx); err != nil {
+		return "", utils.WrapError(fmt.Errorf("send: tx %v failed", tx.Hash().String()), err)
+	}
+	return signedTx.Hash().Hex(), nil
+}
+
+// getChainID returns the chain id of the eth client, which is fetched on the first call
+func (t *ledgerWallet) getChainID(ctx context.Context) (*big.Int, error) {
+	t.chainIDMu.Lock()
+	defer t.chainIDMu.Unlock()
+	if t.chainID == nil {
+		chainID, err := t.ethClient.ChainID(ctx)
+		if err != nil {
+			return nil, utils.WrapError("failed to get chain id", err)
+		}
+		t.chainID = chainID
+	}
+	return t.chainID, nil
+}
+
+func (t *ledgerWallet) GetTransactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error) {
+	txHash := common.HexToHash(txID)
+	return t.ethClient.TransactionReceipt(ctx, txHash)
+}
+
+func (t *ledgerWallet) SenderAddress(ctx context.Context) (common.Address, error) {
+	return t.address, nil
+}
+
+// encodedPath returns the derivation path of the wallet, as sent in the APDUs: the number of indexes followed by the
+// big endian indexes
+func (t *ledgerWallet) encodedPath() []byte {
+	path := make([]byte, 1+4*len(t.path))
+	path[0] = byte(len(t.path))
+	for i, component := range t.path {
+		binary.BigEndian.PutUint32(path[1+4*i:], component)
+	}
+	return path
+}
+
+// deriveAddress returns the address of the key at the derivation path of the wallet
+func (t *ledgerWallet) deriveAddress() (common.Address, error) {
+	// the reply is the length prefixed public key, followed by the length prefixed hex address
+	reply, err := t.exchange(ledgerOpRetrieveAddress, 0, 0, t.encodedPath())
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(reply) < 1 || len(reply) < 1+int(reply[0])+1 {
+		return common.Address{}, errors.New("invalid ledger address reply")
+	}
+	reply = reply[1+int(reply[0]):]
+	if int(reply[0]) != 2*common.AddressLength || len(reply) < 1+int(reply[0]) {
+		return common.Address{}, errors.New("invalid ledger address reply")
+	}
+	return common.HexToAddress(string(reply[1 : 1+int(reply[0])])), nil
+}
+
+// signTransaction signs tx on the device, which only returns once the user confirmed or rejected it
+func (t *ledgerWallet) signTransaction(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var (
+		txPayload []byte
+		err       error
+	)
+	switch tx.Type() {
+	case types.LegacyTxType:
+		// EIP-155 signing payload
+		txPayload, err = rlp.EncodeToBytes([]interface{}{
+			tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), chainID, uint(0), uint(0),
+		})
+	case types.DynamicFeeTxType:
+		if versionLess(t.version, ledgerMinTypedTxVersion) {
+			return nil, fmt.Errorf("ledger ethereum app %d.%d.%d does not support EIP-1559 transactions, upgrade it "+
+				"to %d.%d.%d or later", t.version[0], t.version[1], t.version[2],
+				ledgerMinTypedTxVersion[0], ledgerMinTypedTxVersion[1], ledgerMinTypedTxVersion[2])
+		}
+		txPayload, err = rlp.EncodeToBytes([]interface{}{
+			chainID, tx.Nonce(), tx.GasTipCap(), tx.GasFeeCap(), tx.Gas(), tx.To(), tx.Value(), tx.Data(),
+			tx.AccessList(),
+		})
+		txPayload = append([]byte{types.DynamicFeeTxType}, txPayload...)
+	default:
+		return nil, fmt.Errorf("transactions of type %d are not supported by the ledger wallet", tx.Type())
+	}
+	if err != nil {
+		return nil, utils.WrapError("failed to encode transaction", err)
+	}
+
+	// the transaction is streamed in chunks following the derivation path, the device replying to the last one
+	payload := append(t.encodedPath(), txPayload...)
+	// chunk sizes leaving less than 4 bytes in the last chunk are avoided, as the app fails to parse them
+	// https://github.com/LedgerHQ/app-ethereum/issues/409
+	chunk := 255
+	for ; len(payload)%chunk <= 3; chunk-- {
+	}
+	var reply []byte
+	p1 := byte(ledgerP1InitTransactionData)
+	for len(payload) > 0 {
+		size := min(chunk, len(payload))
+		if reply, err = t.exchange(ledgerOpSignTransaction, p1, 0, payload[:size]); err != nil {
+			return nil, err
+		}
+		payload = payload[size:]
+		p1 = ledgerP1ContTransactionData
+	}
+	if len(reply) != crypto.SignatureLength {
+		return nil, errors.New("invalid ledger signature reply")
+	}
+
+	// the reply is V || R || S, with V being the EIP-155 V of legacy transactions truncated to a byte, and the
+	// parity of typed transactions (27 + parity with older app versions)
+	v := reply[0]
+	if tx.Type() == types.LegacyTxType {
+		v -= byte(chainID.Uint64()*2 + 35)
+	} else if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("invalid ledger signature recovery id %d", reply[0])
+	}
+	signature := append(common.CopyBytes(reply[1:]), v)
+	signedTx, err := tx.WithSignature(types.LatestSignerForChainID(chainID), signature)
+	if err != nil {
+		return nil, utils.WrapError("failed to set ledger signature", err)
+	}
+	signer, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		return nil, utils.WrapError("failed to recover signer of signed transaction", err)
+	}
+	if signer != t.address {
+		return nil, fmt.Errorf("transaction signed by %s instead of %s", signer.Hex(), t.address.Hex())
+	}
+	return signedTx, nil
+}
+
+// exchange sends an APDU to the device and returns its reply, without the status word which is mapped to the typed
+// errors. The APDU is framed like go-ethereum's usbwallet: prefixed with its big endian length, and split in 64 bytes
+// HID reports with a channel, tag and sequence number header.
+func (t *ledgerWallet) exchange(op, p1, p2 byte, data []byte) ([]byte, error) {
+	t.deviceMu.Lock()
+	defer t.deviceMu.Unlock()
+
+	apdu := make([]byte, 2, 7+len(data))
+	binary.BigEndian.PutUint16(apdu, uint16(5+len(data)))
+	apdu = append(apdu, 0xe0, op, p1, p2, byte(len(data)))
+	apdu = append(apdu, data...)
+
+	header := []byte{0x01, 0x01, 0x05, 0x00, 0x00}
+	report := make([]byte, 64)
+	space := len(report) - len(header)
+	for seq := 0; len(apdu) > 0; seq++ {
+		binary.BigEndian.PutUint16(header[3:], uint16(seq))
+		size := min(space, len(apdu))
+		clear(report)
+		copy(report, header)
+		copy(report[len(header):], apdu[:size])
+		if _, err := t.device.Write(report); err != nil {
+			return nil, utils.WrapError("failed to write to ledger device", err)
+		}
+		apdu = apdu[size:]
+	}
+
+	var reply []byte
+	for {
+		if _, err := io.ReadFull(t.device, report); err != nil {
+			return nil, utils.WrapError("failed to read from ledger device", err)
+		}
+		if report[0] != 0x01 || report[1] != 0x01 || report[2] != 0x05 {
+			return nil, errors.New("invalid ledger reply header")
+		}
+		var payload []byte
+		if report[3] == 0x00 && report[4] == 0x00 {
+			reply = make([]byte, 0, int(binary.BigEndian.Uint16(report[5:7])))
+			payload = report[7:]
+		} else {
+			payload = report[5:]
+		}
+		if left := cap(reply) - len(reply); left > len(payload) {
+			reply = append(reply, payload...)
+		} else {
+			reply = append(reply, payload[:left]...)
+			break
+		}
+	}
+	if len(reply) < 2 {
+		return nil, errors.New("ledger reply lacks status word")
+	}
+	status := binary.BigEndian.Uint16(reply[len(reply)-2:])
+	if err := ledgerStatusError(status); err != nil {
+		return nil, err
+	}
+	return reply[:len(reply)-2], nil
+}
+
+// ledgerStatusError maps a status word of the device to a typed error, or nil for success
+func ledgerStatusError(status uint16) error {
+	switch status {
+	case ledgerStatusOK:
+		return nil
+	case ledgerStatusDeviceLocked, ledgerStatusSecurityNotSatisfy:
+		return fmt.Errorf("%w (status %#04x): unlock it with its PIN", ErrLedgerDeviceLocked, status)
+	case ledgerStatusAppNotOpen, ledgerStatusInsNotSupported, ledgerStatusClaNotSupported:
+		return fmt.Errorf("%w (status %#04x): open it on the device", ErrLedgerAppClosed, status)
+	case ledgerStatusUserRejected:
+		return fmt.Errorf("%w (status %#04x)", ErrLedgerUserRejected, status)
+	}
+	return fmt.Errorf("ledger device returned status %#04x", status)
+}
+
+// versionLess returns whether the app version a is older than b
+func versionLess(a, b [3]byte) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLedgerDevice emulates the Ethereum app of a Ledger device signing with its key, behind the HID framing
+type fakeLedgerDevice struct {
+	t       *testing.T
+	key     *ecdsa.PrivateKey
+	version [3]byte
+	// status is returned in place of the reply of every APDU if set, e.g. to emulate a locked device
+	status uint16
+	// rejectTxs makes the user reject the transactions
+	rejectTxs bool
+
+	request   []byte
+	txPayload []byte
+	replies   bytes.Buffer
+	// signChunks is the number of APDUs of the last signed transaction
+	signChunks int
+}
+
+func newFakeLedgerDevice(t *testing.T) *fakeLedgerDevice {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &fakeLedgerDevice{t: t, key: key, version: [3]byte{1, 10, 3}}
+}
+
+func (d *fakeLedgerDevice) address() common.Address {
+	return crypto.PubkeyToAddress(d.key.PublicKey)
+}
+
+func (d *fakeLedgerDevice) Write(report []byte) (int, error) {
+	require.Len(d.t, report, 64)
+	require.Equal(d.t, []byte{0x01, 0x01, 0x05}, report[:3])
+	if binary.BigEndian.Uint16(report[3:5]) == 0 {
+		d.request = nil
+	}
+	d.request = append(d.request, report[5:]...)
+	size := int(binary.BigEndian.Uint16(d.request[:2]))
+	if len(d.request)-2 < size {
+		return len(report), nil
+	}
+	reply, status := d.handle(d.request[2 : 2+size])
+	d.writeReply(binary.BigEndian.AppendUint16(reply, status))
+	return len(report), nil
+}
+
+func (d *fakeLedgerDevice) Read(report []byte) (int, error) {
+	return d.replies.Read(report)
+}
+
+// writeReply frames reply in HID reports, the first one starting with the length of the reply
+func (d *fakeLedgerDevice) writeReply(reply []byte) {
+	reply = append(binary.BigEndian.AppendUint16(nil, uint16(len(reply))), reply...)
+	for seq := 0; len(reply) > 0; seq++ {
+		report := make([]byte, 64)
+		copy(report, []byte{0x01, 0x01, 0x05})
+		binary.BigEndian.PutUint16(report[3:5], uint16(seq))
+		size := copy(report[5:], reply)
+		reply = reply[size:]
+		d.replies.Write(report)
+	}
+}
+
+func (d *fakeLedgerDevice) handle(apdu []byte) ([]byte, uint16) {
+	require.Equal(d.t, byte(0xe0), apdu[0])
+	op, p1, data := apdu[1], apdu[2], apdu[5:]
+	require.Len(d.t, data, int(apdu[4]))
+	if d.status != 0 {
+		return nil, d.status
+	}
+
+	switch op {
+	case ledgerOpGetConfiguration:
+		return append([]byte{0x01}, d.version[:]...), ledgerStatusOK
+	case ledgerOpRetrieveAddress:
+		require.Equal(d.t, byte(5), data[0])
+		pubkey := crypto.FromECDSAPub(&d.key.PublicKey)
+		address := d.address().Hex()[2:]
+		reply := append([]byte{byte(len(pubkey))}, pubkey...)
+		reply = append(reply, byte(len(address)))
+		return append(reply, address...), ledgerStatusOK
+	case ledgerOpSignTransaction:
+		if p1 == ledgerP1InitTransactionData {
+			require.Equal(d.t, byte(5), data[0])
+			d.txPayload = append([]byte{}, data[1+4*5:]...)
+			d.signChunks = 0
+		} else {
+			require.Equal(d.t, byte(ledgerP1ContTransactionData), p1)
+			d.txPayload = append(d.txPayload, data...)
+		}
+		d.signChunks++
+		// the app replies once the whole rlp list of the transaction is received
+		list := d.txPayload
+		typed := list[0] < 0xc0
+		if typed {
+			list = list[1:]
+		}
+		if _, _, err := rlp.SplitList(list); err != nil {
+			return nil, ledgerStatusOK
+		}
+		if d.rejectTxs {
+			return nil, ledgerStatusUserRejected
+		}
+		signature, err := crypto.Sign(crypto.Keccak256(d.txPayload), d.key)
+		require.NoError(d.t, err)
+		v := signature[64]
+		if !typed {
+			v += byte(chainId.Uint64()*2 + 35)
+		}
+		return append([]byte{v}, signature[:64]...), ledgerStatusOK
+	}
+	return nil, ledgerStatusInsNotSupported
+}
+
+func TestLedgerWallet(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	derivationPath := "m/44'/60'/0'/0/1"
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	legacyTx := types.NewTx(&types.LegacyTx{
+		Nonce:    3,
+		GasPrice: big.NewInt(20_000_000_000),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1),
+	})
+	dynamicFeeTx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     4,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       500_000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		// streamed in several APDUs
+		Data: bytes.Repeat([]byte{0xca, 0xfe}, 300),
+	})
+
+	t.Run("Signs legacy and EIP-1559 transactions on the device", func(t *testing.T) {
+		device := newFakeLedgerDevice(t)
+		backend := &fakeKeystoreEthBackend{}
+		ledgerWallet, err := newLedgerWallet(device, derivationPath, backend, logger)
+		require.NoError(t, err)
+		sender, err := ledgerWallet.SenderAddress(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, device.address(), sender)
+
+		for _, tx := range []*types.Transaction{legacyTx, dynamicFeeTx} {
+			txID, err := ledgerWallet.SendTransaction(context.Background(), tx)
+			require.NoError(t, err)
+			signedTx := backend.sentTxs[len(backend.sentTxs)-1]
+			require.Equal(t, signedTx.Hash().Hex(), txID)
+			require.Equal(t, tx.Type(), signedTx.Type())
+			from, err := types.Sender(types.LatestSignerForChainID(chainId), signedTx)
+			require.NoError(t, err)
+			require.Equal(t, device.address(), from)
+		}
+		require.Greater(t, device.signChunks, 1)
+	})
+
+	t.Run("Device errors are typed", func(t *testing.T) {
+		tests := []struct {
+			status      uint16
+			expectedErr error
+		}{
+			{status: ledgerStatusDeviceLocked, expectedErr: ErrLedgerDeviceLocked},
+			{status: ledgerStatusSecurityNotSatisfy, expectedErr: ErrLedgerDeviceLocked},
+			{status: ledgerStatusClaNotSupported, expectedErr: ErrLedgerAppClosed},
+			{status: ledgerStatusAppNotOpen, expectedErr: ErrLedgerAppClosed},
+		}
+		for _, tt := range tests {
+			device := newFakeLedgerDevice(t)
+			device.status = tt.status
+			_, err := newLedgerWallet(device, derivationPath, &fakeKeystoreEthBackend{}, logger)
+			require.ErrorIs(t, err, tt.expectedErr)
+		}
+	})
+
+	t.Run("Transaction rejected on the device is not broadcast", func(t *testing.T) {
+		device := newFakeLedgerDevice(t)
+		backend := &fakeKeystoreEthBackend{}
+		ledgerWallet, err := newLedgerWallet(device, derivationPath, backend, logger)
+		require.NoError(t, err)
+
+		device.rejectTxs = true
+		_, err = ledgerWallet.SendTransaction(context.Background(), dynamicFeeTx)
+		require.ErrorIs(t, err, ErrLedgerUserRejected)
+		require.Empty(t, backend.sentTxs)
+	})
+
+	t.Run("Old app versions only sign legacy transactions", func(t *testing.T) {
+		device := newFakeLedgerDevice(t)
+		device.version = [3]byte{1, 8, 5}
+		backend := &fakeKeystoreEthBackend{}
+		ledgerWallet, err := newLedgerWallet(device, derivationPath, backend, logger)
+		require.NoError(t, err)
+
+		_, err = ledgerWallet.SendTransaction(context.Background(), dynamicFeeTx)
+		require.ErrorContains(t, err, "does not support EIP-1559 transactions")
+		_, err = ledgerWallet.SendTransaction(context.Background(), legacyTx)
+		require.NoError(t, err)
+		require.Len(t, backend.sentTxs, 1)
+	})
+
+	t.Run("Invalid derivation path", func(t *testing.T) {
+		_, err := newLedgerWallet(newFakeLedgerDevice(t), "m/44'/60'/x", &fakeKeystoreEthBackend{}, logger)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,110 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoWalletForSender indicates that the sender selected with WithSenderAddress has no wallet in a MultiWallet
+var ErrNoWalletForSender = errors.New("no wallet for sender")
+
+// MultiWallet routes the transactions to the wallet of their sender, which is selected with WithSenderAddress, so
+// that a single tx manager can send the transactions of several keys, e.g. an operator key and a rewards claimer key
+// held by different wallets.
+type MultiWallet struct {
+	wallets map[common.Address]Wallet
+}
+
+var _ Wallet = (*MultiWallet)(nil)
+
+// NewMultiWallet returns a MultiWallet sending the transactions of each sender address of wallets with its wallet
+func NewMultiWallet(wallets map[common.Address]Wallet) (*MultiWallet, error) {
+	if len(wallets) == 0 {
+		return nil, errors.New("multi wallet needs at least one wallet")
+	}
+	w := &MultiWallet{
+		wallets: make(map[common.Address]Wallet, len(wallets)),
+	}
+	for sender, senderWallet := range wallets {
+		if senderWallet == nil {
+			return nil, fmt.Errorf("nil wallet for sender %s", sender.Hex())
+		}
+		w.wallets[sender] = senderWallet
+	}
+	return w, nil
+}
+
+// Senders returns the sender addresses the MultiWallet has a wallet for, sorted
+func (w *MultiWallet) Senders() []common.Address {
+	senders := make([]common.Address, 0, len(w.wallets))
+	for sender := range w.wallets {
+		senders = append(senders, sender)
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		return bytes.Compare(senders[i][:], senders[j][:]) < 0
+	})
+	return senders
+}
+
+// walletFor returns the wallet of the sender selected with WithSenderAddress
+func (w *MultiWallet) walletFor(ctx context.Context) (common.Address, Wallet, error) {
+	sender, ok := SenderAddressFromContext(ctx)
+	if !ok {
+		return common.Address{}, nil, errors.New(
+			"multi wallet sends for multiple addresses: select the sender with WithSenderAddress, see Senders")
+	}
+	senderWallet, ok := w.wallets[sender]
+	if !ok {
+		return common.Address{}, nil, fmt.Errorf("%w %s", ErrNoWalletForSender, sender.Hex())
+	}
+	return sender, senderWallet, nil
+}
+
+// SendTransaction sends tx with the wallet of the sender selected with WithSenderAddress
+func (w *MultiWallet) SendTransaction(ctx context.Context, tx *types.Transaction) (TxID, error) {
+	_, senderWallet, err := w.walletFor(ctx)
+	if err != nil {
+		return "", err
+	}
+	return senderWallet.SendTransaction(ctx, tx)
+}
+
+// GetTransactionReceipt returns the receipt of txID from the wallet of the sender selected with WithSenderAddress.
+// Without a selected sender, the wallets are asked in the order of Senders until one returns the receipt, so that no
+// state is kept per transaction: the returned error then joins the errors of all the wallets, and matches
+// ethereum.NotFound if the transaction wasn't mined yet.
+func (w *MultiWallet) GetTransactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error) {
+	if _, ok := SenderAddressFromContext(ctx); ok {
+		_, senderWallet, err := w.walletFor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return senderWallet.GetTransactionReceipt(ctx, txID)
+	}
+
+	var errs []error
+	for _, sender := range w.Senders() {
+		receipt, err := w.wallets[sender].GetTransactionReceipt(ctx, txID)
+		if err == nil {
+			return receipt, nil
+		}
+		errs = append(errs, fmt.Errorf("wallet of %s: %w", sender.Hex(), err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// SenderAddress returns the address selected with WithSenderAddress, which must have a wallet. It returns an error
+// without a selected sender, as the MultiWallet sends for all the addresses returned by Senders.
+func (w *MultiWallet) SenderAddress(ctx context.Context) (common.Address, error) {
+	_, senderWallet, err := w.walletFor(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return senderWallet.SenderAddress(ctx)
+}
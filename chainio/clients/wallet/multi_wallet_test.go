@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMinedEthBackend mines every transaction sent to it
+type fakeMinedEthBackend struct {
+	fakeKeystoreEthBackend
+}
+
+func (b *fakeMinedEthBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, tx := range b.sentTxs {
+		if tx.Hash() == txHash {
+			return &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful}, nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+func TestMultiWallet(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	backends := make(map[common.Address]*fakeMinedEthBackend)
+	wallets := make(map[common.Address]Wallet)
+	for i := 0; i < 2; i++ {
+		ecdsaSk, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signerFn, sender, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
+		require.NoError(t, err)
+		backends[sender] = &fakeMinedEthBackend{}
+		wallets[sender], err = NewPrivateKeyWallet(backends[sender], signerFn, sender, logger)
+		require.NoError(t, err)
+	}
+	multiWallet, err := NewMultiWallet(wallets)
+	require.NoError(t, err)
+	senders := multiWallet.Senders()
+	require.Len(t, senders, 2)
+	require.Negative(t, bytes.Compare(senders[0][:], senders[1][:]))
+
+	t.Run("Routes the transactions to the wallet of their sender", func(t *testing.T) {
+		for _, sender := range senders {
+			ctx := WithSenderAddress(context.Background(), sender)
+			address, err := multiWallet.SenderAddress(ctx)
+			require.NoError(t, err)
+			require.Equal(t, sender, address)
+
+			txID, err := multiWallet.SendTransaction(ctx, newKeystoreTestTx(sender))
+			require.NoError(t, err)
+			sentTxs := backends[sender].sentTxs
+			require.Equal(t, sentTxs[len(sentTxs)-1].Hash().Hex(), txID)
+			from, err := types.Sender(types.LatestSignerForChainID(chainId), sentTxs[len(sentTxs)-1])
+			require.NoError(t, err)
+			require.Equal(t, sender, from)
+
+			// the receipt is looked up from the wallet which sent the tx, with or without a selected sender
+			receipt, err := multiWallet.GetTransactionReceipt(ctx, txID)
+			require.NoError(t, err)
+			require.Equal(t, txID, receipt.TxHash.Hex())
+			receipt, err = multiWallet.GetTransactionReceipt(context.Background(), txID)
+			require.NoError(t, err)
+			require.Equal(t, txID, receipt.TxHash.Hex())
+		}
+	})
+
+	t.Run("Sender must be selected and have a wallet", func(t *testing.T) {
+		_, err := multiWallet.SenderAddress(context.Background())
+		require.ErrorContains(t, err, "WithSenderAddress")
+		_, err = multiWallet.SendTransaction(context.Background(), newKeystoreTestTx(senders[0]))
+		require.Error(t, err)
+
+		unknownSenderCtx := WithSenderAddress(context.Background(), common.Address{0x1})
+		_, err = multiWallet.SenderAddress(unknownSenderCtx)
+		require.ErrorIs(t, err, ErrNoWalletForSender)
+		_, err = multiWallet.SendTransaction(unknownSenderCtx, newKeystoreTestTx(senders[0]))
+		require.ErrorIs(t, err, ErrNoWalletForSender)
+		// a transaction unknown to all the wallets isn't mined yet
+		_, err = multiWallet.GetTransactionReceipt(context.Background(), common.Hash{0x1}.Hex())
+		require.ErrorIs(t, err, ethereum.NotFound)
+	})
+
+	t.Run("Needs wallets", func(t *testing.T) {
+		_, err := NewMultiWallet(nil)
+		require.Error(t, err)
+		_, err = NewMultiWallet(map[common.Address]Wallet{{0x1}: nil})
+		require.Error(t, err)
+	})
+}
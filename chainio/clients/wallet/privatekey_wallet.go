@@ -2,13 +2,17 @@ package wallet
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
 )
 
 type EthBackend interface {
@@ -16,30 +20,109 @@ type EthBackend interface {
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
+// PendingNonceBackend is the eth client a wallet seeds its local nonce counter from, see WithLocalNonces
+type PendingNonceBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceSyncer is implemented by the wallets setting the nonces of the transactions they send
+type NonceSyncer interface {
+	// SyncNonce resyncs the local nonce counter with the pending nonce of the sender, e.g. after transactions were
+	// sent by another process
+	SyncNonce(ctx context.Context) error
+}
+
+// PrivateKeyWalletOption configures the wallets created by NewPrivateKeyWallet
+type PrivateKeyWalletOption func(*privateKeyWallet)
+
+// WithLocalNonces makes the wallet set the nonce of every transaction it sends, overriding the nonce set by the
+// caller, from a local counter seeded with the pending nonce of the sender returned by client. The counter is
+// incremented on every successful broadcast, and resynced when the node returns a nonce too low/high error, in which
+// case the transaction is sent again once with the resynced nonce. Concurrent sends are serialized, so that they get
+// consecutive nonces.
+// It must not be used with the geometric txmgr, whose speed ups and cancellations reuse the nonce of the transaction
+// they replace, nor with a txmgr.NonceManager.
+func WithLocalNonces(client PendingNonceBackend) PrivateKeyWalletOption {
+	return func(w *privateKeyWallet) {
+		w.nonceClient = client
+	}
+}
+
 type privateKeyWallet struct {
 	ethClient EthBackend
 	address   common.Address
 	signerFn  signerv2.SignerFn
 	logger    logging.Logger
+
+	// nonceClient is set with WithLocalNonces, nil if the nonces of the transactions are kept
+	nonceClient PendingNonceBackend
+	// nonceMu is held while the transactions are signed and broadcast with the local nonces
+	nonceMu     sync.Mutex
+	nonceSynced bool
+	nextNonce   uint64
 }
 
 var _ Wallet = (*privateKeyWallet)(nil)
+var _ NonceSyncer = (*privateKeyWallet)(nil)
 
 func NewPrivateKeyWallet(
 	ethClient EthBackend,
 	signer signerv2.SignerFn,
 	signerAddress common.Address,
 	logger logging.Logger,
+	opts ...PrivateKeyWalletOption,
 ) (Wallet, error) {
-	return &privateKeyWallet{
+	w := &privateKeyWallet{
 		ethClient: ethClient,
 		address:   signerAddress,
 		signerFn:  signer,
 		logger:    logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
 }
 
 func (t *privateKeyWallet) SendTransaction(ctx context.Context, tx *types.Transaction) (TxID, error) {
+	if t.nonceClient == nil {
+		return t.signAndSend(ctx, tx)
+	}
+
+	t.nonceMu.Lock()
+	defer t.nonceMu.Unlock()
+	if !t.nonceSynced {
+		if err := t.syncNonce(ctx); err != nil {
+			return "", err
+		}
+	}
+	txID, err := t.sendWithLocalNonce(ctx, tx)
+	if err != nil && isNonceError(err) {
+		t.logger.Warn("Nonce error, resyncing the local nonce", "sender", t.address.Hex(), "nonce", t.nextNonce,
+			"err", err)
+		if syncErr := t.syncNonce(ctx); syncErr != nil {
+			return "", utils.WrapError(err, syncErr)
+		}
+		txID, err = t.sendWithLocalNonce(ctx, tx)
+	}
+	return txID, err
+}
+
+// sendWithLocalNonce sends tx with the next local nonce, which is incremented if it is broadcast
+func (t *privateKeyWallet) sendWithLocalNonce(ctx context.Context, tx *types.Transaction) (TxID, error) {
+	txWithNonce, err := withNonce(tx, t.nextNonce)
+	if err != nil {
+		return "", err
+	}
+	txID, err := t.signAndSend(ctx, txWithNonce)
+	if err != nil {
+		return "", err
+	}
+	t.nextNonce++
+	return txID, nil
+}
+
+func (t *privateKeyWallet) signAndSend(ctx context.Context, tx *types.Transaction) (TxID, error) {
 
 	t.logger.Debug("Getting signer for tx")
 	signer, err := t.signerFn(ctx, t.address)
@@ -52,6 +135,10 @@ func (t *privateKeyWallet) SendTransaction(ctx context.Context, tx *types.Transa
 	if err != nil {
 		return "", utils.WrapError(fmt.Errorf("sign: tx %v failed.", tx.Hash().String()), err)
 	}
+	// remote signers only return the signed tx, without the blobs which are broadcast along with it
+	if sidecar := tx.BlobTxSidecar(); sidecar != nil && signedTx.BlobTxSidecar() == nil {
+		signedTx = signedTx.WithBlobTxSidecar(sidecar)
+	}
 
 	err = t.ethClient.SendTransaction(ctx, signedTx)
 	if err != nil {
@@ -61,6 +148,76 @@ func (t *privateKeyWallet) SendTransaction(ctx context.Context, tx *types.Transa
 	return signedTx.Hash().Hex(), nil
 }
 
+// SyncNonce resyncs the local nonce counter with the pending nonce of the sender. It fails if the wallet wasn't
+// created with WithLocalNonces.
+func (t *privateKeyWallet) SyncNonce(ctx context.Context) error {
+	if t.nonceClient == nil {
+		return errors.New("local nonces are not enabled, see WithLocalNonces")
+	}
+	t.nonceMu.Lock()
+	defer t.nonceMu.Unlock()
+	return t.syncNonce(ctx)
+}
+
+func (t *privateKeyWallet) syncNonce(ctx context.Context) error {
+	pendingNonce, err := t.nonceClient.PendingNonceAt(ctx, t.address)
+	if err != nil {
+		return utils.WrapError("failed to get pending nonce", err)
+	}
+	t.nextNonce = pendingNonce
+	t.nonceSynced = true
+	return nil
+}
+
+// isNonceError returns whether err was returned by the node because the nonce of the tx was already used or too high
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "nonce too high")
+}
+
+// withNonce returns tx with the given nonce, keeping its type and fees
+func withNonce(tx *types.Transaction, nonce uint64) (*types.Transaction, error) {
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: tx.GasPrice(),
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}), nil
+	case types.DynamicFeeTxType:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      nonce,
+			GasTipCap:  tx.GasTipCap(),
+			GasFeeCap:  tx.GasFeeCap(),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}), nil
+	case types.BlobTxType:
+		return types.NewTx(&types.BlobTx{
+			ChainID:    uint256.MustFromBig(tx.ChainId()),
+			Nonce:      nonce,
+			GasTipCap:  uint256.MustFromBig(tx.GasTipCap()),
+			GasFeeCap:  uint256.MustFromBig(tx.GasFeeCap()),
+			Gas:        tx.Gas(),
+			To:         *tx.To(),
+			Value:      uint256.MustFromBig(tx.Value()),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+			BlobFeeCap: uint256.MustFromBig(tx.BlobGasFeeCap()),
+			BlobHashes: tx.BlobHashes(),
+			Sidecar:    tx.BlobTxSidecar(),
+		}), nil
+	}
+	return nil, fmt.Errorf("transactions of type %d are not supported", tx.Type())
+}
+
 func (t *privateKeyWallet) GetTransactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error) {
 	txHash := common.HexToHash(txID)
 	return t.ethClient.TransactionReceipt(ctx, txHash)
@@ -2,16 +2,21 @@ package wallet
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -68,3 +73,110 @@ func TestPrivateKeyWallet(t *testing.T) {
 		require.Equal(t, txId, receipt.TxHash.String())
 	})
 }
+
+// fakeNonceEthBackend mines the transactions sent with the pending nonce of the account, and rejects the others like
+// geth's txpool
+type fakeNonceEthBackend struct {
+	mu       sync.Mutex
+	nonce    uint64
+	receipts map[common.Hash]*types.Receipt
+}
+
+func (b *fakeNonceEthBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nonce, nil
+}
+
+func (b *fakeNonceEthBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case tx.Nonce() < b.nonce:
+		return errors.New("nonce too low")
+	case tx.Nonce() > b.nonce:
+		return errors.New("nonce too high")
+	}
+	b.nonce++
+	b.receipts[tx.Hash()] = &types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful}
+	return nil
+}
+
+func (b *fakeNonceEthBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	receipt, ok := b.receipts[txHash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func TestPrivateKeyWalletLocalNonces(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	ecdsaPrivKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signerV2, signerAddr, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaPrivKey}, chainId)
+	require.NoError(t, err)
+
+	t.Run("Concurrent sends get consecutive nonces", func(t *testing.T) {
+		n := 100
+		backend := &fakeNonceEthBackend{nonce: 3, receipts: make(map[common.Hash]*types.Receipt)}
+		skWallet, err := NewPrivateKeyWallet(backend, signerV2, signerAddr, logger, WithLocalNonces(backend))
+		require.NoError(t, err)
+
+		txIDs := make([]TxID, n)
+		g := new(errgroup.Group)
+		for i := 0; i < n; i++ {
+			i := i
+			g.Go(func() error {
+				// the callers all read the same pending nonce
+				txID, err := skWallet.SendTransaction(context.Background(), newKeystoreTestTx(signerAddr))
+				txIDs[i] = txID
+				return err
+			})
+		}
+		require.NoError(t, g.Wait())
+
+		for _, txID := range txIDs {
+			receipt, err := skWallet.GetTransactionReceipt(context.Background(), txID)
+			require.NoError(t, err)
+			require.Equal(t, txID, receipt.TxHash.Hex())
+		}
+		require.Equal(t, uint64(3+n), backend.nonce)
+	})
+
+	t.Run("Resyncs the nonce on nonce errors and with SyncNonce", func(t *testing.T) {
+		backend := &fakeNonceEthBackend{receipts: make(map[common.Hash]*types.Receipt)}
+		skWallet, err := NewPrivateKeyWallet(backend, signerV2, signerAddr, logger, WithLocalNonces(backend))
+		require.NoError(t, err)
+		_, err = skWallet.SendTransaction(context.Background(), newKeystoreTestTx(signerAddr))
+		require.NoError(t, err)
+
+		// another process sent 2 txs
+		backend.mu.Lock()
+		backend.nonce += 2
+		backend.mu.Unlock()
+		_, err = skWallet.SendTransaction(context.Background(), newKeystoreTestTx(signerAddr))
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), backend.nonce)
+
+		// the local nonce is ahead of the pending nonce once the txs were dropped by the node
+		backend.mu.Lock()
+		backend.nonce = 1
+		backend.mu.Unlock()
+		require.NoError(t, skWallet.(NonceSyncer).SyncNonce(context.Background()))
+		_, err = skWallet.SendTransaction(context.Background(), newKeystoreTestTx(signerAddr))
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), backend.nonce)
+	})
+
+	t.Run("Nonces set by the caller are kept by default", func(t *testing.T) {
+		backend := &fakeNonceEthBackend{nonce: 5, receipts: make(map[common.Hash]*types.Receipt)}
+		skWallet, err := NewPrivateKeyWallet(backend, signerV2, signerAddr, logger)
+		require.NoError(t, err)
+		_, err = skWallet.SendTransaction(context.Background(), newKeystoreTestTx(signerAddr))
+		require.ErrorContains(t, err, "nonce too low")
+		require.Error(t, skWallet.(NonceSyncer).SyncNonce(context.Background()))
+	})
+}
@@ -18,6 +18,22 @@ type TxID = string
 type Wallet interface {
 	SendTransaction(ctx context.Context, tx *types.Transaction) (TxID, error)
 	GetTransactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error)
-	// SenderAddress returns the address of the wallet
+	// SenderAddress returns the address of the wallet, or the address selected with WithSenderAddress for the wallets
+	// signing for multiple addresses
 	SenderAddress(ctx context.Context) (common.Address, error)
 }
+
+type senderAddressKey struct{}
+
+// WithSenderAddress returns a context selecting sender as the sender of the transactions sent with it, for the wallets
+// signing for multiple addresses. The tx managers pass it down to their wallet, so that a single tx manager can send
+// transactions from several addresses. Wallets signing for a single address ignore it.
+func WithSenderAddress(ctx context.Context, sender common.Address) context.Context {
+	return context.WithValue(ctx, senderAddressKey{}, sender)
+}
+
+// SenderAddressFromContext returns the sender set with WithSenderAddress, if any
+func SenderAddressFromContext(ctx context.Context) (common.Address, bool) {
+	sender, ok := ctx.Value(senderAddressKey{}).(common.Address)
+	return sender, ok
+}
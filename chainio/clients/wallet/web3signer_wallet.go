@@ -0,0 +1,184 @@
+package wallet
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultWeb3SignerTimeout is the timeout of the requests made to web3signer unless set with WithWeb3SignerTimeout
+const DefaultWeb3SignerTimeout = 10 * time.Second
+
+var (
+	// ErrWeb3SignerKeyLocked indicates that web3signer has no unlocked signing key for the sender
+	ErrWeb3SignerKeyLocked = errors.New("web3signer signing key is locked")
+	// ErrWeb3SignerUnknownAccount indicates that the sender is not an account known by web3signer
+	ErrWeb3SignerUnknownAccount = errors.New("web3signer account is unknown")
+)
+
+// Web3SignerOption configures the connection of the wallets created by NewWeb3SignerWallet
+type Web3SignerOption func(*web3SignerOptions)
+
+type web3SignerOptions struct {
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+// WithWeb3SignerTLSConfig sets the TLS config of the connection to web3signer, e.g. its CA and client certificates
+func WithWeb3SignerTLSConfig(tlsConfig *tls.Config) Web3SignerOption {
+	return func(o *web3SignerOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithWeb3SignerTimeout sets the timeout of each request made to web3signer
+func WithWeb3SignerTimeout(timeout time.Duration) Web3SignerOption {
+	return func(o *web3SignerOptions) {
+		o.timeout = timeout
+	}
+}
+
+// web3SignerTxArgs are the params of eth_signTransaction
+// Reference: https://docs.web3signer.consensys.io/reference/api/json-rpc#eth_signtransaction
+type web3SignerTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+}
+
+type web3SignerWallet struct {
+	signer    *rpc.Client
+	ethClient EthBackend
+	address   common.Address
+	logger    logging.Logger
+}
+
+var _ Wallet = (*web3SignerWallet)(nil)
+
+// NewWeb3SignerWallet returns a wallet signing the transactions of fromAddress with the eth_signTransaction method
+// of the Consensys Web3Signer at endpoint, and broadcasting them with client. The private key of fromAddress never
+// leaves web3signer.
+func NewWeb3SignerWallet(
+	endpoint string,
+	fromAddress common.Address,
+	client EthBackend,
+	logger logging.Logger,
+	opts ...Web3SignerOption,
+) (Wallet, error) {
+	o := &web3SignerOptions{timeout: DefaultWeb3SignerTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+	httpClient := &http.Client{Timeout: o.timeout}
+	if o.tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = o.tlsConfig
+		httpClient.Transport = transport
+	}
+	signer, err := rpc.DialOptions(context.Background(), endpoint, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, utils.WrapError("failed to dial web3signer", err)
+	}
+	return &web3SignerWallet{
+		signer:    signer,
+		ethClient: client,
+		address:   fromAddress,
+		logger:    logger,
+	}, nil
+}
+
+func (t *web3SignerWallet) SendTransaction(ctx context.Context, tx *types.Transaction) (TxID, error) {
+	t.logger.Debug("Signing transaction with web3signer", "sender", t.address.Hex(), "nonce", tx.Nonce())
+	signedTx, err := t.signTransaction(ctx, tx)
+	if err != nil {
+		return "", utils.WrapError(fmt.Errorf("sign: tx %v failed", tx.Hash().String()), err)
+	}
+
+	t.logger.Debug("Sending transaction", "txHash", signedTx.Hash().Hex())
+	if err := t.ethClient.SendTransaction(ctx, signedTx); err != nil {
+		return "", utils.WrapError(fmt.Errorf("send: tx %v failed", tx.Hash().String()), err)
+	}
+	return signedTx.Hash().Hex(), nil
+}
+
+// signTransaction returns tx signed by web3signer, checking that it was signed by the key of the wallet
+func (t *web3SignerWallet) signTransaction(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	if tx.Type() == types.BlobTxType {
+		return nil, errors.New("blob transactions are not supported by web3signer")
+	}
+	args := web3SignerTxArgs{
+		From:  t.address,
+		To:    tx.To(),
+		Gas:   hexutil.Uint64(tx.Gas()),
+		Nonce: hexutil.Uint64(tx.Nonce()),
+		Value: (*hexutil.Big)(tx.Value()),
+		Data:  tx.Data(),
+	}
+	// web3signer signs a legacy tx unless the dynamic fees are set
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var rawTx hexutil.Bytes
+	if err := t.signer.CallContext(ctx, &rawTx, "eth_signTransaction", args); err != nil {
+		return nil, web3SignerError(err)
+	}
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(rawTx); err != nil {
+		return nil, utils.WrapError("failed to decode signed transaction", err)
+	}
+	signer, err := types.Sender(types.LatestSignerForChainID(signedTx.ChainId()), signedTx)
+	if err != nil {
+		return nil, utils.WrapError("failed to recover signer of signed transaction", err)
+	}
+	if signer != t.address {
+		return nil, fmt.Errorf("transaction signed by %s instead of %s", signer.Hex(), t.address.Hex())
+	}
+	return signedTx, nil
+}
+
+// web3SignerError maps the json-rpc errors returned by web3signer for the sender's key to the typed errors
+func web3SignerError(err error) error {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return utils.WrapError("failed to call web3signer", err)
+	}
+	message := strings.ToLower(rpcErr.Error())
+	switch {
+	case strings.Contains(message, "unknown account"):
+		return utils.WrapError(ErrWeb3SignerUnknownAccount, err)
+	// e.g. "Signing from address is not an unlocked account"
+	case strings.Contains(message, "locked"):
+		return utils.WrapError(ErrWeb3SignerKeyLocked, err)
+	}
+	return utils.WrapError("web3signer error", err)
+}
+
+func (t *web3SignerWallet) GetTransactionReceipt(ctx context.Context, txID TxID) (*types.Receipt, error) {
+	txHash := common.HexToHash(txID)
+	return t.ethClient.TransactionReceipt(ctx, txHash)
+}
+
+func (t *web3SignerWallet) SenderAddress(ctx context.Context) (common.Address, error) {
+	return t.address, nil
+}
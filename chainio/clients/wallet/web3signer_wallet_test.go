@@ -0,0 +1,178 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWeb3Signer serves eth_signTransaction, signing with its key and recording the params of the requests
+type fakeWeb3Signer struct {
+	t        *testing.T
+	signer   func(tx *types.Transaction) (*types.Transaction, error)
+	rpcError string
+	delay    time.Duration
+	params   []json.RawMessage
+}
+
+func (s *fakeWeb3Signer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(s.delay)
+	body, err := io.ReadAll(r.Body)
+	require.NoError(s.t, err)
+	var request struct {
+		JsonRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+	}
+	require.NoError(s.t, json.Unmarshal(body, &request))
+	require.Equal(s.t, "2.0", request.JsonRPC)
+	require.Equal(s.t, "eth_signTransaction", request.Method)
+	s.params = append(s.params, request.Params)
+
+	w.Header().Set("Content-Type", "application/json")
+	if s.rpcError != "" {
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":%q}}`, request.ID, s.rpcError)
+		return
+	}
+	var args []web3SignerTxArgs
+	require.NoError(s.t, json.Unmarshal(request.Params, &args))
+	require.Len(s.t, args, 1)
+	signedTx, err := s.signer(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     uint64(args[0].Nonce),
+		GasTipCap: args[0].MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args[0].MaxFeePerGas.ToInt(),
+		Gas:       uint64(args[0].Gas),
+		To:        args[0].To,
+		Value:     args[0].Value.ToInt(),
+		Data:      args[0].Data,
+	}))
+	require.NoError(s.t, err)
+	rawTx, err := signedTx.MarshalBinary()
+	require.NoError(s.t, err)
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%s"}`, request.ID, hexutil.Encode(rawTx))
+}
+
+func TestWeb3SignerWallet(t *testing.T) {
+	logger := testutils.NewTestLogger()
+	ecdsaSk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(ecdsaSk.PublicKey)
+	signWithKey := func(tx *types.Transaction) (*types.Transaction, error) {
+		return types.SignTx(tx, types.LatestSignerForChainID(chainId), ecdsaSk)
+	}
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       50_000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Data:      []byte{0xca, 0xfe},
+	})
+
+	t.Run("Signs with eth_signTransaction and broadcasts the signed tx", func(t *testing.T) {
+		web3Signer := &fakeWeb3Signer{t: t, signer: signWithKey}
+		server := httptest.NewServer(web3Signer)
+		defer server.Close()
+		backend := &fakeKeystoreEthBackend{}
+
+		w3sWallet, err := NewWeb3SignerWallet(server.URL, sender, backend, logger)
+		require.NoError(t, err)
+		txID, err := w3sWallet.SendTransaction(context.Background(), tx)
+		require.NoError(t, err)
+
+		require.Len(t, web3Signer.params, 1)
+		expectedParams := fmt.Sprintf(`[{"from":"%s","to":"%s","gas":"0xc350","maxFeePerGas":"0x6fc23ac00",`+
+			`"maxPriorityFeePerGas":"0x3b9aca00","nonce":"0x7","value":"0x1","data":"0xcafe"}]`,
+			strings.ToLower(sender.Hex()), strings.ToLower(to.Hex()))
+		require.JSONEq(t, expectedParams, string(web3Signer.params[0]))
+		require.Len(t, backend.sentTxs, 1)
+		require.Equal(t, backend.sentTxs[0].Hash().Hex(), txID)
+		from, err := types.Sender(types.LatestSignerForChainID(chainId), backend.sentTxs[0])
+		require.NoError(t, err)
+		require.Equal(t, sender, from)
+	})
+
+	t.Run("Signer errors are typed", func(t *testing.T) {
+		tests := []struct {
+			rpcError    string
+			expectedErr error
+		}{
+			{rpcError: "Signing from address is not an unlocked account", expectedErr: ErrWeb3SignerKeyLocked},
+			{rpcError: "unknown account", expectedErr: ErrWeb3SignerUnknownAccount},
+		}
+		for _, tt := range tests {
+			server := httptest.NewServer(&fakeWeb3Signer{t: t, rpcError: tt.rpcError})
+			backend := &fakeKeystoreEthBackend{}
+			w3sWallet, err := NewWeb3SignerWallet(server.URL, sender, backend, logger)
+			require.NoError(t, err)
+
+			_, err = w3sWallet.SendTransaction(context.Background(), tx)
+			require.ErrorIs(t, err, tt.expectedErr)
+			require.ErrorContains(t, err, tt.rpcError)
+			require.Empty(t, backend.sentTxs)
+			server.Close()
+		}
+	})
+
+	t.Run("Tx signed by another key is not broadcast", func(t *testing.T) {
+		otherSk, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signWithOtherKey := func(tx *types.Transaction) (*types.Transaction, error) {
+			return types.SignTx(tx, types.LatestSignerForChainID(chainId), otherSk)
+		}
+		server := httptest.NewServer(&fakeWeb3Signer{t: t, signer: signWithOtherKey})
+		defer server.Close()
+		backend := &fakeKeystoreEthBackend{}
+
+		w3sWallet, err := NewWeb3SignerWallet(server.URL, sender, backend, logger)
+		require.NoError(t, err)
+		_, err = w3sWallet.SendTransaction(context.Background(), tx)
+		require.Error(t, err)
+		require.Empty(t, backend.sentTxs)
+	})
+
+	t.Run("TLS config and request timeout", func(t *testing.T) {
+		web3Signer := &fakeWeb3Signer{t: t, signer: signWithKey}
+		server := httptest.NewTLSServer(web3Signer)
+		defer server.Close()
+		tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+
+		// the certificate of the server is not trusted without its TLS config
+		w3sWallet, err := NewWeb3SignerWallet(server.URL, sender, &fakeKeystoreEthBackend{}, logger)
+		require.NoError(t, err)
+		_, err = w3sWallet.SendTransaction(context.Background(), tx)
+		require.Error(t, err)
+
+		w3sWallet, err = NewWeb3SignerWallet(server.URL, sender, &fakeKeystoreEthBackend{}, logger,
+			WithWeb3SignerTLSConfig(tlsConfig))
+		require.NoError(t, err)
+		_, err = w3sWallet.SendTransaction(context.Background(), tx)
+		require.NoError(t, err)
+
+		web3Signer.delay = 200 * time.Millisecond
+		w3sWallet, err = NewWeb3SignerWallet(server.URL, sender, &fakeKeystoreEthBackend{}, logger,
+			WithWeb3SignerTLSConfig(tlsConfig), WithWeb3SignerTimeout(50*time.Millisecond))
+		require.NoError(t, err)
+		_, err = w3sWallet.SendTransaction(context.Background(), tx)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package txmgr
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/holiman/uint256"
+)
+
+// ErrBlobTxUnsupported is returned when sending a blob transaction to a chain which hasn't activated EIP-4844
+var ErrBlobTxUnsupported = errors.New("blob transactions are not supported by the chain")
+
+// BlobTxPriceBumpPercentage is the minimum percentage by which all the fees of a blob transaction (gas tip cap, gas fee
+// cap and blob gas fee cap) must be increased to replace it in the mempool. This is the default of geth's
+// blobpool.pricebump.
+const BlobTxPriceBumpPercentage = 100
+
+// NewBlobTxCandidate returns an unsigned blob transaction carrying blobs, to be sent by a TxManager.
+// The TxManager sets its nonce, gas limit and fees, and computes the commitments and proofs of the blobs.
+func NewBlobTxCandidate(chainID *big.Int, to common.Address, data []byte, blobs []kzg4844.Blob) *types.Transaction {
+	return types.NewTx(&types.BlobTx{
+		ChainID: uint256.MustFromBig(chainID),
+		To:      to,
+		Value:   new(uint256.Int),
+		Data:    data,
+		Sidecar: &types.BlobTxSidecar{Blobs: blobs},
+	})
+}
+
+// EstimateBlobGasFeeCap returns the blob gas fee cap of a blob transaction sent on top of the block of header, which
+// is twice its blob base fee, as the gas fee cap is twice the base fee.
+// It returns ErrBlobTxUnsupported if the block doesn't have a blob base fee.
+func EstimateBlobGasFeeCap(header *types.Header) (*big.Int, error) {
+	if header.ExcessBlobGas == nil {
+		return nil, ErrBlobTxUnsupported
+	}
+	blobBaseFee := eip4844.CalcBlobFee(*header.ExcessBlobGas)
+	return blobBaseFee.Mul(blobBaseFee, big.NewInt(2)), nil
+}
+
+// NewTxWithFees returns the transaction tx with the given nonce, gas limit and fees.
+// Blob transactions stay blob transactions paying blobGasFeeCap, with the commitments and proofs of their blobs
+// computed if their sidecar only has blobs. All the other transactions are returned as dynamic fee transactions, and
+// blobGasFeeCap is ignored.
+func NewTxWithFees(
+	tx *types.Transaction,
+	nonce uint64,
+	gas uint64,
+	gasTipCap *big.Int,
+	gasFeeCap *big.Int,
+	blobGasFeeCap *big.Int,
+) (*types.Transaction, error) {
+	if tx.Type() != types.BlobTxType {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      nonce,
+			GasTipCap:  gasTipCap,
+			GasFeeCap:  gasFeeCap,
+			Gas:        gas,
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}), nil
+	}
+
+	if tx.ChainId() == nil || tx.To() == nil {
+		return nil, errors.New("blob transaction must have a chain id and a recipient")
+	}
+	sidecar, err := completeBlobTxSidecar(tx.BlobTxSidecar())
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTx(&types.BlobTx{
+		ChainID:    uint256.MustFromBig(tx.ChainId()),
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(gasTipCap),
+		GasFeeCap:  uint256.MustFromBig(gasFeeCap),
+		Gas:        gas,
+		To:         *tx.To(),
+		Value:      uint256.MustFromBig(tx.Value()),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+		BlobFeeCap: uint256.MustFromBig(blobGasFeeCap),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	}), nil
+}
+
+// completeBlobTxSidecar returns sidecar with the commitments and proofs of its blobs, computing them if they are
+// missing
+func completeBlobTxSidecar(sidecar *types.BlobTxSidecar) (*types.BlobTxSidecar, error) {
+	if sidecar == nil || len(sidecar.Blobs) == 0 {
+		return nil, errors.New("blob transaction has no blobs")
+	}
+	if len(sidecar.Commitments) == len(sidecar.Blobs) && len(sidecar.Proofs) == len(sidecar.Blobs) {
+		return sidecar, nil
+	}
+
+	completeSidecar := &types.BlobTxSidecar{
+		Blobs:       sidecar.Blobs,
+		Commitments: make([]kzg4844.Commitment, len(sidecar.Blobs)),
+		Proofs:      make([]kzg4844.Proof, len(sidecar.Blobs)),
+	}
+	for i := range sidecar.Blobs {
+		commitment, err := kzg4844.BlobToCommitment(&sidecar.Blobs[i])
+		if err != nil {
+			return nil, utils.WrapError("failed to compute blob commitment", err)
+		}
+		proof, err := kzg4844.ComputeBlobProof(&sidecar.Blobs[i], commitment)
+		if err != nil {
+			return nil, utils.WrapError("failed to compute blob proof", err)
+		}
+		completeSidecar.Commitments[i] = commitment
+		completeSidecar.Proofs[i] = proof
+	}
+	return completeSidecar, nil
+}
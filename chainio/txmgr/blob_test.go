@@ -0,0 +1,70 @@
+package txmgr_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTxWithFees(t *testing.T) {
+	gasTipCap, gasFeeCap, blobGasFeeCap := big.NewInt(1), big.NewInt(2), big.NewInt(3)
+
+	t.Run("dynamic fee tx", func(t *testing.T) {
+		candidate := types.NewTx(&types.DynamicFeeTx{To: &common.Address{0x1}, Value: big.NewInt(1), Data: []byte{0x2}})
+		tx, err := txmgr.NewTxWithFees(candidate, 5, 21000, gasTipCap, gasFeeCap, blobGasFeeCap)
+		require.NoError(t, err)
+		require.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+		require.Equal(t, uint64(5), tx.Nonce())
+		require.Equal(t, uint64(21000), tx.Gas())
+		require.Equal(t, gasTipCap, tx.GasTipCap())
+		require.Equal(t, gasFeeCap, tx.GasFeeCap())
+		require.Equal(t, candidate.To(), tx.To())
+		require.Equal(t, candidate.Value(), tx.Value())
+		require.Equal(t, candidate.Data(), tx.Data())
+		require.Nil(t, tx.BlobTxSidecar())
+	})
+
+	t.Run("blob tx", func(t *testing.T) {
+		blobs := []kzg4844.Blob{{0x1}, {0x2}}
+		candidate := txmgr.NewBlobTxCandidate(big.NewInt(31337), common.Address{0x1}, []byte{0x2}, blobs)
+		tx, err := txmgr.NewTxWithFees(candidate, 5, 21000, gasTipCap, gasFeeCap, blobGasFeeCap)
+		require.NoError(t, err)
+		require.Equal(t, uint8(types.BlobTxType), tx.Type())
+		require.Equal(t, uint64(5), tx.Nonce())
+		require.Equal(t, gasTipCap, tx.GasTipCap())
+		require.Equal(t, gasFeeCap, tx.GasFeeCap())
+		require.Equal(t, blobGasFeeCap, tx.BlobGasFeeCap())
+		require.Equal(t, big.NewInt(31337), tx.ChainId())
+
+		// the commitments and proofs of the blobs are computed
+		sidecar := tx.BlobTxSidecar()
+		require.NotNil(t, sidecar)
+		require.Len(t, sidecar.Commitments, len(blobs))
+		for i := range blobs {
+			require.NoError(t, kzg4844.VerifyBlobProof(&sidecar.Blobs[i], sidecar.Commitments[i], sidecar.Proofs[i]))
+		}
+		require.Equal(t, sidecar.BlobHashes(), tx.BlobHashes())
+	})
+
+	t.Run("blob tx without blobs", func(t *testing.T) {
+		candidate := txmgr.NewBlobTxCandidate(big.NewInt(31337), common.Address{0x1}, nil, nil)
+		_, err := txmgr.NewTxWithFees(candidate, 5, 21000, gasTipCap, gasFeeCap, blobGasFeeCap)
+		require.Error(t, err)
+	})
+}
+
+func TestEstimateBlobGasFeeCap(t *testing.T) {
+	_, err := txmgr.EstimateBlobGasFeeCap(&types.Header{})
+	require.ErrorIs(t, err, txmgr.ErrBlobTxUnsupported)
+
+	excessBlobGas := uint64(0)
+	blobGasFeeCap, err := txmgr.EstimateBlobGasFeeCap(&types.Header{ExcessBlobGas: &excessBlobGas})
+	require.NoError(t, err)
+	// twice the min blob base fee of 1 wei
+	require.Equal(t, big.NewInt(2), blobGasFeeCap)
+}
@@ -0,0 +1,109 @@
+package txmgr
+
+import (
+	"context"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultRelayFallbackTimeout is the time after which a transaction sent to a private relay and not included yet is
+// sent to the public mempool, about 10 blocks on mainnet
+const DefaultRelayFallbackTimeout = 2 * time.Minute
+
+// timeout of the requests made to send a transaction to the public mempool after the fallback timeout
+const relayFallbackRequestTimeout = 30 * time.Second
+
+// Broadcaster broadcasts signed transactions to the network. The tx managers send transactions through their wallet,
+// which broadcasts them with its eth client by default. A Broadcaster which is also a wallet.EthBackend, such as a
+// PrivateRelayBroadcaster, can be given to the wallet instead of the eth client, so that all the transactions sent
+// by the tx managers, including their speed ups and cancellations, go through it.
+type Broadcaster interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// PrivateRelayBroadcaster is a Broadcaster submitting the transactions to a private relay (e.g. Flashbots Protect)
+// with eth_sendRawTransaction, so that they are not front-run or sandwiched in the public mempool.
+// A transaction not included after the fallback timeout is sent to the public mempool with the public eth client,
+// which is also used to query the receipts.
+type PrivateRelayBroadcaster struct {
+	relay           *rpc.Client
+	public          wallet.EthBackend
+	logger          logging.Logger
+	fallbackTimeout time.Duration
+}
+
+var _ Broadcaster = (*PrivateRelayBroadcaster)(nil)
+var _ wallet.EthBackend = (*PrivateRelayBroadcaster)(nil)
+
+// NewPrivateRelayBroadcaster returns a PrivateRelayBroadcaster submitting the transactions to the json-rpc endpoint
+// relayUrl, and falling back to public after DefaultRelayFallbackTimeout
+func NewPrivateRelayBroadcaster(
+	relayUrl string,
+	public wallet.EthBackend,
+	logger logging.Logger,
+) (*PrivateRelayBroadcaster, error) {
+	relay, err := rpc.DialHTTP(relayUrl)
+	if err != nil {
+		return nil, utils.WrapError("failed to dial private relay", err)
+	}
+	return &PrivateRelayBroadcaster{
+		relay:           relay,
+		public:          public,
+		logger:          logger.With("component", "PrivateRelayBroadcaster"),
+		fallbackTimeout: DefaultRelayFallbackTimeout,
+	}, nil
+}
+
+// WithFallbackTimeout sets the time after which the transactions not included yet are sent to the public mempool.
+// A zero timeout disables the fallback.
+func (b *PrivateRelayBroadcaster) WithFallbackTimeout(timeout time.Duration) *PrivateRelayBroadcaster {
+	b.fallbackTimeout = timeout
+	return b
+}
+
+// SendTransaction submits tx to the private relay, and schedules its fallback to the public mempool
+func (b *PrivateRelayBroadcaster) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return utils.WrapError("failed to encode transaction", err)
+	}
+	var txHash common.Hash
+	if err := b.relay.CallContext(ctx, &txHash, "eth_sendRawTransaction", hexutil.Bytes(rawTx)); err != nil {
+		return utils.WrapError("failed to send transaction to private relay", err)
+	}
+	b.logger.Debug("sent transaction to private relay", "txHash", tx.Hash().Hex(), "nonce", tx.Nonce())
+
+	if b.fallbackTimeout > 0 {
+		time.AfterFunc(b.fallbackTimeout, func() { b.fallbackToPublicMempool(tx) })
+	}
+	return nil
+}
+
+// fallbackToPublicMempool sends tx to the public mempool unless it was included. The replaced transactions are sent
+// too, and are then rejected by the public mempool unless their replacement is still private.
+func (b *PrivateRelayBroadcaster) fallbackToPublicMempool(tx *types.Transaction) {
+	ctx, cancel := context.WithTimeout(context.Background(), relayFallbackRequestTimeout)
+	defer cancel()
+	if _, err := b.public.TransactionReceipt(ctx, tx.Hash()); err == nil {
+		return
+	}
+
+	b.logger.Info("transaction not included by private relay, sending it to the public mempool",
+		"txHash", tx.Hash().Hex(), "nonce", tx.Nonce(), "fallbackTimeout", b.fallbackTimeout)
+	if err := b.public.SendTransaction(ctx, tx); err != nil {
+		// e.g. the transaction was replaced or mined meanwhile
+		b.logger.Debug("failed to send transaction to the public mempool", "txHash", tx.Hash().Hex(), "err", err)
+	}
+}
+
+// TransactionReceipt returns the receipt of the transaction with txHash from the public eth client
+func (b *PrivateRelayBroadcaster) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return b.public.TransactionReceipt(ctx, txHash)
+}
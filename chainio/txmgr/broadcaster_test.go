@@ -0,0 +1,155 @@
+package txmgr_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRelayService serves eth_sendRawTransaction, recording the submitted transactions
+type fakeRelayService struct {
+	mu      sync.Mutex
+	err     error
+	sentTxs []*types.Transaction
+}
+
+func (s *fakeRelayService) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return common.Hash{}, s.err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	s.sentTxs = append(s.sentTxs, tx)
+	return tx.Hash(), nil
+}
+
+func (s *fakeRelayService) numSentTxs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sentTxs)
+}
+
+func newFakeRelay(t *testing.T) (*fakeRelayService, *httptest.Server) {
+	relay := &fakeRelayService{}
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", relay))
+	server := httptest.NewServer(rpcServer)
+	t.Cleanup(func() {
+		server.Close()
+		rpcServer.Stop()
+	})
+	return relay, server
+}
+
+// fakePublicBackend is the public mempool, whose receipts are those of the txs marked as mined
+type fakePublicBackend struct {
+	mu       sync.Mutex
+	sentTxs  []*types.Transaction
+	minedTxs map[common.Hash]struct{}
+}
+
+func (b *fakePublicBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sentTxs = append(b.sentTxs, tx)
+	return nil
+}
+
+func (b *fakePublicBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.minedTxs[txHash]; ok {
+		return &types.Receipt{TxHash: txHash, BlockNumber: big.NewInt(1)}, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (b *fakePublicBackend) numSentTxs() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.sentTxs)
+}
+
+func TestPrivateRelayBroadcaster(t *testing.T) {
+	ecdsaSk, addr, err := testutils.NewEcdsaSkAndAddress()
+	require.NoError(t, err)
+	chainId := big.NewInt(31337)
+	signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
+	require.NoError(t, err)
+	signer, err := signerFn(context.Background(), addr)
+	require.NoError(t, err)
+	signedTx, err := signer(addr, types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21_000,
+		To:        &common.Address{0x1},
+		Value:     big.NewInt(1),
+	}))
+	require.NoError(t, err)
+
+	t.Run("Included tx is only sent to the relay", func(t *testing.T) {
+		relay, relayServer := newFakeRelay(t)
+		public := &fakePublicBackend{minedTxs: make(map[common.Hash]struct{})}
+		broadcaster, err := txmgr.NewPrivateRelayBroadcaster(relayServer.URL, public, testutils.NewTestLogger())
+		require.NoError(t, err)
+		broadcaster.WithFallbackTimeout(100 * time.Millisecond)
+
+		require.NoError(t, broadcaster.SendTransaction(context.Background(), signedTx))
+		require.Equal(t, 1, relay.numSentTxs())
+		require.Equal(t, signedTx.Hash(), relay.sentTxs[0].Hash())
+		public.mu.Lock()
+		public.minedTxs[signedTx.Hash()] = struct{}{}
+		public.mu.Unlock()
+
+		time.Sleep(300 * time.Millisecond)
+		require.Equal(t, 0, public.numSentTxs())
+		receipt, err := broadcaster.TransactionReceipt(context.Background(), signedTx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, signedTx.Hash(), receipt.TxHash)
+	})
+
+	t.Run("Tx not included is sent to the public mempool after the fallback timeout", func(t *testing.T) {
+		relay, relayServer := newFakeRelay(t)
+		public := &fakePublicBackend{minedTxs: make(map[common.Hash]struct{})}
+		broadcaster, err := txmgr.NewPrivateRelayBroadcaster(relayServer.URL, public, testutils.NewTestLogger())
+		require.NoError(t, err)
+		broadcaster.WithFallbackTimeout(100 * time.Millisecond)
+
+		require.NoError(t, broadcaster.SendTransaction(context.Background(), signedTx))
+		require.Equal(t, 1, relay.numSentTxs())
+		require.Equal(t, 0, public.numSentTxs())
+		require.Eventually(t, func() bool { return public.numSentTxs() == 1 }, time.Second, 20*time.Millisecond)
+		require.Equal(t, signedTx.Hash(), public.sentTxs[0].Hash())
+	})
+
+	t.Run("Relay error is returned", func(t *testing.T) {
+		relay, relayServer := newFakeRelay(t)
+		relay.err = errors.New("relay unavailable")
+		public := &fakePublicBackend{minedTxs: make(map[common.Hash]struct{})}
+		broadcaster, err := txmgr.NewPrivateRelayBroadcaster(relayServer.URL, public, testutils.NewTestLogger())
+		require.NoError(t, err)
+
+		err = broadcaster.SendTransaction(context.Background(), signedTx)
+		require.ErrorContains(t, err, "relay unavailable")
+		require.Equal(t, 0, public.numSentTxs())
+	})
+}
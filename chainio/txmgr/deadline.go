@@ -0,0 +1,44 @@
+package txmgr
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrTxDeadlineExceeded is returned when the deadline of the context of a send passes before its transaction is
+// confirmed. The transaction was broadcast and may still be pending: its fields are enough to keep waiting for it, or
+// to cancel it by sending a self-transfer with the same nonce and a fee cap bumped from LastGasFeeCap (which the
+// GeometricTxManager does itself with AutoCancelOnDeadline).
+//
+// There is a race with the inclusion of the transaction: it can be mined right after the deadline, or even be mined
+// already without the tx manager having seen its receipt yet. The error is returned regardless, so callers must check
+// the chain (e.g. the receipt of TxHash, or the nonce of the sender) before assuming the transaction was not executed.
+//
+// It wraps context.DeadlineExceeded.
+type ErrTxDeadlineExceeded struct {
+	// TxID is the wallet id of the latest transaction sent
+	TxID wallet.TxID
+	// TxHash is the hash of the latest transaction sent, which is its TxID for the wallets using tx hashes as ids
+	TxHash common.Hash
+	Nonce  uint64
+	// LastGasFeeCap is the gas fee cap (or gas price for legacy transactions) of the latest transaction sent, which a
+	// replacement must bump
+	LastGasFeeCap *big.Int
+}
+
+func (e *ErrTxDeadlineExceeded) Error() string {
+	return fmt.Sprintf(
+		"deadline exceeded before transaction %s with nonce %d and gas fee cap %s was confirmed: it may still be mined",
+		e.TxHash.Hex(),
+		e.Nonce,
+		e.LastGasFeeCap,
+	)
+}
+
+func (e *ErrTxDeadlineExceeded) Unwrap() error {
+	return context.DeadlineExceeded
+}
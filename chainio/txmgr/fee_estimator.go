@@ -0,0 +1,165 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum"
+)
+
+// FeeEstimator estimates the EIP-1559 fees of the transactions sent by the SimpleTxManager.
+// It can be implemented to fully customize the fees paid.
+type FeeEstimator interface {
+	EstimateFees(ctx context.Context) (gasTipCap *big.Int, gasFeeCap *big.Int, err error)
+}
+
+// FeeHistoryBackend is the eth client needed by the EIP1559FeeEstimator
+type FeeHistoryBackend interface {
+	ethBackend
+	FeeHistory(
+		ctx context.Context,
+		blockCount uint64,
+		lastBlock *big.Int,
+		rewardPercentiles []float64,
+	) (*ethereum.FeeHistory, error)
+}
+
+// FeeEstimatorParams configures the EIP1559FeeEstimator.
+// If a parameter is not set (aka its zero value is present in the struct), the default value will be used.
+type FeeEstimatorParams struct {
+	// multiplier of the latest base fee in the gas fee cap: gasFeeCap = BaseFeeMultiplier * baseFee + gasTipCap
+	// default: 2, which keeps the tx includable for 6 consecutive 100% full blocks
+	// see https://www.blocknative.com/blog/eip-1559-fees
+	BaseFeeMultiplier float64
+	// percentile of the tips paid in the last FeeHistoryBlocks blocks used as gas tip cap, between 0 and 100.
+	// default: 0, meaning the tip suggested by the node (eth_maxPriorityFeePerGas) is used instead
+	TipPercentile float64
+	// number of blocks whose tips are averaged when TipPercentile is set
+	// default: 10
+	FeeHistoryBlocks uint64
+	// min gas tip cap, in wei
+	// default: nil (no min)
+	MinGasTipCap *big.Int
+	// max gas fee cap, in wei. The gas tip cap is also capped to it.
+	// default: nil (no max)
+	MaxGasFeeCap *big.Int
+}
+
+var defaultFeeEstimatorParams = FeeEstimatorParams{
+	BaseFeeMultiplier: 2,
+	FeeHistoryBlocks:  10,
+}
+
+// EIP1559FeeEstimator estimates the gas tip cap from the node (or from the recent blocks' tips), and the gas fee cap
+// from the latest base fee. With the default params, it estimates the fees the SimpleTxManager always used.
+type EIP1559FeeEstimator struct {
+	client ethBackend
+	// nil when TipPercentile is not used
+	feeHistoryClient FeeHistoryBackend
+	logger           logging.Logger
+	params           FeeEstimatorParams
+}
+
+var _ FeeEstimator = (*EIP1559FeeEstimator)(nil)
+
+func NewEIP1559FeeEstimator(
+	client FeeHistoryBackend,
+	logger logging.Logger,
+	params FeeEstimatorParams,
+) (*EIP1559FeeEstimator, error) {
+	if params.TipPercentile < 0 || params.TipPercentile > 100 {
+		return nil, errors.New("tip percentile must be between 0 and 100")
+	}
+	if params.BaseFeeMultiplier < 0 {
+		return nil, errors.New("base fee multiplier must be positive")
+	}
+	if params.BaseFeeMultiplier == 0 {
+		params.BaseFeeMultiplier = defaultFeeEstimatorParams.BaseFeeMultiplier
+	}
+	if params.FeeHistoryBlocks == 0 {
+		params.FeeHistoryBlocks = defaultFeeEstimatorParams.FeeHistoryBlocks
+	}
+	return &EIP1559FeeEstimator{
+		client:           client,
+		feeHistoryClient: client,
+		logger:           logger,
+		params:           params,
+	}, nil
+}
+
+// newDefaultFeeEstimator returns the estimator used by the SimpleTxManager when none is set, which doesn't need
+// eth_feeHistory
+func newDefaultFeeEstimator(client ethBackend, logger logging.Logger) *EIP1559FeeEstimator {
+	return &EIP1559FeeEstimator{
+		client: client,
+		logger: logger,
+		params: defaultFeeEstimatorParams,
+	}
+}
+
+func (e *EIP1559FeeEstimator) EstimateFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	gasTipCap, err := e.estimateGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e.params.MinGasTipCap != nil && gasTipCap.Cmp(e.params.MinGasTipCap) < 0 {
+		gasTipCap = new(big.Int).Set(e.params.MinGasTipCap)
+	}
+
+	header, err := e.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, utils.WrapError("failed to get latest header", err)
+	}
+	baseFeeCap, _ := new(big.Float).Mul(
+		new(big.Float).SetInt(header.BaseFee),
+		big.NewFloat(e.params.BaseFeeMultiplier),
+	).Int(nil)
+	gasFeeCap := new(big.Int).Add(baseFeeCap, gasTipCap)
+
+	if e.params.MaxGasFeeCap != nil && gasFeeCap.Cmp(e.params.MaxGasFeeCap) > 0 {
+		e.logger.Warn("estimated gas fee cap is above the max, capping it", "gasFeeCap", gasFeeCap,
+			"maxGasFeeCap", e.params.MaxGasFeeCap)
+		gasFeeCap = new(big.Int).Set(e.params.MaxGasFeeCap)
+		if gasTipCap.Cmp(gasFeeCap) > 0 {
+			gasTipCap = new(big.Int).Set(gasFeeCap)
+		}
+	}
+	return gasTipCap, gasFeeCap, nil
+}
+
+func (e *EIP1559FeeEstimator) estimateGasTipCap(ctx context.Context) (*big.Int, error) {
+	if e.params.TipPercentile > 0 && e.feeHistoryClient != nil {
+		feeHistory, err := e.feeHistoryClient.FeeHistory(
+			ctx,
+			e.params.FeeHistoryBlocks,
+			nil,
+			[]float64{e.params.TipPercentile},
+		)
+		if err != nil {
+			return nil, utils.WrapError("failed to get fee history", err)
+		}
+		if len(feeHistory.Reward) == 0 {
+			return nil, errors.New("fee history returned no rewards")
+		}
+		// average the percentile of the tips paid in each block
+		sum := new(big.Int)
+		for _, blockRewards := range feeHistory.Reward {
+			if len(blockRewards) > 0 {
+				sum.Add(sum, blockRewards[0])
+			}
+		}
+		return sum.Div(sum, big.NewInt(int64(len(feeHistory.Reward)))), nil
+	}
+
+	gasTipCap, err := e.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		// If the transaction failed because the backend does not support
+		// eth_maxPriorityFeePerGas, fallback to using the default constant.
+		e.logger.Info("eth_maxPriorityFeePerGas is unsupported by current backend, using fallback gasTipCap")
+		return new(big.Int).Set(FallbackGasTipCap), nil
+	}
+	return gasTipCap, nil
+}
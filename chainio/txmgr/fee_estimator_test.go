@@ -0,0 +1,129 @@
+package txmgr_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeeBackend has a base fee of 10 gwei, suggests a tip of 1 gwei, and returns rewards as its fee history
+type fakeFeeBackend struct {
+	rewards           [][]*big.Int
+	suggestTipErr     error
+	rewardPercentiles []float64
+}
+
+func (b *fakeFeeBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if b.suggestTipErr != nil {
+		return nil, b.suggestTipErr
+	}
+	return big.NewInt(1_000_000_000), nil
+}
+
+func (b *fakeFeeBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(11_000_000_000), nil
+}
+
+func (b *fakeFeeBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: big.NewInt(10_000_000_000)}, nil
+}
+
+func (b *fakeFeeBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+func (b *fakeFeeBackend) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	lastBlock *big.Int,
+	rewardPercentiles []float64,
+) (*ethereum.FeeHistory, error) {
+	b.rewardPercentiles = rewardPercentiles
+	return &ethereum.FeeHistory{Reward: b.rewards}, nil
+}
+
+func TestEIP1559FeeEstimator(t *testing.T) {
+	gwei := func(n int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(n), big.NewInt(1_000_000_000))
+	}
+	tests := []struct {
+		name              string
+		backend           *fakeFeeBackend
+		params            txmgr.FeeEstimatorParams
+		expectedGasTipCap *big.Int
+		expectedGasFeeCap *big.Int
+	}{
+		{
+			name:              "defaults use the suggested tip and twice the base fee",
+			backend:           &fakeFeeBackend{},
+			expectedGasTipCap: gwei(1),
+			expectedGasFeeCap: gwei(21),
+		},
+		{
+			name:              "fallback tip when the node can't suggest one",
+			backend:           &fakeFeeBackend{suggestTipErr: errors.New("method not found")},
+			expectedGasTipCap: gwei(5),
+			expectedGasFeeCap: gwei(25),
+		},
+		{
+			name:              "base fee multiplier",
+			backend:           &fakeFeeBackend{},
+			params:            txmgr.FeeEstimatorParams{BaseFeeMultiplier: 1.5},
+			expectedGasTipCap: gwei(1),
+			expectedGasFeeCap: gwei(16),
+		},
+		{
+			name: "tip from the fee history percentile",
+			backend: &fakeFeeBackend{
+				rewards: [][]*big.Int{{gwei(2)}, {gwei(3)}, {gwei(4)}},
+			},
+			params:            txmgr.FeeEstimatorParams{TipPercentile: 75},
+			expectedGasTipCap: gwei(3),
+			expectedGasFeeCap: gwei(23),
+		},
+		{
+			name:              "min tip",
+			backend:           &fakeFeeBackend{},
+			params:            txmgr.FeeEstimatorParams{MinGasTipCap: gwei(2)},
+			expectedGasTipCap: gwei(2),
+			expectedGasFeeCap: gwei(22),
+		},
+		{
+			name:              "max fee cap",
+			backend:           &fakeFeeBackend{},
+			params:            txmgr.FeeEstimatorParams{MaxGasFeeCap: gwei(15)},
+			expectedGasTipCap: gwei(1),
+			expectedGasFeeCap: gwei(15),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimator, err := txmgr.NewEIP1559FeeEstimator(tt.backend, testutils.GetTestLogger(), tt.params)
+			require.NoError(t, err)
+
+			gasTipCap, gasFeeCap, err := estimator.EstimateFees(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedGasTipCap, gasTipCap)
+			require.Equal(t, tt.expectedGasFeeCap, gasFeeCap)
+			if tt.params.TipPercentile > 0 {
+				require.Equal(t, []float64{tt.params.TipPercentile}, tt.backend.rewardPercentiles)
+			}
+		})
+	}
+}
+
+func TestEIP1559FeeEstimatorInvalidParams(t *testing.T) {
+	_, err := txmgr.NewEIP1559FeeEstimator(
+		&fakeFeeBackend{},
+		testutils.GetTestLogger(),
+		txmgr.FeeEstimatorParams{TipPercentile: 101},
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// executionRevertedErrorCode is the json-rpc error code returned by eth_estimateGas and eth_call when the execution
+// reverts
+const executionRevertedErrorCode = 3
+
+// GasLimitOptions configures the gas limit of the transactions sent by the tx managers.
+// If an option is not set (aka its zero value is present in the struct), it isn't used.
+type GasLimitOptions struct {
+	// GasLimit is used as is as the gas limit of the transaction, which isn't estimated
+	GasLimit uint64
+	// GasLimitMultiplier is the buffer applied to the estimated gas limit, e.g. 1.2 to add 20%
+	GasLimitMultiplier float64
+	// FallbackGasLimit is used as the gas limit when the gas estimation reverts, e.g. for a deposit which only succeeds
+	// once a pending approval is mined
+	FallbackGasLimit uint64
+}
+
+// override returns the options with the options set in overrides replacing them
+func (o GasLimitOptions) override(overrides GasLimitOptions) GasLimitOptions {
+	if overrides.GasLimit > 0 {
+		o.GasLimit = overrides.GasLimit
+	}
+	if overrides.GasLimitMultiplier > 0 {
+		o.GasLimitMultiplier = overrides.GasLimitMultiplier
+	}
+	if overrides.FallbackGasLimit > 0 {
+		o.FallbackGasLimit = overrides.FallbackGasLimit
+	}
+	return o
+}
+
+type gasLimitOptionsKey struct{}
+
+// WithGasLimitOptions returns a context which makes the tx managers use opts, on top of their own options, for the
+// transactions sent with it. This allows overriding the gas limit of a single send, including through the chainio
+// writers.
+func WithGasLimitOptions(ctx context.Context, opts GasLimitOptions) context.Context {
+	return context.WithValue(ctx, gasLimitOptionsKey{}, opts)
+}
+
+// GasLimitOptionsFromContext returns the options set with WithGasLimitOptions, if any
+func GasLimitOptionsFromContext(ctx context.Context) GasLimitOptions {
+	opts, _ := ctx.Value(gasLimitOptionsKey{}).(GasLimitOptions)
+	return opts
+}
+
+// IsExecutionRevertedError returns whether err was returned by the node because the execution of the transaction
+// reverted
+func IsExecutionRevertedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == executionRevertedErrorCode {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "execution reverted")
+}
+
+// GasEstimator is the eth client needed to estimate gas limits
+type GasEstimator interface {
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// EstimateGasLimit returns the gas limit of the transaction described by msg, using opts overridden by the options of
+// ctx (see WithGasLimitOptions). The gas limit is, in order of precedence:
+//   - the GasLimit option, if set
+//   - msg.Gas (e.g. estimated by the caller) or else the estimation of msg, times the GasLimitMultiplier if set
+//   - the FallbackGasLimit, if set and the estimation reverted
+//
+// Where the gas limit came from is logged.
+func EstimateGasLimit(
+	ctx context.Context,
+	client GasEstimator,
+	logger logging.Logger,
+	msg ethereum.CallMsg,
+	opts GasLimitOptions,
+) (uint64, error) {
+	opts = opts.override(GasLimitOptionsFromContext(ctx))
+	if opts.GasLimit > 0 {
+		logger.Info("Using gas limit override", "gasLimitSource", "override", "gasLimit", opts.GasLimit)
+		return opts.GasLimit, nil
+	}
+
+	gasLimit := msg.Gas
+	if gasLimit == 0 {
+		var err error
+		gasLimit, err = client.EstimateGas(ctx, msg)
+		if IsExecutionRevertedError(err) && opts.FallbackGasLimit > 0 {
+			logger.Warn("Gas estimation reverted, using fallback gas limit", "gasLimitSource", "fallback",
+				"gasLimit", opts.FallbackGasLimit, "err", err)
+			return opts.FallbackGasLimit, nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	if opts.GasLimitMultiplier > 0 && opts.GasLimitMultiplier != 1 {
+		bufferedGasLimit := uint64(float64(gasLimit) * opts.GasLimitMultiplier)
+		logger.Debug("Using buffered gas limit estimation", "gasLimitSource", "buffered estimation",
+			"gasLimit", bufferedGasLimit, "estimatedGasLimit", gasLimit, "multiplier", opts.GasLimitMultiplier)
+		return bufferedGasLimit, nil
+	}
+	logger.Debug("Using gas limit estimation", "gasLimitSource", "estimation", "gasLimit", gasLimit)
+	return gasLimit, nil
+}
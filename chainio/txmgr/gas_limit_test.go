@@ -0,0 +1,154 @@
+package txmgr_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	approveData = []byte("approve")
+	depositData = []byte("deposit")
+)
+
+// revertError is returned by eth_estimateGas when the execution reverts
+type revertError struct{}
+
+func (revertError) Error() string  { return "execution reverted: insufficient allowance" }
+func (revertError) ErrorCode() int { return 3 }
+
+// fakeTokenBackend estimates the gas of deposits as reverting until an approval is mined. Sent txs stay pending.
+type fakeTokenBackend struct {
+	approvalMined bool
+	sentTxs       []*types.Transaction
+}
+
+func (b *fakeTokenBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *fakeTokenBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(2), nil
+}
+
+func (b *fakeTokenBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: big.NewInt(1)}, nil
+}
+
+func (b *fakeTokenBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	if bytes.Equal(msg.Data, depositData) && !b.approvalMined {
+		return 0, revertError{}
+	}
+	return 50_000, nil
+}
+
+func (b *fakeTokenBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sentTxs = append(b.sentTxs, tx)
+	return nil
+}
+
+func (b *fakeTokenBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return nil, ethereum.NotFound
+}
+
+func TestSimpleTxManagerGasLimit(t *testing.T) {
+	ecdsaSk, addr, err := testutils.NewEcdsaSkAndAddress()
+	require.NoError(t, err)
+	chainId := big.NewInt(31337)
+	signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
+	require.NoError(t, err)
+	newTx := func(nonce uint64, data []byte) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{ChainID: chainId, Nonce: nonce, To: &common.Address{0x1}, Data: data})
+	}
+
+	tests := []struct {
+		name             string
+		fallbackGasLimit uint64
+		ctxOptions       *txmgr.GasLimitOptions
+		approvalMined    bool
+		// gas limit of the deposit, or 0 if it can't be sent
+		expectedGasLimit uint64
+	}{
+		{
+			name:             "buffered estimation once the approval is mined",
+			approvalMined:    true,
+			expectedGasLimit: 60_000,
+		},
+		{
+			name:             "estimation reverts while the approval is pending",
+			expectedGasLimit: 0,
+		},
+		{
+			name:             "fallback gas limit while the approval is pending",
+			fallbackGasLimit: 100_000,
+			expectedGasLimit: 100_000,
+		},
+		{
+			name:             "fallback gas limit of the send",
+			ctxOptions:       &txmgr.GasLimitOptions{FallbackGasLimit: 90_000},
+			expectedGasLimit: 90_000,
+		},
+		{
+			name:             "gas limit override of the send",
+			fallbackGasLimit: 100_000,
+			ctxOptions:       &txmgr.GasLimitOptions{GasLimit: 70_000},
+			expectedGasLimit: 70_000,
+		},
+		{
+			name:             "gas limit multiplier of the send",
+			approvalMined:    true,
+			ctxOptions:       &txmgr.GasLimitOptions{GasLimitMultiplier: 1.5},
+			expectedGasLimit: 75_000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeTokenBackend{}
+			pkWallet, err := wallet.NewPrivateKeyWallet(backend, signerFn, addr, testutils.GetTestLogger())
+			require.NoError(t, err)
+			txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, backend, testutils.GetTestLogger()).
+				WithFallbackGasLimit(tt.fallbackGasLimit)
+
+			// the approval is sent without waiting for it to be mined
+			ctx := context.Background()
+			_, err = txMgr.Send(ctx, newTx(0, approveData), false)
+			require.NoError(t, err)
+			require.Len(t, backend.sentTxs, 1)
+			require.Equal(t, uint64(60_000), backend.sentTxs[0].Gas())
+			backend.approvalMined = tt.approvalMined
+
+			if tt.ctxOptions != nil {
+				ctx = txmgr.WithGasLimitOptions(ctx, *tt.ctxOptions)
+			}
+			_, err = txMgr.Send(ctx, newTx(1, depositData), false)
+			if tt.expectedGasLimit == 0 {
+				require.ErrorIs(t, err, revertError{})
+				require.Len(t, backend.sentTxs, 1)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, backend.sentTxs, 2)
+			require.Equal(t, tt.expectedGasLimit, backend.sentTxs[1].Gas())
+		})
+	}
+}
+
+func TestIsExecutionRevertedError(t *testing.T) {
+	require.True(t, txmgr.IsExecutionRevertedError(revertError{}))
+	require.True(t, txmgr.IsExecutionRevertedError(fmt.Errorf("failed to estimate gas: %w", revertError{})))
+	require.True(t, txmgr.IsExecutionRevertedError(errors.New("execution reverted")))
+	require.False(t, txmgr.IsExecutionRevertedError(errors.New("insufficient funds for gas * price + value")))
+	require.False(t, txmgr.IsExecutionRevertedError(nil))
+}
@@ -0,0 +1,106 @@
+package txmgr
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// GasPricingMode is the type of the transactions sent by the tx managers, and so how they are priced
+type GasPricingMode int
+
+const (
+	// GasPricingModeAuto sends dynamic fee transactions if the chain has a base fee, and legacy transactions otherwise
+	GasPricingModeAuto GasPricingMode = iota
+	// GasPricingModeEIP1559 always sends dynamic fee (EIP-1559) transactions, priced with a gas tip cap and fee cap
+	GasPricingModeEIP1559
+	// GasPricingModeLegacy always sends legacy (type 0) transactions, priced with the gas price suggested by the node,
+	// for chains which don't support EIP-1559
+	GasPricingModeLegacy
+)
+
+func (m GasPricingMode) String() string {
+	switch m {
+	case GasPricingModeAuto:
+		return "auto"
+	case GasPricingModeEIP1559:
+		return "eip1559"
+	case GasPricingModeLegacy:
+		return "legacy"
+	default:
+		return "unknown"
+	}
+}
+
+// HeaderBackend is the eth client needed by GasPricing to probe the support of EIP-1559
+type HeaderBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// GasPricing resolves the GasPricingMode of a tx manager. In GasPricingModeAuto, the chain is probed once for a base
+// fee, and the decision is cached.
+type GasPricing struct {
+	client HeaderBackend
+
+	mu sync.Mutex
+	// mode is GasPricingModeAuto until it is resolved
+	mode GasPricingMode
+}
+
+func NewGasPricing(mode GasPricingMode, client HeaderBackend) *GasPricing {
+	return &GasPricing{
+		client: client,
+		mode:   mode,
+	}
+}
+
+// IsLegacy returns whether legacy transactions must be sent. In GasPricingModeAuto, it fetches the latest header on
+// the first call, and again on the next calls until that succeeds.
+func (p *GasPricing) IsLegacy(ctx context.Context) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mode == GasPricingModeAuto {
+		header, err := p.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return false, utils.WrapError("failed to get latest header to probe EIP-1559 support", err)
+		}
+		if header.BaseFee == nil {
+			p.mode = GasPricingModeLegacy
+		} else {
+			p.mode = GasPricingModeEIP1559
+		}
+	}
+	return p.mode == GasPricingModeLegacy, nil
+}
+
+// NewLegacyTxWithGasPrice returns the transaction tx as a legacy transaction with the given nonce, gas limit and gas
+// price. Blob transactions can't be legacy transactions, so ErrBlobTxUnsupported is returned for them.
+func NewLegacyTxWithGasPrice(
+	tx *types.Transaction,
+	nonce uint64,
+	gas uint64,
+	gasPrice *big.Int,
+) (*types.Transaction, error) {
+	if tx.Type() == types.BlobTxType {
+		return nil, ErrBlobTxUnsupported
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gas,
+		To:       tx.To(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}), nil
+}
+
+// withNonce returns tx with the given nonce, keeping its type and fees
+func withNonce(tx *types.Transaction, nonce uint64) (*types.Transaction, error) {
+	if tx.Type() == types.LegacyTxType {
+		return NewLegacyTxWithGasPrice(tx, nonce, tx.Gas(), tx.GasPrice())
+	}
+	return NewTxWithFees(tx, nonce, tx.Gas(), tx.GasTipCap(), tx.GasFeeCap(), tx.BlobGasFeeCap())
+}
@@ -0,0 +1,99 @@
+package txmgr_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHeaderBackend returns a header with baseFee, or err, and counts the headers fetched
+type fakeHeaderBackend struct {
+	baseFee      *big.Int
+	err          error
+	headersCount int
+}
+
+func (b *fakeHeaderBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.headersCount++
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &types.Header{BaseFee: b.baseFee}, nil
+}
+
+func TestGasPricing(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		mode           txmgr.GasPricingMode
+		baseFee        *big.Int
+		expectedLegacy bool
+		// number of headers fetched to resolve the mode
+		expectedHeadersCount int
+	}{
+		{name: "auto on a chain with a base fee", baseFee: big.NewInt(1), expectedHeadersCount: 1},
+		{name: "auto on a chain without a base fee", expectedLegacy: true, expectedHeadersCount: 1},
+		{name: "eip1559", mode: txmgr.GasPricingModeEIP1559},
+		{name: "legacy", mode: txmgr.GasPricingModeLegacy, baseFee: big.NewInt(1), expectedLegacy: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeHeaderBackend{baseFee: tt.baseFee}
+			gasPricing := txmgr.NewGasPricing(tt.mode, backend)
+			// the decision is cached
+			for i := 0; i < 2; i++ {
+				legacy, err := gasPricing.IsLegacy(ctx)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedLegacy, legacy)
+			}
+			require.Equal(t, tt.expectedHeadersCount, backend.headersCount)
+		})
+	}
+
+	t.Run("auto probes again after a failure", func(t *testing.T) {
+		backend := &fakeHeaderBackend{err: errors.New("connection refused")}
+		gasPricing := txmgr.NewGasPricing(txmgr.GasPricingModeAuto, backend)
+		_, err := gasPricing.IsLegacy(ctx)
+		require.Error(t, err)
+
+		backend.err = nil
+		legacy, err := gasPricing.IsLegacy(ctx)
+		require.NoError(t, err)
+		require.True(t, legacy)
+		require.Equal(t, 2, backend.headersCount)
+	})
+}
+
+func TestSimpleTxManagerLegacyGasPricingMode(t *testing.T) {
+	ecdsaSk, addr, err := testutils.NewEcdsaSkAndAddress()
+	require.NoError(t, err)
+	signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, big.NewInt(31337))
+	require.NoError(t, err)
+	backend := &fakeTokenBackend{}
+	pkWallet, err := wallet.NewPrivateKeyWallet(backend, signerFn, addr, testutils.GetTestLogger())
+	require.NoError(t, err)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, backend, testutils.GetTestLogger()).
+		WithGasPricingMode(txmgr.GasPricingModeLegacy)
+
+	candidate := types.NewTx(&types.DynamicFeeTx{Nonce: 3, To: &common.Address{0x1}, Data: approveData})
+	_, err = txMgr.Send(context.Background(), candidate, false)
+	require.NoError(t, err)
+	require.Len(t, backend.sentTxs, 1)
+	sentTx := backend.sentTxs[0]
+	require.Equal(t, uint8(types.LegacyTxType), sentTx.Type())
+	require.Equal(t, uint64(3), sentTx.Nonce())
+	// the gas price suggested by the node
+	require.Equal(t, big.NewInt(2), sentTx.GasPrice())
+	require.Equal(t, uint64(60_000), sentTx.Gas())
+	// the tx is signed with replay protection
+	require.Equal(t, big.NewInt(31337), sentTx.ChainId())
+}
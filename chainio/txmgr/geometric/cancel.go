@@ -0,0 +1,155 @@
+package geometric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+var (
+	// ErrTxCancelled is returned to the sender of a transaction whose nonce was used by its cancellation instead
+	ErrTxCancelled = errors.New("transaction cancelled")
+	// ErrCancelLost is returned by Cancel, along with the receipt of the transaction, when the transaction got mined
+	// before its cancellation
+	ErrCancelLost = errors.New("transaction mined before its cancellation")
+)
+
+// Cancel replaces the in-flight transaction with the given nonce by a zero-value transfer to the sender itself, which
+// burns the nonce cheaply. A blob transaction is replaced by a blob self-transfer carrying the same blobs. The
+// self-transfer is bumped like a speed up of the in-flight transaction, so that it replaces it in the mempool, and
+// keeps being sped up until it gets mined.
+//
+// Cancel waits until one of the transactions with that nonce is mined. If it is the cancellation, its receipt is
+// returned, and the Send of the original transaction returns ErrTxCancelled. If the original transaction got mined
+// first, its receipt is returned with ErrCancelLost, and its Send returns it as usual.
+// Only the transactions sent by this GeometricTxManager and still monitored can be cancelled. With a wallet signing for
+// multiple addresses, the sender of the transaction is selected with wallet.WithSenderAddress on ctx.
+func (t *GeometricTxManager) Cancel(ctx context.Context, nonce uint64) (*types.Receipt, error) {
+	from, err := t.wallet.SenderAddress(ctx)
+	if err != nil {
+		return nil, utils.WrapError("failed to get sender address", err)
+	}
+	t.inFlightMu.Lock()
+	req, ok := t.inFlight[senderNonce{from: from, nonce: nonce}]
+	t.inFlightMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no transaction in flight from %s with nonce %d", from.Hex(), nonce)
+	}
+
+	if err := t.sendCancellation(ctx, req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-req.done:
+	}
+	if req.err != nil {
+		return nil, utils.WrapError(fmt.Errorf("failed to wait for transaction with nonce %d", nonce), req.err)
+	}
+	if req.isCancelled() {
+		return req.receipt, nil
+	}
+	return req.receipt, fmt.Errorf("%w: nonce %d was used by %s", ErrCancelLost, nonce, req.receipt.TxHash.Hex())
+}
+
+// sendCancellation broadcasts a self-transfer replacing the current transaction of req. It doesn't return an error if
+// the nonce was already used, in which case the original transaction is being (or was) confirmed.
+func (t *GeometricTxManager) sendCancellation(ctx context.Context, req *txnRequest) error {
+	req.replaceMu.Lock()
+	defer req.replaceMu.Unlock()
+	select {
+	case <-req.done:
+		return nil
+	default:
+	}
+
+	ctx = wallet.WithSenderAddress(ctx, req.from)
+	tx := req.currentTx()
+	cancelTx, err := t.speedUpTxn(ctx, newSelfTransfer(req.from, tx), len(req.attempts()))
+	if err != nil {
+		return utils.WrapError("failed to price cancellation", err)
+	}
+	txID, err := t.wallet.SendTransaction(ctx, cancelTx)
+	if isAlreadyMinedError(err) {
+		t.logger.Info("transaction mined before its cancellation was sent", "nonce", cancelTx.Nonce())
+		return nil
+	} else if err != nil {
+		return utils.WrapError(fmt.Errorf("failed to send cancellation %s", cancelTx.Hash().Hex()), err)
+	}
+
+	t.logger.Info("sent cancellation", "txID", txID, "txHash", cancelTx.Hash().Hex(), "nonce", cancelTx.Nonce(),
+		"replacedTxHash", tx.Hash().Hex())
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	req.tx = cancelTx
+	t.replaceTx(req, &transaction{
+		TxID:        txID,
+		Transaction: cancelTx,
+		requestedAt: time.Now(),
+		state:       TxStateQueued,
+	})
+	req.cancellations[txID] = struct{}{}
+	return nil
+}
+
+// newSelfTransfer returns the zero-value transfer from the sender to itself replacing tx, before it's priced. The
+// self-transfer replacing a blob transaction is a blob transaction too, carrying the same blobs, since the nodes
+// don't let a regular transaction replace a blob one.
+func newSelfTransfer(from common.Address, tx *types.Transaction) *types.Transaction {
+	if tx.Type() == types.BlobTxType {
+		return types.NewTx(&types.BlobTx{
+			ChainID:    uint256.MustFromBig(tx.ChainId()),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  uint256.MustFromBig(tx.GasTipCap()),
+			GasFeeCap:  uint256.MustFromBig(tx.GasFeeCap()),
+			To:         from,
+			Value:      new(uint256.Int),
+			BlobFeeCap: uint256.MustFromBig(tx.BlobGasFeeCap()),
+			BlobHashes: tx.BlobHashes(),
+			Sidecar:    tx.BlobTxSidecar(),
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+		To:        &from,
+		Value:     big.NewInt(0),
+	})
+}
+
+// newDeadlineExceededError returns the error of req, whose send deadline passed before it was confirmed
+func newDeadlineExceededError(req *txnRequest) *txmgr.ErrTxDeadlineExceeded {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	lastTx := req.txAttempts[len(req.txAttempts)-1]
+	return &txmgr.ErrTxDeadlineExceeded{
+		TxID:          lastTx.TxID,
+		TxHash:        common.HexToHash(lastTx.TxID),
+		Nonce:         lastTx.Nonce(),
+		LastGasFeeCap: lastTx.GasFeeCap(),
+	}
+}
+
+// cancelAfterDeadline cancels req once the deadline of its send passed, and monitors it until either the cancellation
+// or the transaction is confirmed
+func (t *GeometricTxManager) cancelAfterDeadline(ctx context.Context, req *txnRequest) (*types.Receipt, error) {
+	nonce := req.currentTx().Nonce()
+	t.logger.Warn("transaction not confirmed before its deadline, cancelling it", "nonce", nonce)
+	if err := t.sendCancellation(ctx, req); err != nil {
+		t.logger.Error("failed to cancel transaction after its deadline", "nonce", nonce, "err", err)
+	}
+	return t.monitorTransaction(ctx, req)
+}
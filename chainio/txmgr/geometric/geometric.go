@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math/big"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
@@ -22,30 +24,101 @@ type transaction struct {
 	*types.Transaction
 	TxID        wallet.TxID
 	requestedAt time.Time
+	// state is updated with changeTxState, holding the mutex of the request once it is monitored
+	state TxState
 }
 
 type txnRequest struct {
-	tx *types.Transaction
-
 	requestedAt time.Time
+	// from is the sender of the transaction
+	from common.Address
+	// deadline is the deadline of the send, after which the request is no longer monitored. Zero if none.
+	deadline time.Time
+	// numSpeedUps is the number of speed ups made before the monitoring of the request was resumed after a restart
+	numSpeedUps int
+
+	// replaceMu serializes the replacements of tx, i.e. the speed ups and the cancellations, which are priced and
+	// broadcast holding it so that they don't override each other. mu is only held to update the fields below, so
+	// that the monitoring of the request isn't stalled by the broadcast of a replacement.
+	replaceMu sync.Mutex
+	// mu guards the fields below once the request is monitored, since Cancel replaces its transaction
+	mu sync.Mutex
+	tx *types.Transaction
 	// txAttempts are the transactions that have been attempted to be mined for this request.
 	// If a transaction hasn't been confirmed within the timeout and a replacement transaction is sent,
 	// the original transaction hash will be kept in this slice
 	txAttempts []*transaction
+	// cancellations are the self-transfers sent by Cancel to replace the transaction
+	cancellations map[wallet.TxID]struct{}
+	// confirmedTxID is the transaction which got confirmed, once there is one
+	confirmedTxID wallet.TxID
+
+	// done is closed once the request is no longer monitored, with its outcome in receipt and err
+	done    chan struct{}
+	receipt *types.Receipt
+	err     error
+}
+
+// currentTx returns the latest transaction of the request, which speed ups and cancellations replace
+func (r *txnRequest) currentTx() *types.Transaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tx
+}
+
+// attempts returns a copy of the transactions sent for the request so far
+func (r *txnRequest) attempts() []*transaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*transaction(nil), r.txAttempts...)
+}
+
+// setConfirmed records the transaction of the request which got confirmed
+func (r *txnRequest) setConfirmed(txID wallet.TxID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.confirmedTxID = txID
+}
+
+// isCancelled returns whether the transaction which got confirmed is one of the self-transfers sent by Cancel
+func (r *txnRequest) isCancelled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.cancellations[r.confirmedTxID]
+	return ok
 }
 
 type ethBackend interface {
 	BlockNumber(ctx context.Context) (uint64, error)
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 }
 
+// senderNonce identifies the transactions of a sender with a given nonce, only one of which can be mined
+type senderNonce struct {
+	from  common.Address
+	nonce uint64
+}
+
 type GeometricTxManager struct {
 	ethClient ethBackend
 	wallet    wallet.Wallet
 	logger    logging.Logger
 	metrics   Metrics
+	// optional, see WithNonceManager
+	nonceManagers map[common.Address]*txmgr.NonceManager
+	// optional, see WithOnStateChange
+	onStateChange OnStateChangeFunc
+	// optional, see WithTxStore
+	txStore TxStore
+	// resolves params.GasPricingMode
+	gasPricing *txmgr.GasPricing
+
+	// inFlight are the requests being monitored, by sender and nonce, so that they can be cancelled
+	inFlightMu sync.Mutex
+	inFlight   map[senderNonce]*txnRequest
 
 	// consts
 	params GeometricTxnManagerParams
@@ -80,11 +153,41 @@ type GeometricTxnManagerParams struct {
 	// multiplier for gas limit to add a buffer and increase chance of tx getting included. Should be >= 1.0
 	// default: 1.2
 	GasMultiplier float64
+	// gas limit used when the gas estimation of a transaction reverts, e.g. because it depends on a pending
+	// transaction. The gas limit options of a single send can be overridden with txmgr.WithGasLimitOptions.
+	// default: 0 (the send fails)
+	FallbackGasLimit uint64
 	// multiplier for gas tip. Should be >= 1.0
+	// When replacing a stuck transaction, the gas tip and fee cap are bumped by at least
+	// minReplacementBumpPercentage, even if this multiplier is lower.
 	// default: 1.25
 	GasTipMultiplier float64
+	// max number of times a stuck transaction is replaced with a higher gas price. Once reached, the manager keeps
+	// waiting for one of the sent transactions to be mined until the context is done.
+	// default: 0 (no limit)
+	MaxSpeedUps int
+	// max gasFeeCap (in wei) of the sent transactions. Fees are capped to it, and stuck transactions stop being
+	// replaced once the capped fees are too low to replace the last sent transaction.
+	// default: 0 (no limit)
+	MaxGasFeeCap uint64
+	// type of the sent transactions. In legacy mode, the gas price suggested by the node is used like the gas tip cap:
+	// it is multiplied by GasTipMultiplier, bumped by at least minReplacementBumpPercentage on replacements, and capped
+	// to MaxGasFeeCap.
+	// default: txmgr.GasPricingModeAuto (legacy transactions only on chains without a base fee)
+	GasPricingMode txmgr.GasPricingMode
+	// cancel the transactions whose send deadline passes before they are confirmed, as with Cancel. The sender still
+	// gets a txmgr.ErrTxDeadlineExceeded when the deadline passes, and the cancellation is monitored in the background.
+	// default: false (the transaction is left pending)
+	AutoCancelOnDeadline bool
 }
 
+// minReplacementBumpPercentage is the minimum percentage by which both the gas tip and fee cap of a transaction must be
+// increased to replace it in the mempool. This is the default of geth's txpool.pricebump.
+const minReplacementBumpPercentage = 10
+
+// errGasFeeCapLimitReached is returned by speedUpTxn when the MaxGasFeeCap prevents replacing the transaction
+var errGasFeeCapLimitReached = errors.New("gas fee cap limit reached")
+
 var defaultParams = GeometricTxnManagerParams{
 	ConfirmationBlocks:         0,                    // tx mined is considered confirmed
 	TxnBroadcastTimeout:        2 * time.Minute,      // fireblocks has had issues so we give it a long time
@@ -132,18 +235,35 @@ func NewGeometricTxnManager(
 ) *GeometricTxManager {
 	fillUnsetParamsWithDefaultValues(&params)
 	return &GeometricTxManager{
-		ethClient: ethClient,
-		wallet:    wallet,
-		logger:    logger.With("component", "GeometricTxManager"),
-		metrics:   metrics,
-		params:    params,
+		ethClient:     ethClient,
+		wallet:        wallet,
+		logger:        logger.With("component", "GeometricTxManager"),
+		metrics:       metrics,
+		inFlight:      make(map[senderNonce]*txnRequest),
+		nonceManagers: make(map[common.Address]*txmgr.NonceManager),
+		params:        params,
+		gasPricing:    txmgr.NewGasPricing(params.GasPricingMode, ethClient),
 	}
 }
 
+// WithNonceManager makes the GeometricTxManager set the nonce of every tx it sends from the address of nonceManager,
+// overriding the nonce set by the caller. The same NonceManager must be used by all the senders of the account, so
+// that they don't use the same nonces.
+// With a wallet signing for multiple addresses, WithNonceManager can be called once per address.
+func (t *GeometricTxManager) WithNonceManager(nonceManager *txmgr.NonceManager) *GeometricTxManager {
+	t.nonceManagers[nonceManager.Address()] = nonceManager
+	return t
+}
+
 // GetNoSendTxOpts This generates a noSend TransactOpts so that we can use
 // this to generate the transaction without actually sending it
 func (m *GeometricTxManager) GetNoSendTxOpts() (*bind.TransactOpts, error) {
-	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	return m.GetNoSendTxOptsWithContext(context.Background())
+}
+
+// GetNoSendTxOptsWithContext is GetNoSendTxOpts with the sender selected on ctx with wallet.WithSenderAddress
+func (m *GeometricTxManager) GetNoSendTxOptsWithContext(ctx context.Context) (*bind.TransactOpts, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	from, err := m.wallet.SenderAddress(ctxWithTimeout)
 	if err != nil {
@@ -158,15 +278,18 @@ func (m *GeometricTxManager) GetNoSendTxOpts() (*bind.TransactOpts, error) {
 
 func newTxnRequest(tx *types.Transaction) *txnRequest {
 	return &txnRequest{
-		tx:          tx,
-		requestedAt: time.Now(),
-		txAttempts:  make([]*transaction, 0),
+		tx:            tx,
+		requestedAt:   time.Now(),
+		txAttempts:    make([]*transaction, 0),
+		cancellations: make(map[wallet.TxID]struct{}),
+		done:          make(chan struct{}),
 	}
 }
 
 // Send is used to sign and send a transaction to an evm chain.
 // It does gas estimation and gas price bumping to ensure the transaction gets mined,
-// but it does not do nonce management, so the tx argument must have the correct nonce already set.
+// but it does not do nonce management unless a NonceManager is set, so the tx argument must have the correct nonce
+// already set.
 //
 // Send is blocking and safe to call concurrently, so sending multiple txs in parallel is safe.
 func (t *GeometricTxManager) Send(
@@ -174,37 +297,188 @@ func (t *GeometricTxManager) Send(
 	tx *types.Transaction,
 	waitForReceipt bool,
 ) (*types.Receipt, error) {
-	return t.processTransaction(ctx, newTxnRequest(tx))
+	resultChan, err := t.SendAsync(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	result := <-resultChan
+	return result.Receipt, result.Err
+}
+
+// SendAsync broadcasts the transaction like Send, then monitors it and bumps its gas price in a background goroutine
+// until it gets mined. See TxManager.SendAsync for the cancellation and ordering semantics: in particular, the
+// replacements already broadcast when ctx is cancelled are not cancelled, and any of them may still get mined.
+//
+// With a wallet signing for multiple addresses, the sender is selected with wallet.WithSenderAddress on ctx. The
+// nonces, speed ups and in-flight metrics of every sender are then handled independently.
+func (t *GeometricTxManager) SendAsync(ctx context.Context, tx *types.Transaction) (<-chan txmgr.TxResult, error) {
+	from, err := t.wallet.SenderAddress(ctx)
+	if err != nil {
+		return nil, utils.WrapError("failed to get sender address", err)
+	}
+	req, err := t.newTxnRequestWithNonce(ctx, from, tx)
+	if err != nil {
+		return nil, err
+	}
+	req.deadline, _ = ctx.Deadline()
+	t.metrics.IncrementProcessingTxCount()
+
+	err = t.broadcastTransaction(ctx, req)
+	if nonceManager, ok := t.nonceManagers[from]; ok {
+		if len(req.txAttempts) == 0 {
+			// no tx was broadcast with this nonce
+			nonceManager.Release(req.tx.Nonce())
+		} else {
+			nonceManager.Complete(req.tx.Nonce())
+		}
+	}
+	if err != nil {
+		t.metrics.DecrementProcessingTxCount()
+		t.changeTxState(&transaction{Transaction: req.tx, state: TxStateQueued}, TxStateFailed)
+		t.resyncNoncesOnNonceError(ctx, req, err)
+		return nil, err
+	}
+	t.persistTx(req)
+	return t.monitorAsync(ctx, req), nil
 }
 
-// processTransaction sends the transaction and runs a monitoring loop to bump the gasPrice until the tx get included.
-// processTransaction can be called concurrently, so sending multiple txs in parallel is safe.
+// monitorAsync registers req as in flight and monitors it in a background goroutine, until its deadline if set.
+// The result is sent on the returned channel, which is then closed.
+func (t *GeometricTxManager) monitorAsync(ctx context.Context, req *txnRequest) <-chan txmgr.TxResult {
+	nonce := req.tx.Nonce()
+	key := senderNonce{from: req.from, nonce: nonce}
+	// the speed ups are signed for the sender of req
+	ctx = wallet.WithSenderAddress(ctx, req.from)
+	t.inFlightMu.Lock()
+	t.inFlight[key] = req
+	t.inFlightMu.Unlock()
+	t.metrics.IncrementInFlightTxCount(req.from.Hex())
+
+	resultChan := make(chan txmgr.TxResult, 1)
+	go func() {
+		defer close(resultChan)
+		defer t.metrics.DecrementProcessingTxCount()
+		defer t.metrics.DecrementInFlightTxCount(req.from.Hex())
+		if !req.deadline.IsZero() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, req.deadline)
+			defer cancel()
+		}
+		receipt, err := t.monitorTransaction(ctx, req)
+		// with AutoCancelOnDeadline, the caller is answered when the deadline passes, and the cancellation is then
+		// monitored in the background
+		answered := false
+		if errors.Is(err, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = newDeadlineExceededError(req)
+			if t.params.AutoCancelOnDeadline {
+				resultChan <- txmgr.TxResult{Receipt: receipt, Err: err}
+				answered = true
+				ctx = context.WithoutCancel(ctx)
+				receipt, err = t.cancelAfterDeadline(ctx, req)
+			}
+		}
+		if err == nil {
+			if receipt.GasUsed > 0 {
+				t.metrics.ObserveGasUsedWei(receipt.GasUsed)
+			}
+			t.metrics.ObserveConfirmationLatencyMs(time.Since(req.requestedAt).Milliseconds())
+			t.metrics.ObserveFeePaidGwei(feePaidGwei(receipt))
+			t.completeTxStates(req, false)
+		} else {
+			if ctx.Err() == nil {
+				t.completeTxStates(req, true)
+			}
+			t.resyncNoncesOnNonceError(ctx, req, err)
+		}
+
+		t.inFlightMu.Lock()
+		if t.inFlight[key] == req {
+			delete(t.inFlight, key)
+		}
+		t.inFlightMu.Unlock()
+		// a request whose monitoring was cancelled (e.g. on shutdown) is kept in the store to be resumed
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.deletePersistedTx(req.from, nonce)
+		}
+		req.receipt, req.err = receipt, err
+		close(req.done)
+
+		if answered {
+			return
+		}
+		if err == nil && req.isCancelled() {
+			err = fmt.Errorf("%w: nonce %d was used by cancellation %s", ErrTxCancelled, nonce, receipt.TxHash.Hex())
+			resultChan <- txmgr.TxResult{Err: err}
+			return
+		}
+		resultChan <- txmgr.TxResult{Receipt: receipt, Err: err}
+	}()
+	return resultChan
+}
+
+// newTxnRequestWithNonce creates the request of tx sent from from, with a nonce reserved from the NonceManager of from
+// if one is set
+func (t *GeometricTxManager) newTxnRequestWithNonce(
+	ctx context.Context,
+	from common.Address,
+	tx *types.Transaction,
+) (*txnRequest, error) {
+	nonceManager, ok := t.nonceManagers[from]
+	if !ok {
+		req := newTxnRequest(tx)
+		req.from = from
+		return req, nil
+	}
+
+	nonce, err := nonceManager.Reserve(ctx)
+	if err != nil {
+		return nil, utils.WrapError("failed to reserve nonce", err)
+	}
+	txWithNonce, err := txmgr.NewTxWithFees(tx, nonce, tx.Gas(), tx.GasTipCap(), tx.GasFeeCap(), tx.BlobGasFeeCap())
+	if err != nil {
+		nonceManager.Release(nonce)
+		return nil, err
+	}
+	req := newTxnRequest(txWithNonce)
+	req.from = from
+	return req, nil
+}
+
+func (t *GeometricTxManager) resyncNoncesOnNonceError(ctx context.Context, req *txnRequest, err error) {
+	nonceManager, ok := t.nonceManagers[req.from]
+	if !ok || !txmgr.IsNonceError(err) {
+		return
+	}
+	t.logger.Warn("nonce error while sending tx, resyncing nonces", "from", req.from.Hex(),
+		"nonce", req.currentTx().Nonce(), "err", err)
+	if resyncErr := nonceManager.Resync(ctx); resyncErr != nil {
+		t.logger.Error("failed to resync nonces", "err", resyncErr)
+	}
+}
+
+// broadcastTransaction sends the first transaction of req, which monitorTransaction then bumps until it gets
+// included.
+// broadcastTransaction can be called concurrently, so sending multiple txs in parallel is safe.
 // However, the nonces have to be set correctly by the caller. One could send txs with nonces 3,2,1,0 in this order.
 // But sending nonces 2,1 and forgetting 0 would cause the manager to get stuck waiting for nonce 0 to be mined.
 // Thus a wallet which manages nonces should be used to ensure the correct nonce is set.
-func (t *GeometricTxManager) processTransaction(ctx context.Context, req *txnRequest) (*types.Receipt, error) {
+func (t *GeometricTxManager) broadcastTransaction(ctx context.Context, req *txnRequest) error {
 	t.logger.Debug("new transaction",
 		"nonce", req.tx.Nonce(), "gasFeeCap", req.tx.GasFeeCap(), "gasTipCap", req.tx.GasTipCap(),
 	)
-	t.metrics.IncrementProcessingTxCount()
-	defer t.metrics.DecrementProcessingTxCount()
-
-	from, err := t.wallet.SenderAddress(ctx)
-	if err != nil {
-		return nil, utils.WrapError("failed to get sender address", err)
-	}
 
 	var txn *types.Transaction
 	var txID wallet.TxID
+	var err error
 	retryFromFailure := 0
 	for retryFromFailure < t.params.MaxSendTransactionRetry {
 		gasTipCap, err := t.estimateGasTipCap(ctx)
 		if err != nil {
-			return nil, utils.WrapError("failed to estimate gas tip cap", err)
+			return utils.WrapError("failed to estimate gas tip cap", err)
 		}
-		txn, err = t.updateGasTipCap(ctx, req.tx, gasTipCap, from)
+		txn, err = t.updateGasTipCap(ctx, req.tx, gasTipCap, nil, nil, req.from)
 		if err != nil {
-			return nil, utils.WrapError("failed to update gas price", err)
+			return utils.WrapError("failed to update gas price", err)
 		}
 		txID, err = t.wallet.SendTransaction(ctx, txn)
 		// the fireblocks and privatekey wallets use go's net.Http client which returns a url.Error on timeouts
@@ -233,7 +507,7 @@ func (t *GeometricTxManager) processTransaction(ctx context.Context, req *txnReq
 			retryFromFailure++
 			continue
 		} else if err != nil {
-			return nil, utils.WrapError(fmt.Errorf("failed to send txn %s", txn.Hash().Hex()), err)
+			return utils.WrapError(fmt.Errorf("failed to send txn %s", txn.Hash().Hex()), err)
 		} else {
 			t.logger.Debug("successfully sent txn", "txID", txID, "txHash", txn.Hash().Hex())
 			break
@@ -241,24 +515,20 @@ func (t *GeometricTxManager) processTransaction(ctx context.Context, req *txnReq
 	}
 	// if all attempts to send the tx failed, return an error
 	if txn == nil || txID == "" {
-		return nil, utils.WrapError(fmt.Errorf("failed to send txn %s", req.tx.Hash().Hex()), err)
+		return utils.WrapError(fmt.Errorf("failed to send txn %s", req.tx.Hash().Hex()), err)
 	}
 
 	req.tx = txn
-	req.txAttempts = append(req.txAttempts, &transaction{
+	attempt := &transaction{
 		TxID:        txID,
 		Transaction: txn,
 		requestedAt: time.Now(),
-	})
-
-	receipt, err := t.monitorTransaction(ctx, req)
-	if err == nil {
-		if receipt.GasUsed > 0 {
-			t.metrics.ObserveGasUsedWei(receipt.GasUsed)
-		}
-		t.metrics.ObserveConfirmationLatencyMs(time.Since(req.requestedAt).Milliseconds())
+		state:       TxStateQueued,
 	}
-	return receipt, err
+	req.txAttempts = append(req.txAttempts, attempt)
+	t.changeTxState(attempt, TxStateBroadcast)
+
+	return nil
 }
 
 // ensureAnyFireblocksTransactionBroadcasted waits until at least one of the bumped transactions are broadcasted to the
@@ -287,24 +557,28 @@ func (t *GeometricTxManager) ensureAnyFireblocksTransactionBroadcasted(ctx conte
 	}
 }
 
-// ensureAnyTransactionConfirmed waits until at least one of the transactions is confirmed (mined + confirmationBlocks
-// blocks). It returns the receipt of the first transaction that is confirmed (only one tx can ever be mined given they
-// all have the same nonce).
+// ensureAnyTransactionConfirmed waits until at least one of the transactions of req is confirmed (mined +
+// confirmationBlocks blocks). It returns the receipt of the first transaction that is confirmed (only one tx can ever
+// be mined given they all have the same nonce). The transactions sent by Cancel meanwhile are queried too.
 func (t *GeometricTxManager) ensureAnyTransactionConfirmed(
 	ctx context.Context,
-	txs []*transaction,
+	req *txnRequest,
 ) (*types.Receipt, error) {
 	queryTicker := time.NewTicker(t.params.GetTxReceiptTickerDuration)
 	defer queryTicker.Stop()
 	var receipt *types.Receipt
 	var err error
 	// transactions that need to be queried. Some transactions will be removed from this map depending on their status.
-	txnsToQuery := make(map[wallet.TxID]*types.Transaction, len(txs))
-	for _, tx := range txs {
-		txnsToQuery[tx.TxID] = tx.Transaction
-	}
+	txnsToQuery := make(map[wallet.TxID]*types.Transaction)
+	numQueuedAttempts := 0
 
 	for {
+		txs := req.attempts()
+		for _, tx := range txs[numQueuedAttempts:] {
+			txnsToQuery[tx.TxID] = tx.Transaction
+		}
+		numQueuedAttempts = len(txs)
+
 		for txID, tx := range txnsToQuery {
 			receipt, err = t.wallet.GetTransactionReceipt(ctx, txID)
 			if err == nil {
@@ -321,8 +595,13 @@ func (t *GeometricTxManager) ensureAnyTransactionConfirmed(
 							chainTip,
 						)
 						break
-					} else {
+					} else if t.isCanonical(ctx, receipt) {
+						req.setConfirmed(txID)
 						return receipt, nil
+					} else {
+						// the tx isn't mined anymore, so it can be sped up
+						receipt = nil
+						break
 					}
 				} else {
 					t.logger.Debug("failed to get chain tip while waiting for transaction to mine", "err", err)
@@ -361,19 +640,44 @@ func (t *GeometricTxManager) ensureAnyTransactionConfirmed(
 	}
 }
 
+// isCanonical returns whether the block of receipt is still part of the canonical chain. Without confirmation blocks,
+// the receipt was just queried so it is canonical.
+func (t *GeometricTxManager) isCanonical(ctx context.Context, receipt *types.Receipt) bool {
+	if t.params.ConfirmationBlocks == 0 {
+		return true
+	}
+	header, err := t.ethClient.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		t.logger.Debug("failed to get header of the transaction block", "blockNumber", receipt.BlockNumber, "err", err)
+		return false
+	}
+	if header.Hash() != receipt.BlockHash {
+		t.logger.Warn(
+			"transaction block was reorged out, waiting for the transaction to be mined again",
+			"txHash",
+			receipt.TxHash.Hex(),
+			"blockNumber",
+			receipt.BlockNumber,
+		)
+		return false
+	}
+	return true
+}
+
 // monitorTransaction waits until the transaction is confirmed (or failed) and resends it with a higher gas price if it
 // is not mined without a timeout.
 // It returns the receipt once the transaction has been confirmed.
 // It returns an error if the transaction fails to be sent for reasons other than timeouts.
 func (t *GeometricTxManager) monitorTransaction(ctx context.Context, req *txnRequest) (*types.Receipt, error) {
-	numSpeedUps := 0
+	numSpeedUps := req.numSpeedUps
 	retryFromFailure := 0
 
 	var receipt *types.Receipt
 	var err error
 
 	rpcCallAttempt := func() error {
-		t.logger.Debug("monitoring transaction", "txHash", req.tx.Hash().Hex(), "nonce", req.tx.Nonce())
+		tx := req.currentTx()
+		t.logger.Debug("monitoring transaction", "txHash", tx.Hash().Hex(), "nonce", tx.Nonce())
 
 		ctxWithTimeout, cancelBroadcastTimeout := context.WithTimeout(ctx, t.params.TxnBroadcastTimeout)
 		defer cancelBroadcastTimeout()
@@ -384,18 +688,18 @@ func (t *GeometricTxManager) monitorTransaction(ctx context.Context, req *txnReq
 			// Fireblocks wallet is used, there may be delays in broadcasting the transaction due to
 			// latency from cosigning and MPC operations. We thus make sure that at least one of the
 			// bumped transactions are broadcasted to the network before querying for its receipt.
-			err = t.ensureAnyFireblocksTransactionBroadcasted(ctxWithTimeout, req.txAttempts)
+			err = t.ensureAnyFireblocksTransactionBroadcasted(ctxWithTimeout, req.attempts())
 			if err != nil && errors.Is(err, context.DeadlineExceeded) {
 				t.logger.Warn(
 					"transaction not broadcasted within timeout",
 					"txHash",
-					req.tx.Hash().Hex(),
+					tx.Hash().Hex(),
 					"nonce",
-					req.tx.Nonce(),
+					tx.Nonce(),
 				)
 				// Consider these transactions failed as they haven't been broadcasted within timeout.
 				// Cancel these transactions to avoid blocking the next transactions.
-				for _, tx := range req.txAttempts {
+				for _, tx := range req.attempts() {
 					cancelled, err := fireblocksWallet.CancelTransactionBroadcast(ctx, tx.TxID)
 					if err != nil {
 						t.logger.Warn("failed to cancel Fireblocks transaction broadcast", "txID", tx.TxID, "err", err)
@@ -403,19 +707,16 @@ func (t *GeometricTxManager) monitorTransaction(ctx context.Context, req *txnReq
 						t.logger.Info("cancelled Fireblocks transaction broadcast because it didn't get broadcasted within timeout", "txID", tx.TxID, "timeout", t.params.TxnBroadcastTimeout.String())
 					}
 				}
-				return fmt.Errorf("transaction %x (with nonce %d) not broadcasted", req.tx.Hash(), req.tx.Nonce())
+				return fmt.Errorf("transaction %x (with nonce %d) not broadcasted", tx.Hash(), tx.Nonce())
 			} else if err != nil {
-				t.logger.Error("unexpected error while waiting for Fireblocks transaction to broadcast", "txHash", req.tx.Hash().Hex(), "err", err)
+				t.logger.Error("unexpected error while waiting for Fireblocks transaction to broadcast", "txHash", tx.Hash().Hex(), "err", err)
 				return err
 			}
 		}
 
 		ctxWithTimeout, cancelEvaluationTimeout := context.WithTimeout(ctx, t.params.TxnConfirmationTimeout)
 		defer cancelEvaluationTimeout()
-		receipt, err = t.ensureAnyTransactionConfirmed(
-			ctxWithTimeout,
-			req.txAttempts,
-		)
+		receipt, err = t.ensureAnyTransactionConfirmed(ctxWithTimeout, req)
 		return err
 	}
 
@@ -429,62 +730,108 @@ func (t *GeometricTxManager) monitorTransaction(ctx context.Context, req *txnReq
 			return receipt, nil
 		}
 
+		tx := req.currentTx()
 		if errors.Is(err, context.DeadlineExceeded) {
+			if ctx.Err() != nil {
+				// the caller's context is done, not just the confirmation timeout
+				return receipt, ctx.Err()
+			}
 			if receipt != nil {
 				t.logger.Warn(
 					"transaction has been mined, but hasn't accumulated the required number of confirmations",
 					"txHash",
-					req.tx.Hash().Hex(),
+					tx.Hash().Hex(),
 					"nonce",
-					req.tx.Nonce(),
+					tx.Nonce(),
+				)
+				continue
+			}
+			if t.params.MaxSpeedUps > 0 && numSpeedUps >= t.params.MaxSpeedUps {
+				t.logger.Warn(
+					"transaction not mined within timeout, but max number of speed ups reached, waiting",
+					"txHash",
+					tx.Hash().Hex(),
+					"nonce",
+					tx.Nonce(),
+					"numSpeedUps",
+					numSpeedUps,
 				)
 				continue
 			}
 			t.logger.Warn(
 				"transaction not mined within timeout, resending with higher gas price",
 				"txHash",
-				req.tx.Hash().Hex(),
+				tx.Hash().Hex(),
 				"nonce",
-				req.tx.Nonce(),
+				tx.Nonce(),
 			)
-			newTx, err := t.speedUpTxn(ctx, req.tx, numSpeedUps)
-			if err != nil {
+			// the transaction is sped up and replaced atomically, so that a concurrent Cancel isn't overridden
+			var txID wallet.TxID
+			var sendErr error
+			req.replaceMu.Lock()
+			newTx, err := t.speedUpTxn(ctx, req.currentTx(), numSpeedUps)
+			if err == nil {
+				txID, sendErr = t.wallet.SendTransaction(ctx, newTx)
+				req.mu.Lock()
+				if sendErr == nil || isReplacementUnderpricedError(sendErr) {
+					// on an underpriced error, the node requires a higher bump than ours: the next speed up is made on
+					// top of this transaction
+					req.tx = newTx
+				}
+				if sendErr == nil {
+					t.replaceTx(req, &transaction{
+						TxID:        txID,
+						Transaction: newTx,
+						requestedAt: time.Now(),
+						state:       TxStateQueued,
+					})
+				}
+				req.mu.Unlock()
+			}
+			req.replaceMu.Unlock()
+
+			if errors.Is(err, errGasFeeCapLimitReached) {
+				t.logger.Warn("cannot speed up transaction further, waiting", "txHash", tx.Hash().Hex(), "err", err)
+				continue
+			} else if err != nil {
 				t.logger.Error("failed to speed up transaction", "err", err)
 				t.metrics.IncrementProcessedTxsTotal("failure")
 				return nil, err
 			}
-			txID, err := t.wallet.SendTransaction(ctx, newTx)
-			if err != nil {
+			if isReplacementUnderpricedError(sendErr) {
+				t.logger.Warn("replacement transaction underpriced, bumping again", "txHash", newTx.Hash().Hex())
+				numSpeedUps++
+				continue
+			} else if isAlreadyMinedError(sendErr) {
+				// one of the previous transactions was mined in the meantime, which the next round will find
+				t.logger.Debug("transaction nonce already used, not replacing it", "txHash", newTx.Hash().Hex())
+				continue
+			} else if sendErr != nil {
 				if retryFromFailure >= t.params.MaxSendTransactionRetry {
 					t.logger.Warn(
 						"failed to send txn - retries exhausted",
 						"txn",
-						req.tx.Hash().Hex(),
+						tx.Hash().Hex(),
 						"attempt",
 						retryFromFailure,
 						"maxRetry",
 						t.params.MaxSendTransactionRetry,
 						"err",
-						err,
+						sendErr,
 					)
 					t.metrics.IncrementProcessedTxsTotal("failure")
-					return nil, err
+					return nil, sendErr
 				} else {
-					t.logger.Warn("failed to send txn - retrying", "txn", req.tx.Hash().Hex(), "attempt", retryFromFailure, "maxRetry", t.params.MaxSendTransactionRetry, "err", err)
+					t.logger.Warn("failed to send txn - retrying", "txn", tx.Hash().Hex(), "attempt", retryFromFailure, "maxRetry", t.params.MaxSendTransactionRetry, "err", sendErr)
 				}
 				retryFromFailure++
 				continue
 			}
 
 			t.logger.Debug("successfully sent txn", "txID", txID, "txHash", newTx.Hash().Hex())
-			req.tx = newTx
-			req.txAttempts = append(req.txAttempts, &transaction{
-				TxID:        txID,
-				Transaction: newTx,
-			})
 			numSpeedUps++
 		} else {
-			t.logger.Error("transaction failed", "txHash", req.tx.Hash().Hex(), "err", err)
+			t.logger.Error("transaction failed", "txHash", tx.Hash().Hex(), "err", err)
 			t.metrics.IncrementProcessedTxsTotal("failure")
 			return nil, err
 		}
@@ -498,8 +845,32 @@ func (t *GeometricTxManager) monitorTransaction(ctx context.Context, req *txnReq
 	}
 }
 
+// isReplacementUnderpricedError returns whether the node rejected a transaction because it doesn't increase the fees
+// of the transaction it replaces enough
+func isReplacementUnderpricedError(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "replacement transaction underpriced")
+}
+
+// isAlreadyMinedError returns whether the node rejected a transaction because its nonce was already used
+func isAlreadyMinedError(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// minReplacementFee returns the minimum value of a fee of tx to replace it, which is bumped by more for blob
+// transactions
+func minReplacementFee(tx *types.Transaction, fee *big.Int) *big.Int {
+	bumpPercentage := int64(minReplacementBumpPercentage)
+	if tx.Type() == types.BlobTxType {
+		bumpPercentage = txmgr.BlobTxPriceBumpPercentage
+	}
+	minFee := new(big.Int).Mul(fee, big.NewInt(100+bumpPercentage))
+	return minFee.Div(minFee, big.NewInt(100))
+}
+
 // speedUpTxn increases the gas price of the existing transaction by specified percentage.
-// It makes sure the new gas price is not lower than the current gas price.
+// It makes sure the new gas price is not lower than the current gas price, and that both the gas tip and fee cap are
+// increased enough to replace the existing transaction.
+// It returns errGasFeeCapLimitReached if MaxGasFeeCap prevents that.
 func (t *GeometricTxManager) speedUpTxn(
 	ctx context.Context,
 	tx *types.Transaction,
@@ -513,10 +884,11 @@ func (t *GeometricTxManager) speedUpTxn(
 			return nil, utils.WrapError("failed to estimate gas tip cap", err)
 		}
 		bumpedGasTipCap := t.addGasTipCapBuffer(tx.GasTipCap())
-		if estimatedGasTipCap.Cmp(bumpedGasTipCap) > 0 {
-			newGasTipCap = estimatedGasTipCap
-		} else {
-			newGasTipCap = bumpedGasTipCap
+		newGasTipCap = minReplacementFee(tx, tx.GasTipCap())
+		for _, gasTipCap := range []*big.Int{estimatedGasTipCap, bumpedGasTipCap} {
+			if gasTipCap.Cmp(newGasTipCap) > 0 {
+				newGasTipCap = gasTipCap
+			}
 		}
 	}
 
@@ -524,10 +896,24 @@ func (t *GeometricTxManager) speedUpTxn(
 	if err != nil {
 		return nil, utils.WrapError("failed to get sender address", err)
 	}
-	newTx, err := t.updateGasTipCap(ctx, tx, newGasTipCap, from)
+	var minBlobGasFeeCap *big.Int
+	if tx.Type() == types.BlobTxType {
+		minBlobGasFeeCap = minReplacementFee(tx, tx.BlobGasFeeCap())
+	}
+	minGasFeeCap := minReplacementFee(tx, tx.GasFeeCap())
+	newTx, err := t.updateGasTipCap(ctx, tx, newGasTipCap, minGasFeeCap, minBlobGasFeeCap, from)
 	if err != nil {
 		return nil, utils.WrapError("failed to update gas price", err)
 	}
+	if newTx.GasTipCap().Cmp(minReplacementFee(tx, tx.GasTipCap())) < 0 ||
+		newTx.GasFeeCap().Cmp(minReplacementFee(tx, tx.GasFeeCap())) < 0 {
+		return nil, fmt.Errorf(
+			"%w: cannot replace tx with gasTipCap %s and gasFeeCap %s",
+			errGasFeeCapLimitReached,
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+		)
+	}
 	t.logger.Info(
 		"increasing gas price",
 		"numSpeedUps", numSpeedUps,
@@ -542,51 +928,142 @@ func (t *GeometricTxManager) speedUpTxn(
 // UpdateGasParams updates the three gas related parameters of a transaction:
 // - gasTipCap: calls the json-rpc method eth_maxPriorityFeePerGas and
 // adds a extra buffer based on o.params.GasTipMultiplierPercentage
-// - gasFeeCap: calculates the gas fee cap as 2 * baseFee + gasTipCap
+// - gasFeeCap: calculates the gas fee cap as 2 * baseFee + gasTipCap, raised to minGasFeeCap if set
+// Both the gas fee cap and tip cap are capped to MaxGasFeeCap if set
 // - gasLimit: calls the json-rpc method eth_estimateGas and
-// adds a extra buffer based on o.params.GasMultiplierPercentage
+// adds a extra buffer based on o.params.GasMultiplier, or uses o.params.FallbackGasLimit if the estimation reverts
+// For blob transactions, the blob gas fee cap is also set to 2 * blobBaseFee, raised to minBlobGasFeeCap if set.
 func (t *GeometricTxManager) updateGasTipCap(
 	ctx context.Context,
 	tx *types.Transaction,
 	newGasTipCap *big.Int,
+	minGasFeeCap *big.Int,
+	minBlobGasFeeCap *big.Int,
 	from common.Address,
 ) (*types.Transaction, error) {
+	legacy, err := t.gasPricing.IsLegacy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if legacy {
+		return t.updateGasPrice(ctx, tx, newGasTipCap, minGasFeeCap, from)
+	}
+
 	gasFeeCap, err := t.estimateGasFeeCap(ctx, newGasTipCap)
 	if err != nil {
 		return nil, utils.WrapError("failed to estimate gas fee cap", err)
 	}
+	if minGasFeeCap != nil && gasFeeCap.Cmp(minGasFeeCap) < 0 {
+		gasFeeCap = new(big.Int).Set(minGasFeeCap)
+	}
+	if t.params.MaxGasFeeCap > 0 {
+		maxGasFeeCap := new(big.Int).SetUint64(t.params.MaxGasFeeCap)
+		if gasFeeCap.Cmp(maxGasFeeCap) > 0 {
+			gasFeeCap = maxGasFeeCap
+		}
+		if newGasTipCap.Cmp(gasFeeCap) > 0 {
+			newGasTipCap = new(big.Int).Set(gasFeeCap)
+		}
+	}
+
+	var blobGasFeeCap *big.Int
+	if tx.Type() == types.BlobTxType {
+		header, err := t.ethClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, utils.WrapError("failed to get latest header", err)
+		}
+		blobGasFeeCap, err = txmgr.EstimateBlobGasFeeCap(header)
+		if err != nil {
+			return nil, err
+		}
+		if minBlobGasFeeCap != nil && blobGasFeeCap.Cmp(minBlobGasFeeCap) < 0 {
+			blobGasFeeCap = new(big.Int).Set(minBlobGasFeeCap)
+		}
+		// the blob hashes are needed by the gas estimation
+		tx, err = txmgr.NewTxWithFees(tx, tx.Nonce(), tx.Gas(), newGasTipCap, gasFeeCap, blobGasFeeCap)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// we reestimate the gas limit because the state of the chain may have changed,
-	// which could cause the previous gas limit to be insufficient
-	gasLimit, err := t.ethClient.EstimateGas(ctx, ethereum.CallMsg{
-		From:      from,
-		To:        tx.To(),
-		GasTipCap: newGasTipCap,
-		GasFeeCap: gasFeeCap,
-		Value:     tx.Value(),
-		Data:      tx.Data(),
+	// which could cause the previous gas limit to be insufficient.
+	// we also add a buffer to the gas limit to account for potential changes in the state of the chain
+	// between the time of estimation and the time the transaction is mined
+	gasLimit, err := txmgr.EstimateGasLimit(ctx, t.ethClient, t.logger, ethereum.CallMsg{
+		From:          from,
+		To:            tx.To(),
+		GasTipCap:     newGasTipCap,
+		GasFeeCap:     gasFeeCap,
+		Value:         tx.Value(),
+		Data:          tx.Data(),
+		BlobGasFeeCap: blobGasFeeCap,
+		BlobHashes:    tx.BlobHashes(),
+	}, txmgr.GasLimitOptions{
+		GasLimitMultiplier: t.params.GasMultiplier,
+		FallbackGasLimit:   t.params.FallbackGasLimit,
 	})
 	if err != nil {
 		return nil, utils.WrapError("failed to estimate gas", err)
 	}
-	// we also add a buffer to the gas limit to account for potential changes in the state of the chain
-	// between the time of estimation and the time the transaction is mined
-	bufferedGasLimit := t.addGasBuffer(gasLimit)
 
-	return types.NewTx(&types.DynamicFeeTx{
-		ChainID:    tx.ChainId(),
-		Nonce:      tx.Nonce(),
-		GasTipCap:  newGasTipCap,
-		GasFeeCap:  gasFeeCap,
-		Gas:        bufferedGasLimit,
-		To:         tx.To(),
-		Value:      tx.Value(),
-		Data:       tx.Data(),
-		AccessList: tx.AccessList(),
-	}), nil
+	return txmgr.NewTxWithFees(tx, tx.Nonce(), gasLimit, newGasTipCap, gasFeeCap, blobGasFeeCap)
+}
+
+// updateGasPrice is the updateGasTipCap of legacy transactions, which only have a gas price: newGasPrice, raised to
+// minGasPrice if set and capped to MaxGasFeeCap if set
+func (t *GeometricTxManager) updateGasPrice(
+	ctx context.Context,
+	tx *types.Transaction,
+	newGasPrice *big.Int,
+	minGasPrice *big.Int,
+	from common.Address,
+) (*types.Transaction, error) {
+	if tx.Type() == types.BlobTxType {
+		return nil, txmgr.ErrBlobTxUnsupported
+	}
+	gasPrice := new(big.Int).Set(newGasPrice)
+	if minGasPrice != nil && gasPrice.Cmp(minGasPrice) < 0 {
+		gasPrice.Set(minGasPrice)
+	}
+	if t.params.MaxGasFeeCap > 0 {
+		maxGasPrice := new(big.Int).SetUint64(t.params.MaxGasFeeCap)
+		if gasPrice.Cmp(maxGasPrice) > 0 {
+			gasPrice = maxGasPrice
+		}
+	}
+
+	gasLimit, err := txmgr.EstimateGasLimit(ctx, t.ethClient, t.logger, ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		GasPrice: gasPrice,
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}, txmgr.GasLimitOptions{
+		GasLimitMultiplier: t.params.GasMultiplier,
+		FallbackGasLimit:   t.params.FallbackGasLimit,
+	})
+	if err != nil {
+		return nil, utils.WrapError("failed to estimate gas", err)
+	}
+
+	return txmgr.NewLegacyTxWithGasPrice(tx, tx.Nonce(), gasLimit, gasPrice)
 }
 
 func (t *GeometricTxManager) estimateGasTipCap(ctx context.Context) (gasTipCap *big.Int, err error) {
+	legacy, err := t.gasPricing.IsLegacy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if legacy {
+		// the gas price of legacy transactions is used as their gas tip cap
+		gasPrice, err := t.ethClient.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, utils.WrapError("failed to suggest gas price", err)
+		}
+		return t.addGasTipCapBuffer(gasPrice), nil
+	}
+
 	gasTipCap, err = t.ethClient.SuggestGasTipCap(ctx)
 	if err != nil {
 		// If the transaction failed because the backend does not support
@@ -621,7 +1098,3 @@ func (t *GeometricTxManager) estimateGasFeeCap(ctx context.Context, gasTipCap *b
 	}
 	return new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), gasTipCap), nil
 }
-
-func (t *GeometricTxManager) addGasBuffer(gasLimit uint64) uint64 {
-	return uint64(t.params.GasMultiplier * float64(gasLimit))
-}
@@ -2,21 +2,32 @@ package geometric
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
 	"github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 )
@@ -26,6 +37,7 @@ var chainId = big.NewInt(31337)
 type testHarness struct {
 	fakeEthBackend *fakeEthBackend
 	txmgr          *GeometricTxManager
+	sender         common.Address
 }
 
 func (h *testHarness) validateTxReceipt(t *testing.T, txReceipt *types.Receipt) {
@@ -39,15 +51,7 @@ func (h *testHarness) validateTxReceipt(t *testing.T, txReceipt *types.Receipt)
 func newTestHarness(t *testing.T, geometricTxnManagerParams *GeometricTxnManagerParams) *testHarness {
 	logger := testutils.NewTestLogger()
 	ethBackend := NewFakeEthBackend()
-
-	ecdsaSk, ecdsaAddr, err := testutils.NewEcdsaSkAndAddress()
-	require.NoError(t, err)
-
-	signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
-	require.NoError(t, err)
-
-	skWallet, err := wallet.NewPrivateKeyWallet(ethBackend, signerFn, ecdsaAddr, logger)
-	require.NoError(t, err)
+	skWallet, ecdsaAddr := newTestWallet(t, ethBackend)
 
 	if geometricTxnManagerParams == nil {
 		geometricTxnManagerParams = &GeometricTxnManagerParams{
@@ -66,9 +70,21 @@ func newTestHarness(t *testing.T, geometricTxnManagerParams *GeometricTxnManager
 	return &testHarness{
 		fakeEthBackend: ethBackend,
 		txmgr:          txmgr,
+		sender:         ecdsaAddr,
 	}
 }
 
+// newTestWallet returns a wallet with a new key, broadcasting with ethBackend
+func newTestWallet(t *testing.T, ethBackend wallet.EthBackend) (wallet.Wallet, common.Address) {
+	ecdsaSk, ecdsaAddr, err := testutils.NewEcdsaSkAndAddress()
+	require.NoError(t, err)
+	signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
+	require.NoError(t, err)
+	skWallet, err := wallet.NewPrivateKeyWallet(ethBackend, signerFn, ecdsaAddr, testutils.NewTestLogger())
+	require.NoError(t, err)
+	return skWallet, ecdsaAddr
+}
+
 func TestGeometricTxManager(t *testing.T) {
 	t.Run("Send 1 tx", func(t *testing.T) {
 		h := newTestHarness(t, nil)
@@ -192,7 +208,10 @@ func TestGeometricTxManager(t *testing.T) {
 		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 		_, err := h.txmgr.Send(ctxWithTimeout, tx, true)
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		var deadlineErr *txmgr.ErrTxDeadlineExceeded
+		require.ErrorAs(t, err, &deadlineErr)
+		require.Equal(t, uint64(100), deadlineErr.Nonce)
 
 	})
 
@@ -209,6 +228,827 @@ func TestGeometricTxManager(t *testing.T) {
 		_, err = h.txmgr.Send(ctxWithTimeout, unsignedTx, true)
 		require.Error(t, err)
 	})
+
+	t.Run("SendAsync n=3 txs", func(t *testing.T) {
+		n := uint64(3)
+		h := newTestHarness(t, nil)
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		resultChans := make([]<-chan txmgr.TxResult, n)
+		for nonce := uint64(0); nonce < n; nonce++ {
+			resultChan, err := h.txmgr.SendAsync(ctxWithTimeout, newUnsignedEthTransferTx(nonce, nil))
+			require.NoError(t, err)
+			resultChans[nonce] = resultChan
+		}
+		for _, resultChan := range resultChans {
+			result := <-resultChan
+			require.NoError(t, result.Err)
+			h.validateTxReceipt(t, result.Receipt)
+			_, ok := <-resultChan
+			require.False(t, ok)
+		}
+	})
+
+	t.Run("SendAsync returns the error of the broadcast", func(t *testing.T) {
+		h := newTestHarness(t, nil)
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.NoError(t, err)
+
+		_, err = h.txmgr.SendAsync(ctxWithTimeout, newUnsignedEthTransferTx(0, nil))
+		require.Error(t, err)
+	})
+
+	t.Run("Cancelling SendAsync stops waiting without unsending the tx", func(t *testing.T) {
+		h := newTestHarness(t, nil)
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.minMinedGasTipCap = new(big.Int).Lsh(big.NewInt(1), 128)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resultChan, err := h.txmgr.SendAsync(ctx, newUnsignedEthTransferTx(0, nil))
+		require.NoError(t, err)
+		// SendAsync returns once the tx is broadcast
+		h.fakeEthBackend.mu.Lock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 1)
+		h.fakeEthBackend.mu.Unlock()
+
+		cancel()
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, context.Canceled)
+		require.Nil(t, result.Receipt)
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Contains(t, h.fakeEthBackend.mempool, senderNonce{from: h.sender, nonce: 0})
+	})
+
+	t.Run("Cancel replaces the stuck tx with a self-transfer", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			// long enough for the txmgr to not speed up the tx itself
+			TxnConfirmationTimeout: 10 * time.Second,
+			GasTipMultiplier:       1.2,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		// the tx pays a 1.2M tip, and its cancellation at least 20% more
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resultChan, err := h.txmgr.SendAsync(ctxWithTimeout, newUnsignedEthTransferTx(0, nil))
+		require.NoError(t, err)
+
+		cancelReceipt, err := h.txmgr.Cancel(ctxWithTimeout, 0)
+		require.NoError(t, err)
+		h.validateTxReceipt(t, cancelReceipt)
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, ErrTxCancelled)
+		require.Nil(t, result.Receipt)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 2)
+		cancelTx := h.fakeEthBackend.sentTxs[1]
+		require.Equal(t, cancelTx.Hash(), cancelReceipt.TxHash)
+		require.Equal(t, uint64(0), cancelTx.Nonce())
+		require.Zero(t, cancelTx.Value().Sign())
+		from, err := h.txmgr.wallet.SenderAddress(ctxWithTimeout)
+		require.NoError(t, err)
+		require.Equal(t, from, *cancelTx.To())
+	})
+
+	t.Run("Cancel replaces a stuck blob tx with a blob self-transfer", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     10 * time.Second,
+			GasTipMultiplier:           1.2,
+		})
+		excessBlobGas := uint64(10_000_000)
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.excessBlobGas = &excessBlobGas
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		blobTx := txmgr.NewBlobTxCandidate(chainId, common.Address{0x1}, nil, []kzg4844.Blob{{0x1}})
+		resultChan, err := h.txmgr.SendAsync(ctxWithTimeout, blobTx)
+		require.NoError(t, err)
+
+		cancelReceipt, err := h.txmgr.Cancel(ctxWithTimeout, 0)
+		require.NoError(t, err)
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, ErrTxCancelled)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 2)
+		stuckTx, cancelTx := h.fakeEthBackend.sentTxs[0], h.fakeEthBackend.sentTxs[1]
+		require.Equal(t, cancelTx.Hash(), cancelReceipt.TxHash)
+		require.Equal(t, uint8(types.BlobTxType), cancelTx.Type())
+		require.Equal(t, h.sender, *cancelTx.To())
+		require.Zero(t, cancelTx.Value().Sign())
+		require.Equal(t, stuckTx.BlobHashes(), cancelTx.BlobHashes())
+		require.NotNil(t, cancelTx.BlobTxSidecar())
+		double := func(n *big.Int) *big.Int { return new(big.Int).Mul(n, big.NewInt(2)) }
+		require.GreaterOrEqual(t, cancelTx.BlobGasFeeCap().Cmp(double(stuckTx.BlobGasFeeCap())), 0)
+	})
+
+	t.Run("Monitoring isn't stalled while a cancellation is broadcast", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     10 * time.Second,
+			GasTipMultiplier:           1.2,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000)
+		h.fakeEthBackend.mu.Unlock()
+		backend := &blockingSendEthBackend{
+			fakeEthBackend: h.fakeEthBackend,
+			sendBlocked:    make(chan struct{}),
+			release:        make(chan struct{}),
+		}
+		blockingWallet, sender := newTestWallet(t, backend)
+		blockingTxmgr := NewGeometricTxnManager(
+			h.fakeEthBackend, blockingWallet, testutils.NewTestLogger(), NewNoopMetrics(), h.txmgr.params,
+		)
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resultChan, err := blockingTxmgr.SendAsync(ctxWithTimeout, newUnsignedEthTransferTx(0, nil))
+		require.NoError(t, err)
+		blockingTxmgr.inFlightMu.Lock()
+		req := blockingTxmgr.inFlight[senderNonce{from: sender, nonce: 0}]
+		blockingTxmgr.inFlightMu.Unlock()
+		require.NotNil(t, req)
+
+		backend.blockSends.Store(true)
+		cancelErrC := make(chan error)
+		go func() {
+			_, err := blockingTxmgr.Cancel(ctxWithTimeout, 0)
+			cancelErrC <- err
+		}()
+		<-backend.sendBlocked
+		// the transactions of the request can be read while the cancellation is broadcast
+		readC := make(chan struct{})
+		go func() {
+			req.currentTx()
+			req.attempts()
+			close(readC)
+		}()
+		select {
+		case <-readC:
+		case <-time.After(time.Second):
+			require.FailNow(t, "the request is locked while the cancellation is broadcast")
+		}
+
+		close(backend.release)
+		require.NoError(t, <-cancelErrC)
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, ErrTxCancelled)
+	})
+
+	t.Run("Cancellation is sent through the private relay like the tx", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     10 * time.Second,
+			GasTipMultiplier:           1.2,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000)
+		h.fakeEthBackend.mu.Unlock()
+		relay := &fakeRelayService{builder: h.fakeEthBackend}
+		rpcServer := rpc.NewServer()
+		require.NoError(t, rpcServer.RegisterName("eth", relay))
+		relayServer := httptest.NewServer(rpcServer)
+		defer relayServer.Close()
+		defer rpcServer.Stop()
+		broadcaster, err := txmgr.NewPrivateRelayBroadcaster(
+			relayServer.URL, h.fakeEthBackend, testutils.NewTestLogger(),
+		)
+		require.NoError(t, err)
+		relayWallet, _ := newTestWallet(t, broadcaster)
+		relayTxmgr := NewGeometricTxnManager(
+			h.fakeEthBackend, relayWallet, testutils.NewTestLogger(), NewNoopMetrics(), h.txmgr.params,
+		)
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resultChan, err := relayTxmgr.SendAsync(ctxWithTimeout, newUnsignedEthTransferTx(0, nil))
+		require.NoError(t, err)
+		_, err = relayTxmgr.Cancel(ctxWithTimeout, 0)
+		require.NoError(t, err)
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, ErrTxCancelled)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		relay.mu.Lock()
+		defer relay.mu.Unlock()
+		// the txs only reached the mempool through the relay
+		require.Len(t, h.fakeEthBackend.sentTxs, 2)
+		require.Len(t, relay.sentTxs, 2)
+		for i, tx := range relay.sentTxs {
+			require.Equal(t, h.fakeEthBackend.sentTxs[i].Hash(), tx.Hash())
+		}
+	})
+
+	t.Run("Cancel returns ErrCancelLost when the tx is mined first", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			// the tx is mined between two receipt queries, and cancelled meanwhile
+			GetTxReceiptTickerDuration: 2 * time.Second,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     10 * time.Second,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.minMinedGasTipCap = new(big.Int).Lsh(big.NewInt(1), 128)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		resultChan, err := h.txmgr.SendAsync(ctxWithTimeout, newUnsignedEthTransferTx(0, nil))
+		require.NoError(t, err)
+		// mine the tx once the txmgr queried its receipt a first time, so that it only finds it 2s later
+		require.Eventually(t, func() bool {
+			h.fakeEthBackend.mu.Lock()
+			defer h.fakeEthBackend.mu.Unlock()
+			if h.fakeEthBackend.receiptQueries == 0 {
+				return false
+			}
+			h.fakeEthBackend.minMinedGasTipCap = nil
+			return true
+		}, time.Second, time.Millisecond)
+		require.Eventually(t, func() bool {
+			h.fakeEthBackend.mu.Lock()
+			defer h.fakeEthBackend.mu.Unlock()
+			return len(h.fakeEthBackend.minedTxs) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		receipt, err := h.txmgr.Cancel(ctxWithTimeout, 0)
+		require.ErrorIs(t, err, ErrCancelLost)
+		result := <-resultChan
+		require.NoError(t, result.Err)
+		require.Equal(t, result.Receipt, receipt)
+		h.validateTxReceipt(t, receipt)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 1)
+		require.Equal(t, h.fakeEthBackend.sentTxs[0].Hash(), receipt.TxHash)
+	})
+
+	t.Run("Cancel fails without a tx in flight", func(t *testing.T) {
+		h := newTestHarness(t, nil)
+		_, err := h.txmgr.Cancel(context.Background(), 0)
+		require.Error(t, err)
+	})
+
+	t.Run("Blob tx to a chain without EIP-4844 fails", func(t *testing.T) {
+		h := newTestHarness(t, nil)
+
+		blobTx := txmgr.NewBlobTxCandidate(chainId, common.Address{0x1}, nil, []kzg4844.Blob{{0x1}})
+		_, err := h.txmgr.Send(context.Background(), blobTx, true)
+		require.ErrorIs(t, err, txmgr.ErrBlobTxUnsupported)
+	})
+
+	t.Run("Stuck blob tx is replaced with all its fees doubled", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     200 * time.Millisecond,
+			GasTipMultiplier:           100,
+			MaxSpeedUps:                1,
+		})
+		excessBlobGas := uint64(10_000_000)
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.excessBlobGas = &excessBlobGas
+		h.fakeEthBackend.minMinedGasTipCap = new(big.Int).Lsh(big.NewInt(1), 128)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		blobTx := txmgr.NewBlobTxCandidate(chainId, common.Address{0x1}, nil, []kzg4844.Blob{{0x1}})
+		_, err := h.txmgr.Send(ctxWithTimeout, blobTx, true)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 2)
+		stuckTx, replacementTx := h.fakeEthBackend.sentTxs[0], h.fakeEthBackend.sentTxs[1]
+		for _, tx := range []*types.Transaction{stuckTx, replacementTx} {
+			require.Equal(t, uint8(types.BlobTxType), tx.Type())
+			require.NotNil(t, tx.BlobTxSidecar())
+			require.Len(t, tx.BlobHashes(), 1)
+		}
+		double := func(n *big.Int) *big.Int { return new(big.Int).Mul(n, big.NewInt(2)) }
+		require.GreaterOrEqual(t, replacementTx.GasTipCap().Cmp(double(stuckTx.GasTipCap())), 0)
+		require.GreaterOrEqual(t, replacementTx.GasFeeCap().Cmp(double(stuckTx.GasFeeCap())), 0)
+		require.GreaterOrEqual(t, replacementTx.BlobGasFeeCap().Cmp(double(stuckTx.BlobGasFeeCap())), 0)
+	})
+
+	t.Run("Lifecycle of a sped up tx is recorded in the metrics and the state changes", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     300 * time.Millisecond,
+			GasTipMultiplier:           1.2,
+		})
+		reg := prometheus.NewRegistry()
+		metrics := NewMetrics(reg, "test", testutils.NewTestLogger())
+		h.txmgr.metrics = metrics
+		type stateChange struct {
+			txID     string
+			from, to TxState
+		}
+		var stateChanges []stateChange
+		h.txmgr.WithOnStateChange(func(txID string, from, to TxState, tx *types.Transaction) {
+			stateChanges = append(stateChanges, stateChange{txID, from, to})
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		// the tx pays a 1.2M tip, and its speed up 1.44M
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resultChan, err := h.txmgr.SendAsync(ctxWithTimeout, newUnsignedEthTransferTx(0, nil))
+		require.NoError(t, err)
+		from, err := h.txmgr.wallet.SenderAddress(ctxWithTimeout)
+		require.NoError(t, err)
+		require.Equal(t, float64(1), testutil.ToFloat64(metrics.inFlightTxCount.WithLabelValues(from.Hex())))
+		result := <-resultChan
+		require.NoError(t, result.Err)
+		require.Equal(t, float64(0), testutil.ToFloat64(metrics.inFlightTxCount.WithLabelValues(from.Hex())))
+
+		require.Len(t, stateChanges, 4)
+		stuckTxID, speedUpTxID := stateChanges[0].txID, stateChanges[2].txID
+		require.NotEqual(t, stuckTxID, speedUpTxID)
+		require.Equal(t, []stateChange{
+			{stuckTxID, TxStateQueued, TxStateBroadcast},
+			{stuckTxID, TxStateBroadcast, TxStateReplaced},
+			{speedUpTxID, TxStateQueued, TxStateBroadcast},
+			{speedUpTxID, TxStateBroadcast, TxStateConfirmed},
+		}, stateChanges)
+
+		for state, count := range map[TxState]float64{
+			TxStateBroadcast: 2, TxStateReplaced: 1, TxStateConfirmed: 1, TxStateFailed: 0,
+		} {
+			require.Equal(t, count, testutil.ToFloat64(metrics.txStateChangesTotal.WithLabelValues(string(state))))
+		}
+		require.Equal(t, 1, testutil.CollectAndCount(metrics.timeToInclusionMs))
+		require.Equal(t, 1, testutil.CollectAndCount(metrics.feePaidGwei))
+		// the metrics are registered on reg only
+		count, err := testutil.GatherAndCount(reg, "txmgr_test_fee_paid_gwei")
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		count, err = testutil.GatherAndCount(prometheus.DefaultGatherer, "txmgr_test_fee_paid_gwei")
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("Failed broadcast is recorded in the state changes", func(t *testing.T) {
+		h := newTestHarness(t, nil)
+		var failedTxs int
+		h.txmgr.WithOnStateChange(func(txID string, from, to TxState, tx *types.Transaction) {
+			require.Equal(t, TxStateQueued, from)
+			require.Equal(t, TxStateFailed, to)
+			require.Empty(t, txID)
+			failedTxs++
+		})
+
+		blobTx := txmgr.NewBlobTxCandidate(chainId, common.Address{0x1}, nil, []kzg4844.Blob{{0x1}})
+		_, err := h.txmgr.Send(context.Background(), blobTx, true)
+		require.ErrorIs(t, err, txmgr.ErrBlobTxUnsupported)
+		require.Equal(t, 1, failedTxs)
+	})
+
+	t.Run("Pending tx is resumed by a new manager after a restart", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			// long enough for the first manager to not speed up the tx
+			TxnConfirmationTimeout: 10 * time.Second,
+			GasTipMultiplier:       1.2,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		// the tx pays a 1.2M tip, so only its speed up gets mined
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000)
+		h.fakeEthBackend.mu.Unlock()
+		storeDir := t.TempDir()
+		txStore, err := NewJSONFileTxStore(storeDir, testutils.NewTestLogger())
+		require.NoError(t, err)
+		h.txmgr.WithTxStore(txStore)
+
+		ctxWithTimeout, cancelTimeout := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelTimeout()
+		ctxWithCancel, kill := context.WithCancel(ctxWithTimeout)
+		resultChan, err := h.txmgr.SendAsync(ctxWithCancel, newUnsignedEthTransferTx(0, nil))
+		require.NoError(t, err)
+		// the manager is killed while the tx is stuck
+		kill()
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, context.Canceled)
+		pendingTxs, err := txStore.List()
+		require.NoError(t, err)
+		require.Len(t, pendingTxs, 1)
+		require.Equal(t, uint64(0), pendingTxs[0].Nonce)
+
+		// the new manager reads the store from disk, and speeds up the tx
+		params := h.txmgr.params
+		params.TxnConfirmationTimeout = 300 * time.Millisecond
+		txStore, err = NewJSONFileTxStore(storeDir, testutils.NewTestLogger())
+		require.NoError(t, err)
+		restartedTxmgr := NewGeometricTxnManager(
+			h.fakeEthBackend, h.txmgr.wallet, testutils.NewTestLogger(), NewNoopMetrics(), params,
+		).WithTxStore(txStore)
+		resultChans, err := restartedTxmgr.ResumePending(context.Background())
+		require.NoError(t, err)
+		require.Len(t, resultChans, 1)
+		result = <-resultChans[0]
+		require.NoError(t, result.Err)
+		h.validateTxReceipt(t, result.Receipt)
+
+		h.fakeEthBackend.mu.Lock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 2)
+		require.Equal(t, h.fakeEthBackend.sentTxs[1].Hash(), result.Receipt.TxHash)
+		h.fakeEthBackend.mu.Unlock()
+		pendingTxs, err = txStore.List()
+		require.NoError(t, err)
+		require.Empty(t, pendingTxs)
+	})
+
+	t.Run("Corrupt and stale pending txs are skipped on resume", func(t *testing.T) {
+		h := newTestHarness(t, nil)
+		storeDir := t.TempDir()
+		txStore, err := NewJSONFileTxStore(storeDir, testutils.NewTestLogger())
+		require.NoError(t, err)
+		h.txmgr.WithTxStore(txStore)
+		require.NoError(t, os.WriteFile(filepath.Join(storeDir, "pending-tx-0.json"), []byte("{"), 0o600))
+		rawTx, err := newUnsignedEthTransferTx(1, nil).MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, txStore.Put(PendingTx{
+			From:     h.sender,
+			Nonce:    1,
+			RawTx:    rawTx,
+			TxIDs:    []wallet.TxID{"0x1"},
+			Deadline: time.Now().Add(-time.Minute),
+		}))
+		require.NoError(t, txStore.Put(PendingTx{
+			From:  h.sender,
+			Nonce: 2,
+			RawTx: []byte{0x1},
+			TxIDs: []wallet.TxID{"0x2"},
+		}))
+
+		resultChans, err := h.txmgr.ResumePending(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, resultChans)
+		// the stale and undecodable txs are removed from the store
+		pendingTxs, err := txStore.List()
+		require.NoError(t, err)
+		require.Empty(t, pendingTxs)
+	})
+
+	t.Run("Legacy tx is sent to a chain without a base fee", func(t *testing.T) {
+		h := newTestHarness(t, nil)
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.noBaseFee = true
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		txReceipt, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.NoError(t, err)
+		h.validateTxReceipt(t, txReceipt)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 1)
+		require.Equal(t, uint8(types.LegacyTxType), h.fakeEthBackend.sentTxs[0].Type())
+	})
+
+	t.Run("Legacy gas pricing mode is used on a chain with a base fee", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 100 * time.Millisecond,
+			GasPricingMode:             txmgr.GasPricingModeLegacy,
+		})
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.NoError(t, err)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 1)
+		require.Equal(t, uint8(types.LegacyTxType), h.fakeEthBackend.sentTxs[0].Type())
+	})
+
+	t.Run("Stuck legacy tx is replaced with a bumped gas price", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     200 * time.Millisecond,
+			GasTipMultiplier:           1.2,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.noBaseFee = true
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		// the tx pays a gas price of 1.2012 gwei, and its speed up 1.2 times more
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000_000)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		txReceipt, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.NoError(t, err)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 2)
+		stuckTx, replacementTx := h.fakeEthBackend.sentTxs[0], h.fakeEthBackend.sentTxs[1]
+		require.Equal(t, replacementTx.Hash(), txReceipt.TxHash)
+		require.Equal(t, uint8(types.LegacyTxType), stuckTx.Type())
+		require.Equal(t, uint8(types.LegacyTxType), replacementTx.Type())
+		require.Equal(t, big.NewInt(1_201_200_000), stuckTx.GasPrice())
+		require.Equal(t, big.NewInt(1_441_440_000), replacementTx.GasPrice())
+	})
+
+	t.Run("Deadline exceeded returns the pending tx, which can still be mined", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     10 * time.Second,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.minMinedGasTipCap = new(big.Int).Lsh(big.NewInt(1), 128)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		_, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		var deadlineErr *txmgr.ErrTxDeadlineExceeded
+		require.ErrorAs(t, err, &deadlineErr)
+
+		h.fakeEthBackend.mu.Lock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 1)
+		pendingTx := h.fakeEthBackend.sentTxs[0]
+		require.Equal(t, pendingTx.Hash(), deadlineErr.TxHash)
+		require.Equal(t, uint64(0), deadlineErr.Nonce)
+		require.Equal(t, pendingTx.GasFeeCap(), deadlineErr.LastGasFeeCap)
+		// the tx is mined after the deadline
+		h.fakeEthBackend.minMinedGasTipCap = nil
+		h.fakeEthBackend.mu.Unlock()
+		require.Eventually(t, func() bool {
+			h.fakeEthBackend.mu.Lock()
+			defer h.fakeEthBackend.mu.Unlock()
+			_, mined := h.fakeEthBackend.minedTxs[pendingTx.Hash()]
+			return mined
+		}, 2*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("AutoCancelOnDeadline cancels the tx once the deadline passes", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     10 * time.Second,
+			GasTipMultiplier:           1.2,
+			AutoCancelOnDeadline:       true,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		// the tx pays a 1.2M tip, and its cancellation at least 20% more
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(1_300_000)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		_, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		var deadlineErr *txmgr.ErrTxDeadlineExceeded
+		require.ErrorAs(t, err, &deadlineErr)
+		require.Equal(t, uint64(0), deadlineErr.Nonce)
+
+		from, err := h.txmgr.wallet.SenderAddress(context.Background())
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			h.fakeEthBackend.mu.Lock()
+			defer h.fakeEthBackend.mu.Unlock()
+			if len(h.fakeEthBackend.sentTxs) != 2 {
+				return false
+			}
+			cancelTx := h.fakeEthBackend.sentTxs[1]
+			_, mined := h.fakeEthBackend.minedTxs[cancelTx.Hash()]
+			return mined && *cancelTx.To() == from && cancelTx.Nonce() == 0
+		}, 2*time.Second, 50*time.Millisecond)
+		// the cancellation is no longer monitored once mined
+		require.Eventually(t, func() bool {
+			h.txmgr.inFlightMu.Lock()
+			defer h.txmgr.inFlightMu.Unlock()
+			return len(h.txmgr.inFlight) == 0
+		}, 2*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("Fallback gas limit is used when the gas estimation reverts", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{FallbackGasLimit: 100_000})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.estimateGasErr = errors.New("execution reverted")
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.NoError(t, err)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 1)
+		require.Equal(t, uint64(100_000), h.fakeEthBackend.sentTxs[0].Gas())
+	})
+
+	t.Run("Underpriced replacement is bumped again until it replaces the stuck tx", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     200 * time.Millisecond,
+			GasTipMultiplier:           1.2,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.gasTipCap = big.NewInt(1_000_000)
+		h.fakeEthBackend.minMinedGasTipCap = big.NewInt(2_000_000)
+		// the node requires a higher bump than the txmgr's 20%, so the first replacements are rejected
+		h.fakeEthBackend.priceBumpPercent = 50
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		txReceipt, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.NoError(t, err)
+
+		h.validateTxReceipt(t, txReceipt)
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Greater(t, h.fakeEthBackend.underpricedTxs, 0)
+		require.Len(t, h.fakeEthBackend.sentTxs, 2)
+		// the receipt is the one of the replacement, which is the tx that got mined
+		require.Equal(t, h.fakeEthBackend.sentTxs[1].Hash(), txReceipt.TxHash)
+	})
+
+	t.Run("Stuck tx is not replaced more than MaxSpeedUps times", func(t *testing.T) {
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     200 * time.Millisecond,
+			GasTipMultiplier:           100,
+			MaxSpeedUps:                2,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.minMinedGasTipCap = new(big.Int).Lsh(big.NewInt(1), 128)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		require.Len(t, h.fakeEthBackend.sentTxs, 3)
+	})
+
+	t.Run("Stuck tx is not replaced above MaxGasFeeCap", func(t *testing.T) {
+		maxGasFeeCap := uint64(2_500_000_000)
+		h := newTestHarness(t, &GeometricTxnManagerParams{
+			GetTxReceiptTickerDuration: 50 * time.Millisecond,
+			TxnBroadcastTimeout:        1 * time.Second,
+			TxnConfirmationTimeout:     200 * time.Millisecond,
+			GasTipMultiplier:           100,
+			MaxGasFeeCap:               maxGasFeeCap,
+		})
+		h.fakeEthBackend.mu.Lock()
+		h.fakeEthBackend.minMinedGasTipCap = new(big.Int).Lsh(big.NewInt(1), 128)
+		h.fakeEthBackend.mu.Unlock()
+
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		// the gasFeeCap starts at 2*baseFee=2gwei and is bumped by 10% each time: 2.2gwei, then 2.42gwei
+		require.Len(t, h.fakeEthBackend.sentTxs, 3)
+		for _, tx := range h.fakeEthBackend.sentTxs {
+			require.LessOrEqual(t, tx.GasFeeCap().Uint64(), maxGasFeeCap)
+		}
+	})
+
+	t.Run("Interleaved sends from two senders use independent nonces", func(t *testing.T) {
+		n := 4
+		h := newTestHarness(t, nil)
+		logger := testutils.NewTestLogger()
+		wallets := make(map[common.Address]wallet.Wallet)
+		var senders []common.Address
+		for i := 0; i < 2; i++ {
+			skWallet, ecdsaAddr := newTestWallet(t, h.fakeEthBackend)
+			wallets[ecdsaAddr] = skWallet
+			senders = append(senders, ecdsaAddr)
+		}
+		multiWallet, err := wallet.NewMultiWallet(wallets)
+		require.NoError(t, err)
+		require.ElementsMatch(t, senders, multiWallet.Senders())
+		h.fakeEthBackend.mu.Lock()
+		// the first sender already sent 5 txs
+		h.fakeEthBackend.nonces[senders[0]] = 5
+		h.fakeEthBackend.mu.Unlock()
+		multiSenderTxmgr := NewGeometricTxnManager(
+			h.fakeEthBackend, multiWallet, logger, NewNoopMetrics(), h.txmgr.params,
+		)
+		for _, sender := range senders {
+			multiSenderTxmgr.WithNonceManager(txmgr.NewNonceManager(h.fakeEthBackend, sender))
+		}
+
+		g := new(errgroup.Group)
+		txReceipts := make(map[common.Address][]*types.Receipt)
+		var txReceiptsMu sync.Mutex
+		for i := 0; i < n; i++ {
+			for _, sender := range senders {
+				sender := sender
+				g.Go(func() error {
+					ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+					defer cancel()
+					ctx := wallet.WithSenderAddress(ctxWithTimeout, sender)
+					txReceipt, err := multiSenderTxmgr.Send(ctx, newUnsignedEthTransferTx(0, nil), true)
+					if err != nil {
+						return err
+					}
+					txReceiptsMu.Lock()
+					defer txReceiptsMu.Unlock()
+					txReceipts[sender] = append(txReceipts[sender], txReceipt)
+					return nil
+				})
+			}
+		}
+		require.NoError(t, g.Wait())
+
+		h.fakeEthBackend.mu.Lock()
+		defer h.fakeEthBackend.mu.Unlock()
+		sentTxs := make(map[common.Hash]*types.Transaction)
+		for _, tx := range h.fakeEthBackend.sentTxs {
+			sentTxs[tx.Hash()] = tx
+		}
+		for i, firstNonce := range []uint64{5, 0} {
+			sender := senders[i]
+			require.Equal(t, firstNonce+uint64(n), h.fakeEthBackend.nonces[sender])
+			nonces := make(map[uint64]struct{})
+			for _, txReceipt := range txReceipts[sender] {
+				tx := sentTxs[txReceipt.TxHash]
+				from, err := types.Sender(types.LatestSignerForChainID(chainId), tx)
+				require.NoError(t, err)
+				require.Equal(t, sender, from)
+				nonces[tx.Nonce()] = struct{}{}
+			}
+			require.Len(t, nonces, n)
+			for nonce := firstNonce; nonce < firstNonce+uint64(n); nonce++ {
+				require.Contains(t, nonces, nonce)
+			}
+		}
+	})
+}
+
+// fakeRelayService is a private relay serving eth_sendRawTransaction, which records the submitted transactions and
+// forwards them to the builder
+type fakeRelayService struct {
+	builder *fakeEthBackend
+	mu      sync.Mutex
+	sentTxs []*types.Transaction
+}
+
+func (s *fakeRelayService) SendRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.builder.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sentTxs = append(s.sentTxs, tx)
+	return tx.Hash(), nil
 }
 
 func newUnsignedEthTransferTx(nonce uint64, gasFeeCap *big.Int) *types.Transaction {
@@ -236,28 +1076,45 @@ type fakeEthBackend struct {
 	congestedBlocks uint64
 	gasTipCap       *big.Int
 	baseFeePerGas   *big.Int
+	// minMinedGasTipCap can be set to only mine txs paying at least this gasTipCap, simulating a network whose
+	// congestion isn't reflected by the suggested gasTipCap
+	minMinedGasTipCap *big.Int
+	// priceBumpPercent is the percentage by which a tx must increase the fees of the tx it replaces, as in geth
+	priceBumpPercent int64
+	// excessBlobGas can be set to support blob txs, whose blob base fee is derived from it
+	excessBlobGas *uint64
+	// noBaseFee can be set to simulate a chain without EIP-1559, whose baseFeePerGas is only the min gas price
+	noBaseFee bool
+	// estimateGasErr can be set to make the gas estimation fail, e.g. with an execution revert
+	estimateGasErr error
 	// mu protects all the below fields which are updated in "mining" goroutines (see Send)
 	mu          sync.Mutex
 	blockNumber uint64
-	// we use a single nonce for now because the txmgr only supports a single sender
-	nonce    uint64
-	mempool  map[uint64]*types.Transaction // nonce -> tx
+	// nonces are the nonces of the next txs to mine, by sender
+	nonces   map[common.Address]uint64
+	mempool  map[senderNonce]*types.Transaction
 	minedTxs map[common.Hash]*types.Receipt
-	logger   logging.Logger
+	// sentTxs are all the txs accepted in the mempool, and underpricedTxs the number of rejected replacements
+	sentTxs        []*types.Transaction
+	underpricedTxs int
+	// receiptQueries is the number of calls to TransactionReceipt
+	receiptQueries int
+	logger         logging.Logger
 }
 
 func NewFakeEthBackend() *fakeEthBackend {
 	logger := testutils.NewTestLogger().With("component", "fakeEthBackend")
 	backend := &fakeEthBackend{
-		congestedBlocks: 0,
-		gasTipCap:       big.NewInt(1),             // 1 wei, same default as anvil
-		baseFeePerGas:   big.NewInt(1_000_000_000), // 1 gwei, same default as anvil
-		mu:              sync.Mutex{},
-		blockNumber:     0,
-		nonce:           0,
-		mempool:         make(map[uint64]*types.Transaction),
-		minedTxs:        make(map[common.Hash]*types.Receipt),
-		logger:          logger,
+		congestedBlocks:  0,
+		gasTipCap:        big.NewInt(1),             // 1 wei, same default as anvil
+		baseFeePerGas:    big.NewInt(1_000_000_000), // 1 gwei, same default as anvil
+		priceBumpPercent: 10,                        // same default as geth
+		mu:               sync.Mutex{},
+		blockNumber:      0,
+		nonces:           make(map[common.Address]uint64),
+		mempool:          make(map[senderNonce]*types.Transaction),
+		minedTxs:         make(map[common.Hash]*types.Receipt),
+		logger:           logger,
 	}
 	backend.startMining()
 	return backend
@@ -272,21 +1129,36 @@ func (s *fakeEthBackend) startMining() {
 	go func() {
 		for {
 			s.mu.Lock()
-			// if there's a tx in the mempool with the current nonce and its gasTipCap is >= baseFeePerGas, mine it
-			if tx, ok := s.mempool[s.nonce]; ok {
-				if tx.GasTipCapIntCmp(s.gasTipCap) >= 0 {
-					delete(s.mempool, s.nonce)
+			minedBlock := false
+			// if there's a tx in the mempool with the current nonce of its sender and its gasTipCap is >= gasTipCap,
+			// mine it
+			for key, tx := range s.mempool {
+				if key.nonce != s.nonces[key.from] {
+					continue
+				}
+				if tx.GasTipCapIntCmp(s.gasTipCap) >= 0 &&
+					(s.minMinedGasTipCap == nil || tx.GasTipCapIntCmp(s.minMinedGasTipCap) >= 0) {
+					delete(s.mempool, key)
+					effectiveGasPrice := new(big.Int).Add(s.baseFeePerGas, tx.GasTipCap())
+					if effectiveGasPrice.Cmp(tx.GasFeeCap()) > 0 {
+						effectiveGasPrice = tx.GasFeeCap()
+					}
 					s.minedTxs[tx.Hash()] = &types.Receipt{
-						BlockNumber: big.NewInt(int64(s.blockNumber)),
-						TxHash:      tx.Hash(),
+						BlockNumber:       big.NewInt(int64(s.blockNumber)),
+						TxHash:            tx.Hash(),
+						GasUsed:           21000,
+						EffectiveGasPrice: effectiveGasPrice,
 					}
-					s.blockNumber++
-					s.nonce++
-					s.logger.Debug("mined tx", "txHash", tx.Hash(), "nonce", tx.Nonce())
+					minedBlock = true
+					s.nonces[key.from]++
+					s.logger.Debug("mined tx", "txHash", tx.Hash(), "from", key.from, "nonce", tx.Nonce())
 				} else {
 					s.logger.Info("tx.gasTipCap < fakeEthBackend.gasTipCap, not mining", "txHash", tx.Hash(), "nonce", tx.Nonce(), "tx.gasTipCap", tx.GasTipCap(), "fakeEthBackend.gasTipCap", s.gasTipCap)
 				}
 			}
+			if minedBlock {
+				s.blockNumber++
+			}
 			if s.congestedBlocks > 0 {
 				s.congestedBlocks--
 			}
@@ -321,13 +1193,27 @@ func (s *fakeEthBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 }
 
 func (s *fakeEthBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
-	return 0, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return 0, s.estimateGasErr
+
+}
 
+func (s *fakeEthBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return new(big.Int).Add(s.baseFeePerGas, s.gasTipCap), nil
 }
 
 func (s *fakeEthBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.noBaseFee {
+		return &types.Header{}, nil
+	}
 	return &types.Header{
-		BaseFee: big.NewInt(0).Set(s.baseFeePerGas),
+		BaseFee:       big.NewInt(0).Set(s.baseFeePerGas),
+		ExcessBlobGas: s.excessBlobGas,
 	}, nil
 }
 
@@ -335,19 +1221,62 @@ func (s *fakeEthBackend) SendTransaction(ctx context.Context, tx *types.Transact
 	if tx.GasFeeCapIntCmp(s.baseFeePerGas) < 0 {
 		return fmt.Errorf("tx.gasFeeCap (%d) < baseFeeCap (%d)", tx.GasFeeCap(), s.baseFeePerGas)
 	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainId), tx)
+	if err != nil {
+		return err
+	}
+	key := senderNonce{from: from, nonce: tx.Nonce()}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if tx.Nonce() < s.nonce {
-		return fmt.Errorf("tx.nonce (%d) < current nonce (%d)", tx.Nonce(), s.nonce)
+	if tx.Nonce() < s.nonces[from] {
+		return fmt.Errorf("nonce too low: tx.nonce (%d) < current nonce (%d)", tx.Nonce(), s.nonces[from])
 	}
-	s.mempool[tx.Nonce()] = tx
+	if prevTx, ok := s.mempool[key]; ok {
+		// like geth, whose blob pool reserves the senders of blob transactions
+		if (prevTx.Type() == types.BlobTxType) != (tx.Type() == types.BlobTxType) {
+			return errors.New("address already reserved")
+		}
+		minFee := func(fee *big.Int) *big.Int {
+			minFee := new(big.Int).Mul(fee, big.NewInt(100+s.priceBumpPercent))
+			return minFee.Div(minFee, big.NewInt(100))
+		}
+		if tx.GasTipCapIntCmp(minFee(prevTx.GasTipCap())) < 0 || tx.GasFeeCapIntCmp(minFee(prevTx.GasFeeCap())) < 0 {
+			s.underpricedTxs++
+			return errors.New("replacement transaction underpriced")
+		}
+	}
+	s.mempool[key] = tx
+	s.sentTxs = append(s.sentTxs, tx)
 	return nil
 }
 
+// blockingSendEthBackend blocks the transactions sent once blockSends is set, until release is closed
+type blockingSendEthBackend struct {
+	*fakeEthBackend
+	blockSends  atomic.Bool
+	sendBlocked chan struct{}
+	release     chan struct{}
+}
+
+func (b *blockingSendEthBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.blockSends.Load() {
+		b.sendBlocked <- struct{}{}
+		<-b.release
+	}
+	return b.fakeEthBackend.SendTransaction(ctx, tx)
+}
+
+func (s *fakeEthBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nonces[account], nil
+}
+
 func (s *fakeEthBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.receiptQueries++
 	if receipt, ok := s.minedTxs[txHash]; ok {
 		return receipt, nil
 	}
@@ -361,8 +1290,9 @@ type integrationEthBackend interface {
 	ethBackend
 }
 type integrationTestHarness struct {
-	ethBackend integrationEthBackend
-	txmgr      *GeometricTxManager
+	ethBackend  integrationEthBackend
+	anvilClient *ethclient.Client
+	txmgr       *GeometricTxManager
 }
 
 func newIntegrationTestHarness(t *testing.T) *integrationTestHarness {
@@ -389,8 +1319,9 @@ func newIntegrationTestHarness(t *testing.T) *integrationTestHarness {
 
 	txmgr := NewGeometricTxnManager(anvilHttpClient, skWallet, logger, NewNoopMetrics(), GeometricTxnManagerParams{})
 	return &integrationTestHarness{
-		ethBackend: anvilHttpClient,
-		txmgr:      txmgr,
+		ethBackend:  anvilHttpClient,
+		anvilClient: anvilHttpClient,
+		txmgr:       txmgr,
 	}
 }
 
@@ -414,4 +1345,84 @@ func TestGeometricTxManagerIntegration(t *testing.T) {
 
 		h.validateTxReceipt(t, txReceipt)
 	})
+
+	t.Run("Tx reorged out before its confirmations is sent again", func(t *testing.T) {
+		h := newIntegrationTestHarness(t)
+		h.txmgr.params.ConfirmationBlocks = 3
+		h.txmgr.params.TxnConfirmationTimeout = 1 * time.Second
+		h.txmgr.params.GetTxReceiptTickerDuration = 100 * time.Millisecond
+		ctx := context.Background()
+		rpcClient := h.anvilClient.Client()
+		var snapshotId string
+		require.NoError(t, rpcClient.CallContext(ctx, &snapshotId, "evm_snapshot"))
+		startBlock, err := h.anvilClient.BlockNumber(ctx)
+		require.NoError(t, err)
+
+		type sendResult struct {
+			receipt *types.Receipt
+			err     error
+		}
+		resultC := make(chan sendResult, 1)
+		go func() {
+			ctxWithTimeout, cancel := context.WithTimeout(ctx, 20*time.Second)
+			defer cancel()
+			txReceipt, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+			resultC <- sendResult{receipt: txReceipt, err: err}
+		}()
+
+		// wait for the tx to be mined (anvil automines it), then orphan its block, which drops the tx
+		require.Eventually(t, func() bool {
+			blockNumber, err := h.anvilClient.BlockNumber(ctx)
+			return err == nil && blockNumber > startBlock
+		}, 5*time.Second, 50*time.Millisecond)
+		orphanedBlock, err := h.anvilClient.BlockByNumber(ctx, new(big.Int).SetUint64(startBlock+1))
+		require.NoError(t, err)
+		var reverted bool
+		require.NoError(t, rpcClient.CallContext(ctx, &reverted, "evm_revert", snapshotId))
+		require.True(t, reverted)
+		// mine a block per second so that the sped up tx gets its confirmations
+		require.NoError(t, rpcClient.CallContext(ctx, nil, "evm_setIntervalMining", 1))
+
+		result := <-resultC
+		require.NoError(t, result.err)
+		require.NotEqual(t, orphanedBlock.Hash(), result.receipt.BlockHash)
+		h.validateTxReceipt(t, result.receipt)
+		canonicalHeader, err := h.anvilClient.HeaderByNumber(ctx, result.receipt.BlockNumber)
+		require.NoError(t, err)
+		require.Equal(t, canonicalHeader.Hash(), result.receipt.BlockHash)
+	})
+
+	t.Run("Stuck tx is replaced until a block is mined", func(t *testing.T) {
+		h := newIntegrationTestHarness(t)
+		h.txmgr.params.TxnConfirmationTimeout = 1 * time.Second
+		h.txmgr.params.GetTxReceiptTickerDuration = 100 * time.Millisecond
+		ctx := context.Background()
+		// simulate congestion: no block gets mined until we ask for it
+		require.NoError(t, h.anvilClient.Client().CallContext(ctx, nil, "evm_setAutomine", false))
+		suggestedGasTipCap, err := h.anvilClient.SuggestGasTipCap(ctx)
+		require.NoError(t, err)
+
+		type sendResult struct {
+			receipt *types.Receipt
+			err     error
+		}
+		resultC := make(chan sendResult, 1)
+		go func() {
+			ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			txReceipt, err := h.txmgr.Send(ctxWithTimeout, newUnsignedEthTransferTx(0, nil), true)
+			resultC <- sendResult{receipt: txReceipt, err: err}
+		}()
+		// leave time for the txmgr to replace the stuck tx a couple of times
+		time.Sleep(2500 * time.Millisecond)
+		require.NoError(t, h.anvilClient.Client().CallContext(ctx, nil, "evm_mine"))
+
+		result := <-resultC
+		require.NoError(t, result.err)
+		h.validateTxReceipt(t, result.receipt)
+		// the mined tx is a replacement, which pays a higher tip than the first tx sent
+		minedTx, _, err := h.anvilClient.TransactionByHash(ctx, result.receipt.TxHash)
+		require.NoError(t, err)
+		require.Equal(t, 1, minedTx.GasTipCap().Cmp(h.txmgr.addGasTipCapBuffer(suggestedGasTipCap)))
+	})
 }
@@ -14,6 +14,11 @@ type Metrics interface {
 	IncrementProcessingTxCount()
 	DecrementProcessingTxCount()
 	IncrementProcessedTxsTotal(state string)
+	IncrementTxStateChangesTotal(state string)
+	ObserveTimeToInclusionMs(latencyMs int64)
+	ObserveFeePaidGwei(feePaidGwei float64)
+	IncrementInFlightTxCount(sender string)
+	DecrementInFlightTxCount(sender string)
 }
 
 const namespace = "txmgr"
@@ -25,6 +30,10 @@ type PromMetrics struct {
 	speedUps              prometheus.Histogram
 	processingTxCount     prometheus.Gauge
 	processedTxsTotal     *prometheus.CounterVec
+	txStateChangesTotal   *prometheus.CounterVec
+	timeToInclusionMs     prometheus.Histogram
+	feePaidGwei           prometheus.Histogram
+	inFlightTxCount       *prometheus.GaugeVec
 }
 
 var _ Metrics = (*PromMetrics)(nil)
@@ -87,6 +96,42 @@ func NewMetrics(reg prometheus.Registerer, subsystem string, logger logging.Logg
 			},
 			[]string{"state"},
 		),
+		txStateChangesTotal: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "tx_state_changes_total",
+				Help:      "number of transactions which entered each state (broadcast, replaced, confirmed, failed)",
+			},
+			[]string{"state"},
+		),
+		timeToInclusionMs: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "time_to_inclusion_ms",
+				Help:      "time from the first broadcast of a transaction until one of its attempts is confirmed",
+				Buckets:   prometheus.ExponentialBuckets(100, 2, 14),
+			},
+		),
+		feePaidGwei: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "fee_paid_gwei",
+				Help:      "total fee (including the blob fee) paid by each confirmed transaction in gwei",
+				Buckets:   prometheus.ExponentialBuckets(1e4, 4, 10),
+			},
+		),
+		inFlightTxCount: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "in_flight_tx_count",
+				Help:      "number of broadcast transactions waiting to be confirmed by sender address",
+			},
+			[]string{"sender"},
+		),
 	}
 }
 
@@ -119,6 +164,26 @@ func (m *PromMetrics) IncrementProcessedTxsTotal(state string) {
 	m.processedTxsTotal.WithLabelValues(state).Inc()
 }
 
+func (m *PromMetrics) IncrementTxStateChangesTotal(state string) {
+	m.txStateChangesTotal.WithLabelValues(state).Inc()
+}
+
+func (m *PromMetrics) ObserveTimeToInclusionMs(latencyMs int64) {
+	m.timeToInclusionMs.Observe(float64(latencyMs))
+}
+
+func (m *PromMetrics) ObserveFeePaidGwei(feePaidGwei float64) {
+	m.feePaidGwei.Observe(feePaidGwei)
+}
+
+func (m *PromMetrics) IncrementInFlightTxCount(sender string) {
+	m.inFlightTxCount.WithLabelValues(sender).Inc()
+}
+
+func (m *PromMetrics) DecrementInFlightTxCount(sender string) {
+	m.inFlightTxCount.WithLabelValues(sender).Dec()
+}
+
 type NoopMetrics struct{}
 
 var _ Metrics = (*NoopMetrics)(nil)
@@ -140,3 +205,13 @@ func (t *NoopMetrics) IncrementProcessingTxCount() {}
 func (t *NoopMetrics) DecrementProcessingTxCount() {}
 
 func (t *NoopMetrics) IncrementProcessedTxsTotal(state string) {}
+
+func (t *NoopMetrics) IncrementTxStateChangesTotal(state string) {}
+
+func (t *NoopMetrics) ObserveTimeToInclusionMs(latencyMs int64) {}
+
+func (t *NoopMetrics) ObserveFeePaidGwei(feePaidGwei float64) {}
+
+func (t *NoopMetrics) IncrementInFlightTxCount(sender string) {}
+
+func (t *NoopMetrics) DecrementInFlightTxCount(sender string) {}
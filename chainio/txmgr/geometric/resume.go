@@ -0,0 +1,153 @@
+package geometric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// WithTxStore makes the GeometricTxManager persist its in-flight transactions in txStore, so that their monitoring can
+// be resumed with ResumePending after a restart. The transactions are removed from the store once confirmed or failed,
+// but kept when their monitoring is cancelled, e.g. on shutdown. Persistence failures are logged and don't fail sends.
+func (t *GeometricTxManager) WithTxStore(txStore TxStore) *GeometricTxManager {
+	t.txStore = txStore
+	return t
+}
+
+// ResumePending resumes the monitoring of the in-flight transactions persisted in the TxStore, e.g. on startup after a
+// restart. Every transaction is waited for and sped up like a transaction sent with SendAsync, until the deadline of
+// its original send, and its result is sent on the channel of its nonce.
+// The transactions which can't be decoded or whose deadline passed are logged, removed from the store and skipped. The
+// nonces of the resumed transactions are adopted by the NonceManager of their sender if one is set.
+// Only the transactions of the sender of the wallet are resumed: with a wallet signing for multiple addresses,
+// ResumePending must be called for every sender, selected with wallet.WithSenderAddress on ctx.
+func (t *GeometricTxManager) ResumePending(ctx context.Context) (map[uint64]<-chan txmgr.TxResult, error) {
+	if t.txStore == nil {
+		return nil, errors.New("no TxStore set, see WithTxStore")
+	}
+	pendingTxs, err := t.txStore.List()
+	if err != nil {
+		return nil, utils.WrapError("failed to list pending transactions", err)
+	}
+	from, err := t.wallet.SenderAddress(ctx)
+	if err != nil {
+		return nil, utils.WrapError("failed to get sender address", err)
+	}
+
+	resultChans := make(map[uint64]<-chan txmgr.TxResult)
+	for _, pendingTx := range pendingTxs {
+		if pendingTx.From != from {
+			continue
+		}
+		t.inFlightMu.Lock()
+		_, inFlight := t.inFlight[senderNonce{from: from, nonce: pendingTx.Nonce}]
+		t.inFlightMu.Unlock()
+		if inFlight {
+			continue
+		}
+
+		req, err := newResumedTxnRequest(pendingTx, from)
+		if err != nil {
+			t.logger.Warn("skipping pending transaction", "nonce", pendingTx.Nonce, "err", err)
+			t.deletePersistedTx(from, pendingTx.Nonce)
+			continue
+		}
+		if nonceManager, ok := t.nonceManagers[from]; ok {
+			if err := nonceManager.Adopt(ctx, pendingTx.Nonce); err != nil {
+				return resultChans, utils.WrapError(fmt.Errorf("failed to adopt nonce %d", pendingTx.Nonce), err)
+			}
+		}
+
+		t.logger.Info("resuming pending transaction", "nonce", pendingTx.Nonce, "txHash", pendingTx.TxHash.Hex(),
+			"numSpeedUps", pendingTx.NumSpeedUps)
+		t.metrics.IncrementProcessingTxCount()
+		resultChans[pendingTx.Nonce] = t.monitorAsync(ctx, req)
+	}
+	return resultChans, nil
+}
+
+// newResumedTxnRequest returns the request of pendingTx, whose transactions were all broadcast by a previous process
+func newResumedTxnRequest(pendingTx PendingTx, from common.Address) (*txnRequest, error) {
+	if !pendingTx.Deadline.IsZero() && time.Now().After(pendingTx.Deadline) {
+		return nil, fmt.Errorf("stale transaction: its deadline %s passed", pendingTx.Deadline)
+	}
+	if len(pendingTx.TxIDs) == 0 {
+		return nil, errors.New("no transaction was broadcast")
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(pendingTx.RawTx); err != nil {
+		return nil, utils.WrapError("failed to decode transaction", err)
+	}
+	if tx.Nonce() != pendingTx.Nonce {
+		return nil, fmt.Errorf("transaction has nonce %d", tx.Nonce())
+	}
+
+	req := newTxnRequest(tx)
+	req.from = from
+	req.deadline = pendingTx.Deadline
+	req.numSpeedUps = pendingTx.NumSpeedUps
+	// only the latest transaction is persisted, so it stands in for the previous ones, which are only queried by TxID
+	for i, txID := range pendingTx.TxIDs {
+		state := TxStateReplaced
+		if i == len(pendingTx.TxIDs)-1 {
+			state = TxStateBroadcast
+		}
+		req.txAttempts = append(req.txAttempts, &transaction{
+			TxID:        txID,
+			Transaction: tx,
+			requestedAt: req.requestedAt,
+			state:       state,
+		})
+	}
+	for _, txID := range pendingTx.Cancellations {
+		req.cancellations[txID] = struct{}{}
+	}
+	return req, nil
+}
+
+// persistTx writes the request to the TxStore if one is set. It must be called with req.mu held once req is monitored.
+func (t *GeometricTxManager) persistTx(req *txnRequest) {
+	if t.txStore == nil {
+		return
+	}
+	rawTx, err := req.tx.MarshalBinary()
+	if err != nil {
+		t.logger.Warn("failed to encode in-flight transaction", "nonce", req.tx.Nonce(), "err", err)
+		return
+	}
+	pendingTx := PendingTx{
+		From:        req.from,
+		Nonce:       req.tx.Nonce(),
+		TxHash:      req.tx.Hash(),
+		RawTx:       rawTx,
+		TxIDs:       make([]wallet.TxID, 0, len(req.txAttempts)),
+		NumSpeedUps: len(req.txAttempts) - 1,
+		Deadline:    req.deadline,
+	}
+	for _, attempt := range req.txAttempts {
+		pendingTx.TxIDs = append(pendingTx.TxIDs, attempt.TxID)
+	}
+	for txID := range req.cancellations {
+		pendingTx.Cancellations = append(pendingTx.Cancellations, txID)
+	}
+	if err := t.txStore.Put(pendingTx); err != nil {
+		t.logger.Warn("failed to persist in-flight transaction", "nonce", pendingTx.Nonce, "err", err)
+	}
+}
+
+// deletePersistedTx removes the request of from with nonce from the TxStore if one is set
+func (t *GeometricTxManager) deletePersistedTx(from common.Address, nonce uint64) {
+	if t.txStore == nil {
+		return
+	}
+	if err := t.txStore.Delete(from, nonce); err != nil {
+		t.logger.Warn("failed to delete persisted transaction", "nonce", nonce, "err", err)
+	}
+}
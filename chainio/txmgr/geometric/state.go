@@ -0,0 +1,91 @@
+package geometric
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TxState is the state of a transaction sent by the GeometricTxManager. Every speed up or cancellation of a request
+// is a new transaction, with its own state.
+type TxState string
+
+const (
+	// TxStateQueued is the state of a transaction which hasn't been broadcast yet
+	TxStateQueued TxState = "queued"
+	// TxStateBroadcast is the state of a transaction sent to the network
+	TxStateBroadcast TxState = "broadcast"
+	// TxStateReplaced is the state of a transaction replaced by a speed up or a cancellation, which may still get mined
+	TxStateReplaced TxState = "replaced"
+	// TxStateConfirmed is the state of the transaction of a request which got confirmed
+	TxStateConfirmed TxState = "confirmed"
+	// TxStateFailed is the state of a transaction which failed to be broadcast, or whose monitoring failed
+	TxStateFailed TxState = "failed"
+)
+
+// OnStateChangeFunc is called with the wallet TxID of a transaction (empty if it was never broadcast) every time its
+// state changes. It is called synchronously by the GeometricTxManager, so it must not block.
+type OnStateChangeFunc func(txID string, from, to TxState, tx *types.Transaction)
+
+// WithOnStateChange makes the GeometricTxManager call onStateChange on every state change of its transactions, e.g.
+// to emit events. The transactions whose monitoring is stopped by the caller's context stay in their last state.
+func (t *GeometricTxManager) WithOnStateChange(onStateChange OnStateChangeFunc) *GeometricTxManager {
+	t.onStateChange = onStateChange
+	return t
+}
+
+// changeTxState moves tx to the state to, recording it in the metrics and calling the OnStateChange hook if set
+func (t *GeometricTxManager) changeTxState(tx *transaction, to TxState) {
+	from := tx.state
+	tx.state = to
+	t.metrics.IncrementTxStateChangesTotal(string(to))
+	if t.onStateChange != nil {
+		t.onStateChange(string(tx.TxID), from, to, tx.Transaction)
+	}
+}
+
+// replaceTx records the broadcast of replacement, which replaces the latest transaction of req, and persists req.
+// It must be called with req.mu held.
+func (t *GeometricTxManager) replaceTx(req *txnRequest, replacement *transaction) {
+	if len(req.txAttempts) > 0 {
+		t.changeTxState(req.txAttempts[len(req.txAttempts)-1], TxStateReplaced)
+	}
+	req.txAttempts = append(req.txAttempts, replacement)
+	t.changeTxState(replacement, TxStateBroadcast)
+	t.persistTx(req)
+}
+
+// completeTxStates moves the transaction of req which got confirmed to TxStateConfirmed, or the latest transaction of
+// req to TxStateFailed if failed is set. It also observes the time to inclusion of confirmed requests.
+func (t *GeometricTxManager) completeTxStates(req *txnRequest, failed bool) {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	if len(req.txAttempts) == 0 {
+		return
+	}
+	if failed {
+		t.changeTxState(req.txAttempts[len(req.txAttempts)-1], TxStateFailed)
+		return
+	}
+	t.metrics.ObserveTimeToInclusionMs(time.Since(req.txAttempts[0].requestedAt).Milliseconds())
+	for _, attempt := range req.txAttempts {
+		if attempt.TxID == req.confirmedTxID {
+			t.changeTxState(attempt, TxStateConfirmed)
+		}
+	}
+}
+
+// feePaidGwei returns the total fee paid by the transaction of receipt in gwei, including its blob fee
+func feePaidGwei(receipt *types.Receipt) float64 {
+	if receipt.EffectiveGasPrice == nil {
+		return 0
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	if receipt.BlobGasPrice != nil {
+		fee.Add(fee, new(big.Int).Mul(new(big.Int).SetUint64(receipt.BlobGasUsed), receipt.BlobGasPrice))
+	}
+	feeGwei, _ := new(big.Float).Quo(new(big.Float).SetInt(fee), big.NewFloat(params.GWei)).Float64()
+	return feeGwei
+}
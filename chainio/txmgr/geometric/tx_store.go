@@ -0,0 +1,181 @@
+package geometric
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PendingTx is the metadata of an in-flight transaction, persisted by a TxStore so that its monitoring can be resumed
+// after a restart (see GeometricTxManager.ResumePending)
+type PendingTx struct {
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+	// TxHash is the hash of the latest transaction sent with the nonce, before signing
+	TxHash common.Hash `json:"txHash"`
+	// RawTx is the binary encoding of the latest transaction sent with the nonce, before signing
+	RawTx hexutil.Bytes `json:"rawTx"`
+	// TxIDs are the wallet ids of all the transactions sent with the nonce, any of which may get mined
+	TxIDs []wallet.TxID `json:"txIDs"`
+	// Cancellations are the wallet ids of the transactions sent by Cancel
+	Cancellations []wallet.TxID `json:"cancellations,omitempty"`
+	// NumSpeedUps is the number of times the transaction was replaced
+	NumSpeedUps int `json:"numSpeedUps"`
+	// Deadline is the deadline of the send, after which the transaction is stale and not resumed. Zero if none.
+	Deadline time.Time `json:"deadline"`
+}
+
+// TxStore persists the in-flight transactions of a GeometricTxManager, which are identified by their sender and nonce
+type TxStore interface {
+	// Put persists tx, replacing the transaction with the same sender and nonce if any
+	Put(tx PendingTx) error
+	// Delete removes the transaction with the given sender and nonce, if any
+	Delete(from common.Address, nonce uint64) error
+	// List returns all the persisted transactions, sorted by sender and nonce
+	List() ([]PendingTx, error)
+}
+
+// InMemoryTxStore is a TxStore keeping the transactions in memory, which survives the recreation of a
+// GeometricTxManager but not a restart of the process
+type InMemoryTxStore struct {
+	mu  sync.Mutex
+	txs map[senderNonce]PendingTx
+}
+
+var _ TxStore = (*InMemoryTxStore)(nil)
+
+func NewInMemoryTxStore() *InMemoryTxStore {
+	return &InMemoryTxStore{
+		txs: make(map[senderNonce]PendingTx),
+	}
+}
+
+func (s *InMemoryTxStore) Put(tx PendingTx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs[senderNonce{from: tx.From, nonce: tx.Nonce}] = tx
+	return nil
+}
+
+func (s *InMemoryTxStore) Delete(from common.Address, nonce uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.txs, senderNonce{from: from, nonce: nonce})
+	return nil
+}
+
+func (s *InMemoryTxStore) List() ([]PendingTx, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	txs := make([]PendingTx, 0, len(s.txs))
+	for _, tx := range s.txs {
+		txs = append(txs, tx)
+	}
+	sortPendingTxs(txs)
+	return txs, nil
+}
+
+func sortPendingTxs(txs []PendingTx) {
+	sort.Slice(txs, func(i, j int) bool {
+		if cmp := txs[i].From.Cmp(txs[j].From); cmp != 0 {
+			return cmp < 0
+		}
+		return txs[i].Nonce < txs[j].Nonce
+	})
+}
+
+// JSONFileTxStore is a TxStore writing every transaction to its own json file in a directory.
+// The files which can't be read or decoded are logged and skipped by List.
+type JSONFileTxStore struct {
+	dir    string
+	logger logging.Logger
+}
+
+var _ TxStore = (*JSONFileTxStore)(nil)
+
+const pendingTxFilePrefix = "pending-tx-"
+
+// NewJSONFileTxStore returns a JSONFileTxStore writing to dir, which is created if it doesn't exist
+func NewJSONFileTxStore(dir string, logger logging.Logger) (*JSONFileTxStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, utils.WrapError("failed to create tx store directory", err)
+	}
+	return &JSONFileTxStore{
+		dir:    dir,
+		logger: logger.With("component", "JSONFileTxStore"),
+	}, nil
+}
+
+func (s *JSONFileTxStore) path(from common.Address, nonce uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%s-%d.json", pendingTxFilePrefix, from.Hex(), nonce))
+}
+
+// Put writes tx to a temporary file renamed over the file of its sender and nonce, so that a crash never leaves a
+// partial file
+func (s *JSONFileTxStore) Put(tx PendingTx) error {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return utils.WrapError("failed to encode pending tx", err)
+	}
+	tmpFile, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return utils.WrapError("failed to create pending tx file", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return utils.WrapError("failed to write pending tx file", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return utils.WrapError("failed to write pending tx file", err)
+	}
+	if err := os.Rename(tmpFile.Name(), s.path(tx.From, tx.Nonce)); err != nil {
+		return utils.WrapError("failed to write pending tx file", err)
+	}
+	return nil
+}
+
+func (s *JSONFileTxStore) Delete(from common.Address, nonce uint64) error {
+	if err := os.Remove(s.path(from, nonce)); err != nil && !os.IsNotExist(err) {
+		return utils.WrapError("failed to delete pending tx file", err)
+	}
+	return nil
+}
+
+func (s *JSONFileTxStore) List() ([]PendingTx, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, utils.WrapError("failed to read tx store directory", err)
+	}
+	var txs []PendingTx
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, pendingTxFilePrefix) || filepath.Ext(name) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			s.logger.Warn("skipping unreadable pending tx file", "file", name, "err", err)
+			continue
+		}
+		var tx PendingTx
+		if err := json.Unmarshal(data, &tx); err != nil {
+			s.logger.Warn("skipping corrupt pending tx file", "file", name, "err", err)
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	sortPendingTxs(txs)
+	return txs, nil
+}
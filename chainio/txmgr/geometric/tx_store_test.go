@@ -0,0 +1,75 @@
+package geometric
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxStores(t *testing.T) {
+	jsonFileTxStore, err := NewJSONFileTxStore(t.TempDir(), testutils.NewTestLogger())
+	require.NoError(t, err)
+	txStores := map[string]TxStore{
+		"in memory": NewInMemoryTxStore(),
+		"json file": jsonFileTxStore,
+	}
+	sender := common.HexToAddress("0x1")
+	otherSender := common.HexToAddress("0x2")
+	for name, txStore := range txStores {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, txStore.Put(PendingTx{
+				From:  sender,
+				Nonce: 2,
+				RawTx: []byte{0x2},
+				TxIDs: []wallet.TxID{"0x2"},
+			}))
+			require.NoError(t, txStore.Put(PendingTx{
+				From:  sender,
+				Nonce: 1,
+				RawTx: []byte{0x1},
+				TxIDs: []wallet.TxID{"0x1"},
+			}))
+			// the same nonce of another sender is a different tx
+			require.NoError(t, txStore.Put(PendingTx{
+				From:  otherSender,
+				Nonce: 1,
+				RawTx: []byte{0x4},
+				TxIDs: []wallet.TxID{"0x4"},
+			}))
+			// a speed up replaces the tx of its nonce
+			speedUp := PendingTx{
+				From:        sender,
+				Nonce:       1,
+				RawTx:       []byte{0x3},
+				TxIDs:       []wallet.TxID{"0x1", "0x3"},
+				NumSpeedUps: 1,
+			}
+			require.NoError(t, txStore.Put(speedUp))
+
+			pendingTxs, err := txStore.List()
+			require.NoError(t, err)
+			require.Len(t, pendingTxs, 3)
+			require.Equal(t, uint64(1), pendingTxs[0].Nonce)
+			require.Equal(t, uint64(2), pendingTxs[1].Nonce)
+			require.Equal(t, speedUp.TxIDs, pendingTxs[0].TxIDs)
+			require.Equal(t, speedUp.RawTx, pendingTxs[0].RawTx)
+			require.Equal(t, otherSender, pendingTxs[2].From)
+
+			require.NoError(t, txStore.Delete(sender, 1))
+			require.NoError(t, txStore.Delete(sender, 1))
+			pendingTxs, err = txStore.List()
+			require.NoError(t, err)
+			require.Len(t, pendingTxs, 2)
+			require.Equal(t, uint64(2), pendingTxs[0].Nonce)
+			require.Equal(t, PendingTx{
+				From:  otherSender,
+				Nonce: 1,
+				RawTx: []byte{0x4},
+				TxIDs: []wallet.TxID{"0x4"},
+			}, pendingTxs[1])
+		})
+	}
+}
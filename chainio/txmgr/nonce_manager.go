@@ -0,0 +1,181 @@
+package txmgr
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceBackend is the eth client needed by the NonceManager
+type NonceBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// nonceErrorMessages are (lowercased) substrings of the errors returned by nodes when a tx nonce doesn't match the
+// account's nonce, or is already used by a pending tx
+var nonceErrorMessages = []string{
+	"nonce too low",
+	"nonce too high",
+	"replacement transaction underpriced",
+}
+
+// IsNonceError returns whether err was returned by the node because the nonce of the tx was already used or too high
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, nonceMsg := range nonceErrorMessages {
+		if strings.Contains(msg, nonceMsg) {
+			return true
+		}
+	}
+	return false
+}
+
+// NonceManager hands out the nonces of an account to concurrent senders, so that txs sent by different writers using
+// the same key don't collide. It is safe for concurrent use, and meant to be shared by all the tx managers of an
+// account.
+//
+// Each nonce obtained with Reserve must be given back with either Complete, once the tx has been broadcast, or
+// Release, if it wasn't, so that the nonce is reused by the next reservation.
+type NonceManager struct {
+	client  NonceBackend
+	address common.Address
+
+	mu     sync.Mutex
+	synced bool
+	// next is the nonce reserved next, unless some released nonces can be reused
+	next uint64
+	// released are the nonces lower than next which were released, sorted in increasing order
+	released []uint64
+	// inFlight are the reserved nonces which haven't been completed or released yet
+	inFlight map[uint64]struct{}
+}
+
+func NewNonceManager(client NonceBackend, address common.Address) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		address:  address,
+		inFlight: make(map[uint64]struct{}),
+	}
+}
+
+// Address returns the address of the account whose nonces are managed
+func (m *NonceManager) Address() common.Address {
+	return m.address
+}
+
+// Reserve returns the next nonce to use. The nonces are synced with the pending nonce of the account on the first
+// reservation.
+func (m *NonceManager) Reserve(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.synced {
+		if err := m.resync(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(m.released) > 0 {
+		nonce = m.released[0]
+		m.released = m.released[1:]
+	} else {
+		nonce = m.next
+		m.next++
+	}
+	m.inFlight[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Complete marks the tx using nonce as broadcast
+func (m *NonceManager) Complete(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.inFlight, nonce)
+}
+
+// Release gives back a reserved nonce whose tx wasn't broadcast, so that it is used by the next reservation
+func (m *NonceManager) Release(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.inFlight[nonce]; !ok {
+		return
+	}
+	delete(m.inFlight, nonce)
+	if nonce >= m.next {
+		// the nonces were resynced since the reservation
+		return
+	}
+	idx, found := slices.BinarySearch(m.released, nonce)
+	if !found {
+		m.released = slices.Insert(m.released, idx, nonce)
+	}
+}
+
+// Adopt marks nonce as used by a tx broadcast by a previous process, e.g. a tx whose monitoring was resumed after a
+// restart, so that it is never reserved. The nonces skipped to adopt it are reserved first.
+func (m *NonceManager) Adopt(ctx context.Context, nonce uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.synced {
+		if err := m.resync(ctx); err != nil {
+			return err
+		}
+	}
+
+	if nonce >= m.next {
+		for skipped := m.next; skipped < nonce; skipped++ {
+			m.released = append(m.released, skipped)
+		}
+		m.next = nonce + 1
+		return nil
+	}
+	if idx, found := slices.BinarySearch(m.released, nonce); found {
+		m.released = slices.Delete(m.released, idx, idx+1)
+	}
+	return nil
+}
+
+// InFlight returns the number of nonces reserved and not completed or released yet
+func (m *NonceManager) InFlight() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.inFlight)
+}
+
+// Resync syncs the nonces with the pending nonce of the account, e.g. after the node returned a nonce error (see
+// IsNonceError) because txs were sent by another process, or a tx considered not broadcast actually was.
+// The nonces currently in flight are never handed out again.
+func (m *NonceManager) Resync(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resync(ctx)
+}
+
+func (m *NonceManager) resync(ctx context.Context) error {
+	pendingNonce, err := m.client.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return utils.WrapError("failed to get pending nonce", err)
+	}
+	m.next = pendingNonce
+	for nonce := range m.inFlight {
+		if nonce >= m.next {
+			m.next = nonce + 1
+		}
+	}
+	// the nonces between the pending nonce and the in flight ones must be reused to not leave gaps
+	m.released = nil
+	for nonce := pendingNonce; nonce < m.next; nonce++ {
+		if _, ok := m.inFlight[nonce]; !ok {
+			m.released = append(m.released, nonce)
+		}
+	}
+	m.synced = true
+	return nil
+}
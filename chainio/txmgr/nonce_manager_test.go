@@ -0,0 +1,156 @@
+package txmgr_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+type fakeNonceBackend struct {
+	pendingNonce uint64
+}
+
+func (b *fakeNonceBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.pendingNonce, nil
+}
+
+func TestNonceManager(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeNonceBackend{pendingNonce: 5}
+	nonceManager := txmgr.NewNonceManager(backend, common.Address{})
+
+	reserve := func() uint64 {
+		nonce, err := nonceManager.Reserve(ctx)
+		require.NoError(t, err)
+		return nonce
+	}
+	require.Equal(t, uint64(5), reserve())
+	require.Equal(t, uint64(6), reserve())
+	require.Equal(t, uint64(7), reserve())
+	require.Equal(t, 3, nonceManager.InFlight())
+
+	// a released nonce is reused before the next ones
+	nonceManager.Complete(5)
+	nonceManager.Release(6)
+	require.Equal(t, 1, nonceManager.InFlight())
+	require.Equal(t, uint64(6), reserve())
+	require.Equal(t, uint64(8), reserve())
+
+	// txs were sent by another process: the nonces restart from the pending nonce, skipping those in flight
+	backend.pendingNonce = 7
+	nonceManager.Complete(6)
+	require.NoError(t, nonceManager.Resync(ctx))
+	require.Equal(t, uint64(9), reserve())
+	nonceManager.Complete(7)
+	nonceManager.Complete(8)
+	nonceManager.Complete(9)
+	require.Equal(t, 0, nonceManager.InFlight())
+
+	// the nonces are reused after a resync to a lower pending nonce, e.g. when txs were dropped
+	backend.pendingNonce = 8
+	require.NoError(t, nonceManager.Resync(ctx))
+	require.Equal(t, uint64(8), reserve())
+}
+
+func TestNonceManagerAdopt(t *testing.T) {
+	ctx := context.Background()
+	nonceManager := txmgr.NewNonceManager(&fakeNonceBackend{pendingNonce: 5}, common.Address{})
+	reserve := func() uint64 {
+		nonce, err := nonceManager.Reserve(ctx)
+		require.NoError(t, err)
+		return nonce
+	}
+
+	// the nonces skipped by an adopted tx are reserved first
+	require.NoError(t, nonceManager.Adopt(ctx, 7))
+	require.Equal(t, uint64(5), reserve())
+	require.Equal(t, uint64(6), reserve())
+	require.Equal(t, uint64(8), reserve())
+
+	// an adopted nonce which was released isn't reserved again
+	nonceManager.Release(6)
+	require.NoError(t, nonceManager.Adopt(ctx, 6))
+	require.Equal(t, uint64(9), reserve())
+	require.Equal(t, 3, nonceManager.InFlight())
+}
+
+func TestNonceManagerConcurrentReservations(t *testing.T) {
+	nonceManager := txmgr.NewNonceManager(&fakeNonceBackend{}, common.Address{})
+	var mu sync.Mutex
+	reserved := make(map[uint64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nonce, err := nonceManager.Reserve(context.Background())
+			require.NoError(t, err)
+			if i%10 == 0 {
+				// simulate a signing failure
+				nonceManager.Release(nonce)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			require.False(t, reserved[nonce])
+			reserved[nonce] = true
+			nonceManager.Complete(nonce)
+		}(i)
+	}
+	wg.Wait()
+	require.Len(t, reserved, 90)
+}
+
+func TestNonceManagerConcurrentSendsIntegration(t *testing.T) {
+	const numTxs = 50
+	ctx := context.Background()
+	ethClient, pkWallet, addr, chainId := newAnvilWallet(t)
+	logger := testutils.GetTestLogger()
+	nonceManager := txmgr.NewNonceManager(ethClient, addr)
+	// two tx managers sharing the nonce manager, as two writers using the same key would
+	txMgrs := []txmgr.TxManager{
+		txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethClient, logger).WithNonceManager(nonceManager),
+		txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethClient, logger).WithNonceManager(nonceManager),
+	}
+
+	receipts := make([]*types.Receipt, numTxs)
+	g := new(errgroup.Group)
+	for i := 0; i < numTxs; i++ {
+		i := i
+		g.Go(func() error {
+			ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			// all the txs have the same nonce, as if each writer had fetched it independently
+			tx := types.NewTx(&types.DynamicFeeTx{
+				ChainID: chainId,
+				To:      &common.Address{0x1},
+				Value:   big.NewInt(1),
+			})
+			receipt, err := txMgrs[i%len(txMgrs)].Send(ctxWithTimeout, tx, true)
+			receipts[i] = receipt
+			return err
+		})
+	}
+	require.NoError(t, g.Wait())
+
+	nonces := make(map[uint64]bool)
+	for _, receipt := range receipts {
+		require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+		tx, _, err := ethClient.TransactionByHash(ctx, receipt.TxHash)
+		require.NoError(t, err)
+		nonces[tx.Nonce()] = true
+	}
+	for nonce := uint64(0); nonce < numTxs; nonce++ {
+		require.True(t, nonces[nonce], "nonce %d not used", nonce)
+	}
+	require.Equal(t, 0, nonceManager.InFlight())
+}
@@ -0,0 +1,151 @@
+package txmgr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// selector of Error(string), used by require and revert with a reason string
+	errorStringSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// selector of Panic(uint256), used by failing asserts, overflows, etc.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// ReceiptWithDetails is a receipt enriched after its confirmation, see EnrichReceipt
+type ReceiptWithDetails struct {
+	*types.Receipt
+	// EffectiveGasCost is the fee paid by the transaction in wei, i.e. gasUsed * effectiveGasPrice
+	EffectiveGasCost *big.Int
+	// RevertReason is the reason of the revert of a failed transaction, empty for successful ones
+	RevertReason string
+}
+
+// ErrTxReverted is returned by the writers enriching their receipts (see their WithReceiptDetails) when a
+// transaction was mined but reverted
+type ErrTxReverted struct {
+	Receipt *ReceiptWithDetails
+}
+
+func (e *ErrTxReverted) Error() string {
+	return fmt.Sprintf("transaction %s reverted: %s", e.Receipt.TxHash.Hex(), e.Receipt.RevertReason)
+}
+
+// CallBackend is the eth client needed to replay a transaction with eth_call
+type CallBackend interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// EnrichReceipt computes the effective gas cost of the receipt of tx, sent by from. If the transaction failed, it is
+// replayed with eth_call at its inclusion block to extract its revert reason, decoded with DecodeRevertReason.
+// The replay runs on the state at the end of the inclusion block, so the reason can differ from the actual one when
+// the transactions mined after tx in that block changed the state it depends on.
+func EnrichReceipt(
+	ctx context.Context,
+	client CallBackend,
+	from common.Address,
+	tx *types.Transaction,
+	receipt *types.Receipt,
+	errorABIs ...*abi.ABI,
+) (*ReceiptWithDetails, error) {
+	details := &ReceiptWithDetails{
+		Receipt:          receipt,
+		EffectiveGasCost: new(big.Int),
+	}
+	if receipt.EffectiveGasPrice != nil {
+		details.EffectiveGasCost.Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return details, nil
+	}
+
+	_, err := client.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, receipt.BlockNumber)
+	if err == nil {
+		// the state the transaction depends on changed later in its block, or it ran out of gas
+		details.RevertReason = "unknown: the replay of the transaction succeeded"
+		return details, nil
+	}
+	if revertData, ok := revertDataFromError(err); ok {
+		details.RevertReason = DecodeRevertReason(revertData, errorABIs...)
+		return details, nil
+	}
+	// the errors returned by the node are execution errors, e.g. out of gas, while the others are transport errors
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return nil, utils.WrapError("failed to replay reverted transaction", err)
+	}
+	details.RevertReason = err.Error()
+	return details, nil
+}
+
+// revertDataFromError returns the revert data carried by the error of an eth_call, if any
+func revertDataFromError(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// DecodeRevertReason returns a readable reason from revert data: the string of Error(string), the cause of
+// Panic(uint256), or the name and arguments of a custom error declared by one of errorABIs. Unknown custom errors are
+// returned as their selector.
+func DecodeRevertReason(data []byte, errorABIs ...*abi.ABI) string {
+	if len(data) < 4 {
+		return "execution reverted"
+	}
+	if bytes.Equal(data[:4], errorStringSelector) || bytes.Equal(data[:4], panicSelector) {
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return fmt.Sprintf("malformed revert data %s", hexutil.Encode(data))
+		}
+		if bytes.Equal(data[:4], panicSelector) {
+			return "panic: " + reason
+		}
+		return reason
+	}
+
+	selector := [4]byte(data[:4])
+	for _, errorABI := range errorABIs {
+		errABI, err := errorABI.ErrorByID(selector)
+		if err != nil {
+			continue
+		}
+		unpacked, err := errABI.Inputs.Unpack(data[4:])
+		if err != nil {
+			return fmt.Sprintf("malformed custom error %s: %s", errABI.Name, hexutil.Encode(data))
+		}
+		args := make([]string, 0, len(unpacked))
+		for _, arg := range unpacked {
+			args = append(args, fmt.Sprint(arg))
+		}
+		return fmt.Sprintf("%s(%s)", errABI.Name, strings.Join(args, ", "))
+	}
+	return fmt.Sprintf("custom error %s", hexutil.Encode(data[:4]))
+}
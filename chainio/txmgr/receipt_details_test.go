@@ -0,0 +1,172 @@
+package txmgr_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const vaultABI = `[{"type":"error","name":"InsufficientBalance","inputs":[` +
+	`{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`
+
+// callRevertError is returned by eth_call when the execution reverts with data
+type callRevertError struct {
+	data []byte
+}
+
+func (e callRevertError) Error() string          { return "execution reverted" }
+func (e callRevertError) ErrorCode() int         { return 3 }
+func (e callRevertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// rpcError is an execution error returned by the node without revert data
+type rpcError struct {
+	message string
+}
+
+func (e rpcError) Error() string  { return e.message }
+func (e rpcError) ErrorCode() int { return -32000 }
+
+// fakeRevertingContract replays the calls made at blockNumber with the error set for it
+type fakeRevertingContract struct {
+	blockNumber *big.Int
+	callErr     error
+	calls       []ethereum.CallMsg
+}
+
+func (c *fakeRevertingContract) CallContract(
+	ctx context.Context,
+	msg ethereum.CallMsg,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	if blockNumber.Cmp(c.blockNumber) != 0 {
+		return nil, errors.New("unexpected block number")
+	}
+	c.calls = append(c.calls, msg)
+	return nil, c.callErr
+}
+
+func TestEnrichReceipt(t *testing.T) {
+	parsedVaultABI, err := abi.JSON(strings.NewReader(vaultABI))
+	require.NoError(t, err)
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	uint256Type, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	errorStringData, err := abi.Arguments{{Type: stringType}}.Pack("insufficient allowance")
+	require.NoError(t, err)
+	errorStringData = append(common.FromHex("0x08c379a0"), errorStringData...)
+	panicData, err := abi.Arguments{{Type: uint256Type}}.Pack(big.NewInt(0x11))
+	require.NoError(t, err)
+	panicData = append(common.FromHex("0x4e487b71"), panicData...)
+	insufficientBalance := parsedVaultABI.Errors["InsufficientBalance"]
+	customErrorData, err := insufficientBalance.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	require.NoError(t, err)
+	customErrorData = append(common.CopyBytes(insufficientBalance.ID[:4]), customErrorData...)
+
+	from := common.Address{0x1}
+	vault := common.Address{0x2}
+	withdrawTx := types.NewTx(&types.DynamicFeeTx{
+		To:    &vault,
+		Gas:   100_000,
+		Value: big.NewInt(0),
+		Data:  []byte("withdraw"),
+	})
+	tests := []struct {
+		name                 string
+		status               uint64
+		callErr              error
+		errorABIs            []*abi.ABI
+		expectedRevertReason string
+		expectedErr          bool
+	}{
+		{
+			name:   "successful tx is not replayed",
+			status: types.ReceiptStatusSuccessful,
+		},
+		{
+			name:                 "require with a reason string",
+			status:               types.ReceiptStatusFailed,
+			callErr:              callRevertError{data: errorStringData},
+			expectedRevertReason: "insufficient allowance",
+		},
+		{
+			name:                 "panic",
+			status:               types.ReceiptStatusFailed,
+			callErr:              callRevertError{data: panicData},
+			expectedRevertReason: "panic: arithmetic underflow or overflow",
+		},
+		{
+			name:                 "custom error declared by the contract abi",
+			status:               types.ReceiptStatusFailed,
+			callErr:              callRevertError{data: customErrorData},
+			errorABIs:            []*abi.ABI{&parsedVaultABI},
+			expectedRevertReason: "InsufficientBalance(1, 2)",
+		},
+		{
+			name:                 "unknown custom error",
+			status:               types.ReceiptStatusFailed,
+			callErr:              callRevertError{data: customErrorData},
+			expectedRevertReason: "custom error " + hexutil.Encode(insufficientBalance.ID[:4]),
+		},
+		{
+			name:                 "revert without data",
+			status:               types.ReceiptStatusFailed,
+			callErr:              callRevertError{},
+			expectedRevertReason: "execution reverted",
+		},
+		{
+			name:                 "execution error without revert data",
+			status:               types.ReceiptStatusFailed,
+			callErr:              rpcError{message: "out of gas"},
+			expectedRevertReason: "out of gas",
+		},
+		{
+			name:        "replay failure",
+			status:      types.ReceiptStatusFailed,
+			callErr:     errors.New("connection refused"),
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract := &fakeRevertingContract{blockNumber: big.NewInt(10), callErr: tt.callErr}
+			receipt := &types.Receipt{
+				Status:            tt.status,
+				TxHash:            common.Hash{0x3},
+				BlockNumber:       big.NewInt(10),
+				GasUsed:           21_000,
+				EffectiveGasPrice: big.NewInt(2_000_000_000),
+			}
+
+			details, err := txmgr.EnrichReceipt(
+				context.Background(), contract, from, withdrawTx, receipt, tt.errorABIs...,
+			)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, receipt, details.Receipt)
+			require.Equal(t, big.NewInt(42_000_000_000_000), details.EffectiveGasCost)
+			require.Equal(t, tt.expectedRevertReason, details.RevertReason)
+			if tt.status == types.ReceiptStatusSuccessful {
+				require.Empty(t, contract.calls)
+				return
+			}
+			require.Len(t, contract.calls, 1)
+			require.Equal(t, from, contract.calls[0].From)
+			require.Equal(t, &vault, contract.calls[0].To)
+			require.Equal(t, withdrawTx.Data(), contract.calls[0].Data)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -25,39 +26,100 @@ var (
 
 type ethBackend interface {
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
 }
 
 type SimpleTxManager struct {
-	wallet             wallet.Wallet
-	client             ethBackend
-	logger             logging.Logger
-	sender             common.Address
-	gasLimitMultiplier float64
+	wallet          wallet.Wallet
+	client          ethBackend
+	logger          logging.Logger
+	gasLimitOptions GasLimitOptions
+	feeEstimator    FeeEstimator
+	gasPricing      *GasPricing
+	// optional, see WithNonceManager
+	nonceManagers map[common.Address]*NonceManager
+	// see WithConfirmationDepth
+	confirmationDepth uint64
 }
 
 var _ TxManager = (*SimpleTxManager)(nil)
 
 // NewSimpleTxManager creates a new simpleTxManager which can be used
 // to send a transaction to smart contracts on the Ethereum node
+//
+// Deprecated: the transactions are sent from the SenderAddress of wallet, and sender is ignored. Use
+// NewSimpleTxManagerFromWallet instead.
 func NewSimpleTxManager(
 	wallet wallet.Wallet,
 	client ethBackend,
 	logger logging.Logger,
 	sender common.Address,
+) *SimpleTxManager {
+	return NewSimpleTxManagerFromWallet(wallet, client, logger)
+}
+
+// NewSimpleTxManagerFromWallet creates a new simpleTxManager sending the transactions from the SenderAddress of
+// wallet to smart contracts on the Ethereum node
+func NewSimpleTxManagerFromWallet(
+	wallet wallet.Wallet,
+	client ethBackend,
+	logger logging.Logger,
 ) *SimpleTxManager {
 	return &SimpleTxManager{
-		wallet:             wallet,
-		client:             client,
-		logger:             logger,
-		sender:             sender,
-		gasLimitMultiplier: FallbackGasLimitMultiplier,
+		wallet:          wallet,
+		client:          client,
+		logger:          logger,
+		gasLimitOptions: GasLimitOptions{GasLimitMultiplier: FallbackGasLimitMultiplier},
+		feeEstimator:    newDefaultFeeEstimator(client, logger),
+		gasPricing:      NewGasPricing(GasPricingModeAuto, client),
+		nonceManagers:   make(map[common.Address]*NonceManager),
 	}
 }
 
 func (m *SimpleTxManager) WithGasLimitMultiplier(multiplier float64) *SimpleTxManager {
-	m.gasLimitMultiplier = multiplier
+	m.gasLimitOptions.GasLimitMultiplier = multiplier
+	return m
+}
+
+// WithFallbackGasLimit makes the SimpleTxManager use gasLimit for the txs whose gas estimation reverts, instead of
+// failing to send them. This is needed for txs which only succeed once a pending tx is mined, e.g. a deposit
+// following an approval. The gas limit options of a single send can be overridden with WithGasLimitOptions.
+func (m *SimpleTxManager) WithFallbackGasLimit(gasLimit uint64) *SimpleTxManager {
+	m.gasLimitOptions.FallbackGasLimit = gasLimit
+	return m
+}
+
+// WithNonceManager makes the SimpleTxManager set the nonce of every tx it sends from the address of nonceManager,
+// overriding the nonce set by the caller. The same NonceManager must be used by all the senders of the account, so
+// that they don't use the same nonces.
+// With a wallet signing for multiple addresses, WithNonceManager can be called once per address.
+func (m *SimpleTxManager) WithNonceManager(nonceManager *NonceManager) *SimpleTxManager {
+	m.nonceManagers[nonceManager.Address()] = nonceManager
+	return m
+}
+
+// WithConfirmationDepth makes Send wait, when waitForReceipt is set, until the block of the receipt has
+// confirmationDepth descendants and is still canonical, instead of returning the first receipt seen.
+// If the tx is reorged out meanwhile, Send keeps waiting for it to be mined again.
+func (m *SimpleTxManager) WithConfirmationDepth(confirmationDepth uint64) *SimpleTxManager {
+	m.confirmationDepth = confirmationDepth
+	return m
+}
+
+// WithFeeEstimator replaces the default fee estimation (eth_maxPriorityFeePerGas as tip, and 2*baseFee + tip as fee
+// cap), e.g. with a configured EIP1559FeeEstimator
+func (m *SimpleTxManager) WithFeeEstimator(feeEstimator FeeEstimator) *SimpleTxManager {
+	m.feeEstimator = feeEstimator
+	return m
+}
+
+// WithGasPricingMode sets the type of the txs sent. By default (GasPricingModeAuto), legacy txs priced with the gas
+// price suggested by the node are sent to chains without a base fee, and dynamic fee txs priced by the FeeEstimator
+// otherwise.
+func (m *SimpleTxManager) WithGasPricingMode(mode GasPricingMode) *SimpleTxManager {
+	m.gasPricing = NewGasPricing(mode, m.client)
 	return m
 }
 
@@ -74,15 +136,17 @@ func (m *SimpleTxManager) Send(
 	waitForReceipt bool,
 ) (*types.Receipt, error) {
 
-	r, err := m.send(ctx, tx)
+	txID, sentTx, err := m.send(ctx, tx)
 	if err != nil {
 		return nil, errors.Join(errors.New("send: failed to estimate gas and nonce"), err)
 	}
 	if !waitForReceipt {
-		return r, nil
+		return &types.Receipt{
+			TxHash: common.HexToHash(txID),
+		}, nil
 	}
 
-	receipt, err := m.waitForReceipt(ctx, r.TxHash.Hex())
+	receipt, err := m.waitForReceipt(ctx, txID, sentTx)
 	if err != nil {
 		log.Info("Transaction receipt not found", "err", err)
 		return nil, err
@@ -91,31 +155,78 @@ func (m *SimpleTxManager) Send(
 	return receipt, nil
 }
 
-func (m *SimpleTxManager) send(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+// SendAsync sends the transaction like Send, then waits for its receipt in a background goroutine.
+// See TxManager.SendAsync for the cancellation and ordering semantics.
+func (m *SimpleTxManager) SendAsync(ctx context.Context, tx *types.Transaction) (<-chan TxResult, error) {
+	txID, sentTx, err := m.send(ctx, tx)
+	if err != nil {
+		return nil, errors.Join(errors.New("send: failed to estimate gas and nonce"), err)
+	}
+
+	resultChan := make(chan TxResult, 1)
+	go func() {
+		defer close(resultChan)
+		receipt, err := m.waitForReceipt(ctx, txID, sentTx)
+		resultChan <- TxResult{Receipt: receipt, Err: err}
+	}()
+	return resultChan, nil
+}
+
+// send sends tx, and returns its wallet id along with the transaction sent
+func (m *SimpleTxManager) send(ctx context.Context, tx *types.Transaction) (wallet.TxID, *types.Transaction, error) {
+	from, err := m.wallet.SenderAddress(ctx)
+	if err != nil {
+		return "", nil, errors.Join(errors.New("send: failed to get sender address"), err)
+	}
+	nonceManager, ok := m.nonceManagers[from]
+	if !ok {
+		return m.sendWithNonce(ctx, tx, tx.Nonce())
+	}
+
+	// on a nonce error, the nonces are resynced with the node and the tx is sent again once
+	for attempt := 0; ; attempt++ {
+		nonce, err := nonceManager.Reserve(ctx)
+		if err != nil {
+			return "", nil, utils.WrapError("send: failed to reserve nonce", err)
+		}
+		txID, sentTx, sendErr := m.sendWithNonce(ctx, tx, nonce)
+		if sendErr == nil {
+			nonceManager.Complete(nonce)
+			return txID, sentTx, nil
+		}
+		nonceManager.Release(nonce)
+		if !IsNonceError(sendErr) || attempt > 0 {
+			return "", nil, sendErr
+		}
+		m.logger.Warn("nonce error while sending tx, resyncing nonces", "nonce", nonce, "err", sendErr)
+		if err := nonceManager.Resync(ctx); err != nil {
+			return "", nil, errors.Join(sendErr, utils.WrapError("send: failed to resync nonces", err))
+		}
+	}
+}
+
+func (m *SimpleTxManager) sendWithNonce(
+	ctx context.Context,
+	tx *types.Transaction,
+	nonce uint64,
+) (wallet.TxID, *types.Transaction, error) {
 	// Estimate gas and nonce
 	// can't print tx hash in logs because the tx changes below when we complete and sign it
 	// so the txHash is meaningless at this point
 	m.logger.Debug("Estimating gas and nonce")
 	tx, err := m.estimateGasAndNonce(ctx, tx)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
-	bumpedGasTx := &types.DynamicFeeTx{
-		To:        tx.To(),
-		Nonce:     tx.Nonce(),
-		GasFeeCap: tx.GasFeeCap(),
-		GasTipCap: tx.GasTipCap(),
-		Gas:       uint64(float64(tx.Gas()) * m.gasLimitMultiplier),
-		Value:     tx.Value(),
-		Data:      tx.Data(),
+	txWithNonce, err := withNonce(tx, nonce)
+	if err != nil {
+		return "", nil, err
 	}
-	txID, err := m.wallet.SendTransaction(ctx, types.NewTx(bumpedGasTx))
+	txID, err := m.wallet.SendTransaction(ctx, txWithNonce)
 	if err != nil {
-		return nil, errors.Join(errors.New("send: failed to estimate gas and nonce"), err)
+		return "", nil, errors.Join(errors.New("send: failed to estimate gas and nonce"), err)
 	}
-	return &types.Receipt{
-		TxHash: common.HexToHash(txID),
-	}, nil
+	return txID, txWithNonce, nil
 }
 
 func NoopSigner(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
@@ -125,29 +236,83 @@ func NoopSigner(addr common.Address, tx *types.Transaction) (*types.Transaction,
 // GetNoSendTxOpts This generates a noSend TransactOpts so that we can use
 // this to generate the transaction without actually sending it
 func (m *SimpleTxManager) GetNoSendTxOpts() (*bind.TransactOpts, error) {
+	return m.GetNoSendTxOptsWithContext(context.Background())
+}
+
+// GetNoSendTxOptsWithContext is GetNoSendTxOpts with the sender selected on ctx with wallet.WithSenderAddress
+func (m *SimpleTxManager) GetNoSendTxOptsWithContext(ctx context.Context) (*bind.TransactOpts, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	from, err := m.wallet.SenderAddress(ctxWithTimeout)
+	if err != nil {
+		return nil, utils.WrapError("failed to get sender address", err)
+	}
 	return &bind.TransactOpts{
-		From:   m.sender,
+		From:   from,
 		NoSend: true,
 		Signer: NoopSigner,
 	}, nil
 }
 
-func (m *SimpleTxManager) waitForReceipt(ctx context.Context, txID wallet.TxID) (*types.Receipt, error) {
+// waitForReceipt waits for the receipt of the transaction tx sent with txID. It returns an ErrTxDeadlineExceeded if
+// the deadline of ctx passes first.
+func (m *SimpleTxManager) waitForReceipt(
+	ctx context.Context,
+	txID wallet.TxID,
+	tx *types.Transaction,
+) (*types.Receipt, error) {
 	// TODO: make this ticker adjustable
 	queryTicker := time.NewTicker(2 * time.Second)
 	defer queryTicker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, &ErrTxDeadlineExceeded{
+					TxID:          txID,
+					TxHash:        common.HexToHash(txID),
+					Nonce:         tx.Nonce(),
+					LastGasFeeCap: tx.GasFeeCap(),
+				}
+			}
 			return nil, errors.Join(errors.New("Context done before tx was mined"), ctx.Err())
 		case <-queryTicker.C:
-			if receipt := m.queryReceipt(ctx, txID); receipt != nil {
+			if receipt := m.queryReceipt(ctx, txID); receipt != nil && m.isConfirmed(ctx, receipt) {
 				return receipt, nil
 			}
 		}
 	}
 }
 
+// isConfirmed returns whether the block of receipt has confirmationDepth descendants and is still canonical.
+// The receipt was just queried, so without confirmation depth it is canonical.
+func (m *SimpleTxManager) isConfirmed(ctx context.Context, receipt *types.Receipt) bool {
+	if m.confirmationDepth == 0 {
+		return true
+	}
+	latestHeader, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.logger.Info("Failed to get latest header", "err", err)
+		return false
+	}
+	if receipt.BlockNumber.Uint64()+m.confirmationDepth > latestHeader.Number.Uint64() {
+		m.logger.Debug("Transaction mined but not confirmed yet", "txHash", receipt.TxHash,
+			"blockNumber", receipt.BlockNumber, "confirmationDepth", m.confirmationDepth, "chainTip", latestHeader.Number)
+		return false
+	}
+	header, err := m.client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		m.logger.Info("Failed to get header of the receipt block", "blockNumber", receipt.BlockNumber, "err", err)
+		return false
+	}
+	if header.Hash() != receipt.BlockHash {
+		m.logger.Warn("Transaction block was reorged out, waiting for the transaction to be mined again",
+			"txHash", receipt.TxHash, "blockNumber", receipt.BlockNumber, "blockHash", receipt.BlockHash)
+		return false
+	}
+	return true
+}
+
 func (m *SimpleTxManager) queryReceipt(ctx context.Context, txID wallet.TxID) *types.Receipt {
 	receipt, err := m.wallet.GetTransactionReceipt(ctx, txID)
 	if errors.Is(err, ethereum.NotFound) {
@@ -167,54 +332,68 @@ func (m *SimpleTxManager) queryReceipt(ctx context.Context, txID wallet.TxID) *t
 // estimateGasAndNonce we are explicitly implementing this because
 // * We want to support legacy transactions (i.e. not dynamic fee)
 // * We want to support gas management, i.e. add buffer to gas limit
+// * We want to support blob transactions, whose blob gas fee cap is estimated from the latest blob base fee
 func (m *SimpleTxManager) estimateGasAndNonce(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
-	gasTipCap, err := m.client.SuggestGasTipCap(ctx)
+	legacy, err := m.gasPricing.IsLegacy(ctx)
 	if err != nil {
-		// If the transaction failed because the backend does not support
-		// eth_maxPriorityFeePerGas, fallback to using the default constant.
-		m.logger.Info("eth_maxPriorityFeePerGas is unsupported by current backend, using fallback gasTipCap")
-		gasTipCap = FallbackGasTipCap
+		return nil, errors.Join(errors.New("send: failed to get gas pricing mode"), err)
 	}
-
-	header, err := m.client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
+	var gasPrice, gasTipCap, gasFeeCap, blobGasFeeCap *big.Int
+	if legacy {
+		if tx.Type() == types.BlobTxType {
+			return nil, ErrBlobTxUnsupported
+		}
+		gasPrice, err = m.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, errors.Join(errors.New("send: failed to suggest gas price"), err)
+		}
+	} else {
+		gasTipCap, gasFeeCap, err = m.feeEstimator.EstimateFees(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// 2*baseFee + gasTipCap makes sure that the tx remains includeable for 6 consecutive 100% full blocks.
-	// see https://www.blocknative.com/blog/eip-1559-fees
-	gasFeeCap := new(big.Int).Add(header.BaseFee.Mul(header.BaseFee, big.NewInt(2)), gasTipCap)
-
-	gasLimit := tx.Gas()
-	// we only estimate if gasLimit is not already set
-	if gasLimit == 0 {
-		from, err := m.wallet.SenderAddress(ctx)
+	if tx.Type() == types.BlobTxType {
+		header, err := m.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, errors.Join(errors.New("send: failed to get latest header"), err)
+		}
+		blobGasFeeCap, err = EstimateBlobGasFeeCap(header)
 		if err != nil {
-			return nil, errors.Join(errors.New("send: failed to get sender address"), err)
+			return nil, err
 		}
-		gasLimit, err = m.client.EstimateGas(ctx, ethereum.CallMsg{
-			From:      from,
-			To:        tx.To(),
-			GasTipCap: gasTipCap,
-			GasFeeCap: gasFeeCap,
-			Value:     tx.Value(),
-			Data:      tx.Data(),
-		})
+		// the blob hashes are needed by the gas estimation
+		tx, err = NewTxWithFees(tx, tx.Nonce(), tx.Gas(), gasTipCap, gasFeeCap, blobGasFeeCap)
 		if err != nil {
-			return nil, errors.Join(errors.New("send: failed to estimate gas"), err)
+			return nil, err
 		}
 	}
 
-	rawTx := &types.DynamicFeeTx{
-		ChainID:   tx.ChainId(),
-		To:        tx.To(),
-		GasTipCap: gasTipCap,
-		GasFeeCap: gasFeeCap,
-		Data:      tx.Data(),
-		Value:     tx.Value(),
-		Gas:       gasLimit,
-		Nonce:     tx.Nonce(), // We are not doing any nonce management for now but we probably should later for more robustness
+	from, err := m.wallet.SenderAddress(ctx)
+	if err != nil {
+		return nil, errors.Join(errors.New("send: failed to get sender address"), err)
+	}
+	// we only estimate if the gas limit is not already set, but add a buffer to it in any case
+	gasLimit, err := EstimateGasLimit(ctx, m.client, m.logger, ethereum.CallMsg{
+		From:          from,
+		To:            tx.To(),
+		Gas:           tx.Gas(),
+		GasPrice:      gasPrice,
+		GasTipCap:     gasTipCap,
+		GasFeeCap:     gasFeeCap,
+		Value:         tx.Value(),
+		Data:          tx.Data(),
+		BlobGasFeeCap: blobGasFeeCap,
+		BlobHashes:    tx.BlobHashes(),
+	}, m.gasLimitOptions)
+	if err != nil {
+		return nil, errors.Join(errors.New("send: failed to estimate gas"), err)
 	}
 
-	return types.NewTx(rawTx), nil
+	// the nonce is set by the caller, or by the NonceManager when the tx is sent
+	if legacy {
+		return NewLegacyTxWithGasPrice(tx, tx.Nonce(), gasLimit, gasPrice)
+	}
+	return NewTxWithFees(tx, tx.Nonce(), gasLimit, gasTipCap, gasFeeCap, blobGasFeeCap)
 }
@@ -0,0 +1,256 @@
+package txmgr_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// newAnvilWallet starts anvil with anvilArgs and returns a client to it, with a wallet of its first prefunded account
+func newAnvilWallet(t *testing.T, anvilArgs ...string) (*ethclient.Client, wallet.Wallet, common.Address, *big.Int) {
+	anvilC, err := testutils.StartAnvilContainerWithArgs("", anvilArgs...)
+	require.NoError(t, err)
+	ctx := context.Background()
+	anvilHttpEndpoint, err := anvilC.Endpoint(ctx, "http")
+	require.NoError(t, err)
+	ethClient, err := ethclient.Dial(anvilHttpEndpoint)
+	require.NoError(t, err)
+
+	ecdsaSk, addr, err := ecdsa.KeyAndAddressFromHexKey(
+		"0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+	)
+	require.NoError(t, err)
+	chainId, err := ethClient.ChainID(ctx)
+	require.NoError(t, err)
+	signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
+	require.NoError(t, err)
+	pkWallet, err := wallet.NewPrivateKeyWallet(ethClient, signerFn, addr, testutils.GetTestLogger())
+	require.NoError(t, err)
+	return ethClient, pkWallet, addr, chainId
+}
+
+func TestSimpleTxManagerConfirmationDepthReorg(t *testing.T) {
+	const confirmationDepth = 3
+	ctx := context.Background()
+	ethClient, pkWallet, _, chainId := newAnvilWallet(t)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethClient, testutils.GetTestLogger()).
+		WithConfirmationDepth(confirmationDepth)
+
+	var snapshotId string
+	require.NoError(t, ethClient.Client().CallContext(ctx, &snapshotId, "evm_snapshot"))
+	startBlock, err := ethClient.BlockNumber(ctx)
+	require.NoError(t, err)
+
+	type sendResult struct {
+		receipt *types.Receipt
+		err     error
+	}
+	resultC := make(chan sendResult, 1)
+	go func() {
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainId, To: &common.Address{0x1}, Value: big.NewInt(1)})
+		receipt, err := txMgr.Send(ctxWithTimeout, tx, true)
+		resultC <- sendResult{receipt: receipt, err: err}
+	}()
+
+	// wait for the tx to be mined (anvil automines it), then orphan its block
+	require.Eventually(t, func() bool {
+		blockNumber, err := ethClient.BlockNumber(ctx)
+		return err == nil && blockNumber > startBlock
+	}, 10*time.Second, 100*time.Millisecond)
+	orphanedBlock, err := ethClient.BlockByNumber(ctx, new(big.Int).SetUint64(startBlock+1))
+	require.NoError(t, err)
+	require.Len(t, orphanedBlock.Transactions(), 1)
+	minedTx := orphanedBlock.Transactions()[0]
+	var reverted bool
+	require.NoError(t, ethClient.Client().CallContext(ctx, &reverted, "evm_revert", snapshotId))
+	require.True(t, reverted)
+
+	// include the tx in another block, and confirm it
+	require.NoError(t, ethClient.Client().CallContext(ctx, nil, "anvil_mine", 1))
+	require.NoError(t, ethClient.SendTransaction(ctx, minedTx))
+	require.NoError(t, ethClient.Client().CallContext(ctx, nil, "anvil_mine", confirmationDepth))
+
+	result := <-resultC
+	require.NoError(t, result.err)
+	require.Equal(t, minedTx.Hash(), result.receipt.TxHash)
+	require.NotEqual(t, orphanedBlock.Hash(), result.receipt.BlockHash)
+	canonicalHeader, err := ethClient.HeaderByNumber(ctx, result.receipt.BlockNumber)
+	require.NoError(t, err)
+	require.Equal(t, canonicalHeader.Hash(), result.receipt.BlockHash)
+}
+
+func TestSimpleTxManagerSendAsync(t *testing.T) {
+	ctx := context.Background()
+	ethClient, pkWallet, _, chainId := newAnvilWallet(t)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethClient, testutils.GetTestLogger())
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	resultChans := make([]<-chan txmgr.TxResult, 3)
+	for nonce := range resultChans {
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainId,
+			Nonce:   uint64(nonce),
+			To:      &common.Address{0x1},
+			Value:   big.NewInt(1),
+		})
+		resultChan, err := txMgr.SendAsync(ctxWithTimeout, tx)
+		require.NoError(t, err)
+		resultChans[nonce] = resultChan
+	}
+	for _, resultChan := range resultChans {
+		result := <-resultChan
+		require.NoError(t, result.Err)
+		require.Equal(t, types.ReceiptStatusSuccessful, result.Receipt.Status)
+	}
+}
+
+// fakeMinedBackend mines every transaction sent to it, recording them
+type fakeMinedBackend struct {
+	fakeTokenBackend
+	mu       sync.Mutex
+	minedTxs map[common.Hash]*types.Transaction
+}
+
+func (b *fakeMinedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.minedTxs[tx.Hash()] = tx
+	return nil
+}
+
+func (b *fakeMinedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.minedTxs[txHash]; !ok {
+		return nil, ethereum.NotFound
+	}
+	return &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(1)}, nil
+}
+
+func TestSimpleTxManagerMultiWallet(t *testing.T) {
+	chainId := big.NewInt(31337)
+	logger := testutils.GetTestLogger()
+	backend := &fakeMinedBackend{minedTxs: make(map[common.Hash]*types.Transaction)}
+	wallets := make(map[common.Address]wallet.Wallet)
+	for i := 0; i < 2; i++ {
+		ecdsaSk, addr, err := testutils.NewEcdsaSkAndAddress()
+		require.NoError(t, err)
+		signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, chainId)
+		require.NoError(t, err)
+		wallets[addr], err = wallet.NewPrivateKeyWallet(backend, signerFn, addr, logger)
+		require.NoError(t, err)
+	}
+	multiWallet, err := wallet.NewMultiWallet(wallets)
+	require.NoError(t, err)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(multiWallet, backend, logger)
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	senders := multiWallet.Senders()
+	resultChans := make([]<-chan txmgr.TxResult, len(senders))
+	for i, sender := range senders {
+		tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainId, To: &common.Address{0x1}, Value: big.NewInt(1)})
+		resultChans[i], err = txMgr.SendAsync(wallet.WithSenderAddress(ctxWithTimeout, sender), tx)
+		require.NoError(t, err)
+	}
+	for i, resultChan := range resultChans {
+		result := <-resultChan
+		require.NoError(t, result.Err)
+		require.Equal(t, types.ReceiptStatusSuccessful, result.Receipt.Status)
+
+		backend.mu.Lock()
+		tx := backend.minedTxs[result.Receipt.TxHash]
+		backend.mu.Unlock()
+		from, err := types.Sender(types.LatestSignerForChainID(chainId), tx)
+		require.NoError(t, err)
+		require.Equal(t, senders[i], from)
+	}
+
+	// the receipts can also be looked up without selecting the sender, without the wallet tracking the transactions
+	for txHash := range backend.minedTxs {
+		receipt, err := multiWallet.GetTransactionReceipt(context.Background(), txHash.Hex())
+		require.NoError(t, err)
+		require.Equal(t, txHash, receipt.TxHash)
+	}
+}
+
+func TestSimpleTxManagerBlobTx(t *testing.T) {
+	ctx := context.Background()
+	ethClient, pkWallet, _, chainId := newAnvilWallet(t)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethClient, testutils.GetTestLogger())
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	blobs := []kzg4844.Blob{{0x1}, {0x2}}
+	receipt, err := txMgr.Send(ctxWithTimeout, txmgr.NewBlobTxCandidate(chainId, common.Address{0x1}, nil, blobs), true)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	require.Equal(t, uint64(len(blobs)*params.BlobTxBlobGasPerBlob), receipt.BlobGasUsed)
+
+	tx, _, err := ethClient.TransactionByHash(ctx, receipt.TxHash)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.BlobTxType), tx.Type())
+	require.Len(t, tx.BlobHashes(), len(blobs))
+}
+
+func TestSimpleTxManagerLegacyTx(t *testing.T) {
+	ctx := context.Background()
+	// berlin is the last hardfork before EIP-1559
+	ethClient, pkWallet, _, _ := newAnvilWallet(t, "--hardfork", "berlin")
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethClient, testutils.GetTestLogger())
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	candidate := types.NewTx(&types.LegacyTx{To: &common.Address{0x1}, Value: big.NewInt(1)})
+	receipt, err := txMgr.Send(ctxWithTimeout, candidate, true)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	tx, _, err := ethClient.TransactionByHash(ctx, receipt.TxHash)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.LegacyTxType), tx.Type())
+}
+
+func TestSimpleTxManagerDeadlineExceeded(t *testing.T) {
+	ecdsaSk, addr, err := testutils.NewEcdsaSkAndAddress()
+	require.NoError(t, err)
+	signerFn, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: ecdsaSk}, big.NewInt(31337))
+	require.NoError(t, err)
+	// the txs sent to the backend are never mined
+	backend := &fakeTokenBackend{}
+	pkWallet, err := wallet.NewPrivateKeyWallet(backend, signerFn, addr, testutils.GetTestLogger())
+	require.NoError(t, err)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, backend, testutils.GetTestLogger())
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	candidate := types.NewTx(&types.DynamicFeeTx{Nonce: 7, To: &common.Address{0x1}, Data: approveData})
+	_, err = txMgr.Send(ctxWithTimeout, candidate, true)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var deadlineErr *txmgr.ErrTxDeadlineExceeded
+	require.ErrorAs(t, err, &deadlineErr)
+
+	require.Len(t, backend.sentTxs, 1)
+	pendingTx := backend.sentTxs[0]
+	require.Equal(t, pendingTx.Hash(), deadlineErr.TxHash)
+	require.Equal(t, uint64(7), deadlineErr.Nonce)
+	require.Equal(t, pendingTx.GasFeeCap(), deadlineErr.LastGasFeeCap)
+}
@@ -7,12 +7,31 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
+// TxResult is the outcome of a transaction sent with SendAsync: either the receipt of the mined transaction, or the
+// error which terminated the waiting for it
+type TxResult struct {
+	Receipt *types.Receipt
+	Err     error
+}
+
 type TxManager interface {
 	// Send is used to sign and send a transaction to an evm chain
 	// It takes an unsigned transaction and then signs it before sending
 	// It might also take care of nonce management and gas estimation, depending on the implementation
 	Send(ctx context.Context, tx *types.Transaction, waitForReceipt bool) (*types.Receipt, error)
 
+	// SendAsync signs and broadcasts a transaction like Send, but returns as soon as it has been broadcast.
+	// Waiting for the receipt (and bumping the gas price, depending on the implementation) continues in the
+	// background, and its outcome is delivered as a single TxResult on the returned channel, which is then closed.
+	// An error is returned directly if the transaction could not be broadcast.
+	//
+	// Cancelling ctx only stops the background waiting: the result then carries the context error, but the
+	// transaction was already broadcast and may still get mined. It is never "unsent".
+	//
+	// Results are not guaranteed to be delivered in nonce order, even for sequential nonces of the same sender.
+	// Callers that need ordered results should read the channels in the order the transactions were sent.
+	SendAsync(ctx context.Context, tx *types.Transaction) (<-chan TxResult, error)
+
 	// GetNoSendTxOpts generates a TransactOpts with
 	// - NoSend=true: b/c we want to manage the sending ourselves
 	// - Signer=NoopSigner: b/c we want the wallet to manage signing
@@ -20,4 +39,8 @@ type TxManager interface {
 	// This is needed when using abigen to construct transactions.
 	// this to generate the transaction without actually sending it
 	GetNoSendTxOpts() (*bind.TransactOpts, error)
+
+	// GetNoSendTxOptsWithContext is GetNoSendTxOpts with the sender of ctx, which is selected with
+	// wallet.WithSenderAddress for a wallet signing for multiple addresses, e.g. a wallet.MultiWallet
+	GetNoSendTxOptsWithContext(ctx context.Context) (*bind.TransactOpts, error)
 }
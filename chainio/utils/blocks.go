@@ -0,0 +1,30 @@
+package utils
+
+import "errors"
+
+var ErrInvalidBlockChunkSize = errors.New("block chunk size must be positive")
+
+// ForEachBlockChunk splits the blocks from startBlock to stopBlock (inclusive) in chunks of chunkSize blocks, the last
+// one being shorter if needed, and calls fn with the first and last block (inclusive) of each chunk in order, stopping
+// at the first error returned by fn. Nothing is called if startBlock is after stopBlock. The chunks don't overflow
+// when stopBlock is close to the max uint64.
+func ForEachBlockChunk(startBlock, stopBlock, chunkSize uint64, fn func(fromBlock, toBlock uint64) error) error {
+	if chunkSize == 0 {
+		return ErrInvalidBlockChunkSize
+	}
+	for fromBlock := startBlock; fromBlock <= stopBlock; fromBlock += chunkSize {
+		toBlock := stopBlock
+		if stopBlock-fromBlock >= chunkSize {
+			// Subtract 1 since the chunks are inclusive
+			toBlock = fromBlock + chunkSize - 1
+		}
+		if err := fn(fromBlock, toBlock); err != nil {
+			return err
+		}
+		// fromBlock + chunkSize would overflow when stopBlock is close to the max uint64
+		if toBlock == stopBlock {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package utils_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachBlockChunk(t *testing.T) {
+	var tests = []struct {
+		name       string
+		startBlock uint64
+		stopBlock  uint64
+		chunkSize  uint64
+		wantChunks [][2]uint64
+	}{
+		{
+			name:       "the last chunk is shorter",
+			startBlock: 10,
+			stopBlock:  34,
+			chunkSize:  10,
+			wantChunks: [][2]uint64{{10, 19}, {20, 29}, {30, 34}},
+		},
+		{
+			name:       "the range is a multiple of the chunk size",
+			startBlock: 0,
+			stopBlock:  19,
+			chunkSize:  10,
+			wantChunks: [][2]uint64{{0, 9}, {10, 19}},
+		},
+		{
+			name:       "single block",
+			startBlock: 5,
+			stopBlock:  5,
+			chunkSize:  10,
+			wantChunks: [][2]uint64{{5, 5}},
+		},
+		{
+			name:       "start block after the stop block",
+			startBlock: 6,
+			stopBlock:  5,
+			chunkSize:  10,
+			wantChunks: nil,
+		},
+		{
+			name:       "stop block close to the max uint64",
+			startBlock: math.MaxUint64 - 14,
+			stopBlock:  math.MaxUint64 - 1,
+			chunkSize:  10,
+			wantChunks: [][2]uint64{{math.MaxUint64 - 14, math.MaxUint64 - 5}, {math.MaxUint64 - 4, math.MaxUint64 - 1}},
+		},
+		{
+			name:       "stop block at the max uint64",
+			startBlock: math.MaxUint64 - 9,
+			stopBlock:  math.MaxUint64,
+			chunkSize:  10,
+			wantChunks: [][2]uint64{{math.MaxUint64 - 9, math.MaxUint64}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotChunks [][2]uint64
+			appendChunk := func(from, to uint64) error {
+				gotChunks = append(gotChunks, [2]uint64{from, to})
+				return nil
+			}
+			err := chainioutils.ForEachBlockChunk(tt.startBlock, tt.stopBlock, tt.chunkSize, appendChunk)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantChunks, gotChunks)
+		})
+	}
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		wantErr := errors.New("query failed")
+		var calls int
+		err := chainioutils.ForEachBlockChunk(0, 99, 10, func(from, to uint64) error {
+			calls++
+			if from == 20 {
+				return wantErr
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, wantErr)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("zero chunk size is refused", func(t *testing.T) {
+		err := chainioutils.ForEachBlockChunk(0, 99, 0, func(from, to uint64) error { return nil })
+		require.ErrorIs(t, err, chainioutils.ErrInvalidBlockChunkSize)
+	})
+}
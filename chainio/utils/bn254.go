@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// BN254.sol is a library, so each binding of a contract using it has its own BN254G1Point and BN254G2Point structs,
+// e.g. regcoord.BN254G1Point and blssigcheck.BN254G1Point. The conversions below are generic over these structs, and
+// check that the points are on the curve, and for G2 in the subgroup, like the precompiles used by the contracts.
+
+// BN254G1Point is the BN254G1Point struct of any binding
+type BN254G1Point interface {
+	~struct {
+		X *big.Int
+		Y *big.Int
+	}
+}
+
+// BN254G2Point is the BN254G2Point struct of any binding, whose coordinates are in the [A1, A0] order of the contracts
+type BN254G2Point interface {
+	~struct {
+		X [2]*big.Int
+		Y [2]*big.Int
+	}
+}
+
+// PubkeyRegistrationParams is the IBLSApkRegistryPubkeyRegistrationParams struct of any binding, e.g. the ones of
+// RegistryCoordinator and BLSApkRegistry
+type PubkeyRegistrationParams[G1 BN254G1Point, G2 BN254G2Point] interface {
+	~struct {
+		PubkeyRegistrationSignature G1
+		PubkeyG1                    G1
+		PubkeyG2                    G2
+	}
+}
+
+type bn254G1Point = struct {
+	X *big.Int
+	Y *big.Int
+}
+
+type bn254G2Point = struct {
+	X [2]*big.Int
+	Y [2]*big.Int
+}
+
+// G1PointToBinding converts p to the BN254G1Point struct T of a binding, e.g.
+// G1PointToBinding[blssigcheck.BN254G1Point](p)
+func G1PointToBinding[T BN254G1Point](p *bls.G1Point) (T, error) {
+	if p == nil || p.G1Affine == nil {
+		return T{}, fmt.Errorf("G1 point: %w", bls.ErrNilPoint)
+	}
+	if !p.IsOnCurve() {
+		return T{}, fmt.Errorf("%w: G1 point is not on the curve", bls.ErrInvalidPoint)
+	}
+	return T(toBN254G1Point(p.G1Affine)), nil
+}
+
+// G1PointFromBinding converts the BN254G1Point struct of a binding to a G1 point. Its coordinates must be elements
+// of the base field.
+func G1PointFromBinding[T BN254G1Point](p T) (*bls.G1Point, error) {
+	point := bn254G1Point(p)
+	if !isFpElement(point.X) || !isFpElement(point.Y) {
+		return nil, fmt.Errorf("%w: G1 point coordinates must be elements of the base field", bls.ErrInvalidPoint)
+	}
+	g1Point := bls.NewG1Point(point.X, point.Y)
+	if !g1Point.IsOnCurve() {
+		return nil, fmt.Errorf("%w: G1 point is not on the curve", bls.ErrInvalidPoint)
+	}
+	return g1Point, nil
+}
+
+// G2PointToBinding converts p to the BN254G2Point struct T of a binding, e.g.
+// G2PointToBinding[blssigcheck.BN254G2Point](p)
+func G2PointToBinding[T BN254G2Point](p *bls.G2Point) (T, error) {
+	if p == nil || p.G2Affine == nil {
+		return T{}, fmt.Errorf("G2 point: %w", bls.ErrNilPoint)
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return T{}, fmt.Errorf("%w: G2 point is not in the subgroup", bls.ErrInvalidPoint)
+	}
+	return T(toBN254G2Point(p.G2Affine)), nil
+}
+
+// G2PointFromBinding converts the BN254G2Point struct of a binding to a G2 point. Its coordinates must be elements
+// of the base field.
+func G2PointFromBinding[T BN254G2Point](p T) (*bls.G2Point, error) {
+	point := bn254G2Point(p)
+	for _, coordinate := range [][2]*big.Int{point.X, point.Y} {
+		if !isFpElement(coordinate[0]) || !isFpElement(coordinate[1]) {
+			return nil, fmt.Errorf("%w: G2 point coordinates must be elements of the base field", bls.ErrInvalidPoint)
+		}
+	}
+	// like the contracts, NewG2Point takes the coordinates in the [A1, A0] order
+	g2Point := bls.NewG2Point(point.X, point.Y)
+	if !g2Point.IsOnCurve() || !g2Point.IsInSubGroup() {
+		return nil, fmt.Errorf("%w: G2 point is not in the subgroup", bls.ErrInvalidPoint)
+	}
+	return g2Point, nil
+}
+
+// SignatureToBinding converts sig to the BN254G1Point struct T of a binding, e.g. the sigma of
+// blssigcheck.IBLSSignatureCheckerNonSignerStakesAndSignature
+func SignatureToBinding[T BN254G1Point](sig *bls.Signature) (T, error) {
+	if sig == nil || sig.G1Point == nil {
+		return T{}, fmt.Errorf("signature: %w", bls.ErrNilPoint)
+	}
+	return G1PointToBinding[T](sig.G1Point)
+}
+
+// SignatureFromBinding converts the BN254G1Point struct of a binding to a signature
+func SignatureFromBinding[T BN254G1Point](p T) (*bls.Signature, error) {
+	g1Point, err := G1PointFromBinding(p)
+	if err != nil {
+		return nil, err
+	}
+	return &bls.Signature{G1Point: g1Point}, nil
+}
+
+// PubkeyRegistrationParamsToBinding converts params to the IBLSApkRegistryPubkeyRegistrationParams struct T of a
+// binding, e.g. PubkeyRegistrationParamsToBinding[regcoord.IBLSApkRegistryPubkeyRegistrationParams](params)
+func PubkeyRegistrationParamsToBinding[T PubkeyRegistrationParams[G1, G2], G1 BN254G1Point, G2 BN254G2Point](
+	params *bls.PubkeyRegistrationParams,
+) (T, error) {
+	if params == nil {
+		return T{}, fmt.Errorf("pubkey registration params: %w", bls.ErrNilPoint)
+	}
+	signature, err := SignatureToBinding[G1](params.PubkeyRegistrationSignature)
+	if err != nil {
+		return T{}, fmt.Errorf("pubkey registration signature: %w", err)
+	}
+	pubkeyG1, err := G1PointToBinding[G1](params.PubkeyG1)
+	if err != nil {
+		return T{}, fmt.Errorf("G1 public key: %w", err)
+	}
+	pubkeyG2, err := G2PointToBinding[G2](params.PubkeyG2)
+	if err != nil {
+		return T{}, fmt.Errorf("G2 public key: %w", err)
+	}
+	return T(struct {
+		PubkeyRegistrationSignature G1
+		PubkeyG1                    G1
+		PubkeyG2                    G2
+	}{signature, pubkeyG1, pubkeyG2}), nil
+}
+
+// PubkeyRegistrationParamsFromBinding converts the IBLSApkRegistryPubkeyRegistrationParams struct of a binding to
+// pubkey registration params
+func PubkeyRegistrationParamsFromBinding[T PubkeyRegistrationParams[G1, G2], G1 BN254G1Point, G2 BN254G2Point](
+	params T,
+) (*bls.PubkeyRegistrationParams, error) {
+	p := struct {
+		PubkeyRegistrationSignature G1
+		PubkeyG1                    G1
+		PubkeyG2                    G2
+	}(params)
+	signature, err := SignatureFromBinding(p.PubkeyRegistrationSignature)
+	if err != nil {
+		return nil, fmt.Errorf("pubkey registration signature: %w", err)
+	}
+	pubkeyG1, err := G1PointFromBinding(p.PubkeyG1)
+	if err != nil {
+		return nil, fmt.Errorf("G1 public key: %w", err)
+	}
+	pubkeyG2, err := G2PointFromBinding(p.PubkeyG2)
+	if err != nil {
+		return nil, fmt.Errorf("G2 public key: %w", err)
+	}
+	return &bls.PubkeyRegistrationParams{
+		PubkeyRegistrationSignature: signature,
+		PubkeyG1:                    pubkeyG1,
+		PubkeyG2:                    pubkeyG2,
+	}, nil
+}
+
+func toBN254G1Point(p *bn254.G1Affine) bn254G1Point {
+	return bn254G1Point{
+		X: p.X.BigInt(new(big.Int)),
+		Y: p.Y.BigInt(new(big.Int)),
+	}
+}
+
+func toBN254G2Point(p *bn254.G2Affine) bn254G2Point {
+	return bn254G2Point{
+		X: [2]*big.Int{p.X.A1.BigInt(new(big.Int)), p.X.A0.BigInt(new(big.Int))},
+		Y: [2]*big.Int{p.Y.A1.BigInt(new(big.Int)), p.Y.A0.BigInt(new(big.Int))},
+	}
+}
+
+// isFpElement returns whether x is the canonical integer of an element of the base field
+func isFpElement(x *big.Int) bool {
+	return x != nil && x.Sign() >= 0 && x.Cmp(fp.Modulus()) < 0
+}
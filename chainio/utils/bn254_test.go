@@ -0,0 +1,207 @@
+package utils_test
+
+import (
+	"math/big"
+	"testing"
+
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	apkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
+	blssigcheck "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IBLSSignatureChecker"
+	avssm "github.com/Layr-Labs/eigensdk-go/contracts/bindings/MockAvsServiceManager"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	regcoordParams = regcoord.IBLSApkRegistryPubkeyRegistrationParams
+	apkregParams   = apkreg.IBLSApkRegistryPubkeyRegistrationParams
+)
+
+// testG1RoundTrip checks that p converted to the binding T and back is p, and that the binding is the one of
+// ConvertToBN254G1Point
+func testG1RoundTrip[T chainioutils.BN254G1Point](t *testing.T, p *bls.G1Point) {
+	binding, err := chainioutils.G1PointToBinding[T](p)
+	require.NoError(t, err)
+	assert.Equal(t, chainioutils.ConvertToBN254G1Point(p), regcoord.BN254G1Point(binding))
+	point, err := chainioutils.G1PointFromBinding(binding)
+	require.NoError(t, err)
+	assert.True(t, p.Equal(point.G1Affine))
+
+	sig, err := chainioutils.SignatureFromBinding(binding)
+	require.NoError(t, err)
+	sigBinding, err := chainioutils.SignatureToBinding[T](sig)
+	require.NoError(t, err)
+	assert.Equal(t, binding, sigBinding)
+}
+
+// testG2RoundTrip checks that p converted to the binding T and back is p, and that the binding is the one of
+// ConvertToBN254G2Point
+func testG2RoundTrip[T chainioutils.BN254G2Point](t *testing.T, p *bls.G2Point) {
+	binding, err := chainioutils.G2PointToBinding[T](p)
+	require.NoError(t, err)
+	assert.Equal(t, chainioutils.ConvertToBN254G2Point(p), regcoord.BN254G2Point(binding))
+	point, err := chainioutils.G2PointFromBinding(binding)
+	require.NoError(t, err)
+	assert.True(t, p.Equal(point.G2Affine))
+}
+
+func TestBindingRoundTrip(t *testing.T) {
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	for i := 0; i < 50; i++ {
+		keyPair, err := bls.GenRandomBlsKeys()
+		require.NoError(t, err)
+		pubkeyG1, pubkeyG2, sig := keyPair.GetPubKeyG1(), keyPair.GetPubKeyG2(), keyPair.SignMessage(msg)
+
+		for _, p := range []*bls.G1Point{pubkeyG1, sig.G1Point} {
+			testG1RoundTrip[regcoord.BN254G1Point](t, p)
+			testG1RoundTrip[apkreg.BN254G1Point](t, p)
+			testG1RoundTrip[blssigcheck.BN254G1Point](t, p)
+			testG1RoundTrip[avssm.BN254G1Point](t, p)
+		}
+		testG2RoundTrip[regcoord.BN254G2Point](t, pubkeyG2)
+		testG2RoundTrip[apkreg.BN254G2Point](t, pubkeyG2)
+		testG2RoundTrip[blssigcheck.BN254G2Point](t, pubkeyG2)
+		testG2RoundTrip[avssm.BN254G2Point](t, pubkeyG2)
+
+		params := keyPair.MakePubkeyRegistrationParams(bls.MapToCurve(msg))
+		regcoordBinding, err := chainioutils.PubkeyRegistrationParamsToBinding[regcoordParams](params)
+		require.NoError(t, err)
+		assert.Equal(t, chainioutils.ConvertToPubkeyRegistrationParams(params), regcoordBinding)
+		apkregBinding, err := chainioutils.PubkeyRegistrationParamsToBinding[apkregParams](params)
+		require.NoError(t, err)
+		for _, binding := range []interface{}{regcoordBinding, apkregBinding} {
+			var roundTrip *bls.PubkeyRegistrationParams
+			switch binding := binding.(type) {
+			case regcoordParams:
+				roundTrip, err = chainioutils.PubkeyRegistrationParamsFromBinding(binding)
+			case apkregParams:
+				roundTrip, err = chainioutils.PubkeyRegistrationParamsFromBinding(binding)
+			}
+			require.NoError(t, err)
+			assert.True(t, params.PubkeyRegistrationSignature.Equal(roundTrip.PubkeyRegistrationSignature.G1Affine))
+			assert.True(t, params.PubkeyG1.Equal(roundTrip.PubkeyG1.G1Affine))
+			assert.True(t, params.PubkeyG2.Equal(roundTrip.PubkeyG2.G2Affine))
+		}
+	}
+
+	// the point at infinity is (0, 0) for the contracts
+	infinityG1, err := chainioutils.G1PointToBinding[regcoord.BN254G1Point](bls.NewZeroG1Point())
+	require.NoError(t, err)
+	assert.Zero(t, infinityG1.X.Sign())
+	assert.Zero(t, infinityG1.Y.Sign())
+	testG1RoundTrip[regcoord.BN254G1Point](t, bls.NewZeroG1Point())
+	testG2RoundTrip[regcoord.BN254G2Point](t, bls.NewZeroG2Point())
+}
+
+// g2PointNotInSubgroup returns a point of the curve of G2 which isn't in its subgroup
+func g2PointNotInSubgroup(t *testing.T) *bls.G2Point {
+	// y² = x³ + 3/(9+u)
+	var b, three, nonResidue bn254.E2
+	three.A0.SetUint64(3)
+	nonResidue.A0.SetUint64(9)
+	nonResidue.A1.SetOne()
+	b.Inverse(&nonResidue).Mul(&b, &three)
+	p := &bn254.G2Affine{}
+	for x := uint64(1); ; x++ {
+		p.X.A0.SetUint64(x)
+		var y bn254.E2
+		y.Square(&p.X).Mul(&y, &p.X).Add(&y, &b)
+		if y.Legendre() == 1 {
+			p.Y.Sqrt(&y)
+			break
+		}
+	}
+	require.True(t, p.IsOnCurve())
+	require.False(t, p.IsInSubGroup())
+	return &bls.G2Point{G2Affine: p}
+}
+
+func TestBindingInvalidPoints(t *testing.T) {
+	keyPair, err := bls.NewKeyPairFromString("0x1234")
+	require.NoError(t, err)
+	pubkeyG1, pubkeyG2 := keyPair.GetPubKeyG1(), keyPair.GetPubKeyG2()
+	validG1, err := chainioutils.G1PointToBinding[blssigcheck.BN254G1Point](pubkeyG1)
+	require.NoError(t, err)
+	validG2, err := chainioutils.G2PointToBinding[blssigcheck.BN254G2Point](pubkeyG2)
+	require.NoError(t, err)
+
+	t.Run("G1 points", func(t *testing.T) {
+		_, err := chainioutils.G1PointToBinding[blssigcheck.BN254G1Point](nil)
+		assert.ErrorIs(t, err, bls.ErrNilPoint)
+		_, err = chainioutils.SignatureToBinding[blssigcheck.BN254G1Point](nil)
+		assert.ErrorIs(t, err, bls.ErrNilPoint)
+		_, err = chainioutils.G1PointToBinding[blssigcheck.BN254G1Point](bls.NewG1Point(big.NewInt(1), big.NewInt(3)))
+		assert.ErrorIs(t, err, bls.ErrInvalidPoint)
+
+		var tests = map[string]blssigcheck.BN254G1Point{
+			"not on the curve": {X: big.NewInt(1), Y: big.NewInt(3)},
+			"nil coordinate":   {X: validG1.X},
+			"negative coordinate": {
+				X: validG1.X,
+				Y: new(big.Int).Neg(validG1.Y),
+			},
+			// the coordinates are equal modulo p to the ones of a point on the curve
+			"coordinate larger than the field": {
+				X: validG1.X,
+				Y: new(big.Int).Add(validG1.Y, fp.Modulus()),
+			},
+		}
+		for name, binding := range tests {
+			_, err := chainioutils.G1PointFromBinding(binding)
+			assert.ErrorIs(t, err, bls.ErrInvalidPoint, name)
+			_, err = chainioutils.SignatureFromBinding(binding)
+			assert.ErrorIs(t, err, bls.ErrInvalidPoint, name)
+		}
+	})
+
+	t.Run("G2 points", func(t *testing.T) {
+		_, err := chainioutils.G2PointToBinding[blssigcheck.BN254G2Point](nil)
+		assert.ErrorIs(t, err, bls.ErrNilPoint)
+		notInSubgroup := g2PointNotInSubgroup(t)
+		_, err = chainioutils.G2PointToBinding[blssigcheck.BN254G2Point](notInSubgroup)
+		assert.ErrorIs(t, err, bls.ErrInvalidPoint)
+
+		notInSubgroupBinding := blssigcheck.BN254G2Point(chainioutils.ConvertToBN254G2Point(notInSubgroup))
+		var tests = map[string]blssigcheck.BN254G2Point{
+			"not in the subgroup": notInSubgroupBinding,
+			// the order of the coordinates of the contracts is [A1, A0]
+			"coordinates in the [A0, A1] order": {
+				X: [2]*big.Int{validG2.X[1], validG2.X[0]},
+				Y: [2]*big.Int{validG2.Y[1], validG2.Y[0]},
+			},
+			"nil coordinate": {X: validG2.X, Y: [2]*big.Int{validG2.Y[0], nil}},
+			"coordinate larger than the field": {
+				X: validG2.X,
+				Y: [2]*big.Int{validG2.Y[0], new(big.Int).Add(validG2.Y[1], fp.Modulus())},
+			},
+		}
+		for name, binding := range tests {
+			_, err := chainioutils.G2PointFromBinding(binding)
+			assert.ErrorIs(t, err, bls.ErrInvalidPoint, name)
+		}
+	})
+
+	t.Run("pubkey registration params", func(t *testing.T) {
+		params := keyPair.MakePubkeyRegistrationParams(bls.MapToCurve([32]byte{1}))
+		_, err := chainioutils.PubkeyRegistrationParamsToBinding[regcoordParams](nil)
+		assert.ErrorIs(t, err, bls.ErrNilPoint)
+		_, err = chainioutils.PubkeyRegistrationParamsToBinding[regcoordParams](
+			&bls.PubkeyRegistrationParams{
+				PubkeyRegistrationSignature: params.PubkeyRegistrationSignature,
+				PubkeyG1:                    params.PubkeyG1,
+				PubkeyG2:                    g2PointNotInSubgroup(t),
+			},
+		)
+		assert.ErrorIs(t, err, bls.ErrInvalidPoint)
+
+		binding := chainioutils.ConvertToPubkeyRegistrationParams(params)
+		binding.PubkeyG1 = regcoord.BN254G1Point{X: big.NewInt(1), Y: big.NewInt(3)}
+		_, err = chainioutils.PubkeyRegistrationParamsFromBinding(binding)
+		assert.ErrorIs(t, err, bls.ErrInvalidPoint)
+	})
+}
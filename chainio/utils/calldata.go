@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+var ErrCalldataSuffixNotAllowed = errors.New("calldata suffix not allowed")
+
+// calldataSuffixSensitiveSelectors are functions whose decoding depends on the exact length of the calldata (e.g.
+// because they forward msg.data), so appending bytes to their calldata could change their behavior
+var calldataSuffixSensitiveSelectors = map[[4]byte]string{
+	selector("multicall(bytes[])"): "multicall(bytes[])",
+}
+
+func selector(signature string) [4]byte {
+	var sel [4]byte
+	copy(sel[:], crypto.Keccak256([]byte(signature))[:4])
+	return sel
+}
+
+// AppendCalldataSuffix returns a copy of tx with suffix appended to its calldata, of the same type as tx.
+// The gas limit of the returned tx is reset to 0, so that the TxManager estimates it again with the suffix included.
+// Plain transfers and calls to functions known to be sensitive to trailing calldata are refused.
+func AppendCalldataSuffix(tx *types.Transaction, suffix []byte) (*types.Transaction, error) {
+	if len(suffix) == 0 {
+		return tx, nil
+	}
+	data := tx.Data()
+	if len(data) < 4 {
+		return nil, fmt.Errorf("%w: tx has no function selector", ErrCalldataSuffixNotAllowed)
+	}
+	var sel [4]byte
+	copy(sel[:], data[:4])
+	if signature, ok := calldataSuffixSensitiveSelectors[sel]; ok {
+		return nil, fmt.Errorf("%w: %s decodes trailing calldata", ErrCalldataSuffixNotAllowed, signature)
+	}
+
+	dataWithSuffix := make([]byte, 0, len(data)+len(suffix))
+	dataWithSuffix = append(dataWithSuffix, data...)
+	dataWithSuffix = append(dataWithSuffix, suffix...)
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: tx.GasPrice(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     dataWithSuffix,
+		}), nil
+	case types.AccessListTxType:
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasPrice:   tx.GasPrice(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       dataWithSuffix,
+			AccessList: tx.AccessList(),
+		}), nil
+	case types.DynamicFeeTxType:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  tx.GasTipCap(),
+			GasFeeCap:  tx.GasFeeCap(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       dataWithSuffix,
+			AccessList: tx.AccessList(),
+		}), nil
+	case types.BlobTxType:
+		return types.NewTx(&types.BlobTx{
+			ChainID:    uint256.MustFromBig(tx.ChainId()),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  uint256.MustFromBig(tx.GasTipCap()),
+			GasFeeCap:  uint256.MustFromBig(tx.GasFeeCap()),
+			To:         *tx.To(),
+			Value:      uint256.MustFromBig(tx.Value()),
+			Data:       dataWithSuffix,
+			AccessList: tx.AccessList(),
+			BlobFeeCap: uint256.MustFromBig(tx.BlobGasFeeCap()),
+			BlobHashes: tx.BlobHashes(),
+			Sidecar:    tx.BlobTxSidecar(),
+		}), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported tx type %d", ErrCalldataSuffixNotAllowed, tx.Type())
+	}
+}
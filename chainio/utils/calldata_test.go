@@ -0,0 +1,94 @@
+package utils_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendCalldataSuffix(t *testing.T) {
+	regCoordAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	require.NoError(t, err)
+	ejector := common.HexToAddress("0x1234")
+	data, err := regCoordAbi.Pack("setEjector", ejector)
+	require.NoError(t, err)
+	to := common.HexToAddress("0x1")
+	tx := types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 21_000, Value: big.NewInt(0), Data: data})
+	suffix := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	t.Run("appends the suffix and resets the gas limit", func(t *testing.T) {
+		txWithSuffix, err := chainioutils.AppendCalldataSuffix(tx, suffix)
+		require.NoError(t, err)
+		require.Zero(t, txWithSuffix.Gas())
+		require.Equal(t, tx.To(), txWithSuffix.To())
+
+		gotData := txWithSuffix.Data()
+		require.Equal(t, suffix, gotData[len(gotData)-len(suffix):])
+		method, err := regCoordAbi.MethodById(gotData[:4])
+		require.NoError(t, err)
+		require.Equal(t, "setEjector", method.Name)
+		args, err := method.Inputs.Unpack(gotData[4 : len(gotData)-len(suffix)])
+		require.NoError(t, err)
+		require.Equal(t, ejector, args[0])
+	})
+
+	t.Run("preserves the type and fees of legacy and access list txs", func(t *testing.T) {
+		legacyTx := types.NewTransaction(1, to, big.NewInt(2), 21_000, big.NewInt(3), data)
+		txWithSuffix, err := chainioutils.AppendCalldataSuffix(legacyTx, suffix)
+		require.NoError(t, err)
+		require.Equal(t, uint8(types.LegacyTxType), txWithSuffix.Type())
+		require.Equal(t, legacyTx.Nonce(), txWithSuffix.Nonce())
+		require.Equal(t, legacyTx.GasPrice(), txWithSuffix.GasPrice())
+		require.Equal(t, legacyTx.Value(), txWithSuffix.Value())
+		require.Equal(t, append(append([]byte{}, data...), suffix...), txWithSuffix.Data())
+		require.Zero(t, txWithSuffix.Gas())
+
+		accessList := types.AccessList{{Address: to, StorageKeys: []common.Hash{{1}}}}
+		accessListTx := types.NewTx(&types.AccessListTx{
+			ChainID:    big.NewInt(1),
+			To:         &to,
+			GasPrice:   big.NewInt(3),
+			Data:       data,
+			AccessList: accessList,
+		})
+		txWithSuffix, err = chainioutils.AppendCalldataSuffix(accessListTx, suffix)
+		require.NoError(t, err)
+		require.Equal(t, uint8(types.AccessListTxType), txWithSuffix.Type())
+		require.Equal(t, accessListTx.GasPrice(), txWithSuffix.GasPrice())
+		require.Equal(t, accessList, txWithSuffix.AccessList())
+	})
+
+	t.Run("empty suffix leaves the tx untouched", func(t *testing.T) {
+		txWithSuffix, err := chainioutils.AppendCalldataSuffix(tx, nil)
+		require.NoError(t, err)
+		require.Equal(t, tx.Hash(), txWithSuffix.Hash())
+	})
+
+	t.Run("refuses txs without a selector", func(t *testing.T) {
+		transferTx := types.NewTx(&types.DynamicFeeTx{To: &to, Value: big.NewInt(1)})
+		_, err := chainioutils.AppendCalldataSuffix(transferTx, suffix)
+		require.ErrorIs(t, err, chainioutils.ErrCalldataSuffixNotAllowed)
+	})
+
+	t.Run("refuses trailing-calldata sensitive functions", func(t *testing.T) {
+		multicallAbi, err := abi.JSON(strings.NewReader(
+			`[{"type":"function","name":"multicall","inputs":[{"name":"data","type":"bytes[]"}],"outputs":[]}]`,
+		))
+		require.NoError(t, err)
+		multicallData, err := multicallAbi.Pack("multicall", [][]byte{})
+		require.NoError(t, err)
+		require.Equal(t, crypto.Keccak256([]byte("multicall(bytes[])"))[:4], multicallData[:4])
+
+		multicallTx := types.NewTx(&types.DynamicFeeTx{To: &to, Data: multicallData})
+		_, err = chainioutils.AppendCalldataSuffix(multicallTx, suffix)
+		require.ErrorIs(t, err, chainioutils.ErrCalldataSuffixNotAllowed)
+	})
+}
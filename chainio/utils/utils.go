@@ -1,8 +1,6 @@
 package utils
 
 import (
-	"math/big"
-
 	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
@@ -17,18 +15,26 @@ func ConvertBn254GethToGnark(input regcoord.BN254G1Point) *bn254.G1Affine {
 // BN254.sol is a library, so bindings for G1 Points and G2 Points are only generated
 // in every contract that imports that library. Thus the output here will need to be
 // type casted if G1Point is needed to interface with another contract (eg: BLSPublicKeyCompendium.sol)
+// Unlike G1PointToBinding, the point isn't checked to be on the curve.
 func ConvertToBN254G1Point(input *bls.G1Point) regcoord.BN254G1Point {
-	output := regcoord.BN254G1Point{
-		X: input.X.BigInt(big.NewInt(0)),
-		Y: input.Y.BigInt(big.NewInt(0)),
-	}
-	return output
+	return regcoord.BN254G1Point(toBN254G1Point(input.G1Affine))
 }
 
+// ConvertToBN254G2Point converts input to the BN254G2Point struct of the RegistryCoordinator binding. Unlike
+// G2PointToBinding, the point isn't checked to be in the subgroup.
 func ConvertToBN254G2Point(input *bls.G2Point) regcoord.BN254G2Point {
-	output := regcoord.BN254G2Point{
-		X: [2]*big.Int{input.X.A1.BigInt(big.NewInt(0)), input.X.A0.BigInt(big.NewInt(0))},
-		Y: [2]*big.Int{input.Y.A1.BigInt(big.NewInt(0)), input.Y.A0.BigInt(big.NewInt(0))},
+	return regcoord.BN254G2Point(toBN254G2Point(input.G2Affine))
+}
+
+// ConvertToPubkeyRegistrationParams converts the params made by bls.KeyPair.MakePubkeyRegistrationParams to the
+// binding struct of IBLSApkRegistry.PubkeyRegistrationParams. See PubkeyRegistrationParamsToBinding for the other
+// bindings, which also checks the points.
+func ConvertToPubkeyRegistrationParams(
+	params *bls.PubkeyRegistrationParams,
+) regcoord.IBLSApkRegistryPubkeyRegistrationParams {
+	return regcoord.IBLSApkRegistryPubkeyRegistrationParams{
+		PubkeyRegistrationSignature: ConvertToBN254G1Point(params.PubkeyRegistrationSignature.G1Point),
+		PubkeyG1:                    ConvertToBN254G1Point(params.PubkeyG1),
+		PubkeyG2:                    ConvertToBN254G2Point(params.PubkeyG2),
 	}
-	return output
 }
@@ -0,0 +1,98 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+var (
+	// ErrNoPoints is returned when aggregating an empty list of points
+	ErrNoPoints = errors.New("no points to aggregate")
+	// ErrNilPoint is returned when a point to aggregate or verify is nil
+	ErrNilPoint = errors.New("nil point")
+	// ErrInvalidPoint is returned when a point is not on the curve or not in its prime order subgroup
+	ErrInvalidPoint = errors.New("invalid point")
+	// ErrInfinityPubkey is returned when verifying against the aggregated public key at infinity, which every
+	// signature at infinity would verify against
+	ErrInfinityPubkey = errors.New("aggregated public key is the point at infinity")
+)
+
+// AggregateG1Points returns the sum of points, without modifying them. Points at infinity are the identity of the sum,
+// while nil points return ErrNilPoint.
+func AggregateG1Points(points []*G1Point) (*G1Point, error) {
+	if len(points) == 0 {
+		return nil, ErrNoPoints
+	}
+	sum := new(bn254.G1Jac)
+	for i, p := range points {
+		if p == nil || p.G1Affine == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilPoint, i)
+		}
+		sum.AddMixed(p.G1Affine)
+	}
+	return &G1Point{new(bn254.G1Affine).FromJacobian(sum)}, nil
+}
+
+// AggregateG2Points returns the sum of points, without modifying them. Points at infinity are the identity of the sum,
+// while nil points return ErrNilPoint.
+func AggregateG2Points(points []*G2Point) (*G2Point, error) {
+	if len(points) == 0 {
+		return nil, ErrNoPoints
+	}
+	sum := new(bn254.G2Jac)
+	for i, p := range points {
+		if p == nil || p.G2Affine == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilPoint, i)
+		}
+		sum.AddMixed(p.G2Affine)
+	}
+	return &G2Point{new(bn254.G2Affine).FromJacobian(sum)}, nil
+}
+
+// AggregateSignatures returns the aggregated signature of signatures, without modifying them. Nil signatures return
+// ErrNilPoint.
+func AggregateSignatures(signatures []*Signature) (*Signature, error) {
+	points := make([]*G1Point, len(signatures))
+	for i, s := range signatures {
+		if s == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilPoint, i)
+		}
+		points[i] = s.G1Point
+	}
+	sum, err := AggregateG1Points(points)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{sum}, nil
+}
+
+// VerifyAggregate verifies that sig is the aggregated signature of msg by the keys aggregated in aggPubkeyG2, with the
+// pairing check e(sig, -G2) * e(H(msg), aggPubkeyG2) == 1 of the BLSSignatureChecker contract, which hashes msg to G1
+// the same way. The contract also checks in the same pairing that the G1 aggregated public key matches aggPubkeyG2,
+// which is checked with G1Point.VerifyEquivalence.
+//
+// Unlike the contract, which accepts it, the aggregated public key at infinity returns ErrInfinityPubkey, and the
+// points are checked to be in the subgroups of the curve like the pairing precompile does, so that a signature
+// verified locally also verifies onchain. The contract only verifies the signatures of the default MapToCurve
+// HashFunction of opts.
+func VerifyAggregate(sig *Signature, aggPubkeyG2 *G2Point, msg [32]byte, opts ...SignOption) (bool, error) {
+	if sig == nil || sig.G1Point == nil || sig.G1Affine == nil {
+		return false, fmt.Errorf("signature: %w", ErrNilPoint)
+	}
+	if aggPubkeyG2 == nil || aggPubkeyG2.G2Affine == nil {
+		return false, fmt.Errorf("aggregated public key: %w", ErrNilPoint)
+	}
+	// G1 has a cofactor of 1, so its points on the curve are in the subgroup
+	if !sig.IsOnCurve() {
+		return false, fmt.Errorf("signature: %w", ErrInvalidPoint)
+	}
+	if !aggPubkeyG2.IsOnCurve() || !aggPubkeyG2.IsInSubGroup() {
+		return false, fmt.Errorf("aggregated public key: %w", ErrInvalidPoint)
+	}
+	if aggPubkeyG2.IsInfinity() {
+		return false, ErrInfinityPubkey
+	}
+	return sig.Verify(aggPubkeyG2, msg, opts...)
+}
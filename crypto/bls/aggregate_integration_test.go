@@ -0,0 +1,139 @@
+package bls_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	avssm "github.com/Layr-Labs/eigensdk-go/contracts/bindings/MockAvsServiceManager"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegrationVerifyAggregate cross-validates VerifyAggregate with the pairing check of the BLSSignatureChecker
+// contract deployed on anvil
+func TestIntegrationVerifyAggregate(t *testing.T) {
+	anvilC, err := testutils.StartAnvilContainer("contracts-deployed-anvil-state.json")
+	require.NoError(t, err)
+	anvilHttpEndpoint, err := anvilC.Endpoint(context.Background(), "http")
+	require.NoError(t, err)
+	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	ethHttpClient, err := ethclient.Dial(anvilHttpEndpoint)
+	require.NoError(t, err)
+	sigChecker, err := avssm.NewContractMockAvsServiceManager(contractAddrs.ServiceManager, ethHttpClient)
+	require.NoError(t, err)
+
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	var signatures []*bls.Signature
+	var pubkeysG1 []*bls.G1Point
+	var pubkeysG2 []*bls.G2Point
+	for i := 0; i < 4; i++ {
+		keyPair, err := bls.GenRandomBlsKeys()
+		require.NoError(t, err)
+		signatures = append(signatures, keyPair.SignMessage(msg))
+		pubkeysG1 = append(pubkeysG1, keyPair.GetPubKeyG1())
+		pubkeysG2 = append(pubkeysG2, keyPair.GetPubKeyG2())
+	}
+	aggregate := func(signatures []*bls.Signature, pubkeysG1 []*bls.G1Point, pubkeysG2 []*bls.G2Point) (
+		*bls.Signature, *bls.G1Point, *bls.G2Point,
+	) {
+		aggSig, err := bls.AggregateSignatures(signatures)
+		require.NoError(t, err)
+		aggPubkeyG1, err := bls.AggregateG1Points(pubkeysG1)
+		require.NoError(t, err)
+		aggPubkeyG2, err := bls.AggregateG2Points(pubkeysG2)
+		require.NoError(t, err)
+		return aggSig, aggPubkeyG1, aggPubkeyG2
+	}
+	verifyOnchain := func(
+		msg [32]byte,
+		aggSig *bls.Signature,
+		aggPubkeyG1 *bls.G1Point,
+		aggPubkeyG2 *bls.G2Point,
+	) bool {
+		res, err := sigChecker.TrySignatureAndApkVerification(
+			&bind.CallOpts{},
+			msg,
+			avssm.BN254G1Point(utils.ConvertToBN254G1Point(aggPubkeyG1)),
+			avssm.BN254G2Point(utils.ConvertToBN254G2Point(aggPubkeyG2)),
+			avssm.BN254G1Point(utils.ConvertToBN254G1Point(aggSig.G1Point)),
+		)
+		require.NoError(t, err)
+		return res.PairingSuccessful && res.SiganatureIsValid
+	}
+
+	tests := []struct {
+		name        string
+		msg         [32]byte
+		signatures  []*bls.Signature
+		pubkeysG1   []*bls.G1Point
+		pubkeysG2   []*bls.G2Point
+		expectValid bool
+	}{
+		{
+			name:        "all signers",
+			msg:         msg,
+			signatures:  signatures,
+			pubkeysG1:   pubkeysG1,
+			pubkeysG2:   pubkeysG2,
+			expectValid: true,
+		},
+		{
+			name:        "single signer",
+			msg:         msg,
+			signatures:  signatures[:1],
+			pubkeysG1:   pubkeysG1[:1],
+			pubkeysG2:   pubkeysG2[:1],
+			expectValid: true,
+		},
+		{
+			name:        "signer at infinity",
+			msg:         msg,
+			signatures:  append([]*bls.Signature{bls.NewZeroSignature()}, signatures[1:]...),
+			pubkeysG1:   append([]*bls.G1Point{bls.NewZeroG1Point()}, pubkeysG1[1:]...),
+			pubkeysG2:   append([]*bls.G2Point{bls.NewZeroG2Point()}, pubkeysG2[1:]...),
+			expectValid: true,
+		},
+		{
+			name:        "missing signature",
+			msg:         msg,
+			signatures:  signatures[1:],
+			pubkeysG1:   pubkeysG1,
+			pubkeysG2:   pubkeysG2,
+			expectValid: false,
+		},
+		{
+			name:        "other message",
+			msg:         crypto.Keccak256Hash([]byte("other task response")),
+			signatures:  signatures,
+			pubkeysG1:   pubkeysG1,
+			pubkeysG2:   pubkeysG2,
+			expectValid: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggSig, aggPubkeyG1, aggPubkeyG2 := aggregate(tt.signatures, tt.pubkeysG1, tt.pubkeysG2)
+			ok, err := bls.VerifyAggregate(aggSig, aggPubkeyG2, tt.msg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectValid, ok)
+			assert.Equal(t, tt.expectValid, verifyOnchain(tt.msg, aggSig, aggPubkeyG1, aggPubkeyG2))
+		})
+	}
+
+	t.Run("infinity aggregated pubkey is rejected locally only", func(t *testing.T) {
+		aggSig, aggPubkeyG1, aggPubkeyG2 := aggregate(
+			[]*bls.Signature{bls.NewZeroSignature()},
+			[]*bls.G1Point{bls.NewZeroG1Point()},
+			[]*bls.G2Point{bls.NewZeroG2Point()},
+		)
+		_, err := bls.VerifyAggregate(aggSig, aggPubkeyG2, msg)
+		assert.ErrorIs(t, err, bls.ErrInfinityPubkey)
+		assert.True(t, verifyOnchain(msg, aggSig, aggPubkeyG1, aggPubkeyG2))
+	})
+}
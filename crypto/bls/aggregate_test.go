@@ -0,0 +1,109 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAggregate(t *testing.T) {
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	var signatures []*Signature
+	var pubkeysG1 []*G1Point
+	var pubkeysG2 []*G2Point
+	for i := 0; i < 3; i++ {
+		keyPair, err := GenRandomBlsKeys()
+		require.NoError(t, err)
+		signatures = append(signatures, keyPair.SignMessage(msg))
+		pubkeysG1 = append(pubkeysG1, keyPair.GetPubKeyG1())
+		pubkeysG2 = append(pubkeysG2, keyPair.GetPubKeyG2())
+	}
+
+	t.Run("aggregated signature verifies against the aggregated pubkey", func(t *testing.T) {
+		firstSignature := *signatures[0].G1Affine
+		aggSig, err := AggregateSignatures(signatures)
+		require.NoError(t, err)
+		aggPubkeyG1, err := AggregateG1Points(pubkeysG1)
+		require.NoError(t, err)
+		aggPubkeyG2, err := AggregateG2Points(pubkeysG2)
+		require.NoError(t, err)
+		// the aggregated points are not modified
+		assert.Equal(t, firstSignature, *signatures[0].G1Affine)
+
+		ok, err := VerifyAggregate(aggSig, aggPubkeyG2, msg)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = aggPubkeyG1.VerifyEquivalence(aggPubkeyG2)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// a missing signature doesn't verify
+		partialSig, err := AggregateSignatures(signatures[1:])
+		require.NoError(t, err)
+		ok, err = VerifyAggregate(partialSig, aggPubkeyG2, msg)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		// nor does another message
+		ok, err = VerifyAggregate(aggSig, aggPubkeyG2, crypto.Keccak256Hash([]byte("other")))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("points at infinity are the identity", func(t *testing.T) {
+		aggSig, err := AggregateSignatures([]*Signature{NewZeroSignature(), signatures[0], NewZeroSignature()})
+		require.NoError(t, err)
+		aggPubkeyG2, err := AggregateG2Points([]*G2Point{NewZeroG2Point(), pubkeysG2[0]})
+		require.NoError(t, err)
+		ok, err := VerifyAggregate(aggSig, aggPubkeyG2, msg)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		zeroSig, err := AggregateSignatures([]*Signature{NewZeroSignature(), NewZeroSignature()})
+		require.NoError(t, err)
+		assert.True(t, zeroSig.IsInfinity())
+		zeroPubkey, err := AggregateG2Points([]*G2Point{NewZeroG2Point()})
+		require.NoError(t, err)
+		_, err = VerifyAggregate(zeroSig, zeroPubkey, msg)
+		assert.ErrorIs(t, err, ErrInfinityPubkey)
+	})
+
+	t.Run("nil and invalid points", func(t *testing.T) {
+		_, err := AggregateSignatures([]*Signature{signatures[0], nil})
+		assert.ErrorIs(t, err, ErrNilPoint)
+		_, err = AggregateSignatures([]*Signature{{G1Point: &G1Point{}}})
+		assert.ErrorIs(t, err, ErrNilPoint)
+		_, err = AggregateG1Points([]*G1Point{nil, pubkeysG1[0]})
+		assert.ErrorIs(t, err, ErrNilPoint)
+		_, err = AggregateG2Points([]*G2Point{pubkeysG2[0], {}})
+		assert.ErrorIs(t, err, ErrNilPoint)
+		_, err = AggregateG2Points(nil)
+		assert.ErrorIs(t, err, ErrNoPoints)
+
+		_, err = VerifyAggregate(nil, pubkeysG2[0], msg)
+		assert.ErrorIs(t, err, ErrNilPoint)
+		_, err = VerifyAggregate(signatures[0], nil, msg)
+		assert.ErrorIs(t, err, ErrNilPoint)
+		notOnCurve := NewG1Point(big.NewInt(1), big.NewInt(3))
+		_, err = VerifyAggregate(&Signature{notOnCurve}, pubkeysG2[0], msg)
+		assert.ErrorIs(t, err, ErrInvalidPoint)
+	})
+}
+
+func BenchmarkVerifyAggregate(b *testing.B) {
+	sigs, pubkeys, msgs := newSignedBatch(b, 100, true)
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(b, err)
+	aggPubkey, err := AggregateG2Points(pubkeys)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ok, err := VerifyAggregate(aggSig, aggPubkey, msgs[0])
+		if err != nil || !ok {
+			b.Fatal("invalid signature")
+		}
+	}
+}
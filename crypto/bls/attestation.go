@@ -4,10 +4,10 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
 
 	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
 
@@ -64,7 +64,11 @@ func (p *G1Point) Sub(p2 *G1Point) *G1Point {
 
 // VerifyEquivalence verifies G1Point is equivalent the G2Point
 func (p *G1Point) VerifyEquivalence(p2 *G2Point) (bool, error) {
-	return bn254utils.CheckG1AndG2DiscreteLogEquality(p.G1Affine, p2.G2Affine)
+	// e(p, G2) * e(-G1, p2) == 1
+	return bn254.PairingCheck(
+		[]bn254.G1Affine{*p.G1Affine, *negG1Generator()},
+		[]bn254.G2Affine{*g2Generator(), *p2.G2Affine},
+	)
 }
 
 func (p *G1Point) Serialize() []byte {
@@ -132,15 +136,18 @@ func (s *Signature) Add(otherS *Signature) *Signature {
 	return s
 }
 
-// Verify a message against a public key
-func (s *Signature) Verify(pubkey *G2Point, message [32]byte) (bool, error) {
-	ok, err := bn254utils.VerifySig(s.G1Affine, pubkey.G2Affine, message)
+// Verify a message against a public key, hashing it to G1 with the HashFunction of opts
+func (s *Signature) Verify(pubkey *G2Point, message [32]byte, opts ...SignOption) (bool, error) {
+	ok, err := verifyHashedMessage(s.G1Affine, pubkey.G2Affine, hashFunction(opts)(message))
 	if err != nil {
 		return false, err
 	}
 	return ok, nil
 }
 
+// ErrKeyZeroized is returned when using the private key of a zeroized key pair
+var ErrKeyZeroized = errors.New("bls key pair is zeroized")
+
 type PrivateKey = fr.Element
 
 func NewPrivateKey(sk string) (*PrivateKey, error) {
@@ -154,13 +161,24 @@ func NewPrivateKey(sk string) (*PrivateKey, error) {
 type KeyPair struct {
 	PrivKey *PrivateKey
 	PubKey  *G1Point
+	// pubKeyG2 caches the G2 public key, computed when first used. It's nil for the key pairs which weren't created by
+	// NewKeyPair, whose G2 public key is computed at each call.
+	pubKeyG2 *pubKeyG2Cache
+}
+
+type pubKeyG2Cache struct {
+	once     sync.Once
+	pubKeyG2 bn254.G2Affine
 }
 
 func NewKeyPair(sk *PrivateKey) *KeyPair {
-	pk := bn254utils.MulByGeneratorG1(sk)
-	return &KeyPair{sk, &G1Point{pk}}
+	pk := new(bn254.G1Affine).ScalarMultiplication(g1Generator(), sk.BigInt(new(big.Int)))
+	return &KeyPair{PrivKey: sk, PubKey: &G1Point{pk}, pubKeyG2: &pubKeyG2Cache{}}
 }
 
+// NewKeyPairFromString returns the key pair of the private key sk, a decimal or 0x prefixed hex string. It's meant for
+// the tests, as sk is usually hard-coded or guessable: use GenRandomBlsKeys or NewKeyPairFromSeed with a secret
+// seed instead.
 func NewKeyPairFromString(sk string) (*KeyPair, error) {
 	ele, err := new(fr.Element).SetString(sk)
 	if err != nil {
@@ -169,6 +187,24 @@ func NewKeyPairFromString(sk string) (*KeyPair, error) {
 	return NewKeyPair(ele), nil
 }
 
+// keyGenDST is the domain separation tag of the hash of the seeds to the private keys of NewKeyPairFromSeed
+var keyGenDST = []byte("EIGENSDK_BLS_KEYGEN_V1_BN254_FR_XMD:SHA-256")
+
+// NewKeyPairFromSeed deterministically derives a key pair from seed, e.g. for the stable keys of the tests and
+// simulations. The private key is the hash of seed to the scalar field with the expand_message_xmd of RFC 9380 and a
+// domain separation tag of its own, so the seed is never used directly as the private key. It returns an error in the
+// negligible case the hash is zero.
+func NewKeyPairFromSeed(seed [32]byte) (*KeyPair, error) {
+	sk, err := fr.Hash(seed[:], keyGenDST, 1)
+	if err != nil {
+		return nil, err
+	}
+	if sk[0].IsZero() {
+		return nil, errors.New("seed derives a zero private key")
+	}
+	return NewKeyPair(&sk[0]), nil
+}
+
 func GenRandomBlsKeys() (*KeyPair, error) {
 
 	//Max random value is order of the curve
@@ -191,20 +227,40 @@ func (k *KeyPair) SaveToFile(path string, password string) error {
 	if err != nil {
 		return err
 	}
+	return writeKeyFile(path, data)
+}
 
+// writeKeyFile writes data to a temporary file with mode 0600 renamed over path, so that the key file is never
+// readable by other users, even when path already exists with a broader mode, and a crash never leaves a partial file
+func writeKeyFile(path string, data []byte) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0750); err != nil {
-		fmt.Println("Error creating directories:", err)
 		return err
 	}
-	err = os.WriteFile(path, data, 0600)
+	// os.CreateTemp creates the file with mode 0600
+	tmpFile, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
 	if err != nil {
 		return err
 	}
-	return nil
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
 }
 
 func (k *KeyPair) EncryptedString(path string, password string) ([]byte, error) {
+	if k.IsZeroized() {
+		return nil, ErrKeyZeroized
+	}
 	sk32Bytes := k.PrivKey.Bytes()
 	skBytes := make([]byte, 32)
 	for i := 0; i < 32; i++ {
@@ -232,11 +288,16 @@ func (k *KeyPair) EncryptedString(path string, password string) ([]byte, error)
 	return data, nil
 }
 
+// ReadPrivateKeyFromFile reads the private key of the keystore file at path, which is either a keystore saved by
+// SaveToFile or an EIP-2335 keystore
 func ReadPrivateKeyFromFile(path string, password string) (*KeyPair, error) {
 	keyStoreContents, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
 		return nil, err
 	}
+	if isEIP2335Keystore(keyStoreContents) {
+		return decryptEIP2335Keystore(keyStoreContents, password)
+	}
 
 	encryptedBLSStruct := &encryptedBLSKeyJSONV3{}
 	err = json.Unmarshal(keyStoreContents, encryptedBLSStruct)
@@ -263,11 +324,40 @@ func ReadPrivateKeyFromFile(path string, password string) (*KeyPair, error) {
 	return keyPair, nil
 }
 
+// Sign signs a message on G1 like SignMessage, returning ErrKeyZeroized once the key pair is zeroized
+func (k *KeyPair) Sign(message [32]byte, opts ...SignOption) (*Signature, error) {
+	if k.IsZeroized() {
+		return nil, ErrKeyZeroized
+	}
+	return k.SignMessage(message, opts...), nil
+}
+
+// Zeroize overwrites the private key with zeros, including for the other holders of the PrivKey pointer, after which
+// the key pair can't sign, e.g. Sign returns ErrKeyZeroized. The public keys are kept, except the G2 public key of the
+// key pairs which weren't created by NewKeyPair. It must not be called concurrently with the other methods of the key
+// pair.
+func (k *KeyPair) Zeroize() {
+	if k.IsZeroized() {
+		return
+	}
+	if k.pubKeyG2 != nil {
+		// cache the G2 public key while it can still be computed
+		k.GetPubKeyG2()
+	}
+	k.PrivKey.SetZero()
+}
+
+// IsZeroized returns whether the private key was zeroized, or is missing or zero
+func (k *KeyPair) IsZeroized() bool {
+	return k.PrivKey == nil || k.PrivKey.IsZero()
+}
+
 // This signs a message on G1, and so will require a G2Pubkey to verify
-func (k *KeyPair) SignMessage(message [32]byte) *Signature {
-	H := bn254utils.MapToCurve(message)
-	sig := new(bn254.G1Affine).ScalarMultiplication(H, k.PrivKey.BigInt(new(big.Int)))
-	return &Signature{&G1Point{sig}}
+// The message is hashed to G1 with the HashFunction of opts, which defaults to the MapToCurve of the contracts
+// Once the key pair is zeroized, the signature is the point at infinity, which doesn't verify; use Sign to get an
+// error instead.
+func (k *KeyPair) SignMessage(message [32]byte, opts ...SignOption) *Signature {
+	return k.SignHashedToCurveMessage(hashFunction(opts)(message))
 }
 
 // This signs a message on G1, and so will require a G2Pubkey to verify
@@ -276,8 +366,15 @@ func (k *KeyPair) SignHashedToCurveMessage(g1HashedMsg *bn254.G1Affine) *Signatu
 	return &Signature{&G1Point{sig}}
 }
 
+// GetPubKeyG2 returns the G2 public key, which is a copy that can be modified
 func (k *KeyPair) GetPubKeyG2() *G2Point {
-	return &G2Point{bn254utils.MulByGeneratorG2(k.PrivKey)}
+	if k.pubKeyG2 == nil {
+		return &G2Point{new(bn254.G2Affine).ScalarMultiplication(g2Generator(), k.PrivKey.BigInt(new(big.Int)))}
+	}
+	k.pubKeyG2.once.Do(func() {
+		k.pubKeyG2.pubKeyG2.ScalarMultiplication(g2Generator(), k.PrivKey.BigInt(new(big.Int)))
+	})
+	return &G2Point{new(bn254.G2Affine).Set(&k.pubKeyG2.pubKeyG2)}
 }
 
 func (k *KeyPair) GetPubKeyG1() *G1Point {
@@ -1,11 +1,15 @@
 package bls
 
 import (
+	"encoding/hex"
+	"math/bits"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBlsKeyCreation(t *testing.T) {
@@ -47,3 +51,134 @@ func TestBlsKeyCreation(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkVerify(b *testing.B) {
+	keyPair, err := GenRandomBlsKeys()
+	require.NoError(b, err)
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	sig := keyPair.SignMessage(msg)
+	pubkey := keyPair.GetPubKeyG2()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ok, err := sig.Verify(pubkey, msg)
+		if err != nil || !ok {
+			b.Fatal("invalid signature")
+		}
+	}
+}
+
+func BenchmarkGetPubKeyG2(b *testing.B) {
+	keyPair, err := GenRandomBlsKeys()
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keyPair.GetPubKeyG2()
+	}
+}
+
+func TestGetPubKeyG2(t *testing.T) {
+	keyPair, err := NewKeyPairFromString("0x1234")
+	require.NoError(t, err)
+	pubKeyG2 := keyPair.GetPubKeyG2()
+	ok, err := keyPair.GetPubKeyG1().VerifyEquivalence(pubKeyG2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// the cached public key isn't modified through the returned copies
+	pubKeyG2.Add(pubKeyG2)
+	assert.NotEqual(t, pubKeyG2, keyPair.GetPubKeyG2())
+
+	// key pairs which weren't created by NewKeyPair compute it at each call
+	literal := &KeyPair{PrivKey: keyPair.PrivKey, PubKey: keyPair.PubKey}
+	assert.Equal(t, keyPair.GetPubKeyG2(), literal.GetPubKeyG2())
+}
+
+func TestZeroize(t *testing.T) {
+	keyPair, err := GenRandomBlsKeys()
+	require.NoError(t, err)
+	privKey := keyPair.PrivKey
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	_, err = keyPair.Sign(msg)
+	require.NoError(t, err)
+
+	keyPair.Zeroize()
+	assert.True(t, keyPair.IsZeroized())
+	// the private key is overwritten in place
+	assert.True(t, privKey.IsZero())
+
+	_, err = keyPair.Sign(msg)
+	assert.ErrorIs(t, err, ErrKeyZeroized)
+	ok, err := keyPair.SignMessage(msg).Verify(keyPair.GetPubKeyG2(), msg)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	keyPath := filepath.Join(t.TempDir(), "test.bls.key.json")
+	assert.ErrorIs(t, keyPair.SaveToFile(keyPath, "test"), ErrKeyZeroized)
+	assert.ErrorIs(t, keyPair.SaveToEIP2335(keyPath, "test"), ErrKeyZeroized)
+	assert.NoFileExists(t, keyPath)
+}
+
+func TestSaveToFileMode(t *testing.T) {
+	keyPair, err := GenRandomBlsKeys()
+	require.NoError(t, err)
+	dir := filepath.Join(t.TempDir(), "keys")
+	keyPath := filepath.Join(dir, "test.bls.key.json")
+
+	require.NoError(t, keyPair.SaveToFile(keyPath, "test"))
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// an existing key file with a broader mode is replaced by a file with mode 0600
+	require.NoError(t, os.Chmod(keyPath, 0644))
+	require.NoError(t, keyPair.SaveToEIP2335(keyPath, "test"))
+	info, err = os.Stat(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	readKeyPair, err := ReadPrivateKeyFromFile(keyPath, "test")
+	require.NoError(t, err)
+	assert.Equal(t, keyPair.PrivKey, readKeyPair.PrivKey)
+
+	// no temporary file is left
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestNewKeyPairFromSeed(t *testing.T) {
+	seed := [32]byte{1}
+	keyPair, err := NewKeyPairFromSeed(seed)
+	require.NoError(t, err)
+	// the key pairs must not change across versions, as the tests and simulations rely on them
+	assert.Equal(t,
+		"1dcc3b851c084add36b9ac60925bb08bc1d1ac5a7340c0b3680134466c98b133"+
+			"236aa1fca174a04a1ec5665c115905dcf38af074de1949ec4349474a87ba7e02",
+		hex.EncodeToString(keyPair.GetPubKeyG1().Serialize()),
+	)
+	for i := 0; i < 3; i++ {
+		again, err := NewKeyPairFromSeed(seed)
+		require.NoError(t, err)
+		assert.True(t, keyPair.PrivKey.Equal(again.PrivKey))
+		assert.True(t, keyPair.GetPubKeyG1().Equal(again.GetPubKeyG1().G1Affine))
+	}
+	// the seed isn't used as the private key
+	assert.NotEqual(t, new(PrivateKey).SetBytes(seed[:]), keyPair.PrivKey)
+
+	privKey := keyPair.PrivKey.Bytes()
+	for bit := 0; bit < 256; bit++ {
+		flipped := seed
+		flipped[bit/8] ^= 1 << (bit % 8)
+		other, err := NewKeyPairFromSeed(flipped)
+		require.NoError(t, err)
+		require.False(t, keyPair.GetPubKeyG1().Equal(other.GetPubKeyG1().G1Affine))
+		// the private keys of seeds differing in one bit are unrelated, differing in about half of their bits
+		otherPrivKey := other.PrivKey.Bytes()
+		distance := 0
+		for i := range privKey {
+			distance += bits.OnesCount8(privKey[i] ^ otherPrivKey[i])
+		}
+		assert.InDelta(t, 128, distance, 64, "bit %d", bit)
+	}
+}
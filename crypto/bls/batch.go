@@ -0,0 +1,189 @@
+package bls
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// BatchVerifyError is returned by the batch verifications when some signatures are invalid
+type BatchVerifyError struct {
+	// InvalidIndices are the indices of the invalid signatures, in increasing order
+	InvalidIndices []int
+}
+
+func (e *BatchVerifyError) Error() string {
+	return fmt.Sprintf("invalid signatures at indices %v", e.InvalidIndices)
+}
+
+// BatchVerify verifies that each sigs[i] is the signature of msgs[i] by pubkeys[i], with a random linear combination
+// of the signatures checked in a single multi-pairing. The signatures of the same message are checked with 2 pairings,
+// see BatchVerifySameMessage.
+//
+// When the batch doesn't verify, the signatures are verified one by one, and false is returned along with a
+// *BatchVerifyError holding the indices of the invalid signatures. The messages are hashed to G1 with the HashFunction
+// of opts.
+func BatchVerify(sigs []*Signature, pubkeys []*G2Point, msgs [][32]byte, opts ...SignOption) (bool, error) {
+	if len(msgs) != len(sigs) {
+		return false, fmt.Errorf("%d messages for %d signatures", len(msgs), len(sigs))
+	}
+	if err := checkBatch(sigs, pubkeys); err != nil {
+		return false, err
+	}
+	sameMessage := true
+	for _, msg := range msgs {
+		sameMessage = sameMessage && msg == msgs[0]
+	}
+	if sameMessage {
+		return batchVerifySameMessage(sigs, pubkeys, msgs[0], opts)
+	}
+	hash := hashFunction(opts)
+
+	scalars, err := randomScalars(len(sigs))
+	if err != nil {
+		return false, err
+	}
+	// e(-sum(r_i * sig_i), G2) * prod(e(r_i * H(msg_i), pubkey_i)) == 1
+	g1Points := make([]bn254.G1Affine, 0, len(sigs)+1)
+	g2Points := make([]bn254.G2Affine, 0, len(sigs)+1)
+	for i := range sigs {
+		msgPoint := new(bn254.G1Affine).ScalarMultiplication(
+			hash(msgs[i]),
+			scalars[i].BigInt(new(big.Int)),
+		)
+		g1Points = append(g1Points, *msgPoint)
+		g2Points = append(g2Points, *pubkeys[i].G2Affine)
+	}
+	aggSig, err := combineG1(sigs, scalars)
+	if err != nil {
+		return false, err
+	}
+	g1Points = append(g1Points, *aggSig)
+	g2Points = append(g2Points, *negG2Generator())
+
+	ok, err := bn254.PairingCheck(g1Points, g2Points)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return true, nil
+	}
+	return verifyEach(sigs, pubkeys, msgs, opts)
+}
+
+// BatchVerifySameMessage verifies that each sigs[i] is the signature of msg by pubkeys[i], with a random linear
+// combination of the signatures and of the public keys checked with 2 pairings.
+//
+// When the batch doesn't verify, the signatures are verified one by one, and false is returned along with a
+// *BatchVerifyError holding the indices of the invalid signatures. The message is hashed to G1 with the HashFunction
+// of opts.
+func BatchVerifySameMessage(sigs []*Signature, pubkeys []*G2Point, msg [32]byte, opts ...SignOption) (bool, error) {
+	if err := checkBatch(sigs, pubkeys); err != nil {
+		return false, err
+	}
+	return batchVerifySameMessage(sigs, pubkeys, msg, opts)
+}
+
+func batchVerifySameMessage(sigs []*Signature, pubkeys []*G2Point, msg [32]byte, opts []SignOption) (bool, error) {
+	scalars, err := randomScalars(len(sigs))
+	if err != nil {
+		return false, err
+	}
+	// e(sum(r_i * sig_i), -G2) * e(H(msg), sum(r_i * pubkey_i)) == 1
+	aggSig, err := combineG1(sigs, scalars)
+	if err != nil {
+		return false, err
+	}
+	g2Points := make([]bn254.G2Affine, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		g2Points[i] = *pubkey.G2Affine
+	}
+	aggPubkey, err := new(bn254.G2Affine).MultiExp(g2Points, scalars, ecc.MultiExpConfig{})
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{*aggSig, *hashFunction(opts)(msg)},
+		[]bn254.G2Affine{*negG2Generator(), *aggPubkey},
+	)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return true, nil
+	}
+	msgs := make([][32]byte, len(sigs))
+	for i := range msgs {
+		msgs[i] = msg
+	}
+	return verifyEach(sigs, pubkeys, msgs, opts)
+}
+
+// checkBatch checks that the batch is not empty and that its points are not nil and in their subgroups, which the
+// random linear combination relies on
+func checkBatch(sigs []*Signature, pubkeys []*G2Point) error {
+	if len(sigs) == 0 {
+		return ErrNoPoints
+	}
+	if len(pubkeys) != len(sigs) {
+		return fmt.Errorf("%d public keys for %d signatures", len(pubkeys), len(sigs))
+	}
+	for i := range sigs {
+		if sigs[i] == nil || sigs[i].G1Point == nil || sigs[i].G1Affine == nil {
+			return fmt.Errorf("signature at index %d: %w", i, ErrNilPoint)
+		}
+		if pubkeys[i] == nil || pubkeys[i].G2Affine == nil {
+			return fmt.Errorf("public key at index %d: %w", i, ErrNilPoint)
+		}
+		if !sigs[i].IsOnCurve() {
+			return fmt.Errorf("signature at index %d: %w", i, ErrInvalidPoint)
+		}
+		if !pubkeys[i].IsOnCurve() || !pubkeys[i].IsInSubGroup() {
+			return fmt.Errorf("public key at index %d: %w", i, ErrInvalidPoint)
+		}
+	}
+	return nil
+}
+
+// randomScalars returns n cryptographically random scalars
+func randomScalars(n int) ([]fr.Element, error) {
+	scalars := make([]fr.Element, n)
+	for i := range scalars {
+		if _, err := scalars[i].SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	return scalars, nil
+}
+
+// combineG1 returns sum(scalars[i] * sigs[i])
+func combineG1(sigs []*Signature, scalars []fr.Element) (*bn254.G1Affine, error) {
+	points := make([]bn254.G1Affine, len(sigs))
+	for i, sig := range sigs {
+		points[i] = *sig.G1Affine
+	}
+	return new(bn254.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{})
+}
+
+// verifyEach verifies the signatures one by one to find the invalid ones
+func verifyEach(sigs []*Signature, pubkeys []*G2Point, msgs [][32]byte, opts []SignOption) (bool, error) {
+	var invalidIndices []int
+	for i := range sigs {
+		ok, err := sigs[i].Verify(pubkeys[i], msgs[i], opts...)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			invalidIndices = append(invalidIndices, i)
+		}
+	}
+	if len(invalidIndices) > 0 {
+		return false, &BatchVerifyError{InvalidIndices: invalidIndices}
+	}
+	// the batch only fails with a negligible probability when every signature is valid
+	return true, nil
+}
@@ -0,0 +1,107 @@
+package bls
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSignedBatch returns n signatures of msgs by random keys, with the same message if sameMessage is set
+func newSignedBatch(t testing.TB, n int, sameMessage bool) ([]*Signature, []*G2Point, [][32]byte) {
+	sigs := make([]*Signature, n)
+	pubkeys := make([]*G2Point, n)
+	msgs := make([][32]byte, n)
+	for i := 0; i < n; i++ {
+		keyPair, err := GenRandomBlsKeys()
+		require.NoError(t, err)
+		msgs[i] = crypto.Keccak256Hash([]byte("task response"))
+		if !sameMessage {
+			msgs[i] = crypto.Keccak256Hash([]byte(fmt.Sprintf("task response %d", i)))
+		}
+		sigs[i] = keyPair.SignMessage(msgs[i])
+		pubkeys[i] = keyPair.GetPubKeyG2()
+	}
+	return sigs, pubkeys, msgs
+}
+
+func TestBatchVerify(t *testing.T) {
+	for _, sameMessage := range []bool{false, true} {
+		t.Run(fmt.Sprintf("same message %t", sameMessage), func(t *testing.T) {
+			sigs, pubkeys, msgs := newSignedBatch(t, 50, sameMessage)
+
+			ok, err := BatchVerify(sigs, pubkeys, msgs)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			if sameMessage {
+				ok, err = BatchVerifySameMessage(sigs, pubkeys, msgs[0])
+				require.NoError(t, err)
+				assert.True(t, ok)
+			}
+
+			// a single corrupted signature fails the batch and is identified
+			corruptedSigs := append([]*Signature{}, sigs...)
+			// a valid signature of another key
+			corruptedSigs[37] = sigs[36]
+			ok, err = BatchVerify(corruptedSigs, pubkeys, msgs)
+			assert.False(t, ok)
+			var batchErr *BatchVerifyError
+			require.ErrorAs(t, err, &batchErr)
+			assert.Equal(t, []int{37}, batchErr.InvalidIndices)
+		})
+	}
+
+	t.Run("swapped signatures of different messages", func(t *testing.T) {
+		sigs, pubkeys, msgs := newSignedBatch(t, 4, false)
+		sigs[1], sigs[2] = sigs[2], sigs[1]
+		ok, err := BatchVerify(sigs, pubkeys, msgs)
+		assert.False(t, ok)
+		var batchErr *BatchVerifyError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, []int{1, 2}, batchErr.InvalidIndices)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		sigs, pubkeys, msgs := newSignedBatch(t, 2, false)
+		_, err := BatchVerify(nil, nil, nil)
+		assert.ErrorIs(t, err, ErrNoPoints)
+		_, err = BatchVerify(sigs, pubkeys[:1], msgs)
+		assert.Error(t, err)
+		_, err = BatchVerify(sigs, pubkeys, msgs[:1])
+		assert.Error(t, err)
+		_, err = BatchVerify([]*Signature{sigs[0], nil}, pubkeys, msgs)
+		assert.ErrorIs(t, err, ErrNilPoint)
+		_, err = BatchVerifySameMessage(sigs, []*G2Point{pubkeys[0], {}}, msgs[0])
+		assert.ErrorIs(t, err, ErrNilPoint)
+		_, err = BatchVerify([]*Signature{sigs[0], {NewG1Point(big.NewInt(1), big.NewInt(3))}}, pubkeys, msgs)
+		assert.ErrorIs(t, err, ErrInvalidPoint)
+	})
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	const n = 100
+	for _, sameMessage := range []bool{false, true} {
+		sigs, pubkeys, msgs := newSignedBatch(b, n, sameMessage)
+		b.Run(fmt.Sprintf("individual/same message %t", sameMessage), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range sigs {
+					ok, err := sigs[j].Verify(pubkeys[j], msgs[j])
+					if err != nil || !ok {
+						b.Fatal("invalid signature")
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("batch/same message %t", sameMessage), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ok, err := BatchVerify(sigs, pubkeys, msgs)
+				if err != nil || !ok {
+					b.Fatal("invalid batch")
+				}
+			}
+		})
+	}
+}
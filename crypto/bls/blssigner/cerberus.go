@@ -0,0 +1,273 @@
+package blssigner
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultCerberusTimeout is the timeout of the requests made to the signer unless set in the CerberusConfig
+const DefaultCerberusTimeout = 5 * time.Second
+
+// CerberusConfig configures the connection to a remote signer implementing the Cerberus API
+// Reference: https://github.com/Layr-Labs/cerberus-api
+type CerberusConfig struct {
+	// URL is the host:port of the gRPC endpoint of the signer
+	URL string
+	// PublicKeyHex selects the key of the signer, it's the hex of the compressed G1 public key
+	PublicKeyHex string
+	// Password unlocks the key, when the signer needs it
+	Password string
+	// APIKey authenticates the requests, as the bearer token of their authorization metadata
+	APIKey string
+	// EnableTLS connects to the signer with TLS, verifying its certificate with the CA certificates of
+	// TLSCACertPath, or with the system ones unless set
+	EnableTLS     bool
+	TLSCACertPath string
+	// Timeout is the timeout of each request, DefaultCerberusTimeout unless set
+	Timeout time.Duration
+}
+
+// CerberusSigner is a Signer whose key is held by a remote signer implementing the Cerberus API
+type CerberusSigner struct {
+	conn        *grpc.ClientConn
+	config      CerberusConfig
+	publicKeyG1 *bls.G1Point
+	publicKeyG2 *bls.G2Point
+}
+
+var _ Signer = (*CerberusSigner)(nil)
+
+// NewCerberusSigner connects to the signer of config, fetching the G2 public key of the selected key. It returns
+// ErrPublicKeyMismatch if the public keys of the signer don't match, or ErrSignerUnavailable if it can't be reached.
+func NewCerberusSigner(ctx context.Context, config CerberusConfig) (*CerberusSigner, error) {
+	if config.URL == "" {
+		return nil, errors.New("cerberus signer URL is required")
+	}
+	publicKeyG1, err := decodeG1PointHex(config.PublicKeyHex)
+	if err != nil {
+		return nil, utils.WrapError("invalid cerberus signer public key", err)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultCerberusTimeout
+	}
+
+	creds := insecure.NewCredentials()
+	if config.EnableTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		if config.TLSCACertPath != "" {
+			creds, err = credentials.NewClientTLSFromFile(config.TLSCACertPath, "")
+			if err != nil {
+				return nil, utils.WrapError("failed to load cerberus signer CA certificates", err)
+			}
+		}
+	}
+	conn, err := grpc.DialContext(
+		ctx,
+		config.URL,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(cerberusCodec{})),
+	)
+	if err != nil {
+		return nil, utils.WrapError("failed to dial cerberus signer", err)
+	}
+
+	s := &CerberusSigner{conn: conn, config: config, publicKeyG1: publicKeyG1}
+	s.publicKeyG2, err = s.getPublicKeyG2(ctx)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Sign signs msg with the SignGeneric method of the signer, checking that the signature verifies against the public
+// key
+func (s *CerberusSigner) Sign(ctx context.Context, msg [32]byte) (*bls.Signature, error) {
+	signature, err := s.sign(ctx, cerberusSignGenericMethod, msg[:])
+	if err != nil {
+		return nil, err
+	}
+	ok, err := signature.Verify(s.publicKeyG2, msg)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: signature doesn't verify against public key %s", ErrPublicKeyMismatch,
+			s.config.PublicKeyHex)
+	}
+	return signature, nil
+}
+
+// SignG1 signs hashedMsg with the SignG1 method of the signer, checking that the signature verifies against the public
+// key
+func (s *CerberusSigner) SignG1(ctx context.Context, hashedMsg *bls.G1Point) (*bls.Signature, error) {
+	if hashedMsg == nil || hashedMsg.G1Affine == nil {
+		return nil, fmt.Errorf("hashed message: %w", bls.ErrNilPoint)
+	}
+	signature, err := s.sign(ctx, cerberusSignG1Method, hashedMsg.Serialize())
+	if err != nil {
+		return nil, err
+	}
+	// e(hashedMsg, publicKeyG2) * e(-signature, G2) == 1
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{*hashedMsg.G1Affine, *new(bn254.G1Affine).Neg(signature.G1Affine)},
+		[]bn254.G2Affine{*s.publicKeyG2.G2Affine, *bn254utils.GetG2Generator()},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: signature doesn't verify against public key %s", ErrPublicKeyMismatch,
+			s.config.PublicKeyHex)
+	}
+	return signature, nil
+}
+
+func (s *CerberusSigner) GetPublicKeyG1() *bls.G1Point {
+	return s.publicKeyG1
+}
+
+func (s *CerberusSigner) GetPublicKeyG2() *bls.G2Point {
+	return s.publicKeyG2
+}
+
+func (s *CerberusSigner) GetOperatorId() types.OperatorId {
+	return types.OperatorIdFromG1Pubkey(s.publicKeyG1)
+}
+
+// Close closes the connection to the signer
+func (s *CerberusSigner) Close() error {
+	return s.conn.Close()
+}
+
+// getPublicKeyG2 returns the G2 public key of the selected key, checking that it matches its G1 public key
+func (s *CerberusSigner) getPublicKeyG2(ctx context.Context) (*bls.G2Point, error) {
+	var res cerberusGetKeyMetadataResponse
+	err := s.invoke(ctx, cerberusGetKeyMetadataMethod, &cerberusGetKeyMetadataRequest{
+		PublicKeyG1: s.config.PublicKeyHex,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyG1, err := decodeG1PointHex(res.PublicKeyG1)
+	if err != nil {
+		return nil, utils.WrapError("invalid G1 public key returned by cerberus signer", err)
+	}
+	if !publicKeyG1.Equal(s.publicKeyG1.G1Affine) {
+		return nil, fmt.Errorf("%w: signer returned the G1 public key %s for %s", ErrPublicKeyMismatch,
+			res.PublicKeyG1, s.config.PublicKeyHex)
+	}
+	publicKeyG2, err := decodeG2PointHex(res.PublicKeyG2)
+	if err != nil {
+		return nil, utils.WrapError("invalid G2 public key returned by cerberus signer", err)
+	}
+	ok, err := s.publicKeyG1.VerifyEquivalence(publicKeyG2)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: G2 public key of the signer doesn't match the G1 public key %s",
+			ErrPublicKeyMismatch, s.config.PublicKeyHex)
+	}
+	return publicKeyG2, nil
+}
+
+// sign signs data with method, SignGeneric or SignG1, returning the G1 signature
+func (s *CerberusSigner) sign(ctx context.Context, method string, data []byte) (*bls.Signature, error) {
+	var res cerberusSignResponse
+	err := s.invoke(ctx, method, &cerberusSignRequest{
+		PublicKeyG1: s.config.PublicKeyHex,
+		Password:    s.config.Password,
+		Data:        data,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decodeG1Point(res.Signature)
+	if err != nil {
+		return nil, utils.WrapError("invalid signature returned by cerberus signer", err)
+	}
+	return &bls.Signature{G1Point: signature}, nil
+}
+
+// invoke calls method with the timeout and the API key of the config, wrapping the errors of the signer
+func (s *CerberusSigner) invoke(ctx context.Context, method string, req cerberusMessage, res cerberusMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+	if s.config.APIKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.config.APIKey)
+	}
+	err := s.conn.Invoke(ctx, method, req, res)
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s: %w", ErrSignerUnavailable, s.config.URL, err)
+	case codes.NotFound:
+		return fmt.Errorf("%w: signer has no key %s: %w", ErrPublicKeyMismatch, s.config.PublicKeyHex, err)
+	default:
+		return utils.WrapError(fmt.Sprintf("cerberus signer %s failed", method), err)
+	}
+}
+
+// decodeG1PointHex decodes the hex of a compressed or serialized G1 point
+func decodeG1PointHex(s string) (*bls.G1Point, error) {
+	b, err := hex.DecodeString(utils.Trim0x(s))
+	if err != nil {
+		return nil, err
+	}
+	return decodeG1Point(b)
+}
+
+// decodeG1Point decodes a compressed or serialized G1 point, checking that it's on the curve
+func decodeG1Point(b []byte) (*bls.G1Point, error) {
+	if len(b) == bn254.SizeOfG1AffineCompressed {
+		return bls.NewG1PointFromBytes(b)
+	}
+	if len(b) != bn254.SizeOfG1AffineUncompressed {
+		return nil, fmt.Errorf("%w: G1 point must be %d or %d bytes, got %d", bls.ErrInvalidPoint,
+			bn254.SizeOfG1AffineCompressed, bn254.SizeOfG1AffineUncompressed, len(b))
+	}
+	p := new(bls.G1Point).Deserialize(b)
+	if !p.IsOnCurve() {
+		return nil, fmt.Errorf("%w: G1 point is not on the curve", bls.ErrInvalidPoint)
+	}
+	return p, nil
+}
+
+// decodeG2PointHex decodes the hex of a compressed or serialized G2 point, checking that it's in the subgroup
+func decodeG2PointHex(s string) (*bls.G2Point, error) {
+	b, err := hex.DecodeString(utils.Trim0x(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == bn254.SizeOfG2AffineCompressed {
+		return bls.NewG2PointFromBytes(b)
+	}
+	if len(b) != bn254.SizeOfG2AffineUncompressed {
+		return nil, fmt.Errorf("%w: G2 point must be %d or %d bytes, got %d", bls.ErrInvalidPoint,
+			bn254.SizeOfG2AffineCompressed, bn254.SizeOfG2AffineUncompressed, len(b))
+	}
+	p := new(bls.G2Point).Deserialize(b)
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return nil, fmt.Errorf("%w: G2 point is not in the subgroup", bls.ErrInvalidPoint)
+	}
+	return p, nil
+}
@@ -0,0 +1,185 @@
+package blssigner
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The messages of the Cerberus API used by the signer, i.e. the SignGeneric and SignG1 methods of the signer.v1.Signer
+// service and the GetKeyMetadata method of the keymanager.v1.KeyManager service, which are encoded with protowire
+// instead of depending on the generated code of the API. Their field numbers are the ones of the protos of the API,
+// whose excerpts in testdata/cerberus are encoded with the protobuf runtime by TestCerberusMessagesProtoEncoding to
+// check the encoding of the messages.
+
+const (
+	cerberusSignGenericMethod    = "/signer.v1.Signer/SignGeneric"
+	cerberusSignG1Method         = "/signer.v1.Signer/SignG1"
+	cerberusGetKeyMetadataMethod = "/keymanager.v1.KeyManager/GetKeyMetadata"
+)
+
+// cerberusMessage is a message of the Cerberus API encoded with protowire
+type cerberusMessage interface {
+	marshal() []byte
+	unmarshal(b []byte) error
+}
+
+// cerberusSignRequest is the request of SignGeneric and SignG1, whose data is the 32 byte message and the 64 byte
+// serialized G1 point respectively:
+//
+//	message SignGenericRequest {
+//	  string public_key_g1 = 1;
+//	  bytes data = 2;
+//	  string password = 3;
+//	}
+type cerberusSignRequest struct {
+	PublicKeyG1 string
+	Data        []byte
+	Password    string
+}
+
+func (m *cerberusSignRequest) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.PublicKeyG1)
+	b = appendBytes(b, 2, m.Data)
+	b = appendString(b, 3, m.Password)
+	return b
+}
+
+func (m *cerberusSignRequest) unmarshal(b []byte) error {
+	return unmarshalFields(b, func(num protowire.Number, v []byte) {
+		switch num {
+		case 1:
+			m.PublicKeyG1 = string(v)
+		case 2:
+			m.Data = v
+		case 3:
+			m.Password = string(v)
+		}
+	})
+}
+
+// cerberusSignResponse is the response of SignGeneric and SignG1
+type cerberusSignResponse struct {
+	Signature []byte
+}
+
+func (m *cerberusSignResponse) marshal() []byte {
+	return appendBytes(nil, 1, m.Signature)
+}
+
+func (m *cerberusSignResponse) unmarshal(b []byte) error {
+	return unmarshalFields(b, func(num protowire.Number, v []byte) {
+		if num == 1 {
+			m.Signature = v
+		}
+	})
+}
+
+type cerberusGetKeyMetadataRequest struct {
+	PublicKeyG1 string
+}
+
+func (m *cerberusGetKeyMetadataRequest) marshal() []byte {
+	return appendString(nil, 1, m.PublicKeyG1)
+}
+
+func (m *cerberusGetKeyMetadataRequest) unmarshal(b []byte) error {
+	return unmarshalFields(b, func(num protowire.Number, v []byte) {
+		if num == 1 {
+			m.PublicKeyG1 = string(v)
+		}
+	})
+}
+
+// cerberusGetKeyMetadataResponse holds the hex encoded public keys of the key, its created_at and updated_at
+// timestamps (fields 3 and 4) are skipped
+type cerberusGetKeyMetadataResponse struct {
+	PublicKeyG1 string
+	PublicKeyG2 string
+}
+
+func (m *cerberusGetKeyMetadataResponse) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.PublicKeyG1)
+	b = appendString(b, 2, m.PublicKeyG2)
+	return b
+}
+
+func (m *cerberusGetKeyMetadataResponse) unmarshal(b []byte) error {
+	return unmarshalFields(b, func(num protowire.Number, v []byte) {
+		switch num {
+		case 1:
+			m.PublicKeyG1 = string(v)
+		case 2:
+			m.PublicKeyG2 = string(v)
+		}
+	})
+}
+
+// cerberusCodec is the grpc codec of the cerberusMessages, which are encoded like the proto codec
+type cerberusCodec struct{}
+
+func (cerberusCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(cerberusMessage)
+	if !ok {
+		return nil, fmt.Errorf("cerberus codec can't marshal %T", v)
+	}
+	return m.marshal(), nil
+}
+
+func (cerberusCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(cerberusMessage)
+	if !ok {
+		return fmt.Errorf("cerberus codec can't unmarshal %T", v)
+	}
+	return m.unmarshal(data)
+}
+
+func (cerberusCodec) Name() string {
+	return "proto"
+}
+
+// appendString appends the string field num, omitted when empty like proto3
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// appendBytes appends the bytes field num, omitted when empty like proto3
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// unmarshalFields calls setField with the length delimited fields of b, skipping the other fields
+func unmarshalFields(b []byte, setField func(num protowire.Number, v []byte)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		setField(num, append([]byte(nil), v...))
+		b = b[n:]
+	}
+	return nil
+}
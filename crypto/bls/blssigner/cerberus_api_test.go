@@ -0,0 +1,302 @@
+package blssigner
+
+import (
+	"encoding/hex"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var (
+	protoCommentRegexp = regexp.MustCompile(`//.*`)
+	protoEmptyRegexp   = regexp.MustCompile(`\{\s*\}`)
+	protoPackageRegexp = regexp.MustCompile(`package\s+([\w.]+)\s*;`)
+	protoServiceRegexp = regexp.MustCompile(`service\s+(\w+)\s*\{([^}]*)\}`)
+	protoRpcRegexp     = regexp.MustCompile(`^rpc\s+(\w+)\s*\(\s*\w+\s*\)\s*returns\s*\(\s*\w+\s*\)$`)
+	protoMessageRegexp = regexp.MustCompile(`message\s+(\w+)\s*\{([^}]*)\}`)
+	protoFieldRegexp   = regexp.MustCompile(`^(\w+)\s+(\w+)\s*=\s*(\d+)$`)
+	protoScalarTypes   = map[string]descriptorpb.FieldDescriptorProto_Type{
+		"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		"bytes":  descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+		"int64":  descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	}
+)
+
+// cerberusProto is a proto file of the Cerberus API in testdata/cerberus, which are excerpts of the protos of
+// github.com/Layr-Labs/cerberus-api
+type cerberusProto struct {
+	file protoreflect.FileDescriptor
+	// methods are the full grpc method names of the services of the file
+	methods []string
+}
+
+// loadCerberusProto reads a proto3 file of testdata/cerberus, made of services and of messages with scalar fields,
+// into a descriptor whose messages are encoded by the protobuf runtime instead of the cerberusCodec
+func loadCerberusProto(t *testing.T, name string) cerberusProto {
+	content, err := os.ReadFile("testdata/cerberus/" + name)
+	require.NoError(t, err)
+	src := protoCommentRegexp.ReplaceAllString(string(content), "")
+	// the empty options of the rpcs end their statements
+	src = protoEmptyRegexp.ReplaceAllString(src, ";")
+
+	pkg := protoPackageRegexp.FindStringSubmatch(src)
+	require.NotNil(t, pkg, "package of %s", name)
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(name),
+		Package: proto.String(pkg[1]),
+		Syntax:  proto.String("proto3"),
+	}
+
+	var methods []string
+	for _, service := range protoServiceRegexp.FindAllStringSubmatch(src, -1) {
+		for _, statement := range protoStatements(service[2]) {
+			rpc := protoRpcRegexp.FindStringSubmatch(statement)
+			require.NotNil(t, rpc, "unsupported statement %q in service %s", statement, service[1])
+			methods = append(methods, "/"+pkg[1]+"."+service[1]+"/"+rpc[1])
+		}
+	}
+
+	for _, message := range protoMessageRegexp.FindAllStringSubmatch(src, -1) {
+		md := &descriptorpb.DescriptorProto{Name: proto.String(message[1])}
+		for _, statement := range protoStatements(message[2]) {
+			field := protoFieldRegexp.FindStringSubmatch(statement)
+			require.NotNil(t, field, "unsupported statement %q in message %s", statement, message[1])
+			typ, ok := protoScalarTypes[field[1]]
+			require.True(t, ok, "unsupported type %s in message %s", field[1], message[1])
+			num, err := strconv.ParseInt(field[3], 10, 32)
+			require.NoError(t, err)
+			md.Field = append(md.Field, &descriptorpb.FieldDescriptorProto{
+				Name:     proto.String(field[2]),
+				Number:   proto.Int32(int32(num)),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     typ.Enum(),
+				JsonName: proto.String(field[2]),
+			})
+		}
+		fd.MessageType = append(fd.MessageType, md)
+	}
+
+	file, err := protodesc.NewFile(fd, nil)
+	require.NoError(t, err)
+	return cerberusProto{file: file, methods: methods}
+}
+
+// protoStatements splits the body of a service or a message into its statements
+func protoStatements(body string) []string {
+	var statements []string
+	for _, statement := range strings.Split(body, ";") {
+		statement = strings.Join(strings.Fields(statement), " ")
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+// marshal encodes the message of the file with the given field values with the protobuf runtime
+func (p cerberusProto) marshal(t *testing.T, message string, fields map[string]interface{}) []byte {
+	md := p.file.Messages().ByName(protoreflect.Name(message))
+	require.NotNil(t, md, "message %s", message)
+	m := dynamicpb.NewMessage(md)
+	for name, v := range fields {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		require.NotNil(t, fd, "field %s of message %s", name, message)
+		m.Set(fd, protoreflect.ValueOf(v))
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	require.NoError(t, err)
+	return b
+}
+
+// TestCerberusMessagesProtoEncoding checks the cerberusCodec against the encodings of the protobuf runtime of the
+// messages of the Cerberus protos in testdata/cerberus
+func TestCerberusMessagesProtoEncoding(t *testing.T) {
+	signerProto := loadCerberusProto(t, "signer.proto")
+	keyManagerProto := loadCerberusProto(t, "keymanager.proto")
+
+	methods := append(signerProto.methods, keyManagerProto.methods...)
+	assert.Contains(t, methods, cerberusSignGenericMethod)
+	assert.Contains(t, methods, cerberusSignG1Method)
+	assert.Contains(t, methods, cerberusGetKeyMetadataMethod)
+
+	publicKeyG1 := "0x" + strings.Repeat("ab", 32)
+	publicKeyG2 := "0x" + strings.Repeat("cd", 64)
+	data := []byte(strings.Repeat("\x01\x02", 32))
+	tests := []struct {
+		name     string
+		proto    cerberusProto
+		protoMsg string
+		fields   map[string]interface{}
+		message  cerberusMessage
+		// decoded is the message unmarshaled from the encoding of the protobuf runtime, message unless set
+		decoded cerberusMessage
+	}{
+		{
+			name:     "sign generic request",
+			proto:    signerProto,
+			protoMsg: "SignGenericRequest",
+			fields: map[string]interface{}{
+				"public_key_g1": publicKeyG1,
+				"data":          data[:32],
+				"password":      "pw",
+			},
+			message: &cerberusSignRequest{PublicKeyG1: publicKeyG1, Data: data[:32], Password: "pw"},
+		},
+		{
+			name:     "sign generic request without password",
+			proto:    signerProto,
+			protoMsg: "SignGenericRequest",
+			fields: map[string]interface{}{
+				"public_key_g1": publicKeyG1,
+				"data":          data[:32],
+			},
+			message: &cerberusSignRequest{PublicKeyG1: publicKeyG1, Data: data[:32]},
+		},
+		{
+			name:     "sign generic response",
+			proto:    signerProto,
+			protoMsg: "SignGenericResponse",
+			fields:   map[string]interface{}{"signature": data},
+			message:  &cerberusSignResponse{Signature: data},
+		},
+		{
+			name:     "sign G1 request",
+			proto:    signerProto,
+			protoMsg: "SignG1Request",
+			fields: map[string]interface{}{
+				"public_key_g1": publicKeyG1,
+				"data":          data,
+				"password":      "pw",
+			},
+			message: &cerberusSignRequest{PublicKeyG1: publicKeyG1, Data: data, Password: "pw"},
+		},
+		{
+			name:     "sign G1 response",
+			proto:    signerProto,
+			protoMsg: "SignG1Response",
+			fields:   map[string]interface{}{"signature": data},
+			message:  &cerberusSignResponse{Signature: data},
+		},
+		{
+			name:     "get key metadata request",
+			proto:    keyManagerProto,
+			protoMsg: "GetKeyMetadataRequest",
+			fields:   map[string]interface{}{"public_key_g1": publicKeyG1},
+			message:  &cerberusGetKeyMetadataRequest{PublicKeyG1: publicKeyG1},
+		},
+		{
+			name:     "get key metadata response",
+			proto:    keyManagerProto,
+			protoMsg: "GetKeyMetadataResponse",
+			fields: map[string]interface{}{
+				"public_key_g1": publicKeyG1,
+				"public_key_g2": publicKeyG2,
+			},
+			message: &cerberusGetKeyMetadataResponse{PublicKeyG1: publicKeyG1, PublicKeyG2: publicKeyG2},
+		},
+		{
+			name:     "get key metadata response with timestamps",
+			proto:    keyManagerProto,
+			protoMsg: "GetKeyMetadataResponse",
+			fields: map[string]interface{}{
+				"public_key_g1": publicKeyG1,
+				"public_key_g2": publicKeyG2,
+				"created_at":    int64(1700000000),
+				"updated_at":    int64(1700000001),
+			},
+			decoded: &cerberusGetKeyMetadataResponse{PublicKeyG1: publicKeyG1, PublicKeyG2: publicKeyG2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden := tt.proto.marshal(t, tt.protoMsg, tt.fields)
+			if tt.message != nil {
+				assert.Equal(t, golden, tt.message.marshal())
+			}
+
+			decoded := tt.decoded
+			if decoded == nil {
+				decoded = tt.message
+			}
+			var got cerberusMessage
+			switch decoded.(type) {
+			case *cerberusSignRequest:
+				got = &cerberusSignRequest{}
+			case *cerberusSignResponse:
+				got = &cerberusSignResponse{}
+			case *cerberusGetKeyMetadataRequest:
+				got = &cerberusGetKeyMetadataRequest{}
+			case *cerberusGetKeyMetadataResponse:
+				got = &cerberusGetKeyMetadataResponse{}
+			}
+			require.NoError(t, got.unmarshal(golden))
+			assert.Equal(t, decoded, got)
+		})
+	}
+}
+
+// TestCerberusMessagesWireFormat pins the field numbers of the Cerberus messages to golden encodings of the messages
+// of the signer.v1 and keymanager.v1 protos of the API, since the fake server shares the codec of the signer
+func TestCerberusMessagesWireFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		message cerberusMessage
+		// golden is the proto encoding of message, field by field
+		golden string
+	}{
+		{
+			// SignGenericRequest{public_key_g1: "ab", data: 0x0102, password: "pw"}
+			name:    "sign request",
+			message: &cerberusSignRequest{PublicKeyG1: "ab", Data: []byte{1, 2}, Password: "pw"},
+			golden:  "0a026162" + "12020102" + "1a027077",
+		},
+		{
+			// SignGenericResponse{signature: 0x0304}
+			name:    "sign response",
+			message: &cerberusSignResponse{Signature: []byte{3, 4}},
+			golden:  "0a020304",
+		},
+		{
+			// GetKeyMetadataRequest{public_key_g1: "ab"}
+			name:    "get key metadata request",
+			message: &cerberusGetKeyMetadataRequest{PublicKeyG1: "ab"},
+			golden:  "0a026162",
+		},
+		{
+			// GetKeyMetadataResponse{public_key_g1: "ab", public_key_g2: "cd"}
+			name:    "get key metadata response",
+			message: &cerberusGetKeyMetadataResponse{PublicKeyG1: "ab", PublicKeyG2: "cd"},
+			golden:  "0a026162" + "12026364",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden, err := hex.DecodeString(tt.golden)
+			require.NoError(t, err)
+			assert.Equal(t, golden, tt.message.marshal())
+		})
+	}
+
+	// the timestamps of GetKeyMetadataResponse, created_at = 3 and updated_at = 4, are skipped
+	golden, err := hex.DecodeString("0a026162" + "12026364" + "1801" + "2002")
+	require.NoError(t, err)
+	var res cerberusGetKeyMetadataResponse
+	require.NoError(t, res.unmarshal(golden))
+	assert.Equal(t, cerberusGetKeyMetadataResponse{PublicKeyG1: "ab", PublicKeyG2: "cd"}, res)
+
+	golden, err = hex.DecodeString("0a026162" + "12020102" + "1a027077")
+	require.NoError(t, err)
+	var req cerberusSignRequest
+	require.NoError(t, req.unmarshal(golden))
+	assert.Equal(t, cerberusSignRequest{PublicKeyG1: "ab", Data: []byte{1, 2}, Password: "pw"}, req)
+}
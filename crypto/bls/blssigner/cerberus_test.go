@@ -0,0 +1,334 @@
+package blssigner
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+	"github.com/Layr-Labs/eigensdk-go/types"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testPassword = "password"
+	testAPIKey   = "api-key"
+)
+
+// fakeCerberus serves the Cerberus API, signing with signingKeyPair and returning the G2 public key of g2KeyPair
+type fakeCerberus struct {
+	t              *testing.T
+	keyPair        *bls.KeyPair
+	signingKeyPair *bls.KeyPair
+	g2KeyPair      *bls.KeyPair
+	// publicKeyG1 is the G1 public key returned by GetKeyMetadata, the one of keyPair unless set
+	publicKeyG1 string
+}
+
+func newFakeCerberus(t *testing.T, keyPair *bls.KeyPair) *fakeCerberus {
+	return &fakeCerberus{t: t, keyPair: keyPair, signingKeyPair: keyPair, g2KeyPair: keyPair}
+}
+
+// serve starts the gRPC server of s, returning its address
+func (s *fakeCerberus) serve() (string, *grpc.Server) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(s.t, err)
+	server := grpc.NewServer(grpc.ForceServerCodec(cerberusCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "signer.v1.Signer",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "SignGeneric", Handler: s.handler(s.signGeneric)},
+			{MethodName: "SignG1", Handler: s.handler(s.signG1)},
+		},
+	}, s)
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "keymanager.v1.KeyManager",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "GetKeyMetadata", Handler: s.getKeyMetadata},
+		},
+	}, s)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	s.t.Cleanup(server.Stop)
+	return lis.Addr().String(), server
+}
+
+func (s *fakeCerberus) publicKeyHex() string {
+	publicKey := s.keyPair.GetPubKeyG1().Bytes()
+	return hex.EncodeToString(publicKey[:])
+}
+
+func (s *fakeCerberus) handler(
+	sign func(req *cerberusSignRequest) (*bls.Signature, error),
+) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(
+		_ interface{},
+		ctx context.Context,
+		dec func(interface{}) error,
+		_ grpc.UnaryServerInterceptor,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("authorization")) != 1 || md.Get("authorization")[0] != "Bearer "+testAPIKey {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+		var req cerberusSignRequest
+		if err := dec(&req); err != nil {
+			return nil, err
+		}
+		if req.PublicKeyG1 != s.publicKeyHex() {
+			return nil, status.Error(codes.NotFound, "key not found")
+		}
+		if req.Password != testPassword {
+			return nil, status.Error(codes.PermissionDenied, "invalid password")
+		}
+		signature, err := sign(&req)
+		if err != nil {
+			return nil, err
+		}
+		return &cerberusSignResponse{Signature: signature.Serialize()}, nil
+	}
+}
+
+func (s *fakeCerberus) signGeneric(req *cerberusSignRequest) (*bls.Signature, error) {
+	if len(req.Data) != 32 {
+		return nil, status.Error(codes.InvalidArgument, "data must be 32 bytes")
+	}
+	return s.signingKeyPair.SignMessage([32]byte(req.Data)), nil
+}
+
+func (s *fakeCerberus) signG1(req *cerberusSignRequest) (*bls.Signature, error) {
+	if len(req.Data) != 64 {
+		return nil, status.Error(codes.InvalidArgument, "data must be 64 bytes")
+	}
+	return s.signingKeyPair.SignHashedToCurveMessage(bn254utils.DeserializeG1(req.Data)), nil
+}
+
+func (s *fakeCerberus) getKeyMetadata(
+	_ interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	_ grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req cerberusGetKeyMetadataRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if req.PublicKeyG1 != s.publicKeyHex() {
+		return nil, status.Error(codes.NotFound, "key not found")
+	}
+	publicKeyG1 := s.publicKeyG1
+	if publicKeyG1 == "" {
+		publicKeyG1 = s.publicKeyHex()
+	}
+	publicKeyG2 := s.g2KeyPair.GetPubKeyG2().Bytes()
+	return &cerberusGetKeyMetadataResponse{
+		PublicKeyG1: publicKeyG1,
+		PublicKeyG2: hex.EncodeToString(publicKeyG2[:]),
+	}, nil
+}
+
+func newTestKeyPair(t *testing.T, sk string) *bls.KeyPair {
+	keyPair, err := bls.NewKeyPairFromString(sk)
+	require.NoError(t, err)
+	return keyPair
+}
+
+func TestCerberusSigner(t *testing.T) {
+	keyPair := newTestKeyPair(t, "0x1234")
+	fake := newFakeCerberus(t, keyPair)
+	url, _ := fake.serve()
+
+	signer, err := NewCerberusSigner(context.Background(), CerberusConfig{
+		URL:          url,
+		PublicKeyHex: fake.publicKeyHex(),
+		Password:     testPassword,
+		APIKey:       testAPIKey,
+	})
+	require.NoError(t, err)
+	defer signer.Close()
+
+	assert.Equal(t, keyPair.GetPubKeyG1().G1Affine, signer.GetPublicKeyG1().G1Affine)
+	assert.Equal(t, keyPair.GetPubKeyG2().G2Affine, signer.GetPublicKeyG2().G2Affine)
+	assert.Equal(t, types.OperatorIdFromKeyPair(keyPair), signer.GetOperatorId())
+
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	signature, err := signer.Sign(context.Background(), msg)
+	require.NoError(t, err)
+	assert.Equal(t, keyPair.SignMessage(msg).G1Affine, signature.G1Affine)
+
+	hashedMsg := bn254utils.MapToCurve([32]byte{1})
+	signature, err = signer.SignG1(context.Background(), &bls.G1Point{G1Affine: hashedMsg})
+	require.NoError(t, err)
+	assert.Equal(t, keyPair.SignHashedToCurveMessage(hashedMsg).G1Affine, signature.G1Affine)
+}
+
+func TestCerberusSignerUnavailable(t *testing.T) {
+	keyPair := newTestKeyPair(t, "0x1234")
+	fake := newFakeCerberus(t, keyPair)
+
+	// nothing listens on the port of a closed listener
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, lis.Close())
+	_, err = NewCerberusSigner(context.Background(), CerberusConfig{
+		URL:          lis.Addr().String(),
+		PublicKeyHex: fake.publicKeyHex(),
+		Password:     testPassword,
+		APIKey:       testAPIKey,
+		Timeout:      time.Second,
+	})
+	require.ErrorIs(t, err, ErrSignerUnavailable)
+
+	// the signer goes down after connecting
+	url, server := fake.serve()
+	signer, err := NewCerberusSigner(context.Background(), CerberusConfig{
+		URL:          url,
+		PublicKeyHex: fake.publicKeyHex(),
+		Password:     testPassword,
+		APIKey:       testAPIKey,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+	defer signer.Close()
+	server.Stop()
+	_, err = signer.Sign(context.Background(), [32]byte{1})
+	require.ErrorIs(t, err, ErrSignerUnavailable)
+}
+
+func TestCerberusSignerPublicKeyMismatch(t *testing.T) {
+	keyPair := newTestKeyPair(t, "0x1234")
+	otherKeyPair := newTestKeyPair(t, "0x5678")
+	otherPublicKey := otherKeyPair.GetPubKeyG1().Bytes()
+
+	tests := map[string]struct {
+		setup func(fake *fakeCerberus)
+		// publicKeyHex is the selected key, the one of keyPair unless set
+		publicKeyHex string
+		// signErr is set when the construction succeeds but the signatures don't verify
+		signErr bool
+	}{
+		"unknown key": {
+			publicKeyHex: hex.EncodeToString(otherPublicKey[:]),
+		},
+		"G1 public key of another key": {
+			setup: func(fake *fakeCerberus) {
+				fake.publicKeyG1 = hex.EncodeToString(otherPublicKey[:])
+			},
+		},
+		"G2 public key of another key": {
+			setup: func(fake *fakeCerberus) {
+				fake.g2KeyPair = otherKeyPair
+			},
+		},
+		"signed by another key": {
+			setup: func(fake *fakeCerberus) {
+				fake.signingKeyPair = otherKeyPair
+			},
+			signErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeCerberus(t, keyPair)
+			if tt.setup != nil {
+				tt.setup(fake)
+			}
+			url, _ := fake.serve()
+			publicKeyHex := tt.publicKeyHex
+			if publicKeyHex == "" {
+				publicKeyHex = fake.publicKeyHex()
+			}
+
+			signer, err := NewCerberusSigner(context.Background(), CerberusConfig{
+				URL:          url,
+				PublicKeyHex: publicKeyHex,
+				Password:     testPassword,
+				APIKey:       testAPIKey,
+			})
+			if !tt.signErr {
+				require.ErrorIs(t, err, ErrPublicKeyMismatch)
+				return
+			}
+			require.NoError(t, err)
+			defer signer.Close()
+
+			_, err = signer.Sign(context.Background(), [32]byte{1})
+			require.ErrorIs(t, err, ErrPublicKeyMismatch)
+			_, err = signer.SignG1(context.Background(), &bls.G1Point{G1Affine: bn254utils.MapToCurve([32]byte{1})})
+			require.ErrorIs(t, err, ErrPublicKeyMismatch)
+		})
+	}
+}
+
+func TestCerberusSignerErrors(t *testing.T) {
+	keyPair := newTestKeyPair(t, "0x1234")
+	fake := newFakeCerberus(t, keyPair)
+	url, _ := fake.serve()
+
+	_, err := NewCerberusSigner(context.Background(), CerberusConfig{URL: url, PublicKeyHex: "0x1234"})
+	require.ErrorIs(t, err, bls.ErrInvalidPoint)
+
+	signer, err := NewCerberusSigner(context.Background(), CerberusConfig{
+		URL:          url,
+		PublicKeyHex: fake.publicKeyHex(),
+		Password:     "wrong",
+		APIKey:       testAPIKey,
+	})
+	require.NoError(t, err)
+	defer signer.Close()
+	_, err = signer.Sign(context.Background(), [32]byte{1})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.NotErrorIs(t, err, ErrSignerUnavailable)
+	require.NotErrorIs(t, err, ErrPublicKeyMismatch)
+
+	signer, err = NewCerberusSigner(context.Background(), CerberusConfig{
+		URL:          url,
+		PublicKeyHex: fake.publicKeyHex(),
+		Password:     testPassword,
+		APIKey:       "wrong",
+	})
+	require.NoError(t, err)
+	defer signer.Close()
+	_, err = signer.Sign(context.Background(), [32]byte{1})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestLocalSigner(t *testing.T) {
+	keyPair := newTestKeyPair(t, "0x1234")
+	signer := NewLocalSigner(keyPair)
+
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	signature, err := signer.Sign(context.Background(), msg)
+	require.NoError(t, err)
+	assert.Equal(t, keyPair.SignMessage(msg), signature)
+
+	hashedMsg := bn254utils.MapToCurve([32]byte{1})
+	signature, err = signer.SignG1(context.Background(), &bls.G1Point{G1Affine: hashedMsg})
+	require.NoError(t, err)
+	assert.Equal(t, keyPair.SignHashedToCurveMessage(hashedMsg), signature)
+	_, err = signer.SignG1(context.Background(), nil)
+	assert.ErrorIs(t, err, bls.ErrNilPoint)
+
+	assert.Equal(t, keyPair.GetPubKeyG1(), signer.GetPublicKeyG1())
+	assert.Equal(t, keyPair.GetPubKeyG2(), signer.GetPublicKeyG2())
+	assert.Equal(t, types.OperatorIdFromKeyPair(keyPair), signer.GetOperatorId())
+
+	keyPair.Zeroize()
+	_, err = signer.Sign(context.Background(), msg)
+	assert.ErrorIs(t, err, bls.ErrKeyZeroized)
+	_, err = signer.SignG1(context.Background(), &bls.G1Point{G1Affine: hashedMsg})
+	assert.ErrorIs(t, err, bls.ErrKeyZeroized)
+}
@@ -0,0 +1,70 @@
+// Package blssigner signs with BLS keys that may be held outside of the operator process, e.g. by a remote signer
+// implementing the Cerberus API, so that the private keys are never loaded by the operator.
+package blssigner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+)
+
+var (
+	// ErrSignerUnavailable is returned when the remote signer can't be reached
+	ErrSignerUnavailable = errors.New("bls signer is unavailable")
+	// ErrPublicKeyMismatch is returned when the keys of the remote signer don't match the selected public key, e.g.
+	// when its signatures don't verify against it
+	ErrPublicKeyMismatch = errors.New("bls signer public key mismatch")
+)
+
+// Signer signs messages with a BLS key pair on G1, whose signatures are verified against its G2 public key
+type Signer interface {
+	// Sign signs msg, hashed to G1 with the MapToCurve of the contracts
+	Sign(ctx context.Context, msg [32]byte) (*bls.Signature, error)
+	// SignG1 signs a message already hashed to G1, e.g. the pubkey registration message hash of the
+	// RegistryCoordinator
+	SignG1(ctx context.Context, hashedMsg *bls.G1Point) (*bls.Signature, error)
+	GetPublicKeyG1() *bls.G1Point
+	GetPublicKeyG2() *bls.G2Point
+	// GetOperatorId returns the operator id of the G1 public key, like BLSApkRegistry.getOperatorId
+	GetOperatorId() types.OperatorId
+}
+
+type localSigner struct {
+	keyPair *bls.KeyPair
+}
+
+var _ Signer = (*localSigner)(nil)
+
+// NewLocalSigner returns the in-process Signer of keyPair
+func NewLocalSigner(keyPair *bls.KeyPair) Signer {
+	return &localSigner{keyPair: keyPair}
+}
+
+func (s *localSigner) Sign(ctx context.Context, msg [32]byte) (*bls.Signature, error) {
+	return s.keyPair.Sign(msg)
+}
+
+func (s *localSigner) SignG1(ctx context.Context, hashedMsg *bls.G1Point) (*bls.Signature, error) {
+	if hashedMsg == nil || hashedMsg.G1Affine == nil {
+		return nil, fmt.Errorf("hashed message: %w", bls.ErrNilPoint)
+	}
+	if s.keyPair.IsZeroized() {
+		return nil, bls.ErrKeyZeroized
+	}
+	return s.keyPair.SignHashedToCurveMessage(hashedMsg.G1Affine), nil
+}
+
+func (s *localSigner) GetPublicKeyG1() *bls.G1Point {
+	return s.keyPair.GetPubKeyG1()
+}
+
+func (s *localSigner) GetPublicKeyG2() *bls.G2Point {
+	return s.keyPair.GetPubKeyG2()
+}
+
+func (s *localSigner) GetOperatorId() types.OperatorId {
+	return types.OperatorIdFromKeyPair(s.keyPair)
+}
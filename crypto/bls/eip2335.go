@@ -0,0 +1,304 @@
+package bls
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// EIP-2335 keystores, as written by the eth staking deposit-cli
+// https://eips.ethereum.org/EIPS/eip-2335
+
+const (
+	eip2335Version = 4
+
+	eip2335KdfScrypt = "scrypt"
+	eip2335KdfPbkdf2 = "pbkdf2"
+	eip2335Checksum  = "sha256"
+	eip2335Cipher    = "aes-128-ctr"
+	eip2335Prf       = "hmac-sha256"
+
+	// scrypt parameters of the keystores written by SaveToEIP2335, which are the ones of the spec
+	eip2335ScryptN     = 262144
+	eip2335ScryptR     = 8
+	eip2335ScryptP     = 1
+	eip2335ScryptDkLen = 32
+)
+
+// ErrInvalidEIP2335Password is returned when the checksum of an EIP-2335 keystore doesn't match its password
+var ErrInvalidEIP2335Password = errors.New("invalid password: checksum mismatch")
+
+type eip2335Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type eip2335Crypto struct {
+	Kdf      eip2335Module `json:"kdf"`
+	Checksum eip2335Module `json:"checksum"`
+	Cipher   eip2335Module `json:"cipher"`
+}
+
+type eip2335Keystore struct {
+	Crypto      eip2335Crypto `json:"crypto"`
+	Description string        `json:"description"`
+	PubKey      string        `json:"pubkey"`
+	Path        string        `json:"path"`
+	UUID        string        `json:"uuid"`
+	Version     int           `json:"version"`
+}
+
+type eip2335ScryptParams struct {
+	DkLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type eip2335Pbkdf2Params struct {
+	DkLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	Prf   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type eip2335CipherParams struct {
+	IV string `json:"iv"`
+}
+
+// isEIP2335Keystore returns whether the keystore contents are an EIP-2335 keystore rather than a legacy one
+func isEIP2335Keystore(keyStoreContents []byte) bool {
+	keystore := struct {
+		Version int `json:"version"`
+		Crypto  struct {
+			Kdf json.RawMessage `json:"kdf"`
+		} `json:"crypto"`
+	}{}
+	if err := json.Unmarshal(keyStoreContents, &keystore); err != nil {
+		return false
+	}
+	return keystore.Version == eip2335Version && len(keystore.Crypto.Kdf) > 0
+}
+
+// SaveToEIP2335 saves the private key in an EIP-2335 keystore file encrypted with password, using the scrypt KDF.
+// The pubkey field of the keystore is the compressed G1 public key.
+func (k *KeyPair) SaveToEIP2335(path string, password string) error {
+	if k.IsZeroized() {
+		return ErrKeyZeroized
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return err
+	}
+	decryptionKey, err := scrypt.Key(
+		eip2335Password(password),
+		salt,
+		eip2335ScryptN,
+		eip2335ScryptR,
+		eip2335ScryptP,
+		eip2335ScryptDkLen,
+	)
+	if err != nil {
+		return err
+	}
+	sk := k.PrivKey.Bytes()
+	cipherMessage, err := aes128CTR(decryptionKey[:16], iv, sk[:])
+	if err != nil {
+		return err
+	}
+
+	kdfParams, err := json.Marshal(eip2335ScryptParams{
+		DkLen: eip2335ScryptDkLen,
+		N:     eip2335ScryptN,
+		P:     eip2335ScryptP,
+		R:     eip2335ScryptR,
+		Salt:  hex.EncodeToString(salt),
+	})
+	if err != nil {
+		return err
+	}
+	cipherParams, err := json.Marshal(eip2335CipherParams{IV: hex.EncodeToString(iv)})
+	if err != nil {
+		return err
+	}
+	pubKey := k.PubKey.Bytes()
+	keystore := eip2335Keystore{
+		Crypto: eip2335Crypto{
+			Kdf: eip2335Module{Function: eip2335KdfScrypt, Params: kdfParams},
+			Checksum: eip2335Module{
+				Function: eip2335Checksum,
+				Params:   json.RawMessage("{}"),
+				Message:  hex.EncodeToString(eip2335Checksum256(decryptionKey, cipherMessage)),
+			},
+			Cipher: eip2335Module{
+				Function: eip2335Cipher,
+				Params:   cipherParams,
+				Message:  hex.EncodeToString(cipherMessage),
+			},
+		},
+		PubKey:  hex.EncodeToString(pubKey[:]),
+		Path:    "",
+		UUID:    uuid.New().String(),
+		Version: eip2335Version,
+	}
+	data, err := json.MarshalIndent(keystore, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeKeyFile(path, data)
+}
+
+// ReadPrivateKeyFromEIP2335 reads the private key of the EIP-2335 keystore file at path, encrypted with the scrypt or
+// pbkdf2 KDF. The secret of the keystore must be a valid BN254 scalar.
+func ReadPrivateKeyFromEIP2335(path string, password string) (*KeyPair, error) {
+	keyStoreContents, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	return decryptEIP2335Keystore(keyStoreContents, password)
+}
+
+func decryptEIP2335Keystore(keyStoreContents []byte, password string) (*KeyPair, error) {
+	keystore := &eip2335Keystore{}
+	if err := json.Unmarshal(keyStoreContents, keystore); err != nil {
+		return nil, err
+	}
+	if keystore.Version != eip2335Version {
+		return nil, fmt.Errorf("unsupported EIP-2335 keystore version %d", keystore.Version)
+	}
+
+	decryptionKey, err := eip2335DecryptionKey(keystore.Crypto.Kdf, password)
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := hex.DecodeString(keystore.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cipher message: %w", err)
+	}
+
+	if keystore.Crypto.Checksum.Function != eip2335Checksum {
+		return nil, fmt.Errorf("unsupported checksum function %q", keystore.Crypto.Checksum.Function)
+	}
+	checksum, err := hex.DecodeString(keystore.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checksum message: %w", err)
+	}
+	if !bytes.Equal(checksum, eip2335Checksum256(decryptionKey, cipherMessage)) {
+		return nil, ErrInvalidEIP2335Password
+	}
+
+	if keystore.Crypto.Cipher.Function != eip2335Cipher {
+		return nil, fmt.Errorf("unsupported cipher function %q", keystore.Crypto.Cipher.Function)
+	}
+	cipherParams := &eip2335CipherParams{}
+	if err := json.Unmarshal(keystore.Crypto.Cipher.Params, cipherParams); err != nil {
+		return nil, fmt.Errorf("invalid cipher params: %w", err)
+	}
+	iv, err := hex.DecodeString(cipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid cipher iv %q", cipherParams.IV)
+	}
+	skBytes, err := aes128CTR(decryptionKey[:16], iv, cipherMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(skBytes) != fr.Bytes {
+		return nil, fmt.Errorf("invalid secret length %d", len(skBytes))
+	}
+	privKey := new(fr.Element)
+	if err := privKey.SetBytesCanonical(skBytes); err != nil {
+		return nil, fmt.Errorf("secret is not a BN254 private key: %w", err)
+	}
+	return NewKeyPair(privKey), nil
+}
+
+// eip2335DecryptionKey derives the decryption key of password with the KDF module kdf
+func eip2335DecryptionKey(kdf eip2335Module, password string) ([]byte, error) {
+	switch kdf.Function {
+	case eip2335KdfScrypt:
+		params := &eip2335ScryptParams{}
+		if err := json.Unmarshal(kdf.Params, params); err != nil {
+			return nil, fmt.Errorf("invalid scrypt params: %w", err)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt salt: %w", err)
+		}
+		if params.DkLen < 32 {
+			return nil, fmt.Errorf("invalid scrypt dklen %d", params.DkLen)
+		}
+		return scrypt.Key(eip2335Password(password), salt, params.N, params.R, params.P, params.DkLen)
+	case eip2335KdfPbkdf2:
+		params := &eip2335Pbkdf2Params{}
+		if err := json.Unmarshal(kdf.Params, params); err != nil {
+			return nil, fmt.Errorf("invalid pbkdf2 params: %w", err)
+		}
+		if params.Prf != eip2335Prf {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf %q", params.Prf)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pbkdf2 salt: %w", err)
+		}
+		if params.DkLen < 32 || params.C <= 0 {
+			return nil, fmt.Errorf("invalid pbkdf2 dklen %d or c %d", params.DkLen, params.C)
+		}
+		return pbkdf2.Key(eip2335Password(password), salt, params.C, params.DkLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("unsupported kdf function %q", kdf.Function)
+	}
+}
+
+// eip2335Password returns the password processed as per the spec: NFKD normalized, without the C0, C1 and Delete
+// control codes, and UTF-8 encoded
+func eip2335Password(password string) []byte {
+	password = strings.Map(func(r rune) rune {
+		if r <= 0x1f || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, norm.NFKD.String(password))
+	if !utf8.ValidString(password) {
+		password = strings.ToValidUTF8(password, "")
+	}
+	return []byte(password)
+}
+
+func eip2335Checksum256(decryptionKey []byte, cipherMessage []byte) []byte {
+	checksum := sha256.Sum256(append(append([]byte{}, decryptionKey[16:32]...), cipherMessage...))
+	return checksum[:]
+}
+
+func aes128CTR(key []byte, iv []byte, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
@@ -0,0 +1,56 @@
+package bls
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the test vectors of the spec, generated by the reference python implementation
+// https://eips.ethereum.org/EIPS/eip-2335#test-cases
+const (
+	eip2335TestPassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	eip2335TestSecret   = "0x000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+)
+
+func TestReadPrivateKeyFromEIP2335(t *testing.T) {
+	expectedKeyPair, err := NewKeyPairFromString(eip2335TestSecret)
+	require.NoError(t, err)
+
+	for _, keystorePath := range []string{"testdata/eip2335_scrypt.json", "testdata/eip2335_pbkdf2.json"} {
+		t.Run(keystorePath, func(t *testing.T) {
+			keyPair, err := ReadPrivateKeyFromEIP2335(keystorePath, eip2335TestPassword)
+			require.NoError(t, err)
+			assert.Equal(t, expectedKeyPair, keyPair)
+
+			// the format is detected by the legacy read path
+			keyPair, err = ReadPrivateKeyFromFile(keystorePath, eip2335TestPassword)
+			require.NoError(t, err)
+			assert.Equal(t, expectedKeyPair, keyPair)
+
+			_, err = ReadPrivateKeyFromEIP2335(keystorePath, "testpassword")
+			assert.ErrorIs(t, err, ErrInvalidEIP2335Password)
+		})
+	}
+}
+
+func TestSaveToEIP2335(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "test.bls.key.json")
+	randomKey, err := GenRandomBlsKeys()
+	require.NoError(t, err)
+
+	err = randomKey.SaveToEIP2335(keyPath, "test")
+	require.NoError(t, err)
+
+	keyPair, err := ReadPrivateKeyFromEIP2335(keyPath, "test")
+	require.NoError(t, err)
+	assert.Equal(t, randomKey, keyPair)
+	keyPair, err = ReadPrivateKeyFromFile(keyPath, "test")
+	require.NoError(t, err)
+	assert.Equal(t, randomKey, keyPair)
+
+	_, err = ReadPrivateKeyFromFile(keyPath, "wrong password")
+	assert.ErrorIs(t, err, ErrInvalidEIP2335Password)
+}
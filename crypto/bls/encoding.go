@@ -0,0 +1,262 @@
+package bls
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The points are marshaled as the 0x prefixed hex of their gnark-crypto compressed encoding, whose 2 most significant
+// bits are the compression flag. The JSON objects of the X and Y coordinates marshaled before are still unmarshaled.
+
+// compressionFlagMask masks the compression flag of the first byte of an encoding, which is 0 when uncompressed
+const compressionFlagMask = 0b11 << 6
+
+// NewG1PointFromBytes returns the G1 point of its compressed encoding, as returned by G1Point.Bytes. It returns
+// ErrInvalidPoint if the encoding is not canonical or not the one of a point on the curve.
+func NewG1PointFromBytes(data []byte) (*G1Point, error) {
+	if len(data) != bn254.SizeOfG1AffineCompressed || data[0]&compressionFlagMask == 0 {
+		return nil, fmt.Errorf("%w: G1 point encoding must be %d compressed bytes", ErrInvalidPoint,
+			bn254.SizeOfG1AffineCompressed)
+	}
+	p := new(bn254.G1Affine)
+	if _, err := p.SetBytes(data); err != nil {
+		return nil, fmt.Errorf("%w: G1 point encoding: %w", ErrInvalidPoint, err)
+	}
+	return &G1Point{p}, nil
+}
+
+// NewG2PointFromBytes returns the G2 point of its compressed encoding, as returned by G2Point.Bytes. It returns
+// ErrInvalidPoint if the encoding is not canonical or not the one of a point of the subgroup.
+func NewG2PointFromBytes(data []byte) (*G2Point, error) {
+	if len(data) != bn254.SizeOfG2AffineCompressed || data[0]&compressionFlagMask == 0 {
+		return nil, fmt.Errorf("%w: G2 point encoding must be %d compressed bytes", ErrInvalidPoint,
+			bn254.SizeOfG2AffineCompressed)
+	}
+	p := new(bn254.G2Affine)
+	if _, err := p.SetBytes(data); err != nil {
+		return nil, fmt.Errorf("%w: G2 point encoding: %w", ErrInvalidPoint, err)
+	}
+	return &G2Point{p}, nil
+}
+
+// MarshalText returns the 0x prefixed hex of the compressed encoding of p
+func (p *G1Point) MarshalText() ([]byte, error) {
+	if p.G1Affine == nil {
+		return nil, fmt.Errorf("G1 point: %w", ErrNilPoint)
+	}
+	data := p.Bytes()
+	return []byte(hexutil.Encode(data[:])), nil
+}
+
+// UnmarshalText sets p to the point of the 0x prefixed hex of its compressed encoding
+func (p *G1Point) UnmarshalText(text []byte) error {
+	data, err := hexutil.Decode(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid G1 point hex: %w", err)
+	}
+	point, err := NewG1PointFromBytes(data)
+	if err != nil {
+		return err
+	}
+	p.G1Affine = point.G1Affine
+	return nil
+}
+
+// MarshalJSON returns the JSON string of the 0x prefixed hex of the compressed encoding of p
+func (p *G1Point) MarshalJSON() ([]byte, error) {
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON sets p to the point of the JSON string of the 0x prefixed hex of its compressed encoding, or of the
+// JSON object of its coordinates
+func (p *G1Point) UnmarshalJSON(data []byte) error {
+	if !isJSONObject(data) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("invalid G1 point JSON: %w", err)
+		}
+		return p.UnmarshalText([]byte(text))
+	}
+	coordinates := struct {
+		X, Y fp.Element
+	}{}
+	if err := json.Unmarshal(data, &coordinates); err != nil {
+		return fmt.Errorf("invalid G1 point JSON: %w", err)
+	}
+	point := &bn254.G1Affine{X: coordinates.X, Y: coordinates.Y}
+	if !point.IsOnCurve() {
+		return fmt.Errorf("%w: G1 point is not on the curve", ErrInvalidPoint)
+	}
+	p.G1Affine = point
+	return nil
+}
+
+// MarshalText returns the 0x prefixed hex of the compressed encoding of p
+func (p *G2Point) MarshalText() ([]byte, error) {
+	if p.G2Affine == nil {
+		return nil, fmt.Errorf("G2 point: %w", ErrNilPoint)
+	}
+	data := p.Bytes()
+	return []byte(hexutil.Encode(data[:])), nil
+}
+
+// UnmarshalText sets p to the point of the 0x prefixed hex of its compressed encoding
+func (p *G2Point) UnmarshalText(text []byte) error {
+	data, err := hexutil.Decode(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid G2 point hex: %w", err)
+	}
+	point, err := NewG2PointFromBytes(data)
+	if err != nil {
+		return err
+	}
+	p.G2Affine = point.G2Affine
+	return nil
+}
+
+// MarshalJSON returns the JSON string of the 0x prefixed hex of the compressed encoding of p
+func (p *G2Point) MarshalJSON() ([]byte, error) {
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON sets p to the point of the JSON string of the 0x prefixed hex of its compressed encoding, or of the
+// JSON object of its coordinates
+func (p *G2Point) UnmarshalJSON(data []byte) error {
+	if !isJSONObject(data) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("invalid G2 point JSON: %w", err)
+		}
+		return p.UnmarshalText([]byte(text))
+	}
+	coordinates := struct {
+		X, Y struct{ A0, A1 fp.Element }
+	}{}
+	if err := json.Unmarshal(data, &coordinates); err != nil {
+		return fmt.Errorf("invalid G2 point JSON: %w", err)
+	}
+	point := &bn254.G2Affine{}
+	point.X.A0, point.X.A1 = coordinates.X.A0, coordinates.X.A1
+	point.Y.A0, point.Y.A1 = coordinates.Y.A0, coordinates.Y.A1
+	if !point.IsOnCurve() || !point.IsInSubGroup() {
+		return fmt.Errorf("%w: G2 point is not in the subgroup", ErrInvalidPoint)
+	}
+	p.G2Affine = point
+	return nil
+}
+
+// MarshalText returns the 0x prefixed hex of the compressed encoding of the G1 point of s
+func (s *Signature) MarshalText() ([]byte, error) {
+	if s.G1Point == nil {
+		return nil, fmt.Errorf("signature: %w", ErrNilPoint)
+	}
+	return s.G1Point.MarshalText()
+}
+
+// UnmarshalText sets s to the signature of the 0x prefixed hex of the compressed encoding of its G1 point
+func (s *Signature) UnmarshalText(text []byte) error {
+	point := &G1Point{}
+	if err := point.UnmarshalText(text); err != nil {
+		return err
+	}
+	s.G1Point = point
+	return nil
+}
+
+// MarshalJSON returns the JSON string of the 0x prefixed hex of the compressed encoding of the G1 point of s
+func (s *Signature) MarshalJSON() ([]byte, error) {
+	if s.G1Point == nil {
+		return nil, fmt.Errorf("signature: %w", ErrNilPoint)
+	}
+	return s.G1Point.MarshalJSON()
+}
+
+// UnmarshalJSON sets s to the signature of the JSON string of the 0x prefixed hex of the compressed encoding of its
+// G1 point, or of the JSON object of its g1_point field
+func (s *Signature) UnmarshalJSON(data []byte) error {
+	point := &G1Point{}
+	if isJSONObject(data) {
+		signature := struct {
+			G1Point json.RawMessage `json:"g1_point"`
+		}{}
+		if err := json.Unmarshal(data, &signature); err != nil {
+			return fmt.Errorf("invalid signature JSON: %w", err)
+		}
+		data = signature.G1Point
+	}
+	if err := point.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	s.G1Point = point
+	return nil
+}
+
+func isJSONObject(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// keyPairJSONVersion is the version of the JSON envelope of the key pairs
+const keyPairJSONVersion = 1
+
+// ErrUnsupportedKeyPairVersion is returned when unmarshaling the JSON envelope of a key pair of another version, e.g.
+// written by a newer release
+var ErrUnsupportedKeyPairVersion = errors.New("unsupported bls key pair JSON version")
+
+// keyPairJSON is the JSON envelope of a key pair, whose public keys are derived from the private key when unmarshaled
+type keyPairJSON struct {
+	Version int    `json:"version"`
+	PrivKey string `json:"privKey"`
+}
+
+// MarshalJSON returns the versioned JSON envelope {"version":1,"privKey":"0x..."} of the unencrypted private key of
+// k, which must be stored as a secret. It returns ErrKeyZeroized once k is zeroized.
+func (k *KeyPair) MarshalJSON() ([]byte, error) {
+	if k.IsZeroized() {
+		return nil, ErrKeyZeroized
+	}
+	privKey := k.PrivKey.Bytes()
+	return json.Marshal(keyPairJSON{Version: keyPairJSONVersion, PrivKey: hexutil.Encode(privKey[:])})
+}
+
+// UnmarshalJSON sets k to the key pair of the versioned JSON envelope of its private key, returning
+// ErrUnsupportedKeyPairVersion if the envelope is of another version
+func (k *KeyPair) UnmarshalJSON(data []byte) error {
+	var envelope keyPairJSON
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("invalid bls key pair JSON: %w", err)
+	}
+	if envelope.Version != keyPairJSONVersion {
+		return fmt.Errorf("%w %d, expected %d", ErrUnsupportedKeyPairVersion, envelope.Version, keyPairJSONVersion)
+	}
+	skBytes, err := hexutil.Decode(envelope.PrivKey)
+	if err != nil {
+		return fmt.Errorf("invalid bls private key hex: %w", err)
+	}
+	if len(skBytes) != fr.Bytes {
+		return fmt.Errorf("invalid bls private key length %d, expected %d", len(skBytes), fr.Bytes)
+	}
+	privKey := new(PrivateKey)
+	if err := privKey.SetBytesCanonical(skBytes); err != nil {
+		return fmt.Errorf("bls private key is not a BN254 scalar: %w", err)
+	}
+	if privKey.IsZero() {
+		return errors.New("bls private key is zero")
+	}
+	*k = *NewKeyPair(privKey)
+	return nil
+}
@@ -0,0 +1,185 @@
+package bls
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointEncoding(t *testing.T) {
+	keyPair, err := GenRandomBlsKeys()
+	require.NoError(t, err)
+	signature := keyPair.SignMessage([32]byte{1})
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		for _, g1Point := range []*G1Point{keyPair.GetPubKeyG1(), NewZeroG1Point()} {
+			data, err := json.Marshal(g1Point)
+			require.NoError(t, err)
+			decoded := &G1Point{}
+			require.NoError(t, json.Unmarshal(data, decoded))
+			assert.Equal(t, g1Point, decoded)
+		}
+		for _, g2Point := range []*G2Point{keyPair.GetPubKeyG2(), NewZeroG2Point()} {
+			data, err := json.Marshal(g2Point)
+			require.NoError(t, err)
+			decoded := &G2Point{}
+			require.NoError(t, json.Unmarshal(data, decoded))
+			assert.Equal(t, g2Point, decoded)
+		}
+
+		data, err := json.Marshal(signature)
+		require.NoError(t, err)
+		compressed := signature.Bytes()
+		assert.Equal(t, `"`+hexutil.Encode(compressed[:])+`"`, string(data))
+		decoded := &Signature{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assert.Equal(t, signature, decoded)
+
+		// in structs
+		type operator struct {
+			PubkeyG1  *G1Point   `json:"pubkey_g1"`
+			PubkeyG2  *G2Point   `json:"pubkey_g2"`
+			Signature *Signature `json:"signature"`
+		}
+		data, err = json.Marshal(operator{keyPair.GetPubKeyG1(), keyPair.GetPubKeyG2(), signature})
+		require.NoError(t, err)
+		decodedOperator := operator{}
+		require.NoError(t, json.Unmarshal(data, &decodedOperator))
+		assert.Equal(t, operator{keyPair.GetPubKeyG1(), keyPair.GetPubKeyG2(), signature}, decodedOperator)
+	})
+
+	t.Run("coordinates JSON is still decoded", func(t *testing.T) {
+		g1Point := &G1Point{}
+		require.NoError(t, json.Unmarshal([]byte(`{"X":1,"Y":2}`), g1Point))
+		assert.Equal(t, NewG1Point(big.NewInt(1), big.NewInt(2)), g1Point)
+
+		pubkeyG2 := keyPair.GetPubKeyG2()
+		data, err := json.Marshal(struct{ X, Y struct{ A0, A1 *fp.Element } }{
+			X: struct{ A0, A1 *fp.Element }{&pubkeyG2.X.A0, &pubkeyG2.X.A1},
+			Y: struct{ A0, A1 *fp.Element }{&pubkeyG2.Y.A0, &pubkeyG2.Y.A1},
+		})
+		require.NoError(t, err)
+		g2Point := &G2Point{}
+		require.NoError(t, json.Unmarshal(data, g2Point))
+		assert.Equal(t, pubkeyG2, g2Point)
+
+		decoded := &Signature{}
+		require.NoError(t, json.Unmarshal([]byte(`{"g1_point":{"X":1,"Y":2}}`), decoded))
+		assert.Equal(t, &Signature{NewG1Point(big.NewInt(1), big.NewInt(2))}, decoded)
+
+		assert.ErrorIs(t, json.Unmarshal([]byte(`{"X":1,"Y":3}`), g1Point), ErrInvalidPoint)
+	})
+
+	t.Run("gob round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(signature))
+		decoded := &Signature{}
+		require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+		assert.Equal(t, signature, decoded)
+	})
+
+	t.Run("invalid encodings are rejected", func(t *testing.T) {
+		compressedG1 := keyPair.GetPubKeyG1().Bytes()
+		compressedG2 := keyPair.GetPubKeyG2().Bytes()
+		// the X coordinate of a compressed point is the field modulus, which is not canonical
+		nonCanonical := fp.Modulus().FillBytes(make([]byte, 32))
+		nonCanonical[0] |= compressedG1[0] & compressionFlagMask
+		// no point of the curve has X = 0, as 3 is not a square
+		notOnCurve := make([]byte, 32)
+		notOnCurve[0] = 0b10 << 6
+		// infinity with trailing bits
+		invalidInfinity := make([]byte, 32)
+		invalidInfinity[0], invalidInfinity[31] = 0b01<<6, 1
+
+		tests := map[string][]byte{
+			"uncompressed":         keyPair.GetPubKeyG1().Serialize(),
+			"truncated":            compressedG1[:31],
+			"non canonical":        nonCanonical,
+			"not on curve":         notOnCurve,
+			"invalid infinity":     invalidInfinity,
+			"G2 point in G1 slot":  compressedG2[:],
+			"uncompressed flag":    append([]byte{compressedG1[0] &^ compressionFlagMask}, compressedG1[1:]...),
+			"G1 point with G2 len": append(compressedG1[:], compressedG1[:]...),
+		}
+		for name, data := range tests {
+			_, err := NewG1PointFromBytes(data)
+			assert.ErrorIs(t, err, ErrInvalidPoint, name)
+		}
+		_, err := NewG2PointFromBytes(compressedG1[:])
+		assert.ErrorIs(t, err, ErrInvalidPoint)
+		_, err = NewG2PointFromBytes(append(notOnCurve, make([]byte, 32)...))
+		assert.ErrorIs(t, err, ErrInvalidPoint)
+
+		assert.ErrorContains(t, json.Unmarshal([]byte(`"1234"`), &G1Point{}), "invalid G1 point hex")
+		assert.ErrorIs(t, json.Unmarshal([]byte(`"`+hexutil.Encode(notOnCurve)+`"`), &Signature{}), ErrInvalidPoint)
+	})
+}
+
+func TestKeyPairJSON(t *testing.T) {
+	keyPair, err := NewKeyPairFromString("0x1234")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(keyPair)
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"version":1,"privKey":"0x0000000000000000000000000000000000000000000000000000000000001234"}`,
+		string(data),
+	)
+	unmarshaled := &KeyPair{}
+	require.NoError(t, json.Unmarshal(data, unmarshaled))
+	assert.Equal(t, keyPair.PrivKey, unmarshaled.PrivKey)
+	assert.Equal(t, keyPair.GetPubKeyG1(), unmarshaled.GetPubKeyG1())
+	assert.Equal(t, keyPair.GetPubKeyG2(), unmarshaled.GetPubKeyG2())
+
+	var tests = map[string]struct {
+		data    string
+		wantErr error
+	}{
+		"future version": {
+			data:    `{"version":2,"privKey":"0x0000000000000000000000000000000000000000000000000000000000001234"}`,
+			wantErr: ErrUnsupportedKeyPairVersion,
+		},
+		"missing version": {
+			data:    `{"privKey":"0x0000000000000000000000000000000000000000000000000000000000001234"}`,
+			wantErr: ErrUnsupportedKeyPairVersion,
+		},
+		"legacy struct encoding": {
+			data: `{"PrivKey":[4660,0,0,0],"PubKey":null}`,
+		},
+		"missing 0x": {
+			data: `{"version":1,"privKey":"0000000000000000000000000000000000000000000000000000000000001234"}`,
+		},
+		"short private key": {
+			data: `{"version":1,"privKey":"0x1234"}`,
+		},
+		"private key larger than the field": {
+			data: `{"version":1,"privKey":"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"}`,
+		},
+		"zero private key": {
+			data: `{"version":1,"privKey":"0x0000000000000000000000000000000000000000000000000000000000000000"}`,
+		},
+		"not an object": {
+			data: `"0x0000000000000000000000000000000000000000000000000000000000001234"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.data), &KeyPair{})
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	keyPair.Zeroize()
+	_, err = json.Marshal(keyPair)
+	assert.ErrorIs(t, err, ErrKeyZeroized)
+}
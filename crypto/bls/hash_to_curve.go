@@ -0,0 +1,104 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+
+	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// Messages are hashed to G1 with MapToCurve by default, the try-and-increment map of the BN254 library of the
+// middleware contracts, which is the only one the BLSSignatureChecker contract verifies. AVSs verifying their
+// signatures offchain, e.g. with the BLS libraries of other languages, can sign and verify with the hash to curve of
+// RFC 9380 instead, passing WithHashFunction(HashToG1Function(dst)).
+
+// ErrInvalidDST is returned for a domain separation tag that RFC 9380 doesn't allow
+var ErrInvalidDST = errors.New("invalid domain separation tag")
+
+// HashToG1Suite is the RFC 9380 suite implemented by HashToG1, which domain separation tags usually include
+const HashToG1Suite = "BN254G1_XMD:SHA-256_SVDW_RO_"
+
+// maxDSTLength is the maximum length of the domain separation tags of expand_message_xmd
+const maxDSTLength = 255
+
+// HashFunction hashes a message to a point of G1
+type HashFunction func(message [32]byte) *bn254.G1Affine
+
+// MapToCurve is the HashFunction of the middleware contracts, and the default one
+func MapToCurve(message [32]byte) *bn254.G1Affine {
+	return bn254utils.MapToCurve(message)
+}
+
+// HashToG1 hashes msg to a point of G1 with the random oracle hash to curve of RFC 9380 of the suite
+// BN254G1_XMD:SHA-256_SVDW_RO_, i.e. with expand_message_xmd with SHA-256 and the Shallue-van de Woestijne map, with
+// the domain separation tag dst. It returns ErrInvalidDST if dst is empty or longer than 255 bytes.
+//
+// It isn't compatible with the contracts, which hash messages with MapToCurve.
+func HashToG1(msg []byte, dst []byte) (*G1Point, error) {
+	if err := checkDST(dst); err != nil {
+		return nil, err
+	}
+	p, err := bn254.HashToG1(msg, dst)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash to G1: %w", err)
+	}
+	return &G1Point{&p}, nil
+}
+
+// HashToG1Function returns the HashFunction hashing the messages with HashToG1 and the domain separation tag dst
+func HashToG1Function(dst []byte) (HashFunction, error) {
+	if err := checkDST(dst); err != nil {
+		return nil, err
+	}
+	dst = append([]byte(nil), dst...)
+	return func(message [32]byte) *bn254.G1Affine {
+		p, err := bn254.HashToG1(message[:], dst)
+		if err != nil {
+			// unreachable, expand_message_xmd only fails with an invalid dst or output length
+			panic(err)
+		}
+		return &p
+	}, nil
+}
+
+type signOptions struct {
+	hashFunction HashFunction
+}
+
+// SignOption configures how messages are signed and verified. Signatures must be verified with the options they were
+// signed with.
+type SignOption func(*signOptions)
+
+// WithHashFunction sets the HashFunction hashing the messages to G1, which defaults to MapToCurve
+func WithHashFunction(hashFunction HashFunction) SignOption {
+	return func(o *signOptions) {
+		o.hashFunction = hashFunction
+	}
+}
+
+// hashFunction returns the HashFunction of opts
+func hashFunction(opts []SignOption) HashFunction {
+	options := signOptions{hashFunction: MapToCurve}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options.hashFunction
+}
+
+// verifyHashedMessage returns whether sig is the signature of msgPoint by pubkey, i.e. e(msgPoint, pubkey) *
+// e(sig, -G2) == 1
+func verifyHashedMessage(sig *bn254.G1Affine, pubkey *bn254.G2Affine, msgPoint *bn254.G1Affine) (bool, error) {
+	return bn254.PairingCheck(
+		[]bn254.G1Affine{*msgPoint, *sig},
+		[]bn254.G2Affine{*pubkey, *negG2Generator()},
+	)
+}
+
+func checkDST(dst []byte) error {
+	if len(dst) == 0 || len(dst) > maxDSTLength {
+		return fmt.Errorf("%w: length must be between 1 and %d bytes, got %d", ErrInvalidDST, maxDSTLength, len(dst))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/field/hash"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpandMsgXmd checks the expand_message_xmd of HashToG1 against the test vectors of RFC 9380, appendix K.1
+func TestExpandMsgXmd(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	tests := map[string]string{
+		"":                 "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		"abc":              "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+		"abcdef0123456789": "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1",
+	}
+	for msg, expected := range tests {
+		uniformBytes, err := hash.ExpandMsgXmd([]byte(msg), dst, 0x20)
+		require.NoError(t, err)
+		assert.Equal(t, expected, hex.EncodeToString(uniformBytes), msg)
+	}
+}
+
+// TestHashToG1 checks HashToG1 against the known answers of the BN254G1_XMD:SHA-256_SVDW_RO_ suite, with the messages
+// and the domain separation tag format of the test vectors of RFC 9380, appendix J. The RFC doesn't specify the suite,
+// whose vectors are the ones of gnark-crypto.
+func TestHashToG1(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-" + HashToG1Suite)
+	tests := []struct {
+		msg  string
+		x, y string
+	}{
+		{
+			msg: "",
+			x:   "0a976ab906170db1f9638d376514dbf8c42aef256a54bbd48521f20749e59e86",
+			y:   "02925ead66b9e68bfc309b014398640ab55f6619ab59bc1fab2210ad4c4d53d5",
+		},
+		{
+			msg: "abc",
+			x:   "23f717bee89b1003957139f193e6be7da1df5f1374b26a4643b0378b5baf53d1",
+			y:   "04142f826b71ee574452dbc47e05bc3e1a647478403a7ba38b7b93948f4e151d",
+		},
+		{
+			msg: "abcdef0123456789",
+			x:   "187dbf1c3c89aceceef254d6548d7163fdfa43084145f92c4c91c85c21442d4a",
+			y:   "0abd99d5b0000910b56058f9cc3b0ab0a22d47cf27615f588924fac1e5c63b4d",
+		},
+		{
+			msg: "q128_" + strings.Repeat("q", 128),
+			x:   "00fe2b0743575324fc452d590d217390ad48e5a16cf051bee5c40a2eba233f5c",
+			y:   "0794211e0cc72d3cbbdf8e4e5cd6e7d7e78d101ff94862caae8acbe63e9fdc78",
+		},
+		{
+			msg: "a512_" + strings.Repeat("a", 512),
+			x:   "01b05dc540bd79fd0fea4fbb07de08e94fc2e7bd171fe025c479dc212a2173ce",
+			y:   "1bf028afc00c0f843d113758968f580640541728cfc6d32ced9779aa613cd9b0",
+		},
+	}
+	for _, tt := range tests {
+		p, err := HashToG1([]byte(tt.msg), dst)
+		require.NoError(t, err)
+		x := p.X.Bytes()
+		y := p.Y.Bytes()
+		assert.Equal(t, tt.x, hex.EncodeToString(x[:]), tt.msg)
+		assert.Equal(t, tt.y, hex.EncodeToString(y[:]), tt.msg)
+	}
+
+	_, err := HashToG1([]byte("abc"), nil)
+	assert.ErrorIs(t, err, ErrInvalidDST)
+	_, err = HashToG1([]byte("abc"), make([]byte, 256))
+	assert.ErrorIs(t, err, ErrInvalidDST)
+	_, err = HashToG1Function(nil)
+	assert.ErrorIs(t, err, ErrInvalidDST)
+}
+
+func TestSignWithHashFunction(t *testing.T) {
+	keyPair, err := NewKeyPairFromString("0x1234")
+	require.NoError(t, err)
+	msg := crypto.Keccak256Hash([]byte("task response"))
+	hashToG1, err := HashToG1Function([]byte("EIGENSDK-V01-CS01-with-" + HashToG1Suite))
+	require.NoError(t, err)
+
+	// the default remains the MapToCurve of the contracts
+	defaultSig := keyPair.SignMessage(msg)
+	assert.Equal(t, keyPair.SignHashedToCurveMessage(bn254utils.MapToCurve(msg)), defaultSig)
+	assert.Equal(t, defaultSig, keyPair.SignMessage(msg, WithHashFunction(MapToCurve)))
+
+	sig := keyPair.SignMessage(msg, WithHashFunction(hashToG1))
+	expectedHash, err := HashToG1(msg[:], []byte("EIGENSDK-V01-CS01-with-"+HashToG1Suite))
+	require.NoError(t, err)
+	expected := new(bn254.G1Affine).ScalarMultiplication(expectedHash.G1Affine, keyPair.PrivKey.BigInt(new(big.Int)))
+	assert.Equal(t, expected, sig.G1Affine)
+	assert.NotEqual(t, defaultSig.G1Affine, sig.G1Affine)
+
+	ok, err := sig.Verify(keyPair.GetPubKeyG2(), msg, WithHashFunction(hashToG1))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = sig.Verify(keyPair.GetPubKeyG2(), msg)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = defaultSig.Verify(keyPair.GetPubKeyG2(), msg, WithHashFunction(hashToG1))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = VerifyAggregate(sig, keyPair.GetPubKeyG2(), msg, WithHashFunction(hashToG1))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	sigs, pubkeys, msgs := newSignedBatch(t, 4, false)
+	for i := range sigs {
+		sigs[i] = keyPair.SignMessage(msgs[i], WithHashFunction(hashToG1))
+		pubkeys[i] = keyPair.GetPubKeyG2()
+	}
+	ok, err = BatchVerify(sigs, pubkeys, msgs, WithHashFunction(hashToG1))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = BatchVerify(sigs, pubkeys, msgs)
+	assert.False(t, ok)
+	assert.Equal(t, &BatchVerifyError{InvalidIndices: []int{0, 1, 2, 3}}, err)
+}
@@ -0,0 +1,54 @@
+package bls
+
+import (
+	"sync"
+
+	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// The generators of G1 and G2 and their negations are parsed once, when first used, instead of at each verification.
+// The pairing checks use e(sig, -G2) so that the signature isn't negated either.
+var (
+	precomputeOnce sync.Once
+	precomputed    struct {
+		g1Gen    bn254.G1Affine
+		negG1Gen bn254.G1Affine
+		g2Gen    bn254.G2Affine
+		negG2Gen bn254.G2Affine
+	}
+)
+
+// Precompute computes the values reused by the signatures and their verification, which are otherwise computed when
+// first used, so that their cost can be paid at startup. It's safe to call concurrently and more than once.
+func Precompute() {
+	precomputeOnce.Do(func() {
+		precomputed.g1Gen = *bn254utils.GetG1Generator()
+		precomputed.negG1Gen.Neg(&precomputed.g1Gen)
+		precomputed.g2Gen = *bn254utils.GetG2Generator()
+		precomputed.negG2Gen.Neg(&precomputed.g2Gen)
+	})
+}
+
+// The returned points are shared and must not be modified
+
+func g1Generator() *bn254.G1Affine {
+	Precompute()
+	return &precomputed.g1Gen
+}
+
+func negG1Generator() *bn254.G1Affine {
+	Precompute()
+	return &precomputed.negG1Gen
+}
+
+func g2Generator() *bn254.G2Affine {
+	Precompute()
+	return &precomputed.g2Gen
+}
+
+func negG2Generator() *bn254.G2Affine {
+	Precompute()
+	return &precomputed.negG2Gen
+}
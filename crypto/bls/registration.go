@@ -0,0 +1,137 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrInvalidPubkeyRegistrationParams is returned when pubkey registration params would be rejected by the
+// BLSApkRegistry contract
+var ErrInvalidPubkeyRegistrationParams = errors.New("invalid pubkey registration params")
+
+// PubkeyRegistrationParams are the params registering a BLS public key with the BLSApkRegistry contract, i.e. the
+// IBLSApkRegistry.PubkeyRegistrationParams struct, which chainio/utils.ConvertToPubkeyRegistrationParams converts
+// them to. The signature of the registration message hash of the operator is the proof of possession of the key.
+type PubkeyRegistrationParams struct {
+	PubkeyRegistrationSignature *Signature
+	PubkeyG1                    *G1Point
+	PubkeyG2                    *G2Point
+}
+
+// MakePubkeyRegistrationParams returns the params registering the public keys of k, signing
+// registrationMessageHashG1, the pubkeyRegistrationMessageHash of the operator returned by the RegistryCoordinator
+// contract, which is already hashed to G1.
+func (k *KeyPair) MakePubkeyRegistrationParams(registrationMessageHashG1 *bn254.G1Affine) *PubkeyRegistrationParams {
+	return &PubkeyRegistrationParams{
+		PubkeyRegistrationSignature: k.SignHashedToCurveMessage(registrationMessageHashG1),
+		PubkeyG1:                    k.GetPubKeyG1(),
+		PubkeyG2:                    k.GetPubKeyG2(),
+	}
+}
+
+// OperatorIDFromG1Pubkey returns the operator id of the G1 public key p, like BLSApkRegistry.getOperatorId, i.e. the
+// keccak256 hash of the abi.encodePacked coordinates of p. It's the types.OperatorId of p, which this package can't
+// return since the types package imports it.
+func OperatorIDFromG1Pubkey(p *G1Point) [32]byte {
+	x := p.X.Bytes()
+	y := p.Y.Bytes()
+	return crypto.Keccak256Hash(x[:], y[:])
+}
+
+// GetOperatorID returns the operator id of the G1 public key of k, see OperatorIDFromG1Pubkey
+func (k *KeyPair) GetOperatorID() [32]byte {
+	return OperatorIDFromG1Pubkey(k.GetPubKeyG1())
+}
+
+// VerifyPubkeyRegistrationParams checks params like BLSApkRegistry.registerBLSPublicKey does, returning
+// ErrInvalidPubkeyRegistrationParams if the public key is zero, if the G1 and G2 public keys don't match or if the
+// signature isn't the one of registrationMessageHashG1.
+//
+// Like the contract, both checks are done in a single pairing check with a random linear combination, whose scalar
+// gamma is the keccak256 hash of the points:
+// e(signature + gamma * pubkeyG1, -G2) * e(registrationMessageHashG1 + gamma * G1, pubkeyG2) == 1
+func VerifyPubkeyRegistrationParams(
+	params *PubkeyRegistrationParams,
+	registrationMessageHashG1 *bn254.G1Affine,
+) error {
+	if params == nil || params.PubkeyRegistrationSignature == nil ||
+		params.PubkeyRegistrationSignature.G1Point == nil || params.PubkeyRegistrationSignature.G1Affine == nil {
+		return fmt.Errorf("%w: signature: %w", ErrInvalidPubkeyRegistrationParams, ErrNilPoint)
+	}
+	if params.PubkeyG1 == nil || params.PubkeyG1.G1Affine == nil {
+		return fmt.Errorf("%w: G1 public key: %w", ErrInvalidPubkeyRegistrationParams, ErrNilPoint)
+	}
+	if params.PubkeyG2 == nil || params.PubkeyG2.G2Affine == nil {
+		return fmt.Errorf("%w: G2 public key: %w", ErrInvalidPubkeyRegistrationParams, ErrNilPoint)
+	}
+	if registrationMessageHashG1 == nil {
+		return fmt.Errorf("%w: registration message hash: %w", ErrInvalidPubkeyRegistrationParams, ErrNilPoint)
+	}
+	signature, pubkeyG1, pubkeyG2 := params.PubkeyRegistrationSignature.G1Affine, params.PubkeyG1.G1Affine,
+		params.PubkeyG2.G2Affine
+	if !signature.IsOnCurve() || !pubkeyG1.IsOnCurve() || !registrationMessageHashG1.IsOnCurve() {
+		return fmt.Errorf("%w: G1 point: %w", ErrInvalidPubkeyRegistrationParams, ErrInvalidPoint)
+	}
+	if !pubkeyG2.IsOnCurve() || !pubkeyG2.IsInSubGroup() {
+		return fmt.Errorf("%w: G2 public key: %w", ErrInvalidPubkeyRegistrationParams, ErrInvalidPoint)
+	}
+	// the contract rejects the hash of the zero public key
+	if pubkeyG1.IsInfinity() {
+		return fmt.Errorf("%w: G1 public key is zero", ErrInvalidPubkeyRegistrationParams)
+	}
+
+	gamma := pubkeyRegistrationGamma(signature, pubkeyG1, pubkeyG2, registrationMessageHashG1)
+	var lhs, rhs bn254.G1Affine
+	lhs.ScalarMultiplication(pubkeyG1, gamma)
+	lhs.Add(&lhs, signature)
+	rhs.ScalarMultiplication(g1Generator(), gamma)
+	rhs.Add(&rhs, registrationMessageHashG1)
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{lhs, rhs},
+		[]bn254.G2Affine{*negG2Generator(), *pubkeyG2},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPubkeyRegistrationParams, err)
+	}
+	if !ok {
+		return fmt.Errorf(
+			"%w: signature is not the one of the registration message hash by the public keys, or the G1 and G2 "+
+				"public keys don't match",
+			ErrInvalidPubkeyRegistrationParams,
+		)
+	}
+	return nil
+}
+
+// pubkeyRegistrationGamma returns the gamma of BLSApkRegistry.registerBLSPublicKey, i.e. the keccak256 hash of the
+// abi.encodePacked coordinates of the points modulo the order of the curve. The G2 coordinates are encoded with their
+// imaginary part first, like the contract.
+func pubkeyRegistrationGamma(
+	signature *bn254.G1Affine,
+	pubkeyG1 *bn254.G1Affine,
+	pubkeyG2 *bn254.G2Affine,
+	registrationMessageHashG1 *bn254.G1Affine,
+) *big.Int {
+	x := signature.X.Bytes()
+	y := signature.Y.Bytes()
+	pubkeyG1X := pubkeyG1.X.Bytes()
+	pubkeyG1Y := pubkeyG1.Y.Bytes()
+	pubkeyG2XA1 := pubkeyG2.X.A1.Bytes()
+	pubkeyG2XA0 := pubkeyG2.X.A0.Bytes()
+	pubkeyG2YA1 := pubkeyG2.Y.A1.Bytes()
+	pubkeyG2YA0 := pubkeyG2.Y.A0.Bytes()
+	hashX := registrationMessageHashG1.X.Bytes()
+	hashY := registrationMessageHashG1.Y.Bytes()
+	hash := crypto.Keccak256(
+		x[:], y[:],
+		pubkeyG1X[:], pubkeyG1Y[:],
+		pubkeyG2XA1[:], pubkeyG2XA0[:], pubkeyG2YA1[:], pubkeyG2YA0[:],
+		hashX[:], hashY[:],
+	)
+	return new(big.Int).Mod(new(big.Int).SetBytes(hash), fr.Modulus())
+}
@@ -0,0 +1,87 @@
+package bls_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	blsapkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegrationPubkeyRegistrationParams registers a BLS public key with the BLSApkRegistry contract deployed on
+// anvil, using the params made by MakePubkeyRegistrationParams
+func TestIntegrationPubkeyRegistrationParams(t *testing.T) {
+	ctx := context.Background()
+	anvilC, err := testutils.StartAnvilContainer("contracts-deployed-anvil-state.json")
+	require.NoError(t, err)
+	anvilHttpEndpoint, err := anvilC.Endpoint(ctx, "http")
+	require.NoError(t, err)
+	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	rpcClient, err := rpc.Dial(anvilHttpEndpoint)
+	require.NoError(t, err)
+	ethHttpClient := ethclient.NewClient(rpcClient)
+
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinator(
+		contractAddrs.RegistryCoordinator,
+		ethHttpClient,
+	)
+	require.NoError(t, err)
+	blsApkRegistryAddr, err := registryCoordinator.BlsApkRegistry(&bind.CallOpts{})
+	require.NoError(t, err)
+	blsApkRegistry, err := blsapkreg.NewContractBLSApkRegistry(blsApkRegistryAddr, ethHttpClient)
+	require.NoError(t, err)
+
+	operator := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	keyPair, err := bls.GenRandomBlsKeys()
+	require.NoError(t, err)
+	registrationMessageHash, err := registryCoordinator.PubkeyRegistrationMessageHash(&bind.CallOpts{}, operator)
+	require.NoError(t, err)
+	registrationMessageHashG1 := utils.ConvertBn254GethToGnark(registrationMessageHash)
+	params := keyPair.MakePubkeyRegistrationParams(registrationMessageHashG1)
+	require.NoError(t, bls.VerifyPubkeyRegistrationParams(params, registrationMessageHashG1))
+
+	// only the registry coordinator can register public keys, so it is impersonated instead of registering the
+	// operator with EigenLayer first
+	regParams := utils.ConvertToPubkeyRegistrationParams(params)
+	blsApkRegistryABI, err := blsapkreg.ContractBLSApkRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+	data, err := blsApkRegistryABI.Pack(
+		"registerBLSPublicKey",
+		operator,
+		blsapkreg.IBLSApkRegistryPubkeyRegistrationParams{
+			PubkeyRegistrationSignature: blsapkreg.BN254G1Point(regParams.PubkeyRegistrationSignature),
+			PubkeyG1:                    blsapkreg.BN254G1Point(regParams.PubkeyG1),
+			PubkeyG2:                    blsapkreg.BN254G2Point(regParams.PubkeyG2),
+		},
+		blsapkreg.BN254G1Point(registrationMessageHash),
+	)
+	require.NoError(t, err)
+	require.NoError(t, rpcClient.Call(nil, "anvil_impersonateAccount", contractAddrs.RegistryCoordinator))
+	var txHash common.Hash
+	require.NoError(t, rpcClient.Call(&txHash, "eth_sendTransaction", map[string]interface{}{
+		"from": contractAddrs.RegistryCoordinator,
+		"to":   blsApkRegistryAddr,
+		"data": hexutil.Bytes(data),
+	}))
+	receipt, err := ethHttpClient.TransactionReceipt(ctx, txHash)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), receipt.Status)
+
+	pubkeyG1, _, err := blsApkRegistry.GetRegisteredPubkey(&bind.CallOpts{}, operator)
+	require.NoError(t, err)
+	require.Equal(t, blsapkreg.BN254G1Point(utils.ConvertToBN254G1Point(keyPair.GetPubKeyG1())), pubkeyG1)
+
+	operatorID, err := blsApkRegistry.GetOperatorId(&bind.CallOpts{}, operator)
+	require.NoError(t, err)
+	require.Equal(t, operatorID, keyPair.GetOperatorID())
+	require.Equal(t, operatorID, bls.OperatorIDFromG1Pubkey(keyPair.GetPubKeyG1()))
+}
@@ -0,0 +1,107 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPubkeyRegistrationParams(t *testing.T) {
+	keyPair, err := NewKeyPairFromString("0x1234")
+	require.NoError(t, err)
+	otherKeyPair, err := NewKeyPairFromString("0x5678")
+	require.NoError(t, err)
+	registrationMessageHashG1 := bn254utils.MapToCurve([32]byte{1})
+	otherMessageHashG1 := bn254utils.MapToCurve([32]byte{2})
+
+	params := keyPair.MakePubkeyRegistrationParams(registrationMessageHashG1)
+	assert.NoError(t, VerifyPubkeyRegistrationParams(params, registrationMessageHashG1))
+	assert.ErrorIs(t, VerifyPubkeyRegistrationParams(params, otherMessageHashG1), ErrInvalidPubkeyRegistrationParams)
+
+	tests := map[string]struct {
+		params      *PubkeyRegistrationParams
+		expectedErr error
+	}{
+		"nil params": {
+			params:      nil,
+			expectedErr: ErrNilPoint,
+		},
+		"nil G2 public key": {
+			params: &PubkeyRegistrationParams{
+				PubkeyRegistrationSignature: params.PubkeyRegistrationSignature,
+				PubkeyG1:                    params.PubkeyG1,
+			},
+			expectedErr: ErrNilPoint,
+		},
+		"G2 public key of another key": {
+			params: &PubkeyRegistrationParams{
+				PubkeyRegistrationSignature: params.PubkeyRegistrationSignature,
+				PubkeyG1:                    params.PubkeyG1,
+				PubkeyG2:                    otherKeyPair.GetPubKeyG2(),
+			},
+			expectedErr: ErrInvalidPubkeyRegistrationParams,
+		},
+		"signature of another key": {
+			params: &PubkeyRegistrationParams{
+				PubkeyRegistrationSignature: otherKeyPair.SignHashedToCurveMessage(registrationMessageHashG1),
+				PubkeyG1:                    params.PubkeyG1,
+				PubkeyG2:                    params.PubkeyG2,
+			},
+			expectedErr: ErrInvalidPubkeyRegistrationParams,
+		},
+		"signature of another message": {
+			params: &PubkeyRegistrationParams{
+				PubkeyRegistrationSignature: keyPair.SignHashedToCurveMessage(otherMessageHashG1),
+				PubkeyG1:                    params.PubkeyG1,
+				PubkeyG2:                    params.PubkeyG2,
+			},
+			expectedErr: ErrInvalidPubkeyRegistrationParams,
+		},
+		"G1 public key not on the curve": {
+			params: &PubkeyRegistrationParams{
+				PubkeyRegistrationSignature: params.PubkeyRegistrationSignature,
+				PubkeyG1:                    NewG1Point(big.NewInt(1), big.NewInt(3)),
+				PubkeyG2:                    params.PubkeyG2,
+			},
+			expectedErr: ErrInvalidPoint,
+		},
+		"zero public key": {
+			params:      NewKeyPair(new(fr.Element)).MakePubkeyRegistrationParams(registrationMessageHashG1),
+			expectedErr: ErrInvalidPubkeyRegistrationParams,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyPubkeyRegistrationParams(tc.params, registrationMessageHashG1)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.ErrorIs(t, err, ErrInvalidPubkeyRegistrationParams)
+		})
+	}
+}
+
+func TestOperatorIDFromG1Pubkey(t *testing.T) {
+	keyPair, err := NewKeyPairFromString("0x1234")
+	require.NoError(t, err)
+
+	// keccak256(abi.encodePacked(uint256 X, uint256 Y)) of BLSApkRegistry.getOperatorId
+	x := keyPair.PubKey.X.BigInt(new(big.Int))
+	y := keyPair.PubKey.Y.BigInt(new(big.Int))
+	expected := crypto.Keccak256Hash(math.U256Bytes(x), math.U256Bytes(y))
+	assert.Equal(t, [32]byte(expected), OperatorIDFromG1Pubkey(keyPair.GetPubKeyG1()))
+	assert.Equal(t, [32]byte(expected), keyPair.GetOperatorID())
+
+	// the generator has small coordinates, which are left padded
+	assert.Equal(
+		t,
+		[32]byte(crypto.Keccak256Hash(common.LeftPadBytes([]byte{1}, 32), common.LeftPadBytes([]byte{2}, 32))),
+		OperatorIDFromG1Pubkey(NewG1Point(big.NewInt(1), big.NewInt(2))),
+	)
+}
@@ -0,0 +1,165 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+var (
+	// ErrInvalidMnemonic is returned when a mnemonic isn't a valid BIP-39 mnemonic of the english wordlist, e.g. when
+	// its checksum doesn't match
+	ErrInvalidMnemonic = errors.New("invalid bip39 mnemonic")
+	// ErrInvalidDerivationPath is returned when a BIP-32 derivation path can't be parsed, e.g. when its hardened
+	// index is out of range
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	// ErrInvalidDerivedKey is returned when a BIP-32 derivation results in an invalid key, which happens with a
+	// probability lower than 1 in 2^127 for each derivation
+	ErrInvalidDerivedKey = errors.New("invalid derived key")
+)
+
+// bip32Seed is the HMAC key of the master key derivation of BIP-32
+var bip32Seed = []byte("Bitcoin seed")
+
+// DerivedKey is a key derived from a mnemonic, see DeriveKeys
+type DerivedKey struct {
+	// Path is the derivation path of the key, e.g. m/44'/60'/0'/0/0
+	Path       string
+	Address    common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// hdKey is an extended private key of BIP-32
+type hdKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+// NewKeyFromMnemonic derives the private key of derivationPath from the BIP-39 mnemonic and its passphrase, like
+// MetaMask and ethers. derivationPath is either absolute, e.g. m/44'/60'/0'/0/0, or relative to the default root
+// path m/44'/60'/0'/0, see accounts.ParseDerivationPath.
+func NewKeyFromMnemonic(mnemonic, passphrase, derivationPath string) (*ecdsa.PrivateKey, error) {
+	path, err := accounts.ParseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidDerivationPath, derivationPath, err)
+	}
+	master, err := masterKeyFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	key, err := master.derivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(key.key)
+}
+
+// DeriveKeys derives the keys of the first count indices of the default derivation path m/44'/60'/0'/0/i from the
+// BIP-39 mnemonic, without passphrase, e.g. the operator, claimer and ejector keys of a single backed up mnemonic
+func DeriveKeys(mnemonic string, count int) ([]DerivedKey, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	master, err := masterKeyFromMnemonic(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	root, err := master.derivePath(accounts.DefaultRootDerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]DerivedKey, count)
+	for i := range keys {
+		child, err := root.derive(uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := crypto.ToECDSA(child.key)
+		if err != nil {
+			return nil, err
+		}
+		path := append(accounts.DerivationPath{}, accounts.DefaultRootDerivationPath...)
+		keys[i] = DerivedKey{
+			Path:       append(path, uint32(i)).String(),
+			Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+			PrivateKey: privateKey,
+		}
+	}
+	return keys, nil
+}
+
+// masterKeyFromMnemonic returns the BIP-32 master key of the BIP-39 seed of mnemonic and passphrase
+func masterKeyFromMnemonic(mnemonic, passphrase string) (*hdKey, error) {
+	// the words may be separated by any whitespace, which the seed doesn't depend on
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		// the error of an invalid mnemonic would reveal its words
+		return nil, ErrInvalidMnemonic
+	}
+	return newMasterKey(seed)
+}
+
+// newMasterKey returns the BIP-32 master key of seed
+func newMasterKey(seed []byte) (*hdKey, error) {
+	mac := hmac.New(sha512.New, bip32Seed)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, fmt.Errorf("%w: master key is out of range", ErrInvalidDerivedKey)
+	}
+	return &hdKey{key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+func (k *hdKey) derivePath(path accounts.DerivationPath) (*hdKey, error) {
+	var err error
+	for _, index := range path {
+		k, err = k.derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// derive returns the child private key of k at index, which is hardened if index >= 2^31
+func (k *hdKey) derive(index uint32) (*hdKey, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0}, k.key...)
+	} else {
+		privateKey, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = crypto.CompressPubkey(&privateKey.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("%w: index %d", ErrInvalidDerivedKey, index)
+	}
+	key := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	key.Mod(key, n)
+	if key.Sign() == 0 {
+		return nil, fmt.Errorf("%w: index %d", ErrInvalidDerivedKey, index)
+	}
+	return &hdKey{key: math.PaddedBigBytes(key, 32), chainCode: sum[32:]}, nil
+}
@@ -0,0 +1,173 @@
+package ecdsa
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// testMnemonic is the mnemonic of the accounts of anvil and hardhat
+	testMnemonic    = "test test test test test test test test test test test junk"
+	abandonMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+)
+
+func TestNewKeyFromMnemonic(t *testing.T) {
+	// the keys derived by MetaMask and ethers
+	var tests = map[string]struct {
+		mnemonic       string
+		passphrase     string
+		derivationPath string
+		wantAddress    string
+		wantKey        string
+	}{
+		"first account": {
+			mnemonic:       testMnemonic,
+			derivationPath: "m/44'/60'/0'/0/0",
+			wantAddress:    "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+			wantKey:        "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+		},
+		"second account": {
+			mnemonic:       testMnemonic,
+			derivationPath: "m/44'/60'/0'/0/1",
+			wantAddress:    "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+			wantKey:        "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+		},
+		"relative path": {
+			mnemonic:       testMnemonic,
+			derivationPath: "2",
+			wantAddress:    "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+			wantKey:        "5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+		},
+		"abandon mnemonic": {
+			mnemonic:       abandonMnemonic,
+			derivationPath: "m/44'/60'/0'/0/0",
+			wantAddress:    "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+			wantKey:        "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727",
+		},
+		"whitespace": {
+			mnemonic:       "  test test test test test test\ttest test test test test   junk\n",
+			derivationPath: "m/44'/60'/0'/0/0",
+			wantAddress:    "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+			wantKey:        "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			privateKey, err := NewKeyFromMnemonic(tt.mnemonic, tt.passphrase, tt.derivationPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKey, hex.EncodeToString(crypto.FromECDSA(privateKey)))
+			assert.Equal(t, gethcommon.HexToAddress(tt.wantAddress), crypto.PubkeyToAddress(privateKey.PublicKey))
+		})
+	}
+
+	t.Run("passphrase", func(t *testing.T) {
+		privateKey, err := NewKeyFromMnemonic(testMnemonic, "", "m/44'/60'/0'/0/0")
+		require.NoError(t, err)
+		withPassphrase, err := NewKeyFromMnemonic(testMnemonic, "passphrase", "m/44'/60'/0'/0/0")
+		require.NoError(t, err)
+		assert.NotEqual(t, crypto.FromECDSA(privateKey), crypto.FromECDSA(withPassphrase))
+	})
+}
+
+func TestNewKeyFromMnemonicErrors(t *testing.T) {
+	var tests = map[string]struct {
+		mnemonic       string
+		derivationPath string
+		wantErr        error
+	}{
+		"invalid checksum": {
+			mnemonic:       "test test test test test test test test test test test test",
+			derivationPath: "m/44'/60'/0'/0/0",
+			wantErr:        ErrInvalidMnemonic,
+		},
+		"unknown word": {
+			mnemonic:       "test test test test test test test test test test test junkk",
+			derivationPath: "m/44'/60'/0'/0/0",
+			wantErr:        ErrInvalidMnemonic,
+		},
+		"empty mnemonic": {
+			derivationPath: "m/44'/60'/0'/0/0",
+			wantErr:        ErrInvalidMnemonic,
+		},
+		"hardened index out of range": {
+			mnemonic:       testMnemonic,
+			derivationPath: "m/44'/60'/2147483648'/0/0",
+			wantErr:        ErrInvalidDerivationPath,
+		},
+		"index out of range": {
+			mnemonic:       testMnemonic,
+			derivationPath: "m/44'/60'/0'/0/4294967296",
+			wantErr:        ErrInvalidDerivationPath,
+		},
+		"malformed path": {
+			mnemonic:       testMnemonic,
+			derivationPath: "m/44'/60'/x",
+			wantErr:        ErrInvalidDerivationPath,
+		},
+		"ambiguous path": {
+			mnemonic:       testMnemonic,
+			derivationPath: "/44'/60'/0'/0/0",
+			wantErr:        ErrInvalidDerivationPath,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewKeyFromMnemonic(tt.mnemonic, "", tt.derivationPath)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDeriveKeys(t *testing.T) {
+	keys, err := DeriveKeys(testMnemonic, 3)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	wantAddresses := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	}
+	for i, key := range keys {
+		assert.Equal(t, gethcommon.HexToAddress(wantAddresses[i]), key.Address)
+		assert.Equal(t, key.Address, crypto.PubkeyToAddress(key.PrivateKey.PublicKey))
+		privateKey, err := NewKeyFromMnemonic(testMnemonic, "", key.Path)
+		require.NoError(t, err)
+		assert.Equal(t, privateKey, key.PrivateKey)
+	}
+	assert.Equal(t, "m/44'/60'/0'/0/2", keys[2].Path)
+
+	keys, err = DeriveKeys(testMnemonic, 0)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	_, err = DeriveKeys(testMnemonic, -1)
+	require.Error(t, err)
+	_, err = DeriveKeys("test", 1)
+	require.ErrorIs(t, err, ErrInvalidMnemonic)
+}
+
+func TestBIP32Derivation(t *testing.T) {
+	// test vector 1 of BIP-32
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+	master, err := newMasterKey(seed)
+	require.NoError(t, err)
+	assert.Equal(t, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", hex.EncodeToString(master.key))
+	assert.Equal(t, "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+		hex.EncodeToString(master.chainCode))
+
+	path, err := accounts.ParseDerivationPath("m/0'/1/2'/2/1000000000")
+	require.NoError(t, err)
+	key, err := master.derivePath(path)
+	require.NoError(t, err)
+	assert.Equal(t, "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", hex.EncodeToString(key.key))
+	assert.Equal(t, "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+		hex.EncodeToString(key.chainCode))
+}
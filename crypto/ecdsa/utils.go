@@ -2,49 +2,130 @@ package ecdsa
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/ethereum/go-ethereum/common"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
 )
 
-func WriteKeyFromHex(path, privateKeyHex, password string) error {
+const (
+	// scryptR and scryptDKLen are the scrypt parameters of geth keystores which it doesn't let set
+	scryptR     = 8
+	scryptDKLen = 32
+)
+
+func WriteKeyFromHex(path, privateKeyHex, password string, opts ...KeystoreOption) error {
 	privateKey, err := crypto.HexToECDSA(privateKeyHex)
 	if err != nil {
 		return err
 	}
-	return WriteKey(path, privateKey, password)
+	return WriteKey(path, privateKey, password, opts...)
+}
+
+var (
+	// ErrInvalidPassword is returned when reading a keystore with the wrong password
+	ErrInvalidPassword = errors.New("invalid keystore password")
+	// ErrInvalidKeystore is returned when a keystore file is malformed, e.g. when its address is missing or invalid
+	ErrInvalidKeystore = errors.New("invalid keystore")
+	// ErrInvalidSignature is returned when recovering the address of a malformed ECDSA signature
+	ErrInvalidSignature = errors.New("invalid ecdsa signature")
+)
+
+type keystoreOptions struct {
+	scryptN int
+	scryptR int
+	scryptP int
+}
+
+// KeystoreOption configures the encryption of the keystores written by WriteKey
+type KeystoreOption func(*keystoreOptions)
+
+// WithScryptN sets the scrypt CPU/memory cost parameter N, a power of 2, which defaults to keystore.StandardScryptN
+func WithScryptN(n int) KeystoreOption {
+	return func(o *keystoreOptions) {
+		o.scryptN = n
+	}
+}
+
+// WithScryptR sets the scrypt block size parameter r, which defaults to 8
+func WithScryptR(r int) KeystoreOption {
+	return func(o *keystoreOptions) {
+		o.scryptR = r
+	}
+}
+
+// WithScryptP sets the scrypt parallelization parameter p, which defaults to keystore.StandardScryptP
+func WithScryptP(p int) KeystoreOption {
+	return func(o *keystoreOptions) {
+		o.scryptP = p
+	}
+}
+
+// WithLightScrypt sets the scrypt parameters to keystore.LightScryptN and keystore.LightScryptP, which are fast to
+// decrypt, e.g. for the keys of tests
+func WithLightScrypt() KeystoreOption {
+	return func(o *keystoreOptions) {
+		o.scryptN = keystore.LightScryptN
+		o.scryptP = keystore.LightScryptP
+	}
 }
 
 // WriteKey writes the private key to the given path
-// The key is encrypted using the given password
+// The key is encrypted using the given password, with the scrypt parameters of opts, which default to the standard
+// ones of geth
 // This function will create the directory if it doesn't exist
 // If there's an existing file at the given path, it will be overwritten
-func WriteKey(path string, privateKey *ecdsa.PrivateKey, password string) error {
+func WriteKey(path string, privateKey *ecdsa.PrivateKey, password string, opts ...KeystoreOption) error {
+	options := keystoreOptions{
+		scryptN: keystore.StandardScryptN,
+		scryptR: scryptR,
+		scryptP: keystore.StandardScryptP,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	UUID, err := uuid.NewRandom()
 	if err != nil {
 		return err
 	}
 
-	// We are using https://github.com/ethereum/go-ethereum/blob/master/accounts/keystore/key.go#L41
+	// We are using the format of https://github.com/ethereum/go-ethereum/blob/master/accounts/keystore/key.go#L41
 	// to store the keys which requires us to have random UUID for encryption
-	key := &keystore.Key{
-		Id:         UUID,
-		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
-		PrivateKey: privateKey,
+	cryptoParams, err := encryptKey(math.PaddedBigBytes(privateKey.D, 32), password, options)
+	if err != nil {
+		return err
 	}
-
-	encryptedBytes, err := keystore.EncryptKey(key, password, keystore.StandardScryptN, keystore.StandardScryptP)
+	encryptedBytes, err := json.Marshal(struct {
+		Address string     `json:"address"`
+		Crypto  cryptoJSON `json:"crypto"`
+		Id      string     `json:"id"`
+		Version int        `json:"version"`
+	}{
+		Address: hex.EncodeToString(crypto.PubkeyToAddress(privateKey.PublicKey).Bytes()),
+		Crypto:  cryptoParams,
+		Id:      UUID.String(),
+		Version: 3,
+	})
 	if err != nil {
 		return err
 	}
@@ -52,6 +133,78 @@ func WriteKey(path string, privateKey *ecdsa.PrivateKey, password string) error
 	return writeBytesToFile(path, encryptedBytes)
 }
 
+// GenerateAndWriteKey generates a new private key and writes it to the given path like WriteKey, returning its
+// address
+func GenerateAndWriteKey(path string, password string, opts ...KeystoreOption) (common.Address, error) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	if err := WriteKey(path, privateKey, password, opts...); err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(privateKey.PublicKey), nil
+}
+
+// cryptoJSON is keystore.CryptoJSON, whose cipher params type is unexported
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// encryptKey encrypts key like keystore.EncryptDataV3, whose scrypt parameter r can't be set
+func encryptKey(key []byte, password string, options keystoreOptions) (cryptoJSON, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return cryptoJSON{}, err
+	}
+	derivedKey, err := scrypt.Key(
+		[]byte(password),
+		salt,
+		options.scryptN,
+		options.scryptR,
+		options.scryptP,
+		scryptDKLen,
+	)
+	if err != nil {
+		return cryptoJSON{}, fmt.Errorf("invalid scrypt parameters: %w", err)
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return cryptoJSON{}, err
+	}
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return cryptoJSON{}, err
+	}
+	cipherText := make([]byte, len(key))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, key)
+
+	return cryptoJSON{
+		Cipher:       "aes-128-ctr",
+		CipherText:   hex.EncodeToString(cipherText),
+		CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+		KDF:          "scrypt",
+		KDFParams: map[string]interface{}{
+			"n":     options.scryptN,
+			"r":     options.scryptR,
+			"p":     options.scryptP,
+			"dklen": scryptDKLen,
+			"salt":  hex.EncodeToString(salt),
+		},
+		MAC: hex.EncodeToString(crypto.Keccak256(derivedKey[16:32], cipherText)),
+	}, nil
+}
+
 func writeBytesToFile(path string, data []byte) error {
 	dir := filepath.Dir(path)
 
@@ -88,6 +241,9 @@ func ReadKey(keyStoreFile string, password string) (*ecdsa.PrivateKey, error) {
 	}
 
 	sk, err := keystore.DecryptKey(keyStoreContents, password)
+	if errors.Is(err, keystore.ErrDecrypt) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPassword, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -95,26 +251,65 @@ func ReadKey(keyStoreFile string, password string) (*ecdsa.PrivateKey, error) {
 	return sk.PrivateKey, nil
 }
 
-// GetAddressFromKeyStoreFile We are using Web3 format defined by
-// https://ethereum.org/en/developers/docs/data-structures-and-encoding/web3-secret-storage/
+// GetAddressFromKeyStoreFile returns the address of a keystore without decrypting its key. We are using Web3 format
+// defined by https://ethereum.org/en/developers/docs/data-structures-and-encoding/web3-secret-storage/
+// The address must be 20 bytes of hex, with or without 0x, and its EIP-55 checksum must be valid when it's mixed case.
 func GetAddressFromKeyStoreFile(keyStoreFile string) (gethcommon.Address, error) {
 	keyJson, err := os.ReadFile(filepath.Clean(keyStoreFile))
 	if err != nil {
 		return gethcommon.Address{}, err
 	}
 
-	// The reason we have map[string]interface{} is because `address` is string but the `crypto` field is an object
-	// we don't care about the object in this method, but we still need to unmarshal it
-	m := make(map[string]interface{})
-	if err := json.Unmarshal(keyJson, &m); err != nil {
-		return gethcommon.Address{}, err
+	// Only the address is unmarshalled, the `crypto` object is skipped
+	var key struct {
+		Address *string `json:"address"`
+	}
+	if err := json.Unmarshal(keyJson, &key); err != nil {
+		return gethcommon.Address{}, utils.WrapError(ErrInvalidKeystore, err)
+	}
+	if key.Address == nil {
+		return gethcommon.Address{}, fmt.Errorf("%w: address not found in key file", ErrInvalidKeystore)
+	}
+	return parseKeystoreAddress(*key.Address)
+}
+
+// parseKeystoreAddress parses the address of a keystore, validating its EIP-55 checksum when it's mixed case
+func parseKeystoreAddress(address string) (gethcommon.Address, error) {
+	if !gethcommon.IsHexAddress(address) {
+		return gethcommon.Address{}, fmt.Errorf("%w: invalid address %q", ErrInvalidKeystore, address)
+	}
+	hexAddress := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	parsed := gethcommon.HexToAddress(address)
+	if hexAddress != strings.ToLower(hexAddress) && hexAddress != strings.ToUpper(hexAddress) &&
+		"0x"+hexAddress != parsed.Hex() {
+		return gethcommon.Address{}, fmt.Errorf("%w: invalid checksum of address %q", ErrInvalidKeystore, address)
 	}
+	return parsed, nil
+}
 
-	if address, ok := m["address"].(string); !ok {
-		return gethcommon.Address{}, errors.New("address not found in key file")
-	} else {
-		return gethcommon.HexToAddress(address), nil
+// RecoverAddress returns the address of the signer of digest, whose 65 byte signature sig is [R || S || V] with V
+// either 0/1 or 27/28. Signatures whose S is in the upper half of the curve order are rejected, like in EIP-2, since
+// they're malleable.
+func RecoverAddress(digest [32]byte, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: signature must be %d bytes, got %d", ErrInvalidSignature,
+			crypto.SignatureLength, len(sig))
+	}
+	sig = bytes.Clone(sig)
+	v := sig[crypto.RecoveryIDOffset]
+	if v == 27 || v == 28 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[crypto.RecoveryIDOffset], r, s, true) {
+		return common.Address{}, fmt.Errorf("%w: invalid signature values (v=%d)", ErrInvalidSignature, v)
+	}
+	pubKey, err := crypto.SigToPub(digest[:], sig)
+	if err != nil {
+		return common.Address{}, utils.WrapError(ErrInvalidSignature, err)
 	}
+	return crypto.PubkeyToAddress(*pubKey), nil
 }
 
 func KeyAndAddressFromHexKey(hexkey string) (*ecdsa.PrivateKey, common.Address, error) {
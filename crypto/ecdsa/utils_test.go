@@ -1,12 +1,18 @@
 package ecdsa
 
 import (
+	"bytes"
+	"encoding/json"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestECDSAPrivateKey(t *testing.T) {
@@ -48,3 +54,274 @@ func TestECDSAPrivateKey(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteKeyScryptParams(t *testing.T) {
+	var tests = map[string]struct {
+		opts    []KeystoreOption
+		wantN   int
+		wantR   int
+		wantP   int
+		wantErr bool
+	}{
+		"standard params by default": {
+			wantN: keystore.StandardScryptN,
+			wantR: 8,
+			wantP: keystore.StandardScryptP,
+		},
+		"light params": {
+			opts:  []KeystoreOption{WithLightScrypt()},
+			wantN: keystore.LightScryptN,
+			wantR: 8,
+			wantP: keystore.LightScryptP,
+		},
+		"custom params": {
+			opts:  []KeystoreOption{WithScryptN(1 << 10), WithScryptR(4), WithScryptP(2)},
+			wantN: 1 << 10,
+			wantR: 4,
+			wantP: 2,
+		},
+		"N not a power of 2": {
+			opts:    []KeystoreOption{WithScryptN(1000)},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			keyPath := filepath.Join(t.TempDir(), "test.ecdsa.key.json")
+			randomKey, err := crypto.GenerateKey()
+			require.NoError(t, err)
+
+			err = WriteKey(keyPath, randomKey, "test", tt.opts...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			keyJSON, err := os.ReadFile(keyPath)
+			require.NoError(t, err)
+			var encryptedKey struct {
+				Address string
+				Crypto  keystore.CryptoJSON
+			}
+			require.NoError(t, json.Unmarshal(keyJSON, &encryptedKey))
+			assert.Equal(t, float64(tt.wantN), encryptedKey.Crypto.KDFParams["n"])
+			assert.Equal(t, float64(tt.wantR), encryptedKey.Crypto.KDFParams["r"])
+			assert.Equal(t, float64(tt.wantP), encryptedKey.Crypto.KDFParams["p"])
+
+			readKey, err := ReadKey(keyPath, "test")
+			require.NoError(t, err)
+			assert.Equal(t, randomKey, readKey)
+
+			address, err := GetAddressFromKeyStoreFile(keyPath)
+			require.NoError(t, err)
+			assert.Equal(t, crypto.PubkeyToAddress(randomKey.PublicKey), address)
+		})
+	}
+}
+
+func TestGenerateAndWriteKey(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "keys", "test.ecdsa.key.json")
+
+	address, err := GenerateAndWriteKey(keyPath, "test", WithLightScrypt())
+	require.NoError(t, err)
+
+	readKey, err := ReadKey(keyPath, "test")
+	require.NoError(t, err)
+	assert.Equal(t, address, crypto.PubkeyToAddress(readKey.PublicKey))
+
+	_, err = ReadKey(keyPath, "wrong")
+	assert.ErrorIs(t, err, ErrInvalidPassword)
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+}
+
+func TestGetAddressFromKeyStoreFile(t *testing.T) {
+	address := gethcommon.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	var tests = map[string]struct {
+		keyJson     string
+		wantAddress gethcommon.Address
+		wantErr     error
+	}{
+		"lowercase without 0x, like geth": {
+			keyJson:     `{"address":"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed","crypto":{},"version":3}`,
+			wantAddress: address,
+		},
+		"uppercase with 0x": {
+			keyJson:     `{"address":"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED","crypto":{}}`,
+			wantAddress: address,
+		},
+		"valid checksum": {
+			keyJson:     `{"address":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed","crypto":{}}`,
+			wantAddress: address,
+		},
+		"valid checksum without 0x": {
+			keyJson:     `{"address":"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed","crypto":{}}`,
+			wantAddress: address,
+		},
+		"invalid checksum": {
+			keyJson: `{"address":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD","crypto":{}}`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"missing address": {
+			keyJson: `{"crypto":{},"version":3}`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"address is not a string": {
+			keyJson: `{"address":1234}`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"address too short": {
+			keyJson: `{"address":"5aaeb6053f3e94c9b9a09f33669435e7ef1bea"}`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"address too long": {
+			keyJson: `{"address":"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00"}`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"address is not hex": {
+			keyJson: `{"address":"5aaeb6053f3e94c9b9a09f33669435e7ef1beaeg"}`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"empty address": {
+			keyJson: `{"address":""}`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"malformed json": {
+			keyJson: `{"address":`,
+			wantErr: ErrInvalidKeystore,
+		},
+		"not an object": {
+			keyJson: `["5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"]`,
+			wantErr: ErrInvalidKeystore,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			keyPath := filepath.Join(t.TempDir(), "key.json")
+			require.NoError(t, os.WriteFile(keyPath, []byte(tt.keyJson), 0o600))
+
+			address, err := GetAddressFromKeyStoreFile(keyPath)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAddress, address)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := GetAddressFromKeyStoreFile(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestRecoverAddress(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := crypto.PubkeyToAddress(privateKey.PublicKey)
+	digest := crypto.Keccak256Hash([]byte("message"))
+	sig, err := crypto.Sign(digest[:], privateKey)
+	require.NoError(t, err)
+
+	// withV returns sig with the recovery id v
+	withV := func(v byte) []byte {
+		s := bytes.Clone(sig)
+		s[64] = v
+		return s
+	}
+	// highS returns the malleable signature (r, n - s) of sig
+	highS := func() []byte {
+		s := bytes.Clone(sig)
+		sInt := new(big.Int).SetBytes(s[32:64])
+		new(big.Int).Sub(crypto.S256().Params().N, sInt).FillBytes(s[32:64])
+		s[64] ^= 1
+		return s
+	}
+
+	var tests = map[string]struct {
+		digest      [32]byte
+		sig         []byte
+		wantAddress gethcommon.Address
+		wantErr     bool
+	}{
+		"v is 0 or 1": {
+			digest:      digest,
+			sig:         sig,
+			wantAddress: signer,
+		},
+		"v is 27 or 28": {
+			digest:      digest,
+			sig:         withV(sig[64] + 27),
+			wantAddress: signer,
+		},
+		"other digest": {
+			digest:      crypto.Keccak256Hash([]byte("other message")),
+			sig:         sig,
+			wantAddress: gethcommon.Address{},
+		},
+		"other recovery id": {
+			digest:      digest,
+			sig:         withV(sig[64] ^ 1),
+			wantAddress: gethcommon.Address{},
+		},
+		"v is 2": {
+			digest:  digest,
+			sig:     withV(2),
+			wantErr: true,
+		},
+		"v is 29": {
+			digest:  digest,
+			sig:     withV(29),
+			wantErr: true,
+		},
+		"high s": {
+			digest:  digest,
+			sig:     highS(),
+			wantErr: true,
+		},
+		"zero r and s": {
+			digest:  digest,
+			sig:     make([]byte, 65),
+			wantErr: true,
+		},
+		"too short": {
+			digest:  digest,
+			sig:     sig[:64],
+			wantErr: true,
+		},
+		"too long": {
+			digest:  digest,
+			sig:     append(bytes.Clone(sig), 0),
+			wantErr: true,
+		},
+		"empty": {
+			digest:  digest,
+			sig:     nil,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			original := bytes.Clone(tt.sig)
+			address, err := RecoverAddress(tt.digest, tt.sig)
+			assert.Equal(t, original, tt.sig, "signature must not be modified")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+				return
+			}
+			if tt.wantAddress == (gethcommon.Address{}) {
+				// a valid signature of another digest or with another recovery id recovers another address, if any
+				if err == nil {
+					assert.NotEqual(t, signer, address)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAddress, address)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package aggregation
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
+	"github.com/Layr-Labs/eigensdk-go/types"
+)
+
+// Responses delivers the responses of type R of the tasks of a service, to its response handler if any or on its
+// response channel
+type Responses[R any] struct {
+	c chan R
+	// handler handles the responses instead of c if set
+	handler        func(response R)
+	handlerTimeout time.Duration
+	// pending is the number of responses waiting to be read from c, the service being degraded from maxPending
+	pending    atomic.Int64
+	maxPending int
+	logger     logging.Logger
+}
+
+// NewResponses returns the responses of a service, which are delivered to handler if set, see Send, or on the
+// response channel otherwise. The service is reported as degraded once maxPending responses wait to be read from the
+// response channel.
+func NewResponses[R any](
+	logger logging.Logger,
+	handler func(response R),
+	handlerTimeout time.Duration,
+	maxPending int,
+) *Responses[R] {
+	return &Responses[R]{
+		c:              make(chan R),
+		handler:        handler,
+		handlerTimeout: handlerTimeout,
+		maxPending:     maxPending,
+		logger:         logger,
+	}
+}
+
+// C returns the response channel, which is closed by Close
+func (r *Responses[R]) C() <-chan R {
+	return r.c
+}
+
+// Send delivers the response of the task taskIndex to the response handler if any, or sends it on the response
+// channel otherwise, or if the response handler doesn't return within its timeout
+func (r *Responses[R]) Send(taskIndex types.TaskIndex, response R) {
+	if r.handler == nil {
+		r.sendOnChannel(response)
+		return
+	}
+
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		r.handler(response)
+	}()
+	timer := time.NewTimer(r.handlerTimeout)
+	defer timer.Stop()
+	select {
+	case <-handled:
+	case <-timer.C:
+		r.logger.Error(
+			"Response handler timed out, sending the response on the response channel instead",
+			"taskIndex", taskIndex,
+			"timeout", r.handlerTimeout,
+		)
+		r.sendOnChannel(response)
+	}
+}
+
+// sendOnChannel sends response on the response channel, counting it as pending until it's read
+func (r *Responses[R]) sendOnChannel(response R) {
+	r.pending.Add(1)
+	defer r.pending.Add(-1)
+	r.c <- response
+}
+
+// Close closes the response channel, once no response is sent anymore
+func (r *Responses[R]) Close() {
+	close(r.c)
+}
+
+// Health reports the service as degraded when the response channel is backed up, i.e. when at least maxPending
+// responses are waiting to be read from it
+func (r *Responses[R]) Health(ctx context.Context) (services.ServiceHealth, string) {
+	pending := r.pending.Load()
+	if pending >= int64(r.maxPending) {
+		return services.ServiceDegraded, fmt.Sprintf(
+			"%d responses waiting to be read from the response channel",
+			pending,
+		)
+	}
+	return services.ServiceHealthy, ""
+}
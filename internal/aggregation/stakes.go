@@ -0,0 +1,233 @@
+package aggregation
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+)
+
+// SignatureStatus is how a signature relates to the digest its signer already signed, see DigestStakes.Check
+type SignatureStatus int
+
+const (
+	// SignatureNew is the status of the first signature of a signer
+	SignatureNew SignatureStatus = iota
+	// SignatureDuplicate is the status of a signature of the digest its signer already signed, which is accepted but
+	// only counted once
+	SignatureDuplicate
+	// SignatureConflicting is the status of a signature of another digest than the one its signer already signed,
+	// which is refused
+	SignatureConflicting
+)
+
+// DigestStakes is the bookkeeping of the stake which signed each digest of a task, the signers being identified by
+// keys of type K, e.g. their operator ids. Each signer only counts towards the first digest it signs.
+type DigestStakes[K comparable] struct {
+	thresholdPercentages map[types.QuorumNum]types.QuorumThresholdPercentage
+	totalStakePerQuorum  map[types.QuorumNum]*big.Int
+	// signedDigests are the digests the signers signed
+	signedDigests map[K]types.TaskResponseDigest
+	digests       map[types.TaskResponseDigest]*digestStake[K]
+}
+
+// digestStake is the stake which signed a digest
+type digestStake[K comparable] struct {
+	signers              map[K]bool
+	signedStakePerQuorum map[types.QuorumNum]*big.Int
+}
+
+// NewDigestStakes returns the bookkeeping of a task whose quorums quorumNumbers[i] have the threshold
+// quorumThresholdPercentages[i] of their total stake totalStakePerQuorum
+func NewDigestStakes[K comparable](
+	quorumNumbers types.QuorumNums,
+	quorumThresholdPercentages types.QuorumThresholdPercentages,
+	totalStakePerQuorum map[types.QuorumNum]*big.Int,
+) *DigestStakes[K] {
+	thresholdPercentages := make(map[types.QuorumNum]types.QuorumThresholdPercentage, len(quorumNumbers))
+	for i, quorumNumber := range quorumNumbers {
+		thresholdPercentages[quorumNumber] = quorumThresholdPercentages[i]
+	}
+	return &DigestStakes[K]{
+		thresholdPercentages: thresholdPercentages,
+		totalStakePerQuorum:  totalStakePerQuorum,
+		signedDigests:        make(map[K]types.TaskResponseDigest),
+		digests:              make(map[types.TaskResponseDigest]*digestStake[K]),
+	}
+}
+
+// Check returns the status of a signature of digest by signer, along with the digest signer already signed unless
+// the signature is new
+func (s *DigestStakes[K]) Check(signer K, digest types.TaskResponseDigest) (SignatureStatus, types.TaskResponseDigest) {
+	signedDigest, signed := s.signedDigests[signer]
+	switch {
+	case !signed:
+		return SignatureNew, types.TaskResponseDigest{}
+	case signedDigest == digest:
+		return SignatureDuplicate, signedDigest
+	default:
+		return SignatureConflicting, signedDigest
+	}
+}
+
+// Add counts the stake stakePerQuorum of signer, whose signature must be new, towards digest
+func (s *DigestStakes[K]) Add(
+	signer K,
+	digest types.TaskResponseDigest,
+	stakePerQuorum map[types.QuorumNum]types.StakeAmount,
+) {
+	s.signedDigests[signer] = digest
+	stake, ok := s.digests[digest]
+	if !ok {
+		stake = &digestStake[K]{
+			signers:              make(map[K]bool),
+			signedStakePerQuorum: make(map[types.QuorumNum]*big.Int),
+		}
+		s.digests[digest] = stake
+	}
+	stake.signers[signer] = true
+	for quorumNumber, quorumStake := range stakePerQuorum {
+		if _, ok := stake.signedStakePerQuorum[quorumNumber]; !ok {
+			// the previous signers of the digest may not be part of this quorum
+			stake.signedStakePerQuorum[quorumNumber] = big.NewInt(0)
+		}
+		stake.signedStakePerQuorum[quorumNumber].Add(stake.signedStakePerQuorum[quorumNumber], quorumStake)
+	}
+}
+
+// ThresholdsMet returns whether the signers of digest met the stake threshold of each quorum of the task
+func (s *DigestStakes[K]) ThresholdsMet(digest types.TaskResponseDigest) bool {
+	stake, ok := s.digests[digest]
+	if !ok {
+		return false
+	}
+	return StakeThresholdsMet(stake.signedStakePerQuorum, s.totalStakePerQuorum, s.thresholdPercentages)
+}
+
+// SignedStakePerQuorum returns a copy of the stake of each quorum which signed digest, the quorums without signers
+// being omitted
+func (s *DigestStakes[K]) SignedStakePerQuorum(digest types.TaskResponseDigest) map[types.QuorumNum]*big.Int {
+	stake, ok := s.digests[digest]
+	if !ok {
+		return map[types.QuorumNum]*big.Int{}
+	}
+	return CloneStakePerQuorum(stake.signedStakePerQuorum)
+}
+
+// SignedStakePercentages returns the percentage of the total stake of each quorum which signed digest, the quorums
+// without stake being omitted
+func (s *DigestStakes[K]) SignedStakePercentages(digest types.TaskResponseDigest) map[types.QuorumNum]float64 {
+	var signedStakePerQuorum map[types.QuorumNum]*big.Int
+	if stake, ok := s.digests[digest]; ok {
+		signedStakePerQuorum = stake.signedStakePerQuorum
+	}
+	percentages := make(map[types.QuorumNum]float64, len(s.totalStakePerQuorum))
+	for quorumNumber, totalStake := range s.totalStakePerQuorum {
+		if totalStake.Sign() == 0 {
+			continue
+		}
+		signedStake, ok := signedStakePerQuorum[quorumNumber]
+		if !ok {
+			signedStake = big.NewInt(0)
+		}
+		percentages[quorumNumber] = SignedStakePercentage(signedStake, totalStake)
+	}
+	return percentages
+}
+
+// TotalStakePerQuorum returns a copy of the total stake of each quorum of the task
+func (s *DigestStakes[K]) TotalStakePerQuorum() map[types.QuorumNum]*big.Int {
+	return CloneStakePerQuorum(s.totalStakePerQuorum)
+}
+
+// ThresholdPercentage returns the stake threshold of the quorum quorumNumber of the task
+func (s *DigestStakes[K]) ThresholdPercentage(quorumNumber types.QuorumNum) types.QuorumThresholdPercentage {
+	return s.thresholdPercentages[quorumNumber]
+}
+
+// Signed returns whether signer signed digest
+func (s *DigestStakes[K]) Signed(signer K, digest types.TaskResponseDigest) bool {
+	signedDigest, signed := s.signedDigests[signer]
+	return signed && signedDigest == digest
+}
+
+// DigestSigners returns the signers of digest, in no particular order
+func (s *DigestStakes[K]) DigestSigners(digest types.TaskResponseDigest) []K {
+	stake, ok := s.digests[digest]
+	if !ok {
+		return nil
+	}
+	signers := make([]K, 0, len(stake.signers))
+	for signer := range stake.signers {
+		signers = append(signers, signer)
+	}
+	return signers
+}
+
+// Signers returns the signers of any digest, in no particular order
+func (s *DigestStakes[K]) Signers() []K {
+	signers := make([]K, 0, len(s.signedDigests))
+	for signer := range s.signedDigests {
+		signers = append(signers, signer)
+	}
+	return signers
+}
+
+// OtherDigests returns the signed digests other than digest, sorted
+func (s *DigestStakes[K]) OtherDigests(digest types.TaskResponseDigest) []types.TaskResponseDigest {
+	var digests []types.TaskResponseDigest
+	for otherDigest := range s.digests {
+		if otherDigest != digest {
+			digests = append(digests, otherDigest)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return bytes.Compare(digests[i][:], digests[j][:]) < 0
+	})
+	return digests
+}
+
+// StakeThresholdsMet returns whether signedStakePerQuorum is at least thresholdPercentages[quorumNumber] of the total
+// stake of each quorum, checking signedStake * 100 >= totalStake * thresholdPercentage like the contracts
+func StakeThresholdsMet(
+	signedStakePerQuorum map[types.QuorumNum]*big.Int,
+	totalStakePerQuorum map[types.QuorumNum]*big.Int,
+	thresholdPercentages map[types.QuorumNum]types.QuorumThresholdPercentage,
+) bool {
+	for quorumNumber, thresholdPercentage := range thresholdPercentages {
+		// a quorum without signers isn't met, even if its total stake is zero
+		signedStake, ok := signedStakePerQuorum[quorumNumber]
+		if !ok {
+			return false
+		}
+		totalStake, ok := totalStakePerQuorum[quorumNumber]
+		if !ok {
+			return false
+		}
+		signedStake = new(big.Int).Mul(signedStake, big.NewInt(100))
+		thresholdStake := new(big.Int).Mul(totalStake, big.NewInt(int64(thresholdPercentage)))
+		if signedStake.Cmp(thresholdStake) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SignedStakePercentage returns the percentage of totalStake that signedStake is, 0 if totalStake is 0
+func SignedStakePercentage(signedStake *big.Int, totalStake *big.Int) float64 {
+	if totalStake.Sign() == 0 {
+		return 0
+	}
+	percentage, _ := new(big.Rat).SetFrac(new(big.Int).Mul(signedStake, big.NewInt(100)), totalStake).Float64()
+	return percentage
+}
+
+// CloneStakePerQuorum returns a deep copy of stakes
+func CloneStakePerQuorum(stakes map[types.QuorumNum]types.StakeAmount) map[types.QuorumNum]types.StakeAmount {
+	out := make(map[types.QuorumNum]types.StakeAmount, len(stakes))
+	for k, v := range stakes {
+		out[k] = new(big.Int).Set(v)
+	}
+	return out
+}
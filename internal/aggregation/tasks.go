@@ -0,0 +1,204 @@
+// Package aggregation is the task machinery shared by the BLS and ECDSA aggregation services: the goroutines of the
+// in-flight tasks and the closing of the services once they are drained, the delivery of the responses of the tasks,
+// and the bookkeeping of the stake which signed each digest of a task against its thresholds.
+package aggregation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+)
+
+var (
+	// ErrClosed is returned by Tasks.Start once the tasks are closed
+	ErrClosed = errors.New("tasks closed")
+	// ErrTaskInFlight is returned by Tasks.Start for a task which is already in-flight
+	ErrTaskInFlight = errors.New("task already in-flight")
+)
+
+// Tasks are the in-flight tasks of an aggregation service, each processed by its own goroutine, which the service
+// reaches through the handle of type H of the task, e.g. the channel its signatures are sent to
+type Tasks[H any] struct {
+	handles map[types.TaskIndex]H
+	// handlesMutex protects handles and closed, so that no task goroutine is added to wg once Close waits for it
+	handlesMutex sync.RWMutex
+	closed       bool
+	wg           sync.WaitGroup
+	// cancelC is closed once the deadline of Close is reached, to cancel the tasks still in-flight
+	cancelC   chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+	// onStop is called once each task stops, see Task.Stop
+	onStop func(taskIndex types.TaskIndex, handle H)
+	logger logging.Logger
+}
+
+// Task is an in-flight task, as seen by the goroutine processing it
+type Task[H any] struct {
+	Index  types.TaskIndex
+	Handle H
+	// ExpiresAt is the time the task expires at, when ExpiredC receives
+	ExpiresAt time.Time
+	ExpiredC  <-chan time.Time
+	// CancelledC is closed once the deadline of Tasks.Close is reached, the task then having to send its response
+	// without waiting for more signatures
+	CancelledC <-chan struct{}
+	stop       func()
+}
+
+// Stop removes the handle of the task, which the service then doesn't reach anymore, e.g. so that its signatures are
+// refused while its response is delivered, since sending it may block until it is read. It only runs once, and runs
+// once the goroutine of the task returns if it wasn't called before.
+func (t *Task[H]) Stop() {
+	t.stop()
+}
+
+// NewTasks returns the tasks of a service, without any in-flight task. onStop is called once each task stops, see
+// Task.Stop, e.g. to release the resources of the task.
+func NewTasks[H any](logger logging.Logger, onStop func(taskIndex types.TaskIndex, handle H)) *Tasks[H] {
+	return &Tasks[H]{
+		handles: make(map[types.TaskIndex]H),
+		cancelC: make(chan struct{}),
+		onStop:  onStop,
+		logger:  logger,
+	}
+}
+
+// Start runs the task taskIndex in a goroutine calling run, which must return once the task completes, expires, see
+// Task.ExpiredC, or is cancelled, see Task.CancelledC. newHandle returns the handle of the task, and is called with
+// the tasks locked once the task is known to be startable, e.g. to persist the task first: the task isn't started if
+// it fails. It returns ErrClosed once Close was called and ErrTaskInFlight if the task is already in-flight.
+func (t *Tasks[H]) Start(
+	taskIndex types.TaskIndex,
+	timeToExpiry time.Duration,
+	newHandle func() (H, error),
+	run func(task *Task[H]),
+) error {
+	t.handlesMutex.Lock()
+	defer t.handlesMutex.Unlock()
+	if t.closed {
+		return ErrClosed
+	}
+	if _, inFlight := t.handles[taskIndex]; inFlight {
+		return ErrTaskInFlight
+	}
+	handle, err := newHandle()
+	if err != nil {
+		return err
+	}
+	t.handles[taskIndex] = handle
+
+	expiredTimer := time.NewTimer(timeToExpiry)
+	task := &Task[H]{
+		Index:      taskIndex,
+		Handle:     handle,
+		ExpiresAt:  time.Now().Add(timeToExpiry),
+		ExpiredC:   expiredTimer.C,
+		CancelledC: t.cancelC,
+		stop: sync.OnceFunc(func() {
+			t.handlesMutex.Lock()
+			delete(t.handles, taskIndex)
+			t.handlesMutex.Unlock()
+			if t.onStop != nil {
+				t.onStop(taskIndex, handle)
+			}
+		}),
+	}
+	t.wg.Add(1)
+	go func() {
+		// the task is only done for Close once its response is delivered
+		defer t.wg.Done()
+		defer task.Stop()
+		defer expiredTimer.Stop()
+		run(task)
+	}()
+	return nil
+}
+
+// Get returns the handle of the in-flight task taskIndex, and false if the task isn't in-flight
+func (t *Tasks[H]) Get(taskIndex types.TaskIndex) (H, bool) {
+	t.handlesMutex.RLock()
+	defer t.handlesMutex.RUnlock()
+	handle, inFlight := t.handles[taskIndex]
+	return handle, inFlight
+}
+
+// InFlight returns the number of in-flight tasks
+func (t *Tasks[H]) InFlight() int {
+	t.handlesMutex.RLock()
+	defer t.handlesMutex.RUnlock()
+	return len(t.handles)
+}
+
+// Close refuses the new tasks with ErrClosed and waits for the in-flight tasks to return, cancelling the remaining ones
+// once ctx is done, and then calls onDrained, e.g. to close the response channel of the service. It returns
+// ctx.Err() if tasks were cancelled, and nil if all the in-flight tasks returned before. It's safe to call several
+// times, the later calls waiting for the first one and returning its result.
+func (t *Tasks[H]) Close(ctx context.Context, onDrained func()) error {
+	t.closeOnce.Do(func() {
+		t.closeErr = t.close(ctx)
+		onDrained()
+		t.logger.Info("Aggregation service closed")
+	})
+	return t.closeErr
+}
+
+func (t *Tasks[H]) close(ctx context.Context) error {
+	t.handlesMutex.Lock()
+	t.closed = true
+	t.handlesMutex.Unlock()
+	t.logger.Info("Aggregation service closing", "inFlightTasks", t.InFlight())
+
+	tasksDoneC := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(tasksDoneC)
+	}()
+	select {
+	case <-tasksDoneC:
+		return nil
+	case <-ctx.Done():
+		t.logger.Warn(
+			"Aggregation service cancelling the in-flight tasks",
+			"inFlightTasks", t.InFlight(),
+			"err", ctx.Err(),
+		)
+		close(t.cancelC)
+		<-tasksDoneC
+		return ctx.Err()
+	}
+}
+
+// TaskParams returns the time to expiry and quorum threshold percentages of a task of numQuorums quorums, which are
+// the defaults of the service if unset, i.e. zero and nil. The returned percentages are a copy, which the later
+// changes of the defaults don't affect.
+func TaskParams(
+	numQuorums int,
+	timeToExpiry time.Duration,
+	quorumThresholdPercentages types.QuorumThresholdPercentages,
+	defaultTimeToExpiry time.Duration,
+	defaultQuorumThresholdPercentages types.QuorumThresholdPercentages,
+) (time.Duration, types.QuorumThresholdPercentages, error) {
+	if timeToExpiry == 0 {
+		timeToExpiry = defaultTimeToExpiry
+	}
+	if quorumThresholdPercentages == nil {
+		quorumThresholdPercentages = defaultQuorumThresholdPercentages
+	}
+	if timeToExpiry <= 0 {
+		return 0, nil, fmt.Errorf("time to expiry must be positive, got %s", timeToExpiry)
+	}
+	if len(quorumThresholdPercentages) != numQuorums {
+		return 0, nil, fmt.Errorf(
+			"got %d quorum threshold percentages for %d quorums",
+			len(quorumThresholdPercentages),
+			numQuorums,
+		)
+	}
+	return timeToExpiry, append(types.QuorumThresholdPercentages{}, quorumThresholdPercentages...), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
 	apkregistrybindings "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
 	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
 	"github.com/Layr-Labs/eigensdk-go/types"
@@ -68,6 +69,44 @@ func (f *FakeAVSRegistryReader) QueryExistingRegisteredOperatorSockets(
 	}, nil
 }
 
+func (f *FakeAVSRegistryReader) QueryExistingRegisteredOperators(
+	ctx context.Context,
+	startBlock uint64,
+	stopBlock uint64,
+	opts avsregistry.QueryOpts,
+) (map[types.OperatorId]avsregistry.OperatorInfo, error) {
+	operators := make(map[types.OperatorId]avsregistry.OperatorInfo)
+	for i, operatorAddr := range f.opAddress {
+		operators[types.OperatorIdFromG1Pubkey(f.opPubKeys[i].G1Pubkey)] = avsregistry.OperatorInfo{
+			OperatorAddr: operatorAddr,
+			Pubkeys:      f.opPubKeys[i],
+			Socket:       f.socket,
+			IsRegistered: true,
+		}
+	}
+	return operators, f.err
+}
+
+func (f *FakeAVSRegistryReader) QueryOperatorInfo(
+	ctx context.Context,
+	operatorAddr common.Address,
+	startBlock uint64,
+	stopBlock uint64,
+	opts avsregistry.QueryOpts,
+) (avsregistry.OperatorInfo, bool, error) {
+	for i, addr := range f.opAddress {
+		if addr == operatorAddr {
+			return avsregistry.OperatorInfo{
+				OperatorAddr: addr,
+				Pubkeys:      f.opPubKeys[i],
+				Socket:       f.socket,
+				IsRegistered: true,
+			}, true, f.err
+		}
+	}
+	return avsregistry.OperatorInfo{}, false, f.err
+}
+
 func (f *FakeAVSRegistryReader) GetOperatorFromId(
 	opts *bind.CallOpts,
 	operatorId types.OperatorId,
@@ -12,6 +12,8 @@ import (
 type Collector struct {
 	rpcRequestDurationSeconds *prometheus.HistogramVec
 	rpcRequestTotal           *prometheus.CounterVec
+	rateLimiterSaturation     *prometheus.GaugeVec
+	rpcBatchSize              *prometheus.HistogramVec
 }
 
 // NewCollector returns an rpccalls Collector that collects metrics for json-rpc calls
@@ -39,6 +41,25 @@ func NewCollector(avsName string, reg prometheus.Registerer) *Collector {
 			},
 			[]string{"method", "client_version"},
 		),
+		rateLimiterSaturation: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   types.EigenPromNamespace,
+				Name:        "rpc_rate_limiter_saturation",
+				Help:        "Fraction of the burst of the json-rpc <limiter> currently consumed",
+				ConstLabels: prometheus.Labels{"avs_name": avsName},
+			},
+			[]string{"limiter"},
+		),
+		rpcBatchSize: promauto.With(reg).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   types.EigenPromNamespace,
+				Name:        "rpc_batch_size",
+				Help:        "Number of requests in json-rpc batches",
+				Buckets:     prometheus.ExponentialBuckets(1, 2, 10),
+				ConstLabels: prometheus.Labels{"avs_name": avsName},
+			},
+			[]string{"client_version"},
+		),
 	}
 }
 
@@ -57,3 +78,17 @@ func (c *Collector) AddRPCRequestTotal(method, clientVersion string) {
 		"client_version": clientVersion,
 	}).Inc()
 }
+
+// ObserveRPCBatchSize observes the number of requests of a json-rpc batch
+func (c *Collector) ObserveRPCBatchSize(size int, clientVersion string) {
+	c.rpcBatchSize.With(prometheus.Labels{
+		"client_version": clientVersion,
+	}).Observe(float64(size))
+}
+
+// SetRateLimiterSaturation sets the saturation (between 0 and 1) of a client-side json-rpc rate limiter
+func (c *Collector) SetRateLimiterSaturation(saturation float64, limiter string) {
+	c.rateLimiterSaturation.With(prometheus.Labels{
+		"limiter": limiter,
+	}).Set(saturation)
+}
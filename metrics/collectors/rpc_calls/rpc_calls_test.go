@@ -23,4 +23,11 @@ func TestRpcCallsCollector(t *testing.T) {
 		1.0,
 		testutil.ToFloat64(rpcCallsCollector.rpcRequestTotal.WithLabelValues("testmethod", "testclient/testversion")),
 	)
+
+	rpcCallsCollector.SetRateLimiterSaturation(0.5, "default")
+	assert.Equal(
+		t,
+		0.5,
+		testutil.ToFloat64(rpcCallsCollector.rateLimiterSaturation.WithLabelValues("default")),
+	)
 }
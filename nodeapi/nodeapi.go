@@ -3,16 +3,24 @@ package nodeapi
 // Implementation of https://docs.eigenlayer.xyz/eigenlayer/avs-guides/spec/api/#api-versioning
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
+	"github.com/Layr-Labs/eigensdk-go/types"
 )
 
 const (
@@ -20,6 +28,8 @@ const (
 	// Spec version is the version of the avs node spec that this node is implementing
 	// see https://docs.eigenlayer.xyz/eigenlayer/avs-guides/spec/api/#api-versioning
 	specSemVer = "v0.0.1"
+	// timeout of the eth node health check made when serving /node/health
+	ethHealthCheckTimeout = 5 * time.Second
 )
 
 type NodeHealth int
@@ -52,6 +62,21 @@ type NodeApi struct {
 	nodeServices  []nodeService
 	ipPortAddr    string
 	logger        logging.Logger
+
+	// optional check of the eth node used by the avs node, see WithEthHealthCheck
+	ethHealthCheck func(ctx context.Context) (eth.NodeHealth, error)
+	// optional health of the services of the avs node, see WithHealthRegistry
+	healthRegistry *services.HealthRegistry
+	// optional export of the operators known by the avs node, see WithOperatorsInfoDebugRoute
+	operatorsInfoExporter OperatorsInfoExporter
+	// optional inspection of the tasks aggregated by the avs node, see WithTaskAggregationDebugRoute
+	taskAggregationInspector TaskAggregationInspector
+}
+
+// OperatorsInfoExporter writes the operators known by an avs node as JSON, e.g.
+// operatorsinfo.OperatorsInfoServiceInMemory
+type OperatorsInfoExporter interface {
+	WriteJSON(ctx context.Context, w io.Writer) error
 }
 
 func NewNodeApi(avsNodeName, avsNodeSemVer, IpPortAddr string, logger logging.Logger) *NodeApi {
@@ -70,6 +95,94 @@ func (api *NodeApi) UpdateHealth(health NodeHealth) {
 	api.health = health
 }
 
+// WithEthHealthCheck makes /node/health also reflect the health of the eth node behind client (see
+// eth.CheckNodeHealthWithMaxBlockAge): the node is reported partially healthy while the eth node is syncing, and
+// unhealthy when it is stale, on the wrong network or can't be reached.
+func (api *NodeApi) WithEthHealthCheck(
+	client eth.HealthCheckClient,
+	expectedChainId *big.Int,
+	maxBlockAge time.Duration,
+) *NodeApi {
+	api.ethHealthCheck = func(ctx context.Context) (eth.NodeHealth, error) {
+		return eth.CheckNodeHealthWithMaxBlockAge(ctx, client, expectedChainId, maxBlockAge)
+	}
+	return api
+}
+
+// WithHealthRegistry makes /node/health also reflect the health of the components registered on registry, as the
+// worst of them: the node is reported partially healthy while a component is degraded, and unhealthy while a
+// component is unhealthy.
+func (api *NodeApi) WithHealthRegistry(registry *services.HealthRegistry) *NodeApi {
+	api.healthRegistry = registry
+	return api
+}
+
+// TaskAggregationInspector writes the aggregation state of the in-flight tasks of an aggregator as JSON, e.g.
+// blsagg.BlsAggregatorService
+type TaskAggregationInspector interface {
+	WriteTaskAggregationStateJSON(taskIndex types.TaskIndex, w io.Writer) (found bool, err error)
+}
+
+// WithOperatorsInfoDebugRoute serves the operators written by exporter at /debug/operators, to inspect what the avs
+// node knows about the operators. The route isn't part of the node api spec.
+func (api *NodeApi) WithOperatorsInfoDebugRoute(exporter OperatorsInfoExporter) *NodeApi {
+	api.operatorsInfoExporter = exporter
+	return api
+}
+
+// WithTaskAggregationDebugRoute serves the aggregation state of the in-flight tasks written by inspector at
+// /debug/tasks/{taskIndex}, to check how close a task is to meeting its stake thresholds. The route isn't part of the
+// node api spec.
+func (api *NodeApi) WithTaskAggregationDebugRoute(inspector TaskAggregationInspector) *NodeApi {
+	api.taskAggregationInspector = inspector
+	return api
+}
+
+// currentHealth returns the health set with UpdateHealth, degraded by the health of the components of the health
+// registry and of the eth node if they are checked
+func (api *NodeApi) currentHealth(ctx context.Context) NodeHealth {
+	health := api.health
+	if api.healthRegistry != nil {
+		registryHealth, components := api.healthRegistry.Check(ctx)
+		for _, component := range components {
+			if component.Health != services.ServiceHealthy {
+				api.logger.Warn(
+					"Service is not healthy",
+					"service", component.Name,
+					"health", component.Health,
+					"reason", component.Reason,
+				)
+			}
+		}
+		switch registryHealth {
+		case services.ServiceHealthy:
+		case services.ServiceDegraded:
+			health = max(health, PartiallyHealthy)
+		default:
+			health = Unhealthy
+		}
+	}
+	if api.ethHealthCheck == nil {
+		return health
+	}
+	ctx, cancel := context.WithTimeout(ctx, ethHealthCheckTimeout)
+	defer cancel()
+	ethHealth, err := api.ethHealthCheck(ctx)
+	if err != nil {
+		api.logger.Error("Failed to check the eth node health", "err", err)
+		return Unhealthy
+	}
+	switch ethHealth.Status {
+	case eth.NodeStatusHealthy:
+		return health
+	case eth.NodeStatusSyncing:
+		return max(health, PartiallyHealthy)
+	default:
+		api.logger.Warn("Eth node is unhealthy", "status", ethHealth.Status, "latestBlockAge", ethHealth.LatestBlockAge)
+		return Unhealthy
+	}
+}
+
 func (api *NodeApi) RegisterNewService(serviceId, serviceName, serviceDescription string, serviceStatus ServiceStatus) {
 	api.nodeServices = append(api.nodeServices, nodeService{
 		Id:          serviceId,
@@ -115,6 +228,12 @@ func (api *NodeApi) Start() <-chan error {
 	// Note: You'll need to extract the service_ID from the URL
 	// /node/services/{service_ID}/health
 	mux.HandleFunc(baseUrl+"/node/services/", api.serviceHealthHandler)
+	if api.operatorsInfoExporter != nil {
+		mux.HandleFunc(baseUrl+"/debug/operators", api.operatorsInfoHandler)
+	}
+	if api.taskAggregationInspector != nil {
+		mux.HandleFunc(baseUrl+"/debug/tasks/", api.taskAggregationHandler)
+	}
 
 	errChan := run(api.logger, &httpServer)
 	return errChan
@@ -135,7 +254,8 @@ func (api *NodeApi) nodeHandler(w http.ResponseWriter, r *http.Request) {
 
 // https://docs.eigenlayer.xyz/eigenlayer/avs-guides/spec/api/#get-eigennodehealth
 func (api *NodeApi) healthHandler(w http.ResponseWriter, r *http.Request) {
-	switch api.health {
+	health := api.currentHealth(r.Context())
+	switch health {
 	case Healthy:
 		// 200 - Node is healthy
 		w.WriteHeader(http.StatusOK)
@@ -148,7 +268,7 @@ func (api *NodeApi) healthHandler(w http.ResponseWriter, r *http.Request) {
 	default:
 		// we still return unhealthy if we don't know the health status, to prevent caller from hanging
 		w.WriteHeader(http.StatusServiceUnavailable)
-		api.logger.Error("Unknown health status", "health", api.health)
+		api.logger.Error("Unknown health status", "health", health)
 	}
 }
 
@@ -198,6 +318,49 @@ func (api *NodeApi) serviceHealthHandler(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNotFound)
 }
 
+func (api *NodeApi) operatorsInfoHandler(w http.ResponseWriter, r *http.Request) {
+	// buffer the operators so that an error can still be reported with the status code
+	var buf bytes.Buffer
+	if err := api.operatorsInfoExporter.WriteJSON(r.Context(), &buf); err != nil {
+		api.logger.Error("Error in operatorsInfoHandler", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := buf.WriteTo(w); err != nil {
+		api.logger.Error("Error in operatorsInfoHandler", "err", err)
+	}
+}
+
+func (api *NodeApi) taskAggregationHandler(w http.ResponseWriter, r *http.Request) {
+	taskIndexStr, found := strings.CutPrefix(r.URL.Path, baseUrl+"/debug/tasks/")
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	taskIndex, err := strconv.ParseUint(taskIndexStr, 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// buffer the state so that an error can still be reported with the status code
+	var buf bytes.Buffer
+	found, err = api.taskAggregationInspector.WriteTaskAggregationStateJSON(types.TaskIndex(taskIndex), &buf)
+	if err != nil {
+		api.logger.Error("Error in taskAggregationHandler", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := buf.WriteTo(w); err != nil {
+		api.logger.Error("Error in taskAggregationHandler", "err", err)
+	}
+}
+
 func run(logger logging.Logger, httpServer *http.Server) <-chan error {
 	errChan := make(chan error, 1)
 	ctx, stop := signal.NotifyContext(
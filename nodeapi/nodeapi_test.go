@@ -1,13 +1,19 @@
 package nodeapi
 
 import (
+	"context"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/services"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,6 +21,23 @@ import (
 var logger = testutils.GetTestLogger()
 var testNodeApi = NewNodeApi("testAvs", "v0.0.1", "localhost:8080", logger)
 
+// fakeEthNode is an eth node on chain 1, which is synced unless syncProgress is set
+type fakeEthNode struct {
+	syncProgress *ethereum.SyncProgress
+}
+
+func (n *fakeEthNode) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (n *fakeEthNode) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	return n.syncProgress, nil
+}
+
+func (n *fakeEthNode) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Time: uint64(time.Now().Unix())}, nil
+}
+
 // just making sure that the nodeapi starts without any errors
 func TestStart(t *testing.T) {
 	errC := testNodeApi.Start()
@@ -177,3 +200,180 @@ func TestServiceHealthHandler(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, res.StatusCode)
 	assert.Equal(t, "", string(data))
 }
+
+// fakeHealthReporter is a service reporting the health it is set to
+type fakeHealthReporter struct {
+	health services.ServiceHealth
+}
+
+func (r *fakeHealthReporter) Name() string {
+	return "fakeService"
+}
+
+func (r *fakeHealthReporter) Health(ctx context.Context) (services.ServiceHealth, string) {
+	return r.health, r.health.String()
+}
+
+func TestHealthHandlerWithHealthRegistry(t *testing.T) {
+	service := &fakeHealthReporter{}
+	ethNode := &fakeEthNode{}
+	registry := services.NewHealthRegistry()
+	registry.Register(service)
+	registry.Register(eth.NewNodeHealthReporter("eth", ethNode, big.NewInt(1), eth.DefaultMaxBlockAge))
+	nodeApi := NewNodeApi("testAvs", "v0.0.1", "localhost:8080", logger).WithHealthRegistry(registry)
+
+	// the steps flip the health of the components in sequence
+	steps := []struct {
+		name           string
+		serviceHealth  services.ServiceHealth
+		ethNodeSyncing bool
+		wantStatusCode int
+	}{
+		{
+			name:           "all components healthy",
+			serviceHealth:  services.ServiceHealthy,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "service degraded",
+			serviceHealth:  services.ServiceDegraded,
+			wantStatusCode: http.StatusPartialContent,
+		},
+		{
+			name:           "service unhealthy and eth node syncing",
+			serviceHealth:  services.ServiceUnhealthy,
+			ethNodeSyncing: true,
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "service recovered and eth node syncing",
+			serviceHealth:  services.ServiceHealthy,
+			ethNodeSyncing: true,
+			wantStatusCode: http.StatusPartialContent,
+		},
+		{
+			name:           "all components recovered",
+			serviceHealth:  services.ServiceHealthy,
+			wantStatusCode: http.StatusOK,
+		},
+	}
+	for _, step := range steps {
+		service.health = step.serviceHealth
+		ethNode.syncProgress = nil
+		if step.ethNodeSyncing {
+			ethNode.syncProgress = &ethereum.SyncProgress{CurrentBlock: 10, HighestBlock: 100}
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/eigen/node/health", nil)
+		w := httptest.NewRecorder()
+		nodeApi.healthHandler(w, req)
+		res := w.Result()
+		res.Body.Close()
+		assert.Equal(t, step.wantStatusCode, res.StatusCode, step.name)
+	}
+}
+
+// fakeOperatorsInfoExporter writes operators, or fails with err if set
+type fakeOperatorsInfoExporter struct {
+	operators string
+	err       error
+}
+
+func (e *fakeOperatorsInfoExporter) WriteJSON(ctx context.Context, w io.Writer) error {
+	if e.err != nil {
+		return e.err
+	}
+	_, err := io.WriteString(w, e.operators)
+	return err
+}
+
+func TestOperatorsInfoHandler(t *testing.T) {
+	exporter := &fakeOperatorsInfoExporter{operators: "{\"lastProcessedBlock\":10,\"operators\":{}}\n"}
+	nodeApi := NewNodeApi("testAvs", "v0.0.1", "localhost:8080", logger).WithOperatorsInfoDebugRoute(exporter)
+
+	req := httptest.NewRequest(http.MethodGet, "/eigen/debug/operators", nil)
+	w := httptest.NewRecorder()
+	nodeApi.operatorsInfoHandler(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Equal(t, exporter.operators, string(data))
+
+	exporter.err = context.DeadlineExceeded
+	w = httptest.NewRecorder()
+	nodeApi.operatorsInfoHandler(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+}
+
+// fakeTaskAggregationInspector writes the states of its tasks, or fails with err if set
+type fakeTaskAggregationInspector struct {
+	states map[uint32]string
+	err    error
+}
+
+func (i *fakeTaskAggregationInspector) WriteTaskAggregationStateJSON(taskIndex uint32, w io.Writer) (bool, error) {
+	if i.err != nil {
+		return false, i.err
+	}
+	state, ok := i.states[taskIndex]
+	if !ok {
+		return false, nil
+	}
+	_, err := io.WriteString(w, state)
+	return true, err
+}
+
+func TestTaskAggregationHandler(t *testing.T) {
+	inspector := &fakeTaskAggregationInspector{
+		states: map[uint32]string{42: "{\"taskIndex\":42}\n"},
+	}
+	nodeApi := NewNodeApi("testAvs", "v0.0.1", "localhost:8080", logger).WithTaskAggregationDebugRoute(inspector)
+
+	var tests = []struct {
+		name           string
+		path           string
+		err            error
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:           "in-flight task",
+			path:           "/eigen/debug/tasks/42",
+			wantStatusCode: http.StatusOK,
+			wantBody:       "{\"taskIndex\":42}\n",
+		},
+		{
+			name:           "unknown task",
+			path:           "/eigen/debug/tasks/43",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid task index",
+			path:           "/eigen/debug/tasks/abc",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "inspector error",
+			path:           "/eigen/debug/tasks/42",
+			err:            context.DeadlineExceeded,
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector.err = tt.err
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			nodeApi.taskAggregationHandler(w, req)
+			res := w.Result()
+			defer res.Body.Close()
+			data, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatusCode, res.StatusCode)
+			assert.Equal(t, tt.wantBody, string(data))
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package avsregistry
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"math/big"
+	"sort"
 
 	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 )
 
@@ -19,6 +25,14 @@ type AvsRegistryService interface {
 		quorumNumbers types.QuorumNums,
 		blockNumber types.BlockNum,
 	) (map[types.OperatorId]types.OperatorAvsState, error)
+	// GetOperatorsAvsStateAtBlockWithOpts is GetOperatorsAvsStateAtBlock restricted to the operators of opts, which
+	// only resolves the info of these operators, e.g. the signers and non-signers of a task.
+	GetOperatorsAvsStateAtBlockWithOpts(
+		ctx context.Context,
+		quorumNumbers types.QuorumNums,
+		blockNumber types.BlockNum,
+		opts OperatorsAvsStateOpts,
+	) (map[types.OperatorId]types.OperatorAvsState, error)
 	// GetQuorumsAvsStateAtBlock returns the aggregated data for a list of quorums at a certain block.
 	// The aggregated data includes the aggregated pubkey and total stake in each quorum.
 	// This information is derivable from the Operators Avs State (returned from GetOperatorsAvsStateAtBlock), but this
@@ -37,3 +51,94 @@ type AvsRegistryService interface {
 		nonSignerOperatorIds []types.OperatorId,
 	) (opstateretriever.OperatorStateRetrieverCheckSignaturesIndices, error)
 }
+
+// OperatorsAvsStateOpts are the options of GetOperatorsAvsStateAtBlockWithOpts
+type OperatorsAvsStateOpts struct {
+	// OperatorIds restricts the returned state to these operators, the ones not registered in any of the quorums
+	// being left out. The state of all the operators of the quorums is returned if nil.
+	OperatorIds []types.OperatorId
+}
+
+// filterOperatorsAvsState returns the state of the operators of operatorsAvsState which are in operatorIds
+func filterOperatorsAvsState(
+	operatorsAvsState map[types.OperatorId]types.OperatorAvsState,
+	operatorIds []types.OperatorId,
+) map[types.OperatorId]types.OperatorAvsState {
+	filteredOperatorsAvsState := make(map[types.OperatorId]types.OperatorAvsState, len(operatorIds))
+	for _, operatorId := range operatorIds {
+		if operatorAvsState, ok := operatorsAvsState[operatorId]; ok {
+			filteredOperatorsAvsState[operatorId] = operatorAvsState
+		}
+	}
+	return filteredOperatorsAvsState
+}
+
+// MissingOperatorPubkeyError is returned by ComputeQuorumAPK for an operator without G1 pubkey
+type MissingOperatorPubkeyError struct {
+	OperatorId types.OperatorId
+}
+
+func (e *MissingOperatorPubkeyError) Error() string {
+	return fmt.Sprintf("operator %s has no G1 pubkey", e.OperatorId)
+}
+
+// ComputeQuorumAPK returns the aggregate pubkey of a quorum, the sum of the G1 pubkeys of its operators, which is the
+// point at infinity for a quorum without operators. It returns a MissingOperatorPubkeyError for the first operator
+// without G1 pubkey, if any.
+func ComputeQuorumAPK(operators []types.OperatorAvsState) (*bls.G1Point, error) {
+	if len(operators) == 0 {
+		return bls.NewZeroG1Point(), nil
+	}
+	pubkeys := make([]*bls.G1Point, len(operators))
+	for i, operator := range operators {
+		pubkey := operator.OperatorInfo.Pubkeys.G1Pubkey
+		if pubkey == nil || pubkey.G1Affine == nil {
+			return nil, &MissingOperatorPubkeyError{OperatorId: operator.OperatorId}
+		}
+		pubkeys[i] = pubkey
+	}
+	return bls.AggregateG1Points(pubkeys)
+}
+
+// QuorumsAvsStateFromOperatorsAvsState aggregates the pubkeys and stakes of operatorsAvsState, the state of the
+// operators at blockNumber returned by GetOperatorsAvsStateAtBlock, into the state of each of quorumNumbers. The
+// aggregate pubkeys are computed with ComputeQuorumAPK.
+func QuorumsAvsStateFromOperatorsAvsState(
+	quorumNumbers types.QuorumNums,
+	blockNumber types.BlockNum,
+	operatorsAvsState map[types.OperatorId]types.OperatorAvsState,
+) (map[types.QuorumNum]types.QuorumAvsState, error) {
+	// the operators are sorted by id, so that the same operator is reported if several have no pubkey
+	operatorIds := make([]types.OperatorId, 0, len(operatorsAvsState))
+	for operatorId := range operatorsAvsState {
+		operatorIds = append(operatorIds, operatorId)
+	}
+	sort.Slice(operatorIds, func(i, j int) bool {
+		return bytes.Compare(operatorIds[i][:], operatorIds[j][:]) < 0
+	})
+
+	quorumsAvsState := make(map[types.QuorumNum]types.QuorumAvsState)
+	for _, quorumNum := range quorumNumbers {
+		var quorumOperators []types.OperatorAvsState
+		totalStake := big.NewInt(0)
+		for _, operatorId := range operatorIds {
+			operator := operatorsAvsState[operatorId]
+			// only include operators that have a stake in this quorum
+			if stake, ok := operator.StakePerQuorum[quorumNum]; ok {
+				quorumOperators = append(quorumOperators, operator)
+				totalStake.Add(totalStake, stake)
+			}
+		}
+		aggPubkeyG1, err := ComputeQuorumAPK(quorumOperators)
+		if err != nil {
+			return nil, utils.WrapError(fmt.Sprintf("failed to compute aggregate pubkey of quorum %d", quorumNum), err)
+		}
+		quorumsAvsState[quorumNum] = types.QuorumAvsState{
+			QuorumNumber: quorumNum,
+			AggPubkeyG1:  aggPubkeyG1,
+			TotalStake:   totalStake,
+			BlockNumber:  blockNumber,
+		}
+	}
+	return quorumsAvsState, nil
+}
@@ -7,8 +7,8 @@ import (
 
 	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 
-	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	opinfoservice "github.com/Layr-Labs/eigensdk-go/services/operatorsinfo"
 	"github.com/Layr-Labs/eigensdk-go/types"
@@ -36,32 +36,166 @@ type avsRegistryReader interface {
 	) (opstateretriever.OperatorStateRetrieverCheckSignaturesIndices, error)
 }
 
+const (
+	// DefaultOperatorsStateCacheSize is the number of operators states the service caches unless set with
+	// WithOperatorsStateCacheSize
+	DefaultOperatorsStateCacheSize = 64
+	// DefaultOperatorAddrCacheSize is the number of operator addresses the service caches unless set with
+	// WithOperatorAddrCacheSize
+	DefaultOperatorAddrCacheSize = 4096
+)
+
 // AvsRegistryServiceChainCaller is a wrapper around Reader that transforms the data into
 // nicer golang types that are easier to work with
+// The operators state at each block is cached, which never needs to be invalidated since the state at past blocks
+// doesn't change.
 type AvsRegistryServiceChainCaller struct {
 	avsRegistryReader
 	operatorInfoService opinfoservice.OperatorsInfoService
 	logger              logging.Logger
+	metrics             Metrics
+
+	operatorsStateCacheSize int
+	operatorAddrCacheSize   int
+	// operatorsStateCache is nil if the operators states aren't cached
+	operatorsStateCache *lru.Cache[operatorsStateCacheKey, map[types.OperatorId]types.OperatorAvsState]
+	// operatorAddrCache holds the addresses of the operator ids, which never change once registered unlike the info
+	// of the operators, e.g. their sockets. It is nil if the operator addresses aren't cached.
+	operatorAddrCache *lru.Cache[types.OperatorId, common.Address]
 }
 
 var _ AvsRegistryService = (*AvsRegistryServiceChainCaller)(nil)
 
+type operatorsStateCacheKey struct {
+	blockNumber types.BlockNum
+	// quorumNumbers are the bytes of the quorum numbers, in the order they were queried
+	quorumNumbers string
+}
+
+type AvsRegistryServiceChainCallerOption func(*AvsRegistryServiceChainCaller)
+
+// WithOperatorsStateCacheSize sets the number of operators states, one per block and quorum numbers, the service
+// caches, DefaultOperatorsStateCacheSize unless set. They aren't cached if size is 0.
+func WithOperatorsStateCacheSize(size int) AvsRegistryServiceChainCallerOption {
+	return func(ar *AvsRegistryServiceChainCaller) {
+		ar.operatorsStateCacheSize = size
+	}
+}
+
+// WithOperatorAddrCacheSize sets the number of operator addresses, one per operator id, the service caches,
+// DefaultOperatorAddrCacheSize unless set. They aren't cached if size is 0.
+func WithOperatorAddrCacheSize(size int) AvsRegistryServiceChainCallerOption {
+	return func(ar *AvsRegistryServiceChainCaller) {
+		ar.operatorAddrCacheSize = size
+	}
+}
+
+// WithMetrics sets the metrics of the service, NoopMetrics unless set
+func WithMetrics(metrics Metrics) AvsRegistryServiceChainCallerOption {
+	return func(ar *AvsRegistryServiceChainCaller) {
+		ar.metrics = metrics
+	}
+}
+
 func NewAvsRegistryServiceChainCaller(
 	reader avsRegistryReader,
 	operatorInfoService opinfoservice.OperatorsInfoService,
 	logger logging.Logger,
+	opts ...AvsRegistryServiceChainCallerOption,
 ) *AvsRegistryServiceChainCaller {
-	return &AvsRegistryServiceChainCaller{
-		avsRegistryReader:   reader,
-		operatorInfoService: operatorInfoService,
-		logger:              logger,
+	ar := &AvsRegistryServiceChainCaller{
+		avsRegistryReader:       reader,
+		operatorInfoService:     operatorInfoService,
+		logger:                  logger,
+		metrics:                 NewNoopMetrics(),
+		operatorsStateCacheSize: DefaultOperatorsStateCacheSize,
+		operatorAddrCacheSize:   DefaultOperatorAddrCacheSize,
+	}
+	for _, opt := range opts {
+		opt(ar)
+	}
+	if ar.operatorsStateCacheSize > 0 {
+		ar.operatorsStateCache = lru.NewCache[operatorsStateCacheKey, map[types.OperatorId]types.OperatorAvsState](
+			ar.operatorsStateCacheSize,
+		)
 	}
+	if ar.operatorAddrCacheSize > 0 {
+		ar.operatorAddrCache = lru.NewCache[types.OperatorId, common.Address](ar.operatorAddrCacheSize)
+	}
+	return ar
 }
 
 func (ar *AvsRegistryServiceChainCaller) GetOperatorsAvsStateAtBlock(
 	ctx context.Context,
 	quorumNumbers types.QuorumNums,
 	blockNumber types.BlockNum,
+) (map[types.OperatorId]types.OperatorAvsState, error) {
+	if ar.operatorsStateCache == nil {
+		return ar.getOperatorsAvsStateAtBlock(ctx, quorumNumbers, blockNumber)
+	}
+	key := operatorsStateCacheKey{
+		blockNumber:   blockNumber,
+		quorumNumbers: string(quorumNumbers.UnderlyingType()),
+	}
+	if operatorsAvsState, ok := ar.operatorsStateCache.Get(key); ok {
+		ar.metrics.IncrementCacheHits(operatorsStateCache)
+		return copyOperatorsAvsState(operatorsAvsState), nil
+	}
+	ar.metrics.IncrementCacheMisses(operatorsStateCache)
+	operatorsAvsState, err := ar.getOperatorsAvsStateAtBlock(ctx, quorumNumbers, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	// the cached state is a copy, which the callers can't modify
+	ar.operatorsStateCache.Add(key, copyOperatorsAvsState(operatorsAvsState))
+	return operatorsAvsState, nil
+}
+
+// GetOperatorsAvsStateAtBlockWithOpts is GetOperatorsAvsStateAtBlock restricted to the operators of opts. The stakes
+// of all the operators are still queried in a single call, but only the info of the operators of opts is resolved,
+// unless the state of all the operators is cached. The restricted state isn't cached.
+func (ar *AvsRegistryServiceChainCaller) GetOperatorsAvsStateAtBlockWithOpts(
+	ctx context.Context,
+	quorumNumbers types.QuorumNums,
+	blockNumber types.BlockNum,
+	opts OperatorsAvsStateOpts,
+) (map[types.OperatorId]types.OperatorAvsState, error) {
+	if opts.OperatorIds == nil {
+		return ar.GetOperatorsAvsStateAtBlock(ctx, quorumNumbers, blockNumber)
+	}
+	if ar.operatorsStateCache != nil {
+		key := operatorsStateCacheKey{
+			blockNumber:   blockNumber,
+			quorumNumbers: string(quorumNumbers.UnderlyingType()),
+		}
+		if operatorsAvsState, ok := ar.operatorsStateCache.Get(key); ok {
+			ar.metrics.IncrementCacheHits(operatorsStateCache)
+			return copyOperatorsAvsState(filterOperatorsAvsState(operatorsAvsState, opts.OperatorIds)), nil
+		}
+	}
+	operatorIds := make(map[types.OperatorId]bool, len(opts.OperatorIds))
+	for _, operatorId := range opts.OperatorIds {
+		operatorIds[operatorId] = true
+	}
+	return ar.queryOperatorsAvsStateAtBlock(ctx, quorumNumbers, blockNumber, operatorIds)
+}
+
+// getOperatorsAvsStateAtBlock queries the state of the operators of quorumNumbers at blockNumber from the chain
+func (ar *AvsRegistryServiceChainCaller) getOperatorsAvsStateAtBlock(
+	ctx context.Context,
+	quorumNumbers types.QuorumNums,
+	blockNumber types.BlockNum,
+) (map[types.OperatorId]types.OperatorAvsState, error) {
+	return ar.queryOperatorsAvsStateAtBlock(ctx, quorumNumbers, blockNumber, nil)
+}
+
+// queryOperatorsAvsStateAtBlock queries the state of the operators of quorumNumbers at blockNumber from the chain,
+// restricted to operatorIds unless it is nil
+func (ar *AvsRegistryServiceChainCaller) queryOperatorsAvsStateAtBlock(
+	ctx context.Context,
+	quorumNumbers types.QuorumNums,
+	blockNumber types.BlockNum,
+	operatorIds map[types.OperatorId]bool,
 ) (map[types.OperatorId]types.OperatorAvsState, error) {
 	operatorsAvsState := make(map[types.OperatorId]types.OperatorAvsState)
 	// Get operator state for each quorum by querying BLSOperatorStateRetriever (this call is why this service
@@ -85,13 +219,19 @@ func (ar *AvsRegistryServiceChainCaller) GetOperatorsAvsStateAtBlock(
 
 	for quorumIdx, quorumNum := range quorumNumbers {
 		for _, operator := range operatorsStakesInQuorums[quorumIdx] {
-			info, err := ar.getOperatorInfo(ctx, operator.OperatorId)
-			if err != nil {
-				return nil, utils.WrapError("Failed to find pubkeys for operator while building operatorsAvsState", err)
+			if operatorIds != nil && !operatorIds[operator.OperatorId] {
+				continue
 			}
 			if operatorAvsState, ok := operatorsAvsState[operator.OperatorId]; ok {
 				operatorAvsState.StakePerQuorum[quorumNum] = operator.Stake
 			} else {
+				info, err := ar.getOperatorInfo(ctx, operator.OperatorId)
+				if err != nil {
+					return nil, utils.WrapError(
+						"Failed to find pubkeys for operator while building operatorsAvsState",
+						err,
+					)
+				}
 				stakePerQuorum := make(map[types.QuorumNum]types.StakeAmount)
 				stakePerQuorum[quorumNum] = operator.Stake
 				operatorsAvsState[operator.OperatorId] = types.OperatorAvsState{
@@ -117,34 +257,44 @@ func (ar *AvsRegistryServiceChainCaller) GetQuorumsAvsStateAtBlock(
 	if err != nil {
 		return nil, utils.WrapError("Failed to get quorum state", err)
 	}
-	quorumsAvsState := make(map[types.QuorumNum]types.QuorumAvsState)
-	for _, quorumNum := range quorumNumbers {
-		aggPubkeyG1 := bls.NewG1Point(big.NewInt(0), big.NewInt(0))
-		totalStake := big.NewInt(0)
-		for _, operator := range operatorsAvsState {
-			// only include operators that have a stake in this quorum
-			if stake, ok := operator.StakePerQuorum[quorumNum]; ok {
-				aggPubkeyG1.Add(operator.OperatorInfo.Pubkeys.G1Pubkey)
-				totalStake.Add(totalStake, stake)
-			}
-		}
-		quorumsAvsState[quorumNum] = types.QuorumAvsState{
-			QuorumNumber: quorumNum,
-			AggPubkeyG1:  aggPubkeyG1,
-			TotalStake:   totalStake,
-			BlockNumber:  blockNumber,
-		}
+	quorumsAvsState, err := QuorumsAvsStateFromOperatorsAvsState(quorumNumbers, blockNumber, operatorsAvsState)
+	if err != nil {
+		return nil, utils.WrapError("Failed to get quorum state", err)
 	}
 	return quorumsAvsState, nil
 }
 
+// getOperatorAddr returns the address of the operator operatorId, which is only queried once per operator id
+func (ar *AvsRegistryServiceChainCaller) getOperatorAddr(
+	ctx context.Context,
+	operatorId types.OperatorId,
+) (common.Address, error) {
+	if ar.operatorAddrCache != nil {
+		if operatorAddr, ok := ar.operatorAddrCache.Get(operatorId); ok {
+			ar.metrics.IncrementCacheHits(operatorAddrCache)
+			return operatorAddr, nil
+		}
+		ar.metrics.IncrementCacheMisses(operatorAddrCache)
+	}
+	operatorAddr, err := ar.avsRegistryReader.GetOperatorFromId(&bind.CallOpts{Context: ctx}, operatorId)
+	if err != nil {
+		return common.Address{}, utils.WrapError("Failed to get operator address from pubkey hash", err)
+	}
+	// the operator ids which aren't registered yet have no address
+	if ar.operatorAddrCache != nil && operatorAddr != (common.Address{}) {
+		ar.operatorAddrCache.Add(operatorId, operatorAddr)
+	}
+	return operatorAddr, nil
+}
+
+// getOperatorInfo returns the current info of the operator operatorId in the operators info service
 func (ar *AvsRegistryServiceChainCaller) getOperatorInfo(
 	ctx context.Context,
 	operatorId types.OperatorId,
 ) (types.OperatorInfo, error) {
-	operatorAddr, err := ar.avsRegistryReader.GetOperatorFromId(&bind.CallOpts{Context: ctx}, operatorId)
+	operatorAddr, err := ar.getOperatorAddr(ctx, operatorId)
 	if err != nil {
-		return types.OperatorInfo{}, utils.WrapError("Failed to get operator address from pubkey hash", err)
+		return types.OperatorInfo{}, err
 	}
 	info, ok := ar.operatorInfoService.GetOperatorInfo(ctx, operatorAddr)
 	if !ok {
@@ -156,3 +306,19 @@ func (ar *AvsRegistryServiceChainCaller) getOperatorInfo(
 	}
 	return info, nil
 }
+
+// copyOperatorsAvsState returns a copy of operatorsAvsState with their own stakes
+func copyOperatorsAvsState(
+	operatorsAvsState map[types.OperatorId]types.OperatorAvsState,
+) map[types.OperatorId]types.OperatorAvsState {
+	operatorsAvsStateCopy := make(map[types.OperatorId]types.OperatorAvsState, len(operatorsAvsState))
+	for operatorId, operatorAvsState := range operatorsAvsState {
+		stakePerQuorum := make(map[types.QuorumNum]types.StakeAmount, len(operatorAvsState.StakePerQuorum))
+		for quorumNum, stake := range operatorAvsState.StakePerQuorum {
+			stakePerQuorum[quorumNum] = new(big.Int).Set(stake)
+		}
+		operatorAvsState.StakePerQuorum = stakePerQuorum
+		operatorsAvsStateCopy[operatorId] = operatorAvsState
+	}
+	return operatorsAvsStateCopy
+}
@@ -9,11 +9,15 @@ import (
 	"reflect"
 	"testing"
 
+	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/internal/fakes"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -258,3 +262,359 @@ func TestAvsRegistryServiceChainCaller_GetQuorumsAvsState(t *testing.T) {
 		})
 	}
 }
+
+// countingAvsRegistryReader counts the chain queries of the avs registry reader it wraps
+type countingAvsRegistryReader struct {
+	*fakes.FakeAVSRegistryReader
+	operatorsStakeQueries int
+	operatorFromIdQueries int
+}
+
+func (r *countingAvsRegistryReader) GetOperatorsStakeInQuorumsAtBlock(
+	opts *bind.CallOpts,
+	quorumNumbers types.QuorumNums,
+	blockNumber types.BlockNum,
+) ([][]opstateretriever.OperatorStateRetrieverOperator, error) {
+	r.operatorsStakeQueries++
+	return r.FakeAVSRegistryReader.GetOperatorsStakeInQuorumsAtBlock(opts, quorumNumbers, blockNumber)
+}
+
+func (r *countingAvsRegistryReader) GetOperatorFromId(
+	opts *bind.CallOpts,
+	operatorId types.OperatorId,
+) (common.Address, error) {
+	r.operatorFromIdQueries++
+	return r.FakeAVSRegistryReader.GetOperatorFromId(opts, operatorId)
+}
+
+func TestAvsRegistryServiceChainCaller_CachesOperatorsAvsState(t *testing.T) {
+	logger := testutils.GetTestLogger()
+	testOperator1 := fakes.TestOperator{
+		OperatorAddr: common.HexToAddress("0x1"),
+		OperatorId:   types.OperatorId{1},
+		OperatorInfo: types.OperatorInfo{
+			Pubkeys: types.OperatorPubkeys{
+				G1Pubkey: bls.NewG1Point(big.NewInt(1), big.NewInt(1)),
+				G2Pubkey: bls.NewG2Point(
+					[2]*big.Int{big.NewInt(1), big.NewInt(1)},
+					[2]*big.Int{big.NewInt(1), big.NewInt(1)},
+				),
+			},
+			Socket: "localhost:8080",
+		},
+	}
+	// the number of times the state is queried at the same block, e.g. once per signature processed by the BLS
+	// aggregation service
+	const numQueries = 10
+
+	var tests = []struct {
+		name                      string
+		opts                      []AvsRegistryServiceChainCallerOption
+		wantOperatorsStakeQueries int
+		wantOperatorFromIdQueries int
+		wantCacheHits             float64
+	}{
+		{
+			name:                      "cached - the stakes are queried once per block and the addresses once",
+			wantOperatorsStakeQueries: 2,
+			wantOperatorFromIdQueries: 1,
+			wantCacheHits:             2*numQueries - 2,
+		},
+		{
+			name:                      "operator addresses only cached - they are resolved once per operator id",
+			opts:                      []AvsRegistryServiceChainCallerOption{WithOperatorsStateCacheSize(0)},
+			wantOperatorsStakeQueries: 2 * numQueries,
+			wantOperatorFromIdQueries: 1,
+			wantCacheHits:             0,
+		},
+		{
+			name: "not cached - the chain is queried each time",
+			opts: []AvsRegistryServiceChainCallerOption{
+				WithOperatorsStateCacheSize(0),
+				WithOperatorAddrCacheSize(0),
+			},
+			wantOperatorsStakeQueries: 2 * numQueries,
+			wantOperatorFromIdQueries: 2 * numQueries,
+			wantCacheHits:             0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &countingAvsRegistryReader{
+				FakeAVSRegistryReader: fakes.NewFakeAVSRegistryReader(&testOperator1, nil),
+			}
+			metrics := NewMetrics(prometheus.NewRegistry(), "test")
+			service := NewAvsRegistryServiceChainCaller(
+				reader,
+				newFakeOperatorInfoService(testOperator1.OperatorInfo),
+				logger,
+				append(tt.opts, WithMetrics(metrics))...,
+			)
+
+			wantOperatorsAvsStateDict := map[types.OperatorId]types.OperatorAvsState{
+				testOperator1.OperatorId: {
+					OperatorId:     testOperator1.OperatorId,
+					OperatorInfo:   testOperator1.OperatorInfo,
+					StakePerQuorum: map[types.QuorumNum]types.StakeAmount{1: big.NewInt(123)},
+					BlockNumber:    1,
+				},
+			}
+			for i := 0; i < numQueries; i++ {
+				gotOperatorsAvsStateDict, err := service.GetOperatorsAvsStateAtBlock(
+					context.Background(),
+					types.QuorumNums{1},
+					1,
+				)
+				if err != nil {
+					t.Fatalf("GetOperatorsAvsState returned error: %v", err)
+				}
+				if !reflect.DeepEqual(wantOperatorsAvsStateDict, gotOperatorsAvsStateDict) {
+					t.Fatalf(
+						"GetOperatorsAvsState returned wrong operatorsAvsStateDict. Got: %v, want: %v.",
+						gotOperatorsAvsStateDict,
+						wantOperatorsAvsStateDict,
+					)
+				}
+				// the state returned can be modified without affecting the cached one
+				gotOperatorsAvsStateDict[testOperator1.OperatorId].StakePerQuorum[1].SetInt64(0)
+			}
+			for i := 0; i < numQueries; i++ {
+				_, err := service.GetQuorumsAvsStateAtBlock(context.Background(), types.QuorumNums{1}, 2)
+				if err != nil {
+					t.Fatalf("GetQuorumsAvsState returned error: %v", err)
+				}
+			}
+
+			if reader.operatorsStakeQueries != tt.wantOperatorsStakeQueries {
+				t.Fatalf(
+					"wrong number of operators stake queries. Got: %d, want: %d.",
+					reader.operatorsStakeQueries,
+					tt.wantOperatorsStakeQueries,
+				)
+			}
+			if reader.operatorFromIdQueries != tt.wantOperatorFromIdQueries {
+				t.Fatalf(
+					"wrong number of operator from id queries. Got: %d, want: %d.",
+					reader.operatorFromIdQueries,
+					tt.wantOperatorFromIdQueries,
+				)
+			}
+			gotCacheHits := testutil.ToFloat64(metrics.cacheHits.WithLabelValues(operatorsStateCache))
+			if gotCacheHits != tt.wantCacheHits {
+				t.Fatalf(
+					"wrong number of operators state cache hits. Got: %v, want: %v.",
+					gotCacheHits,
+					tt.wantCacheHits,
+				)
+			}
+		})
+	}
+}
+
+// manyOperatorsAvsRegistryReader is an avs registry reader with numOperators operators, all registered in quorum 1
+// and the even ones in quorum 2
+type manyOperatorsAvsRegistryReader struct {
+	*fakes.FakeAVSRegistryReader
+	numOperators          int
+	operatorFromIdQueries int
+}
+
+func newManyOperatorsAvsRegistryReader(numOperators int) *manyOperatorsAvsRegistryReader {
+	return &manyOperatorsAvsRegistryReader{
+		FakeAVSRegistryReader: fakes.NewFakeAVSRegistryReader(nil, nil),
+		numOperators:          numOperators,
+	}
+}
+
+func manyOperatorsOperatorId(i int) types.OperatorId {
+	return types.OperatorId(common.BigToHash(big.NewInt(int64(i + 1))))
+}
+
+func (r *manyOperatorsAvsRegistryReader) GetOperatorsStakeInQuorumsAtBlock(
+	opts *bind.CallOpts,
+	quorumNumbers types.QuorumNums,
+	blockNumber types.BlockNum,
+) ([][]opstateretriever.OperatorStateRetrieverOperator, error) {
+	operatorsStakesInQuorums := make([][]opstateretriever.OperatorStateRetrieverOperator, len(quorumNumbers))
+	for quorumIdx, quorumNum := range quorumNumbers {
+		for i := 0; i < r.numOperators; i++ {
+			if quorumNum == 2 && i%2 != 0 {
+				continue
+			}
+			operatorsStakesInQuorums[quorumIdx] = append(
+				operatorsStakesInQuorums[quorumIdx],
+				opstateretriever.OperatorStateRetrieverOperator{
+					OperatorId: manyOperatorsOperatorId(i),
+					Stake:      big.NewInt(int64(i+1) * int64(quorumNum)),
+				},
+			)
+		}
+	}
+	return operatorsStakesInQuorums, nil
+}
+
+func (r *manyOperatorsAvsRegistryReader) GetOperatorFromId(
+	opts *bind.CallOpts,
+	operatorId types.OperatorId,
+) (common.Address, error) {
+	r.operatorFromIdQueries++
+	return common.BytesToAddress(operatorId[:]), nil
+}
+
+func TestAvsRegistryServiceChainCaller_GetOperatorsAvsStateAtBlockWithOpts(t *testing.T) {
+	logger := testutils.GetTestLogger()
+	operatorInfo := types.OperatorInfo{Socket: "localhost:8080"}
+	quorumNumbers := types.QuorumNums{1, 2}
+	const numOperators = 10
+
+	var tests = []struct {
+		name        string
+		opts        []AvsRegistryServiceChainCallerOption
+		operatorIds []types.OperatorId
+		// fullStateFirst queries the state of all the operators before the filtered one
+		fullStateFirst            bool
+		wantOperatorFromIdQueries int
+	}{
+		{
+			name:                      "nil filter returns all the operators",
+			operatorIds:               nil,
+			wantOperatorFromIdQueries: numOperators,
+		},
+		{
+			name:                      "empty filter returns no operator",
+			operatorIds:               []types.OperatorId{},
+			wantOperatorFromIdQueries: 0,
+		},
+		{
+			name: "only the filtered operators are resolved",
+			operatorIds: []types.OperatorId{
+				manyOperatorsOperatorId(0),
+				manyOperatorsOperatorId(3),
+				manyOperatorsOperatorId(4),
+			},
+			opts:                      []AvsRegistryServiceChainCallerOption{WithOperatorAddrCacheSize(0)},
+			wantOperatorFromIdQueries: 3,
+		},
+		{
+			name:                      "unregistered operators are left out",
+			operatorIds:               []types.OperatorId{manyOperatorsOperatorId(1), {0xff}},
+			opts:                      []AvsRegistryServiceChainCallerOption{WithOperatorAddrCacheSize(0)},
+			wantOperatorFromIdQueries: 1,
+		},
+		{
+			name:           "filtered from the cached state of all the operators",
+			operatorIds:    []types.OperatorId{manyOperatorsOperatorId(2), manyOperatorsOperatorId(5)},
+			opts:           []AvsRegistryServiceChainCallerOption{WithOperatorAddrCacheSize(0)},
+			fullStateFirst: true,
+			// only the query of the state of all the operators resolves the operators
+			wantOperatorFromIdQueries: numOperators,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the state of all the operators, queried from a separate service to filter it client-side
+			fullService := NewAvsRegistryServiceChainCaller(
+				newManyOperatorsAvsRegistryReader(numOperators),
+				newFakeOperatorInfoService(operatorInfo),
+				logger,
+			)
+			fullOperatorsAvsState, err := fullService.GetOperatorsAvsStateAtBlock(
+				context.Background(),
+				quorumNumbers,
+				1,
+			)
+			if err != nil {
+				t.Fatalf("GetOperatorsAvsState returned error: %v", err)
+			}
+			wantOperatorsAvsState := fullOperatorsAvsState
+			if tt.operatorIds != nil {
+				wantOperatorsAvsState = make(map[types.OperatorId]types.OperatorAvsState)
+				for _, operatorId := range tt.operatorIds {
+					if operatorAvsState, ok := fullOperatorsAvsState[operatorId]; ok {
+						wantOperatorsAvsState[operatorId] = operatorAvsState
+					}
+				}
+			}
+
+			reader := newManyOperatorsAvsRegistryReader(numOperators)
+			service := NewAvsRegistryServiceChainCaller(
+				reader,
+				newFakeOperatorInfoService(operatorInfo),
+				logger,
+				tt.opts...,
+			)
+			if tt.fullStateFirst {
+				_, err := service.GetOperatorsAvsStateAtBlock(context.Background(), quorumNumbers, 1)
+				if err != nil {
+					t.Fatalf("GetOperatorsAvsState returned error: %v", err)
+				}
+			}
+			gotOperatorsAvsState, err := service.GetOperatorsAvsStateAtBlockWithOpts(
+				context.Background(),
+				quorumNumbers,
+				1,
+				OperatorsAvsStateOpts{OperatorIds: tt.operatorIds},
+			)
+			if err != nil {
+				t.Fatalf("GetOperatorsAvsStateWithOpts returned error: %v", err)
+			}
+			if !reflect.DeepEqual(wantOperatorsAvsState, gotOperatorsAvsState) {
+				t.Fatalf(
+					"GetOperatorsAvsStateWithOpts returned wrong operatorsAvsState. Got: %v, want: %v.",
+					gotOperatorsAvsState,
+					wantOperatorsAvsState,
+				)
+			}
+			if reader.operatorFromIdQueries != tt.wantOperatorFromIdQueries {
+				t.Fatalf(
+					"wrong number of operator from id queries. Got: %d, want: %d.",
+					reader.operatorFromIdQueries,
+					tt.wantOperatorFromIdQueries,
+				)
+			}
+		})
+	}
+}
+
+func BenchmarkAvsRegistryServiceChainCaller_GetOperatorsAvsStateAtBlock(b *testing.B) {
+	logger := testutils.GetTestLogger()
+	quorumNumbers := types.QuorumNums{1, 2}
+	const numOperators = 1000
+	// e.g. the non-signers of a task
+	operatorIds := make([]types.OperatorId, 0, 10)
+	for i := 0; i < 10; i++ {
+		operatorIds = append(operatorIds, manyOperatorsOperatorId(i*numOperators/10))
+	}
+
+	var benchmarks = []struct {
+		name string
+		opts OperatorsAvsStateOpts
+	}{
+		{name: "all operators", opts: OperatorsAvsStateOpts{}},
+		{name: "10 operators", opts: OperatorsAvsStateOpts{OperatorIds: operatorIds}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			reader := newManyOperatorsAvsRegistryReader(numOperators)
+			// nothing is cached, so that every query hits the chain
+			service := NewAvsRegistryServiceChainCaller(
+				reader,
+				newFakeOperatorInfoService(types.OperatorInfo{}),
+				logger,
+				WithOperatorsStateCacheSize(0),
+				WithOperatorAddrCacheSize(0),
+			)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := service.GetOperatorsAvsStateAtBlockWithOpts(context.Background(), quorumNumbers, 1, bm.opts)
+				if err != nil {
+					b.Fatalf("GetOperatorsAvsStateWithOpts returned error: %v", err)
+				}
+			}
+			b.ReportMetric(float64(reader.operatorFromIdQueries)/float64(b.N), "operatorFromIdQueries/op")
+		})
+	}
+}
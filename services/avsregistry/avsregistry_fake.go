@@ -3,10 +3,8 @@ package avsregistry
 import (
 	"context"
 	"errors"
-	"math/big"
 
 	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
-	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 )
@@ -17,12 +15,18 @@ type FakeAvsRegistryService struct {
 
 func NewFakeAvsRegistryService(blockNum types.BlockNum, operators []types.TestOperator) *FakeAvsRegistryService {
 	fakeAvsRegistryService := &FakeAvsRegistryService{
-		operators: map[types.BlockNum]map[types.OperatorId]types.OperatorAvsState{
-			blockNum: {},
-		},
+		operators: map[types.BlockNum]map[types.OperatorId]types.OperatorAvsState{},
 	}
+	fakeAvsRegistryService.SetOperatorsAtBlock(blockNum, operators)
+	return fakeAvsRegistryService
+}
+
+// SetOperatorsAtBlock sets the operators registered at blockNum, e.g. to register operators after the block of the
+// operators of NewFakeAvsRegistryService
+func (f *FakeAvsRegistryService) SetOperatorsAtBlock(blockNum types.BlockNum, operators []types.TestOperator) {
+	f.operators[blockNum] = map[types.OperatorId]types.OperatorAvsState{}
 	for _, operator := range operators {
-		fakeAvsRegistryService.operators[blockNum][operator.OperatorId] = types.OperatorAvsState{
+		f.operators[blockNum][operator.OperatorId] = types.OperatorAvsState{
 			OperatorId: operator.OperatorId,
 			OperatorInfo: types.OperatorInfo{
 				Pubkeys: types.OperatorPubkeys{
@@ -35,7 +39,6 @@ func NewFakeAvsRegistryService(blockNum types.BlockNum, operators []types.TestOp
 			BlockNumber:    blockNum,
 		}
 	}
-	return fakeAvsRegistryService
 }
 
 var _ AvsRegistryService = (*FakeAvsRegistryService)(nil)
@@ -52,6 +55,19 @@ func (f *FakeAvsRegistryService) GetOperatorsAvsStateAtBlock(
 	return operatorsAvsState, nil
 }
 
+func (f *FakeAvsRegistryService) GetOperatorsAvsStateAtBlockWithOpts(
+	ctx context.Context,
+	quorumNumbers types.QuorumNums,
+	blockNumber types.BlockNum,
+	opts OperatorsAvsStateOpts,
+) (map[types.OperatorId]types.OperatorAvsState, error) {
+	operatorsAvsState, err := f.GetOperatorsAvsStateAtBlock(ctx, quorumNumbers, blockNumber)
+	if err != nil || opts.OperatorIds == nil {
+		return operatorsAvsState, err
+	}
+	return filterOperatorsAvsState(operatorsAvsState, opts.OperatorIds), nil
+}
+
 func (f *FakeAvsRegistryService) GetQuorumsAvsStateAtBlock(
 	ctx context.Context,
 	quorumNumbers types.QuorumNums,
@@ -61,25 +77,7 @@ func (f *FakeAvsRegistryService) GetQuorumsAvsStateAtBlock(
 	if !ok {
 		return nil, errors.New("block number not found")
 	}
-	quorumsAvsState := make(map[types.QuorumNum]types.QuorumAvsState)
-	for _, quorumNum := range quorumNumbers {
-		aggPubkeyG1 := bls.NewG1Point(big.NewInt(0), big.NewInt(0))
-		totalStake := big.NewInt(0)
-		for _, operator := range operatorsAvsState {
-			// only include operators that have a stake in this quorum
-			if stake, ok := operator.StakePerQuorum[quorumNum]; ok {
-				aggPubkeyG1.Add(operator.OperatorInfo.Pubkeys.G1Pubkey)
-				totalStake.Add(totalStake, stake)
-			}
-		}
-		quorumsAvsState[quorumNum] = types.QuorumAvsState{
-			QuorumNumber: quorumNum,
-			AggPubkeyG1:  aggPubkeyG1,
-			TotalStake:   totalStake,
-			BlockNumber:  blockNumber,
-		}
-	}
-	return quorumsAvsState, nil
+	return QuorumsAvsStateFromOperatorsAvsState(quorumNumbers, blockNumber, operatorsAvsState)
 }
 
 func (f *FakeAvsRegistryService) GetCheckSignaturesIndices(
@@ -0,0 +1,105 @@
+package avsregistry_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients"
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	blsapkregistry "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
+	regcoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/services/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/services/operatorsinfo"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegrationComputeQuorumAPK cross-validates the aggregate pubkeys computed locally by ComputeQuorumAPK with the
+// ones of the BLSApkRegistry contract deployed on anvil
+func TestIntegrationComputeQuorumAPK(t *testing.T) {
+	anvilC, err := testutils.StartAnvilContainer("contracts-deployed-anvil-state.json")
+	require.NoError(t, err)
+	anvilHttpEndpoint, err := anvilC.Endpoint(context.Background(), "http")
+	require.NoError(t, err)
+	anvilWsEndpoint, err := anvilC.Endpoint(context.Background(), "ws")
+	require.NoError(t, err)
+	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	ethHttpClient, err := ethclient.Dial(anvilHttpEndpoint)
+	require.NoError(t, err)
+	logger := testutils.GetTestLogger()
+
+	ecdsaPrivKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	avsClients, err := clients.BuildAll(clients.BuildAllConfig{
+		EthHttpUrl:                 anvilHttpEndpoint,
+		EthWsUrl:                   anvilWsEndpoint,
+		RegistryCoordinatorAddr:    contractAddrs.RegistryCoordinator.String(),
+		OperatorStateRetrieverAddr: contractAddrs.OperatorStateRetriever.String(),
+		AvsName:                    "avs",
+		PromMetricsIpPortAddress:   "localhost:9090",
+	}, ecdsaPrivKey, logger)
+	require.NoError(t, err)
+	registryCoordinator, err := regcoordinator.NewContractRegistryCoordinator(
+		contractAddrs.RegistryCoordinator,
+		ethHttpClient,
+	)
+	require.NoError(t, err)
+	blsApkRegistryAddr, err := registryCoordinator.BlsApkRegistry(&bind.CallOpts{})
+	require.NoError(t, err)
+	blsApkRegistry, err := blsapkregistry.NewContractBLSApkRegistry(blsApkRegistryAddr, ethHttpClient)
+	require.NoError(t, err)
+
+	blsKeyPair, err := bls.NewKeyPairFromString("0x1")
+	require.NoError(t, err)
+	quorumNumbers := types.QuorumNums{0}
+	_, err = avsClients.AvsRegistryChainWriter.RegisterOperator(
+		context.Background(),
+		ecdsaPrivKey,
+		blsKeyPair,
+		quorumNumbers,
+		"socket",
+		true,
+	)
+	require.NoError(t, err)
+	blockNumber, err := ethHttpClient.BlockNumber(context.Background())
+	require.NoError(t, err)
+
+	operatorsInfoService := operatorsinfo.NewOperatorsInfoServiceInMemory(
+		context.Background(),
+		avsClients.AvsRegistryChainSubscriber,
+		avsClients.AvsRegistryChainReader,
+		nil,
+		operatorsinfo.Opts{},
+		logger,
+	)
+	avsRegistryService := avsregistry.NewAvsRegistryServiceChainCaller(
+		avsClients.AvsRegistryChainReader,
+		operatorsInfoService,
+		logger,
+	)
+	quorumsAvsState, err := avsRegistryService.GetQuorumsAvsStateAtBlock(
+		context.Background(),
+		quorumNumbers,
+		types.BlockNum(blockNumber),
+	)
+	require.NoError(t, err)
+
+	for _, quorumNumber := range quorumNumbers {
+		wantAPK, err := blsApkRegistry.GetApk(
+			&bind.CallOpts{BlockNumber: new(big.Int).SetUint64(blockNumber)},
+			quorumNumber.UnderlyingType(),
+		)
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			blsapkregistry.BN254G1Point(chainioutils.ConvertToBN254G1Point(quorumsAvsState[quorumNumber].AggPubkeyG1)),
+			wantAPK,
+		)
+	}
+}
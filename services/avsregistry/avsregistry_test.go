@@ -0,0 +1,87 @@
+package avsregistry
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+)
+
+func TestComputeQuorumAPK(t *testing.T) {
+	keyPairs := make([]*bls.KeyPair, 3)
+	operators := make([]types.OperatorAvsState, 3)
+	for i := range keyPairs {
+		keyPair, err := bls.GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("failed to generate bls keys: %v", err)
+		}
+		keyPairs[i] = keyPair
+		operators[i] = types.OperatorAvsState{
+			OperatorId: types.OperatorIdFromKeyPair(keyPair),
+			OperatorInfo: types.OperatorInfo{
+				Pubkeys: types.OperatorPubkeys{G1Pubkey: keyPair.GetPubKeyG1(), G2Pubkey: keyPair.GetPubKeyG2()},
+			},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+		}
+	}
+	operatorWithoutPubkey := types.OperatorAvsState{
+		OperatorId:     types.OperatorId{4},
+		StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+	}
+
+	var tests = []struct {
+		name       string
+		operators  []types.OperatorAvsState
+		wantAPK    *bls.G1Point
+		wantErrFor *types.OperatorId
+	}{
+		{
+			name:    "empty quorum - point at infinity",
+			wantAPK: bls.NewZeroG1Point(),
+		},
+		{
+			name:      "1 operator - its pubkey",
+			operators: operators[:1],
+			wantAPK:   keyPairs[0].GetPubKeyG1(),
+		},
+		{
+			name:      "3 operators - sum of their pubkeys",
+			operators: operators,
+			wantAPK: bls.NewZeroG1Point().
+				Add(keyPairs[0].GetPubKeyG1()).
+				Add(keyPairs[1].GetPubKeyG1()).
+				Add(keyPairs[2].GetPubKeyG1()),
+		},
+		{
+			name:       "operator without pubkey - error naming the operator",
+			operators:  append([]types.OperatorAvsState{operators[0]}, operatorWithoutPubkey),
+			wantErrFor: &operatorWithoutPubkey.OperatorId,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAPK, gotErr := ComputeQuorumAPK(tt.operators)
+			if tt.wantErrFor != nil {
+				var missingPubkeyErr *MissingOperatorPubkeyError
+				if !errors.As(gotErr, &missingPubkeyErr) || missingPubkeyErr.OperatorId != *tt.wantErrFor {
+					t.Fatalf(
+						"ComputeQuorumAPK returned wrong error. Got: %v, want operator: %v.",
+						gotErr,
+						*tt.wantErrFor,
+					)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("ComputeQuorumAPK returned error: %v", gotErr)
+			}
+			if !reflect.DeepEqual(tt.wantAPK, gotAPK) {
+				t.Fatalf("ComputeQuorumAPK returned wrong apk. Got: %v, want: %v.", gotAPK, tt.wantAPK)
+			}
+		})
+	}
+}
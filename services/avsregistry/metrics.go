@@ -0,0 +1,71 @@
+package avsregistry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// operatorsStateCache is the label of the metrics of the cache of the operators state per block
+	operatorsStateCache = "operators_state"
+	// operatorAddrCache is the label of the metrics of the cache of the operator address per operator id
+	operatorAddrCache = "operator_addr"
+)
+
+type Metrics interface {
+	IncrementCacheHits(cache string)
+	IncrementCacheMisses(cache string)
+}
+
+const namespace = "avsregistry"
+
+type PromMetrics struct {
+	cacheHits   *prometheus.CounterVec
+	cacheMisses *prometheus.CounterVec
+}
+
+var _ Metrics = (*PromMetrics)(nil)
+
+// NewMetrics returns the metrics of an AvsRegistryServiceChainCaller registered on reg
+func NewMetrics(reg prometheus.Registerer, subsystem string) *PromMetrics {
+	return &PromMetrics{
+		cacheHits: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "cache_hits_total",
+				Help:      "number of lookups served by the cache, by cache",
+			},
+			[]string{"cache"},
+		),
+		cacheMisses: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "cache_misses_total",
+				Help:      "number of lookups not served by the cache, which queried the chain, by cache",
+			},
+			[]string{"cache"},
+		),
+	}
+}
+
+func (m *PromMetrics) IncrementCacheHits(cache string) {
+	m.cacheHits.WithLabelValues(cache).Inc()
+}
+
+func (m *PromMetrics) IncrementCacheMisses(cache string) {
+	m.cacheMisses.WithLabelValues(cache).Inc()
+}
+
+type NoopMetrics struct{}
+
+var _ Metrics = (*NoopMetrics)(nil)
+
+func NewNoopMetrics() *NoopMetrics {
+	return &NoopMetrics{}
+}
+
+func (m *NoopMetrics) IncrementCacheHits(cache string) {}
+
+func (m *NoopMetrics) IncrementCacheMisses(cache string) {}
@@ -1,20 +1,31 @@
+// Package blsagg aggregates the BLS signatures of the task responses of the operators of an AVS. The aggregation
+// service never signs: it only verifies the signatures sent by the operators against their G2 public keys and
+// aggregates them, so it takes no bls.KeyPair nor blssigner.Signer. The operators sign their task responses with their
+// own blssigner.Signer, e.g. a remote Cerberus signer, and send the signatures to the aggregator.
 package blsagg
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"runtime"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/internal/aggregation"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
 	"github.com/Layr-Labs/eigensdk-go/services/avsregistry"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
@@ -31,10 +42,10 @@ var (
 		return fmt.Errorf("task %d expired", taskIndex)
 	}
 	TaskNotFoundErrorFn = func(taskIndex types.TaskIndex) error {
-		return fmt.Errorf("task %d not initialized or already completed", taskIndex)
+		return &TaskNotFoundError{TaskIndex: taskIndex}
 	}
 	OperatorNotPartOfTaskQuorumErrorFn = func(operatorId types.OperatorId, taskIndex types.TaskIndex) error {
-		return fmt.Errorf("operator %x not part of task %d's quorum", operatorId, taskIndex)
+		return &OperatorNotPartOfTaskQuorumError{OperatorId: operatorId, TaskIndex: taskIndex}
 	}
 	HashFunctionError = func(err error) error {
 		return fmt.Errorf("Failed to hash task response: %w", err)
@@ -43,8 +54,80 @@ var (
 		return fmt.Errorf("Failed to verify signature: %w", err)
 	}
 	IncorrectSignatureError = errors.New("Signature verification failed. Incorrect Signature.")
+	// ErrConflictingSignature is matched by the ConflictingSignatureError returned by ProcessNewSignature
+	ErrConflictingSignature = errors.New("conflicting signature")
+	// ErrServiceClosed is returned by the initialization of the tasks once the service is closed, see Close
+	ErrServiceClosed = errors.New("aggregation service closed")
+	// ErrTaskCancelled is matched by the TaskCancelledError of the responses of the tasks cancelled by Close
+	ErrTaskCancelled = errors.New("task cancelled")
+	// ErrTaskNotFound is matched by the TaskNotFoundError returned for the tasks which aren't in-flight
+	ErrTaskNotFound = errors.New("task not found")
 )
 
+// TaskNotFoundError is returned for a task which isn't in-flight, because it wasn't initialized or already completed
+type TaskNotFoundError struct {
+	TaskIndex types.TaskIndex
+}
+
+func (e *TaskNotFoundError) Error() string {
+	return fmt.Sprintf("task %d not initialized or already completed", e.TaskIndex)
+}
+
+func (e *TaskNotFoundError) Is(target error) bool {
+	return target == ErrTaskNotFound
+}
+
+// TaskCancelledError is the error of the response of a task which was still in-flight when the deadline of Close was
+// reached, without having met its stake thresholds
+type TaskCancelledError struct {
+	TaskIndex types.TaskIndex
+}
+
+func (e *TaskCancelledError) Error() string {
+	return fmt.Sprintf("%s: task %d was in-flight when the service closed", ErrTaskCancelled, e.TaskIndex)
+}
+
+func (e *TaskCancelledError) Is(target error) bool {
+	return target == ErrTaskCancelled
+}
+
+// ConflictingSignatureError is returned by ProcessNewSignature for the valid signature of an operator which already
+// signed another task response digest of the same task, which may indicate that the operator equivocates. The
+// signature isn't aggregated, the operator only counting towards the digest it signed first.
+type ConflictingSignatureError struct {
+	OperatorId types.OperatorId
+	TaskIndex  types.TaskIndex
+	// FirstDigest is the digest the operator signed first
+	FirstDigest  types.TaskResponseDigest
+	SecondDigest types.TaskResponseDigest
+}
+
+func (e *ConflictingSignatureError) Error() string {
+	return fmt.Sprintf(
+		"%s: operator %s signed digests %s and %s of task %d",
+		ErrConflictingSignature,
+		e.OperatorId,
+		e.FirstDigest,
+		e.SecondDigest,
+		e.TaskIndex,
+	)
+}
+
+func (e *ConflictingSignatureError) Is(target error) bool {
+	return target == ErrConflictingSignature
+}
+
+// OperatorNotPartOfTaskQuorumError is returned by ProcessNewSignature for the signature of an operator which wasn't
+// registered in any of the quorums of the task at its reference block, e.g. which registered after it
+type OperatorNotPartOfTaskQuorumError struct {
+	OperatorId types.OperatorId
+	TaskIndex  types.TaskIndex
+}
+
+func (e *OperatorNotPartOfTaskQuorumError) Error() string {
+	return fmt.Sprintf("operator %s not part of task %d's quorum", e.OperatorId, e.TaskIndex)
+}
+
 // BlsAggregationServiceResponse is the response from the bls aggregation service
 type BlsAggregationServiceResponse struct {
 	Err                error                    // if Err is not nil, the other fields are not valid
@@ -63,19 +146,89 @@ type BlsAggregationServiceResponse struct {
 	QuorumApkIndices             []uint32
 	TotalStakeIndices            []uint32
 	NonSignerStakeIndices        [][]uint32
+	// DuplicateSignatures is the number of signatures received again from operators which already signed the same
+	// digest, which are accepted but only counted once
+	DuplicateSignatures int
+	// ConflictingSignatures is the number of signatures refused with a ConflictingSignatureError
+	ConflictingSignatures int
+	// OtherDigestsSignedStake is the stake which signed the other digests of the task, which didn't meet the stake
+	// thresholds first, sorted by digest. It's nil if all the signers signed TaskResponseDigest.
+	OtherDigestsSignedStake []DigestSignedStake
+	// QuorumSigners are the signers of TaskResponseDigest when its stake thresholds were met, and Signers the ones at
+	// the end of the window of the task, which also include the operators which signed during the window, both sorted
+	// by id. They are only set for the tasks with a window, see InitializeNewTaskWithWindow.
+	QuorumSigners []types.OperatorId
+	Signers       []types.OperatorId
+}
+
+// TaskAggregationState is the progress of the aggregation of an in-flight task, see GetTaskAggregationState
+type TaskAggregationState struct {
+	TaskIndex        types.TaskIndex `json:"taskIndex"`
+	TaskCreatedBlock uint32          `json:"taskCreatedBlock"`
+	// TaskResponseDigest is the digest which met the stake thresholds if ThresholdsMet, or else the digest signed most
+	// recently, whose signed stake is the one of QuorumStakes
+	TaskResponseDigest types.TaskResponseDigest                   `json:"taskResponseDigest"`
+	QuorumStakes       map[types.QuorumNum]QuorumAggregationState `json:"quorumStakes"`
+	// ThresholdsMet is whether the signers of TaskResponseDigest met the stake thresholds, the task waiting for the end
+	// of its window to complete
+	ThresholdsMet bool `json:"thresholdsMet"`
+	// Signers are the operators whose signatures were aggregated, over any digest, sorted by id
+	Signers []types.OperatorId `json:"signers"`
+	// OtherDigestsSignedStake is the stake which signed the other digests of the task, sorted by digest
+	OtherDigestsSignedStake []DigestSignedStake `json:"otherDigestsSignedStake"`
+	// TimeToExpiry is the time left until the task expires
+	TimeToExpiry time.Duration `json:"timeToExpiry"`
+}
+
+// QuorumAggregationState is the stake of a quorum which signed the digest of a TaskAggregationState
+type QuorumAggregationState struct {
+	SignedStake *big.Int `json:"signedStake"`
+	TotalStake  *big.Int `json:"totalStake"`
+	// SignedStakePercentage is the percentage of TotalStake which signed, to compare with ThresholdPercentage
+	SignedStakePercentage float64                         `json:"signedStakePercentage"`
+	ThresholdPercentage   types.QuorumThresholdPercentage `json:"thresholdPercentage"`
+}
+
+// taskStateQuerier queries the aggregation state of an in-flight task from its goroutine
+type taskStateQuerier struct {
+	queryC chan chan<- TaskAggregationState
+	// doneC is closed once the goroutine of the task stops answering the queries
+	doneC chan struct{}
+}
+
+// DigestSignedStake is the stake which signed a task response digest of a task
+type DigestSignedStake struct {
+	TaskResponseDigest   types.TaskResponseDigest
+	SignedStakePerQuorum map[types.QuorumNum]*big.Int
+	// Signers is the number of operators which signed the digest
+	Signers int
 }
 
 // aggregatedOperators is meant to be used as a value in a map
-// map[taskResponseDigest]aggregatedOperators
+// map[taskResponseDigest]aggregatedOperators. The stake and the ids of the signers are kept by the
+// aggregation.DigestStakes of the task.
 type aggregatedOperators struct {
 	// aggregate g2 pubkey of all operatos who signed on this taskResponseDigest
 	signersApkG2 *bls.G2Point
 	// aggregate signature of all operators who signed on this taskResponseDigest
 	signersAggSigG1 *bls.Signature
-	// aggregate stake of all operators who signed on this header for each quorum
-	signersTotalStakePerQuorum map[types.QuorumNum]*big.Int
-	// set of OperatorId of operators who signed on this header
-	signersOperatorIdsSet map[types.OperatorId]bool
+}
+
+// blsTaskHandle is the handle the service reaches an in-flight task with
+type blsTaskHandle struct {
+	// signedTaskRespsC is the channel to send the signed task responses to the goroutine processing the task
+	signedTaskRespsC chan types.SignedTaskResponseDigest
+	// stateQuerier queries the aggregation state of the task, see GetTaskAggregationState
+	stateQuerier taskStateQuerier
+}
+
+// signatureVerificationResult is the result of the verification of the signature of a signed task response digest by
+// a verification worker
+type signatureVerificationResult struct {
+	signedTaskResponseDigest types.SignedTaskResponseDigest
+	// persist is set unless the signature is a recovered one, which is already stored
+	persist bool
+	err     error
 }
 
 // BlsAggregationService is the interface provided to avs aggregator code for doing bls aggregation
@@ -108,6 +261,8 @@ type BlsAggregationService interface {
 	// before sending the aggregation response through the aggregatedResponsesC channel.
 	// If the task expiration is reached before the window finishes, the task response will still be sent to the
 	// aggregatedResponsesC channel.
+	// The signatures received once the window ended are refused with a TaskNotFoundError. The response lists both the
+	// signers at the time the quorum was reached and the ones at the end of the window.
 	InitializeNewTaskWithWindow(
 		taskIndex types.TaskIndex,
 		taskCreatedBlock uint32,
@@ -117,10 +272,22 @@ type BlsAggregationService interface {
 		windowDuration time.Duration,
 	) error
 
+	// InitializeNewTaskWithMetadata is InitializeNewTaskWithWindow with the time to expiry, quorum threshold
+	// percentages and window duration of metadata. Its unset time to expiry and quorum threshold percentages are the
+	// ones of the default task metadata of the service, as of the initialization of the task.
+	InitializeNewTaskWithMetadata(
+		taskIndex types.TaskIndex,
+		taskCreatedBlock uint32,
+		quorumNumbers types.QuorumNums,
+		metadata TaskMetadata,
+	) error
+
 	// ProcessNewSignature processes a new signature over a taskResponseDigest for a particular taskIndex by a
 	// particular operator It verifies that the signature is correct and returns an error if it is not, and then
 	// aggregates the signature and stake of
 	// the operator with all other signatures for the same taskIndex and taskResponseDigest pair.
+	// The signatures of an operator which already signed the same digest are accepted without being aggregated
+	// again, while the ones over another digest are refused with a ConflictingSignatureError.
 	// Note: This function currently only verifies signatures over the taskResponseDigest directly, so avs code needs to
 	// verify that the digest passed to ProcessNewSignature is indeed the digest of a valid taskResponse (that is,
 	// BlsAggregationService does not verify semantic integrity of the taskResponses)
@@ -135,7 +302,114 @@ type BlsAggregationService interface {
 	// GetResponseChannel returns the single channel that meant to be used as the response channel
 	// Any task that is completed (see the completion criterion in the comment above InitializeNewTask)
 	// will be sent on this channel along with all the necessary information to call BLSSignatureChecker onchain
+	// If a response handler is set with WithResponseHandler, the completed tasks are delivered to it instead, and
+	// only the ones it failed to handle in time are sent on this channel
+	// The channel is closed by Close, once the responses of all the tasks were delivered.
 	GetResponseChannel() <-chan BlsAggregationServiceResponse
+
+	// Close stops the service: the new tasks are refused with ErrServiceClosed, while the in-flight tasks keep
+	// aggregating their signatures until they complete or expire. Once ctx is done, the remaining tasks are cancelled,
+	// their responses having a TaskCancelledError unless they met their stake thresholds. The response channel is
+	// closed last, once all the responses were delivered, so it must still be read while the service closes.
+	Close(ctx context.Context) error
+}
+
+// TaskMetadata overrides the default task metadata of the service for a task, see InitializeNewTaskWithMetadata
+type TaskMetadata struct {
+	// TimeToExpiry is the time after which the task expires, the default one of the service if zero
+	TimeToExpiry time.Duration
+	// QuorumThresholdPercentages are the thresholds of the quorums of the task, in the order of its quorum numbers,
+	// the default ones of the service if nil
+	QuorumThresholdPercentages types.QuorumThresholdPercentages
+	// WindowDuration is the time the task is still open to signatures once its thresholds are met, see
+	// InitializeNewTaskWithWindow
+	WindowDuration time.Duration
+}
+
+// BlsAggregatorServiceOption configures the service returned by NewBlsAggregatorService
+type BlsAggregatorServiceOption func(*BlsAggregatorService)
+
+// WithDefaultTaskMetadata sets the default time to expiry and quorum threshold percentages of the tasks initialized
+// with InitializeNewTaskWithMetadata, see SetDefaultTaskMetadata. Its window duration is ignored.
+func WithDefaultTaskMetadata(metadata TaskMetadata) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		a.defaultTaskMetadata = metadata
+	}
+}
+
+// WithSignatureStore sets the store persisting the in-flight tasks of the service and the signatures it accepts, which
+// are recovered with RecoverTasks once the service restarts
+func WithSignatureStore(store SignatureStore) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		a.signatureStore = store
+	}
+}
+
+// WithVerificationWorkers sets the number of signatures the service verifies concurrently, across all its tasks,
+// runtime.NumCPU() unless set or if workers isn't positive. The hash function of the service is called concurrently by
+// the verification workers.
+func WithVerificationWorkers(workers int) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		if workers > 0 {
+			a.verificationWorkers = workers
+		}
+	}
+}
+
+// DefaultResponseHandlerTimeout is the time the response handler has to handle each response unless set with
+// WithResponseHandlerTimeout
+const DefaultResponseHandlerTimeout = time.Second
+
+// WithResponseHandler sets the handler the responses of the service are delivered to, instead of the response
+// channel. It's called synchronously by the goroutines of the tasks, and so must not block: if it doesn't return
+// within the response handler timeout, see WithResponseHandlerTimeout, the response is considered as dropped by the
+// handler and is sent on the response channel instead, which should thus still be read. Each response is delivered
+// by exactly one of the handler and the response channel.
+func WithResponseHandler(handler func(response BlsAggregationServiceResponse)) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		a.responseHandler = handler
+	}
+}
+
+// WithResponseHandlerTimeout sets the time the response handler has to handle each response before it's sent on the
+// response channel instead, DefaultResponseHandlerTimeout unless set
+func WithResponseHandlerTimeout(timeout time.Duration) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		a.responseHandlerTimeout = timeout
+	}
+}
+
+// DefaultMaxPendingResponses is the number of responses waiting to be read from the response channel from which the
+// service reports itself as degraded, unless set with WithMaxPendingResponses
+const DefaultMaxPendingResponses = 8
+
+// WithMaxPendingResponses sets the number of responses waiting to be read from the response channel from which the
+// service reports itself as degraded, see Health. DefaultMaxPendingResponses unless set.
+func WithMaxPendingResponses(maxPendingResponses int) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		if maxPendingResponses > 0 {
+			a.maxPendingResponses = maxPendingResponses
+		}
+	}
+}
+
+// WithMetrics sets the metrics of the service, NoopMetrics unless set
+func WithMetrics(metrics Metrics) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		a.metrics = metrics
+	}
+}
+
+// WithPrometheusRegisterer sets the metrics of the service to the ones NewMetrics registers on reg, without
+// subsystem. The service has no metrics if reg is nil.
+func WithPrometheusRegisterer(reg prometheus.Registerer) BlsAggregatorServiceOption {
+	return func(a *BlsAggregatorService) {
+		if reg == nil {
+			a.metrics = NewNoopMetrics()
+			return
+		}
+		a.metrics = NewMetrics(reg, "")
+	}
 }
 
 // BlsAggregatorService is a service that performs BLS signature aggregation for an AVS' tasks
@@ -151,28 +425,44 @@ type BlsAggregationService interface {
 //     only submitted after the previous one's response has been aggregated and responded onchain, could have
 //     a much simpler AggregationService without all the complicated parallel goroutines.
 type BlsAggregatorService struct {
-	// aggregatedResponsesC is the channel which all goroutines share to send their responses back to the
-	// main thread after they are done aggregating (either they reached the threshold, or timeout expired)
-	aggregatedResponsesC chan BlsAggregationServiceResponse
-	// signedTaskRespsCs are the channels to send the signed task responses to the goroutines processing them
-	// each new task is assigned a new goroutine and a new channel
-	signedTaskRespsCs map[types.TaskIndex]chan types.SignedTaskResponseDigest
-	// we add chans to taskChans from the main thread (InitializeNewTask) when we create new tasks,
-	// we read them in ProcessNewSignature from the main thread when we receive new signed tasks,
-	// and remove them from its respective goroutine when the task is completed or reached timeout
-	// we thus need a mutex to protect taskChans
-	taskChansMutex     sync.RWMutex
+	// responses delivers the responses the goroutines of the tasks send once they are done aggregating (either they
+	// reached the threshold, or timeout expired)
+	responses *aggregation.Responses[BlsAggregationServiceResponse]
+	// tasks are the in-flight tasks, each assigned a new goroutine and a handle to send it the signed task responses
+	tasks              *aggregation.Tasks[blsTaskHandle]
 	avsRegistryService avsregistry.AvsRegistryService
 	logger             logging.Logger
 
 	hashFunction types.TaskResponseHashFunction
+
+	// defaultTaskMetadata is the default metadata of the tasks initialized with InitializeNewTaskWithMetadata
+	defaultTaskMetadata      TaskMetadata
+	defaultTaskMetadataMutex sync.RWMutex
+
+	// signatureStore persists the tasks and their accepted signatures, nil if they aren't persisted
+	signatureStore SignatureStore
+
+	// verificationWorkersC holds a value for each signature being verified, bounding the concurrent verifications to
+	// its capacity verificationWorkers
+	verificationWorkersC chan struct{}
+	verificationWorkers  int
+	metrics              Metrics
+
+	// responseHandler handles the responses instead of the response channel if set, see WithResponseHandler
+	responseHandler        func(response BlsAggregationServiceResponse)
+	responseHandlerTimeout time.Duration
+	// maxPendingResponses is the number of responses waiting to be read from the response channel from which the
+	// service is degraded
+	maxPendingResponses int
 }
 
 var _ BlsAggregationService = (*BlsAggregatorService)(nil)
+var _ services.HealthReporter = (*BlsAggregatorService)(nil)
 
 // NewBlsAggregatorService creates a new BlsAggregatorService
 // avsRegistryService is the AVS registry service to use
-// hashFunction is the hash function to use to compute the taskResponseDigest from the taskResponse
+// hashFunction is the hash function to use to compute the taskResponseDigest from the taskResponse, which must be safe
+// for concurrent use since the signatures are verified concurrently, see WithVerificationWorkers
 // logger is the logger to use
 //
 // An example of hashFunction is the one defined in blsagg_test.go:
@@ -191,19 +481,42 @@ func NewBlsAggregatorService(
 	avsRegistryService avsregistry.AvsRegistryService,
 	hashFunction types.TaskResponseHashFunction,
 	logger logging.Logger,
+	opts ...BlsAggregatorServiceOption,
 ) *BlsAggregatorService {
-	return &BlsAggregatorService{
-		aggregatedResponsesC: make(chan BlsAggregationServiceResponse),
-		signedTaskRespsCs:    make(map[types.TaskIndex]chan types.SignedTaskResponseDigest),
-		taskChansMutex:       sync.RWMutex{},
-		avsRegistryService:   avsRegistryService,
-		logger:               logger,
-		hashFunction:         hashFunction,
+	a := &BlsAggregatorService{
+		avsRegistryService:     avsRegistryService,
+		logger:                 logger,
+		hashFunction:           hashFunction,
+		verificationWorkers:    runtime.NumCPU(),
+		metrics:                NewNoopMetrics(),
+		responseHandlerTimeout: DefaultResponseHandlerTimeout,
+		maxPendingResponses:    DefaultMaxPendingResponses,
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+	a.verificationWorkersC = make(chan struct{}, a.verificationWorkers)
+	a.tasks = aggregation.NewTasks(logger, a.stopTask)
+	a.responses = aggregation.NewResponses(
+		logger,
+		a.responseHandler,
+		a.responseHandlerTimeout,
+		a.maxPendingResponses,
+	)
+	return a
+}
+
+// SetDefaultTaskMetadata sets the default time to expiry and quorum threshold percentages of the tasks initialized
+// with InitializeNewTaskWithMetadata. The tasks already initialized keep the ones they were initialized with. Its
+// window duration is ignored.
+func (a *BlsAggregatorService) SetDefaultTaskMetadata(metadata TaskMetadata) {
+	a.defaultTaskMetadataMutex.Lock()
+	defer a.defaultTaskMetadataMutex.Unlock()
+	a.defaultTaskMetadata = metadata
 }
 
 func (a *BlsAggregatorService) GetResponseChannel() <-chan BlsAggregationServiceResponse {
-	return a.aggregatedResponsesC
+	return a.responses.C()
 }
 
 // InitializeNewTask creates a new task goroutine meant to process new signed task responses for that task
@@ -236,7 +549,8 @@ func (a *BlsAggregatorService) InitializeNewTask(
 // whose stake in each of the listed quorums adds up to at least quorumThresholdPercentages[i] of the total stake in
 // that quorum.
 // Once the quorum is reached, the task is still open for a window of `windowDuration` time to receive more signatures,
-// before sending the aggregation response through the aggregatedResponsesC channel.
+// before sending the aggregation response through the aggregatedResponsesC channel. The signatures received once the
+// window ended are refused with a TaskNotFoundError.
 func (a *BlsAggregatorService) InitializeNewTaskWithWindow(
 	taskIndex types.TaskIndex,
 	taskCreatedBlock uint32,
@@ -259,26 +573,162 @@ func (a *BlsAggregatorService) InitializeNewTaskWithWindow(
 		timeToExpiry,
 	)
 
-	a.taskChansMutex.Lock()
-	defer a.taskChansMutex.Unlock()
-	if _, taskExists := a.signedTaskRespsCs[taskIndex]; taskExists {
+	newHandle := func() (blsTaskHandle, error) {
+		if a.signatureStore != nil {
+			err := a.signatureStore.SaveTask(StoredTask{
+				TaskIndex:                  taskIndex,
+				TaskCreatedBlock:           taskCreatedBlock,
+				QuorumNumbers:              quorumNumbers,
+				QuorumThresholdPercentages: quorumThresholdPercentages,
+				ExpiresAt:                  time.Now().Add(timeToExpiry),
+				WindowDuration:             windowDuration,
+			})
+			if err != nil {
+				return blsTaskHandle{}, utils.WrapError("failed to store task", err)
+			}
+		}
+		return newBlsTaskHandle(), nil
+	}
+	err := a.tasks.Start(taskIndex, timeToExpiry, newHandle, func(task *aggregation.Task[blsTaskHandle]) {
+		a.singleTaskAggregatorGoroutineFunc(
+			task,
+			taskCreatedBlock,
+			quorumNumbers,
+			quorumThresholdPercentages,
+			windowDuration,
+			nil,
+		)
+	})
+	return taskStartError(taskIndex, err)
+}
+
+// taskStartError returns the error of the initialization of the task taskIndex, for the error err of
+// aggregation.Tasks.Start
+func taskStartError(taskIndex types.TaskIndex, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, aggregation.ErrClosed):
+		return TaskInitializationErrorFn(ErrServiceClosed, taskIndex)
+	case errors.Is(err, aggregation.ErrTaskInFlight):
 		return TaskAlreadyInitializedErrorFn(taskIndex)
+	default:
+		return TaskInitializationErrorFn(err, taskIndex)
 	}
-	signedTaskRespsC := make(chan types.SignedTaskResponseDigest)
-	a.signedTaskRespsCs[taskIndex] = signedTaskRespsC
+}
 
-	go a.singleTaskAggregatorGoroutineFunc(
+// InitializeNewTaskWithMetadata is InitializeNewTaskWithWindow with the time to expiry, quorum threshold percentages
+// and window duration of metadata. Its unset time to expiry and quorum threshold percentages are the ones of the
+// default task metadata of the service, as of the initialization of the task: the later changes of the default task
+// metadata don't affect the task.
+func (a *BlsAggregatorService) InitializeNewTaskWithMetadata(
+	taskIndex types.TaskIndex,
+	taskCreatedBlock uint32,
+	quorumNumbers types.QuorumNums,
+	metadata TaskMetadata,
+) error {
+	a.defaultTaskMetadataMutex.RLock()
+	defaultTaskMetadata := a.defaultTaskMetadata
+	a.defaultTaskMetadataMutex.RUnlock()
+
+	// the task keeps its own copy of the percentages, which the later changes of the default task metadata don't affect
+	timeToExpiry, quorumThresholdPercentages, err := aggregation.TaskParams(
+		len(quorumNumbers),
+		metadata.TimeToExpiry,
+		metadata.QuorumThresholdPercentages,
+		defaultTaskMetadata.TimeToExpiry,
+		defaultTaskMetadata.QuorumThresholdPercentages,
+	)
+	if err != nil {
+		return TaskInitializationErrorFn(err, taskIndex)
+	}
+
+	return a.InitializeNewTaskWithWindow(
 		taskIndex,
 		taskCreatedBlock,
 		quorumNumbers,
 		quorumThresholdPercentages,
 		timeToExpiry,
-		windowDuration,
-		signedTaskRespsC,
+		metadata.WindowDuration,
 	)
+}
+
+// RecoverTasks initializes again the tasks of the signature store of the service, which were in-flight when the
+// service stopped, e.g. on startup after a crash. Each task keeps its expiry time, and the signatures accepted for it
+// are verified and aggregated again against the operators state at its reference block, as if they were processed with
+// ProcessNewSignature. The tasks which already expired are discarded, and the tasks already initialized are skipped.
+// It does nothing if the service has no signature store.
+func (a *BlsAggregatorService) RecoverTasks(ctx context.Context) error {
+	if a.signatureStore == nil {
+		return nil
+	}
+	taskIndices, err := a.signatureStore.ListTasks()
+	if err != nil {
+		return utils.WrapError("failed to list stored tasks", err)
+	}
+	for _, taskIndex := range taskIndices {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		task, err := a.signatureStore.LoadTask(taskIndex)
+		if err != nil {
+			return utils.WrapError(fmt.Sprintf("failed to load stored task %d", taskIndex), err)
+		}
+		timeToExpiry := time.Until(task.ExpiresAt)
+		if timeToExpiry <= 0 {
+			a.logger.Info("Discarding expired stored task", "taskIndex", taskIndex, "expiresAt", task.ExpiresAt)
+			if err := a.signatureStore.DeleteTask(taskIndex); err != nil {
+				return utils.WrapError(fmt.Sprintf("failed to delete stored task %d", taskIndex), err)
+			}
+			continue
+		}
+		if err := a.recoverTask(task, timeToExpiry); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// recoverTask starts the goroutine of the stored task, which expires after timeToExpiry
+func (a *BlsAggregatorService) recoverTask(task StoredTask, timeToExpiry time.Duration) error {
+	if len(task.QuorumThresholdPercentages) != len(task.QuorumNumbers) {
+		return TaskInitializationErrorFn(
+			fmt.Errorf(
+				"stored task has %d quorum threshold percentages for %d quorums",
+				len(task.QuorumThresholdPercentages),
+				len(task.QuorumNumbers),
+			),
+			task.TaskIndex,
+		)
+	}
+	a.logger.Info(
+		"AggregatorService recovering stored task",
+		"taskIndex", task.TaskIndex,
+		"taskCreatedBlock", task.TaskCreatedBlock,
+		"signatures", len(task.Signatures),
+		"timeToExpiry", timeToExpiry,
+	)
+
+	newHandle := func() (blsTaskHandle, error) {
+		return newBlsTaskHandle(), nil
+	}
+	err := a.tasks.Start(task.TaskIndex, timeToExpiry, newHandle, func(t *aggregation.Task[blsTaskHandle]) {
+		a.singleTaskAggregatorGoroutineFunc(
+			t,
+			task.TaskCreatedBlock,
+			task.QuorumNumbers,
+			task.QuorumThresholdPercentages,
+			task.WindowDuration,
+			task.Signatures,
+		)
+	})
+	if errors.Is(err, aggregation.ErrTaskInFlight) {
+		a.logger.Warn("Stored task already initialized, skipping its recovery", "taskIndex", task.TaskIndex)
+		return nil
+	}
+	return taskStartError(task.TaskIndex, err)
+}
+
 func (a *BlsAggregatorService) ProcessNewSignature(
 	ctx context.Context,
 	taskIndex types.TaskIndex,
@@ -286,10 +736,9 @@ func (a *BlsAggregatorService) ProcessNewSignature(
 	blsSignature *bls.Signature,
 	operatorId types.OperatorId,
 ) error {
-	a.taskChansMutex.Lock()
-	taskC, taskInitialized := a.signedTaskRespsCs[taskIndex]
-	a.taskChansMutex.Unlock()
+	taskHandle, taskInitialized := a.tasks.Get(taskIndex)
 	if !taskInitialized {
+		a.metrics.IncrementRejectedSignatures(RejectedSignatureReasonLate)
 		return TaskNotFoundErrorFn(taskIndex)
 	}
 
@@ -300,7 +749,7 @@ func (a *BlsAggregatorService) ProcessNewSignature(
 	select {
 	// we need to send this as part of select because if the goroutine is processing another SignedTaskResponseDigest
 	// and cannot receive this one, we want the context to be able to cancel the request
-	case taskC <- types.SignedTaskResponseDigest{
+	case taskHandle.signedTaskRespsC <- types.SignedTaskResponseDigest{
 		TaskResponse:                taskResponse,
 		BlsSignature:                blsSignature,
 		OperatorId:                  operatorId,
@@ -315,32 +764,34 @@ func (a *BlsAggregatorService) ProcessNewSignature(
 }
 
 func (a *BlsAggregatorService) singleTaskAggregatorGoroutineFunc(
-	taskIndex types.TaskIndex,
+	task *aggregation.Task[blsTaskHandle],
 	taskCreatedBlock uint32,
 	quorumNumbers types.QuorumNums,
 	quorumThresholdPercentages []types.QuorumThresholdPercentage,
-	timeToExpiry time.Duration,
 	windowDuration time.Duration,
-	signedTaskRespsC <-chan types.SignedTaskResponseDigest,
+	recoveredSignatures []StoredSignature,
 ) {
+	taskIndex := task.Index
+	signedTaskRespsC := task.Handle.signedTaskRespsC
+	stateQuerier := task.Handle.stateQuerier
 	a.logger.Debug("AggregatorService goroutine processing new task",
 		"taskIndex", taskIndex,
 		"taskCreatedBlock", taskCreatedBlock)
 
-	defer a.closeTaskGoroutine(taskIndex)
-	quorumThresholdPercentagesMap := make(map[types.QuorumNum]types.QuorumThresholdPercentage)
+	taskStartedAt := time.Now()
+	a.metrics.IncrementInFlightTasks()
+	// the signatures of the task are refused with a TaskNotFoundError and the queries of its state aren't answered
+	// anymore once it completes, since sending its response may block until it is read
+	stopAcceptingRequests := task.Stop
 	for i, quorumNumber := range quorumNumbers {
-		quorumThresholdPercentagesMap[quorumNumber] = quorumThresholdPercentages[i]
 		a.logger.Debug("AggregatorService goroutine quorum threshold percentage",
 			"taskIndex", taskIndex,
 			"quorumNumber", quorumNumber,
 			"quorumThresholdPercentage", quorumThresholdPercentages[i])
 	}
-	operatorsAvsStateDict, err := a.avsRegistryService.GetOperatorsAvsStateAtBlock(
-		context.Background(),
-		quorumNumbers,
-		taskCreatedBlock,
-	)
+	// all the stakes and pubkeys of the task are the ones of this snapshot of the operators at the reference block of
+	// the task, which the signatures are verified and the signed stakes are accounted against
+	operatorsAvsStateDict, err := a.getTaskOperatorsAvsState(quorumNumbers, taskCreatedBlock)
 	if err != nil {
 		a.logger.Error(
 			"Task goroutine failed to get operators state from avs registry",
@@ -349,29 +800,25 @@ func (a *BlsAggregatorService) singleTaskAggregatorGoroutineFunc(
 			"err",
 			err,
 		)
-		a.aggregatedResponsesC <- BlsAggregationServiceResponse{
+		stopAcceptingRequests()
+		a.sendResponse(BlsAggregationServiceResponse{
 			Err:       TaskInitializationErrorFn(fmt.Errorf("AggregatorService failed to get operators state from avs registry at blockNum %d: %w", taskCreatedBlock, err), taskIndex),
 			TaskIndex: taskIndex,
-		}
+		})
 		return
 	}
-	quorumsAvsStakeDict, err := a.avsRegistryService.GetQuorumsAvsStateAtBlock(
-		context.Background(),
+	quorumsAvsStakeDict, err := avsregistry.QuorumsAvsStateFromOperatorsAvsState(
 		quorumNumbers,
 		taskCreatedBlock,
+		operatorsAvsStateDict,
 	)
 	if err != nil {
-		a.logger.Error(
-			"Task goroutine failed to get quorums state from avs registry",
-			"taskIndex",
-			taskIndex,
-			"err",
-			err,
-		)
-		a.aggregatedResponsesC <- BlsAggregationServiceResponse{
-			Err:       TaskInitializationErrorFn(fmt.Errorf("Aggregator failed to get quorums state from avs registry: %w", err), taskIndex),
+		a.logger.Error("Task goroutine failed to compute quorums state", "taskIndex", taskIndex, "err", err)
+		stopAcceptingRequests()
+		a.sendResponse(BlsAggregationServiceResponse{
+			Err:       TaskInitializationErrorFn(utils.WrapError("failed to compute quorums state", err), taskIndex),
 			TaskIndex: taskIndex,
-		}
+		})
 		return
 	}
 	totalStakePerQuorum := make(map[types.QuorumNum]*big.Int)
@@ -386,165 +833,375 @@ func (a *BlsAggregatorService) singleTaskAggregatorGoroutineFunc(
 	for _, quorumNumber := range quorumNumbers {
 		quorumApksG1 = append(quorumApksG1, quorumsAvsStakeDict[quorumNumber].AggPubkeyG1)
 	}
-
-	// TODO(samlaf): instead of taking a TTE, we should take a block as input
-	// and monitor the chain and only close the task goroutine when that block is reached
-	taskExpiredTimer := time.NewTimer(timeToExpiry)
+	// digestStakes are the stakes which signed each digest, each operator only signing one
+	digestStakes := aggregation.NewDigestStakes[types.OperatorId](
+		quorumNumbers,
+		quorumThresholdPercentages,
+		totalStakePerQuorum,
+	)
 
 	aggregatedOperatorsDict := map[types.TaskResponseDigest]aggregatedOperators{}
-	// The windowTimer is initialized to be longer than the taskExpiredTimer as it will
-	// be overwritten once the stake threshold is met
-	windowTimer := time.NewTimer(timeToExpiry + 1*time.Second)
+	// windowC receives once the window of the task ends, which only starts once the stake threshold is met
+	var windowC <-chan time.Time
 	openWindow := false
 	var lastSignedTaskResponseDigest types.SignedTaskResponseDigest
 	var lastDigestAggregatedOperators aggregatedOperators
 	var lastTaskResponseDigest types.TaskResponseDigest
-	for {
-		select {
-		case signedTaskResponseDigest := <-signedTaskRespsC:
-			a.logger.Debug(
-				"Task goroutine received new signed task response digest",
-				"taskIndex",
-				taskIndex,
-				"signedTaskResponseDigest",
-				signedTaskResponseDigest,
-			)
+	// the signatures are verified concurrently by the verification workers of the service, while they are only
+	// aggregated by this goroutine, in the order their verifications complete
+	verificationResultsC := make(chan signatureVerificationResult, a.verificationWorkers)
+	pendingVerifications := 0
+	duplicateSignatures := 0
+	conflictingSignatures := 0
+	// quorumSigners are the signers of the digest which met the stake thresholds at that time, only kept for the
+	// responses of the tasks with a window
+	var quorumSigners []types.OperatorId
 
-			// compute the taskResponseDigest using the hash function
-			taskResponseDigest, err := a.hashFunction(signedTaskResponseDigest.TaskResponse)
-			if err != nil {
-				// this error should never happen, because we've already hashed the taskResponse in verifySignature,
-				// but keeping here in case the verifySignature implementation ever changes or some catastrophic bug
-				// happens..
-				continue
+	// aggregateSignedTaskResponseDigest aggregates a signed task response digest once its signature is verified,
+	// sending the result of its verification to its SignatureVerificationErrorC. It's only stored if persist is set,
+	// the recovered signatures being already stored.
+	aggregateSignedTaskResponseDigest := func(result signatureVerificationResult) {
+		signedTaskResponseDigest := result.signedTaskResponseDigest
+		if result.err != nil {
+			var notPartOfTaskQuorumErr *OperatorNotPartOfTaskQuorumError
+			if errors.As(result.err, &notPartOfTaskQuorumErr) {
+				a.metrics.IncrementRejectedSignatures(RejectedSignatureReasonUnknownOperator)
+			} else {
+				a.metrics.IncrementRejectedSignatures(RejectedSignatureReasonInvalid)
 			}
+			signedTaskResponseDigest.SignatureVerificationErrorC <- result.err
+			return
+		}
 
-			// check if the operator has already signed for this digest
-			digestAggregatedOperators, ok := aggregatedOperatorsDict[taskResponseDigest]
-			if ok {
-				if digestAggregatedOperators.signersOperatorIdsSet[signedTaskResponseDigest.OperatorId] {
-					a.logger.Info(
-						"Duplicate signature received",
-						"operatorId", fmt.Sprintf("%x", signedTaskResponseDigest.OperatorId),
-						"taskIndex", taskIndex,
-					)
-					signedTaskResponseDigest.SignatureVerificationErrorC <- fmt.Errorf("duplicate signature from operator %x for task %d", signedTaskResponseDigest.OperatorId, taskIndex)
-					continue
-				}
-			}
+		// compute the taskResponseDigest using the hash function
+		taskResponseDigest, err := a.hashFunction(signedTaskResponseDigest.TaskResponse)
+		if err != nil {
+			// this error should never happen, because we've already hashed the taskResponse in verifySignature,
+			// but keeping here in case the verifySignature implementation ever changes or some catastrophic bug
+			// happens..
+			a.metrics.IncrementRejectedSignatures(RejectedSignatureReasonInvalid)
+			signedTaskResponseDigest.SignatureVerificationErrorC <- HashFunctionError(err)
+			return
+		}
 
-			err = a.verifySignature(taskIndex, signedTaskResponseDigest, operatorsAvsStateDict)
-			// return the err (or nil) to the operator, and then proceed to do aggregation logic asynchronously (when no
-			// error)
-			signedTaskResponseDigest.SignatureVerificationErrorC <- err
-			if err != nil {
-				continue
+		// check if the operator has already signed, possibly while its signature was verified
+		status, signedDigest := digestStakes.Check(signedTaskResponseDigest.OperatorId, taskResponseDigest)
+		switch status {
+		case aggregation.SignatureDuplicate:
+			a.logger.Info(
+				"Duplicate signature received",
+				"operatorId", signedTaskResponseDigest.OperatorId.String(),
+				"taskIndex", taskIndex,
+			)
+			duplicateSignatures++
+			signedTaskResponseDigest.SignatureVerificationErrorC <- nil
+			return
+		case aggregation.SignatureConflicting:
+			a.logger.Warn(
+				"Conflicting signature received",
+				"operatorId", signedTaskResponseDigest.OperatorId.String(),
+				"taskIndex", taskIndex,
+				"firstDigest", signedDigest,
+				"secondDigest", taskResponseDigest,
+			)
+			conflictingSignatures++
+			a.metrics.IncrementRejectedSignatures(RejectedSignatureReasonConflicting)
+			signedTaskResponseDigest.SignatureVerificationErrorC <- &ConflictingSignatureError{
+				OperatorId:   signedTaskResponseDigest.OperatorId,
+				TaskIndex:    taskIndex,
+				FirstDigest:  signedDigest,
+				SecondDigest: taskResponseDigest,
 			}
+			return
+		}
 
-			// after verifying signature we aggregate its sig and pubkey, and update the signed stake amount
-			if !ok {
-				// first operator to sign on this digest
-				digestAggregatedOperators = aggregatedOperators{
-					// we've already verified that the operator is part of the task's quorum, so we don't need checks
-					// here
-					signersApkG2: bls.NewZeroG2Point().
-						Add(operatorsAvsStateDict[signedTaskResponseDigest.OperatorId].OperatorInfo.Pubkeys.G2Pubkey),
-					signersAggSigG1:       signedTaskResponseDigest.BlsSignature,
-					signersOperatorIdsSet: map[types.OperatorId]bool{signedTaskResponseDigest.OperatorId: true},
-					signersTotalStakePerQuorum: cloneStakePerQuorumMap(
-						operatorsAvsStateDict[signedTaskResponseDigest.OperatorId].StakePerQuorum,
-					),
-				}
-			} else {
-				a.logger.Debug("Task goroutine updating existing aggregated operator signatures",
-					"taskIndex", taskIndex,
-					"taskResponseDigest", taskResponseDigest)
-
-				digestAggregatedOperators.signersAggSigG1.Add(signedTaskResponseDigest.BlsSignature)
-				digestAggregatedOperators.signersApkG2.Add(operatorsAvsStateDict[signedTaskResponseDigest.OperatorId].OperatorInfo.Pubkeys.G2Pubkey)
-				digestAggregatedOperators.signersOperatorIdsSet[signedTaskResponseDigest.OperatorId] = true
-				for quorumNum, stake := range operatorsAvsStateDict[signedTaskResponseDigest.OperatorId].StakePerQuorum {
-					if _, ok := digestAggregatedOperators.signersTotalStakePerQuorum[quorumNum]; !ok {
-						// if we haven't seen this quorum before, initialize its signed stake to 0
-						// possible if previous operators who sent us signatures were not part of this quorum
-						digestAggregatedOperators.signersTotalStakePerQuorum[quorumNum] = big.NewInt(0)
-					}
-					digestAggregatedOperators.signersTotalStakePerQuorum[quorumNum].Add(digestAggregatedOperators.signersTotalStakePerQuorum[quorumNum], stake)
-				}
+		if result.persist {
+			// the signature is only accepted once it is stored, so that it isn't lost if the service restarts
+			err = a.storeSignature(taskIndex, signedTaskResponseDigest)
+		}
+		// return the err (or nil) to the operator, and then proceed to do aggregation logic asynchronously (when no
+		// error)
+		signedTaskResponseDigest.SignatureVerificationErrorC <- err
+		if err != nil {
+			return
+		}
+
+		// after verifying signature we aggregate its sig and pubkey, and update the signed stake amount
+		operatorAvsState := operatorsAvsStateDict[signedTaskResponseDigest.OperatorId]
+		digestStakes.Add(signedTaskResponseDigest.OperatorId, taskResponseDigest, operatorAvsState.StakePerQuorum)
+		digestAggregatedOperators, ok := aggregatedOperatorsDict[taskResponseDigest]
+		if !ok {
+			// first operator to sign on this digest
+			digestAggregatedOperators = aggregatedOperators{
+				// we've already verified that the operator is part of the task's quorum, so we don't need checks
+				// here
+				signersApkG2: bls.NewZeroG2Point().Add(operatorAvsState.OperatorInfo.Pubkeys.G2Pubkey),
+				// the signatures are aggregated in a new one, not in the one of the sender
+				signersAggSigG1: bls.NewZeroSignature().Add(signedTaskResponseDigest.BlsSignature),
 			}
+		} else {
+			a.logger.Debug("Task goroutine updating existing aggregated operator signatures",
+				"taskIndex", taskIndex,
+				"taskResponseDigest", taskResponseDigest)
+
+			digestAggregatedOperators.signersAggSigG1.Add(signedTaskResponseDigest.BlsSignature)
+			digestAggregatedOperators.signersApkG2.Add(operatorAvsState.OperatorInfo.Pubkeys.G2Pubkey)
+		}
 
-			// update the buffer variables to be used when the window timer fires
+		// update the buffer variables to be used when the window timer fires. Once the window is open, they are the
+		// ones of the digest whose signers met the stake thresholds first, which the other digests can't replace.
+		if !openWindow || taskResponseDigest == lastTaskResponseDigest {
 			lastDigestAggregatedOperators = digestAggregatedOperators
 			lastTaskResponseDigest = taskResponseDigest
 			lastSignedTaskResponseDigest = signedTaskResponseDigest
+		}
+
+		// update the aggregatedOperatorsDict. Note that we need to assign the whole struct value at once,
+		// because of https://github.com/golang/go/issues/3117
+		aggregatedOperatorsDict[taskResponseDigest] = digestAggregatedOperators
+
+		if !openWindow && digestStakes.ThresholdsMet(taskResponseDigest) {
+			a.logger.Debug("Task goroutine stake threshold reached",
+				"taskIndex", taskIndex,
+				"taskResponseDigest", taskResponseDigest)
 
-			// update the aggregatedOperatorsDict. Note that we need to assign the whole struct value at once,
-			// because of https://github.com/golang/go/issues/3117
-			aggregatedOperatorsDict[taskResponseDigest] = digestAggregatedOperators
-
-			if !openWindow && checkIfStakeThresholdsMet(
-				a.logger,
-				digestAggregatedOperators.signersTotalStakePerQuorum,
-				totalStakePerQuorum,
-				quorumThresholdPercentagesMap,
-			) {
-				a.logger.Debug("Task goroutine stake threshold reached",
-					"taskIndex", taskIndex,
-					"taskResponseDigest", taskResponseDigest)
-
-				openWindow = true
-				windowTimer = time.NewTimer(windowDuration)
-				a.logger.Debug("Window timer started")
+			a.metrics.ObserveTimeToQuorum(time.Since(taskStartedAt))
+			openWindow = true
+			if windowDuration > 0 {
+				quorumSigners = sortedOperatorIds(digestStakes.DigestSigners(taskResponseDigest))
 			}
-		case <-taskExpiredTimer.C:
-			if openWindow {
-				a.sendAggregatedResponse(
-					operatorsAvsStateDict,
+			windowC = time.After(windowDuration)
+			a.logger.Debug("Window timer started")
+		}
+	}
+
+	// verifySignedTaskResponseDigest starts the verification of a signed task response digest once a verification
+	// worker is available, aggregating the signatures verified meanwhile. Waiting for a worker applies backpressure to
+	// the senders of the signatures, which aren't received meanwhile.
+	verifySignedTaskResponseDigest := func(signedTaskResponseDigest types.SignedTaskResponseDigest, persist bool) {
+		a.logger.Debug(
+			"Task goroutine received new signed task response digest",
+			"taskIndex",
+			taskIndex,
+			"signedTaskResponseDigest",
+			signedTaskResponseDigest,
+		)
+
+		a.metrics.IncrementQueuedVerifications()
+		defer a.metrics.DecrementQueuedVerifications()
+		for {
+			select {
+			case a.verificationWorkersC <- struct{}{}:
+				pendingVerifications++
+				go a.verifySignatureWorker(
 					taskIndex,
-					taskCreatedBlock,
-					lastSignedTaskResponseDigest,
-					lastDigestAggregatedOperators,
-					quorumNumbers,
-					lastTaskResponseDigest,
-					quorumApksG1,
+					signedTaskResponseDigest,
+					persist,
+					operatorsAvsStateDict,
+					verificationResultsC,
 				)
+				return
+			case result := <-verificationResultsC:
+				pendingVerifications--
+				aggregateSignedTaskResponseDigest(result)
+			}
+		}
+	}
+
+	// taskAggregationState returns the current aggregation state of the task
+	taskAggregationState := func() TaskAggregationState {
+		state := TaskAggregationState{
+			TaskIndex:               taskIndex,
+			TaskCreatedBlock:        taskCreatedBlock,
+			TaskResponseDigest:      lastTaskResponseDigest,
+			QuorumStakes:            make(map[types.QuorumNum]QuorumAggregationState, len(quorumNumbers)),
+			ThresholdsMet:           openWindow,
+			Signers:                 sortedOperatorIds(digestStakes.Signers()),
+			OtherDigestsSignedStake: otherDigestsSignedStake(digestStakes, lastTaskResponseDigest),
+			TimeToExpiry:            max(time.Until(task.ExpiresAt), 0),
+		}
+		signedStakePerQuorum := digestStakes.SignedStakePerQuorum(lastTaskResponseDigest)
+		for _, quorumNumber := range quorumNumbers {
+			signedStake := big.NewInt(0)
+			if stake, ok := signedStakePerQuorum[quorumNumber]; ok {
+				signedStake.Set(stake)
+			}
+			totalStake := new(big.Int).Set(totalStakePerQuorum[quorumNumber])
+			state.QuorumStakes[quorumNumber] = QuorumAggregationState{
+				SignedStake:           signedStake,
+				TotalStake:            totalStake,
+				SignedStakePercentage: aggregation.SignedStakePercentage(signedStake, totalStake),
+				ThresholdPercentage:   digestStakes.ThresholdPercentage(quorumNumber),
 			}
+		}
+		return state
+	}
 
-			a.aggregatedResponsesC <- BlsAggregationServiceResponse{
+	// waitPendingVerifications aggregates the signatures whose verifications are pending, which were received before
+	// the task completed
+	waitPendingVerifications := func() {
+		for ; pendingVerifications > 0; pendingVerifications-- {
+			aggregateSignedTaskResponseDigest(<-verificationResultsC)
+		}
+	}
+
+	// sendAggregatedResponse sends the response of the digest whose signers met the stake thresholds
+	sendAggregatedResponse := func() {
+		a.setSignedStakePercentages(digestStakes, lastTaskResponseDigest)
+		a.sendAggregatedResponse(
+			operatorsAvsStateDict,
+			taskIndex,
+			taskCreatedBlock,
+			lastSignedTaskResponseDigest,
+			lastDigestAggregatedOperators,
+			digestStakes,
+			quorumNumbers,
+			lastTaskResponseDigest,
+			quorumApksG1,
+			duplicateSignatures,
+			conflictingSignatures,
+			quorumSigners,
+		)
+	}
+
+	recoveredSignatureErrorCs := make([]chan error, len(recoveredSignatures))
+	for i, recoveredSignature := range recoveredSignatures {
+		recoveredSignatureErrorCs[i] = make(chan error, 1)
+		verifySignedTaskResponseDigest(types.SignedTaskResponseDigest{
+			TaskResponse:                recoveredSignature.TaskResponse,
+			BlsSignature:                recoveredSignature.BlsSignature,
+			OperatorId:                  recoveredSignature.OperatorId,
+			SignatureVerificationErrorC: recoveredSignatureErrorCs[i],
+		}, false)
+	}
+	waitPendingVerifications()
+	for i, recoveredSignature := range recoveredSignatures {
+		if err := <-recoveredSignatureErrorCs[i]; err != nil {
+			a.logger.Warn(
+				"Task goroutine dropping recovered signature",
+				"taskIndex", taskIndex,
+				"operatorId", recoveredSignature.OperatorId,
+				"err", err,
+			)
+		}
+	}
+
+	for {
+		select {
+		case signedTaskResponseDigest := <-signedTaskRespsC:
+			verifySignedTaskResponseDigest(signedTaskResponseDigest, true)
+		case result := <-verificationResultsC:
+			pendingVerifications--
+			aggregateSignedTaskResponseDigest(result)
+		case respC := <-stateQuerier.queryC:
+			respC <- taskAggregationState()
+		case <-task.ExpiredC:
+			stopAcceptingRequests()
+			waitPendingVerifications()
+			// the task whose window was cut short by its expiry met its stake thresholds, so it isn't counted as expired
+			if openWindow {
+				sendAggregatedResponse()
+			} else {
+				a.metrics.IncrementExpiredTasks()
+			}
+
+			a.sendResponse(BlsAggregationServiceResponse{
 				Err:       TaskExpiredErrorFn(taskIndex),
 				TaskIndex: taskIndex,
+			})
+			return
+		case <-task.CancelledC:
+			stopAcceptingRequests()
+			waitPendingVerifications()
+			// the task which met its stake thresholds is completed without waiting for the end of its window
+			if openWindow {
+				a.logger.Info("Task goroutine completing task before the end of its window", "taskIndex", taskIndex)
+				sendAggregatedResponse()
+				return
 			}
+			a.logger.Info("Task goroutine cancelling task", "taskIndex", taskIndex)
+			a.sendResponse(BlsAggregationServiceResponse{
+				Err:       &TaskCancelledError{TaskIndex: taskIndex},
+				TaskIndex: taskIndex,
+			})
 			return
-		case <-windowTimer.C:
+		case <-windowC:
 			a.logger.Debug("Window timer expired")
-			a.sendAggregatedResponse(
-				operatorsAvsStateDict,
-				taskIndex,
-				taskCreatedBlock,
-				lastSignedTaskResponseDigest,
-				lastDigestAggregatedOperators,
-				quorumNumbers,
-				lastTaskResponseDigest,
-				quorumApksG1,
-			)
+			stopAcceptingRequests()
+			waitPendingVerifications()
+			sendAggregatedResponse()
 			return
 		}
 	}
 }
 
+// verifySignatureWorker verifies the signature of signedTaskResponseDigest on a verification worker of the service,
+// which it then releases, and sends the result of the verification to verificationResultsC
+func (a *BlsAggregatorService) verifySignatureWorker(
+	taskIndex types.TaskIndex,
+	signedTaskResponseDigest types.SignedTaskResponseDigest,
+	persist bool,
+	operatorsAvsStateDict map[types.OperatorId]types.OperatorAvsState,
+	verificationResultsC chan<- signatureVerificationResult,
+) {
+	defer func() { <-a.verificationWorkersC }()
+	a.metrics.IncrementInFlightVerifications()
+	defer a.metrics.DecrementInFlightVerifications()
+	verificationResultsC <- signatureVerificationResult{
+		signedTaskResponseDigest: signedTaskResponseDigest,
+		persist:                  persist,
+		err:                      a.verifySignature(taskIndex, signedTaskResponseDigest, operatorsAvsStateDict),
+	}
+}
+
+// getTaskOperatorsAvsState returns the state of the operators registered in any of quorumNumbers at blockNumber, with
+// their stakes in quorumNumbers only
+func (a *BlsAggregatorService) getTaskOperatorsAvsState(
+	quorumNumbers types.QuorumNums,
+	blockNumber uint32,
+) (map[types.OperatorId]types.OperatorAvsState, error) {
+	operatorsAvsState, err := a.avsRegistryService.GetOperatorsAvsStateAtBlock(
+		context.Background(),
+		quorumNumbers,
+		blockNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	taskOperatorsAvsState := make(map[types.OperatorId]types.OperatorAvsState, len(operatorsAvsState))
+	for operatorId, operatorAvsState := range operatorsAvsState {
+		stakePerQuorum := make(map[types.QuorumNum]types.StakeAmount)
+		for _, quorumNumber := range quorumNumbers {
+			if stake, ok := operatorAvsState.StakePerQuorum[quorumNumber]; ok {
+				stakePerQuorum[quorumNumber] = new(big.Int).Set(stake)
+			}
+		}
+		if len(stakePerQuorum) == 0 {
+			continue
+		}
+		operatorAvsState.StakePerQuorum = stakePerQuorum
+		taskOperatorsAvsState[operatorId] = operatorAvsState
+	}
+	return taskOperatorsAvsState, nil
+}
+
 func (a *BlsAggregatorService) sendAggregatedResponse(
 	operatorsAvsStateDict map[types.OperatorId]types.OperatorAvsState,
 	taskIndex types.TaskIndex,
 	taskCreatedBlock uint32,
 	signedTaskResponseDigest types.SignedTaskResponseDigest,
 	digestAggregatedOperators aggregatedOperators,
+	digestStakes *aggregation.DigestStakes[types.OperatorId],
 	quorumNumbers types.QuorumNums,
 	taskResponseDigest types.TaskResponseDigest,
 	quorumApksG1 []*bls.G1Point,
+	duplicateSignatures int,
+	conflictingSignatures int,
+	quorumSigners []types.OperatorId,
 ) {
 	nonSignersOperatorIds := []types.OperatorId{}
 	for operatorId := range operatorsAvsStateDict {
-		if _, operatorSigned := digestAggregatedOperators.signersOperatorIdsSet[operatorId]; !operatorSigned {
+		if !digestStakes.Signed(operatorId, taskResponseDigest) {
 			nonSignersOperatorIds = append(nonSignersOperatorIds, operatorId)
 		}
 	}
@@ -569,10 +1226,10 @@ func (a *BlsAggregatorService) sendAggregatedResponse(
 		nonSignersOperatorIds,
 	)
 	if err != nil {
-		a.aggregatedResponsesC <- BlsAggregationServiceResponse{
+		a.sendResponse(BlsAggregationServiceResponse{
 			Err:       utils.WrapError(errors.New("Failed to get check signatures indices"), err),
 			TaskIndex: taskIndex,
-		}
+		})
 		return
 	}
 
@@ -589,18 +1246,143 @@ func (a *BlsAggregatorService) sendAggregatedResponse(
 		QuorumApkIndices:             indices.QuorumApkIndices,
 		TotalStakeIndices:            indices.TotalStakeIndices,
 		NonSignerStakeIndices:        indices.NonSignerStakeIndices,
+		DuplicateSignatures:          duplicateSignatures,
+		ConflictingSignatures:        conflictingSignatures,
+		OtherDigestsSignedStake:      otherDigestsSignedStake(digestStakes, taskResponseDigest),
+	}
+	if quorumSigners != nil {
+		blsAggregationServiceResponse.QuorumSigners = quorumSigners
+		blsAggregationServiceResponse.Signers = sortedOperatorIds(digestStakes.DigestSigners(taskResponseDigest))
 	}
-	a.aggregatedResponsesC <- blsAggregationServiceResponse
+	a.sendResponse(blsAggregationServiceResponse)
+}
+
+// sortedOperatorIds sorts operatorIds by id and returns them
+func sortedOperatorIds(operatorIds []types.OperatorId) []types.OperatorId {
+	sort.Slice(operatorIds, func(i, j int) bool {
+		return bytes.Compare(operatorIds[i][:], operatorIds[j][:]) < 0
+	})
+	return operatorIds
+}
+
+// setSignedStakePercentages sets the signed stake percentage metrics to the percentages of the total stake of each
+// quorum signed by the signers of taskResponseDigest
+func (a *BlsAggregatorService) setSignedStakePercentages(
+	digestStakes *aggregation.DigestStakes[types.OperatorId],
+	taskResponseDigest types.TaskResponseDigest,
+) {
+	for quorumNum, percentage := range digestStakes.SignedStakePercentages(taskResponseDigest) {
+		a.metrics.SetSignedStakePercentage(quorumNum, percentage)
+	}
+}
+
+// GetTaskAggregationState returns the current aggregation state of the in-flight task taskIndex, e.g. to check how
+// close it is to meeting its stake thresholds. It returns a TaskNotFoundError if the task isn't in-flight, which
+// includes the tasks completing, whose response is being delivered.
+func (a *BlsAggregatorService) GetTaskAggregationState(taskIndex types.TaskIndex) (TaskAggregationState, error) {
+	taskHandle, taskInFlight := a.tasks.Get(taskIndex)
+	if !taskInFlight {
+		return TaskAggregationState{}, TaskNotFoundErrorFn(taskIndex)
+	}
+	stateQuerier := taskHandle.stateQuerier
+
+	respC := make(chan TaskAggregationState, 1)
+	select {
+	case stateQuerier.queryC <- respC:
+		return <-respC, nil
+	case <-stateQuerier.doneC:
+		return TaskAggregationState{}, TaskNotFoundErrorFn(taskIndex)
+	}
+}
+
+// WriteTaskAggregationStateJSON writes the aggregation state of the in-flight task taskIndex to w as JSON, see
+// GetTaskAggregationState. It returns found=false if the task isn't in-flight.
+func (a *BlsAggregatorService) WriteTaskAggregationStateJSON(taskIndex types.TaskIndex, w io.Writer) (bool, error) {
+	state, err := a.GetTaskAggregationState(taskIndex)
+	if errors.Is(err, ErrTaskNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.NewEncoder(w).Encode(state)
+}
+
+// sendResponse delivers response to the response handler of the service if any, or sends it on the response channel
+// otherwise, or if the response handler doesn't return within its timeout
+func (a *BlsAggregatorService) sendResponse(response BlsAggregationServiceResponse) {
+	a.responses.Send(response.TaskIndex, response)
+}
+
+// Close stops the service, see BlsAggregationService.Close. It returns ctx.Err() if tasks were cancelled, and nil if
+// all the in-flight tasks completed or expired before. It's safe to call several times, the later calls waiting for
+// the first one and returning its result.
+func (a *BlsAggregatorService) Close(ctx context.Context) error {
+	return a.tasks.Close(ctx, a.responses.Close)
+}
+
+// Name identifies the service in the health reports, see services.HealthRegistry
+func (a *BlsAggregatorService) Name() string {
+	return "bls_aggregation"
 }
 
-// closeTaskGoroutine is run when the goroutine processing taskIndex's task responses ends (for whatever reason)
-// it deletes the response channel for taskIndex from a.taskChans
-// so that the main thread knows that this task goroutine is no longer running
-// and doesn't try to send new signatures to it
-func (a *BlsAggregatorService) closeTaskGoroutine(taskIndex types.TaskIndex) {
-	a.taskChansMutex.Lock()
-	delete(a.signedTaskRespsCs, taskIndex)
-	a.taskChansMutex.Unlock()
+// Health reports the service as degraded when the response channel is backed up, i.e. when at least
+// maxPendingResponses responses are waiting to be read from it, see WithMaxPendingResponses
+func (a *BlsAggregatorService) Health(ctx context.Context) (services.ServiceHealth, string) {
+	return a.responses.Health(ctx)
+}
+
+func newTaskStateQuerier() taskStateQuerier {
+	return taskStateQuerier{
+		queryC: make(chan chan<- TaskAggregationState),
+		doneC:  make(chan struct{}),
+	}
+}
+
+func newBlsTaskHandle() blsTaskHandle {
+	return blsTaskHandle{
+		signedTaskRespsC: make(chan types.SignedTaskResponseDigest),
+		stateQuerier:     newTaskStateQuerier(),
+	}
+}
+
+// stopTask is run when the goroutine processing taskIndex's task responses stops accepting them (for whatever
+// reason), once the handle of the task is removed from the in-flight tasks so that the main thread doesn't try to
+// send new signatures to it
+func (a *BlsAggregatorService) stopTask(taskIndex types.TaskIndex, handle blsTaskHandle) {
+	close(handle.stateQuerier.doneC)
+	a.metrics.DecrementInFlightTasks()
+	// the task isn't in-flight anymore, so it isn't recovered if the service restarts
+	if a.signatureStore != nil {
+		if err := a.signatureStore.DeleteTask(taskIndex); err != nil {
+			a.logger.Error("Failed to delete stored task", "taskIndex", taskIndex, "err", err)
+		}
+	}
+}
+
+// storeSignature saves the signature of signedTaskResponseDigest to the signature store of the service, if any
+func (a *BlsAggregatorService) storeSignature(
+	taskIndex types.TaskIndex,
+	signedTaskResponseDigest types.SignedTaskResponseDigest,
+) error {
+	if a.signatureStore == nil {
+		return nil
+	}
+	err := a.signatureStore.Save(taskIndex, StoredSignature{
+		OperatorId:   signedTaskResponseDigest.OperatorId,
+		TaskResponse: signedTaskResponseDigest.TaskResponse,
+		BlsSignature: signedTaskResponseDigest.BlsSignature,
+	})
+	if err != nil {
+		a.logger.Error(
+			"Failed to store signature",
+			"taskIndex", taskIndex,
+			"operatorId", signedTaskResponseDigest.OperatorId,
+			"err", err,
+		)
+		return utils.WrapError("failed to store signature", err)
+	}
+	return nil
 }
 
 // verifySignature verifies that a signature is valid against the operator pubkey stored in the
@@ -646,7 +1428,7 @@ func (a *BlsAggregatorService) verifySignature(
 			taskIndex,
 		)
 		return fmt.Errorf(
-			"taskId %d: Operator G2 pubkey not found (operatorId: %x)",
+			"taskId %d: Operator G2 pubkey not found (operatorId: %s)",
 			taskIndex,
 			signedTaskResponseDigest.OperatorId,
 		)
@@ -669,58 +1451,19 @@ func (a *BlsAggregatorService) verifySignature(
 	return nil
 }
 
-// checkIfStakeThresholdsMet checks at least quorumThresholdPercentage of stake
-// has signed for each quorum.
-func checkIfStakeThresholdsMet(
-	logger logging.Logger,
-	signedStakePerQuorum map[types.QuorumNum]*big.Int,
-	totalStakePerQuorum map[types.QuorumNum]*big.Int,
-	quorumThresholdPercentagesMap map[types.QuorumNum]types.QuorumThresholdPercentage,
-) bool {
-	logger.Debug("Checking if stake thresholds are met.")
-	for quorumNum, quorumThresholdPercentage := range quorumThresholdPercentagesMap {
-		signedStakeByQuorum, ok := signedStakePerQuorum[quorumNum]
-		if !ok {
-			// signedStakePerQuorum not contain the quorum,
-			// this case means signedStakePerQuorum has not signed for each quorum.
-			// even the total stake for this quorum is zero.
-			return false
-		}
-
-		totalStakeByQuorum, ok := totalStakePerQuorum[quorumNum]
-		if !ok {
-			// Note this case should not happen.
-			// The `totalStakePerQuorum` is got from the contract, so if we not found the
-			// totalStakeByQuorum, that means the code have a bug.
-			logger.Errorf("TotalStake not found for quorum %d.", quorumNum)
-			return false
-		}
-
-		logger.Debug("Stakes for quorum",
-			"quorumNum", quorumNum,
-			"totalStakeByQuorum", totalStakeByQuorum,
-			"signedStakeByQuorum", signedStakeByQuorum)
-
-		// we check that signedStake >= totalStake * quorumThresholdPercentage / 100
-		// to be exact (and do like the contracts), we actually check that
-		// signedStake * 100 >= totalStake * quorumThresholdPercentage
-		signedStake := big.NewInt(0).Mul(signedStakeByQuorum, big.NewInt(100))
-		thresholdStake := big.NewInt(0).Mul(totalStakeByQuorum, big.NewInt(int64(quorumThresholdPercentage)))
-
-		logger.Debug("Checking if signed stake is greater than threshold",
-			"signedStake", signedStake,
-			"thresholdStake", thresholdStake)
-		if signedStake.Cmp(thresholdStake) < 0 {
-			return false
-		}
-	}
-	return true
-}
-
-func cloneStakePerQuorumMap(stakes map[types.QuorumNum]types.StakeAmount) map[types.QuorumNum]types.StakeAmount {
-	out := make(map[types.QuorumNum]types.StakeAmount, len(stakes))
-	for k, v := range stakes {
-		out[k] = new(big.Int).Set(v)
+// otherDigestsSignedStake returns the stake which signed the digests of digestStakes other than taskResponseDigest,
+// sorted by digest, or nil if there's none
+func otherDigestsSignedStake(
+	digestStakes *aggregation.DigestStakes[types.OperatorId],
+	taskResponseDigest types.TaskResponseDigest,
+) []DigestSignedStake {
+	var digestsSignedStake []DigestSignedStake
+	for _, digest := range digestStakes.OtherDigests(taskResponseDigest) {
+		digestsSignedStake = append(digestsSignedStake, DigestSignedStake{
+			TaskResponseDigest:   digest,
+			SignedStakePerQuorum: digestStakes.SignedStakePerQuorum(digest),
+			Signers:              len(digestStakes.DigestSigners(digest)),
+		})
 	}
-	return out
+	return digestsSignedStake
 }
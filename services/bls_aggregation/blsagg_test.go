@@ -1,12 +1,18 @@
 package blsagg
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
 	"math/big"
 	"os"
+	"runtime"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,15 +22,22 @@ import (
 	"github.com/Layr-Labs/eigensdk-go/chainio/utils"
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
 	"github.com/Layr-Labs/eigensdk-go/services/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/services/mocks"
 	"github.com/Layr-Labs/eigensdk-go/services/operatorsinfo"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/mock/gomock"
 
 	avssm "github.com/Layr-Labs/eigensdk-go/contracts/bindings/MockAvsServiceManager"
+	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
 )
 
 // TestBlsAgg is a suite of test that tests the main aggregation logic of the aggregation service
@@ -107,7 +120,7 @@ func TestBlsAgg(t *testing.T) {
 			SignersApkG2:        testOperator1.BlsKeypair.GetPubKeyG2(),
 			SignersAggSigG1:     testOperator1.BlsKeypair.SignMessage(taskResponseDigest),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 	})
@@ -197,7 +210,7 @@ func TestBlsAgg(t *testing.T) {
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)).
 				Add(testOperator3.BlsKeypair.SignMessage(taskResponseDigest)),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 	})
@@ -273,7 +286,7 @@ func TestBlsAgg(t *testing.T) {
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.EqualValues(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 	})
 
@@ -401,8 +414,8 @@ func TestBlsAgg(t *testing.T) {
 		}
 
 		// we don't know which of task1 or task2 responses will be received first
-		gotAggregationServiceResponseTaskFirstReceived := <-blsAggServ.aggregatedResponsesC
-		gotAggregationServiceResponseTaskSecondReceived := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponseTaskFirstReceived := <-blsAggServ.GetResponseChannel()
+		gotAggregationServiceResponseTaskSecondReceived := <-blsAggServ.GetResponseChannel()
 
 		if gotAggregationServiceResponseTaskFirstReceived.TaskIndex == task1Index {
 			require.EqualValues(t, wantAggregationServiceResponseTask1, gotAggregationServiceResponseTaskFirstReceived)
@@ -472,12 +485,8 @@ func TestBlsAgg(t *testing.T) {
 			testOperator1.OperatorId,
 		)
 
-		if err != nil {
-			logger.Info("Received error from second signature", "error", err)
-			require.Contains(t, err.Error(), "duplicate signature")
-		} else {
-			t.Fatal("Expected an error for duplicate signature, but got nil")
-		}
+		// the duplicate signature is accepted, but only counted once
+		require.NoError(t, err)
 
 		logger.Info("Processing second signature", "operatorId", testOperator2.OperatorId)
 		blsSigOp2 := testOperator2.BlsKeypair.SignMessage(taskResponseDigest)
@@ -503,11 +512,229 @@ func TestBlsAgg(t *testing.T) {
 				Add(testOperator2.BlsKeypair.GetPubKeyG2()),
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)),
+			DuplicateSignatures: 1,
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.EqualValues(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 
 	})
+	t.Run("1 quorum 2 operators operator 1 signs 2 digests - conflicting signature refused", func(t *testing.T) {
+		testOperator1 := types.TestOperator{
+			OperatorId:     types.OperatorId{1},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+			BlsKeypair:     newBlsKeyPairPanics("0x1"),
+		}
+		testOperator2 := types.TestOperator{
+			OperatorId:     types.OperatorId{2},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+			BlsKeypair:     newBlsKeyPairPanics("0x2"),
+		}
+		blockNum := uint32(1)
+		taskIndex := types.TaskIndex(0)
+		taskResponse1 := mockTaskResponse{123}
+		taskResponseDigest1, err := hashFunction(taskResponse1)
+		require.NoError(t, err)
+		taskResponse2 := mockTaskResponse{456}
+		taskResponseDigest2, err := hashFunction(taskResponse2)
+		require.NoError(t, err)
+
+		fakeAvsRegistryService := avsregistry.NewFakeAvsRegistryService(
+			blockNum,
+			[]types.TestOperator{testOperator1, testOperator2},
+		)
+		metrics := NewMetrics(prometheus.NewRegistry(), "test")
+		blsAggServ := NewBlsAggregatorService(
+			fakeAvsRegistryService,
+			hashFunction,
+			testutils.GetTestLogger(),
+			WithMetrics(metrics),
+		)
+		err = blsAggServ.InitializeNewTask(
+			taskIndex,
+			blockNum,
+			types.QuorumNums{0},
+			[]types.QuorumThresholdPercentage{100},
+			10*time.Second,
+		)
+		require.NoError(t, err)
+
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse1,
+			testOperator1.BlsKeypair.SignMessage(taskResponseDigest1),
+			testOperator1.OperatorId,
+		)
+		require.NoError(t, err)
+		// an incorrect signature over another digest isn't a conflicting one
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse2,
+			testOperator2.BlsKeypair.SignMessage(taskResponseDigest2),
+			testOperator1.OperatorId,
+		)
+		require.Equal(t, IncorrectSignatureError, err)
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse2,
+			testOperator1.BlsKeypair.SignMessage(taskResponseDigest2),
+			testOperator1.OperatorId,
+		)
+		require.ErrorIs(t, err, ErrConflictingSignature)
+		var conflictingSignatureErr *ConflictingSignatureError
+		require.ErrorAs(t, err, &conflictingSignatureErr)
+		require.Equal(t, &ConflictingSignatureError{
+			OperatorId:   testOperator1.OperatorId,
+			TaskIndex:    taskIndex,
+			FirstDigest:  taskResponseDigest1,
+			SecondDigest: taskResponseDigest2,
+		}, conflictingSignatureErr)
+		require.Equal(t, float64(1), testutil.ToFloat64(metrics.rejectedSignatures.WithLabelValues("conflicting")))
+
+		// operator 1 only counts towards the first digest
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse1,
+			testOperator2.BlsKeypair.SignMessage(taskResponseDigest1),
+			testOperator2.OperatorId,
+		)
+		require.NoError(t, err)
+		wantAggregationServiceResponse := BlsAggregationServiceResponse{
+			Err:                 nil,
+			TaskIndex:           taskIndex,
+			TaskResponse:        taskResponse1,
+			TaskResponseDigest:  taskResponseDigest1,
+			NonSignersPubkeysG1: []*bls.G1Point{},
+			QuorumApksG1: []*bls.G1Point{testOperator1.BlsKeypair.GetPubKeyG1().
+				Add(testOperator2.BlsKeypair.GetPubKeyG1()),
+			},
+			SignersApkG2: testOperator1.BlsKeypair.GetPubKeyG2().
+				Add(testOperator2.BlsKeypair.GetPubKeyG2()),
+			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest1).
+				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest1)),
+			ConflictingSignatures: 1,
+		}
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
+		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
+	})
+
+	t.Run("1 quorum 2 operators stake split 40/60 across 2 digests", func(t *testing.T) {
+		testOperator1 := types.TestOperator{
+			OperatorId:     types.OperatorId{1},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(40)},
+			BlsKeypair:     newBlsKeyPairPanics("0x1"),
+		}
+		testOperator2 := types.TestOperator{
+			OperatorId:     types.OperatorId{2},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(60)},
+			BlsKeypair:     newBlsKeyPairPanics("0x2"),
+		}
+		blockNum := uint32(1)
+		taskIndex := types.TaskIndex(0)
+		taskResponse1 := mockTaskResponse{123}
+		taskResponseDigest1, err := hashFunction(taskResponse1)
+		require.NoError(t, err)
+		taskResponse2 := mockTaskResponse{456}
+		taskResponseDigest2, err := hashFunction(taskResponse2)
+		require.NoError(t, err)
+
+		var tests = map[string]struct {
+			quorumThresholdPercentage types.QuorumThresholdPercentage
+			windowDuration            time.Duration
+			wantResponse              BlsAggregationServiceResponse
+		}{
+			"60% digest meets the threshold": {
+				quorumThresholdPercentage: 50,
+				wantResponse: BlsAggregationServiceResponse{
+					TaskIndex:           taskIndex,
+					TaskResponse:        taskResponse2,
+					TaskResponseDigest:  taskResponseDigest2,
+					NonSignersPubkeysG1: []*bls.G1Point{testOperator1.BlsKeypair.GetPubKeyG1()},
+					SignersApkG2:        testOperator2.BlsKeypair.GetPubKeyG2(),
+					SignersAggSigG1:     testOperator2.BlsKeypair.SignMessage(taskResponseDigest2),
+					OtherDigestsSignedStake: []DigestSignedStake{{
+						TaskResponseDigest:   taskResponseDigest1,
+						SignedStakePerQuorum: map[types.QuorumNum]*big.Int{0: big.NewInt(40)},
+						Signers:              1,
+					}},
+				},
+			},
+			"40% digest meets the threshold first": {
+				quorumThresholdPercentage: 40,
+				windowDuration:            500 * time.Millisecond,
+				wantResponse: BlsAggregationServiceResponse{
+					TaskIndex:           taskIndex,
+					TaskResponse:        taskResponse1,
+					TaskResponseDigest:  taskResponseDigest1,
+					NonSignersPubkeysG1: []*bls.G1Point{testOperator2.BlsKeypair.GetPubKeyG1()},
+					SignersApkG2:        testOperator1.BlsKeypair.GetPubKeyG2(),
+					SignersAggSigG1:     testOperator1.BlsKeypair.SignMessage(taskResponseDigest1),
+					OtherDigestsSignedStake: []DigestSignedStake{{
+						TaskResponseDigest:   taskResponseDigest2,
+						SignedStakePerQuorum: map[types.QuorumNum]*big.Int{0: big.NewInt(60)},
+						Signers:              1,
+					}},
+					QuorumSigners: []types.OperatorId{testOperator1.OperatorId},
+					Signers:       []types.OperatorId{testOperator1.OperatorId},
+				},
+			},
+			"no digest meets the threshold": {
+				quorumThresholdPercentage: 70,
+				wantResponse: BlsAggregationServiceResponse{
+					Err:       TaskExpiredErrorFn(taskIndex),
+					TaskIndex: taskIndex,
+				},
+			},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				fakeAvsRegistryService := avsregistry.NewFakeAvsRegistryService(
+					blockNum,
+					[]types.TestOperator{testOperator1, testOperator2},
+				)
+				blsAggServ := NewBlsAggregatorService(fakeAvsRegistryService, hashFunction, testutils.GetTestLogger())
+				err := blsAggServ.InitializeNewTaskWithWindow(
+					taskIndex,
+					blockNum,
+					types.QuorumNums{0},
+					[]types.QuorumThresholdPercentage{tt.quorumThresholdPercentage},
+					tasksTimeToExpiry,
+					tt.windowDuration,
+				)
+				require.NoError(t, err)
+
+				err = blsAggServ.ProcessNewSignature(
+					context.Background(),
+					taskIndex,
+					taskResponse1,
+					testOperator1.BlsKeypair.SignMessage(taskResponseDigest1),
+					testOperator1.OperatorId,
+				)
+				require.NoError(t, err)
+				err = blsAggServ.ProcessNewSignature(
+					context.Background(),
+					taskIndex,
+					taskResponse2,
+					testOperator2.BlsKeypair.SignMessage(taskResponseDigest2),
+					testOperator2.OperatorId,
+				)
+				require.NoError(t, err)
+
+				wantResponse := tt.wantResponse
+				if wantResponse.Err == nil {
+					wantResponse.QuorumApksG1 = []*bls.G1Point{
+						testOperator1.BlsKeypair.GetPubKeyG1().Add(testOperator2.BlsKeypair.GetPubKeyG1()),
+					}
+				}
+				gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
+				require.Equal(t, wantResponse, gotAggregationServiceResponse)
+			})
+		}
+	})
+
 	t.Run("1 quorum 1 operator 0 signatures - task expired", func(t *testing.T) {
 		testOperator1 := types.TestOperator{
 			OperatorId:     types.OperatorId{1},
@@ -534,7 +761,7 @@ func TestBlsAgg(t *testing.T) {
 		wantAggregationServiceResponse := BlsAggregationServiceResponse{
 			Err: TaskExpiredErrorFn(taskIndex),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 	})
 
@@ -593,7 +820,7 @@ func TestBlsAgg(t *testing.T) {
 			SignersApkG2:    testOperator1.BlsKeypair.GetPubKeyG2(),
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 	})
 
@@ -643,7 +870,7 @@ func TestBlsAgg(t *testing.T) {
 		wantAggregationServiceResponse := BlsAggregationServiceResponse{
 			Err: TaskExpiredErrorFn(taskIndex),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 	})
 
@@ -716,7 +943,7 @@ func TestBlsAgg(t *testing.T) {
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.EqualValues(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 	})
 
@@ -802,7 +1029,7 @@ func TestBlsAgg(t *testing.T) {
 				SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 					Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)),
 			}
-			gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+			gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 			require.EqualValues(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		},
 	)
@@ -872,7 +1099,7 @@ func TestBlsAgg(t *testing.T) {
 			wantAggregationServiceResponse := BlsAggregationServiceResponse{
 				Err: TaskExpiredErrorFn(taskIndex),
 			}
-			gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+			gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 			require.EqualValues(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 			require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 		},
@@ -918,7 +1145,7 @@ func TestBlsAgg(t *testing.T) {
 		wantAggregationServiceResponse := BlsAggregationServiceResponse{
 			Err: TaskExpiredErrorFn(taskIndex),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.EqualValues(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 	})
@@ -973,7 +1200,7 @@ func TestBlsAgg(t *testing.T) {
 			wantAggregationServiceResponse := BlsAggregationServiceResponse{
 				Err: TaskExpiredErrorFn(taskIndex),
 			}
-			gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+			gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 			require.EqualValues(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 			require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 		},
@@ -1075,12 +1302,83 @@ func TestBlsAgg(t *testing.T) {
 				SignersApkG2:        testOperator1.BlsKeypair.GetPubKeyG2(),
 				SignersAggSigG1:     testOperator1.BlsKeypair.SignMessage(taskResponseDigest1),
 			}
-			gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+			gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 			require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 			require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 		},
 	)
 
+	t.Run("operator registered after the task reference block - signature refused", func(t *testing.T) {
+		testOperator1 := types.TestOperator{
+			OperatorId:     types.OperatorId{1},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+			BlsKeypair:     newBlsKeyPairPanics("0x1"),
+		}
+		testOperator2 := types.TestOperator{
+			OperatorId:     types.OperatorId{2},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(300)},
+			BlsKeypair:     newBlsKeyPairPanics("0x2"),
+		}
+		referenceBlock := uint32(1)
+		taskIndex := types.TaskIndex(0)
+		quorumNumbers := types.QuorumNums{0}
+		quorumThresholdPercentages := []types.QuorumThresholdPercentage{100}
+		taskResponse := mockTaskResponse{123}
+		taskResponseDigest, err := hashFunction(taskResponse)
+		require.Nil(t, err)
+
+		fakeAvsRegistryService := avsregistry.NewFakeAvsRegistryService(
+			referenceBlock,
+			[]types.TestOperator{testOperator1},
+		)
+		// operator 2 registers after the reference block, with the stake which would meet the threshold without
+		// operator 1
+		fakeAvsRegistryService.SetOperatorsAtBlock(referenceBlock+1, []types.TestOperator{testOperator1, testOperator2})
+		blsAggServ := NewBlsAggregatorService(fakeAvsRegistryService, hashFunction, testutils.GetTestLogger())
+
+		err = blsAggServ.InitializeNewTask(
+			taskIndex,
+			referenceBlock,
+			quorumNumbers,
+			quorumThresholdPercentages,
+			tasksTimeToExpiry,
+		)
+		require.Nil(t, err)
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse,
+			testOperator2.BlsKeypair.SignMessage(taskResponseDigest),
+			testOperator2.OperatorId,
+		)
+		var notPartOfQuorumErr *OperatorNotPartOfTaskQuorumError
+		require.ErrorAs(t, err, &notPartOfQuorumErr)
+		require.Equal(t, testOperator2.OperatorId, notPartOfQuorumErr.OperatorId)
+		require.Equal(t, taskIndex, notPartOfQuorumErr.TaskIndex)
+
+		// the stake of operator 2 isn't accounted, so the task only completes with the signature of operator 1
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse,
+			testOperator1.BlsKeypair.SignMessage(taskResponseDigest),
+			testOperator1.OperatorId,
+		)
+		require.Nil(t, err)
+		wantAggregationServiceResponse := BlsAggregationServiceResponse{
+			Err:                 nil,
+			TaskIndex:           taskIndex,
+			TaskResponse:        taskResponse,
+			TaskResponseDigest:  taskResponseDigest,
+			NonSignersPubkeysG1: []*bls.G1Point{},
+			QuorumApksG1:        []*bls.G1Point{testOperator1.BlsKeypair.GetPubKeyG1()},
+			SignersApkG2:        testOperator1.BlsKeypair.GetPubKeyG2(),
+			SignersAggSigG1:     testOperator1.BlsKeypair.SignMessage(taskResponseDigest),
+		}
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
+		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
+	})
+
 	t.Run("1 quorum 2 operator 2 signatures on 2 different msgs - task expired", func(t *testing.T) {
 		testOperator1 := types.TestOperator{
 			OperatorId:     types.OperatorId{1},
@@ -1139,7 +1437,7 @@ func TestBlsAgg(t *testing.T) {
 		wantAggregationServiceResponse := BlsAggregationServiceResponse{
 			Err: TaskExpiredErrorFn(taskIndex),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 	})
@@ -1280,8 +1578,15 @@ func TestBlsAgg(t *testing.T) {
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)).
 				Add(testOperator3.BlsKeypair.SignMessage(taskResponseDigest)),
+			// the signature of operator 3 is aggregated during the window
+			QuorumSigners: []types.OperatorId{testOperator1.OperatorId, testOperator2.OperatorId},
+			Signers: []types.OperatorId{
+				testOperator1.OperatorId,
+				testOperator2.OperatorId,
+				testOperator3.OperatorId,
+			},
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 		elapsed := time.Since(start)
@@ -1387,8 +1692,15 @@ func TestBlsAgg(t *testing.T) {
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)).
 				Add(testOperator3.BlsKeypair.SignMessage(taskResponseDigest)),
+			// the signature of operator 3 is aggregated during the window
+			QuorumSigners: []types.OperatorId{testOperator1.OperatorId, testOperator2.OperatorId},
+			Signers: []types.OperatorId{
+				testOperator1.OperatorId,
+				testOperator2.OperatorId,
+				testOperator3.OperatorId,
+			},
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 		elapsed := time.Since(start)
@@ -1464,8 +1776,8 @@ func TestBlsAgg(t *testing.T) {
 		require.Nil(t, err)
 
 		time.Sleep(1 * time.Millisecond)
-		// quorum has already been reached, next signatures should not be aggregated
-		// this should timeout as the task goroutine is blocked on the response channel
+		// quorum has already been reached and the window ended, next signatures are refused even though the response
+		// isn't read yet
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 		blsSigOp3 := testOperator3.BlsKeypair.SignMessage(taskResponseDigest)
@@ -1476,7 +1788,7 @@ func TestBlsAgg(t *testing.T) {
 			blsSigOp3,
 			testOperator3.OperatorId,
 		)
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.Equal(t, TaskNotFoundErrorFn(taskIndex), err)
 
 		wantAggregationServiceResponse := BlsAggregationServiceResponse{
 			Err:                 nil,
@@ -1493,7 +1805,7 @@ func TestBlsAgg(t *testing.T) {
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)),
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 		elapsed := time.Since(start)
@@ -1569,8 +1881,8 @@ func TestBlsAgg(t *testing.T) {
 
 		time.Sleep(2 * time.Second)
 
-		// quorum has already been reached, next signatures should not be aggregated
-		// this should timeout as the task goroutine is blocked on the response channel
+		// quorum has already been reached and the window ended, next signatures are refused even though the response
+		// isn't read yet
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 		blsSigOp3 := testOperator3.BlsKeypair.SignMessage(taskResponseDigest)
@@ -1581,7 +1893,7 @@ func TestBlsAgg(t *testing.T) {
 			blsSigOp3,
 			testOperator3.OperatorId,
 		)
-		require.Equal(t, context.DeadlineExceeded, err)
+		require.Equal(t, TaskNotFoundErrorFn(taskIndex), err)
 
 		wantAggregationServiceResponse := BlsAggregationServiceResponse{
 			Err:                 nil,
@@ -1597,8 +1909,10 @@ func TestBlsAgg(t *testing.T) {
 				Add(testOperator2.BlsKeypair.GetPubKeyG2()),
 			SignersAggSigG1: testOperator1.BlsKeypair.SignMessage(taskResponseDigest).
 				Add(testOperator2.BlsKeypair.SignMessage(taskResponseDigest)),
+			QuorumSigners: []types.OperatorId{testOperator1.OperatorId, testOperator2.OperatorId},
+			Signers:       []types.OperatorId{testOperator1.OperatorId, testOperator2.OperatorId},
 		}
-		gotAggregationServiceResponse := <-blsAggServ.aggregatedResponsesC
+		gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
 		require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
 		require.EqualValues(t, taskIndex, gotAggregationServiceResponse.TaskIndex)
 		elapsed := time.Since(start)
@@ -1607,6 +1921,482 @@ func TestBlsAgg(t *testing.T) {
 	})
 }
 
+func TestBlsAggTaskMetadata(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	testOperator1 := types.TestOperator{
+		OperatorId:     types.OperatorId{1},
+		StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+		BlsKeypair:     newBlsKeyPairPanics("0x1"),
+	}
+	testOperator2 := types.TestOperator{
+		OperatorId:     types.OperatorId{2},
+		StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+		BlsKeypair:     newBlsKeyPairPanics("0x2"),
+	}
+	blockNum := uint32(1)
+	quorumNumbers := types.QuorumNums{0}
+	taskResponse := 123
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.NoError(t, err)
+
+	fakeAvsRegistryService := avsregistry.NewFakeAvsRegistryService(
+		blockNum,
+		[]types.TestOperator{testOperator1, testOperator2},
+	)
+	blsAggServ := NewBlsAggregatorService(
+		fakeAvsRegistryService,
+		hashFunction,
+		testutils.GetTestLogger(),
+		WithDefaultTaskMetadata(TaskMetadata{
+			TimeToExpiry:               time.Second,
+			QuorumThresholdPercentages: types.QuorumThresholdPercentages{100},
+		}),
+	)
+
+	start := time.Now()
+	// a fast task with the default threshold, which operator 1 alone doesn't meet
+	fastTask := types.TaskIndex(0)
+	err = blsAggServ.InitializeNewTaskWithMetadata(fastTask, blockNum, quorumNumbers, TaskMetadata{
+		TimeToExpiry: 300 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	// a slow task with a lower threshold, which operator 1 alone meets
+	slowTask := types.TaskIndex(1)
+	err = blsAggServ.InitializeNewTaskWithMetadata(slowTask, blockNum, quorumNumbers, TaskMetadata{
+		TimeToExpiry:               time.Hour,
+		QuorumThresholdPercentages: types.QuorumThresholdPercentages{50},
+	})
+	require.NoError(t, err)
+	// a task with the default metadata, which the later change of the default metadata doesn't affect
+	defaultTask := types.TaskIndex(2)
+	err = blsAggServ.InitializeNewTaskWithMetadata(defaultTask, blockNum, quorumNumbers, TaskMetadata{})
+	require.NoError(t, err)
+	blsAggServ.SetDefaultTaskMetadata(TaskMetadata{
+		TimeToExpiry:               time.Hour,
+		QuorumThresholdPercentages: types.QuorumThresholdPercentages{50},
+	})
+
+	blsSig := testOperator1.BlsKeypair.SignMessage(taskResponseDigest)
+	for _, taskIndex := range []types.TaskIndex{fastTask, slowTask, defaultTask} {
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse,
+			blsSig,
+			testOperator1.OperatorId,
+		)
+		require.NoError(t, err)
+	}
+
+	gotResponse := <-blsAggServ.GetResponseChannel()
+	require.NoError(t, gotResponse.Err)
+	require.Equal(t, slowTask, gotResponse.TaskIndex)
+	require.Equal(t, taskResponseDigest, gotResponse.TaskResponseDigest)
+	require.Equal(t, []*bls.G1Point{testOperator2.BlsKeypair.GetPubKeyG1()}, gotResponse.NonSignersPubkeysG1)
+
+	gotResponse = <-blsAggServ.GetResponseChannel()
+	require.Equal(t, TaskExpiredErrorFn(fastTask), gotResponse.Err)
+	require.Equal(t, fastTask, gotResponse.TaskIndex)
+	require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	gotResponse = <-blsAggServ.GetResponseChannel()
+	require.Equal(t, TaskExpiredErrorFn(defaultTask), gotResponse.Err)
+	require.Equal(t, defaultTask, gotResponse.TaskIndex)
+	elapsed := time.Since(start)
+	require.GreaterOrEqual(t, elapsed, time.Second)
+	require.Less(t, elapsed, 10*time.Second)
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		blsAggServ := NewBlsAggregatorService(fakeAvsRegistryService, hashFunction, testutils.GetTestLogger())
+		// there is no default time to expiry
+		err := blsAggServ.InitializeNewTaskWithMetadata(0, blockNum, quorumNumbers, TaskMetadata{
+			QuorumThresholdPercentages: types.QuorumThresholdPercentages{100},
+		})
+		require.Error(t, err)
+		err = blsAggServ.InitializeNewTaskWithMetadata(0, blockNum, quorumNumbers, TaskMetadata{
+			TimeToExpiry:               time.Second,
+			QuorumThresholdPercentages: types.QuorumThresholdPercentages{100, 100},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestBlsAggRecoverTasks(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	testOperators := []types.TestOperator{}
+	for i := 1; i <= 3; i++ {
+		testOperators = append(testOperators, types.TestOperator{
+			OperatorId:     types.OperatorId{byte(i)},
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+			BlsKeypair:     newBlsKeyPairPanics(fmt.Sprintf("0x%d", i)),
+		})
+	}
+	blockNum := uint32(1)
+	taskIndex := types.TaskIndex(0)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+
+	for name, openStore := range newTestSignatureStores(t) {
+		t.Run(name, func(t *testing.T) {
+			fakeAvsRegistryService := avsregistry.NewFakeAvsRegistryService(blockNum, testOperators)
+			blsAggServ := NewBlsAggregatorService(
+				fakeAvsRegistryService,
+				hashFunction,
+				testutils.GetTestLogger(),
+				WithSignatureStore(openStore()),
+			)
+			err := blsAggServ.InitializeNewTask(
+				taskIndex,
+				blockNum,
+				types.QuorumNums{0},
+				types.QuorumThresholdPercentages{100},
+				10*time.Second,
+			)
+			require.Nil(t, err)
+			// the task needs the signatures of the 3 operators, the service stops after 2 of them
+			for _, testOperator := range testOperators[:2] {
+				err = blsAggServ.ProcessNewSignature(
+					context.Background(),
+					taskIndex,
+					taskResponse,
+					testOperator.BlsKeypair.SignMessage(taskResponseDigest),
+					testOperator.OperatorId,
+				)
+				require.Nil(t, err)
+			}
+
+			// the recovered task completes with the signature of the last operator
+			blsAggServ = NewBlsAggregatorService(
+				fakeAvsRegistryService,
+				hashFunction,
+				testutils.GetTestLogger(),
+				WithSignatureStore(openStore()),
+			)
+			require.NoError(t, blsAggServ.RecoverTasks(context.Background()))
+			err = blsAggServ.ProcessNewSignature(
+				context.Background(),
+				taskIndex,
+				taskResponse,
+				testOperators[2].BlsKeypair.SignMessage(taskResponseDigest),
+				testOperators[2].OperatorId,
+			)
+			require.Nil(t, err)
+
+			wantAggregationServiceResponse := BlsAggregationServiceResponse{
+				Err:                 nil,
+				TaskIndex:           taskIndex,
+				TaskResponse:        taskResponse,
+				TaskResponseDigest:  taskResponseDigest,
+				NonSignersPubkeysG1: []*bls.G1Point{},
+				QuorumApksG1:        []*bls.G1Point{bls.NewZeroG1Point()},
+				SignersApkG2:        bls.NewZeroG2Point(),
+				SignersAggSigG1:     bls.NewZeroSignature(),
+			}
+			for _, testOperator := range testOperators {
+				wantAggregationServiceResponse.QuorumApksG1[0].Add(testOperator.BlsKeypair.GetPubKeyG1())
+				wantAggregationServiceResponse.SignersApkG2.Add(testOperator.BlsKeypair.GetPubKeyG2())
+				wantAggregationServiceResponse.SignersAggSigG1.Add(
+					testOperator.BlsKeypair.SignMessage(taskResponseDigest),
+				)
+			}
+			gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
+			require.Equal(t, wantAggregationServiceResponse, gotAggregationServiceResponse)
+
+			// the completed task isn't recovered again
+			require.Eventually(t, func() bool {
+				taskIndices, err := blsAggServ.signatureStore.ListTasks()
+				return err == nil && len(taskIndices) == 0
+			}, time.Second, 10*time.Millisecond)
+		})
+	}
+
+	t.Run("expired task discarded", func(t *testing.T) {
+		store := NewInMemorySignatureStore()
+		require.NoError(t, store.SaveTask(StoredTask{
+			TaskIndex:                  taskIndex,
+			TaskCreatedBlock:           blockNum,
+			QuorumNumbers:              types.QuorumNums{0},
+			QuorumThresholdPercentages: types.QuorumThresholdPercentages{100},
+			ExpiresAt:                  time.Now().Add(-time.Second),
+		}))
+		blsAggServ := NewBlsAggregatorService(
+			avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+			hashFunction,
+			testutils.GetTestLogger(),
+			WithSignatureStore(store),
+		)
+		require.NoError(t, blsAggServ.RecoverTasks(context.Background()))
+		taskIndices, err := store.ListTasks()
+		require.NoError(t, err)
+		require.Empty(t, taskIndices)
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse,
+			testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+			testOperators[0].OperatorId,
+		)
+		require.Equal(t, TaskNotFoundErrorFn(taskIndex), err)
+	})
+}
+
+func TestBlsAggConcurrentVerification(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskIndex := types.TaskIndex(0)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+	// the odd operators sign with the key of the next one
+	const numOperators = 40
+	testOperators := newTestOperators(t, numOperators)
+	signatures := make([]*bls.Signature, numOperators)
+	for i, testOperator := range testOperators {
+		if i%2 == 1 {
+			testOperator = testOperators[(i+1)%numOperators]
+		}
+		signatures[i] = testOperator.BlsKeypair.SignMessage(taskResponseDigest)
+	}
+
+	reg := prometheus.NewRegistry()
+	metrics := NewMetrics(reg, "test")
+	blsAggServ := NewBlsAggregatorService(
+		avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+		hashFunction,
+		testutils.GetTestLogger(),
+		WithVerificationWorkers(4),
+		WithMetrics(metrics),
+	)
+	err = blsAggServ.InitializeNewTask(
+		taskIndex,
+		blockNum,
+		types.QuorumNums{0},
+		types.QuorumThresholdPercentages{50},
+		10*time.Second,
+	)
+	require.Nil(t, err)
+
+	errs := make([]error, numOperators)
+	var wg sync.WaitGroup
+	for i, testOperator := range testOperators {
+		wg.Add(1)
+		go func(i int, testOperator types.TestOperator) {
+			defer wg.Done()
+			errs[i] = blsAggServ.ProcessNewSignature(
+				context.Background(),
+				taskIndex,
+				taskResponse,
+				signatures[i],
+				testOperator.OperatorId,
+			)
+		}(i, testOperator)
+	}
+	wg.Wait()
+	// the error of each signature is returned to its sender
+	for i, err := range errs {
+		if i%2 == 1 {
+			require.Equal(t, IncorrectSignatureError, err, "operator %d", i)
+		} else {
+			require.Nil(t, err, "operator %d", i)
+		}
+	}
+
+	gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
+	require.Nil(t, gotAggregationServiceResponse.Err)
+	require.Len(t, gotAggregationServiceResponse.NonSignersPubkeysG1, numOperators/2)
+	wantSignersApkG2 := bls.NewZeroG2Point()
+	for i := 0; i < numOperators; i += 2 {
+		wantSignersApkG2.Add(testOperators[i].BlsKeypair.GetPubKeyG2())
+	}
+	require.Equal(t, wantSignersApkG2, gotAggregationServiceResponse.SignersApkG2)
+	require.Equal(t, float64(0), testutil.ToFloat64(metrics.queuedVerifications))
+	require.Equal(t, float64(0), testutil.ToFloat64(metrics.inFlightVerifications))
+}
+
+func TestBlsAggResponseHandler(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskIndex := types.TaskIndex(0)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+	testOperators := newTestOperators(t, 1)
+
+	var tests = map[string]struct {
+		handlerHangs      bool
+		wantHandled       int
+		wantSentOnChannel int
+	}{
+		"handler returns in time - response delivered to the handler only": {
+			wantHandled: 1,
+		},
+		"handler hangs - response sent on the response channel instead": {
+			handlerHangs:      true,
+			wantSentOnChannel: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			handledResponsesC := make(chan BlsAggregationServiceResponse, 2)
+			releaseHandlerC := make(chan struct{})
+			defer close(releaseHandlerC)
+			blsAggServ := NewBlsAggregatorService(
+				avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+				hashFunction,
+				testutils.GetTestLogger(),
+				WithResponseHandler(func(response BlsAggregationServiceResponse) {
+					if tt.handlerHangs {
+						<-releaseHandlerC
+						return
+					}
+					handledResponsesC <- response
+				}),
+				WithResponseHandlerTimeout(100*time.Millisecond),
+			)
+			err := blsAggServ.InitializeNewTask(
+				taskIndex,
+				blockNum,
+				types.QuorumNums{0},
+				types.QuorumThresholdPercentages{100},
+				10*time.Second,
+			)
+			require.Nil(t, err)
+			err = blsAggServ.ProcessNewSignature(
+				context.Background(),
+				taskIndex,
+				taskResponse,
+				testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+				testOperators[0].OperatorId,
+			)
+			require.Nil(t, err)
+
+			var handledResponses, sentResponses []BlsAggregationServiceResponse
+			timeout := time.After(time.Second)
+		collect:
+			for {
+				select {
+				case response := <-handledResponsesC:
+					handledResponses = append(handledResponses, response)
+				case response := <-blsAggServ.GetResponseChannel():
+					sentResponses = append(sentResponses, response)
+				case <-timeout:
+					break collect
+				}
+			}
+			// each response is delivered exactly once
+			require.Len(t, handledResponses, tt.wantHandled)
+			require.Len(t, sentResponses, tt.wantSentOnChannel)
+			for _, response := range append(handledResponses, sentResponses...) {
+				require.Nil(t, response.Err)
+				require.Equal(t, taskIndex, response.TaskIndex)
+				require.Equal(t, taskResponseDigest, response.TaskResponseDigest)
+			}
+		})
+	}
+}
+
+// newTestOperators returns n operators with the same stake in quorum 0
+func newTestOperators(tb testing.TB, n int) []types.TestOperator {
+	testOperators := make([]types.TestOperator, n)
+	for i := range testOperators {
+		keyPair, err := bls.GenRandomBlsKeys()
+		require.NoError(tb, err)
+		testOperators[i] = types.TestOperator{
+			OperatorId:     types.OperatorIdFromKeyPair(keyPair),
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(100)},
+			BlsKeypair:     keyPair,
+		}
+	}
+	return testOperators
+}
+
+// BenchmarkBlsAggTimeToQuorum measures the time from the initialization of a task until its response, once all its
+// operators sent their signatures concurrently
+func BenchmarkBlsAggTimeToQuorum(b *testing.B) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.NoError(b, err)
+	const numOperators = 500
+	testOperators := newTestOperators(b, numOperators)
+	signatures := make([]*bls.Signature, numOperators)
+	for i, testOperator := range testOperators {
+		signatures[i] = testOperator.BlsKeypair.SignMessage(taskResponseDigest)
+	}
+	logger := logging.NewTextSLogger(io.Discard, &logging.SLoggerOptions{Level: slog.LevelError})
+
+	workerCounts := []int{1}
+	if runtime.NumCPU() > 1 {
+		workerCounts = append(workerCounts, runtime.NumCPU())
+	}
+	for _, workers := range workerCounts {
+		b.Run(fmt.Sprintf("%d workers", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				blsAggServ := NewBlsAggregatorService(
+					avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+					hashFunction,
+					logger,
+					WithVerificationWorkers(workers),
+				)
+				err := blsAggServ.InitializeNewTask(
+					types.TaskIndex(i),
+					blockNum,
+					types.QuorumNums{0},
+					types.QuorumThresholdPercentages{100},
+					time.Minute,
+				)
+				require.NoError(b, err)
+				for j, testOperator := range testOperators {
+					go func(j int, operatorId types.OperatorId) {
+						// the signatures sent once the task completed are refused
+						_ = blsAggServ.ProcessNewSignature(
+							context.Background(),
+							types.TaskIndex(i),
+							taskResponse,
+							signatures[j],
+							operatorId,
+						)
+					}(j, testOperator.OperatorId)
+				}
+				response := <-blsAggServ.GetResponseChannel()
+				require.NoError(b, response.Err)
+			}
+		})
+	}
+}
+
 func TestIntegrationBlsAgg(t *testing.T) {
 
 	tasksTimeToExpiry := 10 * time.Second
@@ -1725,7 +2515,7 @@ func TestIntegrationBlsAgg(t *testing.T) {
 		require.Nil(t, err)
 
 		// wait for the response from the aggregation service and check the signature
-		blsAggServiceResp := <-blsAggServ.aggregatedResponsesC
+		blsAggServiceResp := <-blsAggServ.GetResponseChannel()
 		_, _, err = avsServiceManager.CheckSignatures(
 			&bind.CallOpts{},
 			taskResponseDigest,
@@ -1772,3 +2562,351 @@ func (blsAggServiceResp *BlsAggregationServiceResponse) toNonSignerStakesAndSign
 	}
 	return nonSignerStakesAndSignature
 }
+
+func TestBlsAggHealth(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+	testOperators := newTestOperators(t, 1)
+
+	blsAggServ := NewBlsAggregatorService(
+		avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+		hashFunction,
+		testutils.GetTestLogger(),
+		WithMaxPendingResponses(2),
+	)
+	health, _ := blsAggServ.Health(context.Background())
+	require.Equal(t, services.ServiceHealthy, health)
+
+	// the responses of two tasks are not read from the response channel
+	for taskIndex := types.TaskIndex(0); taskIndex < 2; taskIndex++ {
+		err := blsAggServ.InitializeNewTask(
+			taskIndex,
+			blockNum,
+			types.QuorumNums{0},
+			types.QuorumThresholdPercentages{100},
+			10*time.Second,
+		)
+		require.Nil(t, err)
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse,
+			testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+			testOperators[0].OperatorId,
+		)
+		require.Nil(t, err)
+	}
+	require.Eventually(t, func() bool {
+		health, _ := blsAggServ.Health(context.Background())
+		return health == services.ServiceDegraded
+	}, time.Second, 10*time.Millisecond)
+
+	<-blsAggServ.GetResponseChannel()
+	require.Eventually(t, func() bool {
+		health, _ := blsAggServ.Health(context.Background())
+		return health == services.ServiceHealthy
+	}, time.Second, 10*time.Millisecond)
+	<-blsAggServ.GetResponseChannel()
+}
+
+// TestBlsAggWithMockAvsRegistryService checks the calls of the aggregation service to the AVS registry service, which
+// is mocked with services/mocks
+func TestBlsAggWithMockAvsRegistryService(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskIndex := types.TaskIndex(0)
+	quorumNumbers := types.QuorumNums{0}
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+	// operator 1 signs, which is enough to reach the threshold of 50%, while operator 2 doesn't
+	testOperators := newTestOperators(t, 2)
+	operatorsAvsState := map[types.OperatorId]types.OperatorAvsState{}
+	for _, operator := range testOperators {
+		operatorsAvsState[operator.OperatorId] = types.OperatorAvsState{
+			OperatorId: operator.OperatorId,
+			OperatorInfo: types.OperatorInfo{
+				Pubkeys: types.OperatorPubkeys{
+					G1Pubkey: operator.BlsKeypair.GetPubKeyG1(),
+					G2Pubkey: operator.BlsKeypair.GetPubKeyG2(),
+				},
+			},
+			StakePerQuorum: operator.StakePerQuorum,
+			BlockNumber:    blockNum,
+		}
+	}
+	indices := opstateretriever.OperatorStateRetrieverCheckSignaturesIndices{
+		NonSignerQuorumBitmapIndices: []uint32{1},
+		QuorumApkIndices:             []uint32{2},
+		TotalStakeIndices:            []uint32{3},
+		NonSignerStakeIndices:        [][]uint32{{4}},
+	}
+
+	avsRegistryService := mocks.NewMockAvsRegistryService(gomock.NewController(t))
+	avsRegistryService.EXPECT().
+		GetOperatorsAvsStateAtBlock(gomock.Any(), quorumNumbers, blockNum).
+		Return(operatorsAvsState, nil)
+	nonSignerOperatorIds := []types.OperatorId{testOperators[1].OperatorId}
+	avsRegistryService.EXPECT().
+		GetCheckSignaturesIndices(gomock.Any(), blockNum, quorumNumbers, nonSignerOperatorIds).
+		Return(indices, nil)
+	blsAggServ := NewBlsAggregatorService(avsRegistryService, hashFunction, testutils.GetTestLogger())
+
+	err = blsAggServ.InitializeNewTask(
+		taskIndex,
+		blockNum,
+		quorumNumbers,
+		types.QuorumThresholdPercentages{50},
+		10*time.Second,
+	)
+	require.Nil(t, err)
+	err = blsAggServ.ProcessNewSignature(
+		context.Background(),
+		taskIndex,
+		taskResponse,
+		testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+		testOperators[0].OperatorId,
+	)
+	require.Nil(t, err)
+
+	gotAggregationServiceResponse := <-blsAggServ.GetResponseChannel()
+	require.Nil(t, gotAggregationServiceResponse.Err)
+	require.Equal(
+		t,
+		[]*bls.G1Point{testOperators[1].BlsKeypair.GetPubKeyG1()},
+		gotAggregationServiceResponse.NonSignersPubkeysG1,
+	)
+	require.Equal(t, indices.NonSignerQuorumBitmapIndices, gotAggregationServiceResponse.NonSignerQuorumBitmapIndices)
+	require.Equal(t, indices.QuorumApkIndices, gotAggregationServiceResponse.QuorumApkIndices)
+	require.Equal(t, indices.TotalStakeIndices, gotAggregationServiceResponse.TotalStakeIndices)
+	require.Equal(t, indices.NonSignerStakeIndices, gotAggregationServiceResponse.NonSignerStakeIndices)
+}
+
+func TestBlsAggClose(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+
+	// closeService closes the service with the given timeout while reading its responses, returning them once the
+	// response channel is closed along with the error of Close
+	closeService := func(
+		blsAggServ *BlsAggregatorService,
+		timeout time.Duration,
+	) ([]BlsAggregationServiceResponse, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		closeErrC := make(chan error, 1)
+		go func() {
+			closeErrC <- blsAggServ.Close(ctx)
+		}()
+		responses := []BlsAggregationServiceResponse{}
+		for response := range blsAggServ.GetResponseChannel() {
+			responses = append(responses, response)
+		}
+		return responses, <-closeErrC
+	}
+
+	var tests = []struct {
+		name string
+		// the operators only sign if signed is set
+		signed         bool
+		windowDuration time.Duration
+		closeTimeout   time.Duration
+		wantCloseErr   error
+		wantRespErr    error
+	}{
+		{
+			name:         "in-flight task without signatures is cancelled at the deadline",
+			closeTimeout: 100 * time.Millisecond,
+			wantCloseErr: context.DeadlineExceeded,
+			wantRespErr:  ErrTaskCancelled,
+		},
+		{
+			name:           "in-flight task in its window completes before the deadline",
+			signed:         true,
+			windowDuration: 100 * time.Millisecond,
+			closeTimeout:   10 * time.Second,
+		},
+		{
+			name:           "in-flight task in its window completes at the deadline",
+			signed:         true,
+			windowDuration: time.Minute,
+			closeTimeout:   100 * time.Millisecond,
+			wantCloseErr:   context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+			testOperators := newTestOperators(t, 1)
+			blsAggServ := NewBlsAggregatorService(
+				avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+				hashFunction,
+				testutils.GetTestLogger(),
+			)
+			err := blsAggServ.InitializeNewTaskWithWindow(
+				0,
+				blockNum,
+				types.QuorumNums{0},
+				types.QuorumThresholdPercentages{100},
+				time.Minute,
+				tt.windowDuration,
+			)
+			require.Nil(t, err)
+			if tt.signed {
+				err = blsAggServ.ProcessNewSignature(
+					context.Background(),
+					0,
+					taskResponse,
+					testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+					testOperators[0].OperatorId,
+				)
+				require.Nil(t, err)
+			}
+
+			responses, err := closeService(blsAggServ, tt.closeTimeout)
+			require.ErrorIs(t, err, tt.wantCloseErr)
+			require.Len(t, responses, 1)
+			if tt.wantRespErr != nil {
+				require.ErrorIs(t, responses[0].Err, tt.wantRespErr)
+			} else {
+				require.Nil(t, responses[0].Err)
+				require.Equal(t, taskResponseDigest, responses[0].TaskResponseDigest)
+			}
+
+			// the service refuses the new tasks once closed, and can be closed again
+			err = blsAggServ.InitializeNewTask(
+				1,
+				blockNum,
+				types.QuorumNums{0},
+				types.QuorumThresholdPercentages{100},
+				time.Minute,
+			)
+			require.ErrorIs(t, err, ErrServiceClosed)
+			require.ErrorIs(t, blsAggServ.Close(context.Background()), tt.wantCloseErr)
+		})
+	}
+
+	t.Run("service without tasks", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+		blsAggServ := NewBlsAggregatorService(
+			avsregistry.NewFakeAvsRegistryService(blockNum, newTestOperators(t, 1)),
+			hashFunction,
+			testutils.GetTestLogger(),
+		)
+		responses, err := closeService(blsAggServ, time.Second)
+		require.Nil(t, err)
+		require.Empty(t, responses)
+	})
+}
+
+func TestBlsAggGetTaskAggregationState(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskIndex := types.TaskIndex(42)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+	testOperators := newTestOperators(t, 3)
+	blsAggServ := NewBlsAggregatorService(
+		avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+		hashFunction,
+		testutils.GetTestLogger(),
+	)
+
+	_, err = blsAggServ.GetTaskAggregationState(taskIndex)
+	require.ErrorIs(t, err, ErrTaskNotFound)
+	// the window keeps the task in-flight once all the operators signed
+	err = blsAggServ.InitializeNewTaskWithWindow(
+		taskIndex,
+		blockNum,
+		types.QuorumNums{0},
+		types.QuorumThresholdPercentages{100},
+		time.Minute,
+		time.Minute,
+	)
+	require.Nil(t, err)
+
+	wantSigners := []types.OperatorId{}
+	for i, testOperator := range testOperators {
+		err = blsAggServ.ProcessNewSignature(
+			context.Background(),
+			taskIndex,
+			taskResponse,
+			testOperator.BlsKeypair.SignMessage(taskResponseDigest),
+			testOperator.OperatorId,
+		)
+		require.Nil(t, err)
+		wantSigners = append(wantSigners, testOperator.OperatorId)
+		sort.Slice(wantSigners, func(i, j int) bool {
+			return bytes.Compare(wantSigners[i][:], wantSigners[j][:]) < 0
+		})
+
+		state, err := blsAggServ.GetTaskAggregationState(taskIndex)
+		require.Nil(t, err)
+		require.Equal(t, taskIndex, state.TaskIndex)
+		require.Equal(t, blockNum, state.TaskCreatedBlock)
+		require.Equal(t, taskResponseDigest, state.TaskResponseDigest)
+		require.Equal(t, wantSigners, state.Signers)
+		require.Equal(t, i == len(testOperators)-1, state.ThresholdsMet)
+		require.Empty(t, state.OtherDigestsSignedStake)
+		require.Greater(t, state.TimeToExpiry, time.Duration(0))
+		require.LessOrEqual(t, state.TimeToExpiry, time.Minute)
+		quorumStake := state.QuorumStakes[0]
+		require.Equal(t, big.NewInt(int64(100*(i+1))), quorumStake.SignedStake)
+		require.Equal(t, big.NewInt(300), quorumStake.TotalStake)
+		require.InDelta(t, 100*float64(i+1)/3, quorumStake.SignedStakePercentage, 1e-9)
+		require.Equal(t, types.QuorumThresholdPercentage(100), quorumStake.ThresholdPercentage)
+	}
+
+	var buf bytes.Buffer
+	found, err := blsAggServ.WriteTaskAggregationStateJSON(taskIndex, &buf)
+	require.Nil(t, err)
+	require.True(t, found)
+	var gotState TaskAggregationState
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &gotState))
+	require.Equal(t, wantSigners, gotState.Signers)
+
+	// the task completes once the service closes, after which it isn't found
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	go func() {
+		_ = blsAggServ.Close(ctx)
+	}()
+	gotResponse := <-blsAggServ.GetResponseChannel()
+	require.Nil(t, gotResponse.Err)
+	_, err = blsAggServ.GetTaskAggregationState(taskIndex)
+	require.ErrorIs(t, err, ErrTaskNotFound)
+	found, err = blsAggServ.WriteTaskAggregationStateJSON(taskIndex, &buf)
+	require.Nil(t, err)
+	require.False(t, found)
+}
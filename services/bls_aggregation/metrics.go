@@ -0,0 +1,186 @@
+package blsagg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RejectedSignatureReason is the reason a signature was refused by ProcessNewSignature, as counted by the metrics
+type RejectedSignatureReason string
+
+const (
+	// RejectedSignatureReasonInvalid is the reason of the signatures which failed verification
+	RejectedSignatureReasonInvalid RejectedSignatureReason = "invalid"
+	// RejectedSignatureReasonUnknownOperator is the reason of the signatures of operators which aren't part of the
+	// quorums of the task at its reference block
+	RejectedSignatureReasonUnknownOperator RejectedSignatureReason = "unknown_operator"
+	// RejectedSignatureReasonConflicting is the reason of the signatures refused with a ConflictingSignatureError
+	RejectedSignatureReasonConflicting RejectedSignatureReason = "conflicting"
+	// RejectedSignatureReasonLate is the reason of the signatures of tasks which aren't in-flight, e.g. which already
+	// completed or expired
+	RejectedSignatureReasonLate RejectedSignatureReason = "late"
+)
+
+type Metrics interface {
+	IncrementQueuedVerifications()
+	DecrementQueuedVerifications()
+	IncrementInFlightVerifications()
+	DecrementInFlightVerifications()
+	IncrementInFlightTasks()
+	DecrementInFlightTasks()
+	ObserveTimeToQuorum(duration time.Duration)
+	IncrementExpiredTasks()
+	IncrementRejectedSignatures(reason RejectedSignatureReason)
+	SetSignedStakePercentage(quorumNumber types.QuorumNum, percentage float64)
+}
+
+const namespace = "blsagg"
+
+type PromMetrics struct {
+	queuedVerifications   prometheus.Gauge
+	inFlightVerifications prometheus.Gauge
+	inFlightTasks         prometheus.Gauge
+	timeToQuorum          prometheus.Histogram
+	expiredTasks          prometheus.Counter
+	rejectedSignatures    *prometheus.CounterVec
+	signedStakePercentage *prometheus.GaugeVec
+}
+
+var _ Metrics = (*PromMetrics)(nil)
+
+// NewMetrics returns the metrics of a BlsAggregatorService registered on reg, which aren't exposed if reg is nil
+func NewMetrics(reg prometheus.Registerer, subsystem string) *PromMetrics {
+	return &PromMetrics{
+		queuedVerifications: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queued_verifications",
+				Help:      "number of signatures waiting for a verification worker",
+			},
+		),
+		inFlightVerifications: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "in_flight_verifications",
+				Help:      "number of signatures being verified by the verification workers",
+			},
+		),
+		inFlightTasks: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "in_flight_tasks",
+				Help:      "number of tasks being aggregated",
+			},
+		),
+		timeToQuorum: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "time_to_quorum_seconds",
+				Help:      "time from the initialization of a task until its stake thresholds are met",
+				Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+			},
+		),
+		expiredTasks: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "expired_tasks_total",
+				Help:      "number of tasks which expired before meeting their stake thresholds",
+			},
+		),
+		rejectedSignatures: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "rejected_signatures_total",
+				Help:      "number of signatures refused, by reason",
+			},
+			[]string{"reason"},
+		),
+		signedStakePercentage: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "signed_stake_percentage",
+				Help:      "percentage of the stake of each quorum which signed the response of the most recent task",
+			},
+			[]string{"quorum"},
+		),
+	}
+}
+
+func (m *PromMetrics) IncrementQueuedVerifications() {
+	m.queuedVerifications.Inc()
+}
+
+func (m *PromMetrics) DecrementQueuedVerifications() {
+	m.queuedVerifications.Dec()
+}
+
+func (m *PromMetrics) IncrementInFlightVerifications() {
+	m.inFlightVerifications.Inc()
+}
+
+func (m *PromMetrics) DecrementInFlightVerifications() {
+	m.inFlightVerifications.Dec()
+}
+
+func (m *PromMetrics) IncrementInFlightTasks() {
+	m.inFlightTasks.Inc()
+}
+
+func (m *PromMetrics) DecrementInFlightTasks() {
+	m.inFlightTasks.Dec()
+}
+
+func (m *PromMetrics) ObserveTimeToQuorum(duration time.Duration) {
+	m.timeToQuorum.Observe(duration.Seconds())
+}
+
+func (m *PromMetrics) IncrementExpiredTasks() {
+	m.expiredTasks.Inc()
+}
+
+func (m *PromMetrics) IncrementRejectedSignatures(reason RejectedSignatureReason) {
+	m.rejectedSignatures.WithLabelValues(string(reason)).Inc()
+}
+
+func (m *PromMetrics) SetSignedStakePercentage(quorumNumber types.QuorumNum, percentage float64) {
+	m.signedStakePercentage.WithLabelValues(fmt.Sprint(quorumNumber)).Set(percentage)
+}
+
+type NoopMetrics struct{}
+
+var _ Metrics = (*NoopMetrics)(nil)
+
+func NewNoopMetrics() *NoopMetrics {
+	return &NoopMetrics{}
+}
+
+func (m *NoopMetrics) IncrementQueuedVerifications() {}
+
+func (m *NoopMetrics) DecrementQueuedVerifications() {}
+
+func (m *NoopMetrics) IncrementInFlightVerifications() {}
+
+func (m *NoopMetrics) DecrementInFlightVerifications() {}
+
+func (m *NoopMetrics) IncrementInFlightTasks() {}
+
+func (m *NoopMetrics) DecrementInFlightTasks() {}
+
+func (m *NoopMetrics) ObserveTimeToQuorum(duration time.Duration) {}
+
+func (m *NoopMetrics) IncrementExpiredTasks() {}
+
+func (m *NoopMetrics) IncrementRejectedSignatures(reason RejectedSignatureReason) {}
+
+func (m *NoopMetrics) SetSignedStakePercentage(quorumNumber types.QuorumNum, percentage float64) {}
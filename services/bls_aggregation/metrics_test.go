@@ -0,0 +1,191 @@
+package blsagg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/services/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+// gatheredMetricValue scrapes reg and returns the value of the metric name with labels, the sample count for
+// histograms
+func gatheredMetricValue(t *testing.T, reg prometheus.Gatherer, name string, labels map[string]string) float64 {
+	metricFamilies, err := reg.Gather()
+	require.NoError(t, err)
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					continue metrics
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	require.Failf(t, "metric not gathered", "%s %v", name, labels)
+	return 0
+}
+
+func TestBlsAggMetrics(t *testing.T) {
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+	blockNum := uint32(1)
+	taskResponse := storedTaskResponse{123}
+	taskResponseDigest, err := hashFunction(taskResponse)
+	require.Nil(t, err)
+	testOperators := newTestOperators(t, 2)
+	unknownOperator := newTestOperators(t, 1)[0]
+
+	reg := prometheus.NewRegistry()
+	blsAggServ := NewBlsAggregatorService(
+		avsregistry.NewFakeAvsRegistryService(blockNum, testOperators),
+		hashFunction,
+		testutils.GetTestLogger(),
+		WithPrometheusRegisterer(reg),
+	)
+
+	// task 1 reaches quorum once operator 0 signs
+	quorumTaskIndex := types.TaskIndex(1)
+	err = blsAggServ.InitializeNewTask(
+		quorumTaskIndex,
+		blockNum,
+		types.QuorumNums{0},
+		types.QuorumThresholdPercentages{50},
+		10*time.Second,
+	)
+	require.Nil(t, err)
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_in_flight_tasks", nil))
+	err = blsAggServ.ProcessNewSignature(
+		context.Background(),
+		quorumTaskIndex,
+		taskResponse,
+		testOperators[1].BlsKeypair.SignMessage(types.TaskResponseDigest{}),
+		testOperators[1].OperatorId,
+	)
+	require.Equal(t, IncorrectSignatureError, err)
+	err = blsAggServ.ProcessNewSignature(
+		context.Background(),
+		quorumTaskIndex,
+		taskResponse,
+		unknownOperator.BlsKeypair.SignMessage(taskResponseDigest),
+		unknownOperator.OperatorId,
+	)
+	require.ErrorAs(t, err, new(*OperatorNotPartOfTaskQuorumError))
+	err = blsAggServ.ProcessNewSignature(
+		context.Background(),
+		quorumTaskIndex,
+		taskResponse,
+		testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+		testOperators[0].OperatorId,
+	)
+	require.Nil(t, err)
+	response := <-blsAggServ.GetResponseChannel()
+	require.Nil(t, response.Err)
+
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_time_to_quorum_seconds", nil))
+	require.Equal(t, float64(50), gatheredMetricValue(t, reg, "blsagg_signed_stake_percentage", map[string]string{
+		"quorum": "0",
+	}))
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_rejected_signatures_total", map[string]string{
+		"reason": string(RejectedSignatureReasonInvalid),
+	}))
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_rejected_signatures_total", map[string]string{
+		"reason": string(RejectedSignatureReasonUnknownOperator),
+	}))
+	require.Eventually(t, func() bool {
+		return gatheredMetricValue(t, reg, "blsagg_in_flight_tasks", nil) == 0
+	}, time.Second, 10*time.Millisecond)
+	// the signature of operator 1 arrives once the task completed
+	err = blsAggServ.ProcessNewSignature(
+		context.Background(),
+		quorumTaskIndex,
+		taskResponse,
+		testOperators[1].BlsKeypair.SignMessage(taskResponseDigest),
+		testOperators[1].OperatorId,
+	)
+	require.Equal(t, TaskNotFoundErrorFn(quorumTaskIndex), err)
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_rejected_signatures_total", map[string]string{
+		"reason": string(RejectedSignatureReasonLate),
+	}))
+
+	// task 2 expires before reaching quorum
+	expiredTaskIndex := types.TaskIndex(2)
+	err = blsAggServ.InitializeNewTask(
+		expiredTaskIndex,
+		blockNum,
+		types.QuorumNums{0},
+		types.QuorumThresholdPercentages{100},
+		100*time.Millisecond,
+	)
+	require.Nil(t, err)
+	err = blsAggServ.ProcessNewSignature(
+		context.Background(),
+		expiredTaskIndex,
+		taskResponse,
+		testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+		testOperators[0].OperatorId,
+	)
+	require.Nil(t, err)
+	response = <-blsAggServ.GetResponseChannel()
+	require.Equal(t, TaskExpiredErrorFn(expiredTaskIndex), response.Err)
+
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_expired_tasks_total", nil))
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_time_to_quorum_seconds", nil))
+	// the signed stake percentage is still the one of the task which reached quorum
+	require.Equal(t, float64(50), gatheredMetricValue(t, reg, "blsagg_signed_stake_percentage", map[string]string{
+		"quorum": "0",
+	}))
+	require.Eventually(t, func() bool {
+		return gatheredMetricValue(t, reg, "blsagg_in_flight_tasks", nil) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// task 3 reaches quorum, then expires before the end of its window
+	windowTaskIndex := types.TaskIndex(3)
+	err = blsAggServ.InitializeNewTaskWithWindow(
+		windowTaskIndex,
+		blockNum,
+		types.QuorumNums{0},
+		types.QuorumThresholdPercentages{50},
+		100*time.Millisecond,
+		10*time.Second,
+	)
+	require.Nil(t, err)
+	err = blsAggServ.ProcessNewSignature(
+		context.Background(),
+		windowTaskIndex,
+		taskResponse,
+		testOperators[0].BlsKeypair.SignMessage(taskResponseDigest),
+		testOperators[0].OperatorId,
+	)
+	require.Nil(t, err)
+	response = <-blsAggServ.GetResponseChannel()
+	require.Nil(t, response.Err)
+	response = <-blsAggServ.GetResponseChannel()
+	require.Equal(t, TaskExpiredErrorFn(windowTaskIndex), response.Err)
+
+	require.Equal(t, float64(1), gatheredMetricValue(t, reg, "blsagg_expired_tasks_total", nil))
+	require.Equal(t, float64(2), gatheredMetricValue(t, reg, "blsagg_time_to_quorum_seconds", nil))
+}
@@ -0,0 +1,135 @@
+package blsagg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+)
+
+// ErrTaskNotStored is returned by a SignatureStore for a task which isn't stored, e.g. which was deleted
+var ErrTaskNotStored = errors.New("task not stored")
+
+// SignatureStore persists the in-flight tasks of a BlsAggregatorService and the signatures it accepted, so that they
+// can be recovered with RecoverTasks once the service restarts, see WithSignatureStore. Its methods are called
+// synchronously by the service, which only accepts a signature once it is saved.
+type SignatureStore interface {
+	// SaveTask stores task, without its signatures, replacing the task with the same index if any
+	SaveTask(task StoredTask) error
+	// Save appends signature to the signatures of the stored task taskIndex, returning ErrTaskNotStored if there's
+	// none
+	Save(taskIndex types.TaskIndex, signature StoredSignature) error
+	// LoadTask returns the stored task taskIndex with its signatures in the order they were saved, or
+	// ErrTaskNotStored
+	LoadTask(taskIndex types.TaskIndex) (StoredTask, error)
+	// ListTasks returns the indices of the stored tasks, sorted in increasing order
+	ListTasks() ([]types.TaskIndex, error)
+	// DeleteTask deletes the stored task taskIndex and its signatures, if any
+	DeleteTask(taskIndex types.TaskIndex) error
+}
+
+// StoredTask is a task as persisted by a SignatureStore, with the arguments it was initialized with
+type StoredTask struct {
+	TaskIndex                  types.TaskIndex
+	TaskCreatedBlock           uint32
+	QuorumNumbers              types.QuorumNums
+	QuorumThresholdPercentages types.QuorumThresholdPercentages
+	// ExpiresAt is the time the task expires, which the recovered task keeps
+	ExpiresAt      time.Time
+	WindowDuration time.Duration
+	// Signatures are the signatures accepted for the task, in the order they were accepted. They are verified again
+	// against the operators state at TaskCreatedBlock when the task is recovered.
+	Signatures []StoredSignature
+}
+
+// StoredSignature is a signature accepted by the service, as persisted by a SignatureStore
+type StoredSignature struct {
+	OperatorId   types.OperatorId
+	TaskResponse types.TaskResponse
+	BlsSignature *bls.Signature
+}
+
+// InMemorySignatureStore is a SignatureStore keeping the tasks in memory, which survive the restarts of the services
+// sharing it but not the ones of the process
+type InMemorySignatureStore struct {
+	tasks map[types.TaskIndex]StoredTask
+	lock  sync.Mutex
+}
+
+var _ SignatureStore = (*InMemorySignatureStore)(nil)
+
+// NewInMemorySignatureStore returns an empty InMemorySignatureStore
+func NewInMemorySignatureStore() *InMemorySignatureStore {
+	return &InMemorySignatureStore{tasks: make(map[types.TaskIndex]StoredTask)}
+}
+
+func (s *InMemorySignatureStore) SaveTask(task StoredTask) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	task.Signatures = nil
+	s.tasks[task.TaskIndex] = copyStoredTask(task)
+	return nil
+}
+
+func (s *InMemorySignatureStore) Save(taskIndex types.TaskIndex, signature StoredSignature) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	task, ok := s.tasks[taskIndex]
+	if !ok {
+		return fmt.Errorf("%w: task %d", ErrTaskNotStored, taskIndex)
+	}
+	// the service aggregates the signatures it accepted in place
+	task.Signatures = append(task.Signatures, copyStoredSignature(signature))
+	s.tasks[taskIndex] = task
+	return nil
+}
+
+func (s *InMemorySignatureStore) LoadTask(taskIndex types.TaskIndex) (StoredTask, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	task, ok := s.tasks[taskIndex]
+	if !ok {
+		return StoredTask{}, fmt.Errorf("%w: task %d", ErrTaskNotStored, taskIndex)
+	}
+	return copyStoredTask(task), nil
+}
+
+func (s *InMemorySignatureStore) ListTasks() ([]types.TaskIndex, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	taskIndices := make([]types.TaskIndex, 0, len(s.tasks))
+	for taskIndex := range s.tasks {
+		taskIndices = append(taskIndices, taskIndex)
+	}
+	sort.Slice(taskIndices, func(i, j int) bool { return taskIndices[i] < taskIndices[j] })
+	return taskIndices, nil
+}
+
+func (s *InMemorySignatureStore) DeleteTask(taskIndex types.TaskIndex) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.tasks, taskIndex)
+	return nil
+}
+
+func copyStoredTask(task StoredTask) StoredTask {
+	task.QuorumNumbers = append(types.QuorumNums{}, task.QuorumNumbers...)
+	task.QuorumThresholdPercentages = append(types.QuorumThresholdPercentages{}, task.QuorumThresholdPercentages...)
+	signatures := make([]StoredSignature, 0, len(task.Signatures))
+	for _, signature := range task.Signatures {
+		signatures = append(signatures, copyStoredSignature(signature))
+	}
+	task.Signatures = signatures
+	return task
+}
+
+func copyStoredSignature(signature StoredSignature) StoredSignature {
+	if signature.BlsSignature != nil && signature.BlsSignature.G1Point != nil {
+		signature.BlsSignature = &bls.Signature{G1Point: bls.NewZeroG1Point().Add(signature.BlsSignature.G1Point)}
+	}
+	return signature
+}
@@ -0,0 +1,241 @@
+package blsagg
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+var (
+	// the key of a task is its prefix followed by the big endian encoding of its index
+	levelDBTaskKeyPrefix = []byte("task/")
+	// the key of a signature is its prefix followed by the big endian encodings of the index of its task and of its
+	// position in the signatures of the task
+	levelDBSignatureKeyPrefix = []byte("sig/")
+)
+
+// TaskResponseDecoder decodes a task response from its JSON encoding, e.g. by unmarshalling it into the task response
+// type of the AVS. The digest of the decoded task response must be the one of the encoded task response, otherwise
+// its signatures are rejected when the task is recovered.
+type TaskResponseDecoder func(data []byte) (types.TaskResponse, error)
+
+// LevelDBSignatureStore is a SignatureStore persisting the tasks in a LevelDB database, which survive the restarts of
+// the process. The task responses of the signatures are encoded with encoding/json, and decoded with the
+// TaskResponseDecoder of the store. Each write is synced to disk before returning.
+type LevelDBSignatureStore struct {
+	db                  *leveldb.DB
+	decodeTaskResponse  TaskResponseDecoder
+	writeOptions        *opt.WriteOptions
+	saveSignaturesMutex sync.Mutex
+}
+
+var _ SignatureStore = (*LevelDBSignatureStore)(nil)
+
+type levelDBStoredTask struct {
+	TaskIndex                  types.TaskIndex                  `json:"taskIndex"`
+	TaskCreatedBlock           uint32                           `json:"taskCreatedBlock"`
+	QuorumNumbers              []uint8                          `json:"quorumNumbers"`
+	QuorumThresholdPercentages types.QuorumThresholdPercentages `json:"quorumThresholdPercentages"`
+	ExpiresAt                  time.Time                        `json:"expiresAt"`
+	WindowDuration             time.Duration                    `json:"windowDuration"`
+}
+
+type levelDBStoredSignature struct {
+	OperatorId   types.OperatorId `json:"operatorId"`
+	TaskResponse json.RawMessage  `json:"taskResponse"`
+	BlsSignature *bls.Signature   `json:"blsSignature"`
+}
+
+// NewLevelDBSignatureStore opens the LevelDB database at path, creating it if it doesn't exist, whose task responses
+// are decoded with decodeTaskResponse. The store must be closed with Close.
+func NewLevelDBSignatureStore(path string, decodeTaskResponse TaskResponseDecoder) (*LevelDBSignatureStore, error) {
+	if decodeTaskResponse == nil {
+		return nil, errors.New("task response decoder is nil")
+	}
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, utils.WrapError(fmt.Sprintf("failed to open signature store %s", path), err)
+	}
+	return &LevelDBSignatureStore{
+		db:                 db,
+		decodeTaskResponse: decodeTaskResponse,
+		writeOptions:       &opt.WriteOptions{Sync: true},
+	}, nil
+}
+
+// Close closes the database of the store
+func (s *LevelDBSignatureStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *LevelDBSignatureStore) SaveTask(task StoredTask) error {
+	quorumNumbers := make([]uint8, len(task.QuorumNumbers))
+	for i, quorumNumber := range task.QuorumNumbers {
+		quorumNumbers[i] = quorumNumber.UnderlyingType()
+	}
+	data, err := json.Marshal(levelDBStoredTask{
+		TaskIndex:                  task.TaskIndex,
+		TaskCreatedBlock:           task.TaskCreatedBlock,
+		QuorumNumbers:              quorumNumbers,
+		QuorumThresholdPercentages: task.QuorumThresholdPercentages,
+		ExpiresAt:                  task.ExpiresAt,
+		WindowDuration:             task.WindowDuration,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.saveSignaturesMutex.Lock()
+	defer s.saveSignaturesMutex.Unlock()
+	batch := new(leveldb.Batch)
+	if err := s.deleteTaskSignatures(batch, task.TaskIndex); err != nil {
+		return err
+	}
+	batch.Put(levelDBTaskKey(task.TaskIndex), data)
+	return s.db.Write(batch, s.writeOptions)
+}
+
+func (s *LevelDBSignatureStore) Save(taskIndex types.TaskIndex, signature StoredSignature) error {
+	taskResponse, err := json.Marshal(signature.TaskResponse)
+	if err != nil {
+		return utils.WrapError("failed to encode task response", err)
+	}
+	data, err := json.Marshal(levelDBStoredSignature{
+		OperatorId:   signature.OperatorId,
+		TaskResponse: taskResponse,
+		BlsSignature: signature.BlsSignature,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the position of the signature depends on the signatures already saved
+	s.saveSignaturesMutex.Lock()
+	defer s.saveSignaturesMutex.Unlock()
+	if _, err := s.db.Get(levelDBTaskKey(taskIndex), nil); err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return fmt.Errorf("%w: task %d", ErrTaskNotStored, taskIndex)
+		}
+		return err
+	}
+	position := uint32(0)
+	iter := s.db.NewIterator(util.BytesPrefix(levelDBSignaturesKeyPrefix(taskIndex)), nil)
+	if iter.Last() {
+		position = binary.BigEndian.Uint32(iter.Key()[len(iter.Key())-4:]) + 1
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return s.db.Put(
+		binary.BigEndian.AppendUint32(levelDBSignaturesKeyPrefix(taskIndex), position),
+		data,
+		s.writeOptions,
+	)
+}
+
+func (s *LevelDBSignatureStore) LoadTask(taskIndex types.TaskIndex) (StoredTask, error) {
+	snapshot, err := s.db.GetSnapshot()
+	if err != nil {
+		return StoredTask{}, err
+	}
+	defer snapshot.Release()
+
+	data, err := snapshot.Get(levelDBTaskKey(taskIndex), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return StoredTask{}, fmt.Errorf("%w: task %d", ErrTaskNotStored, taskIndex)
+		}
+		return StoredTask{}, err
+	}
+	var storedTask levelDBStoredTask
+	if err := json.Unmarshal(data, &storedTask); err != nil {
+		return StoredTask{}, utils.WrapError(fmt.Sprintf("failed to decode task %d", taskIndex), err)
+	}
+	task := StoredTask{
+		TaskIndex:                  storedTask.TaskIndex,
+		TaskCreatedBlock:           storedTask.TaskCreatedBlock,
+		QuorumNumbers:              make(types.QuorumNums, len(storedTask.QuorumNumbers)),
+		QuorumThresholdPercentages: storedTask.QuorumThresholdPercentages,
+		ExpiresAt:                  storedTask.ExpiresAt,
+		WindowDuration:             storedTask.WindowDuration,
+		Signatures:                 []StoredSignature{},
+	}
+	for i, quorumNumber := range storedTask.QuorumNumbers {
+		task.QuorumNumbers[i] = types.QuorumNum(quorumNumber)
+	}
+
+	iter := snapshot.NewIterator(util.BytesPrefix(levelDBSignaturesKeyPrefix(taskIndex)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		var storedSignature levelDBStoredSignature
+		if err := json.Unmarshal(iter.Value(), &storedSignature); err != nil {
+			return StoredTask{}, utils.WrapError(fmt.Sprintf("failed to decode signature of task %d", taskIndex), err)
+		}
+		taskResponse, err := s.decodeTaskResponse(storedSignature.TaskResponse)
+		if err != nil {
+			return StoredTask{}, utils.WrapError(
+				fmt.Sprintf("failed to decode task response of task %d", taskIndex),
+				err,
+			)
+		}
+		task.Signatures = append(task.Signatures, StoredSignature{
+			OperatorId:   storedSignature.OperatorId,
+			TaskResponse: taskResponse,
+			BlsSignature: storedSignature.BlsSignature,
+		})
+	}
+	if err := iter.Error(); err != nil {
+		return StoredTask{}, err
+	}
+	return task, nil
+}
+
+func (s *LevelDBSignatureStore) ListTasks() ([]types.TaskIndex, error) {
+	iter := s.db.NewIterator(util.BytesPrefix(levelDBTaskKeyPrefix), nil)
+	defer iter.Release()
+	taskIndices := []types.TaskIndex{}
+	for iter.Next() {
+		taskIndices = append(taskIndices, binary.BigEndian.Uint32(iter.Key()[len(levelDBTaskKeyPrefix):]))
+	}
+	return taskIndices, iter.Error()
+}
+
+func (s *LevelDBSignatureStore) DeleteTask(taskIndex types.TaskIndex) error {
+	s.saveSignaturesMutex.Lock()
+	defer s.saveSignaturesMutex.Unlock()
+	batch := new(leveldb.Batch)
+	if err := s.deleteTaskSignatures(batch, taskIndex); err != nil {
+		return err
+	}
+	batch.Delete(levelDBTaskKey(taskIndex))
+	return s.db.Write(batch, s.writeOptions)
+}
+
+// deleteTaskSignatures adds the deletions of the signatures of the task taskIndex to batch
+func (s *LevelDBSignatureStore) deleteTaskSignatures(batch *leveldb.Batch, taskIndex types.TaskIndex) error {
+	iter := s.db.NewIterator(util.BytesPrefix(levelDBSignaturesKeyPrefix(taskIndex)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+	return iter.Error()
+}
+
+func levelDBTaskKey(taskIndex types.TaskIndex) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte{}, levelDBTaskKeyPrefix...), taskIndex)
+}
+
+func levelDBSignaturesKeyPrefix(taskIndex types.TaskIndex) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte{}, levelDBSignatureKeyPrefix...), taskIndex)
+}
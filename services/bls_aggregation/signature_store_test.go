@@ -0,0 +1,119 @@
+package blsagg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+type storedTaskResponse struct {
+	Value int
+}
+
+func decodeStoredTaskResponse(data []byte) (types.TaskResponse, error) {
+	var taskResponse storedTaskResponse
+	err := json.Unmarshal(data, &taskResponse)
+	return taskResponse, err
+}
+
+// newTestSignatureStores returns the signature stores to test, and a function reopening each of them
+func newTestSignatureStores(t *testing.T) map[string]func() SignatureStore {
+	path := filepath.Join(t.TempDir(), "signatures")
+	var levelDBStore *LevelDBSignatureStore
+	t.Cleanup(func() {
+		if levelDBStore != nil {
+			require.NoError(t, levelDBStore.Close())
+		}
+	})
+	inMemoryStore := NewInMemorySignatureStore()
+	return map[string]func() SignatureStore{
+		"in memory": func() SignatureStore {
+			return inMemoryStore
+		},
+		"leveldb": func() SignatureStore {
+			if levelDBStore != nil {
+				require.NoError(t, levelDBStore.Close())
+			}
+			var err error
+			levelDBStore, err = NewLevelDBSignatureStore(path, decodeStoredTaskResponse)
+			require.NoError(t, err)
+			return levelDBStore
+		},
+	}
+}
+
+func TestSignatureStore(t *testing.T) {
+	keyPair1 := newBlsKeyPairPanics("0x1")
+	keyPair2 := newBlsKeyPairPanics("0x2")
+	task := StoredTask{
+		TaskIndex:                  7,
+		TaskCreatedBlock:           100,
+		QuorumNumbers:              types.QuorumNums{0, 2},
+		QuorumThresholdPercentages: types.QuorumThresholdPercentages{67, 100},
+		ExpiresAt:                  time.Unix(1700000000, 0).UTC(),
+		WindowDuration:             time.Second,
+	}
+	signatures := []StoredSignature{
+		{
+			OperatorId:   types.OperatorId{2},
+			TaskResponse: storedTaskResponse{123},
+			BlsSignature: keyPair2.SignMessage([32]byte{1}),
+		},
+		{
+			OperatorId:   types.OperatorId{1},
+			TaskResponse: storedTaskResponse{123},
+			BlsSignature: keyPair1.SignMessage([32]byte{1}),
+		},
+	}
+
+	for name, openStore := range newTestSignatureStores(t) {
+		t.Run(name, func(t *testing.T) {
+			store := openStore()
+			err := store.Save(task.TaskIndex, signatures[0])
+			require.ErrorIs(t, err, ErrTaskNotStored)
+			_, err = store.LoadTask(task.TaskIndex)
+			require.ErrorIs(t, err, ErrTaskNotStored)
+
+			require.NoError(t, store.SaveTask(task))
+			require.NoError(t, store.SaveTask(StoredTask{TaskIndex: 3, QuorumNumbers: types.QuorumNums{0}}))
+			for _, signature := range signatures {
+				accepted := signature
+				accepted.BlsSignature = bls.NewZeroSignature().Add(signature.BlsSignature)
+				require.NoError(t, store.Save(task.TaskIndex, accepted))
+				// the stored signatures aren't affected by the aggregation of the accepted ones in place
+				accepted.BlsSignature.Add(keyPair1.SignMessage([32]byte{2}))
+			}
+
+			store = openStore()
+			taskIndices, err := store.ListTasks()
+			require.NoError(t, err)
+			require.Equal(t, []types.TaskIndex{3, 7}, taskIndices)
+			wantTask := task
+			wantTask.Signatures = signatures
+			gotTask, err := store.LoadTask(task.TaskIndex)
+			require.NoError(t, err)
+			require.Equal(t, wantTask.ExpiresAt.UnixNano(), gotTask.ExpiresAt.UnixNano())
+			gotTask.ExpiresAt = wantTask.ExpiresAt
+			require.Equal(t, wantTask, gotTask)
+
+			// saving the task again drops its signatures
+			require.NoError(t, store.SaveTask(task))
+			gotTask, err = store.LoadTask(task.TaskIndex)
+			require.NoError(t, err)
+			require.Empty(t, gotTask.Signatures)
+
+			require.NoError(t, store.DeleteTask(task.TaskIndex))
+			require.NoError(t, store.DeleteTask(task.TaskIndex))
+			_, err = store.LoadTask(task.TaskIndex)
+			require.ErrorIs(t, err, ErrTaskNotStored)
+			taskIndices, err = store.ListTasks()
+			require.NoError(t, err)
+			require.Equal(t, []types.TaskIndex{3}, taskIndices)
+		})
+	}
+}
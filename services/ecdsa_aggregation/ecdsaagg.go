@@ -0,0 +1,640 @@
+// Package ecdsaagg aggregates the ECDSA signatures of the task responses of the operators of an AVS registered in an
+// ECDSAStakeRegistry. The SDK has no bindings of the ECDSAStakeRegistry, so it provides no implementation of the
+// EcdsaOperatorsService the aggregation service gets the operators of the tasks from: the caller supplies one, reading
+// the operators of the AVS, their signing keys and their stakes at the reference block of the tasks, e.g. from the
+// bindings of its own ECDSAStakeRegistry and the OperatorRegistered events it emits.
+package ecdsaagg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/internal/aggregation"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
+	blsagg "github.com/Layr-Labs/eigensdk-go/services/bls_aggregation"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The tasks of the service fail with the errors of the BLS aggregation service, so that the tasks of both services
+// are handled the same way, e.g. ProcessNewSignature returns a blsagg.TaskNotFoundError for the tasks which aren't
+// in-flight.
+var (
+	// ErrInvalidSignature is matched by the errors returned by ProcessNewSignature for the signatures which aren't
+	// valid 65 bytes [R || S || V] ECDSA signatures, or which the ECDSAStakeRegistry wouldn't accept, e.g. whose S
+	// value is malleable
+	ErrInvalidSignature = errors.New("invalid ECDSA signature")
+	// ErrSignerNotPartOfTaskQuorum is matched by the SignerNotPartOfTaskQuorumError returned by ProcessNewSignature
+	ErrSignerNotPartOfTaskQuorum = errors.New("signer not part of task quorum")
+)
+
+// SignerNotPartOfTaskQuorumError is returned by ProcessNewSignature for a signature whose key isn't the signing key of
+// an operator registered in any of the quorums of the task at its reference block
+type SignerNotPartOfTaskQuorumError struct {
+	// SignerAddr is the address of the key recovered from the signature
+	SignerAddr common.Address
+	TaskIndex  types.TaskIndex
+}
+
+func (e *SignerNotPartOfTaskQuorumError) Error() string {
+	return fmt.Sprintf("%s: signer %s of task %d", ErrSignerNotPartOfTaskQuorum, e.SignerAddr, e.TaskIndex)
+}
+
+func (e *SignerNotPartOfTaskQuorumError) Is(target error) bool {
+	return target == ErrSignerNotPartOfTaskQuorum
+}
+
+// ConflictingSignatureError is returned by ProcessNewSignature for the valid signature of an operator which already
+// signed another task response digest of the same task. It matches blsagg.ErrConflictingSignature.
+type ConflictingSignatureError struct {
+	OperatorAddr common.Address
+	TaskIndex    types.TaskIndex
+	// FirstDigest is the digest the operator signed first
+	FirstDigest  types.TaskResponseDigest
+	SecondDigest types.TaskResponseDigest
+}
+
+func (e *ConflictingSignatureError) Error() string {
+	return fmt.Sprintf(
+		"%s: operator %s signed digests %s and %s of task %d",
+		blsagg.ErrConflictingSignature,
+		e.OperatorAddr,
+		e.FirstDigest,
+		e.SecondDigest,
+		e.TaskIndex,
+	)
+}
+
+func (e *ConflictingSignatureError) Is(target error) bool {
+	return target == blsagg.ErrConflictingSignature
+}
+
+// EcdsaOperator is an operator signing the task responses with an ECDSA key, as registered at a block
+type EcdsaOperator struct {
+	OperatorAddr common.Address
+	// SigningKeyAddr is the address of the key the operator signs with, which may differ from OperatorAddr, e.g. the
+	// signing key of the operator in the ECDSAStakeRegistry
+	SigningKeyAddr common.Address
+	StakePerQuorum map[types.QuorumNum]types.StakeAmount
+}
+
+// EcdsaOperatorsService returns the ECDSA operators registered at a block, e.g. read from the ECDSAStakeRegistry of
+// the AVS. It is implemented by the caller of NewEcdsaAggregatorService, see the package doc.
+type EcdsaOperatorsService interface {
+	// GetEcdsaOperatorsAtBlock returns the operators registered in any of quorumNumbers at blockNumber
+	GetEcdsaOperatorsAtBlock(
+		ctx context.Context,
+		quorumNumbers types.QuorumNums,
+		blockNumber uint32,
+	) ([]EcdsaOperator, error)
+}
+
+// EcdsaAggregationServiceResponse is the response from the ECDSA aggregation service
+type EcdsaAggregationServiceResponse struct {
+	Err                error                    // if Err is not nil, the other fields are not valid
+	TaskIndex          types.TaskIndex          // unique identifier of the task
+	TaskResponse       types.TaskResponse       // the task response that was signed
+	TaskResponseDigest types.TaskResponseDigest // digest of the task response that was signed
+	// ReferenceBlock is the block the signers were registered at, the block the task was created at
+	ReferenceBlock uint32
+	// SignerAddrs are the addresses of the operators which signed TaskResponseDigest, in ascending order as the
+	// ECDSAStakeRegistry requires, and Signatures their signatures in the same order, with V in {27, 28}
+	SignerAddrs []common.Address
+	Signatures  [][]byte
+	// SignedStakePerQuorum and TotalStakePerQuorum are the stakes of the signers and of all the operators of each
+	// quorum of the task at ReferenceBlock
+	SignedStakePerQuorum map[types.QuorumNum]*big.Int
+	TotalStakePerQuorum  map[types.QuorumNum]*big.Int
+}
+
+// signatureDataArguments are the arguments of the signature data of ECDSAStakeRegistry.isValidSignature
+var signatureDataArguments = func() abi.Arguments {
+	addressesType, _ := abi.NewType("address[]", "", nil)
+	bytesArrayType, _ := abi.NewType("bytes[]", "", nil)
+	uint32Type, _ := abi.NewType("uint32", "", nil)
+	return abi.Arguments{{Type: addressesType}, {Type: bytesArrayType}, {Type: uint32Type}}
+}()
+
+// SignatureData returns abi.encode(SignerAddrs, Signatures, ReferenceBlock), the signature data to check the signatures
+// of the response on-chain with ECDSAStakeRegistry.isValidSignature(TaskResponseDigest, signatureData)
+func (r *EcdsaAggregationServiceResponse) SignatureData() ([]byte, error) {
+	return signatureDataArguments.Pack(r.SignerAddrs, r.Signatures, r.ReferenceBlock)
+}
+
+// EcdsaAggregationService is the ECDSA counterpart of blsagg.BlsAggregationService, for the operators signing the task
+// responses with ECDSA keys, e.g. the small operator sets of an AVS supporting both BLS and ECDSA operators
+type EcdsaAggregationService interface {
+	// InitializeNewTask creates a new task goroutine meant to process the signatures of the task sent via
+	// ProcessNewSignature. The task completes once a TaskResponseDigest is signed by operators whose stake in each of
+	// quorumNumbers adds up to at least quorumThresholdPercentages[i] of the total stake of the quorum at
+	// taskCreatedBlock, sending its response through the response channel, or once timeToExpiry is reached, sending a
+	// blsagg.TaskExpiredErrorFn error instead.
+	InitializeNewTask(
+		taskIndex types.TaskIndex,
+		taskCreatedBlock uint32,
+		quorumNumbers types.QuorumNums,
+		quorumThresholdPercentages types.QuorumThresholdPercentages,
+		timeToExpiry time.Duration,
+	) error
+
+	// InitializeNewTaskWithMetadata is InitializeNewTask with the time to expiry and quorum threshold percentages of
+	// metadata, whose unset ones are the ones of the default task metadata of the service, as of the initialization of
+	// the task. The ECDSA tasks have no window, so the window duration of metadata must be zero.
+	InitializeNewTaskWithMetadata(
+		taskIndex types.TaskIndex,
+		taskCreatedBlock uint32,
+		quorumNumbers types.QuorumNums,
+		metadata blsagg.TaskMetadata,
+	) error
+
+	// ProcessNewSignature processes the 65 bytes [R || S || V] ECDSA signature of the digest of taskResponse, whose V
+	// is either in {0, 1} or {27, 28}. The signer is the operator whose signing key is recovered from the signature,
+	// which is refused with a SignerNotPartOfTaskQuorumError if it isn't registered in the quorums of the task. It
+	// returns a blsagg.TaskNotFoundError if the task isn't in-flight.
+	ProcessNewSignature(
+		ctx context.Context,
+		taskIndex types.TaskIndex,
+		taskResponse types.TaskResponse,
+		signature []byte,
+	) error
+
+	// GetResponseChannel returns the channel the responses of the tasks are sent to, which is closed by Close once
+	// the responses of all the tasks were delivered
+	GetResponseChannel() <-chan EcdsaAggregationServiceResponse
+
+	// Close stops the service like blsagg.BlsAggregationService.Close: the new tasks are refused with
+	// blsagg.ErrServiceClosed, while the in-flight tasks keep aggregating their signatures until they complete or
+	// expire. Once ctx is done, the remaining tasks are cancelled, their responses having a blsagg.TaskCancelledError.
+	// The response channel must still be read while the service closes.
+	Close(ctx context.Context) error
+}
+
+// signedTaskResponse is a signature sent to the goroutine of its task, which sends the result of its verification
+// to signatureVerificationErrorC
+type signedTaskResponse struct {
+	taskResponse                types.TaskResponse
+	signature                   []byte
+	signatureVerificationErrorC chan error
+}
+
+// EcdsaAggregatorServiceOption configures the service returned by NewEcdsaAggregatorService
+type EcdsaAggregatorServiceOption func(*EcdsaAggregatorService)
+
+// WithDefaultTaskMetadata sets the default time to expiry and quorum threshold percentages of the tasks initialized
+// with InitializeNewTaskWithMetadata, see SetDefaultTaskMetadata. Its window duration is ignored.
+func WithDefaultTaskMetadata(metadata blsagg.TaskMetadata) EcdsaAggregatorServiceOption {
+	return func(a *EcdsaAggregatorService) {
+		a.defaultTaskMetadata = metadata
+	}
+}
+
+// WithMaxPendingResponses sets the number of responses waiting to be read from the response channel from which the
+// service reports itself as degraded, see Health. blsagg.DefaultMaxPendingResponses unless set.
+func WithMaxPendingResponses(maxPendingResponses int) EcdsaAggregatorServiceOption {
+	return func(a *EcdsaAggregatorService) {
+		if maxPendingResponses > 0 {
+			a.maxPendingResponses = maxPendingResponses
+		}
+	}
+}
+
+// WithMetrics sets the metrics of the service, blsagg.NoopMetrics unless set. The service has no verification
+// workers, so its verification metrics aren't updated.
+func WithMetrics(metrics blsagg.Metrics) EcdsaAggregatorServiceOption {
+	return func(a *EcdsaAggregatorService) {
+		a.metrics = metrics
+	}
+}
+
+// WithPrometheusRegisterer sets the metrics of the service to the ones blsagg.NewMetrics registers on reg, with the
+// ecdsa subsystem. The service has no metrics if reg is nil.
+func WithPrometheusRegisterer(reg prometheus.Registerer) EcdsaAggregatorServiceOption {
+	return func(a *EcdsaAggregatorService) {
+		if reg == nil {
+			a.metrics = blsagg.NewNoopMetrics()
+			return
+		}
+		a.metrics = blsagg.NewMetrics(reg, "ecdsa")
+	}
+}
+
+// EcdsaAggregatorService is an EcdsaAggregationService processing each task in a goroutine, with the task machinery
+// of the blsagg.BlsAggregatorService
+type EcdsaAggregatorService struct {
+	responses *aggregation.Responses[EcdsaAggregationServiceResponse]
+	// tasks are the in-flight tasks, whose handles are the channels their signatures are sent to
+	tasks                 *aggregation.Tasks[chan signedTaskResponse]
+	ecdsaOperatorsService EcdsaOperatorsService
+	hashFunction          types.TaskResponseHashFunction
+	logger                logging.Logger
+	metrics               blsagg.Metrics
+
+	// defaultTaskMetadata is the default metadata of the tasks initialized with InitializeNewTaskWithMetadata
+	defaultTaskMetadata      blsagg.TaskMetadata
+	defaultTaskMetadataMutex sync.RWMutex
+
+	// maxPendingResponses is the number of responses waiting to be read from the response channel from which the
+	// service is degraded
+	maxPendingResponses int
+}
+
+var _ EcdsaAggregationService = (*EcdsaAggregatorService)(nil)
+var _ services.HealthReporter = (*EcdsaAggregatorService)(nil)
+
+// NewEcdsaAggregatorService creates a new EcdsaAggregatorService, which gets the operators of the tasks from
+// ecdsaOperatorsService and computes the digests of the task responses with hashFunction
+func NewEcdsaAggregatorService(
+	ecdsaOperatorsService EcdsaOperatorsService,
+	hashFunction types.TaskResponseHashFunction,
+	logger logging.Logger,
+	opts ...EcdsaAggregatorServiceOption,
+) *EcdsaAggregatorService {
+	a := &EcdsaAggregatorService{
+		ecdsaOperatorsService: ecdsaOperatorsService,
+		hashFunction:          hashFunction,
+		logger:                logger,
+		metrics:               blsagg.NewNoopMetrics(),
+		maxPendingResponses:   blsagg.DefaultMaxPendingResponses,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.tasks = aggregation.NewTasks(logger, func(types.TaskIndex, chan signedTaskResponse) {
+		a.metrics.DecrementInFlightTasks()
+	})
+	a.responses = aggregation.NewResponses[EcdsaAggregationServiceResponse](logger, nil, 0, a.maxPendingResponses)
+	return a
+}
+
+// SetDefaultTaskMetadata sets the default time to expiry and quorum threshold percentages of the tasks initialized
+// with InitializeNewTaskWithMetadata. The tasks already initialized keep the ones they were initialized with. Its
+// window duration is ignored.
+func (a *EcdsaAggregatorService) SetDefaultTaskMetadata(metadata blsagg.TaskMetadata) {
+	a.defaultTaskMetadataMutex.Lock()
+	defer a.defaultTaskMetadataMutex.Unlock()
+	a.defaultTaskMetadata = metadata
+}
+
+func (a *EcdsaAggregatorService) GetResponseChannel() <-chan EcdsaAggregationServiceResponse {
+	return a.responses.C()
+}
+
+func (a *EcdsaAggregatorService) InitializeNewTask(
+	taskIndex types.TaskIndex,
+	taskCreatedBlock uint32,
+	quorumNumbers types.QuorumNums,
+	quorumThresholdPercentages types.QuorumThresholdPercentages,
+	timeToExpiry time.Duration,
+) error {
+	a.logger.Debug(
+		"EcdsaAggregatorService initializing new task",
+		"taskIndex", taskIndex,
+		"taskCreatedBlock", taskCreatedBlock,
+		"quorumNumbers", quorumNumbers,
+		"quorumThresholdPercentages", quorumThresholdPercentages,
+		"timeToExpiry", timeToExpiry,
+	)
+	if len(quorumNumbers) != len(quorumThresholdPercentages) {
+		return blsagg.TaskInitializationErrorFn(
+			fmt.Errorf(
+				"%d quorum numbers and %d quorum threshold percentages",
+				len(quorumNumbers),
+				len(quorumThresholdPercentages),
+			),
+			taskIndex,
+		)
+	}
+
+	newHandle := func() (chan signedTaskResponse, error) {
+		return make(chan signedTaskResponse), nil
+	}
+	err := a.tasks.Start(taskIndex, timeToExpiry, newHandle, func(task *aggregation.Task[chan signedTaskResponse]) {
+		a.singleTaskAggregatorGoroutineFunc(task, taskCreatedBlock, quorumNumbers, quorumThresholdPercentages)
+	})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, aggregation.ErrClosed):
+		return blsagg.TaskInitializationErrorFn(blsagg.ErrServiceClosed, taskIndex)
+	case errors.Is(err, aggregation.ErrTaskInFlight):
+		return blsagg.TaskAlreadyInitializedErrorFn(taskIndex)
+	default:
+		return blsagg.TaskInitializationErrorFn(err, taskIndex)
+	}
+}
+
+// InitializeNewTaskWithMetadata is InitializeNewTask with the time to expiry and quorum threshold percentages of
+// metadata, whose unset ones are the ones of the default task metadata of the service, as of the initialization of the
+// task: the later changes of the default task metadata don't affect the task.
+func (a *EcdsaAggregatorService) InitializeNewTaskWithMetadata(
+	taskIndex types.TaskIndex,
+	taskCreatedBlock uint32,
+	quorumNumbers types.QuorumNums,
+	metadata blsagg.TaskMetadata,
+) error {
+	if metadata.WindowDuration != 0 {
+		return blsagg.TaskInitializationErrorFn(
+			fmt.Errorf("ECDSA tasks have no window, got a window duration of %s", metadata.WindowDuration),
+			taskIndex,
+		)
+	}
+	a.defaultTaskMetadataMutex.RLock()
+	defaultTaskMetadata := a.defaultTaskMetadata
+	a.defaultTaskMetadataMutex.RUnlock()
+
+	timeToExpiry, quorumThresholdPercentages, err := aggregation.TaskParams(
+		len(quorumNumbers),
+		metadata.TimeToExpiry,
+		metadata.QuorumThresholdPercentages,
+		defaultTaskMetadata.TimeToExpiry,
+		defaultTaskMetadata.QuorumThresholdPercentages,
+	)
+	if err != nil {
+		return blsagg.TaskInitializationErrorFn(err, taskIndex)
+	}
+	return a.InitializeNewTask(taskIndex, taskCreatedBlock, quorumNumbers, quorumThresholdPercentages, timeToExpiry)
+}
+
+func (a *EcdsaAggregatorService) ProcessNewSignature(
+	ctx context.Context,
+	taskIndex types.TaskIndex,
+	taskResponse types.TaskResponse,
+	signature []byte,
+) error {
+	taskC, taskInitialized := a.tasks.Get(taskIndex)
+	if !taskInitialized {
+		a.metrics.IncrementRejectedSignatures(blsagg.RejectedSignatureReasonLate)
+		return blsagg.TaskNotFoundErrorFn(taskIndex)
+	}
+
+	signatureVerificationErrorC := make(chan error)
+	select {
+	case taskC <- signedTaskResponse{
+		taskResponse:                taskResponse,
+		signature:                   signature,
+		signatureVerificationErrorC: signatureVerificationErrorC,
+	}:
+		return <-signatureVerificationErrorC
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the service, see EcdsaAggregationService.Close. It returns ctx.Err() if tasks were cancelled, and nil
+// if all the in-flight tasks completed or expired before. It's safe to call several times.
+func (a *EcdsaAggregatorService) Close(ctx context.Context) error {
+	return a.tasks.Close(ctx, a.responses.Close)
+}
+
+// Name identifies the service in the health reports, see services.HealthRegistry
+func (a *EcdsaAggregatorService) Name() string {
+	return "ecdsa_aggregation"
+}
+
+// Health reports the service as degraded when the response channel is backed up, i.e. when at least
+// maxPendingResponses responses are waiting to be read from it, see WithMaxPendingResponses
+func (a *EcdsaAggregatorService) Health(ctx context.Context) (services.ServiceHealth, string) {
+	return a.responses.Health(ctx)
+}
+
+// digestSignatures are the signatures of the operators which signed a digest of a task
+type digestSignatures struct {
+	taskResponse types.TaskResponse
+	signatures   map[common.Address][]byte
+}
+
+func (a *EcdsaAggregatorService) singleTaskAggregatorGoroutineFunc(
+	task *aggregation.Task[chan signedTaskResponse],
+	taskCreatedBlock uint32,
+	quorumNumbers types.QuorumNums,
+	quorumThresholdPercentages types.QuorumThresholdPercentages,
+) {
+	taskIndex := task.Index
+	taskStartedAt := time.Now()
+	a.metrics.IncrementInFlightTasks()
+
+	operators, err := a.ecdsaOperatorsService.GetEcdsaOperatorsAtBlock(
+		context.Background(),
+		quorumNumbers,
+		taskCreatedBlock,
+	)
+	if err != nil {
+		a.logger.Error("Task goroutine failed to get the ECDSA operators", "taskIndex", taskIndex, "err", err)
+		// the task stops accepting signatures once it completes, since sending its response may block until it is read
+		task.Stop()
+		a.responses.Send(taskIndex, EcdsaAggregationServiceResponse{
+			Err: blsagg.TaskInitializationErrorFn(
+				utils.WrapError(fmt.Sprintf("failed to get ECDSA operators at block %d", taskCreatedBlock), err),
+				taskIndex,
+			),
+			TaskIndex: taskIndex,
+		})
+		return
+	}
+	// the operators of the task by signing key, with their stakes in the quorums of the task only
+	operatorsBySigningKey := make(map[common.Address]EcdsaOperator, len(operators))
+	totalStakePerQuorum := make(map[types.QuorumNum]*big.Int, len(quorumNumbers))
+	for _, quorumNumber := range quorumNumbers {
+		totalStakePerQuorum[quorumNumber] = big.NewInt(0)
+	}
+	for _, operator := range operators {
+		stakePerQuorum := make(map[types.QuorumNum]types.StakeAmount)
+		for _, quorumNumber := range quorumNumbers {
+			if stake, ok := operator.StakePerQuorum[quorumNumber]; ok {
+				stakePerQuorum[quorumNumber] = stake
+				totalStakePerQuorum[quorumNumber].Add(totalStakePerQuorum[quorumNumber], stake)
+			}
+		}
+		if len(stakePerQuorum) == 0 {
+			continue
+		}
+		operator.StakePerQuorum = stakePerQuorum
+		operatorsBySigningKey[operator.SigningKeyAddr] = operator
+	}
+
+	// digestStakes are the stakes which signed each digest, each operator only signing one
+	digestStakes := aggregation.NewDigestStakes[common.Address](
+		quorumNumbers,
+		quorumThresholdPercentages,
+		totalStakePerQuorum,
+	)
+	signaturesByDigest := map[types.TaskResponseDigest]*digestSignatures{}
+
+	// aggregateSignature verifies a signature of the task and adds it to the signers of its digest, returning the
+	// digest it signed
+	aggregateSignature := func(signedTaskResponse signedTaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseDigest, err := a.hashFunction(signedTaskResponse.taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, blsagg.HashFunctionError(err)
+		}
+		signerAddr, signature, err := recoverSigner(taskResponseDigest, signedTaskResponse.signature)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		operator, ok := operatorsBySigningKey[signerAddr]
+		if !ok {
+			return types.TaskResponseDigest{}, &SignerNotPartOfTaskQuorumError{
+				SignerAddr: signerAddr,
+				TaskIndex:  taskIndex,
+			}
+		}
+
+		switch status, signedDigest := digestStakes.Check(operator.OperatorAddr, taskResponseDigest); status {
+		case aggregation.SignatureDuplicate:
+			// duplicate signatures are accepted but only counted once
+			return taskResponseDigest, nil
+		case aggregation.SignatureConflicting:
+			return types.TaskResponseDigest{}, &ConflictingSignatureError{
+				OperatorAddr: operator.OperatorAddr,
+				TaskIndex:    taskIndex,
+				FirstDigest:  signedDigest,
+				SecondDigest: taskResponseDigest,
+			}
+		}
+		digestStakes.Add(operator.OperatorAddr, taskResponseDigest, operator.StakePerQuorum)
+
+		signatures, ok := signaturesByDigest[taskResponseDigest]
+		if !ok {
+			signatures = &digestSignatures{
+				taskResponse: signedTaskResponse.taskResponse,
+				signatures:   map[common.Address][]byte{},
+			}
+			signaturesByDigest[taskResponseDigest] = signatures
+		}
+		signatures.signatures[operator.OperatorAddr] = signature
+		return taskResponseDigest, nil
+	}
+
+	for {
+		select {
+		case signedTaskResponse := <-task.Handle:
+			taskResponseDigest, err := aggregateSignature(signedTaskResponse)
+			signedTaskResponse.signatureVerificationErrorC <- err
+			if err != nil {
+				a.logger.Debug("Task goroutine refused signature", "taskIndex", taskIndex, "err", err)
+				a.metrics.IncrementRejectedSignatures(rejectedSignatureReason(err))
+				continue
+			}
+			if !digestStakes.ThresholdsMet(taskResponseDigest) {
+				continue
+			}
+			a.logger.Debug(
+				"Task goroutine stake thresholds reached",
+				"taskIndex", taskIndex,
+				"taskResponseDigest", taskResponseDigest,
+			)
+			a.metrics.ObserveTimeToQuorum(time.Since(taskStartedAt))
+			task.Stop()
+			for quorumNumber, percentage := range digestStakes.SignedStakePercentages(taskResponseDigest) {
+				a.metrics.SetSignedStakePercentage(quorumNumber, percentage)
+			}
+			a.responses.Send(taskIndex, newEcdsaAggregationServiceResponse(
+				taskIndex,
+				taskCreatedBlock,
+				taskResponseDigest,
+				signaturesByDigest[taskResponseDigest],
+				digestStakes,
+			))
+			return
+		case <-task.ExpiredC:
+			task.Stop()
+			a.metrics.IncrementExpiredTasks()
+			a.responses.Send(taskIndex, EcdsaAggregationServiceResponse{
+				Err:       blsagg.TaskExpiredErrorFn(taskIndex),
+				TaskIndex: taskIndex,
+			})
+			return
+		case <-task.CancelledC:
+			// the task would have completed if it had met its stake thresholds
+			task.Stop()
+			a.logger.Info("Task goroutine cancelling task", "taskIndex", taskIndex)
+			a.responses.Send(taskIndex, EcdsaAggregationServiceResponse{
+				Err:       &blsagg.TaskCancelledError{TaskIndex: taskIndex},
+				TaskIndex: taskIndex,
+			})
+			return
+		}
+	}
+}
+
+// rejectedSignatureReason returns the reason of the metrics of the signature refused with err
+func rejectedSignatureReason(err error) blsagg.RejectedSignatureReason {
+	switch {
+	case errors.Is(err, ErrSignerNotPartOfTaskQuorum):
+		return blsagg.RejectedSignatureReasonUnknownOperator
+	case errors.Is(err, blsagg.ErrConflictingSignature):
+		return blsagg.RejectedSignatureReasonConflicting
+	default:
+		return blsagg.RejectedSignatureReasonInvalid
+	}
+}
+
+// newEcdsaAggregationServiceResponse returns the response of a task whose digest taskResponseDigest was signed with
+// signatures, ordered as the ECDSAStakeRegistry requires
+func newEcdsaAggregationServiceResponse(
+	taskIndex types.TaskIndex,
+	taskCreatedBlock uint32,
+	taskResponseDigest types.TaskResponseDigest,
+	signatures *digestSignatures,
+	digestStakes *aggregation.DigestStakes[common.Address],
+) EcdsaAggregationServiceResponse {
+	signerAddrs := digestStakes.DigestSigners(taskResponseDigest)
+	sort.Slice(signerAddrs, func(i, j int) bool {
+		return bytes.Compare(signerAddrs[i][:], signerAddrs[j][:]) < 0
+	})
+	signerSignatures := make([][]byte, len(signerAddrs))
+	for i, signerAddr := range signerAddrs {
+		signerSignatures[i] = signatures.signatures[signerAddr]
+	}
+	return EcdsaAggregationServiceResponse{
+		TaskIndex:            taskIndex,
+		TaskResponse:         signatures.taskResponse,
+		TaskResponseDigest:   taskResponseDigest,
+		ReferenceBlock:       taskCreatedBlock,
+		SignerAddrs:          signerAddrs,
+		Signatures:           signerSignatures,
+		SignedStakePerQuorum: digestStakes.SignedStakePerQuorum(taskResponseDigest),
+		TotalStakePerQuorum:  digestStakes.TotalStakePerQuorum(),
+	}
+}
+
+// recoverSigner returns the address of the key which signed digest, and the signature with V in {27, 28}, which is
+// the form the ECDSAStakeRegistry expects
+func recoverSigner(digest types.TaskResponseDigest, signature []byte) (common.Address, []byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, nil, fmt.Errorf(
+			"%w: %d bytes instead of %d",
+			ErrInvalidSignature,
+			len(signature),
+			crypto.SignatureLength,
+		)
+	}
+	// crypto.SigToPub expects V in {0, 1}
+	recoverySignature := bytes.Clone(signature)
+	if recoverySignature[crypto.RecoveryIDOffset] >= 27 {
+		recoverySignature[crypto.RecoveryIDOffset] -= 27
+	}
+	r := new(big.Int).SetBytes(recoverySignature[:32])
+	s := new(big.Int).SetBytes(recoverySignature[32:64])
+	if !crypto.ValidateSignatureValues(recoverySignature[crypto.RecoveryIDOffset], r, s, true) {
+		return common.Address{}, nil, fmt.Errorf("%w: invalid R, S or V values", ErrInvalidSignature)
+	}
+	pubkey, err := crypto.SigToPub(digest[:], recoverySignature)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	contractSignature := recoverySignature
+	contractSignature[crypto.RecoveryIDOffset] += 27
+	return crypto.PubkeyToAddress(*pubkey), contractSignature, nil
+}
@@ -0,0 +1,431 @@
+package ecdsaagg
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/services"
+	blsagg "github.com/Layr-Labs/eigensdk-go/services/bls_aggregation"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEcdsaOperatorsService returns the same operators at every block
+type fakeEcdsaOperatorsService struct {
+	operators []EcdsaOperator
+}
+
+func (s *fakeEcdsaOperatorsService) GetEcdsaOperatorsAtBlock(
+	ctx context.Context,
+	quorumNumbers types.QuorumNums,
+	blockNumber uint32,
+) ([]EcdsaOperator, error) {
+	return s.operators, nil
+}
+
+// testEcdsaOperator is an operator signing with a key distinct from its address
+type testEcdsaOperator struct {
+	EcdsaOperator
+	signingKey *ecdsa.PrivateKey
+}
+
+func newTestEcdsaOperator(t *testing.T, stake int64) testEcdsaOperator {
+	signingKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	operatorKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return testEcdsaOperator{
+		EcdsaOperator: EcdsaOperator{
+			OperatorAddr:   crypto.PubkeyToAddress(operatorKey.PublicKey),
+			SigningKeyAddr: crypto.PubkeyToAddress(signingKey.PublicKey),
+			StakePerQuorum: map[types.QuorumNum]types.StakeAmount{0: big.NewInt(stake)},
+		},
+		signingKey: signingKey,
+	}
+}
+
+func TestEcdsaAgg(t *testing.T) {
+	tasksTimeToExpiry := 1 * time.Second
+	logger := testutils.GetTestLogger()
+
+	hashFunction := func(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+		taskResponseBytes, err := json.Marshal(taskResponse)
+		if err != nil {
+			return types.TaskResponseDigest{}, err
+		}
+		return types.TaskResponseDigest(sha256.Sum256(taskResponseBytes)), nil
+	}
+
+	t.Run("3 ecdsa operators reach quorum", func(t *testing.T) {
+		operators := []testEcdsaOperator{
+			newTestEcdsaOperator(t, 100),
+			newTestEcdsaOperator(t, 100),
+			newTestEcdsaOperator(t, 100),
+			newTestEcdsaOperator(t, 100),
+		}
+		fakeOperatorsService := &fakeEcdsaOperatorsService{}
+		for _, operator := range operators {
+			fakeOperatorsService.operators = append(fakeOperatorsService.operators, operator.EcdsaOperator)
+		}
+		ecdsaAggServ := NewEcdsaAggregatorService(fakeOperatorsService, hashFunction, logger)
+
+		taskIndex := types.TaskIndex(0)
+		taskCreatedBlock := uint32(1)
+		taskResponse := mockTaskResponse{123}
+		taskResponseDigest, err := hashFunction(taskResponse)
+		require.NoError(t, err)
+		// 3 of the 4 operators are needed
+		err = ecdsaAggServ.InitializeNewTask(
+			taskIndex,
+			taskCreatedBlock,
+			types.QuorumNums{0},
+			types.QuorumThresholdPercentages{75},
+			tasksTimeToExpiry,
+		)
+		require.NoError(t, err)
+
+		for i, operator := range operators[:3] {
+			signature, err := crypto.Sign(taskResponseDigest[:], operator.signingKey)
+			require.NoError(t, err)
+			if i == 0 {
+				// the signatures with V in {27, 28} are accepted as well
+				signature[crypto.RecoveryIDOffset] += 27
+				// a duplicate signature is accepted but only counted once
+				err = ecdsaAggServ.ProcessNewSignature(context.Background(), taskIndex, taskResponse, signature)
+				require.NoError(t, err)
+			}
+			err = ecdsaAggServ.ProcessNewSignature(context.Background(), taskIndex, taskResponse, signature)
+			require.NoError(t, err)
+		}
+
+		gotResponse := <-ecdsaAggServ.GetResponseChannel()
+		require.NoError(t, gotResponse.Err)
+		require.Equal(t, taskIndex, gotResponse.TaskIndex)
+		require.Equal(t, taskResponse, gotResponse.TaskResponse)
+		require.Equal(t, taskResponseDigest, gotResponse.TaskResponseDigest)
+		require.Equal(t, taskCreatedBlock, gotResponse.ReferenceBlock)
+		require.Equal(t, map[types.QuorumNum]*big.Int{0: big.NewInt(300)}, gotResponse.SignedStakePerQuorum)
+		require.Equal(t, map[types.QuorumNum]*big.Int{0: big.NewInt(400)}, gotResponse.TotalStakePerQuorum)
+
+		signingKeyAddrs := map[common.Address]common.Address{}
+		for _, operator := range operators[:3] {
+			signingKeyAddrs[operator.OperatorAddr] = operator.SigningKeyAddr
+		}
+		require.Len(t, gotResponse.SignerAddrs, 3)
+		require.Len(t, gotResponse.Signatures, 3)
+		for i, signerAddr := range gotResponse.SignerAddrs {
+			if i > 0 {
+				require.Negative(t, bytes.Compare(gotResponse.SignerAddrs[i-1][:], signerAddr[:]))
+			}
+			signature := gotResponse.Signatures[i]
+			require.Contains(t, []byte{27, 28}, signature[crypto.RecoveryIDOffset])
+			recoverySignature := bytes.Clone(signature)
+			recoverySignature[crypto.RecoveryIDOffset] -= 27
+			pubkey, err := crypto.SigToPub(taskResponseDigest[:], recoverySignature)
+			require.NoError(t, err)
+			require.Equal(t, signingKeyAddrs[signerAddr], crypto.PubkeyToAddress(*pubkey))
+		}
+
+		signatureData, err := gotResponse.SignatureData()
+		require.NoError(t, err)
+		unpacked, err := signatureDataArguments.Unpack(signatureData)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{gotResponse.SignerAddrs, gotResponse.Signatures, taskCreatedBlock}, unpacked)
+
+		// the task doesn't accept signatures once completed
+		signature, err := crypto.Sign(taskResponseDigest[:], operators[3].signingKey)
+		require.NoError(t, err)
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), taskIndex, taskResponse, signature)
+		require.Equal(t, blsagg.TaskNotFoundErrorFn(taskIndex), err)
+	})
+
+	t.Run("signature from an unregistered key is rejected", func(t *testing.T) {
+		operator := newTestEcdsaOperator(t, 100)
+		fakeOperatorsService := &fakeEcdsaOperatorsService{operators: []EcdsaOperator{operator.EcdsaOperator}}
+		ecdsaAggServ := NewEcdsaAggregatorService(fakeOperatorsService, hashFunction, logger)
+
+		taskIndex := types.TaskIndex(0)
+		taskResponse := mockTaskResponse{123}
+		taskResponseDigest, err := hashFunction(taskResponse)
+		require.NoError(t, err)
+		err = ecdsaAggServ.InitializeNewTask(
+			taskIndex,
+			1,
+			types.QuorumNums{0},
+			types.QuorumThresholdPercentages{100},
+			tasksTimeToExpiry,
+		)
+		require.NoError(t, err)
+
+		unregisteredKey, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signature, err := crypto.Sign(taskResponseDigest[:], unregisteredKey)
+		require.NoError(t, err)
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), taskIndex, taskResponse, signature)
+		var signerErr *SignerNotPartOfTaskQuorumError
+		require.True(t, errors.As(err, &signerErr))
+		require.Equal(t, crypto.PubkeyToAddress(unregisteredKey.PublicKey), signerErr.SignerAddr)
+
+		// signatures which aren't 65 bytes long are refused
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), taskIndex, taskResponse, signature[:64])
+		require.ErrorIs(t, err, ErrInvalidSignature)
+
+		// the task expires without the signature of the operator
+		gotResponse := <-ecdsaAggServ.GetResponseChannel()
+		require.Equal(t, blsagg.TaskExpiredErrorFn(taskIndex), gotResponse.Err)
+	})
+
+	t.Run("conflicting signature is rejected", func(t *testing.T) {
+		operators := []testEcdsaOperator{newTestEcdsaOperator(t, 100), newTestEcdsaOperator(t, 100)}
+		fakeOperatorsService := &fakeEcdsaOperatorsService{
+			operators: []EcdsaOperator{operators[0].EcdsaOperator, operators[1].EcdsaOperator},
+		}
+		ecdsaAggServ := NewEcdsaAggregatorService(fakeOperatorsService, hashFunction, logger)
+
+		taskIndex := types.TaskIndex(0)
+		err := ecdsaAggServ.InitializeNewTask(
+			taskIndex,
+			1,
+			types.QuorumNums{0},
+			types.QuorumThresholdPercentages{100},
+			tasksTimeToExpiry,
+		)
+		require.NoError(t, err)
+
+		for _, taskResponse := range []mockTaskResponse{{1}, {2}} {
+			taskResponseDigest, err := hashFunction(taskResponse)
+			require.NoError(t, err)
+			signature, err := crypto.Sign(taskResponseDigest[:], operators[0].signingKey)
+			require.NoError(t, err)
+			err = ecdsaAggServ.ProcessNewSignature(context.Background(), taskIndex, taskResponse, signature)
+			if taskResponse.Value == 1 {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, blsagg.ErrConflictingSignature)
+			}
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		operators := []testEcdsaOperator{newTestEcdsaOperator(t, 100), newTestEcdsaOperator(t, 100)}
+		fakeOperatorsService := &fakeEcdsaOperatorsService{
+			operators: []EcdsaOperator{operators[0].EcdsaOperator, operators[1].EcdsaOperator},
+		}
+		reg := prometheus.NewRegistry()
+		ecdsaAggServ := NewEcdsaAggregatorService(
+			fakeOperatorsService,
+			hashFunction,
+			logger,
+			WithPrometheusRegisterer(reg),
+		)
+
+		// the first task completes, with a signature conflicting with the previous one of the same operator
+		taskResponse := mockTaskResponse{1}
+		taskResponseDigest, err := hashFunction(taskResponse)
+		require.NoError(t, err)
+		err = ecdsaAggServ.InitializeNewTask(0, 1, types.QuorumNums{0}, types.QuorumThresholdPercentages{100}, time.Hour)
+		require.NoError(t, err)
+		signature, err := crypto.Sign(taskResponseDigest[:], operators[0].signingKey)
+		require.NoError(t, err)
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), 0, taskResponse, signature)
+		require.NoError(t, err)
+		conflictingTaskResponse := mockTaskResponse{2}
+		conflictingTaskResponseDigest, err := hashFunction(conflictingTaskResponse)
+		require.NoError(t, err)
+		conflictingSignature, err := crypto.Sign(conflictingTaskResponseDigest[:], operators[0].signingKey)
+		require.NoError(t, err)
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), 0, conflictingTaskResponse, conflictingSignature)
+		require.ErrorIs(t, err, blsagg.ErrConflictingSignature)
+		signature, err = crypto.Sign(taskResponseDigest[:], operators[1].signingKey)
+		require.NoError(t, err)
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), 0, taskResponse, signature)
+		require.NoError(t, err)
+		gotResponse := <-ecdsaAggServ.GetResponseChannel()
+		require.NoError(t, gotResponse.Err)
+
+		// the signature of the completed task is late, and the second task expires
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), 0, taskResponse, signature)
+		require.Equal(t, blsagg.TaskNotFoundErrorFn(0), err)
+		err = ecdsaAggServ.InitializeNewTask(
+			1,
+			1,
+			types.QuorumNums{0},
+			types.QuorumThresholdPercentages{100},
+			100*time.Millisecond,
+		)
+		require.NoError(t, err)
+		gotResponse = <-ecdsaAggServ.GetResponseChannel()
+		require.Equal(t, blsagg.TaskExpiredErrorFn(1), gotResponse.Err)
+
+		signedStakePercentageHelp := "percentage of the stake of each quorum which signed the response of the most " +
+			"recent task"
+		wantMetrics := `
+# HELP blsagg_ecdsa_expired_tasks_total number of tasks which expired before meeting their stake thresholds
+# TYPE blsagg_ecdsa_expired_tasks_total counter
+blsagg_ecdsa_expired_tasks_total 1
+# HELP blsagg_ecdsa_in_flight_tasks number of tasks being aggregated
+# TYPE blsagg_ecdsa_in_flight_tasks gauge
+blsagg_ecdsa_in_flight_tasks 0
+# HELP blsagg_ecdsa_rejected_signatures_total number of signatures refused, by reason
+# TYPE blsagg_ecdsa_rejected_signatures_total counter
+blsagg_ecdsa_rejected_signatures_total{reason="conflicting"} 1
+blsagg_ecdsa_rejected_signatures_total{reason="late"} 1
+# HELP blsagg_ecdsa_signed_stake_percentage ` + signedStakePercentageHelp + `
+# TYPE blsagg_ecdsa_signed_stake_percentage gauge
+blsagg_ecdsa_signed_stake_percentage{quorum="0"} 100
+`
+		require.NoError(t, testutil.GatherAndCompare(
+			reg,
+			strings.NewReader(wantMetrics),
+			"blsagg_ecdsa_expired_tasks_total",
+			"blsagg_ecdsa_in_flight_tasks",
+			"blsagg_ecdsa_rejected_signatures_total",
+			"blsagg_ecdsa_signed_stake_percentage",
+		))
+	})
+
+	t.Run("task metadata defaults", func(t *testing.T) {
+		operator := newTestEcdsaOperator(t, 100)
+		fakeOperatorsService := &fakeEcdsaOperatorsService{operators: []EcdsaOperator{operator.EcdsaOperator}}
+		ecdsaAggServ := NewEcdsaAggregatorService(
+			fakeOperatorsService,
+			hashFunction,
+			logger,
+			WithDefaultTaskMetadata(blsagg.TaskMetadata{
+				TimeToExpiry:               100 * time.Millisecond,
+				QuorumThresholdPercentages: types.QuorumThresholdPercentages{100},
+			}),
+		)
+
+		// the task gets the default time to expiry and quorum threshold percentages
+		err := ecdsaAggServ.InitializeNewTaskWithMetadata(0, 1, types.QuorumNums{0}, blsagg.TaskMetadata{})
+		require.NoError(t, err)
+		// the later changes of the default task metadata don't affect the task
+		ecdsaAggServ.SetDefaultTaskMetadata(blsagg.TaskMetadata{TimeToExpiry: time.Hour})
+		gotResponse := <-ecdsaAggServ.GetResponseChannel()
+		require.Equal(t, blsagg.TaskExpiredErrorFn(0), gotResponse.Err)
+
+		// the task has no quorum threshold percentages
+		err = ecdsaAggServ.InitializeNewTaskWithMetadata(1, 1, types.QuorumNums{0}, blsagg.TaskMetadata{})
+		require.ErrorContains(t, err, "Failed to initialize task 1")
+		// the ECDSA tasks have no window
+		err = ecdsaAggServ.InitializeNewTaskWithMetadata(1, 1, types.QuorumNums{0}, blsagg.TaskMetadata{
+			QuorumThresholdPercentages: types.QuorumThresholdPercentages{100},
+			WindowDuration:             time.Second,
+		})
+		require.ErrorContains(t, err, "Failed to initialize task 1")
+	})
+
+	t.Run("close drains the in-flight tasks", func(t *testing.T) {
+		operator := newTestEcdsaOperator(t, 100)
+		fakeOperatorsService := &fakeEcdsaOperatorsService{operators: []EcdsaOperator{operator.EcdsaOperator}}
+		ecdsaAggServ := NewEcdsaAggregatorService(fakeOperatorsService, hashFunction, logger)
+
+		taskResponse := mockTaskResponse{123}
+		taskResponseDigest, err := hashFunction(taskResponse)
+		require.NoError(t, err)
+		err = ecdsaAggServ.InitializeNewTask(0, 1, types.QuorumNums{0}, types.QuorumThresholdPercentages{100}, time.Hour)
+		require.NoError(t, err)
+
+		closeErrC := make(chan error)
+		go func() {
+			closeErrC <- ecdsaAggServ.Close(context.Background())
+		}()
+		// the new tasks are refused once the service closes, while the in-flight task still completes. The task is
+		// initialized again to wait for the service to close, since it's refused as already initialized until then.
+		require.Eventually(t, func() bool {
+			err := ecdsaAggServ.InitializeNewTask(
+				0,
+				1,
+				types.QuorumNums{0},
+				types.QuorumThresholdPercentages{100},
+				time.Hour,
+			)
+			return errors.Is(err, blsagg.ErrServiceClosed)
+		}, time.Second, 10*time.Millisecond)
+		signature, err := crypto.Sign(taskResponseDigest[:], operator.signingKey)
+		require.NoError(t, err)
+		err = ecdsaAggServ.ProcessNewSignature(context.Background(), 0, taskResponse, signature)
+		require.NoError(t, err)
+
+		gotResponse := <-ecdsaAggServ.GetResponseChannel()
+		require.NoError(t, gotResponse.Err)
+		require.NoError(t, <-closeErrC)
+		_, ok := <-ecdsaAggServ.GetResponseChannel()
+		require.False(t, ok)
+	})
+
+	t.Run("close cancels the tasks still in-flight at its deadline", func(t *testing.T) {
+		operator := newTestEcdsaOperator(t, 100)
+		fakeOperatorsService := &fakeEcdsaOperatorsService{operators: []EcdsaOperator{operator.EcdsaOperator}}
+		ecdsaAggServ := NewEcdsaAggregatorService(fakeOperatorsService, hashFunction, logger)
+
+		err := ecdsaAggServ.InitializeNewTask(
+			0,
+			1,
+			types.QuorumNums{0},
+			types.QuorumThresholdPercentages{100},
+			time.Hour,
+		)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		closeErrC := make(chan error)
+		go func() {
+			closeErrC <- ecdsaAggServ.Close(ctx)
+		}()
+		gotResponse := <-ecdsaAggServ.GetResponseChannel()
+		require.ErrorIs(t, gotResponse.Err, blsagg.ErrTaskCancelled)
+		require.ErrorIs(t, <-closeErrC, context.DeadlineExceeded)
+	})
+
+	t.Run("health is degraded while the responses aren't read", func(t *testing.T) {
+		operator := newTestEcdsaOperator(t, 100)
+		fakeOperatorsService := &fakeEcdsaOperatorsService{operators: []EcdsaOperator{operator.EcdsaOperator}}
+		ecdsaAggServ := NewEcdsaAggregatorService(
+			fakeOperatorsService,
+			hashFunction,
+			logger,
+			WithMaxPendingResponses(1),
+		)
+		health, _ := ecdsaAggServ.Health(context.Background())
+		require.Equal(t, services.ServiceHealthy, health)
+
+		err := ecdsaAggServ.InitializeNewTask(
+			0,
+			1,
+			types.QuorumNums{0},
+			types.QuorumThresholdPercentages{100},
+			10*time.Millisecond,
+		)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			health, _ := ecdsaAggServ.Health(context.Background())
+			return health == services.ServiceDegraded
+		}, time.Second, 10*time.Millisecond)
+
+		<-ecdsaAggServ.GetResponseChannel()
+		require.Eventually(t, func() bool {
+			health, _ := ecdsaAggServ.Health(context.Background())
+			return health == services.ServiceHealthy
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+type mockTaskResponse struct {
+	Value int
+}
@@ -0,0 +1,309 @@
+package ejectionmonitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// DefaultPollInterval is the interval between the checks of the registration of the operator unless set with
+// WithPollInterval
+const DefaultPollInterval = time.Minute
+
+type avsRegistryReader interface {
+	GetOperatorFromId(opts *bind.CallOpts, operatorId types.OperatorId) (common.Address, error)
+
+	QueryRegistrationDetail(
+		opts *bind.CallOpts,
+		operatorAddress common.Address,
+	) (avsregistry.QuorumRegistrationDetail, error)
+}
+
+type avsRegistrySubscriber interface {
+	SubscribeToOperatorDeregistrations() (
+		<-chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered,
+		event.Subscription,
+		error,
+	)
+}
+
+// Ejection is the removal of the monitored operator from quorums it is expected to be registered in, e.g. by the
+// ejector of the AVS
+type Ejection struct {
+	OperatorId   types.OperatorId
+	OperatorAddr common.Address
+	// QuorumNumbers are the monitored quorums the operator was removed from since it was last seen registered in them
+	QuorumNumbers types.QuorumNums
+	// BlockNumber is the block of the OperatorDeregistered event which revealed the ejection, or 0 if it was revealed
+	// by a periodic check
+	BlockNumber uint64
+}
+
+// EjectionCallback is called by an EjectionMonitor when the operator is ejected. It is called from the goroutine of
+// the monitor, which doesn't check the registration of the operator until it returns.
+type EjectionCallback func(ctx context.Context, ejection Ejection)
+
+// EjectionMonitor monitors the registration of an operator in the quorums it is expected to be registered in, calling
+// an EjectionCallback when it is removed from any of them. The registration is checked on the OperatorDeregistered
+// events of the operator, and periodically in case the events are missed, e.g. while the websocket connection of the
+// subscriber reconnects, or the operator is only removed from some of the quorums, which doesn't emit the event.
+// The callback is called once per removal from a quorum: once the operator registers again in the quorum, it is
+// called again if the operator is removed again.
+type EjectionMonitor struct {
+	avsRegistryReader     avsRegistryReader
+	avsRegistrySubscriber avsRegistrySubscriber
+	operatorId            types.OperatorId
+	quorumNumbers         types.QuorumNums
+	onEjection            EjectionCallback
+	logger                logging.Logger
+	metrics               Metrics
+	pollInterval          time.Duration
+
+	operatorAddr common.Address
+	// ejectedQuorums are the monitored quorums the operator was removed from, only accessed by the goroutine of the
+	// monitor
+	ejectedQuorums map[types.QuorumNum]bool
+
+	// the state reported by Health, updated by the goroutine of the monitor
+	healthMutex      sync.Mutex
+	ejectedSnapshot  types.QuorumNums
+	checkFailing     bool
+	subscriptionDown bool
+}
+
+var _ services.HealthReporter = (*EjectionMonitor)(nil)
+
+type EjectionMonitorOption func(*EjectionMonitor)
+
+// WithPollInterval sets the interval between the periodic checks of the registration of the operator,
+// DefaultPollInterval unless set
+func WithPollInterval(pollInterval time.Duration) EjectionMonitorOption {
+	return func(m *EjectionMonitor) {
+		m.pollInterval = pollInterval
+	}
+}
+
+// WithMetrics sets the metrics of the monitor, NoopMetrics unless set
+func WithMetrics(metrics Metrics) EjectionMonitorOption {
+	return func(m *EjectionMonitor) {
+		m.metrics = metrics
+	}
+}
+
+// NewEjectionMonitor returns a monitor of the registration of operatorId in quorumNumbers, which calls onEjection
+// when the operator is removed from any of them once started, see Start. The subscriber should be built with a
+// reconnecting websocket client (see eth.NewWsClientWithReconnect), so that its subscription survives connection
+// drops.
+func NewEjectionMonitor(
+	avsRegistryReader avsRegistryReader,
+	avsRegistrySubscriber avsRegistrySubscriber,
+	operatorId types.OperatorId,
+	quorumNumbers types.QuorumNums,
+	onEjection EjectionCallback,
+	logger logging.Logger,
+	opts ...EjectionMonitorOption,
+) *EjectionMonitor {
+	m := &EjectionMonitor{
+		avsRegistryReader:     avsRegistryReader,
+		avsRegistrySubscriber: avsRegistrySubscriber,
+		operatorId:            operatorId,
+		quorumNumbers:         quorumNumbers,
+		onEjection:            onEjection,
+		logger:                logger.With(logging.ComponentKey, "ejectionmonitor/EjectionMonitor"),
+		metrics:               NewNoopMetrics(),
+		pollInterval:          DefaultPollInterval,
+		ejectedQuorums:        make(map[types.QuorumNum]bool),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start subscribes to the deregistrations of the operators and checks the registration of the operator once, calling
+// the EjectionCallback if it isn't registered in all the monitored quorums already, then monitors it in a goroutine
+// until ctx is done. It returns an error if the operator never registered or the subscription fails.
+func (m *EjectionMonitor) Start(ctx context.Context) error {
+	if m.pollInterval <= 0 {
+		return fmt.Errorf("invalid poll interval %s", m.pollInterval)
+	}
+	operatorAddr, err := m.avsRegistryReader.GetOperatorFromId(&bind.CallOpts{Context: ctx}, m.operatorId)
+	if err != nil {
+		return utils.WrapError("failed to get the address of the operator", err)
+	}
+	if operatorAddr == (common.Address{}) {
+		return fmt.Errorf("operator %s never registered", m.operatorId.String())
+	}
+	m.operatorAddr = operatorAddr
+
+	deregisteredC, sub, err := m.avsRegistrySubscriber.SubscribeToOperatorDeregistrations()
+	if err != nil {
+		return utils.WrapError("failed to subscribe to operator deregistrations", err)
+	}
+	m.checkRegistration(ctx, 0)
+	go m.monitor(ctx, deregisteredC, sub)
+	return nil
+}
+
+// monitor checks the registration of the operator on its deregistrations and periodically until ctx is done
+func (m *EjectionMonitor) monitor(
+	ctx context.Context,
+	deregisteredC <-chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered,
+	sub event.Subscription,
+) {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+	// subErrC is nil while the subscription is down, the registration only being checked periodically
+	subErrC := sub.Err()
+	defer func() {
+		if sub != nil {
+			sub.Unsubscribe()
+		}
+	}()
+
+	// resubscribe replaces the subscription which failed, checking the registration since the events emitted while
+	// it was down are missed
+	resubscribe := func() {
+		var err error
+		deregisteredC, sub, err = m.avsRegistrySubscriber.SubscribeToOperatorDeregistrations()
+		if err != nil {
+			m.logger.Error("Failed to resubscribe to operator deregistrations", "err", err)
+			deregisteredC, sub, subErrC = nil, nil, nil
+			m.setSubscriptionDown(true)
+			return
+		}
+		subErrC = sub.Err()
+		m.setSubscriptionDown(false)
+		m.checkRegistration(ctx, 0)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-subErrC:
+			m.logger.Warn("Operator deregistrations subscription failed, resubscribing", "err", err)
+			sub.Unsubscribe()
+			resubscribe()
+		case deregistered := <-deregisteredC:
+			if deregistered.OperatorId != m.operatorId {
+				continue
+			}
+			m.checkRegistration(ctx, deregistered.Raw.BlockNumber)
+		case <-ticker.C:
+			if sub == nil {
+				resubscribe()
+				continue
+			}
+			m.checkRegistration(ctx, 0)
+		}
+	}
+}
+
+// checkRegistration queries the quorums the operator is registered in, calling the EjectionCallback if it was removed
+// from any of the monitored quorums since the last check. blockNumber is the block of the deregistration event which
+// triggered the check, if any.
+func (m *EjectionMonitor) checkRegistration(ctx context.Context, blockNumber uint64) {
+	registrationDetail, err := m.avsRegistryReader.QueryRegistrationDetail(
+		&bind.CallOpts{Context: ctx},
+		m.operatorAddr,
+	)
+	if err != nil {
+		if ctx.Err() == nil {
+			m.logger.Warn("Failed to check the registration of the operator", "err", err)
+			m.metrics.IncrementFailedChecks()
+			m.setCheckFailing(true)
+		}
+		return
+	}
+	m.setCheckFailing(false)
+
+	var newlyEjectedQuorums types.QuorumNums
+	for _, quorumNumber := range m.quorumNumbers {
+		registered := int(quorumNumber) < len(registrationDetail) && registrationDetail[quorumNumber]
+		m.metrics.SetRegistered(quorumNumber, registered)
+		if registered {
+			delete(m.ejectedQuorums, quorumNumber)
+			continue
+		}
+		if !m.ejectedQuorums[quorumNumber] {
+			m.ejectedQuorums[quorumNumber] = true
+			newlyEjectedQuorums = append(newlyEjectedQuorums, quorumNumber)
+		}
+	}
+	m.setEjectedSnapshot()
+	if len(newlyEjectedQuorums) == 0 {
+		return
+	}
+
+	m.logger.Warn(
+		"Operator ejected",
+		"operatorId", m.operatorId,
+		"operatorAddr", m.operatorAddr,
+		"quorumNumbers", newlyEjectedQuorums,
+		"block", blockNumber,
+	)
+	m.metrics.IncrementEjections()
+	m.onEjection(ctx, Ejection{
+		OperatorId:    m.operatorId,
+		OperatorAddr:  m.operatorAddr,
+		QuorumNumbers: newlyEjectedQuorums,
+		BlockNumber:   blockNumber,
+	})
+}
+
+func (m *EjectionMonitor) setEjectedSnapshot() {
+	ejectedQuorums := make(types.QuorumNums, 0, len(m.ejectedQuorums))
+	for quorumNumber := range m.ejectedQuorums {
+		ejectedQuorums = append(ejectedQuorums, quorumNumber)
+	}
+	sort.Slice(ejectedQuorums, func(i, j int) bool { return ejectedQuorums[i] < ejectedQuorums[j] })
+	m.healthMutex.Lock()
+	defer m.healthMutex.Unlock()
+	m.ejectedSnapshot = ejectedQuorums
+}
+
+func (m *EjectionMonitor) setCheckFailing(checkFailing bool) {
+	m.healthMutex.Lock()
+	defer m.healthMutex.Unlock()
+	m.checkFailing = checkFailing
+}
+
+func (m *EjectionMonitor) setSubscriptionDown(subscriptionDown bool) {
+	m.healthMutex.Lock()
+	defer m.healthMutex.Unlock()
+	m.subscriptionDown = subscriptionDown
+}
+
+func (m *EjectionMonitor) Name() string {
+	return "ejectionmonitor"
+}
+
+// Health reports the monitor unhealthy while the operator isn't registered in all the monitored quorums, and degraded
+// while its registration can't be checked or its deregistrations subscription is down
+func (m *EjectionMonitor) Health(ctx context.Context) (services.ServiceHealth, string) {
+	m.healthMutex.Lock()
+	defer m.healthMutex.Unlock()
+	if len(m.ejectedSnapshot) > 0 {
+		return services.ServiceUnhealthy, fmt.Sprintf("operator ejected from quorums %v", m.ejectedSnapshot)
+	}
+	if m.checkFailing {
+		return services.ServiceDegraded, "failed to check the registration of the operator"
+	}
+	if m.subscriptionDown {
+		return services.ServiceDegraded, "operator deregistrations subscription down"
+	}
+	return services.ServiceHealthy, ""
+}
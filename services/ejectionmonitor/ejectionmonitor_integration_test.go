@@ -0,0 +1,91 @@
+package ejectionmonitor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/services/ejectionmonitor"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegrationEjectionMonitor ejects an operator registered on anvil and checks that the monitor reports it within
+// its poll interval
+func TestIntegrationEjectionMonitor(t *testing.T) {
+	anvilC, err := testutils.StartAnvilContainer("contracts-deployed-anvil-state.json")
+	require.NoError(t, err)
+	anvilHttpEndpoint, err := anvilC.Endpoint(context.Background(), "http")
+	require.NoError(t, err)
+	anvilWsEndpoint, err := anvilC.Endpoint(context.Background(), "ws")
+	require.NoError(t, err)
+	contractAddrs := testutils.GetContractAddressesFromContractRegistry(anvilHttpEndpoint)
+	logger := testutils.GetTestLogger()
+
+	// the operator is also the owner of the RegistryCoordinator, which makes it the ejector below
+	ecdsaPrivKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	operatorAddr := crypto.PubkeyToAddress(ecdsaPrivKey.PublicKey)
+	avsClients, err := clients.BuildAll(clients.BuildAllConfig{
+		EthHttpUrl:                 anvilHttpEndpoint,
+		EthWsUrl:                   anvilWsEndpoint,
+		RegistryCoordinatorAddr:    contractAddrs.RegistryCoordinator.String(),
+		OperatorStateRetrieverAddr: contractAddrs.OperatorStateRetriever.String(),
+		AvsName:                    "avs",
+		PromMetricsIpPortAddress:   "localhost:9090",
+	}, ecdsaPrivKey, logger)
+	require.NoError(t, err)
+
+	blsKeyPair, err := bls.NewKeyPairFromString("0x1")
+	require.NoError(t, err)
+	quorumNumbers := types.QuorumNums{0}
+	_, err = avsClients.AvsRegistryChainWriter.RegisterOperator(
+		context.Background(),
+		ecdsaPrivKey,
+		blsKeyPair,
+		quorumNumbers,
+		"socket",
+		true,
+	)
+	require.NoError(t, err)
+	_, err = avsClients.AvsRegistryChainWriter.SetEjector(context.Background(), operatorAddr, true)
+	require.NoError(t, err)
+
+	pollInterval := 2 * time.Second
+	ejectionC := make(chan ejectionmonitor.Ejection, 1)
+	monitor := ejectionmonitor.NewEjectionMonitor(
+		avsClients.AvsRegistryChainReader,
+		avsClients.AvsRegistryChainSubscriber,
+		types.OperatorIdFromKeyPair(blsKeyPair),
+		quorumNumbers,
+		func(ctx context.Context, ejection ejectionmonitor.Ejection) { ejectionC <- ejection },
+		logger,
+		ejectionmonitor.WithPollInterval(pollInterval),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, monitor.Start(ctx))
+
+	noSendTxOpts, err := avsClients.TxManager.GetNoSendTxOpts()
+	require.NoError(t, err)
+	tx, err := avsClients.AvsRegistryContractBindings.RegistryCoordinator.EjectOperator(
+		noSendTxOpts,
+		operatorAddr,
+		quorumNumbers.UnderlyingType(),
+	)
+	require.NoError(t, err)
+	_, err = avsClients.TxManager.Send(context.Background(), tx, true)
+	require.NoError(t, err)
+
+	select {
+	case ejection := <-ejectionC:
+		require.Equal(t, operatorAddr, ejection.OperatorAddr)
+		require.Equal(t, quorumNumbers, ejection.QuorumNumbers)
+	case <-time.After(pollInterval + time.Second):
+		t.Fatal("the ejection wasn't reported within the poll interval")
+	}
+}
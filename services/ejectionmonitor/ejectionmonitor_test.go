@@ -0,0 +1,264 @@
+package ejectionmonitor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/services"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// fakeAvsRegistry is an avs registry reader and subscriber whose operator is registered in the quorums it is set to
+type fakeAvsRegistry struct {
+	operatorAddr common.Address
+
+	mu                sync.Mutex
+	registeredQuorums []bool
+	queryErr          error
+	deregisteredC     chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered
+	subErrC           chan error
+	subscriptions     int
+}
+
+func newFakeAvsRegistry(registeredQuorums ...bool) *fakeAvsRegistry {
+	return &fakeAvsRegistry{
+		operatorAddr:      common.HexToAddress("0x1"),
+		registeredQuorums: registeredQuorums,
+	}
+}
+
+func (r *fakeAvsRegistry) setRegisteredQuorums(registeredQuorums ...bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registeredQuorums = registeredQuorums
+}
+
+func (r *fakeAvsRegistry) setQueryErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queryErr = err
+}
+
+func (r *fakeAvsRegistry) GetOperatorFromId(opts *bind.CallOpts, operatorId types.OperatorId) (common.Address, error) {
+	return r.operatorAddr, nil
+}
+
+func (r *fakeAvsRegistry) QueryRegistrationDetail(
+	opts *bind.CallOpts,
+	operatorAddress common.Address,
+) (avsregistry.QuorumRegistrationDetail, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.queryErr != nil {
+		return nil, r.queryErr
+	}
+	return append(avsregistry.QuorumRegistrationDetail{}, r.registeredQuorums...), nil
+}
+
+func (r *fakeAvsRegistry) SubscribeToOperatorDeregistrations() (
+	<-chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered,
+	event.Subscription,
+	error,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions++
+	r.deregisteredC = make(chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered)
+	subErrC := make(chan error, 1)
+	r.subErrC = subErrC
+	sub := event.NewSubscription(func(quit <-chan struct{}) error {
+		select {
+		case err := <-subErrC:
+			return err
+		case <-quit:
+			return nil
+		}
+	})
+	return r.deregisteredC, sub, nil
+}
+
+// deregister sends the OperatorDeregistered event of operatorId at blockNumber
+func (r *fakeAvsRegistry) deregister(operatorId types.OperatorId, blockNumber uint64) {
+	r.mu.Lock()
+	deregisteredC := r.deregisteredC
+	r.mu.Unlock()
+	deregisteredC <- &regcoord.ContractRegistryCoordinatorOperatorDeregistered{
+		Operator:   r.operatorAddr,
+		OperatorId: operatorId,
+		Raw:        gethtypes.Log{BlockNumber: blockNumber},
+	}
+}
+
+// failSubscription makes the current subscription fail with err
+func (r *fakeAvsRegistry) failSubscription(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subErrC <- err
+}
+
+// startEjectionMonitor starts an EjectionMonitor of the operator of avsRegistry in quorums 0 and 1, returning the
+// channel its ejections are sent to
+func startEjectionMonitor(
+	t *testing.T,
+	avsRegistry *fakeAvsRegistry,
+	operatorId types.OperatorId,
+	pollInterval time.Duration,
+) (*EjectionMonitor, <-chan Ejection) {
+	ejectionC := make(chan Ejection, 10)
+	monitor := NewEjectionMonitor(
+		avsRegistry,
+		avsRegistry,
+		operatorId,
+		types.QuorumNums{0, 1},
+		func(ctx context.Context, ejection Ejection) { ejectionC <- ejection },
+		testutils.GetTestLogger(),
+		WithPollInterval(pollInterval),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := monitor.Start(ctx); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	return monitor, ejectionC
+}
+
+func waitEjection(t *testing.T, ejectionC <-chan Ejection, wantEjection Ejection) {
+	t.Helper()
+	select {
+	case gotEjection := <-ejectionC:
+		if !reflect.DeepEqual(wantEjection, gotEjection) {
+			t.Fatalf("wrong ejection. Got: %v, want: %v.", gotEjection, wantEjection)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for ejection %v", wantEjection)
+	}
+}
+
+func requireNoEjection(t *testing.T, ejectionC <-chan Ejection) {
+	t.Helper()
+	select {
+	case ejection := <-ejectionC:
+		t.Fatalf("unexpected ejection %v", ejection)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEjectionMonitor(t *testing.T) {
+	operatorId := types.OperatorId{1}
+
+	t.Run("deregistration event", func(t *testing.T) {
+		avsRegistry := newFakeAvsRegistry(true, true)
+		// the registration is only checked on the events
+		_, ejectionC := startEjectionMonitor(t, avsRegistry, operatorId, time.Hour)
+		requireNoEjection(t, ejectionC)
+
+		avsRegistry.setRegisteredQuorums(false, false)
+		// the deregistrations of the other operators are ignored
+		avsRegistry.deregister(types.OperatorId{2}, 10)
+		requireNoEjection(t, ejectionC)
+		avsRegistry.deregister(operatorId, 11)
+		waitEjection(t, ejectionC, Ejection{
+			OperatorId:    operatorId,
+			OperatorAddr:  avsRegistry.operatorAddr,
+			QuorumNumbers: types.QuorumNums{0, 1},
+			BlockNumber:   11,
+		})
+	})
+
+	t.Run("removal from a quorum found by the periodic check", func(t *testing.T) {
+		avsRegistry := newFakeAvsRegistry(true, true, true)
+		_, ejectionC := startEjectionMonitor(t, avsRegistry, operatorId, 10*time.Millisecond)
+
+		avsRegistry.setRegisteredQuorums(true, false, true)
+		waitEjection(t, ejectionC, Ejection{
+			OperatorId:    operatorId,
+			OperatorAddr:  avsRegistry.operatorAddr,
+			QuorumNumbers: types.QuorumNums{1},
+		})
+		// the ejection is only reported once
+		requireNoEjection(t, ejectionC)
+
+		// the operator is reported again once it registers again and is removed again
+		avsRegistry.setRegisteredQuorums(true, true, true)
+		time.Sleep(50 * time.Millisecond)
+		avsRegistry.setRegisteredQuorums(false, true, true)
+		waitEjection(t, ejectionC, Ejection{
+			OperatorId:    operatorId,
+			OperatorAddr:  avsRegistry.operatorAddr,
+			QuorumNumbers: types.QuorumNums{0},
+		})
+	})
+
+	t.Run("operator already ejected when started", func(t *testing.T) {
+		avsRegistry := newFakeAvsRegistry(true)
+		_, ejectionC := startEjectionMonitor(t, avsRegistry, operatorId, time.Hour)
+		// quorum 1 doesn't exist anymore
+		waitEjection(t, ejectionC, Ejection{
+			OperatorId:    operatorId,
+			OperatorAddr:  avsRegistry.operatorAddr,
+			QuorumNumbers: types.QuorumNums{1},
+		})
+	})
+
+	t.Run("failed subscription is resubscribed and the registration checked", func(t *testing.T) {
+		avsRegistry := newFakeAvsRegistry(true, true)
+		_, ejectionC := startEjectionMonitor(t, avsRegistry, operatorId, time.Hour)
+
+		// the event is missed while the subscription is down
+		avsRegistry.setRegisteredQuorums(false, false)
+		avsRegistry.failSubscription(errors.New("connection lost"))
+		waitEjection(t, ejectionC, Ejection{
+			OperatorId:    operatorId,
+			OperatorAddr:  avsRegistry.operatorAddr,
+			QuorumNumbers: types.QuorumNums{0, 1},
+		})
+		avsRegistry.mu.Lock()
+		subscriptions := avsRegistry.subscriptions
+		avsRegistry.mu.Unlock()
+		if subscriptions != 2 {
+			t.Fatalf("wrong number of subscriptions. Got: %d, want: %d.", subscriptions, 2)
+		}
+	})
+}
+
+func TestEjectionMonitorHealth(t *testing.T) {
+	avsRegistry := newFakeAvsRegistry(true, true)
+	monitor, ejectionC := startEjectionMonitor(t, avsRegistry, types.OperatorId{1}, 10*time.Millisecond)
+
+	waitHealth := func(t *testing.T, wantHealth services.ServiceHealth) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			health, reason := monitor.Health(context.Background())
+			if health == wantHealth {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("wrong health. Got: %v (%s), want: %v.", health, reason, wantHealth)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	waitHealth(t, services.ServiceHealthy)
+	avsRegistry.setQueryErr(errors.New("rpc error"))
+	waitHealth(t, services.ServiceDegraded)
+	avsRegistry.setQueryErr(nil)
+	waitHealth(t, services.ServiceHealthy)
+	avsRegistry.setRegisteredQuorums(true, false)
+	waitHealth(t, services.ServiceUnhealthy)
+	<-ejectionC
+	avsRegistry.setRegisteredQuorums(true, true)
+	waitHealth(t, services.ServiceHealthy)
+}
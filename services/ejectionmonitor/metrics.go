@@ -0,0 +1,86 @@
+package ejectionmonitor
+
+import (
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type Metrics interface {
+	SetRegistered(quorumNumber types.QuorumNum, registered bool)
+	IncrementEjections()
+	IncrementFailedChecks()
+}
+
+const namespace = "ejectionmonitor"
+
+type PromMetrics struct {
+	registered   *prometheus.GaugeVec
+	ejections    prometheus.Counter
+	failedChecks prometheus.Counter
+}
+
+var _ Metrics = (*PromMetrics)(nil)
+
+// NewMetrics returns the metrics of an EjectionMonitor registered on reg
+func NewMetrics(reg prometheus.Registerer, subsystem string) *PromMetrics {
+	return &PromMetrics{
+		registered: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "registered",
+				Help:      "1 if the operator is registered in the monitored quorum, 0 otherwise",
+			},
+			[]string{"quorum"},
+		),
+		ejections: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "ejections_total",
+				Help:      "number of ejections of the operator from the monitored quorums",
+			},
+		),
+		failedChecks: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "failed_checks_total",
+				Help:      "number of checks of the registration of the operator which failed to query the chain",
+			},
+		),
+	}
+}
+
+func (m *PromMetrics) SetRegistered(quorumNumber types.QuorumNum, registered bool) {
+	value := 0.0
+	if registered {
+		value = 1
+	}
+	m.registered.WithLabelValues(fmt.Sprint(quorumNumber)).Set(value)
+}
+
+func (m *PromMetrics) IncrementEjections() {
+	m.ejections.Inc()
+}
+
+func (m *PromMetrics) IncrementFailedChecks() {
+	m.failedChecks.Inc()
+}
+
+type NoopMetrics struct{}
+
+var _ Metrics = (*NoopMetrics)(nil)
+
+func NewNoopMetrics() *NoopMetrics {
+	return &NoopMetrics{}
+}
+
+func (m *NoopMetrics) SetRegistered(quorumNumber types.QuorumNum, registered bool) {}
+
+func (m *NoopMetrics) IncrementEjections() {}
+
+func (m *NoopMetrics) IncrementFailedChecks() {}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// ServiceHealth is the health of a service as reported by a HealthReporter, ordered from the best to the worst
+type ServiceHealth int
+
+const (
+	ServiceHealthy ServiceHealth = iota
+	// ServiceDegraded means the service works but is impaired, e.g. it is initializing or lagging behind
+	ServiceDegraded
+	ServiceUnhealthy
+)
+
+func (h ServiceHealth) String() string {
+	switch h {
+	case ServiceHealthy:
+		return "Healthy"
+	case ServiceDegraded:
+		return "Degraded"
+	case ServiceUnhealthy:
+		return "Unhealthy"
+	default:
+		return "Unknown"
+	}
+}
+
+// HealthReporter is implemented by the services and clients reporting their health, see HealthRegistry
+type HealthReporter interface {
+	// Name identifies the service in the health reports
+	Name() string
+	// Health returns the current health of the service, and the reason it isn't healthy if so
+	Health(ctx context.Context) (ServiceHealth, string)
+}
+
+// ComponentHealth is the health reported by a HealthReporter of a HealthRegistry
+type ComponentHealth struct {
+	Name   string
+	Health ServiceHealth
+	Reason string
+}
+
+// HealthRegistry computes the health of a node from the health reported by its components
+type HealthRegistry struct {
+	reporters      []HealthReporter
+	reportersMutex sync.RWMutex
+}
+
+// NewHealthRegistry returns a HealthRegistry without any component, which reports a healthy node
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{}
+}
+
+// Register adds reporter to the components of the registry
+func (r *HealthRegistry) Register(reporter HealthReporter) {
+	r.reportersMutex.Lock()
+	defer r.reportersMutex.Unlock()
+	r.reporters = append(r.reporters, reporter)
+}
+
+// Check queries the health of every component, returning the worst of them along with the health of each component
+func (r *HealthRegistry) Check(ctx context.Context) (ServiceHealth, []ComponentHealth) {
+	r.reportersMutex.RLock()
+	reporters := append([]HealthReporter{}, r.reporters...)
+	r.reportersMutex.RUnlock()
+
+	health := ServiceHealthy
+	components := make([]ComponentHealth, 0, len(reporters))
+	for _, reporter := range reporters {
+		componentHealth, reason := reporter.Health(ctx)
+		components = append(components, ComponentHealth{
+			Name:   reporter.Name(),
+			Health: componentHealth,
+			Reason: reason,
+		})
+		health = max(health, componentHealth)
+	}
+	return health, components
+}
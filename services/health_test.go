@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// fakeHealthReporter reports the health it is set to
+type fakeHealthReporter struct {
+	name   string
+	health ServiceHealth
+	reason string
+}
+
+func (r *fakeHealthReporter) Name() string {
+	return r.name
+}
+
+func (r *fakeHealthReporter) Health(ctx context.Context) (ServiceHealth, string) {
+	return r.health, r.reason
+}
+
+func TestHealthRegistry(t *testing.T) {
+	registry := NewHealthRegistry()
+	if health, components := registry.Check(context.Background()); health != ServiceHealthy || len(components) != 0 {
+		t.Fatalf("empty registry returned wrong health. Got: %v %v, want: %v.", health, components, ServiceHealthy)
+	}
+
+	aggregator := &fakeHealthReporter{name: "aggregator"}
+	operatorsInfo := &fakeHealthReporter{name: "operatorsinfo"}
+	registry.Register(aggregator)
+	registry.Register(operatorsInfo)
+
+	var tests = []struct {
+		name                string
+		aggregatorHealth    ServiceHealth
+		operatorsInfoHealth ServiceHealth
+		wantHealth          ServiceHealth
+	}{
+		{
+			name:       "all components healthy",
+			wantHealth: ServiceHealthy,
+		},
+		{
+			name:                "one component degraded",
+			operatorsInfoHealth: ServiceDegraded,
+			wantHealth:          ServiceDegraded,
+		},
+		{
+			name:                "one component unhealthy and one degraded",
+			aggregatorHealth:    ServiceUnhealthy,
+			operatorsInfoHealth: ServiceDegraded,
+			wantHealth:          ServiceUnhealthy,
+		},
+		{
+			name:       "components recovered",
+			wantHealth: ServiceHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator.health = tt.aggregatorHealth
+			operatorsInfo.health = tt.operatorsInfoHealth
+			operatorsInfo.reason = tt.operatorsInfoHealth.String()
+
+			gotHealth, gotComponents := registry.Check(context.Background())
+			if gotHealth != tt.wantHealth {
+				t.Fatalf("Check returned wrong health. Got: %v, want: %v.", gotHealth, tt.wantHealth)
+			}
+			wantComponents := []ComponentHealth{
+				{Name: "aggregator", Health: tt.aggregatorHealth},
+				{Name: "operatorsinfo", Health: tt.operatorsInfoHealth, Reason: tt.operatorsInfoHealth.String()},
+			}
+			if !reflect.DeepEqual(wantComponents, gotComponents) {
+				t.Fatalf("Check returned wrong component health. Got: %v, want: %v.", gotComponents, wantComponents)
+			}
+		})
+	}
+}
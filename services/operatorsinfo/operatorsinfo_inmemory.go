@@ -2,47 +2,72 @@ package operatorsinfo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"math/big"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
 	blsapkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
 	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
 	"github.com/ethereum/go-ethereum/event"
 
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/errgroup"
 )
 
 var defaultLogFilterQueryBlockRange = big.NewInt(10_000)
 
+// backfillProgressLogInterval is the number of chunks of the query of past events between progress logs
+const backfillProgressLogInterval = 10
+
+const (
+	// DefaultChainLookupBlockRange is the number of blocks queried by the chain lookups of the operators missing from
+	// the db unless set with Opts.ChainLookupBlockRange
+	DefaultChainLookupBlockRange = 10_000
+	// DefaultChainLookupInterval is the minimum interval between the chain lookups of the same operator unless set with
+	// Opts.ChainLookupInterval
+	DefaultChainLookupInterval = 30 * time.Second
+)
+
 type avsRegistryReader interface {
-	QueryExistingRegisteredOperatorSockets(
+	QueryExistingRegisteredOperators(
 		ctx context.Context,
-		startBlock *big.Int,
-		stopBlock *big.Int,
-		blockRange *big.Int,
-	) (map[types.OperatorId]types.Socket, error)
+		startBlock uint64,
+		stopBlock uint64,
+		opts avsregistry.QueryOpts,
+	) (map[types.OperatorId]avsregistry.OperatorInfo, error)
 
-	QueryExistingRegisteredOperatorPubKeys(
+	QueryOperatorInfo(
 		ctx context.Context,
-		startBlock *big.Int,
-		stopBlock *big.Int,
-		blockRange *big.Int,
-	) ([]types.OperatorAddr, []types.OperatorPubkeys, error)
+		operatorAddr common.Address,
+		startBlock uint64,
+		stopBlock uint64,
+		opts avsregistry.QueryOpts,
+	) (avsregistry.OperatorInfo, bool, error)
 }
 
 type avsRegistrySubscriber interface {
 	SubscribeToNewPubkeyRegistrations() (chan *blsapkreg.ContractBLSApkRegistryNewPubkeyRegistration, event.Subscription, error)
 	SubscribeToOperatorSocketUpdates() (chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate, event.Subscription, error)
+	SubscribeToOperatorRegistrations() (<-chan *regcoord.ContractRegistryCoordinatorOperatorRegistered, event.Subscription, error)
+	SubscribeToOperatorDeregistrations() (<-chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered, event.Subscription, error)
 }
 
 // OperatorsInfoServiceInMemory is a stateful goroutine (see https://gobyexample.com/stateful-goroutines)
-// implementation of OperatorsInfoService that listen for the NewPubkeyRegistration and OperatorSocketUpdate events
-// using a websocket connection
+// implementation of OperatorsInfoService that listen for the NewPubkeyRegistration, OperatorSocketUpdate,
+// OperatorRegistered and OperatorDeregistered events using a websocket connection
 // to an eth client and stores the pubkeys/sockets in memory. Another possible implementation is using a mutex
 // (https://gobyexample.com/mutexes) instead. We can switch to that if we ever find a good reason to.
 //
@@ -60,11 +85,58 @@ type OperatorsInfoServiceInMemory struct {
 	avsRegistryReader        avsRegistryReader
 	logger                   logging.Logger
 	queryC                   chan<- query
+	// operatorInfoC receives the operators found by the chain lookups, to add them to the db
+	operatorInfoC chan<- avsregistry.OperatorInfo
+	// allOperatorsC receives the queries of GetAllOperatorsInfo
+	allOperatorsC chan<- allOperatorsQuery
 	// queried via the queryC channel, so don't need mutex to access
 	pubkeyDict       map[common.Address]types.OperatorPubkeys
 	operatorAddrToId map[common.Address]types.OperatorId
 	socketDict       map[types.OperatorId]types.Socket
+	// operatorStateDict is the registration state of the operators and the blocks of their most recent events applied
+	// to the db, used to ignore the stale events
+	operatorStateDict map[types.OperatorId]operatorState
+	// snapshotStore is nil if the operators aren't checkpointed
+	snapshotStore OperatorsSnapshotStore
+	// lastBlock is the last block whose events were processed, as of the last snapshot
+	lastBlock uint64
+	// lastProcessedBlock is the most recent block whose events were processed, see GetAllOperatorsInfo
+	lastProcessedBlock uint64
+
+	opts Opts
+	// backfilled is set once the past events are queried, and subscriptionsDown counts the subscriptions being
+	// reopened after an error, both reported by Health
+	backfilled        atomic.Bool
+	subscriptionsDown atomic.Int32
+	// chainLookupTimes are the times of the last chain lookups of the operators, which are rate limited
+	chainLookupTimes map[common.Address]time.Time
+	chainLookupMutex sync.Mutex
+}
+
+// operatorState is the registration state of an operator, along with the blocks of the most recent events of each kind
+// applied to the db. The events older than these blocks are stale, e.g. received by a subscription once the past events
+// including them were queried, and are ignored. The blocks are tracked per kind of event since each kind is received
+// by its own subscription, without ordering between the subscriptions.
+type operatorState struct {
+	// registered is whether the operator is registered with the RegistryCoordinator
+	registered bool
+	// lastUpdateBlock is the block of the most recent registration or deregistration of the operator
+	lastUpdateBlock uint64
+	pubkeyBlock     uint64
+	socketBlock     uint64
+}
+
+// allOperatorsQuery queries a copy of the db, see GetAllOperatorsInfo
+type allOperatorsQuery struct {
+	// channel through which to receive the resp, buffered so that the service doesn't wait for the caller
+	respC chan<- allOperatorsResp
 }
+
+type allOperatorsResp struct {
+	operators          map[common.Address]types.OperatorInfo
+	lastProcessedBlock uint64
+}
+
 type query struct {
 	operatorAddr common.Address
 	// channel through which to receive the resp
@@ -76,14 +148,51 @@ type resp struct {
 	operatorInfo types.OperatorInfo
 	// false if operators were not present in the pubkey dict
 	operatorExists bool
+	// false if the operator isn't registered with the RegistryCoordinator, or its registration isn't known
+	operatorRegistered bool
 }
 
 type Opts struct {
+	// StartBlock is the first block whose events are queried to fill the db, 0 if nil. The operators which registered
+	// their pubkeys before StartBlock are missing from the db, so it must be at most the deployment block of the AVS
+	// registry contracts, or the block of their first pubkey registration.
 	StartBlock *big.Int
-	StopBlock  *big.Int
+	// StopBlock is the last block whose events are queried to fill the db, the current block if nil
+	StopBlock *big.Int
+	// BlockChunkSize is the number of blocks of each chunk of the query of past events, queried with a single log
+	// filter query. The logFilterQueryBlockRange of the service if 0. Public RPCs usually reject the queries of more
+	// than a few thousand blocks.
+	BlockChunkSize uint64
+	// MaxConcurrentChunks is the number of chunks of the query of past events queried concurrently, 1 if 0. The
+	// blocks are queried in a single call, chunked by the reader, unless StopBlock or CurrentBlockNumber is set.
+	MaxConcurrentChunks int
+	// SnapshotStore checkpoints the operators of the service and the last block whose events it processed, so that
+	// on restart it loads its snapshot and only queries the events of the blocks after it. The service queries the
+	// events from StartBlock instead if the snapshot can't be loaded, e.g. if it's corrupted or of another version.
+	// No snapshot is saved if nil.
+	SnapshotStore OperatorsSnapshotStore
+	// CurrentBlockNumber returns the number of the current block, e.g. the BlockNumber method of an eth client. It's
+	// required with SnapshotStore unless StopBlock is set, the service checkpointing the block its past events are
+	// queried up to.
+	CurrentBlockNumber func(ctx context.Context) (uint64, error)
+	// DisableChainLookup disables the chain lookups of the operators missing from the db, e.g. which registered after
+	// the past events were queried and whose events weren't received yet. GetOperatorInfo reports them as not found
+	// instead.
+	DisableChainLookup bool
+	// ChainLookupBlockRange is the number of blocks up to the current one whose events are queried by the chain
+	// lookups, DefaultChainLookupBlockRange if 0. All the blocks are queried if CurrentBlockNumber isn't set.
+	ChainLookupBlockRange uint64
+	// ChainLookupInterval is the minimum interval between the chain lookups of the same operator, so that the
+	// operators which aren't registered don't trigger a lookup per query, DefaultChainLookupInterval if 0
+	ChainLookupInterval time.Duration
+	// FilterUnregisteredOperators makes GetOperatorInfo report the operators which aren't registered with the
+	// RegistryCoordinator as not found, e.g. the ones which deregistered. The operators are served regardless of their
+	// registration otherwise.
+	FilterUnregisteredOperators bool
 }
 
 var _ OperatorsInfoService = (*OperatorsInfoServiceInMemory)(nil)
+var _ services.HealthReporter = (*OperatorsInfoServiceInMemory)(nil)
 
 // NewOperatorsInfoServiceInMemory constructs a OperatorsInfoServiceInMemory and starts it in a goroutine.
 // It takes a context as argument because the "backfilling" of the database is done inside this constructor,
@@ -101,24 +210,38 @@ func NewOperatorsInfoServiceInMemory(
 	logger logging.Logger,
 ) *OperatorsInfoServiceInMemory {
 	queryC := make(chan query)
+	operatorInfoC := make(chan avsregistry.OperatorInfo)
+	allOperatorsC := make(chan allOperatorsQuery)
 	if logFilterQueryBlockRange == nil {
 		logFilterQueryBlockRange = defaultLogFilterQueryBlockRange
 	}
+	if opts.ChainLookupBlockRange == 0 {
+		opts.ChainLookupBlockRange = DefaultChainLookupBlockRange
+	}
+	if opts.ChainLookupInterval == 0 {
+		opts.ChainLookupInterval = DefaultChainLookupInterval
+	}
 	pkcs := &OperatorsInfoServiceInMemory{
 		avsRegistrySubscriber:    avsRegistrySubscriber,
 		avsRegistryReader:        avsRegistryReader,
 		logFilterQueryBlockRange: logFilterQueryBlockRange,
 		logger:                   logger,
 		queryC:                   queryC,
+		operatorInfoC:            operatorInfoC,
+		allOperatorsC:            allOperatorsC,
 		pubkeyDict:               make(map[common.Address]types.OperatorPubkeys),
 		operatorAddrToId:         make(map[common.Address]types.OperatorId),
 		socketDict:               make(map[types.OperatorId]types.Socket),
+		operatorStateDict:        make(map[types.OperatorId]operatorState),
+		snapshotStore:            opts.SnapshotStore,
+		opts:                     opts,
+		chainLookupTimes:         make(map[common.Address]time.Time),
 	}
 	// We use this waitgroup to wait on the initialization of the inmemory pubkey dict,
 	// which requires querying the past events of the pubkey registration contract
 	wg := sync.WaitGroup{}
 	wg.Add(1)
-	pkcs.startServiceInGoroutine(ctx, queryC, &wg, opts)
+	pkcs.startServiceInGoroutine(ctx, queryC, operatorInfoC, allOperatorsC, &wg, opts)
 	wg.Wait()
 	return pkcs
 }
@@ -126,6 +249,8 @@ func NewOperatorsInfoServiceInMemory(
 func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 	ctx context.Context,
 	queryC <-chan query,
+	operatorInfoC <-chan avsregistry.OperatorInfo,
+	allOperatorsC <-chan allOperatorsQuery,
 	wg *sync.WaitGroup,
 	opts Opts,
 ) {
@@ -160,6 +285,28 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 			)
 			panic(err)
 		}
+		operatorRegisteredC, operatorRegisteredSub, err := ops.avsRegistrySubscriber.SubscribeToOperatorRegistrations()
+		if err != nil {
+			ops.logger.Error(
+				"Fatal error opening websocket subscription for operator registrations",
+				"err",
+				err,
+				"service",
+				"OperatorPubkeysServiceInMemory",
+			)
+			panic(err)
+		}
+		operatorDeregisteredC, operatorDeregisteredSub, err := ops.avsRegistrySubscriber.SubscribeToOperatorDeregistrations()
+		if err != nil {
+			ops.logger.Error(
+				"Fatal error opening websocket subscription for operator deregistrations",
+				"err",
+				err,
+				"service",
+				"OperatorPubkeysServiceInMemory",
+			)
+			panic(err)
+		}
 		err = ops.queryPastRegisteredOperatorEventsAndFillDb(ctx, opts)
 		if err != nil {
 			ops.logger.Error(
@@ -171,6 +318,7 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 			)
 			panic(err)
 		}
+		ops.backfilled.Store(true)
 		// The constructor can return after we have backfilled the db by querying the events of operators that have
 		// registered with the blsApkRegistry
 		// before the block at which we started the ws subscription above
@@ -191,6 +339,7 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 					"OperatorPubkeysServiceInMemory",
 				)
 				newPubkeyRegistrationSub.Unsubscribe()
+				ops.subscriptionsDown.Add(1)
 				newPubkeyRegistrationC, newPubkeyRegistrationSub, err = ops.avsRegistrySubscriber.SubscribeToNewPubkeyRegistrations()
 				if err != nil {
 					ops.logger.Error(
@@ -203,6 +352,7 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 					// see the warning above the struct definition to understand why we panic here
 					panic(err)
 				}
+				ops.subscriptionsDown.Add(-1)
 			case err := <-newSocketRegistrationSub.Err():
 				ops.logger.Error(
 					"Error in websocket subscription for new socket registration events. Attempting to reconnect...",
@@ -212,6 +362,7 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 					"OperatorPubkeysServiceInMemory",
 				)
 				newSocketRegistrationSub.Unsubscribe()
+				ops.subscriptionsDown.Add(1)
 				newSocketRegistrationC, newSocketRegistrationSub, err = ops.avsRegistrySubscriber.SubscribeToOperatorSocketUpdates()
 				if err != nil {
 					ops.logger.Error(
@@ -223,9 +374,56 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 					)
 					panic(err)
 				}
+				ops.subscriptionsDown.Add(-1)
+			case err := <-operatorRegisteredSub.Err():
+				ops.logger.Error(
+					"Error in websocket subscription for operator registration events. Attempting to reconnect...",
+					"err",
+					err,
+					"service",
+					"OperatorPubkeysServiceInMemory",
+				)
+				operatorRegisteredSub.Unsubscribe()
+				ops.subscriptionsDown.Add(1)
+				operatorRegisteredC, operatorRegisteredSub, err = ops.avsRegistrySubscriber.SubscribeToOperatorRegistrations()
+				if err != nil {
+					ops.logger.Error(
+						"Error opening websocket subscription for operator registrations",
+						"err",
+						err,
+						"service",
+						"OperatorPubkeysServiceInMemory",
+					)
+					panic(err)
+				}
+				ops.subscriptionsDown.Add(-1)
+			case err := <-operatorDeregisteredSub.Err():
+				ops.logger.Error(
+					"Error in websocket subscription for operator deregistration events. Attempting to reconnect...",
+					"err",
+					err,
+					"service",
+					"OperatorPubkeysServiceInMemory",
+				)
+				operatorDeregisteredSub.Unsubscribe()
+				ops.subscriptionsDown.Add(1)
+				operatorDeregisteredC, operatorDeregisteredSub, err = ops.avsRegistrySubscriber.SubscribeToOperatorDeregistrations()
+				if err != nil {
+					ops.logger.Error(
+						"Error opening websocket subscription for operator deregistrations",
+						"err",
+						err,
+						"service",
+						"OperatorPubkeysServiceInMemory",
+					)
+					panic(err)
+				}
+				ops.subscriptionsDown.Add(-1)
 			case newPubkeyRegistrationEvent := <-newPubkeyRegistrationC:
+				ops.processBlock(newPubkeyRegistrationEvent.Raw.BlockNumber)
 				operatorAddr := newPubkeyRegistrationEvent.Operator
-				ops.pubkeyDict[operatorAddr] = types.OperatorPubkeys{
+				operatorId := types.OperatorIdFromContractG1Pubkey(newPubkeyRegistrationEvent.PubkeyG1)
+				pubkeys := types.OperatorPubkeys{
 					G1Pubkey: bls.NewG1Point(
 						newPubkeyRegistrationEvent.PubkeyG1.X,
 						newPubkeyRegistrationEvent.PubkeyG1.Y,
@@ -235,9 +433,17 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 						newPubkeyRegistrationEvent.PubkeyG2.Y,
 					),
 				}
-
-				operatorId := types.OperatorIdFromContractG1Pubkey(newPubkeyRegistrationEvent.PubkeyG1)
-				ops.operatorAddrToId[operatorAddr] = operatorId
+				blockNumber := newPubkeyRegistrationEvent.Raw.BlockNumber
+				if !ops.setOperatorPubkeys(operatorAddr, operatorId, pubkeys, blockNumber) {
+					ops.logger.Debug(
+						"Ignoring stale new pubkey registration event",
+						"service", "OperatorPubkeysServiceInMemory",
+						"block", newPubkeyRegistrationEvent.Raw.BlockNumber,
+						"operatorAddr", operatorAddr,
+						"operatorId", operatorId,
+					)
+					continue
+				}
 				ops.logger.Debug(
 					"Added operator pubkeys to pubkey dict from new pubkey registration event",
 					"service",
@@ -253,7 +459,9 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 					"G2pubkey",
 					ops.pubkeyDict[operatorAddr].G2Pubkey,
 				)
+				ops.saveSnapshotAtEvent(newPubkeyRegistrationEvent.Raw.BlockNumber)
 			case newSocketRegistrationEvent := <-newSocketRegistrationC:
+				ops.processBlock(newSocketRegistrationEvent.Raw.BlockNumber)
 				ops.logger.Debug(
 					"Received new socket registration event",
 					"service",
@@ -263,10 +471,66 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 					"socket",
 					newSocketRegistrationEvent.Socket,
 				)
-				ops.updateSocketMapping(
+				if !ops.setOperatorSocket(
 					newSocketRegistrationEvent.OperatorId,
 					types.Socket(newSocketRegistrationEvent.Socket),
+					newSocketRegistrationEvent.Raw.BlockNumber,
+				) {
+					ops.logger.Debug(
+						"Ignoring stale socket registration event",
+						"service", "OperatorPubkeysServiceInMemory",
+						"block", newSocketRegistrationEvent.Raw.BlockNumber,
+						"operatorId", types.OperatorId(newSocketRegistrationEvent.OperatorId),
+					)
+					continue
+				}
+				ops.saveSnapshotAtEvent(newSocketRegistrationEvent.Raw.BlockNumber)
+			case operatorRegisteredEvent := <-operatorRegisteredC:
+				ops.processBlock(operatorRegisteredEvent.Raw.BlockNumber)
+				ops.logger.Debug(
+					"Received operator registration event",
+					"service", "OperatorPubkeysServiceInMemory",
+					"block", operatorRegisteredEvent.Raw.BlockNumber,
+					"operatorAddr", operatorRegisteredEvent.Operator,
+					"operatorId", types.OperatorId(operatorRegisteredEvent.OperatorId),
 				)
+				if !ops.setOperatorRegistered(
+					operatorRegisteredEvent.OperatorId,
+					true,
+					operatorRegisteredEvent.Raw.BlockNumber,
+				) {
+					ops.logger.Debug(
+						"Ignoring stale operator registration event",
+						"service", "OperatorPubkeysServiceInMemory",
+						"block", operatorRegisteredEvent.Raw.BlockNumber,
+						"operatorId", types.OperatorId(operatorRegisteredEvent.OperatorId),
+					)
+					continue
+				}
+				ops.saveSnapshotAtEvent(operatorRegisteredEvent.Raw.BlockNumber)
+			case operatorDeregisteredEvent := <-operatorDeregisteredC:
+				ops.processBlock(operatorDeregisteredEvent.Raw.BlockNumber)
+				ops.logger.Debug(
+					"Received operator deregistration event",
+					"service", "OperatorPubkeysServiceInMemory",
+					"block", operatorDeregisteredEvent.Raw.BlockNumber,
+					"operatorAddr", operatorDeregisteredEvent.Operator,
+					"operatorId", types.OperatorId(operatorDeregisteredEvent.OperatorId),
+				)
+				if !ops.setOperatorRegistered(
+					operatorDeregisteredEvent.OperatorId,
+					false,
+					operatorDeregisteredEvent.Raw.BlockNumber,
+				) {
+					ops.logger.Debug(
+						"Ignoring stale operator deregistration event",
+						"service", "OperatorPubkeysServiceInMemory",
+						"block", operatorDeregisteredEvent.Raw.BlockNumber,
+						"operatorId", types.OperatorId(operatorDeregisteredEvent.OperatorId),
+					)
+					continue
+				}
+				ops.saveSnapshotAtEvent(operatorDeregisteredEvent.Raw.BlockNumber)
 			// Receive a query from GetOperatorPubkeys
 			case query := <-queryC:
 				pubkeys, ok := ops.pubkeyDict[query.operatorAddr]
@@ -276,7 +540,26 @@ func (ops *OperatorsInfoServiceInMemory) startServiceInGoroutine(
 					Socket:  socket,
 					Pubkeys: pubkeys,
 				}
-				query.respC <- resp{operatorInfo, ok}
+				query.respC <- resp{operatorInfo, ok, ops.operatorStateDict[operatorId].registered}
+			// Receive a query from GetAllOperatorsInfo, the copy being serialized by the caller
+			case query := <-allOperatorsC:
+				query.respC <- allOperatorsResp{ops.copyAllOperatorsInfo(), ops.lastProcessedBlock}
+			// Receive an operator found by a chain lookup of GetOperatorInfo
+			case operator := <-operatorInfoC:
+				operatorId := types.OperatorIdFromG1Pubkey(operator.Pubkeys.G1Pubkey)
+				ops.setOperatorInfo(operatorId, operator)
+				ops.logger.Debug(
+					"Added operator found by chain lookup to pubkey dict",
+					"service",
+					"OperatorPubkeysServiceInMemory",
+					"operatorAddr",
+					operator.OperatorAddr,
+					"operatorId",
+					operatorId,
+				)
+				if ops.snapshotStore != nil {
+					ops.saveSnapshot()
+				}
 			}
 		}
 	}()
@@ -286,81 +569,281 @@ func (ops *OperatorsInfoServiceInMemory) queryPastRegisteredOperatorEventsAndFil
 	ctx context.Context,
 	opts Opts,
 ) error {
-	// Querying with nil startBlock and stopBlock will return all events. It doesn't matter if we query some events that
-	// we will receive again in the websocket,
+	// Querying with zero startBlock and stopBlock will return all events. It doesn't matter if we query some events
+	// that we will receive again in the websocket,
 	// since we will just overwrite the pubkey dict with the same values.
-	wg := sync.WaitGroup{}
-	var alreadyRegisteredOperatorAddrs []common.Address
-	var alreadyRegisteredOperatorPubkeys []types.OperatorPubkeys
-	var pubkeysErr error
-
-	// we make both Queries in parallel because they take time and we don't want to wait for one to finish before
-	// starting the other
-	wg.Add(2)
-	go func() {
-		alreadyRegisteredOperatorAddrs, alreadyRegisteredOperatorPubkeys, pubkeysErr = ops.avsRegistryReader.QueryExistingRegisteredOperatorPubKeys(
-			ctx,
-			opts.StartBlock,
-			opts.StopBlock,
-			ops.logFilterQueryBlockRange,
-		)
-		wg.Done()
-	}()
-	var socketsMap map[types.OperatorId]types.Socket
-	var socketsErr error
-	go func() {
-		socketsMap, socketsErr = ops.avsRegistryReader.QueryExistingRegisteredOperatorSockets(
-			ctx,
-			opts.StartBlock,
-			opts.StopBlock,
-			ops.logFilterQueryBlockRange,
-		)
-		wg.Done()
-	}()
+	var startBlock, stopBlock uint64
+	if opts.StartBlock != nil {
+		startBlock = opts.StartBlock.Uint64()
+	}
+	if opts.StopBlock != nil {
+		stopBlock = opts.StopBlock.Uint64()
+	}
+	if ops.snapshotStore != nil {
+		if stopBlock == 0 {
+			if opts.CurrentBlockNumber == nil {
+				return errors.New("CurrentBlockNumber is required with SnapshotStore unless StopBlock is set")
+			}
+			currentBlock, err := opts.CurrentBlockNumber(ctx)
+			if err != nil {
+				return utils.WrapError("error getting current block number", err)
+			}
+			stopBlock = currentBlock
+		}
+		if ops.loadSnapshot() && ops.lastBlock+1 > startBlock {
+			startBlock = ops.lastBlock + 1
+		}
+		if startBlock > stopBlock {
+			ops.logger.Info(
+				"Operators snapshot is up to date, skipping the query of past events",
+				"lastBlock", ops.lastBlock,
+				"service", "OperatorPubkeysServiceInMemory",
+			)
+			return nil
+		}
+	}
+	if stopBlock == 0 && opts.CurrentBlockNumber != nil {
+		currentBlock, err := opts.CurrentBlockNumber(ctx)
+		if err != nil {
+			return utils.WrapError("error getting current block number", err)
+		}
+		stopBlock = currentBlock
+	}
+	operators, err := ops.queryExistingRegisteredOperators(ctx, startBlock, stopBlock, opts)
+	if err != nil {
+		return utils.WrapError(errors.New("error querying existing registered operators"), err)
+	}
 
-	wg.Wait()
-	if pubkeysErr != nil {
-		return utils.WrapError(errors.New("error querying existing registered operators"), pubkeysErr)
-	}
-	if socketsErr != nil {
-		return utils.WrapError(errors.New("error querying existing registered operator sockets"), socketsErr)
-	}
-	ops.logger.Debug(
-		"List of queried operator registration events in blsApkRegistry",
-		"alreadyRegisteredOperatorAddr",
-		alreadyRegisteredOperatorAddrs,
-		"alreadyRegisteredOperatorPubkeys",
-		alreadyRegisteredOperatorPubkeys,
-		"service",
-		"OperatorPubkeysServiceInMemory",
-	)
-	for operatorId, socket := range socketsMap {
-		// we print each socket info on a separate line because slog for some reason doesn't pass map keys via their
+	// Fill the pubkeydict db with the operators and pubkeys found
+	for operatorId, operator := range operators {
+		// we print each operator info on a separate line because slog for some reason doesn't pass map keys via their
 		// LogValue() function, so operatorId (of custom type Bytes32) prints as a byte array instead of its hex
 		// representation from LogValue()
 		// passing the Bytes32 directly to a slog log statements does call LogValue() and prints the hex representation
 		ops.logger.Debug(
-			"operator socket returned from registration events query",
+			"operator returned from registration events query",
 			"operatorId",
 			operatorId,
+			"operatorAddr",
+			operator.OperatorAddr,
 			"socket",
-			socket,
+			operator.Socket,
+			"isRegistered",
+			operator.IsRegistered,
 			"service",
 			"OperatorPubkeysServiceInMemory",
 		)
+		ops.setOperatorInfo(operatorId, operator)
+		ops.processBlock(operator.LastUpdateBlock)
 	}
-
-	// Fill the pubkeydict db with the operators and pubkeys found
-	for i, operatorAddr := range alreadyRegisteredOperatorAddrs {
-		operatorPubkeys := alreadyRegisteredOperatorPubkeys[i]
-		ops.pubkeyDict[operatorAddr] = operatorPubkeys
-		operatorId := types.OperatorIdFromG1Pubkey(operatorPubkeys.G1Pubkey)
-		ops.operatorAddrToId[operatorAddr] = operatorId
-		ops.updateSocketMapping(operatorId, socketsMap[operatorId])
+	ops.processBlock(stopBlock)
+	if ops.snapshotStore != nil {
+		ops.lastBlock = stopBlock
+		ops.saveSnapshot()
 	}
 	return nil
 }
 
+// queryExistingRegisteredOperators queries the operators of the events between startBlock and stopBlock in chunks of
+// opts.BlockChunkSize blocks, up to opts.MaxConcurrentChunks at a time, and merges the chunks in chain order. The
+// blocks are queried in a single call if stopBlock is 0, the current block not being known.
+func (ops *OperatorsInfoServiceInMemory) queryExistingRegisteredOperators(
+	ctx context.Context,
+	startBlock uint64,
+	stopBlock uint64,
+	opts Opts,
+) (map[types.OperatorId]avsregistry.OperatorInfo, error) {
+	chunkSize := opts.BlockChunkSize
+	if chunkSize == 0 {
+		chunkSize = ops.logFilterQueryBlockRange.Uint64()
+	}
+	queryOpts := avsregistry.QueryOpts{BlockRange: chunkSize}
+	if stopBlock == 0 {
+		return ops.avsRegistryReader.QueryExistingRegisteredOperators(ctx, startBlock, stopBlock, queryOpts)
+	}
+	if startBlock > stopBlock {
+		return map[types.OperatorId]avsregistry.OperatorInfo{}, nil
+	}
+
+	numChunks := (stopBlock-startBlock)/chunkSize + 1
+	chunks := make([]map[types.OperatorId]avsregistry.OperatorInfo, numChunks)
+	var queriedChunks atomic.Uint64
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(max(opts.MaxConcurrentChunks, 1))
+	var numStartedChunks uint64
+	err := chainioutils.ForEachBlockChunk(startBlock, stopBlock, chunkSize, func(fromBlock, toBlock uint64) error {
+		i := numStartedChunks
+		numStartedChunks++
+		group.Go(func() error {
+			operators, err := ops.avsRegistryReader.QueryExistingRegisteredOperators(
+				groupCtx,
+				fromBlock,
+				toBlock,
+				queryOpts,
+			)
+			if err != nil {
+				return utils.WrapError(
+					fmt.Sprintf("error querying the events of blocks %d to %d", fromBlock, toBlock),
+					err,
+				)
+			}
+			chunks[i] = operators
+			queried := queriedChunks.Add(1)
+			if queried%backfillProgressLogInterval == 0 || queried == numChunks {
+				ops.logger.Info(
+					"Querying past operator events",
+					"queriedChunks", queried,
+					"chunks", numChunks,
+					"startBlock", startBlock,
+					"stopBlock", stopBlock,
+					"service", "OperatorPubkeysServiceInMemory",
+				)
+			}
+			return nil
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	operators := make(map[types.OperatorId]avsregistry.OperatorInfo)
+	for _, chunk := range chunks {
+		for operatorId, chunkOperator := range chunk {
+			operators[operatorId] = mergeOperatorInfo(operators[operatorId], chunkOperator)
+		}
+	}
+	return operators, nil
+}
+
+// mergeOperatorInfo returns operator updated with the fields set by the events of a later chunk. IsRegistered is only
+// merged if the later chunk has registration events of the operator, since a chunk without them reports it as not
+// registered.
+func mergeOperatorInfo(operator avsregistry.OperatorInfo, later avsregistry.OperatorInfo) avsregistry.OperatorInfo {
+	if later.OperatorAddr != (common.Address{}) {
+		operator.OperatorAddr = later.OperatorAddr
+	}
+	if later.Pubkeys.G1Pubkey != nil {
+		operator.Pubkeys = later.Pubkeys
+	}
+	if later.Socket != "" {
+		operator.Socket = later.Socket
+	}
+	if later.LastRegistrationUpdateBlock != 0 {
+		operator.IsRegistered = later.IsRegistered
+		operator.LastRegistrationUpdateBlock = later.LastRegistrationUpdateBlock
+	}
+	operator.LastUpdateBlock = later.LastUpdateBlock
+	return operator
+}
+
+// loadSnapshot fills the db with the snapshot of the snapshot store, returning whether it was loaded. The snapshots
+// which can't be loaded or are of another version are discarded with a warning, the db being rebuilt from the events.
+func (ops *OperatorsInfoServiceInMemory) loadSnapshot() bool {
+	snapshot, err := ops.snapshotStore.Load()
+	if err != nil {
+		if errors.Is(err, ErrOperatorsSnapshotNotFound) {
+			ops.logger.Info("No operators snapshot found", "service", "OperatorPubkeysServiceInMemory")
+		} else {
+			ops.logger.Warn(
+				"Failed to load operators snapshot, rebuilding the operators from the past events",
+				"err", err,
+				"service", "OperatorPubkeysServiceInMemory",
+			)
+		}
+		return false
+	}
+	if snapshot.Version != OperatorsSnapshotVersion {
+		ops.logger.Warn(
+			"Operators snapshot version mismatch, rebuilding the operators from the past events",
+			"version", snapshot.Version,
+			"expectedVersion", OperatorsSnapshotVersion,
+			"service", "OperatorPubkeysServiceInMemory",
+		)
+		return false
+	}
+	for _, operator := range snapshot.Operators {
+		if operator.Pubkeys.G1Pubkey == nil || operator.Pubkeys.G2Pubkey == nil {
+			ops.logger.Warn(
+				"Operators snapshot has an operator without pubkeys, rebuilding the operators from the past events",
+				"operatorAddr", operator.OperatorAddr,
+				"service", "OperatorPubkeysServiceInMemory",
+			)
+			clear(ops.pubkeyDict)
+			clear(ops.operatorAddrToId)
+			clear(ops.operatorStateDict)
+			return false
+		}
+		ops.setOperatorPubkeys(operator.OperatorAddr, operator.OperatorId, operator.Pubkeys, snapshot.LastBlock)
+	}
+	for _, socket := range snapshot.Sockets {
+		ops.setOperatorSocket(socket.OperatorId, socket.Socket, snapshot.LastBlock)
+	}
+	for _, registration := range snapshot.Registrations {
+		ops.setOperatorRegistered(registration.OperatorId, registration.Registered, registration.LastUpdateBlock)
+	}
+	ops.lastBlock = snapshot.LastBlock
+	ops.processBlock(snapshot.LastBlock)
+	ops.logger.Info(
+		"Loaded operators snapshot",
+		"lastBlock", snapshot.LastBlock,
+		"operators", len(snapshot.Operators),
+		"service", "OperatorPubkeysServiceInMemory",
+	)
+	return true
+}
+
+// saveSnapshotAtEvent checkpoints the db once an event of blockNumber was processed. The other events of blockNumber
+// may not be processed yet, so the snapshot is the one of the previous block, whose events are all processed.
+func (ops *OperatorsInfoServiceInMemory) saveSnapshotAtEvent(blockNumber uint64) {
+	if ops.snapshotStore == nil {
+		return
+	}
+	if blockNumber > ops.lastBlock+1 {
+		ops.lastBlock = blockNumber - 1
+	}
+	ops.saveSnapshot()
+}
+
+// saveSnapshot saves the db to the snapshot store, logging the failures since the snapshot is only an optimization
+func (ops *OperatorsInfoServiceInMemory) saveSnapshot() {
+	snapshot := OperatorsSnapshot{
+		Version:   OperatorsSnapshotVersion,
+		LastBlock: ops.lastBlock,
+		Operators: make([]SnapshotOperator, 0, len(ops.pubkeyDict)),
+		Sockets:   make([]SnapshotSocket, 0, len(ops.socketDict)),
+	}
+	for operatorAddr, pubkeys := range ops.pubkeyDict {
+		snapshot.Operators = append(snapshot.Operators, SnapshotOperator{
+			OperatorAddr: operatorAddr,
+			OperatorId:   ops.operatorAddrToId[operatorAddr],
+			Pubkeys:      pubkeys,
+		})
+	}
+	for operatorId, socket := range ops.socketDict {
+		snapshot.Sockets = append(snapshot.Sockets, SnapshotSocket{OperatorId: operatorId, Socket: socket})
+	}
+	for operatorId, state := range ops.operatorStateDict {
+		if state.registered || state.lastUpdateBlock != 0 {
+			snapshot.Registrations = append(snapshot.Registrations, SnapshotRegistration{
+				OperatorId:      operatorId,
+				Registered:      state.registered,
+				LastUpdateBlock: state.lastUpdateBlock,
+			})
+		}
+	}
+	if err := ops.snapshotStore.Save(snapshot); err != nil {
+		ops.logger.Error(
+			"Failed to save operators snapshot",
+			"err", err,
+			"service", "OperatorPubkeysServiceInMemory",
+		)
+	}
+}
+
 // TODO(samlaf): we might want to also add an async version of this method that returns a channel of operator pubkeys?
 func (ops *OperatorsInfoServiceInMemory) GetOperatorInfo(
 	ctx context.Context,
@@ -372,8 +855,243 @@ func (ops *OperatorsInfoServiceInMemory) GetOperatorInfo(
 	case <-ctx.Done():
 		return types.OperatorInfo{}, false
 	case resp := <-respC:
-		return resp.operatorInfo, resp.operatorExists
+		if resp.operatorExists && !resp.operatorRegistered && ops.opts.FilterUnregisteredOperators {
+			return types.OperatorInfo{}, false
+		}
+		if resp.operatorExists || ops.opts.DisableChainLookup {
+			return resp.operatorInfo, resp.operatorExists
+		}
+	}
+	return ops.lookupOperatorInfo(ctx, operator)
+}
+
+// GetAllOperatorsInfo returns a copy of the info of all the operators known by the service, including the
+// unregistered ones, along with the most recent block whose events were processed. It's meant for debugging, e.g. to
+// dump what the service knows when an aggregation fails, see WriteJSON.
+func (ops *OperatorsInfoServiceInMemory) GetAllOperatorsInfo(
+	ctx context.Context,
+) (map[common.Address]types.OperatorInfo, uint64, error) {
+	respC := make(chan allOperatorsResp, 1)
+	select {
+	case ops.allOperatorsC <- allOperatorsQuery{respC}:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	select {
+	case resp := <-respC:
+		return resp.operators, resp.lastProcessedBlock, nil
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+// operatorsInfoJSON is the JSON encoding of the operators written by WriteJSON
+type operatorsInfoJSON struct {
+	LastProcessedBlock uint64                                `json:"lastProcessedBlock"`
+	Operators          map[common.Address]types.OperatorInfo `json:"operators"`
+}
+
+// WriteJSON writes the operators returned by GetAllOperatorsInfo to w as JSON
+func (ops *OperatorsInfoServiceInMemory) WriteJSON(ctx context.Context, w io.Writer) error {
+	operators, lastProcessedBlock, err := ops.GetAllOperatorsInfo(ctx)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(operatorsInfoJSON{
+		LastProcessedBlock: lastProcessedBlock,
+		Operators:          operators,
+	})
+}
+
+// copyAllOperatorsInfo returns a deep copy of the operators of the db
+func (ops *OperatorsInfoServiceInMemory) copyAllOperatorsInfo() map[common.Address]types.OperatorInfo {
+	operators := make(map[common.Address]types.OperatorInfo, len(ops.pubkeyDict))
+	for operatorAddr, pubkeys := range ops.pubkeyDict {
+		operators[operatorAddr] = types.OperatorInfo{
+			Socket: ops.socketDict[ops.operatorAddrToId[operatorAddr]],
+			Pubkeys: types.OperatorPubkeys{
+				G1Pubkey: &bls.G1Point{G1Affine: new(bn254.G1Affine).Set(pubkeys.G1Pubkey.G1Affine)},
+				G2Pubkey: &bls.G2Point{G2Affine: new(bn254.G2Affine).Set(pubkeys.G2Pubkey.G2Affine)},
+			},
+		}
+	}
+	return operators
+}
+
+// processBlock records that the events of blockNumber were processed
+func (ops *OperatorsInfoServiceInMemory) processBlock(blockNumber uint64) {
+	ops.lastProcessedBlock = max(ops.lastProcessedBlock, blockNumber)
+}
+
+// lookupOperatorInfo queries the info of the operator missing from the db from the chain, adding it to the db if found.
+// The lookups of each operator are rate limited, the operators being reported as not found meanwhile.
+func (ops *OperatorsInfoServiceInMemory) lookupOperatorInfo(
+	ctx context.Context,
+	operator common.Address,
+) (types.OperatorInfo, bool) {
+	if !ops.allowChainLookup(operator) {
+		return types.OperatorInfo{}, false
+	}
+	var startBlock, stopBlock uint64
+	if ops.opts.CurrentBlockNumber != nil {
+		currentBlock, err := ops.opts.CurrentBlockNumber(ctx)
+		if err != nil {
+			ops.logger.Warn(
+				"Failed to get current block number for operator chain lookup",
+				"operatorAddr", operator,
+				"err", err,
+				"service", "OperatorPubkeysServiceInMemory",
+			)
+			return types.OperatorInfo{}, false
+		}
+		stopBlock = currentBlock
+		if currentBlock >= ops.opts.ChainLookupBlockRange {
+			startBlock = currentBlock - ops.opts.ChainLookupBlockRange + 1
+		}
+	}
+	operatorInfo, found, err := ops.avsRegistryReader.QueryOperatorInfo(
+		ctx,
+		operator,
+		startBlock,
+		stopBlock,
+		avsregistry.QueryOpts{BlockRange: ops.logFilterQueryBlockRange.Uint64()},
+	)
+	if err != nil {
+		ops.logger.Warn(
+			"Failed to look up operator on chain",
+			"operatorAddr", operator,
+			"err", err,
+			"service", "OperatorPubkeysServiceInMemory",
+		)
+		return types.OperatorInfo{}, false
+	}
+	if !found {
+		ops.logger.Debug(
+			"Operator not found by chain lookup",
+			"operatorAddr", operator,
+			"service", "OperatorPubkeysServiceInMemory",
+		)
+		return types.OperatorInfo{}, false
+	}
+	select {
+	case ops.operatorInfoC <- operatorInfo:
+	case <-ctx.Done():
+	}
+	if !operatorInfo.IsRegistered && ops.opts.FilterUnregisteredOperators {
+		return types.OperatorInfo{}, false
+	}
+	return types.OperatorInfo{Socket: operatorInfo.Socket, Pubkeys: operatorInfo.Pubkeys}, true
+}
+
+// allowChainLookup returns whether operator can be looked up on chain, recording the lookup if so
+func (ops *OperatorsInfoServiceInMemory) allowChainLookup(operator common.Address) bool {
+	ops.chainLookupMutex.Lock()
+	defer ops.chainLookupMutex.Unlock()
+	now := time.Now()
+	for lookedUpOperator, lookupTime := range ops.chainLookupTimes {
+		if now.Sub(lookupTime) >= ops.opts.ChainLookupInterval {
+			delete(ops.chainLookupTimes, lookedUpOperator)
+		}
+	}
+	if _, ok := ops.chainLookupTimes[operator]; ok {
+		return false
+	}
+	ops.chainLookupTimes[operator] = now
+	return true
+}
+
+// Name identifies the service in the health reports, see services.HealthRegistry
+func (ops *OperatorsInfoServiceInMemory) Name() string {
+	return "operatorsinfo"
+}
+
+// Health reports the service as degraded while the past events aren't queried yet, or while a websocket subscription
+// is down, the events of the operators possibly being missed meanwhile
+func (ops *OperatorsInfoServiceInMemory) Health(ctx context.Context) (services.ServiceHealth, string) {
+	if !ops.backfilled.Load() {
+		return services.ServiceDegraded, "past operator events not queried yet"
+	}
+	if subscriptionsDown := ops.subscriptionsDown.Load(); subscriptionsDown > 0 {
+		return services.ServiceDegraded, fmt.Sprintf("%d websocket subscriptions down", subscriptionsDown)
+	}
+	return services.ServiceHealthy, ""
+}
+
+// setOperatorInfo applies the state of the operator operatorId rebuilt from its events by the avs registry reader to
+// the db. The operators whose pubkey registration is outside of the queried range can't be served, since their pubkeys
+// aren't known, but their registration state and socket are still applied.
+func (ops *OperatorsInfoServiceInMemory) setOperatorInfo(
+	operatorId types.OperatorId,
+	operator avsregistry.OperatorInfo,
+) {
+	if operator.Pubkeys.G1Pubkey != nil {
+		ops.setOperatorPubkeys(operator.OperatorAddr, operatorId, operator.Pubkeys, operator.LastUpdateBlock)
+	}
+	if operator.Socket != "" {
+		ops.setOperatorSocket(operatorId, operator.Socket, operator.LastUpdateBlock)
+	}
+	// the readers which don't track the registration blocks only report the registered operators
+	if operator.LastRegistrationUpdateBlock != 0 || operator.IsRegistered {
+		ops.setOperatorRegistered(operatorId, operator.IsRegistered, operator.LastRegistrationUpdateBlock)
+	}
+}
+
+// setOperatorPubkeys maps operatorAddr to the operator operatorId whose pubkeys were registered at blockNumber,
+// returning false if the event is stale. An operator re-registering with new pubkeys gets a new operator id, so the
+// address is only remapped to operatorId if the pubkeys of its current operator id were registered at an earlier block.
+func (ops *OperatorsInfoServiceInMemory) setOperatorPubkeys(
+	operatorAddr common.Address,
+	operatorId types.OperatorId,
+	pubkeys types.OperatorPubkeys,
+	blockNumber uint64,
+) bool {
+	currentOperatorId, ok := ops.operatorAddrToId[operatorAddr]
+	if ok && currentOperatorId != operatorId && blockNumber < ops.operatorStateDict[currentOperatorId].pubkeyBlock {
+		return false
+	}
+	state := ops.operatorStateDict[operatorId]
+	if blockNumber < state.pubkeyBlock {
+		return false
+	}
+	state.pubkeyBlock = blockNumber
+	ops.operatorStateDict[operatorId] = state
+	ops.pubkeyDict[operatorAddr] = pubkeys
+	ops.operatorAddrToId[operatorAddr] = operatorId
+	return true
+}
+
+// setOperatorSocket sets the socket of the operator operatorId updated at blockNumber, returning false if the event
+// is stale
+func (ops *OperatorsInfoServiceInMemory) setOperatorSocket(
+	operatorId types.OperatorId,
+	socket types.Socket,
+	blockNumber uint64,
+) bool {
+	state := ops.operatorStateDict[operatorId]
+	if blockNumber < state.socketBlock {
+		return false
+	}
+	state.socketBlock = blockNumber
+	ops.operatorStateDict[operatorId] = state
+	ops.updateSocketMapping(operatorId, socket)
+	return true
+}
+
+// setOperatorRegistered sets whether the operator operatorId is registered as of blockNumber, returning false if the
+// event is stale
+func (ops *OperatorsInfoServiceInMemory) setOperatorRegistered(
+	operatorId types.OperatorId,
+	registered bool,
+	blockNumber uint64,
+) bool {
+	state := ops.operatorStateDict[operatorId]
+	if blockNumber < state.lastUpdateBlock {
+		return false
 	}
+	state.registered = registered
+	state.lastUpdateBlock = blockNumber
+	ops.operatorStateDict[operatorId] = state
+	return true
 }
 
 func (ops *OperatorsInfoServiceInMemory) updateSocketMapping(operatorId types.OperatorId, socket types.Socket) {
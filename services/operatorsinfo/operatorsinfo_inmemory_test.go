@@ -1,23 +1,32 @@
 package operatorsinfo
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 
 	"log/slog"
 	"math/big"
 	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/event"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
 	apkregistrybindings "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
 	blsapkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
 	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/internal/fakes"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/services"
 	"github.com/Layr-Labs/eigensdk-go/types"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -27,6 +36,8 @@ import (
 type fakeAVSRegistrySubscriber struct {
 	pubkeyRegistrationEventC   chan *apkregistrybindings.ContractBLSApkRegistryNewPubkeyRegistration
 	operatorSocketUpdateEventC chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate
+	operatorRegisteredEventC   chan *regcoord.ContractRegistryCoordinatorOperatorRegistered
+	operatorDeregisteredEventC chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered
 	eventSubscription          *fakeEventSubscription
 }
 
@@ -50,6 +61,14 @@ func (f *fakeAVSRegistrySubscriber) SubscribeToOperatorSocketUpdates() (chan *re
 	return f.operatorSocketUpdateEventC, f.eventSubscription, nil
 }
 
+func (f *fakeAVSRegistrySubscriber) SubscribeToOperatorRegistrations() (<-chan *regcoord.ContractRegistryCoordinatorOperatorRegistered, event.Subscription, error) {
+	return f.operatorRegisteredEventC, f.eventSubscription, nil
+}
+
+func (f *fakeAVSRegistrySubscriber) SubscribeToOperatorDeregistrations() (<-chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered, event.Subscription, error) {
+	return f.operatorDeregisteredEventC, f.eventSubscription, nil
+}
+
 type fakeEventSubscription struct {
 	errC chan error
 }
@@ -179,3 +198,706 @@ func TestGetOperatorInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestOperatorsInfoServiceRegistrationLifecycle(t *testing.T) {
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	operatorAddr := common.HexToAddress("0x1")
+	newTestKey := func() (types.OperatorId, types.OperatorPubkeys) {
+		keyPair, err := bls.GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("failed to generate bls keys: %v", err)
+		}
+		return types.OperatorIdFromKeyPair(keyPair), types.OperatorPubkeys{
+			G1Pubkey: keyPair.GetPubKeyG1(),
+			G2Pubkey: keyPair.GetPubKeyG2(),
+		}
+	}
+	// the operator registers with key 1, deregisters and registers again with key 2
+	operatorId1, pubkeys1 := newTestKey()
+	operatorId2, pubkeys2 := newTestKey()
+
+	// startService starts a service filtering the unregistered operators, returning the subscriber through which its
+	// events are sent
+	startService := func(
+		t *testing.T,
+		operators map[types.OperatorId]avsregistry.OperatorInfo,
+	) (*OperatorsInfoServiceInMemory, *fakeAVSRegistrySubscriber) {
+		subscriber := &fakeAVSRegistrySubscriber{
+			pubkeyRegistrationEventC:   make(chan *apkregistrybindings.ContractBLSApkRegistryNewPubkeyRegistration),
+			operatorSocketUpdateEventC: make(chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate),
+			operatorRegisteredEventC:   make(chan *regcoord.ContractRegistryCoordinatorOperatorRegistered),
+			operatorDeregisteredEventC: make(chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered),
+			eventSubscription:          newFakeEventSubscription(nil),
+		}
+		service := NewOperatorsInfoServiceInMemory(
+			context.Background(),
+			subscriber,
+			&recordingAVSRegistryReader{operators: operators},
+			nil,
+			Opts{FilterUnregisteredOperators: true, DisableChainLookup: true},
+			logger,
+		)
+		return service, subscriber
+	}
+	// register sends the events of the registration of the operator with pubkeys at block
+	register := func(
+		subscriber *fakeAVSRegistrySubscriber,
+		operatorId types.OperatorId,
+		pubkeys types.OperatorPubkeys,
+		socket string,
+		block uint64,
+	) {
+		subscriber.pubkeyRegistrationEventC <- &apkregistrybindings.ContractBLSApkRegistryNewPubkeyRegistration{
+			Operator: operatorAddr,
+			PubkeyG1: apkregistrybindings.BN254G1Point(chainioutils.ConvertToBN254G1Point(pubkeys.G1Pubkey)),
+			PubkeyG2: apkregistrybindings.BN254G2Point(chainioutils.ConvertToBN254G2Point(pubkeys.G2Pubkey)),
+			Raw:      gethtypes.Log{BlockNumber: block},
+		}
+		subscriber.operatorRegisteredEventC <- &regcoord.ContractRegistryCoordinatorOperatorRegistered{
+			Operator:   operatorAddr,
+			OperatorId: operatorId,
+			Raw:        gethtypes.Log{BlockNumber: block},
+		}
+		subscriber.operatorSocketUpdateEventC <- &regcoord.ContractRegistryCoordinatorOperatorSocketUpdate{
+			OperatorId: operatorId,
+			Socket:     socket,
+			Raw:        gethtypes.Log{BlockNumber: block},
+		}
+	}
+	deregister := func(subscriber *fakeAVSRegistrySubscriber, operatorId types.OperatorId, block uint64) {
+		subscriber.operatorDeregisteredEventC <- &regcoord.ContractRegistryCoordinatorOperatorDeregistered{
+			Operator:   operatorAddr,
+			OperatorId: operatorId,
+			Raw:        gethtypes.Log{BlockNumber: block},
+		}
+	}
+	// checkOperatorInfo checks the operator is served with pubkeys and socket, or not found if pubkeys is nil
+	checkOperatorInfo := func(
+		t *testing.T,
+		service *OperatorsInfoServiceInMemory,
+		pubkeys *types.OperatorPubkeys,
+		socket types.Socket,
+	) {
+		gotOperatorInfo, gotOperatorFound := service.GetOperatorInfo(context.Background(), operatorAddr)
+		if pubkeys == nil {
+			if gotOperatorFound {
+				t.Fatalf("GetOperatorInfo found unregistered operator. Got: %v.", gotOperatorInfo)
+			}
+			return
+		}
+		wantOperatorInfo := types.OperatorInfo{Pubkeys: *pubkeys, Socket: socket}
+		if !gotOperatorFound || !reflect.DeepEqual(wantOperatorInfo, gotOperatorInfo) {
+			t.Fatalf(
+				"GetOperatorInfo returned wrong operator info. Got: %v (found: %v), want: %v.",
+				gotOperatorInfo,
+				gotOperatorFound,
+				wantOperatorInfo,
+			)
+		}
+	}
+
+	t.Run("register, deregister and register with a new key", func(t *testing.T) {
+		service, subscriber := startService(t, nil)
+
+		register(subscriber, operatorId1, pubkeys1, "localhost:1", 10)
+		checkOperatorInfo(t, service, &pubkeys1, "localhost:1")
+
+		deregister(subscriber, operatorId1, 20)
+		checkOperatorInfo(t, service, nil, "")
+
+		register(subscriber, operatorId2, pubkeys2, "localhost:2", 30)
+		checkOperatorInfo(t, service, &pubkeys2, "localhost:2")
+
+		// the events of the first registration are delivered again, e.g. after a reconnection
+		register(subscriber, operatorId1, pubkeys1, "localhost:1", 10)
+		deregister(subscriber, operatorId2, 25)
+		checkOperatorInfo(t, service, &pubkeys2, "localhost:2")
+	})
+
+	t.Run("subscription events older than the past events are ignored", func(t *testing.T) {
+		service, subscriber := startService(t, map[types.OperatorId]avsregistry.OperatorInfo{
+			operatorId1: {
+				OperatorAddr:                operatorAddr,
+				Pubkeys:                     pubkeys1,
+				Socket:                      "localhost:1",
+				IsRegistered:                false,
+				LastUpdateBlock:             20,
+				LastRegistrationUpdateBlock: 20,
+			},
+			operatorId2: {
+				OperatorAddr:                operatorAddr,
+				Pubkeys:                     pubkeys2,
+				Socket:                      "localhost:2",
+				IsRegistered:                true,
+				LastUpdateBlock:             30,
+				LastRegistrationUpdateBlock: 30,
+			},
+		})
+		checkOperatorInfo(t, service, &pubkeys2, "localhost:2")
+
+		// the subscriptions started before the past events were queried, so they deliver the events of both
+		// registrations once the past events are applied
+		register(subscriber, operatorId1, pubkeys1, "localhost:1", 10)
+		deregister(subscriber, operatorId1, 20)
+		checkOperatorInfo(t, service, &pubkeys2, "localhost:2")
+	})
+}
+
+// blockingResubscriptionSubscriber is a fakeAVSRegistrySubscriber whose socket update subscription fails through
+// socketSubscription, and whose resubscriptions to the socket updates block until released
+type blockingResubscriptionSubscriber struct {
+	*fakeAVSRegistrySubscriber
+	socketSubscription  *fakeEventSubscription
+	socketSubscriptions int
+	releaseC            chan struct{}
+}
+
+func (f *blockingResubscriptionSubscriber) SubscribeToOperatorSocketUpdates() (chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate, event.Subscription, error) {
+	f.socketSubscriptions++
+	if f.socketSubscriptions > 1 {
+		<-f.releaseC
+	}
+	return nil, f.socketSubscription, nil
+}
+
+func TestOperatorsInfoServiceHealth(t *testing.T) {
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	subscriber := &blockingResubscriptionSubscriber{
+		fakeAVSRegistrySubscriber: newFakeAVSRegistrySubscriber(newFakeEventSubscription(nil), nil, nil),
+		socketSubscription:        newFakeEventSubscription(make(chan error)),
+		releaseC:                  make(chan struct{}),
+	}
+	service := NewOperatorsInfoServiceInMemory(
+		context.Background(),
+		subscriber,
+		&recordingAVSRegistryReader{},
+		nil,
+		Opts{},
+		logger,
+	)
+	checkHealth := func(t *testing.T, wantHealth services.ServiceHealth) {
+		deadline := time.Now().Add(time.Second)
+		for {
+			gotHealth, reason := service.Health(context.Background())
+			if gotHealth == wantHealth {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Health returned wrong health. Got: %v (%s), want: %v.", gotHealth, reason, wantHealth)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// the service is backfilled once constructed
+	checkHealth(t, services.ServiceHealthy)
+
+	subscriber.socketSubscription.errC <- errors.New("websocket connection closed")
+	checkHealth(t, services.ServiceDegraded)
+
+	close(subscriber.releaseC)
+	checkHealth(t, services.ServiceHealthy)
+}
+
+func TestOperatorsInfoServiceGetAllOperatorsInfo(t *testing.T) {
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	newTestKey := func() (types.OperatorId, types.OperatorPubkeys) {
+		keyPair, err := bls.GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("failed to generate bls keys: %v", err)
+		}
+		return types.OperatorIdFromKeyPair(keyPair), types.OperatorPubkeys{
+			G1Pubkey: keyPair.GetPubKeyG1(),
+			G2Pubkey: keyPair.GetPubKeyG2(),
+		}
+	}
+	// operator 1 is backfilled up to block 10, and operator 2 registers its pubkeys at block 20
+	operatorAddr1, operatorAddr2 := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	operatorId1, pubkeys1 := newTestKey()
+	_, pubkeys2 := newTestKey()
+	subscriber := &fakeAVSRegistrySubscriber{
+		pubkeyRegistrationEventC:   make(chan *apkregistrybindings.ContractBLSApkRegistryNewPubkeyRegistration),
+		operatorSocketUpdateEventC: make(chan *regcoord.ContractRegistryCoordinatorOperatorSocketUpdate),
+		operatorRegisteredEventC:   make(chan *regcoord.ContractRegistryCoordinatorOperatorRegistered),
+		operatorDeregisteredEventC: make(chan *regcoord.ContractRegistryCoordinatorOperatorDeregistered),
+		eventSubscription:          newFakeEventSubscription(nil),
+	}
+	service := NewOperatorsInfoServiceInMemory(
+		context.Background(),
+		subscriber,
+		&recordingAVSRegistryReader{operators: map[types.OperatorId]avsregistry.OperatorInfo{
+			operatorId1: {
+				OperatorAddr:    operatorAddr1,
+				Pubkeys:         pubkeys1,
+				Socket:          "localhost:8080",
+				IsRegistered:    true,
+				LastUpdateBlock: 5,
+			},
+		}},
+		nil,
+		Opts{StopBlock: big.NewInt(10), DisableChainLookup: true},
+		logger,
+	)
+	subscriber.pubkeyRegistrationEventC <- &apkregistrybindings.ContractBLSApkRegistryNewPubkeyRegistration{
+		Operator: operatorAddr2,
+		PubkeyG1: apkregistrybindings.BN254G1Point(chainioutils.ConvertToBN254G1Point(pubkeys2.G1Pubkey)),
+		PubkeyG2: apkregistrybindings.BN254G2Point(chainioutils.ConvertToBN254G2Point(pubkeys2.G2Pubkey)),
+		Raw:      gethtypes.Log{BlockNumber: 20},
+	}
+
+	gotOperators, gotLastBlock, err := service.GetAllOperatorsInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllOperatorsInfo failed: %v", err)
+	}
+	wantOperators := map[common.Address]types.OperatorInfo{
+		operatorAddr1: {Pubkeys: pubkeys1, Socket: "localhost:8080"},
+		operatorAddr2: {Pubkeys: pubkeys2},
+	}
+	if !reflect.DeepEqual(wantOperators, gotOperators) {
+		t.Fatalf("GetAllOperatorsInfo returned wrong operators. Got: %v, want: %v.", gotOperators, wantOperators)
+	}
+	if gotLastBlock != 20 {
+		t.Fatalf("GetAllOperatorsInfo returned wrong last block. Got: %v, want: %v.", gotLastBlock, 20)
+	}
+
+	// the returned operators are a copy, which can be modified without affecting the service
+	gotOperators[operatorAddr1].Pubkeys.G1Pubkey.X.SetZero()
+	delete(gotOperators, operatorAddr2)
+	gotOperatorInfo, gotOperatorFound := service.GetOperatorInfo(context.Background(), operatorAddr1)
+	if !gotOperatorFound || !reflect.DeepEqual(pubkeys1.G1Pubkey, gotOperatorInfo.Pubkeys.G1Pubkey) {
+		t.Fatalf("GetOperatorInfo returned a modified operator. Got: %v, want: %v.", gotOperatorInfo.Pubkeys, pubkeys1)
+	}
+	if _, gotOperatorFound := service.GetOperatorInfo(context.Background(), operatorAddr2); !gotOperatorFound {
+		t.Fatalf("GetOperatorInfo didn't find operator %v", operatorAddr2)
+	}
+
+	var buf bytes.Buffer
+	if err := service.WriteJSON(context.Background(), &buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var gotJSON operatorsInfoJSON
+	if err := json.Unmarshal(buf.Bytes(), &gotJSON); err != nil {
+		t.Fatalf("WriteJSON wrote invalid JSON %s: %v", buf.String(), err)
+	}
+	if gotJSON.LastProcessedBlock != 20 || !reflect.DeepEqual(wantOperators, gotJSON.Operators) {
+		t.Fatalf("WriteJSON wrote wrong operators. Got: %v, want: %v.", gotJSON.Operators, wantOperators)
+	}
+}
+
+// recordingAVSRegistryReader returns its operators from the queries of past events, recording their block ranges, and
+// its chainOperators from the lookups of single operators, recording the operators looked up
+type recordingAVSRegistryReader struct {
+	operators      map[types.OperatorId]avsregistry.OperatorInfo
+	queryRanges    [][2]uint64
+	chainOperators map[common.Address]avsregistry.OperatorInfo
+	lookups        []common.Address
+	lookupRanges   [][2]uint64
+}
+
+func (r *recordingAVSRegistryReader) QueryExistingRegisteredOperators(
+	ctx context.Context,
+	startBlock uint64,
+	stopBlock uint64,
+	opts avsregistry.QueryOpts,
+) (map[types.OperatorId]avsregistry.OperatorInfo, error) {
+	r.queryRanges = append(r.queryRanges, [2]uint64{startBlock, stopBlock})
+	return r.operators, nil
+}
+
+func (r *recordingAVSRegistryReader) QueryOperatorInfo(
+	ctx context.Context,
+	operatorAddr common.Address,
+	startBlock uint64,
+	stopBlock uint64,
+	opts avsregistry.QueryOpts,
+) (avsregistry.OperatorInfo, bool, error) {
+	r.lookups = append(r.lookups, operatorAddr)
+	r.lookupRanges = append(r.lookupRanges, [2]uint64{startBlock, stopBlock})
+	operator, ok := r.chainOperators[operatorAddr]
+	return operator, ok, nil
+}
+
+func TestOperatorsInfoServiceChainLookup(t *testing.T) {
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	keyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("failed to generate bls keys: %v", err)
+	}
+	// the operator registered after the past events were queried, and its events weren't received yet
+	registeredOperator := avsregistry.OperatorInfo{
+		OperatorAddr: common.HexToAddress("0x1"),
+		Pubkeys:      types.OperatorPubkeys{G1Pubkey: keyPair.GetPubKeyG1(), G2Pubkey: keyPair.GetPubKeyG2()},
+		Socket:       types.Socket("localhost:8080"),
+		IsRegistered: true,
+	}
+	unknownOperatorAddr := common.HexToAddress("0x2")
+
+	var tests = []struct {
+		name              string
+		opts              Opts
+		queryOperatorAddr common.Address
+		wantOperatorFound bool
+		wantLookups       int
+		wantLookupRange   [2]uint64
+	}{
+		{
+			name:              "registered operator is found by a single lookup",
+			opts:              Opts{},
+			queryOperatorAddr: registeredOperator.OperatorAddr,
+			wantOperatorFound: true,
+			wantLookups:       1,
+		},
+		{
+			name: "lookup is bounded to the last blocks",
+			opts: Opts{
+				CurrentBlockNumber: func(ctx context.Context) (uint64, error) {
+					return 1000, nil
+				},
+				ChainLookupBlockRange: 100,
+			},
+			queryOperatorAddr: registeredOperator.OperatorAddr,
+			wantOperatorFound: true,
+			wantLookups:       1,
+			wantLookupRange:   [2]uint64{901, 1000},
+		},
+		{
+			name:              "unknown operator is looked up once per interval",
+			opts:              Opts{},
+			queryOperatorAddr: unknownOperatorAddr,
+			wantOperatorFound: false,
+			wantLookups:       1,
+		},
+		{
+			name:              "operator isn't looked up if chain lookups are disabled",
+			opts:              Opts{DisableChainLookup: true},
+			queryOperatorAddr: registeredOperator.OperatorAddr,
+			wantOperatorFound: false,
+			wantLookups:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &recordingAVSRegistryReader{
+				chainOperators: map[common.Address]avsregistry.OperatorInfo{
+					registeredOperator.OperatorAddr: registeredOperator,
+				},
+			}
+			service := NewOperatorsInfoServiceInMemory(
+				context.Background(),
+				newFakeAVSRegistrySubscriber(newFakeEventSubscription(nil), nil, nil),
+				reader,
+				nil,
+				tt.opts,
+				logger,
+			)
+
+			// the operator is queried twice in a row, the second query being answered from the db or rate limited
+			for i := 0; i < 2; i++ {
+				gotOperatorInfo, gotOperatorFound := service.GetOperatorInfo(context.Background(), tt.queryOperatorAddr)
+				if tt.wantOperatorFound != gotOperatorFound {
+					t.Fatalf(
+						"GetOperatorInfo returned wrong ok. Got: %v, want: %v.",
+						gotOperatorFound,
+						tt.wantOperatorFound,
+					)
+				}
+				wantOperatorInfo := types.OperatorInfo{
+					Pubkeys: registeredOperator.Pubkeys,
+					Socket:  registeredOperator.Socket,
+				}
+				if tt.wantOperatorFound && !reflect.DeepEqual(wantOperatorInfo, gotOperatorInfo) {
+					t.Fatalf(
+						"GetOperatorInfo returned wrong operator info. Got: %v, want: %v.",
+						gotOperatorInfo,
+						wantOperatorInfo,
+					)
+				}
+			}
+			if len(reader.lookups) != tt.wantLookups {
+				t.Fatalf("wrong number of chain lookups. Got: %v, want: %v.", len(reader.lookups), tt.wantLookups)
+			}
+			if tt.wantLookups > 0 && reader.lookupRanges[0] != tt.wantLookupRange {
+				t.Fatalf("wrong block range looked up. Got: %v, want: %v.", reader.lookupRanges[0], tt.wantLookupRange)
+			}
+		})
+	}
+}
+
+// rangeLimitedAVSRegistryReader returns the operators of its events within the queried block ranges, failing the test
+// if a range is wider than maxBlockRange like public RPCs reject wide log filter queries
+type rangeLimitedAVSRegistryReader struct {
+	t             *testing.T
+	maxBlockRange uint64
+	// events are the states of the operators set at each block, in block order
+	events      []rangeLimitedEvent
+	mu          sync.Mutex
+	queryRanges [][2]uint64
+}
+
+type rangeLimitedEvent struct {
+	block      uint64
+	operatorId types.OperatorId
+	operator   avsregistry.OperatorInfo
+}
+
+func (r *rangeLimitedAVSRegistryReader) QueryExistingRegisteredOperators(
+	ctx context.Context,
+	startBlock uint64,
+	stopBlock uint64,
+	opts avsregistry.QueryOpts,
+) (map[types.OperatorId]avsregistry.OperatorInfo, error) {
+	r.mu.Lock()
+	r.queryRanges = append(r.queryRanges, [2]uint64{startBlock, stopBlock})
+	r.mu.Unlock()
+	if stopBlock-startBlock+1 > r.maxBlockRange {
+		r.t.Errorf("queried blocks %d to %d, wider than the max range %d", startBlock, stopBlock, r.maxBlockRange)
+	}
+	operators := make(map[types.OperatorId]avsregistry.OperatorInfo)
+	for _, event := range r.events {
+		if event.block >= startBlock && event.block <= stopBlock {
+			operators[event.operatorId] = mergeOperatorInfo(operators[event.operatorId], event.operator)
+		}
+	}
+	return operators, nil
+}
+
+func (r *rangeLimitedAVSRegistryReader) QueryOperatorInfo(
+	ctx context.Context,
+	operatorAddr common.Address,
+	startBlock uint64,
+	stopBlock uint64,
+	opts avsregistry.QueryOpts,
+) (avsregistry.OperatorInfo, bool, error) {
+	return avsregistry.OperatorInfo{}, false, nil
+}
+
+func TestOperatorsInfoServiceChunkedBackfill(t *testing.T) {
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	newTestOperator := func(addr string) (types.OperatorId, avsregistry.OperatorInfo) {
+		keyPair, err := bls.GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("failed to generate bls keys: %v", err)
+		}
+		return types.OperatorIdFromKeyPair(keyPair), avsregistry.OperatorInfo{
+			OperatorAddr: common.HexToAddress(addr),
+			Pubkeys:      types.OperatorPubkeys{G1Pubkey: keyPair.GetPubKeyG1(), G2Pubkey: keyPair.GetPubKeyG2()},
+			Socket:       types.Socket("localhost:" + addr),
+		}
+	}
+	operator1Id, operator1 := newTestOperator("0x1")
+	operator2Id, operator2 := newTestOperator("0x2")
+	// operator 1 registers in the first chunk and updates its socket in the last one
+	events := []rangeLimitedEvent{
+		{block: 5, operatorId: operator1Id, operator: operator1},
+		{block: 120, operatorId: operator2Id, operator: operator2},
+		{block: 250, operatorId: operator1Id, operator: avsregistry.OperatorInfo{Socket: "localhost:0x1-updated"}},
+	}
+	updatedOperator1 := operator1
+	updatedOperator1.Socket = "localhost:0x1-updated"
+
+	var tests = []struct {
+		name                     string
+		logFilterQueryBlockRange *big.Int
+		opts                     Opts
+		wantQueryRanges          [][2]uint64
+		wantOperators            []avsregistry.OperatorInfo
+		wantMissingOperators     []avsregistry.OperatorInfo
+	}{
+		{
+			name: "chunks of BlockChunkSize queried concurrently",
+			opts: Opts{
+				StopBlock:           big.NewInt(299),
+				BlockChunkSize:      100,
+				MaxConcurrentChunks: 2,
+			},
+			wantQueryRanges: [][2]uint64{{0, 99}, {100, 199}, {200, 299}},
+			wantOperators:   []avsregistry.OperatorInfo{updatedOperator1, operator2},
+		},
+		{
+			name:                     "chunks of logFilterQueryBlockRange by default",
+			logFilterQueryBlockRange: big.NewInt(100),
+			opts: Opts{
+				CurrentBlockNumber: func(ctx context.Context) (uint64, error) {
+					return 250, nil
+				},
+			},
+			wantQueryRanges: [][2]uint64{{0, 99}, {100, 199}, {200, 250}},
+			wantOperators:   []avsregistry.OperatorInfo{updatedOperator1, operator2},
+		},
+		{
+			name: "operators registered before StartBlock are missing",
+			opts: Opts{
+				StartBlock:     big.NewInt(100),
+				StopBlock:      big.NewInt(299),
+				BlockChunkSize: 100,
+			},
+			wantQueryRanges:      [][2]uint64{{100, 199}, {200, 299}},
+			wantOperators:        []avsregistry.OperatorInfo{operator2},
+			wantMissingOperators: []avsregistry.OperatorInfo{operator1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &rangeLimitedAVSRegistryReader{t: t, maxBlockRange: 100, events: events}
+			service := NewOperatorsInfoServiceInMemory(
+				context.Background(),
+				newFakeAVSRegistrySubscriber(newFakeEventSubscription(nil), nil, nil),
+				reader,
+				tt.logFilterQueryBlockRange,
+				tt.opts,
+				logger,
+			)
+
+			gotQueryRanges := reader.queryRanges
+			sort.Slice(gotQueryRanges, func(i, j int) bool { return gotQueryRanges[i][0] < gotQueryRanges[j][0] })
+			if !reflect.DeepEqual(tt.wantQueryRanges, gotQueryRanges) {
+				t.Fatalf("wrong block ranges queried. Got: %v, want: %v.", gotQueryRanges, tt.wantQueryRanges)
+			}
+			for _, operator := range tt.wantOperators {
+				wantOperatorInfo := types.OperatorInfo{Pubkeys: operator.Pubkeys, Socket: operator.Socket}
+				gotOperatorInfo, gotOperatorFound := service.GetOperatorInfo(
+					context.Background(),
+					operator.OperatorAddr,
+				)
+				if !gotOperatorFound || !reflect.DeepEqual(wantOperatorInfo, gotOperatorInfo) {
+					t.Fatalf(
+						"GetOperatorInfo returned wrong operator info. Got: %v (found: %v), want: %v.",
+						gotOperatorInfo,
+						gotOperatorFound,
+						wantOperatorInfo,
+					)
+				}
+			}
+			for _, operator := range tt.wantMissingOperators {
+				_, gotOperatorFound := service.GetOperatorInfo(context.Background(), operator.OperatorAddr)
+				if gotOperatorFound {
+					t.Fatalf("GetOperatorInfo found operator %v registered before StartBlock", operator.OperatorAddr)
+				}
+			}
+		})
+	}
+}
+
+func TestOperatorsInfoServiceSnapshot(t *testing.T) {
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{Level: slog.LevelDebug})
+	newTestOperator := func(addr string) (types.OperatorId, avsregistry.OperatorInfo) {
+		keyPair, err := bls.GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("failed to generate bls keys: %v", err)
+		}
+		return types.OperatorIdFromKeyPair(keyPair), avsregistry.OperatorInfo{
+			OperatorAddr: common.HexToAddress(addr),
+			Pubkeys:      types.OperatorPubkeys{G1Pubkey: keyPair.GetPubKeyG1(), G2Pubkey: keyPair.GetPubKeyG2()},
+			Socket:       types.Socket("localhost:" + addr),
+			IsRegistered: true,
+		}
+	}
+	operator1Id, operator1 := newTestOperator("0x1")
+	operator2Id, operator2 := newTestOperator("0x2")
+
+	// startService starts a service at currentBlock whose past events are the ones of operators, returning the block
+	// ranges it queried
+	startService := func(
+		t *testing.T,
+		store OperatorsSnapshotStore,
+		currentBlock uint64,
+		operators map[types.OperatorId]avsregistry.OperatorInfo,
+	) (*OperatorsInfoServiceInMemory, [][2]uint64) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		reader := &recordingAVSRegistryReader{operators: operators}
+		service := NewOperatorsInfoServiceInMemory(
+			ctx,
+			newFakeAVSRegistrySubscriber(newFakeEventSubscription(nil), nil, nil),
+			reader,
+			nil,
+			Opts{
+				SnapshotStore: store,
+				CurrentBlockNumber: func(ctx context.Context) (uint64, error) {
+					return currentBlock, nil
+				},
+			},
+			logger,
+		)
+		return service, reader.queryRanges
+	}
+	checkOperatorInfo := func(
+		t *testing.T,
+		service *OperatorsInfoServiceInMemory,
+		operator avsregistry.OperatorInfo,
+	) {
+		wantOperatorInfo := types.OperatorInfo{Pubkeys: operator.Pubkeys, Socket: operator.Socket}
+		gotOperatorInfo, gotOperatorFound := service.GetOperatorInfo(context.Background(), operator.OperatorAddr)
+		if !gotOperatorFound || !reflect.DeepEqual(wantOperatorInfo, gotOperatorInfo) {
+			t.Fatalf(
+				"GetOperatorInfo returned wrong operator info. Got: %v (found: %v), want: %v.",
+				gotOperatorInfo,
+				gotOperatorFound,
+				wantOperatorInfo,
+			)
+		}
+	}
+	checkQueryRanges := func(t *testing.T, gotQueryRanges [][2]uint64, wantQueryRanges [][2]uint64) {
+		if !reflect.DeepEqual(wantQueryRanges, gotQueryRanges) {
+			t.Fatalf("wrong block ranges queried. Got: %v, want: %v.", gotQueryRanges, wantQueryRanges)
+		}
+	}
+
+	t.Run("restart - only the blocks after the snapshot are queried", func(t *testing.T) {
+		store := NewFileOperatorsSnapshotStore(filepath.Join(t.TempDir(), "operators.json"))
+		_, queryRanges := startService(t, store, 100, map[types.OperatorId]avsregistry.OperatorInfo{
+			operator1Id: operator1,
+		})
+		checkQueryRanges(t, queryRanges, [][2]uint64{{0, 100}})
+
+		service, queryRanges := startService(t, store, 150, map[types.OperatorId]avsregistry.OperatorInfo{
+			operator2Id: operator2,
+		})
+		checkQueryRanges(t, queryRanges, [][2]uint64{{101, 150}})
+		checkOperatorInfo(t, service, operator1)
+		checkOperatorInfo(t, service, operator2)
+
+		// no block after the snapshot
+		service, queryRanges = startService(t, store, 150, nil)
+		checkQueryRanges(t, queryRanges, nil)
+		checkOperatorInfo(t, service, operator1)
+		checkOperatorInfo(t, service, operator2)
+	})
+
+	t.Run("corrupted snapshot - full rebuild", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "operators.json")
+		if err := os.WriteFile(path, []byte(`{"version": 1, "lastBlock": 10`), 0o600); err != nil {
+			t.Fatalf("failed to write snapshot: %v", err)
+		}
+		store := NewFileOperatorsSnapshotStore(path)
+		service, queryRanges := startService(t, store, 100, map[types.OperatorId]avsregistry.OperatorInfo{
+			operator1Id: operator1,
+		})
+		checkQueryRanges(t, queryRanges, [][2]uint64{{0, 100}})
+		checkOperatorInfo(t, service, operator1)
+
+		// the rebuilt operators are saved again
+		snapshot, err := store.Load()
+		if err != nil {
+			t.Fatalf("failed to load snapshot: %v", err)
+		}
+		if snapshot.LastBlock != 100 || len(snapshot.Operators) != 1 {
+			t.Fatalf("wrong snapshot saved: %v", snapshot)
+		}
+	})
+
+	t.Run("snapshot of another version - full rebuild", func(t *testing.T) {
+		store := NewFileOperatorsSnapshotStore(filepath.Join(t.TempDir(), "operators.json"))
+		err := store.Save(OperatorsSnapshot{Version: OperatorsSnapshotVersion + 1, LastBlock: 90})
+		if err != nil {
+			t.Fatalf("failed to save snapshot: %v", err)
+		}
+		service, queryRanges := startService(t, store, 100, map[types.OperatorId]avsregistry.OperatorInfo{
+			operator1Id: operator1,
+		})
+		checkQueryRanges(t, queryRanges, [][2]uint64{{0, 100}})
+		checkOperatorInfo(t, service, operator1)
+	})
+}
@@ -0,0 +1,116 @@
+package operatorsinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OperatorsSnapshotVersion is the version of the snapshots saved by OperatorsInfoServiceInMemory. The snapshots of
+// other versions are discarded, the service rebuilding its operators from the chain instead.
+const OperatorsSnapshotVersion = 2
+
+// ErrOperatorsSnapshotNotFound is returned by an OperatorsSnapshotStore which has no snapshot, e.g. on the first start
+var ErrOperatorsSnapshotNotFound = errors.New("operators snapshot not found")
+
+// OperatorsSnapshotStore persists the operators of an OperatorsInfoServiceInMemory, so that on restart the service
+// only queries the events of the blocks after its snapshot, see Opts.SnapshotStore
+type OperatorsSnapshotStore interface {
+	// Load returns the saved snapshot, or ErrOperatorsSnapshotNotFound if there's none
+	Load() (OperatorsSnapshot, error)
+	// Save replaces the saved snapshot with snapshot
+	Save(snapshot OperatorsSnapshot) error
+}
+
+// OperatorsSnapshot is the state of an OperatorsInfoServiceInMemory once it processed the events up to LastBlock
+type OperatorsSnapshot struct {
+	Version   int                `json:"version"`
+	LastBlock uint64             `json:"lastBlock"`
+	Operators []SnapshotOperator `json:"operators"`
+	// Sockets are the sockets of the operators, including the ones whose pubkeys aren't known yet
+	Sockets []SnapshotSocket `json:"sockets"`
+	// Registrations are the registration states of the operators whose registration events were processed
+	Registrations []SnapshotRegistration `json:"registrations"`
+}
+
+// SnapshotOperator is an operator whose pubkeys are known, as persisted in an OperatorsSnapshot
+type SnapshotOperator struct {
+	OperatorAddr common.Address        `json:"operatorAddr"`
+	OperatorId   types.OperatorId      `json:"operatorId"`
+	Pubkeys      types.OperatorPubkeys `json:"pubkeys"`
+}
+
+// SnapshotSocket is the socket of an operator, as persisted in an OperatorsSnapshot
+type SnapshotSocket struct {
+	OperatorId types.OperatorId `json:"operatorId"`
+	Socket     types.Socket     `json:"socket"`
+}
+
+// SnapshotRegistration is the registration state of an operator, as persisted in an OperatorsSnapshot
+type SnapshotRegistration struct {
+	OperatorId types.OperatorId `json:"operatorId"`
+	Registered bool             `json:"registered"`
+	// LastUpdateBlock is the block of the most recent registration or deregistration of the operator
+	LastUpdateBlock uint64 `json:"lastUpdateBlock"`
+}
+
+// FileOperatorsSnapshotStore is an OperatorsSnapshotStore saving the snapshot as a JSON file. The file is replaced
+// atomically, so that a crash while saving leaves the previous snapshot.
+type FileOperatorsSnapshotStore struct {
+	path string
+}
+
+var _ OperatorsSnapshotStore = (*FileOperatorsSnapshotStore)(nil)
+
+// NewFileOperatorsSnapshotStore returns a FileOperatorsSnapshotStore saving the snapshot to the file at path
+func NewFileOperatorsSnapshotStore(path string) *FileOperatorsSnapshotStore {
+	return &FileOperatorsSnapshotStore{path: path}
+}
+
+func (s *FileOperatorsSnapshotStore) Load() (OperatorsSnapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return OperatorsSnapshot{}, fmt.Errorf("%w: %s", ErrOperatorsSnapshotNotFound, s.path)
+		}
+		return OperatorsSnapshot{}, utils.WrapError(fmt.Sprintf("failed to read operators snapshot %s", s.path), err)
+	}
+	var snapshot OperatorsSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return OperatorsSnapshot{}, utils.WrapError(fmt.Sprintf("failed to decode operators snapshot %s", s.path), err)
+	}
+	return snapshot, nil
+}
+
+func (s *FileOperatorsSnapshotStore) Save(snapshot OperatorsSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return utils.WrapError("failed to encode operators snapshot", err)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return utils.WrapError("failed to create operators snapshot file", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return utils.WrapError("failed to write operators snapshot", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return utils.WrapError("failed to write operators snapshot", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return utils.WrapError("failed to write operators snapshot", err)
+	}
+	if err := os.Rename(tmpFile.Name(), s.path); err != nil {
+		return utils.WrapError(fmt.Sprintf("failed to replace operators snapshot %s", s.path), err)
+	}
+	return nil
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/shurcooL/graphql"
@@ -16,17 +19,43 @@ import (
 
 type (
 	QueryOperatorByAddressGql struct {
-		Operator IndexedOperatorInfoGql `graphql:"operator(address: $address)"`
+		// the operators are indexed by address, which is the id of the Operator entities
+		Operator IndexedOperatorInfoGql `graphql:"operator(id: $id)"`
+	}
+	// QueryOperatorsGql queries a page of the operators ordered by id, starting after $lastId
+	QueryOperatorsGql struct {
+		Operators []IndexedOperatorInfoGql `graphql:"operators(first: $first, orderBy: id, where: {id_gt: $lastId})"`
 	}
 	OperatorsInfoServiceSubgraph struct {
 		logger logging.Logger
 		client GraphQLQuerier
 		name   string
+
+		// maxRetries is the number of times a query is retried after its first attempt failed, with a backoff
+		// doubling from initialBackoff, see WithSubgraphQueryRetries
+		maxRetries     int
+		initialBackoff time.Duration
+		// fallback serves the operators the subgraph doesn't have or returns invalid data for, see
+		// WithFallbackOperatorsInfoService
+		fallback OperatorsInfoService
+		// cache holds the operators found in the subgraph for cacheTTL, unless cacheTTL is 0, see
+		// WithOperatorsInfoCache
+		cache      map[common.Address]cachedOperatorInfo
+		cacheMutex sync.RWMutex
+		cacheTTL   time.Duration
+	}
+	// OperatorsInfoServiceSubgraphOption configures an OperatorsInfoServiceSubgraph
+	OperatorsInfoServiceSubgraphOption func(*OperatorsInfoServiceSubgraph)
+	// cachedOperatorInfo is an operator of the cache of an OperatorsInfoServiceSubgraph
+	cachedOperatorInfo struct {
+		operatorInfo types.OperatorInfo
+		cachedAt     time.Time
 	}
 	SocketUpdates struct {
 		Socket graphql.String
 	}
 	IndexedOperatorInfoGql struct {
+		Id         graphql.String
 		Address    graphql.String
 		PubkeyG1_X graphql.String   `graphql:"pubkeyG1_X"`
 		PubkeyG1_Y graphql.String   `graphql:"pubkeyG1_Y"`
@@ -56,35 +85,100 @@ type (
 
 var _ OperatorsInfoService = (*OperatorsInfoServiceSubgraph)(nil)
 
-// NewOperatorsInfoServiceSubgraph constructs a OperatorsInfoServiceSubgraph and starts it in a goroutine.
-// It takes a context as argument because the "backfilling" of the database is done inside this constructor,
-// so we wait for all past NewPubkeyRegistration/OperatorSocketUpdate events to be queried and the db to be filled
-// before returning the service.
-// The constructor is thus following a RAII-like pattern, of initializing the serving during construction.
-// Using a separate initialize() function might lead to some users forgetting to call it and the service not behaving
-// properly.
+const (
+	// DefaultSubgraphQueryRetries is the number of times a subgraph query is retried, unless set with
+	// WithSubgraphQueryRetries
+	DefaultSubgraphQueryRetries = 3
+	// DefaultSubgraphQueryBackoff is the backoff before the first retry of a subgraph query, unless set with
+	// WithSubgraphQueryRetries
+	DefaultSubgraphQueryBackoff = 100 * time.Millisecond
+	// subgraphQueryMaxBackoff bounds the backoff between the retries of a subgraph query
+	subgraphQueryMaxBackoff = 2 * time.Second
+	// subgraphOperatorsPageSize is the number of operators queried per page, the maximum allowed by graph-node
+	subgraphOperatorsPageSize = 1000
+)
+
+// WithSubgraphQueryRetries sets the number of times a failed subgraph query is retried, with a backoff doubling from
+// initialBackoff. DefaultSubgraphQueryRetries and DefaultSubgraphQueryBackoff unless set.
+func WithSubgraphQueryRetries(maxRetries int, initialBackoff time.Duration) OperatorsInfoServiceSubgraphOption {
+	return func(ops *OperatorsInfoServiceSubgraph) {
+		ops.maxRetries = maxRetries
+		ops.initialBackoff = initialBackoff
+	}
+}
+
+// WithFallbackOperatorsInfoService makes the service query the operators from fallback when the subgraph doesn't
+// have them, e.g. while it is lagging behind the chain, when it returns data not matching the expected schema or
+// invalid pubkeys, or when it can't be reached. fallback is typically an OperatorsInfoServiceInMemory looking up the
+// operators on chain.
+func WithFallbackOperatorsInfoService(fallback OperatorsInfoService) OperatorsInfoServiceSubgraphOption {
+	return func(ops *OperatorsInfoServiceSubgraph) {
+		ops.fallback = fallback
+	}
+}
+
+// WithOperatorsInfoCache caches the operators found in the subgraph for ttl, the sockets updated meanwhile being
+// served stale. The constructor then fills the cache with all the operators of the subgraph.
+func WithOperatorsInfoCache(ttl time.Duration) OperatorsInfoServiceSubgraphOption {
+	return func(ops *OperatorsInfoServiceSubgraph) {
+		ops.cacheTTL = ttl
+	}
+}
+
+// NewOperatorsInfoServiceSubgraph constructs a OperatorsInfoServiceSubgraph querying the operators from the subgraph
+// behind client. It takes a context as argument because, if the operators are cached (see WithOperatorsInfoCache),
+// the cache is filled with all the operators of the subgraph inside this constructor, the operators which couldn't be
+// queried being queried again when requested.
 func NewOperatorsInfoServiceSubgraph(
 	ctx context.Context,
 	client GraphQLQuerier,
 	logger logging.Logger,
+	opts ...OperatorsInfoServiceSubgraphOption,
 ) *OperatorsInfoServiceSubgraph {
-	return &OperatorsInfoServiceSubgraph{
-		logger: logger,
-		client: client,
-		name:   "OperatorsInfoServiceSubgraph",
+	ops := &OperatorsInfoServiceSubgraph{
+		logger:         logger,
+		client:         client,
+		name:           "OperatorsInfoServiceSubgraph",
+		maxRetries:     DefaultSubgraphQueryRetries,
+		initialBackoff: DefaultSubgraphQueryBackoff,
+		cache:          make(map[common.Address]cachedOperatorInfo),
+	}
+	for _, opt := range opts {
+		opt(ops)
 	}
+	if ops.cacheTTL > 0 {
+		if err := ops.fillCache(ctx); err != nil {
+			ops.logger.Warn("Failed to cache the operators of the subgraph", "service", ops.name, "err", err)
+		}
+	}
+	return ops
 }
 
+// GetOperatorInfo returns the info of the operator from the cache, or else from the subgraph. The operators the
+// subgraph doesn't have or returns invalid data for are queried from the fallback service, if any.
 // TODO(samlaf): we might want to also add an async version of this method that returns a channel of operator pubkeys?
 func (ops *OperatorsInfoServiceSubgraph) GetOperatorInfo(
 	ctx context.Context,
 	operator common.Address,
 ) (operatorPubkeys types.OperatorInfo, operatorFound bool) {
+	if operatorInfo, ok := ops.getCachedOperatorInfo(operator); ok {
+		return operatorInfo, true
+	}
 	operatorInfo, err := ops.getIndexedOperatorInfoByOperatorId(ctx, operator)
-	if err != nil {
+	if err == nil {
+		ops.cacheOperatorInfo(operator, *operatorInfo)
+		return *operatorInfo, true
+	}
+	if ops.fallback == nil {
 		return types.OperatorInfo{}, false
 	}
-	return *operatorInfo, true
+	ops.logger.Warn(
+		"Failed to get operator info from the subgraph, falling back",
+		"service", ops.name,
+		"operator", operator,
+		"err", err,
+	)
+	return ops.fallback.GetOperatorInfo(ctx, operator)
 }
 
 func (ops *OperatorsInfoServiceSubgraph) getIndexedOperatorInfoByOperatorId(
@@ -97,20 +191,113 @@ func (ops *OperatorsInfoServiceSubgraph) getIndexedOperatorInfoByOperatorId(
 			"id": graphql.String(fmt.Sprintf("0x%s", hex.EncodeToString(operator[:]))),
 		}
 	)
-	err := ops.client.Query(ctx, &query, variables)
+	err := ops.query(ctx, &query, variables)
 	if err != nil {
 		ops.logger.Error("Error requesting info for operator", "err", err, "operator", hex.EncodeToString(operator[:]))
 		return nil, err
 	}
+	if query.Operator.Address == "" {
+		return nil, errors.New("operator not found in the subgraph")
+	}
+	if indexedOperator := common.HexToAddress(string(query.Operator.Address)); indexedOperator != operator {
+		return nil, fmt.Errorf("subgraph returned operator %s", indexedOperator)
+	}
 
 	return convertIndexedOperatorInfoGqlToOperatorInfo(&query.Operator)
 }
 
+// fillCache caches all the operators of the subgraph, querying them page by page. The operators whose data is invalid
+// are skipped.
+func (ops *OperatorsInfoServiceSubgraph) fillCache(ctx context.Context) error {
+	lastId := ""
+	cachedOperators := 0
+	for {
+		var query QueryOperatorsGql
+		variables := map[string]any{
+			"first":  graphql.Int(subgraphOperatorsPageSize),
+			"lastId": graphql.String(lastId),
+		}
+		if err := ops.query(ctx, &query, variables); err != nil {
+			return utils.WrapError(fmt.Sprintf("failed to query the operators after %q", lastId), err)
+		}
+		for _, indexedOperator := range query.Operators {
+			operatorInfo, err := convertIndexedOperatorInfoGqlToOperatorInfo(&indexedOperator)
+			if err != nil {
+				ops.logger.Warn(
+					"Skipping invalid operator of the subgraph",
+					"service", ops.name,
+					"operator", indexedOperator.Address,
+					"err", err,
+				)
+				continue
+			}
+			ops.cacheOperatorInfo(common.HexToAddress(string(indexedOperator.Address)), *operatorInfo)
+			cachedOperators++
+		}
+		if len(query.Operators) < subgraphOperatorsPageSize {
+			ops.logger.Info("Cached the operators of the subgraph", "service", ops.name, "operators", cachedOperators)
+			return nil
+		}
+		lastId = string(query.Operators[len(query.Operators)-1].Id)
+	}
+}
+
+// query runs the query q on the subgraph, retrying it with exponential backoff when it fails
+func (ops *OperatorsInfoServiceSubgraph) query(ctx context.Context, q any, variables map[string]any) error {
+	backoff := ops.initialBackoff
+	for retry := 0; ; retry++ {
+		err := ops.client.Query(ctx, q, variables)
+		if err == nil || retry >= ops.maxRetries || ctx.Err() != nil {
+			return err
+		}
+		ops.logger.Debug("Retrying subgraph query", "service", ops.name, "retry", retry+1, "err", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, subgraphQueryMaxBackoff)
+	}
+}
+
+func (ops *OperatorsInfoServiceSubgraph) getCachedOperatorInfo(operator common.Address) (types.OperatorInfo, bool) {
+	if ops.cacheTTL <= 0 {
+		return types.OperatorInfo{}, false
+	}
+	ops.cacheMutex.RLock()
+	defer ops.cacheMutex.RUnlock()
+	cached, ok := ops.cache[operator]
+	if !ok || time.Since(cached.cachedAt) > ops.cacheTTL {
+		return types.OperatorInfo{}, false
+	}
+	return cached.operatorInfo, true
+}
+
+func (ops *OperatorsInfoServiceSubgraph) cacheOperatorInfo(operator common.Address, operatorInfo types.OperatorInfo) {
+	if ops.cacheTTL <= 0 {
+		return
+	}
+	ops.cacheMutex.Lock()
+	defer ops.cacheMutex.Unlock()
+	ops.cache[operator] = cachedOperatorInfo{operatorInfo: operatorInfo, cachedAt: time.Now()}
+}
+
+// convertIndexedOperatorInfoGqlToOperatorInfo returns the info of the indexed operator, or an error if it is partial
+// or its pubkeys aren't valid points of the bn254 subgroups, e.g. if the subgraph doesn't match the expected schema
 func convertIndexedOperatorInfoGqlToOperatorInfo(operator *IndexedOperatorInfoGql) (*types.OperatorInfo, error) {
 
 	if len(operator.SocketUpdates) == 0 {
 		return nil, errors.New("no socket found for operator")
 	}
+	if len(operator.PubkeyG2_X) != 2 || len(operator.PubkeyG2_Y) != 2 {
+		return nil, fmt.Errorf(
+			"expected 2 coordinates for each of pubkeyG2_X and pubkeyG2_Y, got %d and %d",
+			len(operator.PubkeyG2_X),
+			len(operator.PubkeyG2_Y),
+		)
+	}
 
 	pubkeyG1 := new(bn254.G1Affine)
 	_, err := pubkeyG1.X.SetString(string(operator.PubkeyG1_X))
@@ -139,6 +326,12 @@ func convertIndexedOperatorInfoGqlToOperatorInfo(operator *IndexedOperatorInfoGq
 	if err != nil {
 		return nil, err
 	}
+	if !pubkeyG1.IsOnCurve() || !pubkeyG1.IsInSubGroup() || pubkeyG1.IsInfinity() {
+		return nil, errors.New("invalid G1 pubkey")
+	}
+	if !pubkeyG2.IsOnCurve() || !pubkeyG2.IsInSubGroup() || pubkeyG2.IsInfinity() {
+		return nil, errors.New("invalid G2 pubkey")
+	}
 
 	return &types.OperatorInfo{
 		Socket: types.Socket(string(operator.SocketUpdates[0].Socket)),
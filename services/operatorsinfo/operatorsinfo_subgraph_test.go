@@ -2,13 +2,24 @@ package operatorsinfo
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/testutils"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/shurcooL/graphql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mockGraphQLQuerier struct {
@@ -61,3 +72,243 @@ func TestIndexedChainState_GetIndexedOperatorState(t *testing.T) {
 	assert.True(t, success)
 	assert.Equal(t, operatorPubkeys.Socket, types.Socket("localhost:32006;32007"))
 }
+
+// fakeSubgraph serves canned GraphQL responses for the operators queries of an OperatorsInfoServiceSubgraph
+type fakeSubgraph struct {
+	// operators are the operators of the subgraph, ordered by id
+	operators []map[string]any
+	// failures is the number of requests answered with an error before the subgraph recovers
+	failures int
+	// operatorResponse overrides the response to the queries of a single operator, if set
+	operatorResponse string
+
+	mutex     sync.Mutex
+	requests  int
+	pageSizes []int
+}
+
+func (s *fakeSubgraph) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests++
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "indexer unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if strings.Contains(req.Query, "operators(") {
+		lastId := req.Variables["lastId"].(string)
+		first := int(req.Variables["first"].(float64))
+		page := []map[string]any{}
+		for _, operator := range s.operators {
+			if operator["id"].(string) > lastId && len(page) < first {
+				page = append(page, operator)
+			}
+		}
+		s.pageSizes = append(s.pageSizes, len(page))
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"operators": page}})
+		return
+	}
+	if s.operatorResponse != "" {
+		_, _ = io.WriteString(w, s.operatorResponse)
+		return
+	}
+	var found map[string]any
+	for _, operator := range s.operators {
+		if operator["id"] == req.Variables["id"] {
+			found = operator
+		}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"operator": found}})
+}
+
+func (s *fakeSubgraph) requestCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+// newIndexedOperator returns the subgraph entity of an operator with pubkeys and socket
+func newIndexedOperator(operatorAddr common.Address, pubkeys types.OperatorPubkeys, socket string) map[string]any {
+	id := strings.ToLower(operatorAddr.Hex())
+	return map[string]any{
+		"id":         id,
+		"address":    id,
+		"pubkeyG1_X": pubkeys.G1Pubkey.X.String(),
+		"pubkeyG1_Y": pubkeys.G1Pubkey.Y.String(),
+		"pubkeyG2_X": []string{pubkeys.G2Pubkey.X.A1.String(), pubkeys.G2Pubkey.X.A0.String()},
+		"pubkeyG2_Y": []string{pubkeys.G2Pubkey.Y.A1.String(), pubkeys.G2Pubkey.Y.A0.String()},
+		"socketUpdates": []map[string]any{
+			{"socket": socket},
+		},
+	}
+}
+
+// fallbackOperatorsInfoService serves its operators, recording the operators requested
+type fallbackOperatorsInfoService struct {
+	operators map[common.Address]types.OperatorInfo
+	requested []common.Address
+}
+
+func (f *fallbackOperatorsInfoService) GetOperatorInfo(
+	ctx context.Context,
+	operator common.Address,
+) (types.OperatorInfo, bool) {
+	f.requested = append(f.requested, operator)
+	operatorInfo, ok := f.operators[operator]
+	return operatorInfo, ok
+}
+
+func TestOperatorsInfoServiceSubgraph(t *testing.T) {
+	logger := testutils.GetTestLogger()
+	keyPair, err := bls.GenRandomBlsKeys()
+	require.NoError(t, err)
+	pubkeys := types.OperatorPubkeys{G1Pubkey: keyPair.GetPubKeyG1(), G2Pubkey: keyPair.GetPubKeyG2()}
+	operatorAddr := common.HexToAddress("0x1")
+	fallbackOperatorInfo := types.OperatorInfo{Pubkeys: pubkeys, Socket: "fallback:8080"}
+
+	// invalidOperator returns an operator whose data is modified by modify
+	invalidOperator := func(modify func(operator map[string]any)) map[string]any {
+		operator := newIndexedOperator(operatorAddr, pubkeys, "localhost:8080")
+		modify(operator)
+		return operator
+	}
+	var tests = []struct {
+		name             string
+		operators        []map[string]any
+		failures         int
+		operatorResponse string
+		wantOperatorInfo types.OperatorInfo
+		wantFallback     bool
+	}{
+		{
+			name:             "operator in the subgraph",
+			operators:        []map[string]any{newIndexedOperator(operatorAddr, pubkeys, "localhost:8080")},
+			wantOperatorInfo: types.OperatorInfo{Pubkeys: pubkeys, Socket: "localhost:8080"},
+		},
+		{
+			name:             "operator in the subgraph once it recovers",
+			operators:        []map[string]any{newIndexedOperator(operatorAddr, pubkeys, "localhost:8080")},
+			failures:         2,
+			wantOperatorInfo: types.OperatorInfo{Pubkeys: pubkeys, Socket: "localhost:8080"},
+		},
+		{
+			name:             "subgraph unavailable",
+			failures:         10,
+			wantOperatorInfo: fallbackOperatorInfo,
+			wantFallback:     true,
+		},
+		{
+			name:             "operator not in the subgraph",
+			wantOperatorInfo: fallbackOperatorInfo,
+			wantFallback:     true,
+		},
+		{
+			name: "operator without socket",
+			operators: []map[string]any{invalidOperator(func(operator map[string]any) {
+				operator["socketUpdates"] = []map[string]any{}
+			})},
+			wantOperatorInfo: fallbackOperatorInfo,
+			wantFallback:     true,
+		},
+		{
+			name: "operator with partial G2 pubkey",
+			operators: []map[string]any{invalidOperator(func(operator map[string]any) {
+				operator["pubkeyG2_X"] = []string{pubkeys.G2Pubkey.X.A1.String()}
+			})},
+			wantOperatorInfo: fallbackOperatorInfo,
+			wantFallback:     true,
+		},
+		{
+			name: "operator with G1 pubkey not on the curve",
+			operators: []map[string]any{invalidOperator(func(operator map[string]any) {
+				operator["pubkeyG1_Y"] = "1"
+			})},
+			wantOperatorInfo: fallbackOperatorInfo,
+			wantFallback:     true,
+		},
+		{
+			name:             "schema mismatch",
+			operatorResponse: `{"data":{"operator":{"id":"0x1","address":"0x1","pubkeyG1":{"X":"1","Y":"2"}}}}`,
+			wantOperatorInfo: fallbackOperatorInfo,
+			wantFallback:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subgraph := &fakeSubgraph{
+				operators:        tt.operators,
+				failures:         tt.failures,
+				operatorResponse: tt.operatorResponse,
+			}
+			server := httptest.NewServer(subgraph)
+			defer server.Close()
+			fallback := &fallbackOperatorsInfoService{
+				operators: map[common.Address]types.OperatorInfo{operatorAddr: fallbackOperatorInfo},
+			}
+			service := NewOperatorsInfoServiceSubgraph(
+				context.Background(),
+				graphql.NewClient(server.URL, server.Client()),
+				logger,
+				WithSubgraphQueryRetries(2, time.Millisecond),
+				WithFallbackOperatorsInfoService(fallback),
+			)
+
+			gotOperatorInfo, gotOperatorFound := service.GetOperatorInfo(context.Background(), operatorAddr)
+			require.True(t, gotOperatorFound)
+			require.Equal(t, tt.wantOperatorInfo, gotOperatorInfo)
+			require.Equal(t, tt.wantFallback, len(fallback.requested) == 1)
+		})
+	}
+}
+
+func TestOperatorsInfoServiceSubgraphCache(t *testing.T) {
+	logger := testutils.GetTestLogger()
+	keyPair, err := bls.GenRandomBlsKeys()
+	require.NoError(t, err)
+	pubkeys := types.OperatorPubkeys{G1Pubkey: keyPair.GetPubKeyG1(), G2Pubkey: keyPair.GetPubKeyG2()}
+	// the operators span two pages, all sharing the same pubkeys, and one of them is invalid
+	subgraph := &fakeSubgraph{}
+	for i := 1; i <= subgraphOperatorsPageSize+10; i++ {
+		operatorAddr := common.BigToAddress(big.NewInt(int64(i)))
+		subgraph.operators = append(subgraph.operators, newIndexedOperator(operatorAddr, pubkeys, "localhost:8080"))
+	}
+	invalidOperatorAddr := common.BigToAddress(big.NewInt(int64(subgraphOperatorsPageSize + 10)))
+	subgraph.operators[len(subgraph.operators)-1]["pubkeyG2_Y"] = []string{}
+	server := httptest.NewServer(subgraph)
+	defer server.Close()
+
+	service := NewOperatorsInfoServiceSubgraph(
+		context.Background(),
+		graphql.NewClient(server.URL, server.Client()),
+		logger,
+		WithOperatorsInfoCache(time.Hour),
+	)
+	require.Equal(t, []int{subgraphOperatorsPageSize, 10}, subgraph.pageSizes)
+	requests := subgraph.requestCount()
+
+	// the cached operators are served without querying the subgraph
+	for _, i := range []int64{1, subgraphOperatorsPageSize, subgraphOperatorsPageSize + 1} {
+		gotOperatorInfo, gotOperatorFound := service.GetOperatorInfo(
+			context.Background(),
+			common.BigToAddress(big.NewInt(i)),
+		)
+		require.True(t, gotOperatorFound)
+		require.Equal(t, types.OperatorInfo{Pubkeys: pubkeys, Socket: "localhost:8080"}, gotOperatorInfo)
+	}
+	require.Equal(t, requests, subgraph.requestCount())
+
+	// the invalid operator wasn't cached, so it's queried again
+	_, gotOperatorFound := service.GetOperatorInfo(context.Background(), invalidOperatorAddr)
+	require.False(t, gotOperatorFound)
+	require.Greater(t, subgraph.requestCount(), requests)
+}
@@ -0,0 +1,159 @@
+package signerv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrSigningRejected is matched by the errors of the approval signers when their approver doesn't approve a
+// transaction, which are *SigningRejectedError holding the reason
+var ErrSigningRejected = errors.New("signing rejected")
+
+// SigningRejectedError is returned by the approval signers when their approver doesn't approve a transaction
+type SigningRejectedError struct {
+	// Reason is the reason of the rejection, e.g. the policy rule violated by the transaction
+	Reason string
+	// Err is the error returned by the approver, e.g. context.DeadlineExceeded when it timed out
+	Err error
+}
+
+func (e *SigningRejectedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrSigningRejected, e.Reason)
+}
+
+func (e *SigningRejectedError) Is(target error) bool {
+	return target == ErrSigningRejected
+}
+
+func (e *SigningRejectedError) Unwrap() error {
+	return e.Err
+}
+
+// Approver approves a transaction before it's signed by returning nil, e.g. after a human approved it or after
+// checking it against a policy. Any error rejects the transaction.
+type Approver func(ctx context.Context, tx *types.Transaction) error
+
+// ApprovalSignerOption configures the signer returned by NewApprovalSigner
+type ApprovalSignerOption func(*approvalSignerOptions)
+
+type approvalSignerOptions struct {
+	timeout time.Duration
+}
+
+// WithApprovalTimeout sets the time the approver has to approve each transaction, after which its context is
+// cancelled and the transaction is rejected. The approver has no timeout unless set.
+func WithApprovalTimeout(timeout time.Duration) ApprovalSignerOption {
+	return func(o *approvalSignerOptions) {
+		o.timeout = timeout
+	}
+}
+
+// NewApprovalSigner returns a SignerFn whose transactions are signed by inner only once approver approved them.
+// Otherwise the signer returns a *SigningRejectedError, and inner doesn't sign. The approver is called with the
+// context of the SignerFn call.
+func NewApprovalSigner(inner SignerFn, approver Approver, opts ...ApprovalSignerOption) SignerFn {
+	var options approvalSignerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(ctx context.Context, address common.Address) (bind.SignerFn, error) {
+		innerSigner, err := inner(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if err := approve(ctx, approver, tx, options.timeout); err != nil {
+				return nil, err
+			}
+			return innerSigner(address, tx)
+		}, nil
+	}
+}
+
+// approve calls approver with timeout, if set, returning a *SigningRejectedError unless tx is approved. The
+// transaction is rejected once ctx is done, even if the approver doesn't return.
+func approve(ctx context.Context, approver Approver, tx *types.Transaction, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	approved := make(chan error, 1)
+	go func() {
+		approved <- approver(ctx, tx)
+	}()
+	var err error
+	select {
+	case err = <-approved:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err == nil {
+		return nil
+	}
+	var rejectedErr *SigningRejectedError
+	if errors.As(err, &rejectedErr) {
+		return err
+	}
+	reason := err.Error()
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = fmt.Sprintf("approval of tx %s timed out", tx.Hash().Hex())
+	}
+	return &SigningRejectedError{Reason: reason, Err: err}
+}
+
+// ApprovalPolicy is a policy of the transactions approved by its Approve method. Its unset rules allow any
+// transaction.
+type ApprovalPolicy struct {
+	// AllowedTargets are the addresses the transactions may be sent to, which rejects the contract creations
+	AllowedTargets []common.Address
+	// MaxGasFee is the maximum fee in wei the transactions may pay for their gas, i.e. their gas limit times their max
+	// fee per gas, plus the same for their blob gas
+	MaxGasFee *big.Int
+	// MaxValue is the maximum value in wei the transactions may send
+	MaxValue *big.Int
+}
+
+// Approve is the Approver of the policy, returning a *SigningRejectedError holding the violated rule
+func (p ApprovalPolicy) Approve(ctx context.Context, tx *types.Transaction) error {
+	if len(p.AllowedTargets) > 0 {
+		if tx.To() == nil {
+			return &SigningRejectedError{Reason: "contract creation is not allowed"}
+		}
+		if !p.isAllowedTarget(*tx.To()) {
+			return &SigningRejectedError{Reason: fmt.Sprintf("target %s is not allowed", tx.To().Hex())}
+		}
+	}
+	if p.MaxGasFee != nil {
+		// the cost of the transaction is its value plus its max gas fee
+		gasFee := new(big.Int).Sub(tx.Cost(), tx.Value())
+		if gasFee.Cmp(p.MaxGasFee) > 0 {
+			return &SigningRejectedError{
+				Reason: fmt.Sprintf("max gas fee %s wei exceeds the maximum of %s wei", gasFee, p.MaxGasFee),
+			}
+		}
+	}
+	if p.MaxValue != nil && tx.Value().Cmp(p.MaxValue) > 0 {
+		return &SigningRejectedError{
+			Reason: fmt.Sprintf("value %s wei exceeds the maximum of %s wei", tx.Value(), p.MaxValue),
+		}
+	}
+	return nil
+}
+
+func (p ApprovalPolicy) isAllowedTarget(target common.Address) bool {
+	for _, allowed := range p.AllowedTargets {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package signerv2_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingSigner returns a private key SignerFn counting the transactions it signed, and its address
+func newCountingSigner(t *testing.T, chainID *big.Int) (signerv2.SignerFn, common.Address, *int) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signed := new(int)
+	signer := func(ctx context.Context, address common.Address) (bind.SignerFn, error) {
+		signerFn, err := signerv2.PrivateKeySignerFn(privateKey, chainID)
+		if err != nil {
+			return nil, err
+		}
+		return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			*signed++
+			return signerFn(address, tx)
+		}, nil
+	}
+	return signer, crypto.PubkeyToAddress(privateKey.PublicKey), signed
+}
+
+func TestApprovalSignerPolicy(t *testing.T) {
+	chainID := big.NewInt(1)
+	target := common.HexToAddress("0x1234")
+	policy := signerv2.ApprovalPolicy{
+		AllowedTargets: []common.Address{target},
+		// 100000 gas at 10 gwei
+		MaxGasFee: big.NewInt(1e15),
+		MaxValue:  big.NewInt(1e18),
+	}
+
+	var tests = map[string]struct {
+		tx         *types.DynamicFeeTx
+		wantReason string
+	}{
+		"allowed": {
+			tx: &types.DynamicFeeTx{To: &target, Gas: 100000, GasFeeCap: big.NewInt(1e10), Value: big.NewInt(1e18)},
+		},
+		"target not allowed": {
+			tx: &types.DynamicFeeTx{
+				To:        &common.Address{0x1},
+				Gas:       100000,
+				GasFeeCap: big.NewInt(1e10),
+				Value:     big.NewInt(0),
+			},
+			wantReason: "target 0x0100000000000000000000000000000000000000 is not allowed",
+		},
+		"contract creation": {
+			tx:         &types.DynamicFeeTx{Gas: 100000, GasFeeCap: big.NewInt(1e10), Value: big.NewInt(0)},
+			wantReason: "contract creation is not allowed",
+		},
+		"gas fee too high": {
+			tx: &types.DynamicFeeTx{
+				To:        &target,
+				Gas:       100001,
+				GasFeeCap: big.NewInt(1e10),
+				Value:     big.NewInt(0),
+			},
+			wantReason: "max gas fee 1000010000000000 wei exceeds the maximum of 1000000000000000 wei",
+		},
+		"value too high": {
+			tx: &types.DynamicFeeTx{
+				To:        &target,
+				Gas:       21000,
+				GasFeeCap: big.NewInt(1e10),
+				Value:     big.NewInt(1e18 + 1),
+			},
+			wantReason: "value 1000000000000000001 wei exceeds the maximum of 1000000000000000000 wei",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			inner, address, signed := newCountingSigner(t, chainID)
+			signer, err := signerv2.NewApprovalSigner(inner, policy.Approve)(context.Background(), address)
+			require.NoError(t, err)
+
+			tt.tx.ChainID = chainID
+			signedTx, err := signer(address, types.NewTx(tt.tx))
+			if tt.wantReason != "" {
+				require.ErrorIs(t, err, signerv2.ErrSigningRejected)
+				var rejectedErr *signerv2.SigningRejectedError
+				require.ErrorAs(t, err, &rejectedErr)
+				assert.Equal(t, tt.wantReason, rejectedErr.Reason)
+				assert.Zero(t, *signed)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, *signed)
+			from, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+			require.NoError(t, err)
+			assert.Equal(t, address, from)
+		})
+	}
+}
+
+func TestApprovalSignerApprover(t *testing.T) {
+	chainID := big.NewInt(1)
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, To: &common.Address{0x1}, Value: big.NewInt(0)})
+
+	t.Run("approver error", func(t *testing.T) {
+		inner, address, signed := newCountingSigner(t, chainID)
+		approverErr := errors.New("denied by the operator")
+		approver := func(ctx context.Context, tx *types.Transaction) error {
+			return approverErr
+		}
+		signer, err := signerv2.NewApprovalSigner(inner, approver)(context.Background(), address)
+		require.NoError(t, err)
+
+		_, err = signer(address, tx)
+		require.ErrorIs(t, err, signerv2.ErrSigningRejected)
+		require.ErrorIs(t, err, approverErr)
+		var rejectedErr *signerv2.SigningRejectedError
+		require.ErrorAs(t, err, &rejectedErr)
+		assert.Equal(t, "denied by the operator", rejectedErr.Reason)
+		assert.Zero(t, *signed)
+	})
+
+	t.Run("approver timeout", func(t *testing.T) {
+		inner, address, signed := newCountingSigner(t, chainID)
+		// the approver never returns, e.g. waiting for a human approval, ignoring its context
+		blocked := make(chan struct{})
+		defer close(blocked)
+		approver := func(ctx context.Context, tx *types.Transaction) error {
+			<-blocked
+			return nil
+		}
+		signer, err := signerv2.NewApprovalSigner(
+			inner,
+			approver,
+			signerv2.WithApprovalTimeout(10*time.Millisecond),
+		)(context.Background(), address)
+		require.NoError(t, err)
+
+		_, err = signer(address, tx)
+		require.ErrorIs(t, err, signerv2.ErrSigningRejected)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		var rejectedErr *signerv2.SigningRejectedError
+		require.ErrorAs(t, err, &rejectedErr)
+		assert.Contains(t, rejectedErr.Reason, "timed out")
+		assert.Zero(t, *signed)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		inner, address, signed := newCountingSigner(t, chainID)
+		approver := func(ctx context.Context, tx *types.Transaction) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		signer, err := signerv2.NewApprovalSigner(inner, approver)(ctx, address)
+		require.NoError(t, err)
+		cancel()
+
+		_, err = signer(address, tx)
+		require.ErrorIs(t, err, signerv2.ErrSigningRejected)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, *signed)
+	})
+
+	t.Run("approved", func(t *testing.T) {
+		inner, address, signed := newCountingSigner(t, chainID)
+		var approvedTx *types.Transaction
+		approver := func(ctx context.Context, tx *types.Transaction) error {
+			approvedTx = tx
+			return nil
+		}
+		signer, err := signerv2.NewApprovalSigner(
+			inner,
+			approver,
+			signerv2.WithApprovalTimeout(time.Second),
+		)(context.Background(), address)
+		require.NoError(t, err)
+
+		_, err = signer(address, tx)
+		require.NoError(t, err)
+		assert.Equal(t, tx.Hash(), approvedTx.Hash())
+		assert.Equal(t, 1, *signed)
+	})
+}
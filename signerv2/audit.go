@@ -0,0 +1,119 @@
+package signerv2
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultAuditTimeout is the time the audit logger has to log each record unless set with WithAuditTimeout
+const DefaultAuditTimeout = time.Second
+
+// SignRecord is the audit record of a transaction signed by a signer, see WithAuditLogger
+type SignRecord struct {
+	Timestamp time.Time      `json:"timestamp"`
+	ChainID   *big.Int       `json:"chainId"`
+	From      common.Address `json:"from"`
+	// To is nil for the contract creations
+	To    *common.Address `json:"to"`
+	Nonce uint64          `json:"nonce"`
+	Value *big.Int        `json:"value"`
+	Gas   uint64          `json:"gas"`
+	// GasPrice is the gas price of the legacy and access list transactions, and the max fee per gas of the others
+	GasPrice *big.Int `json:"gasPrice"`
+	// GasTipCap is the max priority fee per gas, equal to GasPrice for the legacy and access list transactions
+	GasTipCap    *big.Int    `json:"gasTipCap"`
+	CalldataHash common.Hash `json:"calldataHash"`
+	TxHash       common.Hash `json:"txHash"`
+}
+
+// NewSignRecord returns the record of signedTx signed on behalf of from at timestamp
+func NewSignRecord(timestamp time.Time, from common.Address, signedTx *types.Transaction) SignRecord {
+	return SignRecord{
+		Timestamp:    timestamp,
+		ChainID:      signedTx.ChainId(),
+		From:         from,
+		To:           signedTx.To(),
+		Nonce:        signedTx.Nonce(),
+		Value:        signedTx.Value(),
+		Gas:          signedTx.Gas(),
+		GasPrice:     signedTx.GasFeeCap(),
+		GasTipCap:    signedTx.GasTipCap(),
+		CalldataHash: crypto.Keccak256Hash(signedTx.Data()),
+		TxHash:       signedTx.Hash(),
+	}
+}
+
+// SignerOption configures the signers returned by SignerFromConfig and NewAuditSigner
+type SignerOption func(*signerOptions)
+
+type signerOptions struct {
+	auditLogger  func(record SignRecord)
+	auditTimeout time.Duration
+}
+
+// WithAuditLogger sets the function called with the record of each transaction signed by the signer, e.g. the Log
+// method of a JSONLinesAuditLogger. It's only called once a transaction is signed, and the signer waits for it to
+// return for at most the audit timeout, see WithAuditTimeout.
+func WithAuditLogger(auditLogger func(record SignRecord)) SignerOption {
+	return func(o *signerOptions) {
+		o.auditLogger = auditLogger
+	}
+}
+
+// WithAuditTimeout sets the time the signer waits for the audit logger to log each record, DefaultAuditTimeout
+// unless set. The signed transaction is returned once the timeout expires, while the audit logger keeps logging the
+// record.
+func WithAuditTimeout(timeout time.Duration) SignerOption {
+	return func(o *signerOptions) {
+		o.auditTimeout = timeout
+	}
+}
+
+// NewAuditSigner returns a SignerFn signing with inner, configured by opts, e.g. logging the record of each signed
+// transaction with the audit logger set by WithAuditLogger. It's used by SignerFromConfig to configure its signers.
+func NewAuditSigner(inner SignerFn, opts ...SignerOption) SignerFn {
+	options := signerOptions{auditTimeout: DefaultAuditTimeout}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.auditLogger == nil {
+		return inner
+	}
+
+	return func(ctx context.Context, address common.Address) (bind.SignerFn, error) {
+		innerSigner, err := inner(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			signedTx, err := innerSigner(address, tx)
+			if err != nil {
+				return nil, err
+			}
+			logSignRecord(options, NewSignRecord(time.Now().UTC(), address, signedTx))
+			return signedTx, nil
+		}, nil
+	}
+}
+
+// logSignRecord calls the audit logger of options with record, returning once it returned or the audit timeout
+// expired
+func logSignRecord(options signerOptions, record SignRecord) {
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		options.auditLogger(record)
+	}()
+	timer := time.NewTimer(options.auditTimeout)
+	defer timer.Stop()
+	select {
+	case <-logged:
+	case <-timer.C:
+	}
+}
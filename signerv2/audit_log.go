@@ -0,0 +1,116 @@
+package signerv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// rotatedAuditLogTimeFormat is the format of the time appended to the path of the rotated audit logs, which sorts
+// them by time
+const rotatedAuditLogTimeFormat = "20060102T150405.000000000Z"
+
+// JSONLinesAuditLogger appends the records of the signed transactions to a file, one JSON object per line. The file
+// is rotated once it would exceed its max size: it's renamed to its path suffixed with the time of the rotation, and a
+// new file is created at its path.
+type JSONLinesAuditLogger struct {
+	path    string
+	maxSize int64
+	logger  logging.Logger
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewJSONLinesAuditLogger opens the audit log at path, creating it if it doesn't exist, whose files are rotated once
+// they would exceed maxSize bytes. The records which can't be written are logged to logger, or to stderr if nil.
+func NewJSONLinesAuditLogger(path string, maxSize int64, logger logging.Logger) (*JSONLinesAuditLogger, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("audit log max size must be positive, got %d", maxSize)
+	}
+	if logger == nil {
+		logger = logging.NewTextSLogger(os.Stderr, nil)
+	}
+	l := &JSONLinesAuditLogger{path: path, maxSize: maxSize, logger: logger}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Log appends record to the audit log. It's the audit logger of the signers, see WithAuditLogger.
+func (l *JSONLinesAuditLogger) Log(record SignRecord) {
+	if err := l.write(record); err != nil {
+		l.logger.Error("Failed to write sign record to the audit log", "path", l.path, "txHash", record.TxHash.Hex(),
+			"record", record, "err", err)
+	}
+}
+
+// Close closes the file of the audit log
+func (l *JSONLinesAuditLogger) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func (l *JSONLinesAuditLogger) write(record SignRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("audit log %s is closed", l.path)
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// rotate renames the file of the audit log and opens a new one at its path
+func (l *JSONLinesAuditLogger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+	rotatedPath := fmt.Sprintf("%s.%s", l.path, time.Now().UTC().Format(rotatedAuditLogTimeFormat))
+	if err := os.Rename(l.path, rotatedPath); err != nil {
+		return fmt.Errorf("failed to rotate audit log %s: %w", l.path, err)
+	}
+	return l.open()
+}
+
+func (l *JSONLinesAuditLogger) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", l.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open audit log %s: %w", l.path, err)
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
@@ -0,0 +1,241 @@
+package signerv2_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWeb3Signer starts a web3signer responding to eth_signTransaction with signedTx, or with a JSON RPC error unless
+// the transaction is sent from the sender of signedTx
+func newWeb3Signer(t *testing.T, from common.Address, signedTx *types.Transaction) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     string              `json:"id"`
+			Params []map[string]string `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		if common.HexToAddress(request.Params[0]["from"]) != from {
+			response["error"] = map[string]interface{}{"code": -32000, "message": "signing key not found"}
+		} else {
+			rawTx, err := rlp.EncodeToBytes(signedTx)
+			require.NoError(t, err)
+			response["result"] = hexutil.Encode(rawTx)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// auditRecords collects the records of the audit logger
+type auditRecords struct {
+	lock    sync.Mutex
+	records []signerv2.SignRecord
+}
+
+func (r *auditRecords) log(record signerv2.SignRecord) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.records = append(r.records, record)
+}
+
+func (r *auditRecords) get() []signerv2.SignRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]signerv2.SignRecord(nil), r.records...)
+}
+
+func TestSignerAuditLogger(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	chainID := big.NewInt(31337)
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	data := common.Hex2Bytes("6057361d00000000000000000000000000000000000000000000000000000000000f4240")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       50000,
+		To:        &to,
+		Value:     big.NewInt(1e18),
+		Data:      data,
+	})
+	web3SignedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+	require.NoError(t, err)
+
+	var tests = map[string]signerv2.Config{
+		"private key": {PrivateKey: privateKey},
+		"kms":         {KMSKeyID: "key", KMSClient: &fakeKMSClient{key: privateKey}},
+		"web3signer":  {Endpoint: newWeb3Signer(t, address, web3SignedTx).URL, Address: address.Hex()},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			records := &auditRecords{}
+			signerFn, signerAddress, err := signerv2.SignerFromConfig(
+				config,
+				chainID,
+				signerv2.WithAuditLogger(records.log),
+			)
+			require.NoError(t, err)
+			require.Equal(t, address, signerAddress)
+			signer, err := signerFn(context.Background(), address)
+			require.NoError(t, err)
+
+			before := time.Now()
+			signedTx, err := signer(address, tx)
+			require.NoError(t, err)
+			from, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+			require.NoError(t, err)
+			require.Equal(t, address, from)
+
+			require.Len(t, records.get(), 1)
+			record := records.get()[0]
+			assert.WithinRange(t, record.Timestamp, before, time.Now())
+			assert.Equal(t, signerv2.SignRecord{
+				Timestamp:    record.Timestamp,
+				ChainID:      chainID,
+				From:         address,
+				To:           &to,
+				Nonce:        7,
+				Value:        big.NewInt(1e18),
+				Gas:          50000,
+				GasPrice:     big.NewInt(2e9),
+				GasTipCap:    big.NewInt(1e9),
+				CalldataHash: crypto.Keccak256Hash(data),
+				TxHash:       signedTx.Hash(),
+			}, record)
+
+			// the transactions which aren't signed aren't logged
+			_, err = signer(common.Address{0x1}, tx)
+			require.Error(t, err)
+			assert.Len(t, records.get(), 1)
+		})
+	}
+}
+
+func TestSignerAuditLoggerTimeout(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	blocked := make(chan struct{})
+	defer close(blocked)
+	logged := make(chan signerv2.SignRecord, 1)
+	signerFn, _, err := signerv2.SignerFromConfig(
+		signerv2.Config{PrivateKey: privateKey},
+		big.NewInt(1),
+		signerv2.WithAuditLogger(func(record signerv2.SignRecord) {
+			<-blocked
+			logged <- record
+		}),
+		signerv2.WithAuditTimeout(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	signer, err := signerFn(context.Background(), address)
+	require.NoError(t, err)
+
+	start := time.Now()
+	signedTx, err := signer(address, types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), Value: big.NewInt(0)}))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the audit logger still logs the record once it's unblocked
+	blocked <- struct{}{}
+	assert.Equal(t, signedTx.Hash(), (<-logged).TxHash)
+}
+
+// readAuditLog returns the transaction hashes of the records of the audit log at path
+func readAuditLog(t *testing.T, path string) []common.Hash {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var hashes []common.Hash
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record signerv2.SignRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		hashes = append(hashes, record.TxHash)
+	}
+	require.NoError(t, scanner.Err())
+	return hashes
+}
+
+func TestJSONLinesAuditLogger(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(1)
+
+	// the records have the same length
+	timestamp := time.Unix(1700000000, 0).UTC()
+	var records []signerv2.SignRecord
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		signedTx, err := types.SignNewTx(privateKey, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+			ChainID: chainID,
+			Nonce:   nonce,
+			Value:   big.NewInt(0),
+		})
+		require.NoError(t, err)
+		records = append(records, signerv2.NewSignRecord(timestamp, common.Address{0x1}, signedTx))
+	}
+	line, err := json.Marshal(records[0])
+	require.NoError(t, err)
+
+	// each file holds 2 records
+	auditLogger, err := signerv2.NewJSONLinesAuditLogger(path, int64(2*len(line)+2), nil)
+	require.NoError(t, err)
+	for _, record := range records[:3] {
+		auditLogger.Log(record)
+	}
+	require.NoError(t, auditLogger.Close())
+	// the audit log is appended to when reopened
+	auditLogger, err = signerv2.NewJSONLinesAuditLogger(path, int64(2*len(line)+2), nil)
+	require.NoError(t, err)
+	for _, record := range records[3:] {
+		auditLogger.Log(record)
+	}
+	require.NoError(t, auditLogger.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	rotated, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	require.Len(t, rotated, 2)
+	// the rotated files sort by time
+	var hashes []common.Hash
+	for _, rotatedPath := range append(rotated, path) {
+		hashes = append(hashes, readAuditLog(t, rotatedPath)...)
+	}
+	require.Len(t, hashes, len(records))
+	for i, record := range records {
+		assert.Equal(t, record.TxHash, hashes[i])
+	}
+
+	_, err = signerv2.NewJSONLinesAuditLogger(path, 0, nil)
+	require.Error(t, err)
+	_, err = signerv2.NewJSONLinesAuditLogger(filepath.Join(dir, "missing", "audit.jsonl"), 1024, nil)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -1,6 +1,12 @@
 package signerv2
 
-import "crypto/ecdsa"
+import (
+	"crypto/ecdsa"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	eigenkms "github.com/Layr-Labs/eigensdk-go/aws/kms"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
 
 type Config struct {
 	PrivateKey   *ecdsa.PrivateKey
@@ -8,6 +14,22 @@ type Config struct {
 	Password     string
 	Endpoint     string
 	Address      string
+	// Web3SignerTLS configures the TLS connection to the web3signer at Endpoint, e.g. for mutual TLS
+	Web3SignerTLS *Web3SignerTLSConfig
+	// KMSKeyID is the ID of the AWS KMS key to sign with, see KMSSignerFn
+	KMSKeyID string
+	// KMSRegion is the region of the KMS key, whose client is created with the default AWS config
+	KMSRegion string
+	// KMSClient is the client of the KMS key, used instead of the one of KMSRegion if set
+	KMSClient eigenkms.Client
+	// GCPKMSKeyName is the resource name of the Cloud KMS key version to sign with, see GCPKMSSignerFn
+	GCPKMSKeyName string
+	// GCPKMSClient is the Cloud KMS client of the key, which is created with the application default credentials
+	// unless set
+	GCPKMSClient *kms.KeyManagementClient
+	// Logger logs the warnings of the signers, e.g. when the TLS certificate of web3signer isn't verified, which are
+	// logged to stderr unless set
+	Logger logging.Logger
 }
 
 func (c Config) IsPrivateKeySigner() bool {
@@ -24,3 +46,23 @@ func (c Config) IsWeb3Signer() bool {
 	}
 	return true
 }
+
+func (c Config) IsKMSSigner() bool {
+	return c.KMSKeyID != ""
+}
+
+func (c Config) IsGCPKMSSigner() bool {
+	return c.GCPKMSKeyName != ""
+}
+
+// web3SignerOptions returns the options of the connection to the web3signer of c
+func (c Config) web3SignerOptions() ([]Web3SignerOption, error) {
+	if c.Web3SignerTLS == nil {
+		return nil, nil
+	}
+	tlsConfig, err := c.Web3SignerTLS.TLSConfig(c.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return []Web3SignerOption{WithWeb3SignerTLSConfig(tlsConfig)}, nil
+}
@@ -0,0 +1,105 @@
+package signerv2
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// The typed data of the EIP-712 signatures checked by the EigenLayer core contracts, whose domain has the name
+// "EigenLayer" and no version.
+// https://github.com/Layr-Labs/eigenlayer-contracts/blob/dev/docs/core/DelegationManager.md
+
+const eigenLayerDomainName = "EigenLayer"
+
+var (
+	// delegationApprovalTypes are the types of the DelegationApproval struct signed by the delegation approver of an
+	// operator, see DelegationManager.calculateDelegationApprovalDigestHash
+	delegationApprovalTypes = apitypes.Types{
+		"EIP712Domain": eigenLayerDomainType,
+		"DelegationApproval": {
+			{Name: "delegationApprover", Type: "address"},
+			{Name: "staker", Type: "address"},
+			{Name: "operator", Type: "address"},
+			{Name: "salt", Type: "bytes32"},
+			{Name: "expiry", Type: "uint256"},
+		},
+	}
+	// operatorAVSRegistrationTypes are the types of the OperatorAVSRegistration struct signed by an operator
+	// registering to an AVS, see AVSDirectory.calculateOperatorAVSRegistrationDigestHash
+	operatorAVSRegistrationTypes = apitypes.Types{
+		"EIP712Domain": eigenLayerDomainType,
+		"OperatorAVSRegistration": {
+			{Name: "operator", Type: "address"},
+			{Name: "avs", Type: "address"},
+			{Name: "salt", Type: "bytes32"},
+			{Name: "expiry", Type: "uint256"},
+		},
+	}
+
+	eigenLayerDomainType = []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}
+)
+
+// EigenLayerDomain returns the EIP-712 domain of the EigenLayer core contract verifyingContract on chainID
+func EigenLayerDomain(chainID *big.Int, verifyingContract common.Address) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              eigenLayerDomainName,
+		ChainId:           (*math.HexOrDecimal256)(new(big.Int).Set(chainID)),
+		VerifyingContract: verifyingContract.Hex(),
+	}
+}
+
+// DelegationApprovalTypedData returns the typed data of the approval by delegationApprover of the delegation of staker
+// to operator, whose digest is the one of DelegationManager.calculateDelegationApprovalDigestHash
+func DelegationApprovalTypedData(
+	chainID *big.Int,
+	delegationManager common.Address,
+	staker common.Address,
+	operator common.Address,
+	delegationApprover common.Address,
+	approverSalt [32]byte,
+	expiry *big.Int,
+) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       delegationApprovalTypes,
+		PrimaryType: "DelegationApproval",
+		Domain:      EigenLayerDomain(chainID, delegationManager),
+		Message: apitypes.TypedDataMessage{
+			"delegationApprover": delegationApprover.Hex(),
+			"staker":             staker.Hex(),
+			"operator":           operator.Hex(),
+			"salt":               hexutil.Encode(approverSalt[:]),
+			"expiry":             (*math.HexOrDecimal256)(new(big.Int).Set(expiry)),
+		},
+	}
+}
+
+// OperatorAVSRegistrationTypedData returns the typed data of the registration of operator to avs, whose digest is
+// the one of AVSDirectory.calculateOperatorAVSRegistrationDigestHash
+func OperatorAVSRegistrationTypedData(
+	chainID *big.Int,
+	avsDirectory common.Address,
+	operator common.Address,
+	avs common.Address,
+	salt [32]byte,
+	expiry *big.Int,
+) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       operatorAVSRegistrationTypes,
+		PrimaryType: "OperatorAVSRegistration",
+		Domain:      EigenLayerDomain(chainID, avsDirectory),
+		Message: apitypes.TypedDataMessage{
+			"operator": operator.Hex(),
+			"avs":      avs.Hex(),
+			"salt":     hexutil.Encode(salt[:]),
+			"expiry":   (*math.HexOrDecimal256)(new(big.Int).Set(expiry)),
+		},
+	}
+}
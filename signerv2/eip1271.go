@@ -0,0 +1,119 @@
+package signerv2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	sdkEcdsa "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// EIP-1271 signatures of smart contract accounts, e.g. Safe multisigs
+// https://eips.ethereum.org/EIPS/eip-1271
+
+var (
+	// eip1271MagicValue is returned by isValidSignature(bytes32,bytes) for the valid signatures
+	eip1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+	// eip1271LegacyMagicValue is returned by the legacy isValidSignature(bytes,bytes) for the valid signatures
+	eip1271LegacyMagicValue = []byte{0x20, 0xc1, 0x3b, 0x0b}
+
+	eip1271ABI = mustParseABI(`[{"type":"function","name":"isValidSignature","stateMutability":"view",` +
+		`"inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],` +
+		`"outputs":[{"name":"magicValue","type":"bytes4"}]}]`)
+	eip1271LegacyABI = mustParseABI(`[{"type":"function","name":"isValidSignature","stateMutability":"view",` +
+		`"inputs":[{"name":"data","type":"bytes"},{"name":"signature","type":"bytes"}],` +
+		`"outputs":[{"name":"magicValue","type":"bytes4"}]}]`)
+)
+
+// VerifySignature returns whether sig is a valid signature of digest by signer, which is either an EOA or a smart
+// contract account.
+//
+// sig is first verified as a 65 byte [R || S || V] ECDSA signature, with a V of 0/1 or 27/28 and a low S like the
+// ECDSA.recover of the EigenLayer contracts. When it isn't the one of signer and signer has code, it is verified with
+// the EIP-1271 isValidSignature(bytes32,bytes) of signer, and then with the legacy isValidSignature(bytes,bytes) with
+// digest as data. A revert of isValidSignature means the signature is invalid.
+func VerifySignature(
+	ctx context.Context,
+	client eth.HttpBackend,
+	signer common.Address,
+	digest [32]byte,
+	sig []byte,
+) (bool, error) {
+	if recovered := recoverSigner(digest, sig); recovered != (common.Address{}) && recovered == signer {
+		return true, nil
+	}
+
+	code, err := client.CodeAt(ctx, signer, nil)
+	if err != nil {
+		return false, utils.WrapError("failed to get the code of the signer", err)
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+
+	valid, err := isValidSignature(ctx, client, signer, eip1271ABI, eip1271MagicValue, digest, sig)
+	if err != nil || valid {
+		return valid, err
+	}
+	return isValidSignature(ctx, client, signer, eip1271LegacyABI, eip1271LegacyMagicValue, digest[:], sig)
+}
+
+// recoverSigner returns the address of the ECDSA signature sig of digest, or the zero address if sig isn't a valid
+// ECDSA signature
+func recoverSigner(digest [32]byte, sig []byte) common.Address {
+	address, err := sdkEcdsa.RecoverAddress(digest, sig)
+	if err != nil {
+		return common.Address{}
+	}
+	return address
+}
+
+// isValidSignature calls the isValidSignature method of contractABI on signer, returning whether it returned
+// magicValue
+func isValidSignature(
+	ctx context.Context,
+	client eth.HttpBackend,
+	signer common.Address,
+	contractABI abi.ABI,
+	magicValue []byte,
+	data interface{},
+	sig []byte,
+) (bool, error) {
+	input, err := contractABI.Pack("isValidSignature", data, sig)
+	if err != nil {
+		return false, err
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &signer, Data: input}, nil)
+	if err != nil {
+		if isExecutionReverted(err) {
+			return false, nil
+		}
+		return false, utils.WrapError("failed to call isValidSignature", err)
+	}
+	// the bytes4 magic value is left aligned in the returned word
+	return len(output) >= 32 && bytes.Equal(output[:4], magicValue), nil
+}
+
+// isExecutionReverted returns whether err was returned by the node because the call reverted
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == 3 {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "execution reverted")
+}
+
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
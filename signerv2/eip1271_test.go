@@ -0,0 +1,126 @@
+package signerv2_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// eip1271WalletBytecode is a minimal EIP-1271 wallet, whose isValidSignature(bytes32 hash, bytes signature)
+	// returns the magic value when ecrecover of the [R || S || V] signature of hash is the owner passed to its
+	// constructor, and 0xffffffff otherwise
+	eip1271WalletBytecode = "602061008f6000396000516000556100748061001b6000396000f360003560e01c631626ba7e1461001457" +
+		"600080fd5b600435600052602435600401806020013560405280604001356060526060013560001a602052602060806080600060015a" +
+		"fa50608051600054146100635763ffffffff60e01b60005260206000f35b631626ba7e60e01b60005260206000f3"
+	// eip1271LegacyWalletBytecode is the same wallet with the legacy isValidSignature(bytes data, bytes signature),
+	// which checks the signature of the first 32 bytes of data
+	eip1271LegacyWalletBytecode = "60206100936000396000516000556100788061001b6000396000f360003560e01c6320c13b0b146100" +
+		"1457600080fd5b60043560240135600052602435600401806020013560405280604001356060526060013560001a60205260206080" +
+		"6080600060015afa50608051600054146100675763ffffffff60e01b60005260206000f35b6320c13b0b60e01b60005260206000f3"
+	eip1271WalletABI = `[{"type":"constructor","inputs":[{"name":"owner","type":"address"}]}]`
+)
+
+func TestVerifySignature(t *testing.T) {
+	ctx := context.Background()
+	client, err := ethclient.Dial(anvilEndpoint)
+	require.NoError(t, err)
+	deployerKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	chainID := big.NewInt(31337)
+	ownerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner := crypto.PubkeyToAddress(ownerKey.PublicKey)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	digest := crypto.Keccak256Hash([]byte("digest"))
+
+	ownerSig, err := crypto.Sign(digest[:], ownerKey)
+	require.NoError(t, err)
+	otherSig, err := crypto.Sign(digest[:], otherKey)
+	require.NoError(t, err)
+	// the signatures checked by contracts have a V of 27/28
+	ownerSig[64] += 27
+	otherSig[64] += 27
+
+	t.Run("EOA", func(t *testing.T) {
+		valid, err := signerv2.VerifySignature(ctx, client, owner, digest, ownerSig)
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		// V of 0/1
+		sig := append([]byte{}, ownerSig...)
+		sig[64] -= 27
+		valid, err = signerv2.VerifySignature(ctx, client, owner, digest, sig)
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		// the same signature with a high S
+		sig = append([]byte{}, ownerSig...)
+		s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(sig[32:64]))
+		copy(sig[32:64], common.LeftPadBytes(s.Bytes(), 32))
+		sig[64] = 55 - sig[64]
+		valid, err = signerv2.VerifySignature(ctx, client, owner, digest, sig)
+		require.NoError(t, err)
+		require.False(t, valid)
+
+		valid, err = signerv2.VerifySignature(ctx, client, owner, digest, otherSig)
+		require.NoError(t, err)
+		require.False(t, valid)
+
+		valid, err = signerv2.VerifySignature(ctx, client, owner, digest, []byte{1, 2, 3})
+		require.NoError(t, err)
+		require.False(t, valid)
+	})
+
+	for name, bytecode := range map[string]string{
+		"EIP-1271 wallet":        eip1271WalletBytecode,
+		"legacy EIP-1271 wallet": eip1271LegacyWalletBytecode,
+	} {
+		t.Run(name, func(t *testing.T) {
+			wallet := deployEIP1271Wallet(t, client, deployerKey, chainID, bytecode, owner)
+
+			valid, err := signerv2.VerifySignature(ctx, client, wallet, digest, ownerSig)
+			require.NoError(t, err)
+			require.True(t, valid)
+
+			valid, err = signerv2.VerifySignature(ctx, client, wallet, digest, otherSig)
+			require.NoError(t, err)
+			require.False(t, valid)
+
+			valid, err = signerv2.VerifySignature(ctx, client, wallet, crypto.Keccak256Hash(digest[:]), ownerSig)
+			require.NoError(t, err)
+			require.False(t, valid)
+		})
+	}
+}
+
+// deployEIP1271Wallet deploys the wallet of bytecode owned by owner
+func deployEIP1271Wallet(
+	t *testing.T,
+	client *ethclient.Client,
+	deployerKey *ecdsa.PrivateKey,
+	chainID *big.Int,
+	bytecode string,
+	owner common.Address,
+) common.Address {
+	walletABI, err := abi.JSON(strings.NewReader(eip1271WalletABI))
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(deployerKey, chainID)
+	require.NoError(t, err)
+	_, tx, _, err := bind.DeployContract(opts, walletABI, common.FromHex(bytecode), client, owner)
+	require.NoError(t, err)
+	wallet, err := bind.WaitDeployed(context.Background(), client, tx)
+	require.NoError(t, err)
+	return wallet
+}
@@ -0,0 +1,196 @@
+package signerv2
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var (
+	// ErrGCPKMSPermissionDenied is returned when the caller is not allowed to use the Cloud KMS key
+	ErrGCPKMSPermissionDenied = errors.New("permission denied on the cloud kms key")
+	// ErrGCPKMSKeyDisabled is returned when the Cloud KMS key version is not enabled, e.g. disabled or destroyed
+	ErrGCPKMSKeyDisabled = errors.New("cloud kms key version is not enabled")
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// GCPKMSSignerFn returns a bind.SignerFn that uses the Cloud KMS key version keyName, i.e.
+// projects/{project}/locations/{location}/keyRings/{keyRing}/cryptoKeys/{key}/cryptoKeyVersions/{version}, to sign
+// transactions. The key must have the EC_SIGN_SECP256K1_SHA256 algorithm. Its public key, and the address derived from
+// it, are fetched once when creating the signer.
+//
+// Like the AWS KMS signer, it normalizes S to the lower half of the curve order and finds the recovery id (V) by
+// recovering the public key, and its V value is 0/1.
+func GCPKMSSignerFn(
+	ctx context.Context,
+	client *kms.KeyManagementClient,
+	keyName string,
+	chainID *big.Int,
+) (bind.SignerFn, error) {
+	if client == nil {
+		return nil, errors.New("cloud kms client is required")
+	}
+	pk, err := getGCPKMSPublicKey(ctx, client, keyName)
+	if err != nil {
+		return nil, err
+	}
+	return gcpKMSSignerFn(ctx, client, pk, keyName, chainID)
+}
+
+func gcpKMSSignerFn(
+	ctx context.Context,
+	client *kms.KeyManagementClient,
+	pk *ecdsa.PublicKey,
+	keyName string,
+	chainID *big.Int,
+) (bind.SignerFn, error) {
+	if chainID == nil {
+		return nil, errors.New("chainID is required")
+	}
+
+	pubKeyBytes := crypto.FromECDSAPub(pk)
+	keyAddr := crypto.PubkeyToAddress(*pk)
+	signer := types.LatestSignerForChainID(chainID)
+	return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != keyAddr {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		txHashBytes := signer.Hash(tx).Bytes()
+		rBytes, sBytes, err := gcpKMSSign(ctx, client, keyName, txHashBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		signature, err := getEthereumSignature(pubKeyBytes, txHashBytes, rBytes, normalizeS(sBytes))
+		if err != nil {
+			return nil, err
+		}
+
+		return tx.WithSignature(signer, signature)
+	}, nil
+}
+
+// gcpKMSDigestSignerFn returns a DigestSignerFn signing the digests with the Cloud KMS key version keyName, whose
+// public key is pk
+func gcpKMSDigestSignerFn(client *kms.KeyManagementClient, pk *ecdsa.PublicKey, keyName string) DigestSignerFn {
+	pubKeyBytes := crypto.FromECDSAPub(pk)
+	keyAddr := crypto.PubkeyToAddress(*pk)
+	return func(ctx context.Context, address common.Address, digest [32]byte) ([]byte, error) {
+		if address != keyAddr {
+			return nil, bind.ErrNotAuthorized
+		}
+		rBytes, sBytes, err := gcpKMSSign(ctx, client, keyName, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		return getEthereumSignature(pubKeyBytes, digest[:], rBytes, normalizeS(sBytes))
+	}
+}
+
+// getGCPKMSPublicKey returns the secp256k1 public key of the Cloud KMS key version keyName
+func getGCPKMSPublicKey(
+	ctx context.Context,
+	client *kms.KeyManagementClient,
+	keyName string,
+) (*ecdsa.PublicKey, error) {
+	res, err := client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: keyName})
+	if err != nil {
+		return nil, gcpKMSError(keyName, "get the public key of", err)
+	}
+	if res.Algorithm != kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256 {
+		return nil, fmt.Errorf("cloud kms key %s has the algorithm %s instead of %s", keyName, res.Algorithm,
+			kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256)
+	}
+	if res.PemCrc32C != nil && int64(crc32.Checksum([]byte(res.Pem), crc32cTable)) != res.PemCrc32C.Value {
+		return nil, fmt.Errorf("public key of cloud kms key %s corrupted in transit", keyName)
+	}
+
+	block, _ := pem.Decode([]byte(res.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("invalid public key PEM of cloud kms key %s", keyName)
+	}
+	// x509.ParsePKIXPublicKey doesn't support secp256k1
+	var publicKeyInfo struct {
+		Algorithm struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(block.Bytes, &publicKeyInfo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal public key of cloud kms key %s: %w", keyName, err)
+	}
+	pk, err := crypto.UnmarshalPubkey(publicKeyInfo.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal public key of cloud kms key %s: %w", keyName, err)
+	}
+	return pk, nil
+}
+
+// gcpKMSSign signs digest with the Cloud KMS key version keyName, returning the R and S values of the DER signature
+func gcpKMSSign(
+	ctx context.Context,
+	client *kms.KeyManagementClient,
+	keyName string,
+	digest []byte,
+) (r []byte, s []byte, err error) {
+	res, err := client.AsymmetricSign(ctx, &kmspb.AsymmetricSignRequest{
+		Name: keyName,
+		// the transaction hash is not a SHA-256 digest, but it is signed as is like the digest of the algorithm
+		Digest:       &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest}},
+		DigestCrc32C: wrapperspb.Int64(int64(crc32.Checksum(digest, crc32cTable))),
+	})
+	if err != nil {
+		return nil, nil, gcpKMSError(keyName, "sign with", err)
+	}
+	if !res.VerifiedDigestCrc32C {
+		return nil, nil, fmt.Errorf("digest signed by cloud kms key %s corrupted in transit", keyName)
+	}
+	if res.SignatureCrc32C != nil && int64(crc32.Checksum(res.Signature, crc32cTable)) != res.SignatureCrc32C.Value {
+		return nil, nil, fmt.Errorf("signature of cloud kms key %s corrupted in transit", keyName)
+	}
+
+	var signature struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(res.Signature, &signature); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal signature of cloud kms key %s: %w", keyName, err)
+	}
+	return signature.R.Bytes(), signature.S.Bytes(), nil
+}
+
+// gcpKMSError returns an actionable error for the common errors of Cloud KMS
+func gcpKMSError(keyName string, action string, err error) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		return fmt.Errorf(
+			"%w: failed to %s %s, grant the roles/cloudkms.signerVerifier role on the key to the signer account: %w",
+			ErrGCPKMSPermissionDenied, action, keyName, err)
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: failed to %s %s, enable the key version: %w", ErrGCPKMSKeyDisabled, action,
+			keyName, err)
+	case codes.NotFound:
+		return fmt.Errorf(
+			"failed to %s %s, which is not found: the key name must be the resource name of a key version "+
+				"(projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*): %w", action, keyName, err)
+	default:
+		return fmt.Errorf("failed to %s cloud kms key %s: %w", action, keyName, err)
+	}
+}
@@ -0,0 +1,237 @@
+package signerv2_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/pem"
+	"hash/crc32"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	gcpKMSKeyName = "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+	// gcpKMSKeyHex is the private key of the fake Cloud KMS key
+	gcpKMSKeyHex = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// fakeGCPKMSServer signs like Cloud KMS with a local secp256k1 key, returning PEM encoded public keys and DER encoded
+// signatures
+type fakeGCPKMSServer struct {
+	kmspb.UnimplementedKeyManagementServiceServer
+
+	key   *ecdsa.PrivateKey
+	signs int
+	// getPublicKeys counts the public key lookups
+	getPublicKeys int
+	// err is returned by every call when set
+	err error
+}
+
+func (s *fakeGCPKMSServer) GetPublicKey(
+	ctx context.Context,
+	req *kmspb.GetPublicKeyRequest,
+) (*kmspb.PublicKey, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.getPublicKeys++
+	pubKey := crypto.FromECDSAPub(&s.key.PublicKey)
+	der, err := asn1.Marshal(struct {
+		Algorithm struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}
+		PublicKey asn1.BitString
+	}{
+		Algorithm: struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}{
+			// id-ecPublicKey, secp256k1
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.ObjectIdentifier{1, 3, 132, 0, 10},
+		},
+		PublicKey: asn1.BitString{Bytes: pubKey, BitLength: 8 * len(pubKey)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return &kmspb.PublicKey{
+		Name:      req.Name,
+		Pem:       string(pemKey),
+		PemCrc32C: wrapperspb.Int64(int64(crc32.Checksum(pemKey, castagnoliTable))),
+		Algorithm: kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256,
+	}, nil
+}
+
+func (s *fakeGCPKMSServer) AsymmetricSign(
+	ctx context.Context,
+	req *kmspb.AsymmetricSignRequest,
+) (*kmspb.AsymmetricSignResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	digest := req.Digest.GetSha256()
+	if req.DigestCrc32C == nil || int64(crc32.Checksum(digest, castagnoliTable)) != req.DigestCrc32C.Value {
+		return nil, status.Error(codes.InvalidArgument, "digest crc32c mismatch")
+	}
+	signature, err := crypto.Sign(digest, s.key)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	sValue := new(big.Int).SetBytes(signature[32:64])
+	// Cloud KMS doesn't normalize S, so every other signature has a high S
+	s.signs++
+	if s.signs%2 == 0 {
+		sValue.Sub(crypto.S256().Params().N, sValue)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, sValue})
+	if err != nil {
+		return nil, err
+	}
+	return &kmspb.AsymmetricSignResponse{
+		Name:                 req.Name,
+		Signature:            der,
+		SignatureCrc32C:      wrapperspb.Int64(int64(crc32.Checksum(der, castagnoliTable))),
+		VerifiedDigestCrc32C: true,
+	}, nil
+}
+
+// newFakeGCPKMSClient returns a Cloud KMS client of server, served in memory
+func newFakeGCPKMSClient(t *testing.T, server *fakeGCPKMSServer) *kms.KeyManagementClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	kmspb.RegisterKeyManagementServiceServer(grpcServer, server)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	client, err := kms.NewKeyManagementClient(context.Background(), option.WithGRPCConn(conn))
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestGCPKMSSignerFn(t *testing.T) {
+	key, err := crypto.HexToECDSA(gcpKMSKeyHex)
+	assert.Nil(t, err)
+	keyAddr := crypto.PubkeyToAddress(key.PublicKey)
+	server := &fakeGCPKMSServer{key: key}
+	client := newFakeGCPKMSClient(t, server)
+	chainID := big.NewInt(17000)
+	txSigner := gtypes.LatestSignerForChainID(chainID)
+
+	signer, err := signerv2.GCPKMSSignerFn(context.Background(), client, gcpKMSKeyName, chainID)
+	assert.Nil(t, err)
+	for nonce := uint64(0); nonce < 8; nonce++ {
+		tx := gtypes.NewTx(&gtypes.DynamicFeeTx{
+			ChainID: chainID,
+			Nonce:   nonce,
+			To:      &common.Address{},
+			Value:   big.NewInt(1),
+		})
+		signedTx, err := signer(keyAddr, tx)
+		assert.Nil(t, err)
+		// the signature is the same as the one of the key, which is deterministic and has a low S
+		expectedTx, err := gtypes.SignTx(tx, txSigner, key)
+		assert.Nil(t, err)
+		signedBytes, err := signedTx.MarshalBinary()
+		assert.Nil(t, err)
+		expectedBytes, err := expectedTx.MarshalBinary()
+		assert.Nil(t, err)
+		assert.Equal(t, expectedBytes, signedBytes)
+	}
+	// the public key is fetched once
+	assert.Equal(t, 1, server.getPublicKeys)
+
+	_, err = signer(common.Address{1}, gtypes.NewTx(&gtypes.DynamicFeeTx{ChainID: chainID}))
+	assert.ErrorIs(t, err, bind.ErrNotAuthorized)
+
+	t.Run("signer from config", func(t *testing.T) {
+		signerFn, senderAddress, err := signerv2.SignerFromConfig(signerv2.Config{
+			GCPKMSKeyName: gcpKMSKeyName,
+			GCPKMSClient:  client,
+		}, chainID)
+		assert.Nil(t, err)
+		assert.Equal(t, keyAddr, senderAddress)
+		signer, err := signerFn(context.Background(), senderAddress)
+		assert.Nil(t, err)
+		signedTx, err := signer(senderAddress, gtypes.NewTx(&gtypes.DynamicFeeTx{ChainID: chainID}))
+		assert.Nil(t, err)
+		from, err := gtypes.Sender(txSigner, signedTx)
+		assert.Nil(t, err)
+		assert.Equal(t, keyAddr, from)
+	})
+}
+
+func TestGCPKMSSignerFnErrors(t *testing.T) {
+	key, err := crypto.HexToECDSA(gcpKMSKeyHex)
+	assert.Nil(t, err)
+	chainID := big.NewInt(17000)
+
+	tests := map[string]struct {
+		err         error
+		expectedErr error
+	}{
+		"permission denied": {
+			err:         status.Error(codes.PermissionDenied, "permission denied"),
+			expectedErr: signerv2.ErrGCPKMSPermissionDenied,
+		},
+		"key disabled": {
+			err:         status.Error(codes.FailedPrecondition, "key version is not enabled"),
+			expectedErr: signerv2.ErrGCPKMSKeyDisabled,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := &fakeGCPKMSServer{key: key, err: tc.err}
+			client := newFakeGCPKMSClient(t, server)
+			_, err := signerv2.GCPKMSSignerFn(context.Background(), client, gcpKMSKeyName, chainID)
+			assert.ErrorIs(t, err, tc.expectedErr)
+
+			// the key is disabled after the signer is created
+			server.err = nil
+			signer, err := signerv2.GCPKMSSignerFn(context.Background(), client, gcpKMSKeyName, chainID)
+			assert.Nil(t, err)
+			server.err = tc.err
+			_, err = signer(crypto.PubkeyToAddress(key.PublicKey), gtypes.NewTx(&gtypes.DynamicFeeTx{ChainID: chainID}))
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
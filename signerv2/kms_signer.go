@@ -9,7 +9,6 @@ import (
 	"math/big"
 
 	eigenkms "github.com/Layr-Labs/eigensdk-go/aws/kms"
-	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -20,13 +19,21 @@ import (
 var secp256k1N = crypto.S256().Params().N
 var secp256k1HalfN = new(big.Int).Div(secp256k1N, big.NewInt(2))
 
-func NewKMSSigner(ctx context.Context, svc *kms.Client, pk *ecdsa.PublicKey, keyId string, chainID *big.Int) SignerFn {
+// NewKMSSigner returns a SignerFn signing with the KMS key keyId, whose public key is pk
+func NewKMSSigner(
+	ctx context.Context,
+	svc eigenkms.Client,
+	pk *ecdsa.PublicKey,
+	keyId string,
+	chainID *big.Int,
+) SignerFn {
 	return func(ctx context.Context, address common.Address) (bind.SignerFn, error) {
-		return KMSSignerFn(ctx, svc, pk, keyId, chainID)
+		return kmsSignerFn(ctx, svc, pk, keyId, chainID)
 	}
 }
 
-// KMSSignerFn returns a SignerFn that uses a KMS key to sign transactions
+// KMSSignerFn returns a bind.SignerFn that uses the KMS key keyId, an ECC_SECG_P256K1 key with the SIGN_VERIFY usage,
+// to sign transactions. Its public key, and the address derived from it, are fetched once when creating the signer.
 // Heavily taken from https://github.com/welthee/go-ethereum-aws-kms-tx-signer
 // It constructs R and S values from KMS, and constructs the recovery id (V) by trying to recover with both 0 and 1
 // values:
@@ -35,7 +42,23 @@ func NewKMSSigner(ctx context.Context, svc *kms.Client, pk *ecdsa.PublicKey, key
 // Its V value is 0/1 instead of 27/28 because `types.LatestSignerForChainID` expects 0/1 which turns it into 27/28
 func KMSSignerFn(
 	ctx context.Context,
-	svc *kms.Client,
+	svc eigenkms.Client,
+	keyId string,
+	chainID *big.Int,
+) (bind.SignerFn, error) {
+	if svc == nil {
+		return nil, errors.New("kms client is required")
+	}
+	pk, err := eigenkms.GetECDSAPublicKey(ctx, svc, keyId)
+	if err != nil {
+		return nil, err
+	}
+	return kmsSignerFn(ctx, svc, pk, keyId, chainID)
+}
+
+func kmsSignerFn(
+	ctx context.Context,
+	svc eigenkms.Client,
 	pk *ecdsa.PublicKey,
 	keyId string,
 	chainID *big.Int,
@@ -65,13 +88,7 @@ func KMSSignerFn(
 			return nil, err
 		}
 
-		// Adjust S value from signature according to Ethereum standard
-		sBigInt := new(big.Int).SetBytes(sBytes)
-		if sBigInt.Cmp(secp256k1HalfN) > 0 {
-			sBytes = new(big.Int).Sub(secp256k1N, sBigInt).Bytes()
-		}
-
-		signature, err := getEthereumSignature(pubKeyBytes, txHashBytes, rBytes, sBytes)
+		signature, err := getEthereumSignature(pubKeyBytes, txHashBytes, rBytes, normalizeS(sBytes))
 		if err != nil {
 			return nil, err
 		}
@@ -80,6 +97,31 @@ func KMSSignerFn(
 	}, nil
 }
 
+// kmsDigestSignerFn returns a DigestSignerFn signing the digests with the KMS key keyId, whose public key is pk
+func kmsDigestSignerFn(svc eigenkms.Client, pk *ecdsa.PublicKey, keyId string) DigestSignerFn {
+	pubKeyBytes := secp256k1.S256().Marshal(pk.X, pk.Y)
+	keyAddr := crypto.PubkeyToAddress(*pk)
+	return func(ctx context.Context, address common.Address, digest [32]byte) ([]byte, error) {
+		if address != keyAddr {
+			return nil, bind.ErrNotAuthorized
+		}
+		rBytes, sBytes, err := eigenkms.GetECDSASignature(ctx, svc, keyId, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		return getEthereumSignature(pubKeyBytes, digest[:], rBytes, normalizeS(sBytes))
+	}
+}
+
+// normalizeS returns the S value of a signature in the lower half of the curve order, as required by Ethereum
+func normalizeS(sBytes []byte) []byte {
+	sBigInt := new(big.Int).SetBytes(sBytes)
+	if sBigInt.Cmp(secp256k1HalfN) > 0 {
+		return new(big.Int).Sub(secp256k1N, sBigInt).Bytes()
+	}
+	return sBytes
+}
+
 func getEthereumSignature(expectedPublicKeyBytes []byte, txHash []byte, r []byte, s []byte) ([]byte, error) {
 	rsSignature := append(adjustSignatureLength(r), adjustSignatureLength(s)...)
 	signature := append(rsSignature, []byte{0}...)
@@ -2,6 +2,8 @@ package signerv2_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
 	"fmt"
 	"math/big"
 	"os"
@@ -13,8 +15,10 @@ import (
 	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/testutils"
 
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/kms/types"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	gtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -124,7 +128,7 @@ func TestSignTransactionWithKmsSigner(t *testing.T) {
 	pkWallet, err := wallet.NewPrivateKeyWallet(ethClient, signer, keyAddr, logger)
 	assert.Nil(t, err)
 	assert.NotNil(t, pkWallet)
-	txMgr := txmgr.NewSimpleTxManager(pkWallet, ethClient, logger, keyAddr)
+	txMgr := txmgr.NewSimpleTxManagerFromWallet(pkWallet, ethClient, logger)
 	assert.NotNil(t, txMgr)
 	receipt, err := txMgr.Send(context.Background(), gtypes.NewTx(&gtypes.DynamicFeeTx{
 		ChainID: &testData.Input.ChainID,
@@ -138,3 +142,115 @@ func TestSignTransactionWithKmsSigner(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, big.NewInt(999979000000000000), balance)
 }
+
+// fakeKMSClient signs like KMS with a local secp256k1 key, returning DER encoded public keys and signatures
+type fakeKMSClient struct {
+	key   *ecdsa.PrivateKey
+	signs int
+	// getPublicKeys counts the public key lookups
+	getPublicKeys int
+}
+
+func (c *fakeKMSClient) GetPublicKey(
+	ctx context.Context,
+	params *kms.GetPublicKeyInput,
+	optFns ...func(*kms.Options),
+) (*kms.GetPublicKeyOutput, error) {
+	c.getPublicKeys++
+	pubKey := crypto.FromECDSAPub(&c.key.PublicKey)
+	der, err := asn1.Marshal(struct {
+		Algorithm struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}
+		PublicKey asn1.BitString
+	}{
+		Algorithm: struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}{
+			// id-ecPublicKey, secp256k1
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.ObjectIdentifier{1, 3, 132, 0, 10},
+		},
+		PublicKey: asn1.BitString{Bytes: pubKey, BitLength: 8 * len(pubKey)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &kms.GetPublicKeyOutput{KeyId: params.KeyId, PublicKey: der}, nil
+}
+
+func (c *fakeKMSClient) Sign(
+	ctx context.Context,
+	params *kms.SignInput,
+	optFns ...func(*kms.Options),
+) (*kms.SignOutput, error) {
+	if params.SigningAlgorithm != types.SigningAlgorithmSpecEcdsaSha256 ||
+		params.MessageType != types.MessageTypeDigest {
+		return nil, fmt.Errorf("unexpected signing algorithm %s or message type %s", params.SigningAlgorithm,
+			params.MessageType)
+	}
+	signature, err := crypto.Sign(params.Message, c.key)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	// KMS doesn't normalize S, so every other signature has a high S
+	c.signs++
+	if c.signs%2 == 0 {
+		s.Sub(crypto.S256().Params().N, s)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{KeyId: params.KeyId, Signature: der}, nil
+}
+
+func TestKMSSignerFn(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	keyAddr := crypto.PubkeyToAddress(key.PublicKey)
+	kmsClient := &fakeKMSClient{key: key}
+	chainID := big.NewInt(17000)
+
+	signer, err := signerv2.KMSSignerFn(context.Background(), kmsClient, "keyId", chainID)
+	assert.Nil(t, err)
+	for nonce := uint64(0); nonce < 8; nonce++ {
+		signedTx, err := signer(keyAddr, gtypes.NewTx(&gtypes.DynamicFeeTx{
+			ChainID: chainID,
+			Nonce:   nonce,
+			To:      &common.Address{},
+			Value:   big.NewInt(1),
+		}))
+		assert.Nil(t, err)
+		from, err := gtypes.Sender(gtypes.LatestSignerForChainID(chainID), signedTx)
+		assert.Nil(t, err)
+		assert.Equal(t, keyAddr, from)
+		_, _, s := signedTx.RawSignatureValues()
+		assert.True(t, s.Cmp(new(big.Int).Rsh(crypto.S256().Params().N, 1)) <= 0)
+	}
+	// the public key is fetched once
+	assert.Equal(t, 1, kmsClient.getPublicKeys)
+
+	_, err = signer(common.Address{1}, gtypes.NewTx(&gtypes.DynamicFeeTx{ChainID: chainID}))
+	assert.ErrorIs(t, err, bind.ErrNotAuthorized)
+
+	t.Run("signer from config", func(t *testing.T) {
+		signerFn, senderAddress, err := signerv2.SignerFromConfig(signerv2.Config{
+			KMSKeyID:  "keyId",
+			KMSClient: kmsClient,
+		}, chainID)
+		assert.Nil(t, err)
+		assert.Equal(t, keyAddr, senderAddress)
+		signer, err := signerFn(context.Background(), senderAddress)
+		assert.Nil(t, err)
+		signedTx, err := signer(senderAddress, gtypes.NewTx(&gtypes.DynamicFeeTx{ChainID: chainID}))
+		assert.Nil(t, err)
+		from, err := gtypes.Sender(gtypes.LatestSignerForChainID(chainID), signedTx)
+		assert.Nil(t, err)
+		assert.Equal(t, keyAddr, from)
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"math/big"
 
+	kms "cloud.google.com/go/kms/apiv1"
+	eigenkms "github.com/Layr-Labs/eigensdk-go/aws/kms"
 	sdkEcdsa "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,6 +18,10 @@ import (
 
 type SignerFn func(ctx context.Context, address common.Address) (bind.SignerFn, error)
 
+// DigestSignerFn signs a 32 byte digest (not a transaction) on behalf of address, returning a 65 byte
+// [R || S || V] signature. V may be either 0/1 or 27/28, depending on the signer.
+type DigestSignerFn func(ctx context.Context, address common.Address, digest [32]byte) ([]byte, error)
+
 func PrivateKeySignerFn(privateKey *ecdsa.PrivateKey, chainID *big.Int) (bind.SignerFn, error) {
 	from := crypto.PubkeyToAddress(privateKey.PublicKey)
 	signer := types.LatestSignerForChainID(chainID)
@@ -31,6 +38,18 @@ func PrivateKeySignerFn(privateKey *ecdsa.PrivateKey, chainID *big.Int) (bind.Si
 	}, nil
 }
 
+// PrivateKeyDigestSignerFn adapts a raw private key to a DigestSignerFn
+func PrivateKeyDigestSignerFn(privateKey *ecdsa.PrivateKey) DigestSignerFn {
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	return func(ctx context.Context, address common.Address, digest [32]byte) ([]byte, error) {
+		if address != from {
+			return nil, bind.ErrNotAuthorized
+		}
+		return crypto.Sign(digest[:], privateKey)
+	}
+}
+
 func KeyStoreSignerFn(path string, password string, chainID *big.Int) (bind.SignerFn, error) {
 	privateKey, err := sdkEcdsa.ReadKey(path, password)
 	if err != nil {
@@ -42,15 +61,17 @@ func KeyStoreSignerFn(path string, password string, chainID *big.Int) (bind.Sign
 // Web3SignerFn creates a signer function that uses a remote signer
 // It exposes `eth_SignTransaction` endpoint which return rlp
 // encoded signed tx
-func Web3SignerFn(remoteSignerUrl string) (bind.SignerFn, error) {
-	client := NewWeb3SignerClient(remoteSignerUrl)
+func Web3SignerFn(remoteSignerUrl string, opts ...Web3SignerOption) (bind.SignerFn, error) {
+	client := NewWeb3SignerClient(remoteSignerUrl, opts...)
 
 	return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
 		return client.SignTransaction(address, tx)
 	}, nil
 }
 
-func SignerFromConfig(c Config, chainID *big.Int) (SignerFn, common.Address, error) {
+// SignerFromConfig returns the signer configured by c, and its address. The signer is configured by opts, e.g. to log
+// the records of the signed transactions with WithAuditLogger.
+func SignerFromConfig(c Config, chainID *big.Int, opts ...SignerOption) (SignerFn, common.Address, error) {
 	var signer SignerFn
 	var senderAddress common.Address
 	var err error
@@ -69,11 +90,45 @@ func SignerFromConfig(c Config, chainID *big.Int) (SignerFn, common.Address, err
 		}
 	} else if c.IsWeb3Signer() {
 		senderAddress = common.HexToAddress(c.Address)
+		opts, err := c.web3SignerOptions()
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		signer = func(ctx context.Context, address common.Address) (bind.SignerFn, error) {
+			return Web3SignerFn(c.Endpoint, opts...)
+		}
+	} else if c.IsKMSSigner() {
+		kmsClient := c.KMSClient
+		if kmsClient == nil {
+			kmsClient, err = eigenkms.NewKMSClient(context.Background(), c.KMSRegion)
+			if err != nil {
+				return nil, common.Address{}, err
+			}
+		}
+		pk, err := eigenkms.GetECDSAPublicKey(context.Background(), kmsClient, c.KMSKeyID)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		senderAddress = crypto.PubkeyToAddress(*pk)
+		signer = NewKMSSigner(context.Background(), kmsClient, pk, c.KMSKeyID, chainID)
+	} else if c.IsGCPKMSSigner() {
+		kmsClient := c.GCPKMSClient
+		if kmsClient == nil {
+			kmsClient, err = kms.NewKeyManagementClient(context.Background())
+			if err != nil {
+				return nil, common.Address{}, fmt.Errorf("failed to create cloud kms client: %w", err)
+			}
+		}
+		pk, err := getGCPKMSPublicKey(context.Background(), kmsClient, c.GCPKMSKeyName)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		senderAddress = crypto.PubkeyToAddress(*pk)
 		signer = func(ctx context.Context, address common.Address) (bind.SignerFn, error) {
-			return Web3SignerFn(c.Endpoint)
+			return gcpKMSSignerFn(ctx, kmsClient, pk, c.GCPKMSKeyName, chainID)
 		}
 	} else {
 		return nil, common.Address{}, errors.New("no signer found")
 	}
-	return signer, senderAddress, nil
+	return NewAuditSigner(signer, opts...), senderAddress, nil
 }
@@ -38,6 +38,26 @@ func TestPrivateKeySignerFn(t *testing.T) {
 	require.Equal(t, address, from)
 }
 
+func TestPrivateKeyDigestSignerFn(t *testing.T) {
+	privateKeyHex := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	require.NoError(t, err)
+
+	signer := signerv2.PrivateKeyDigestSignerFn(privateKey)
+
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	digest := crypto.Keccak256Hash([]byte("digest"))
+	signature, err := signer(context.Background(), address, digest)
+	require.NoError(t, err)
+
+	pubKey, err := crypto.SigToPub(digest[:], signature)
+	require.NoError(t, err)
+	require.Equal(t, address, crypto.PubkeyToAddress(*pubKey))
+
+	_, err = signer(context.Background(), common.Address{0x1}, digest)
+	require.Error(t, err)
+}
+
 func TestKeyStoreSignerFn(t *testing.T) {
 	keystorePath := "mockdata/dummy.key.json"
 	keystorePassword := "testpassword"
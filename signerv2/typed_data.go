@@ -0,0 +1,250 @@
+package signerv2
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	eigenkms "github.com/Layr-Labs/eigensdk-go/aws/kms"
+	sdkEcdsa "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
+	"github.com/Layr-Labs/eigensdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	kms "cloud.google.com/go/kms/apiv1"
+)
+
+const eip712DomainType = "EIP712Domain"
+
+// TypedDataSigner signs EIP-712 typed data, returning a 65 byte [R || S || V] signature whose V is 27/28, as expected
+// by the ECDSA.recover of the EigenLayer contracts.
+//
+// The primary type of message is the type of types that no other type references. The EIP712Domain type is derived
+// from the fields set in domain when types doesn't have it.
+type TypedDataSigner interface {
+	SignTypedData(
+		ctx context.Context,
+		domain apitypes.TypedDataDomain,
+		types apitypes.Types,
+		message map[string]interface{},
+	) ([]byte, error)
+}
+
+// TypedDataSignerFn is a function implementing TypedDataSigner
+type TypedDataSignerFn func(
+	ctx context.Context,
+	domain apitypes.TypedDataDomain,
+	types apitypes.Types,
+	message map[string]interface{},
+) ([]byte, error)
+
+func (f TypedDataSignerFn) SignTypedData(
+	ctx context.Context,
+	domain apitypes.TypedDataDomain,
+	types apitypes.Types,
+	message map[string]interface{},
+) ([]byte, error) {
+	return f(ctx, domain, types, message)
+}
+
+// NewTypedData returns the typed data of message, with its primary type and the EIP712Domain type filled in
+func NewTypedData(
+	domain apitypes.TypedDataDomain,
+	types apitypes.Types,
+	message map[string]interface{},
+) (apitypes.TypedData, error) {
+	primaryType, err := typedDataPrimaryType(types)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	if _, ok := types[eip712DomainType]; !ok {
+		withDomain := make(apitypes.Types, len(types)+1)
+		for name, fields := range types {
+			withDomain[name] = fields
+		}
+		withDomain[eip712DomainType] = domainType(domain)
+		types = withDomain
+	}
+	return apitypes.TypedData{
+		Types:       types,
+		PrimaryType: primaryType,
+		Domain:      domain,
+		Message:     message,
+	}, nil
+}
+
+// TypedDataDigest returns the EIP-712 digest of message, i.e. keccak256("\x19\x01" || domainSeparator || hashStruct)
+func TypedDataDigest(
+	domain apitypes.TypedDataDomain,
+	types apitypes.Types,
+	message map[string]interface{},
+) ([32]byte, error) {
+	typedData, err := NewTypedData(domain, types, message)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return [32]byte{}, utils.WrapError("failed to hash typed data", err)
+	}
+	return [32]byte(digest), nil
+}
+
+// DigestTypedDataSigner returns a TypedDataSigner that computes the EIP-712 digest locally and signs it with signer
+// on behalf of address. It's the fallback of the signers that can't sign typed data, e.g. KMS keys.
+func DigestTypedDataSigner(signer DigestSignerFn, address common.Address) TypedDataSigner {
+	return TypedDataSignerFn(func(
+		ctx context.Context,
+		domain apitypes.TypedDataDomain,
+		types apitypes.Types,
+		message map[string]interface{},
+	) ([]byte, error) {
+		digest, err := TypedDataDigest(domain, types, message)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := signer(ctx, address, digest)
+		if err != nil {
+			return nil, err
+		}
+		return toRecoverableSignature(signature)
+	})
+}
+
+// PrivateKeyTypedDataSigner returns a TypedDataSigner signing with privateKey
+func PrivateKeyTypedDataSigner(privateKey *ecdsa.PrivateKey) TypedDataSigner {
+	return DigestTypedDataSigner(PrivateKeyDigestSignerFn(privateKey), crypto.PubkeyToAddress(privateKey.PublicKey))
+}
+
+// Web3TypedDataSigner returns a TypedDataSigner using the `eth_signTypedData` endpoint of a remote signer to sign on
+// behalf of address
+func Web3TypedDataSigner(remoteSignerUrl string, address common.Address, opts ...Web3SignerOption) TypedDataSigner {
+	client := newWeb3Signer(remoteSignerUrl, opts...)
+	return TypedDataSignerFn(func(
+		ctx context.Context,
+		domain apitypes.TypedDataDomain,
+		types apitypes.Types,
+		message map[string]interface{},
+	) ([]byte, error) {
+		typedData, err := NewTypedData(domain, types, message)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := client.SignTypedData(ctx, address, typedData)
+		if err != nil {
+			return nil, err
+		}
+		return toRecoverableSignature(signature)
+	})
+}
+
+// TypedDataSignerFromConfig returns the TypedDataSigner of the signer of c and its address. Web3signer signs the typed
+// data remotely, while the KMS signers sign the digest computed locally.
+func TypedDataSignerFromConfig(c Config) (TypedDataSigner, common.Address, error) {
+	if c.IsPrivateKeySigner() {
+		return PrivateKeyTypedDataSigner(c.PrivateKey), crypto.PubkeyToAddress(c.PrivateKey.PublicKey), nil
+	} else if c.IsLocalKeystoreSigner() {
+		privateKey, err := sdkEcdsa.ReadKey(c.KeystorePath, c.Password)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		return PrivateKeyTypedDataSigner(privateKey), crypto.PubkeyToAddress(privateKey.PublicKey), nil
+	} else if c.IsWeb3Signer() {
+		address := common.HexToAddress(c.Address)
+		opts, err := c.web3SignerOptions()
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		return Web3TypedDataSigner(c.Endpoint, address, opts...), address, nil
+	} else if c.IsKMSSigner() {
+		kmsClient := c.KMSClient
+		if kmsClient == nil {
+			var err error
+			kmsClient, err = eigenkms.NewKMSClient(context.Background(), c.KMSRegion)
+			if err != nil {
+				return nil, common.Address{}, err
+			}
+		}
+		pk, err := eigenkms.GetECDSAPublicKey(context.Background(), kmsClient, c.KMSKeyID)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		address := crypto.PubkeyToAddress(*pk)
+		return DigestTypedDataSigner(kmsDigestSignerFn(kmsClient, pk, c.KMSKeyID), address), address, nil
+	} else if c.IsGCPKMSSigner() {
+		kmsClient := c.GCPKMSClient
+		if kmsClient == nil {
+			var err error
+			kmsClient, err = kms.NewKeyManagementClient(context.Background())
+			if err != nil {
+				return nil, common.Address{}, fmt.Errorf("failed to create cloud kms client: %w", err)
+			}
+		}
+		pk, err := getGCPKMSPublicKey(context.Background(), kmsClient, c.GCPKMSKeyName)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		address := crypto.PubkeyToAddress(*pk)
+		return DigestTypedDataSigner(gcpKMSDigestSignerFn(kmsClient, pk, c.GCPKMSKeyName), address), address, nil
+	}
+	return nil, common.Address{}, errors.New("no signer found")
+}
+
+// typedDataPrimaryType returns the type of types that isn't referenced by the other types
+func typedDataPrimaryType(types apitypes.Types) (string, error) {
+	referenced := make(map[string]bool)
+	for _, fields := range types {
+		for _, field := range fields {
+			// strip the array suffixes, e.g. Foo[2][]
+			name, _, _ := strings.Cut(field.Type, "[")
+			referenced[name] = true
+		}
+	}
+	var primaryTypes []string
+	for name := range types {
+		if name != eip712DomainType && !referenced[name] {
+			primaryTypes = append(primaryTypes, name)
+		}
+	}
+	if len(primaryTypes) != 1 {
+		return "", fmt.Errorf("typed data must have a single primary type, got %v", primaryTypes)
+	}
+	return primaryTypes[0], nil
+}
+
+// domainType returns the EIP712Domain type of the fields set in domain, in the order of the spec
+func domainType(domain apitypes.TypedDataDomain) []apitypes.Type {
+	var fields []apitypes.Type
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
+
+// toRecoverableSignature returns a copy of signature with a V of 27/28
+func toRecoverableSignature(signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length: expected %d, got %d", crypto.SignatureLength, len(signature))
+	}
+	signature = bytes.Clone(signature)
+	if signature[crypto.RecoveryIDOffset] < 27 {
+		signature[crypto.RecoveryIDOffset] += 27
+	}
+	return signature, nil
+}
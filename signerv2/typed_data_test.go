@@ -0,0 +1,248 @@
+package signerv2_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/require"
+)
+
+// eigenLayerDigest returns the digest of the struct hash of the EigenLayer contract verifyingContract, computed like
+// the contracts do
+func eigenLayerDigest(chainID *big.Int, verifyingContract common.Address, structHash []byte) common.Hash {
+	domainSeparator := crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte("EigenLayer")),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+		common.LeftPadBytes(verifyingContract.Bytes(), 32),
+	)
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator, structHash)
+}
+
+func TestDelegationApprovalTypedData(t *testing.T) {
+	chainID := big.NewInt(17000)
+	delegationManager := common.HexToAddress("0xA44151489861Fe9e3055d95adC98FbD462B948e7")
+	staker := common.HexToAddress("0x1")
+	operator := common.HexToAddress("0x2")
+	delegationApprover := common.HexToAddress("0x3")
+	salt := crypto.Keccak256Hash([]byte("salt"))
+	expiry := big.NewInt(1717171717)
+
+	structHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("DelegationApproval(address delegationApprover,address staker,address operator,"+
+			"bytes32 salt,uint256 expiry)")),
+		common.LeftPadBytes(delegationApprover.Bytes(), 32),
+		common.LeftPadBytes(staker.Bytes(), 32),
+		common.LeftPadBytes(operator.Bytes(), 32),
+		salt[:],
+		common.LeftPadBytes(expiry.Bytes(), 32),
+	)
+
+	typedData := signerv2.DelegationApprovalTypedData(
+		chainID,
+		delegationManager,
+		staker,
+		operator,
+		delegationApprover,
+		salt,
+		expiry,
+	)
+	digest, err := signerv2.TypedDataDigest(typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+	require.Equal(t, eigenLayerDigest(chainID, delegationManager, structHash), common.Hash(digest))
+}
+
+func TestOperatorAVSRegistrationTypedData(t *testing.T) {
+	chainID := big.NewInt(17000)
+	avsDirectory := common.HexToAddress("0x055733000064333CaDDbC92763c58BF0192fFeBf")
+	operator := common.HexToAddress("0x1")
+	avs := common.HexToAddress("0x2")
+	salt := crypto.Keccak256Hash([]byte("salt"))
+	expiry := big.NewInt(1717171717)
+
+	structHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("OperatorAVSRegistration(address operator,address avs,bytes32 salt,uint256 expiry)")),
+		common.LeftPadBytes(operator.Bytes(), 32),
+		common.LeftPadBytes(avs.Bytes(), 32),
+		salt[:],
+		common.LeftPadBytes(expiry.Bytes(), 32),
+	)
+
+	typedData := signerv2.OperatorAVSRegistrationTypedData(chainID, avsDirectory, operator, avs, salt, expiry)
+	digest, err := signerv2.TypedDataDigest(typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+	require.Equal(t, eigenLayerDigest(chainID, avsDirectory, structHash), common.Hash(digest))
+
+	// the EIP712Domain type is derived from the domain when missing
+	types := apitypes.Types{"OperatorAVSRegistration": typedData.Types["OperatorAVSRegistration"]}
+	digestWithoutDomainType, err := signerv2.TypedDataDigest(typedData.Domain, types, typedData.Message)
+	require.NoError(t, err)
+	require.Equal(t, digest, digestWithoutDomainType)
+}
+
+func TestTypedDataDigestPrimaryType(t *testing.T) {
+	domain := apitypes.TypedDataDomain{Name: "test"}
+	types := apitypes.Types{
+		"A": {{Name: "value", Type: "uint256"}},
+		"B": {{Name: "value", Type: "uint256"}},
+	}
+	_, err := signerv2.TypedDataDigest(domain, types, map[string]interface{}{"value": "1"})
+	require.ErrorContains(t, err, "single primary type")
+
+	// B is referenced by A, so A is the primary type
+	types["A"] = []apitypes.Type{{Name: "values", Type: "B[]"}}
+	_, err = signerv2.TypedDataDigest(domain, types, map[string]interface{}{
+		"values": []interface{}{map[string]interface{}{"value": "1"}, map[string]interface{}{"value": "2"}},
+	})
+	require.NoError(t, err)
+}
+
+func TestPrivateKeyTypedDataSigner(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	typedData := signerv2.OperatorAVSRegistrationTypedData(
+		big.NewInt(1),
+		common.HexToAddress("0x135dda560e946695d6f155dacafc6f1f25c1f5af"),
+		address,
+		common.HexToAddress("0x870679e138bcdf293b7ff14dd44b70fc97e12fc0"),
+		[32]byte{1},
+		big.NewInt(1717171717),
+	)
+	digest, err := signerv2.TypedDataDigest(typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+
+	signer := signerv2.PrivateKeyTypedDataSigner(privateKey)
+	signature, err := signer.SignTypedData(context.Background(), typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+	requireSignedBy(t, address, digest, signature)
+}
+
+func TestDigestTypedDataSigner(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	typedData := signerv2.DelegationApprovalTypedData(
+		big.NewInt(1),
+		common.HexToAddress("0x39053D51B77DC0d36036Fc1fCc8Cb819df8Ef37A"),
+		common.HexToAddress("0x1"),
+		common.HexToAddress("0x2"),
+		address,
+		[32]byte{1},
+		big.NewInt(1717171717),
+	)
+	digest, err := signerv2.TypedDataDigest(typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+
+	var signedDigest [32]byte
+	signer := signerv2.DigestTypedDataSigner(
+		func(ctx context.Context, from common.Address, digest [32]byte) ([]byte, error) {
+			signedDigest = digest
+			return signerv2.PrivateKeyDigestSignerFn(privateKey)(ctx, from, digest)
+		},
+		address,
+	)
+	signature, err := signer.SignTypedData(context.Background(), typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+	require.Equal(t, digest, signedDigest)
+	requireSignedBy(t, address, digest, signature)
+
+	t.Run("KMS signer from config", func(t *testing.T) {
+		kmsClient := &fakeKMSClient{key: privateKey}
+		signer, signerAddress, err := signerv2.TypedDataSignerFromConfig(signerv2.Config{
+			KMSKeyID:  "keyId",
+			KMSClient: kmsClient,
+		})
+		require.NoError(t, err)
+		require.Equal(t, address, signerAddress)
+		// the fake KMS client returns a high S every other signature
+		for i := 0; i < 2; i++ {
+			signature, err := signer.SignTypedData(
+				context.Background(),
+				typedData.Domain,
+				typedData.Types,
+				typedData.Message,
+			)
+			require.NoError(t, err)
+			requireSignedBy(t, address, digest, signature)
+		}
+	})
+}
+
+func TestWeb3TypedDataSigner(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	// signs like web3signer, which returns a V of 27/28
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			ID     string            `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		require.Equal(t, "eth_signTypedData", request.Method)
+		require.Len(t, request.Params, 2)
+		var from common.Address
+		require.NoError(t, json.Unmarshal(request.Params[0], &from))
+		require.Equal(t, address, from)
+		var typedData apitypes.TypedData
+		require.NoError(t, json.Unmarshal(request.Params[1], &typedData))
+		digest, _, err := apitypes.TypedDataAndHash(typedData)
+		require.NoError(t, err)
+		signature, err := crypto.Sign(digest, privateKey)
+		require.NoError(t, err)
+		signature[crypto.RecoveryIDOffset] += 27
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result":  hexutil.Encode(signature),
+		}))
+	}))
+	defer server.Close()
+
+	typedData := signerv2.OperatorAVSRegistrationTypedData(
+		big.NewInt(1),
+		common.HexToAddress("0x135dda560e946695d6f155dacafc6f1f25c1f5af"),
+		address,
+		common.HexToAddress("0x870679e138bcdf293b7ff14dd44b70fc97e12fc0"),
+		[32]byte{1},
+		big.NewInt(1717171717),
+	)
+	digest, err := signerv2.TypedDataDigest(typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+
+	signer, signerAddress, err := signerv2.TypedDataSignerFromConfig(signerv2.Config{
+		Endpoint: server.URL,
+		Address:  address.Hex(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, address, signerAddress)
+	signature, err := signer.SignTypedData(context.Background(), typedData.Domain, typedData.Types, typedData.Message)
+	require.NoError(t, err)
+	requireSignedBy(t, address, digest, signature)
+}
+
+// requireSignedBy requires signature to be the signature of digest by address, with a V of 27/28
+func requireSignedBy(t *testing.T, address common.Address, digest [32]byte, signature []byte) {
+	require.Len(t, signature, crypto.SignatureLength)
+	require.Contains(t, []byte{27, 28}, signature[crypto.RecoveryIDOffset])
+	recoverable := append([]byte{}, signature...)
+	recoverable[crypto.RecoveryIDOffset] -= 27
+	pubKey, err := crypto.SigToPub(digest[:], recoverable)
+	require.NoError(t, err)
+	require.Equal(t, address, crypto.PubkeyToAddress(*pubKey))
+	// the contracts reject the signatures with a high S
+	s := new(big.Int).SetBytes(signature[32:64])
+	require.True(t, s.Cmp(new(big.Int).Rsh(crypto.S256().Params().N, 1)) <= 0)
+}
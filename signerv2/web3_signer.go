@@ -2,15 +2,21 @@ package signerv2
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/google/uuid"
 )
 
@@ -23,10 +29,32 @@ type JsonRpcRequest struct {
 	ID      string      `json:"id"`
 }
 
+// ErrWeb3SignerTLSHandshake is returned when the TLS handshake with web3signer fails, e.g. because of an untrusted
+// certificate or a missing client certificate
+var ErrWeb3SignerTLSHandshake = errors.New("web3signer TLS handshake failed")
+
+// ErrWeb3SignerAPI is returned when web3signer rejects a request, e.g. with a JSON RPC error
+var ErrWeb3SignerAPI = errors.New("web3signer API error")
+
 type Web3SignerClient interface {
 	SignTransaction(from common.Address, tx *types.Transaction) (*types.Transaction, error)
 }
 
+// Web3SignerOption configures the connection of the clients created by NewWeb3SignerClient
+type Web3SignerOption func(*web3SignerOptions)
+
+type web3SignerOptions struct {
+	tlsConfig *tls.Config
+}
+
+// WithWeb3SignerTLSConfig sets the TLS config of the connection to web3signer, e.g. its CA and client certificates,
+// see Web3SignerTLSConfig
+func WithWeb3SignerTLSConfig(tlsConfig *tls.Config) Web3SignerOption {
+	return func(o *web3SignerOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
 // Web3Signer is a client for a remote signer
 // It currently implements `eth_signTransaction` method of Consensys Web3 Signer
 // Reference: https://docs.web3signer.consensys.io/reference/api/json-rpc#eth_signtransaction
@@ -35,8 +63,21 @@ type Web3Signer struct {
 	client http.Client
 }
 
-func NewWeb3SignerClient(url string) Web3SignerClient {
+func NewWeb3SignerClient(url string, opts ...Web3SignerOption) Web3SignerClient {
+	return newWeb3Signer(url, opts...)
+}
+
+func newWeb3Signer(url string, opts ...Web3SignerOption) *Web3Signer {
+	o := &web3SignerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	client := http.Client{}
+	if o.tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = o.tlsConfig
+		client.Transport = transport
+	}
 	return &Web3Signer{client: client, url: url}
 }
 
@@ -44,6 +85,9 @@ func (r Web3Signer) SignTransaction(
 	from common.Address,
 	tx *types.Transaction,
 ) (*types.Transaction, error) {
+	if tx.Type() == types.BlobTxType {
+		return nil, errors.New("blob transactions are not supported by web3signer")
+	}
 	method := "eth_signTransaction"
 	id := uuid.New().String()
 	params := []map[string]string{
@@ -56,6 +100,12 @@ func (r Web3Signer) SignTransaction(
 			"data":     utils.Add0x(hex.EncodeToString(tx.Data())),
 		},
 	}
+	// web3signer signs a legacy tx unless the dynamic fees are set
+	if tx.Type() == types.DynamicFeeTxType {
+		delete(params[0], "gasPrice")
+		params[0]["maxFeePerGas"] = utils.Add0x(hex.EncodeToString(tx.GasFeeCap().Bytes()))
+		params[0]["maxPriorityFeePerGas"] = utils.Add0x(hex.EncodeToString(tx.GasTipCap().Bytes()))
+	}
 
 	request := JsonRpcRequest{
 		JsonRPC: "2.0",
@@ -64,36 +114,109 @@ func (r Web3Signer) SignTransaction(
 		ID:      id,
 	}
 
-	jsonData, err := json.Marshal(request)
+	var rlpEncodedSignedTx string
+	if err := r.call(context.Background(), request, &rlpEncodedSignedTx); err != nil {
+		return nil, err
+	}
+	rlpEncodedSignedTx = utils.Trim0x(rlpEncodedSignedTx)
+	signedTxBytes, err := hex.DecodeString(rlpEncodedSignedTx)
 	if err != nil {
-		return nil, utils.WrapError("error marshalling request", err)
+		return nil, err
 	}
-
-	resp, err := r.client.Post(r.url, "application/json", bytes.NewBuffer(jsonData))
+	var signedTx types.Transaction
+	err = rlp.DecodeBytes(signedTxBytes, &signedTx)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return &signedTx, nil
+}
 
-	var result map[string]interface{}
-	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, utils.WrapError("error decoding response", err)
+// SignTypedData signs typedData on behalf of from with the `eth_signTypedData` method of the remote signer, returning
+// the 65 byte [R || S || V] signature
+// Reference: https://docs.web3signer.consensys.io/reference/api/json-rpc#eth_signtypeddata
+func (r Web3Signer) SignTypedData(
+	ctx context.Context,
+	from common.Address,
+	typedData apitypes.TypedData,
+) ([]byte, error) {
+	request := JsonRpcRequest{
+		JsonRPC: "2.0",
+		Method:  "eth_signTypedData",
+		Params:  []interface{}{from.Hex(), typedData},
+		ID:      uuid.New().String(),
 	}
 
-	if result["error"] != nil {
-		return nil, utils.WrapError("error in response", fmt.Errorf("%v", result["error"]))
+	var result string
+	if err := r.call(ctx, request, &result); err != nil {
+		return nil, err
 	}
 
-	rlpEncodedSignedTx := result["result"].(string)
-	rlpEncodedSignedTx = utils.Trim0x(rlpEncodedSignedTx)
-	signedTxBytes, err := hex.DecodeString(rlpEncodedSignedTx)
+	signature, err := hex.DecodeString(utils.Trim0x(result))
 	if err != nil {
 		return nil, err
 	}
-	var signedTx types.Transaction
-	err = rlp.DecodeBytes(signedTxBytes, &signedTx)
+	return signature, nil
+}
+
+// call sends request to web3signer, decoding its result into result. The errors of the TLS handshake are wrapped with
+// ErrWeb3SignerTLSHandshake, while the errors returned by web3signer are wrapped with ErrWeb3SignerAPI.
+func (r Web3Signer) call(ctx context.Context, request JsonRpcRequest, result interface{}) error {
+	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return nil, err
+		return utils.WrapError("error marshalling request", err)
 	}
-	return &signedTx, nil
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(httpRequest)
+	if err != nil {
+		if isTLSHandshakeError(err) {
+			return utils.WrapError(ErrWeb3SignerTLSHandshake, err)
+		}
+		return utils.WrapError("failed to send request to web3signer", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  interface{}     `json:"error"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%w: unexpected status %s", ErrWeb3SignerAPI, resp.Status)
+		}
+		return utils.WrapError("error decoding response", err)
+	}
+
+	if response.Error != nil {
+		return utils.WrapError(ErrWeb3SignerAPI, fmt.Errorf("error in response: %v", response.Error))
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return utils.WrapError("error decoding result", err)
+	}
+	return nil
+}
+
+// isTLSHandshakeError returns whether err was returned because the TLS handshake failed, either on the client side,
+// e.g. because of an untrusted certificate, or on the server side, which sends an alert, e.g. for a missing client
+// certificate. With TLS 1.3, the alerts of the server about the client certificate are only received after the
+// handshake, with the response.
+func isTLSHandshakeError(err error) bool {
+	var (
+		certVerificationErr *tls.CertificateVerificationError
+		recordHeaderErr     tls.RecordHeaderError
+		alertErr            tls.AlertError
+		unknownAuthorityErr x509.UnknownAuthorityError
+		hostnameErr         x509.HostnameError
+		certInvalidErr      x509.CertificateInvalidError
+	)
+	if errors.As(err, &certVerificationErr) || errors.As(err, &recordHeaderErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &certInvalidErr) {
+		return true
+	}
+	// the alerts of the server are returned as the errors of the read of the response
+	return strings.Contains(err.Error(), "remote error: tls:")
 }
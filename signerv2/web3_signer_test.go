@@ -0,0 +1,290 @@
+package signerv2_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA issues the certificates of the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns a certificate issued by ca, valid for dnsName and the loopback address
+func (ca *testCA) issue(t *testing.T, dnsName string, extKeyUsage x509.ExtKeyUsage) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{extKeyUsage},
+		DNSNames:     []string{dnsName},
+	}
+	if dnsName == "localhost" {
+		template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1)}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writePEM writes the PEM of cert to dir, returning the paths of its certificate and key
+func writePEM(t *testing.T, dir string, name string, cert tls.Certificate) (string, string) {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	require.NoError(t, err)
+	certPath := filepath.Join(dir, name+".crt")
+	keyPath := filepath.Join(dir, name+".key")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	require.NoError(t, os.WriteFile(certPath, certPEM, 0o600))
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	require.NoError(t, os.WriteFile(keyPath, keyPEM, 0o600))
+	return certPath, keyPath
+}
+
+// newMTLSWeb3Signer starts a web3signer requiring the client certificates issued by ca, whose certificate is issued
+// for serverName. It responds to eth_signTransaction with signedTx, or with a JSON RPC error if rpcError is set.
+func newMTLSWeb3Signer(
+	t *testing.T,
+	ca *testCA,
+	serverName string,
+	signedTx *types.Transaction,
+	rpcError bool,
+) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request signerv2.JsonRpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		require.Equal(t, "eth_signTransaction", request.Method)
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		if rpcError {
+			response["error"] = map[string]interface{}{"code": -32000, "message": "signing key is locked"}
+		} else {
+			rawTx, err := rlp.EncodeToBytes(signedTx)
+			require.NoError(t, err)
+			response["result"] = hexutil.Encode(rawTx)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{ca.issue(t, serverName, x509.ExtKeyUsageServerAuth)},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWeb3SignerMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caCertPath := filepath.Join(dir, "ca.crt")
+	require.NoError(
+		t,
+		os.WriteFile(caCertPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0o600),
+	)
+	clientCertPath, clientKeyPath := writePEM(t, dir, "client", ca.issue(t, "client", x509.ExtKeyUsageClientAuth))
+	otherCA := newTestCA(t)
+	otherCertPath, otherKeyPath := writePEM(t, dir, "other", otherCA.issue(t, "client", x509.ExtKeyUsageClientAuth))
+
+	privateKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	chainID := big.NewInt(31337)
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(0),
+	})
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+	require.NoError(t, err)
+
+	server := newMTLSWeb3Signer(t, ca, "localhost", signedTx, false)
+	// the certificate of web3signer.internal is verified with the server name override
+	internalServer := newMTLSWeb3Signer(t, ca, "web3signer.internal", signedTx, false)
+	rpcErrorServer := newMTLSWeb3Signer(t, ca, "localhost", signedTx, true)
+
+	tests := map[string]struct {
+		endpoint    string
+		tlsConfig   *signerv2.Web3SignerTLSConfig
+		expectedErr error
+	}{
+		"mutual TLS": {
+			endpoint: server.URL,
+			tlsConfig: &signerv2.Web3SignerTLSConfig{
+				CACertPath:     caCertPath,
+				ClientCertPath: clientCertPath,
+				ClientKeyPath:  clientKeyPath,
+			},
+		},
+		"server name override": {
+			endpoint: internalServer.URL,
+			tlsConfig: &signerv2.Web3SignerTLSConfig{
+				CACertPath:     caCertPath,
+				ClientCertPath: clientCertPath,
+				ClientKeyPath:  clientKeyPath,
+				ServerName:     "web3signer.internal",
+			},
+		},
+		"insecure skip verify": {
+			endpoint: internalServer.URL,
+			tlsConfig: &signerv2.Web3SignerTLSConfig{
+				ClientCertPath:     clientCertPath,
+				ClientKeyPath:      clientKeyPath,
+				InsecureSkipVerify: true,
+			},
+		},
+		"no TLS config": {
+			endpoint:    server.URL,
+			expectedErr: signerv2.ErrWeb3SignerTLSHandshake,
+		},
+		"no client certificate": {
+			endpoint:    server.URL,
+			tlsConfig:   &signerv2.Web3SignerTLSConfig{CACertPath: caCertPath},
+			expectedErr: signerv2.ErrWeb3SignerTLSHandshake,
+		},
+		"client certificate of another CA": {
+			endpoint: server.URL,
+			tlsConfig: &signerv2.Web3SignerTLSConfig{
+				CACertPath:     caCertPath,
+				ClientCertPath: otherCertPath,
+				ClientKeyPath:  otherKeyPath,
+			},
+			expectedErr: signerv2.ErrWeb3SignerTLSHandshake,
+		},
+		"wrong server name": {
+			endpoint: internalServer.URL,
+			tlsConfig: &signerv2.Web3SignerTLSConfig{
+				CACertPath:     caCertPath,
+				ClientCertPath: clientCertPath,
+				ClientKeyPath:  clientKeyPath,
+			},
+			expectedErr: signerv2.ErrWeb3SignerTLSHandshake,
+		},
+		"signer API error": {
+			endpoint: rpcErrorServer.URL,
+			tlsConfig: &signerv2.Web3SignerTLSConfig{
+				CACertPath:     caCertPath,
+				ClientCertPath: clientCertPath,
+				ClientKeyPath:  clientKeyPath,
+			},
+			expectedErr: signerv2.ErrWeb3SignerAPI,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logs bytes.Buffer
+			signer, signerAddress, err := signerv2.SignerFromConfig(signerv2.Config{
+				Endpoint:      tt.endpoint,
+				Address:       address.Hex(),
+				Web3SignerTLS: tt.tlsConfig,
+				Logger:        logging.NewTextSLogger(&logs, &logging.SLoggerOptions{NoColor: true}),
+			}, chainID)
+			require.NoError(t, err)
+			require.Equal(t, address, signerAddress)
+			if tt.tlsConfig != nil && tt.tlsConfig.InsecureSkipVerify {
+				assert.Contains(t, logs.String(), "INSECURE")
+			} else {
+				assert.Empty(t, logs.String())
+			}
+
+			signerFn, err := signer(context.Background(), address)
+			require.NoError(t, err)
+			result, err := signerFn(address, tx)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				for _, otherErr := range []error{signerv2.ErrWeb3SignerTLSHandshake, signerv2.ErrWeb3SignerAPI} {
+					if otherErr != tt.expectedErr {
+						require.NotErrorIs(t, err, otherErr)
+					}
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, signedTx.Hash(), result.Hash())
+		})
+	}
+}
+
+func TestWeb3SignerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	clientCertPath, clientKeyPath := writePEM(t, dir, "client", ca.issue(t, "client", x509.ExtKeyUsageClientAuth))
+
+	_, err := signerv2.Web3SignerTLSConfig{CACertPath: filepath.Join(dir, "missing.crt")}.TLSConfig(nil)
+	require.Error(t, err)
+	// a key isn't a CA certificate
+	_, err = signerv2.Web3SignerTLSConfig{CACertPath: clientKeyPath}.TLSConfig(nil)
+	require.Error(t, err)
+	_, err = signerv2.Web3SignerTLSConfig{ClientCertPath: clientCertPath}.TLSConfig(nil)
+	require.Error(t, err)
+	_, err = signerv2.Web3SignerTLSConfig{ClientCertPath: clientCertPath, ClientKeyPath: clientCertPath}.TLSConfig(nil)
+	require.Error(t, err)
+
+	tlsConfig, err := signerv2.Web3SignerTLSConfig{
+		CACertPath:     clientCertPath,
+		ClientCertPath: clientCertPath,
+		ClientKeyPath:  clientKeyPath,
+		ServerName:     "web3signer.internal",
+	}.TLSConfig(nil)
+	require.NoError(t, err)
+	require.Len(t, tlsConfig.Certificates, 1)
+	require.NotNil(t, tlsConfig.RootCAs)
+	require.Equal(t, "web3signer.internal", tlsConfig.ServerName)
+	require.False(t, tlsConfig.InsecureSkipVerify)
+}
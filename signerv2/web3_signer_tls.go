@@ -0,0 +1,68 @@
+package signerv2
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// Web3SignerTLSConfig configures the TLS connection to web3signer, e.g. when it requires mutual TLS
+type Web3SignerTLSConfig struct {
+	// CACertPath is the path of the PEM bundle of the CAs verifying the certificate of web3signer, which is verified
+	// with the system CAs unless set
+	CACertPath string
+	// ClientCertPath and ClientKeyPath are the paths of the PEM client certificate and private key presented to
+	// web3signer for mutual TLS
+	ClientCertPath string
+	ClientKeyPath  string
+	// ServerName overrides the name the certificate of web3signer is verified against, which is the host of the
+	// endpoint unless set
+	ServerName string
+	// InsecureSkipVerify disables the verification of the certificate of web3signer, which must only be used in
+	// development environments
+	InsecureSkipVerify bool
+}
+
+// TLSConfig loads the certificates of c, returning the *tls.Config of the connection to web3signer. A warning is
+// logged with logger, or to stderr if nil, when InsecureSkipVerify is set.
+func (c Web3SignerTLSConfig) TLSConfig(logger logging.Logger) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+	if c.CACertPath != "" {
+		caCerts, err := os.ReadFile(c.CACertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read web3signer CA certificates: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no PEM certificate found in web3signer CA certificates %s", c.CACertPath)
+		}
+	}
+	if (c.ClientCertPath == "") != (c.ClientKeyPath == "") {
+		return nil, errors.New("both the client certificate and the client key of web3signer must be set")
+	}
+	if c.ClientCertPath != "" {
+		clientCert, err := tls.LoadX509KeyPair(c.ClientCertPath, c.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load web3signer client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	if c.InsecureSkipVerify {
+		if logger == nil {
+			logger = logging.NewTextSLogger(os.Stderr, nil)
+		}
+		logger.Warn(
+			"INSECURE: the TLS certificate of web3signer is not verified, which must only be done in development " +
+				"environments, as anyone on the network path can impersonate web3signer",
+		)
+		tlsConfig.InsecureSkipVerify = true
+	}
+	return tlsConfig, nil
+}
@@ -20,12 +20,18 @@ import (
 )
 
 func StartAnvilContainer(anvilStateFileName string) (testcontainers.Container, error) {
+	return StartAnvilContainerWithArgs(anvilStateFileName)
+}
+
+// StartAnvilContainerWithArgs starts anvil like StartAnvilContainer, passing it extraArgs, e.g. "--hardfork", "berlin"
+// to start a chain without EIP-1559
+func StartAnvilContainerWithArgs(anvilStateFileName string, extraArgs ...string) (testcontainers.Container, error) {
 
 	ctx := context.Background()
 	req := testcontainers.ContainerRequest{
 		Image:        "ghcr.io/foundry-rs/foundry:nightly-3abac322efdb69e27b6fe8748b72754ae878f64d@sha256:871b66957335636a02c6c324c969db9adb1d6d64f148753c4a986cf32a40dc3c",
 		Entrypoint:   []string{"anvil"},
-		Cmd:          []string{"--host", "0.0.0.0", "--base-fee", "0", "--gas-price", "0"},
+		Cmd:          append([]string{"--host", "0.0.0.0", "--base-fee", "0", "--gas-price", "0"}, extraArgs...),
 		ExposedPorts: []string{"8545/tcp"},
 		WaitingFor:   wait.ForLog("Listening on"),
 	}
@@ -0,0 +1,54 @@
+package testutils
+
+import (
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NumTestBlsKeyPairs is the number of key pairs returned by TestBlsKeyPairs
+const NumTestBlsKeyPairs = 10
+
+// TestBlsKeyPair is a well-known BLS key pair of the tests, derived from its seed with bls.NewKeyPairFromSeed
+type TestBlsKeyPair struct {
+	Seed       [32]byte
+	KeyPair    *bls.KeyPair
+	OperatorId types.OperatorId
+}
+
+// testBlsOperatorIds are the precomputed operator ids of the key pairs of TestBlsKeyPairs
+var testBlsOperatorIds = [NumTestBlsKeyPairs]string{
+	"0xedb15ec3c667a7261909a34976a59db691cd2a5958a97cb02875be78be974d83",
+	"0xc9b6b73946721ff878b33ed07fee6c5ed2cea871b3ca744c7b6bed91f70a65de",
+	"0xbb947759827b939192503b78a638dd3de986d12d7e4c478b05e6694f060057ac",
+	"0xc343e8b1316feadd3e667ff6b75b2672323f988bfaa514b2d22dc6965d1dbaf3",
+	"0x8bbf2945770433259962c6bb9230590d9ee092785d03776aa21be5df15bdb09a",
+	"0xbf0d4037fb085802982e8b7b3df631444213114a5cfbbc9e9dfc00d69930370f",
+	"0xf2334d508097f11c4af67a98ba01ed271d16627f9a0fca8a9b28d01d4ad269ed",
+	"0x1186b1efaf0deb53a320010d7e51c6082d44f39cb17171981d1f267405202ab3",
+	"0x795db723a3ce0dae0207272fb6cee73e8d9ffbb7c05a48f848350656f22bf582",
+	"0xef15ca4e55671af6bb40ee2d220aebb481685fa4a5147d2a5ad33fd557bcc435",
+}
+
+// TestBlsKeyPairs returns the NumTestBlsKeyPairs well-known key pairs of the tests, which are the same across runs.
+// The seed of the key pair i is the 32 bytes big endian encoding of i+1. Each call returns new key pairs, which the
+// tests may zeroize.
+func TestBlsKeyPairs() []TestBlsKeyPair {
+	keyPairs := make([]TestBlsKeyPair, NumTestBlsKeyPairs)
+	for i := range keyPairs {
+		var seed [32]byte
+		seed[31] = byte(i + 1)
+		keyPair, err := bls.NewKeyPairFromSeed(seed)
+		if err != nil {
+			panic(fmt.Sprintf("failed to derive test bls key pair %d: %v", i, err))
+		}
+		keyPairs[i] = TestBlsKeyPair{
+			Seed:       seed,
+			KeyPair:    keyPair,
+			OperatorId: types.OperatorId(common.HexToHash(testBlsOperatorIds[i])),
+		}
+	}
+	return keyPairs
+}
@@ -0,0 +1,24 @@
+package testutils
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestBlsKeyPairs(t *testing.T) {
+	keyPairs := TestBlsKeyPairs()
+	require.Len(t, keyPairs, NumTestBlsKeyPairs)
+	operatorIds := make(map[types.OperatorId]bool)
+	for i, keyPair := range keyPairs {
+		assert.Equal(t, types.OperatorIdFromKeyPair(keyPair.KeyPair), keyPair.OperatorId, "key pair %d", i)
+		operatorIds[keyPair.OperatorId] = true
+	}
+	assert.Len(t, operatorIds, NumTestBlsKeyPairs)
+
+	// the key pairs are new at each call
+	keyPairs[0].KeyPair.Zeroize()
+	assert.False(t, TestBlsKeyPairs()[0].KeyPair.IsZeroized())
+}
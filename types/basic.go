@@ -3,15 +3,40 @@ package types
 // This file defines internal types that have custom LogValues (for go's slog), to make debugging easier
 
 import (
-	"encoding/hex"
+	"fmt"
 	"log/slog"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-// make TaskResponseDigests print as hex encoded strings instead of a sequence of bytes
+// make TaskResponseDigests and OperatorIds print as 0x prefixed hex encoded strings instead of a sequence of bytes
 type Bytes32 [32]byte
 
 func (m Bytes32) LogValue() slog.Value {
-	return slog.StringValue(hex.EncodeToString(m[:]))
+	return slog.StringValue(m.String())
+}
+
+// String returns the 0x prefixed hex of m
+func (m Bytes32) String() string {
+	return hexutil.Encode(m[:])
+}
+
+// MarshalText returns the 0x prefixed hex of m
+func (m Bytes32) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText sets m to the bytes of their 0x prefixed hex
+func (m *Bytes32) UnmarshalText(text []byte) error {
+	b, err := hexutil.Decode(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid bytes32 hex: %w", err)
+	}
+	if len(b) != len(m) {
+		return fmt.Errorf("invalid bytes32 hex: expected %d bytes, got %d", len(m), len(b))
+	}
+	copy(m[:], b)
+	return nil
 }
 
 func (m *Bytes32) UnderlyingType() [32]byte {
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorIdHex(t *testing.T) {
+	keyPair, err := bls.NewKeyPairFromString("0x1234")
+	require.NoError(t, err)
+	operatorId := OperatorIdFromKeyPair(keyPair)
+	assert.Equal(t, OperatorId(keyPair.GetOperatorID()), operatorId)
+
+	hex := operatorId.String()
+	assert.Len(t, hex, 66)
+	assert.Equal(t, "0x", hex[:2])
+	assert.Equal(t, hex, fmt.Sprintf("%v", operatorId))
+	assert.Equal(t, hex, operatorId.LogValue().String())
+
+	text, err := operatorId.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, hex, string(text))
+
+	data, err := json.Marshal(map[string]OperatorId{"operatorId": operatorId})
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"operatorId":%q}`, hex), string(data))
+	var unmarshaled map[string]OperatorId
+	require.NoError(t, json.Unmarshal(data, &unmarshaled))
+	assert.Equal(t, operatorId, unmarshaled["operatorId"])
+
+	var invalid OperatorId
+	assert.Error(t, invalid.UnmarshalText([]byte("0x1234")))
+	assert.Error(t, invalid.UnmarshalText([]byte(hex[2:])))
+}
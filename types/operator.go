@@ -9,8 +9,6 @@ import (
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/utils"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/math"
-	"github.com/ethereum/go-ethereum/crypto"
 
 	apkreg "github.com/Layr-Labs/eigensdk-go/contracts/bindings/BLSApkRegistry"
 )
@@ -100,10 +98,9 @@ type StakeAmount = *big.Int
 // It is the hash of the operator's G1 pubkey
 type OperatorId = Bytes32
 
+// OperatorIdFromG1Pubkey returns the operator id of pubkey, like BLSApkRegistry.getOperatorId
 func OperatorIdFromG1Pubkey(pubkey *bls.G1Point) OperatorId {
-	x := pubkey.X.BigInt(new(big.Int))
-	y := pubkey.Y.BigInt(new(big.Int))
-	return OperatorId(crypto.Keccak256Hash(append(math.U256Bytes(x), math.U256Bytes(y)...)))
+	return OperatorId(bls.OperatorIDFromG1Pubkey(pubkey))
 }
 
 func OperatorIdFromContractG1Pubkey(pubkey apkreg.BN254G1Point) OperatorId {